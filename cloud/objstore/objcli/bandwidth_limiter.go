@@ -0,0 +1,55 @@
+package objcli
+
+import (
+	"math"
+
+	"golang.org/x/time/rate"
+)
+
+// BandwidthLimiter limits the throughput of uploads/downloads to a given number of bytes per second. A single limiter
+// may be shared by multiple clients (or operations), in which case their combined throughput is limited.
+//
+// NOTE: The limit may be adjusted at runtime, this affects any transfers which are already in progress.
+type BandwidthLimiter struct {
+	limiter *rate.Limiter
+}
+
+// NewBandwidthLimiter returns a new limiter which allows the given number of bytes per second, a value <= 0 disables
+// limiting.
+func NewBandwidthLimiter(bytesPerSecond int) *BandwidthLimiter {
+	if bytesPerSecond <= 0 {
+		return &BandwidthLimiter{limiter: rate.NewLimiter(rate.Inf, math.MaxInt)}
+	}
+
+	return &BandwidthLimiter{limiter: rate.NewLimiter(rate.Limit(bytesPerSecond), bytesPerSecond)}
+}
+
+// SetBytesPerSecond adjusts the number of bytes per second allowed by the limiter, a value <= 0 disables limiting.
+func (b *BandwidthLimiter) SetBytesPerSecond(bytesPerSecond int) {
+	// The burst must remain non-zero when limiting is disabled, the rate limited readers/writers wait in chunks which
+	// are (at most) the size of the burst. The burst is updated whilst the limit is finite, to avoid accounting for
+	// tokens using an infinite limit.
+	if bytesPerSecond <= 0 {
+		b.limiter.SetBurst(math.MaxInt)
+		b.limiter.SetLimit(rate.Inf)
+
+		return
+	}
+
+	b.limiter.SetLimit(rate.Limit(bytesPerSecond))
+	b.limiter.SetBurst(bytesPerSecond)
+}
+
+// BytesPerSecond returns the number of bytes per second allowed by the limiter, zero indicates limiting is disabled.
+func (b *BandwidthLimiter) BytesPerSecond() int {
+	if b.limiter.Limit() == rate.Inf {
+		return 0
+	}
+
+	return int(b.limiter.Limit())
+}
+
+// Limiter returns the underlying rate limiter, allowing the limiter to be used with the 'ratelimit' package.
+func (b *BandwidthLimiter) Limiter() *rate.Limiter {
+	return b.limiter
+}
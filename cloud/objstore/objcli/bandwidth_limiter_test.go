@@ -0,0 +1,75 @@
+package objcli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/couchbase/tools-common/types/v2/ratelimit"
+)
+
+func TestNewBandwidthLimiter(t *testing.T) {
+	limiter := NewBandwidthLimiter(1024)
+	require.Equal(t, 1024, limiter.BytesPerSecond())
+	require.Equal(t, rate.Limit(1024), limiter.Limiter().Limit())
+	require.Equal(t, 1024, limiter.Limiter().Burst())
+}
+
+func TestNewBandwidthLimiterUnlimited(t *testing.T) {
+	for _, bytesPerSecond := range []int{0, -1} {
+		limiter := NewBandwidthLimiter(bytesPerSecond)
+		require.Zero(t, limiter.BytesPerSecond())
+		require.Equal(t, rate.Inf, limiter.Limiter().Limit())
+		require.NotZero(t, limiter.Limiter().Burst())
+	}
+}
+
+func TestBandwidthLimiterSetBytesPerSecond(t *testing.T) {
+	limiter := NewBandwidthLimiter(0)
+
+	limiter.SetBytesPerSecond(512)
+	require.Equal(t, 512, limiter.BytesPerSecond())
+	require.Equal(t, 512, limiter.Limiter().Burst())
+
+	limiter.SetBytesPerSecond(0)
+	require.Zero(t, limiter.BytesPerSecond())
+	require.NotZero(t, limiter.Limiter().Burst())
+}
+
+func TestBandwidthLimiterUnlimitedReader(t *testing.T) {
+	var (
+		limiter = NewBandwidthLimiter(0)
+		data    = bytes.Repeat([]byte("a"), 1024*1024)
+		start   = time.Now()
+	)
+
+	read, err := io.ReadAll(ratelimit.NewRateLimitedReader(context.Background(), bytes.NewReader(data), limiter.Limiter()))
+	require.NoError(t, err)
+	require.Equal(t, data, read)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestBandwidthLimiterAdjustedAtRuntime(t *testing.T) {
+	var (
+		limiter = NewBandwidthLimiter(1)
+		reader  = ratelimit.NewRateLimitedReader(context.Background(), bytes.NewReader(testData), limiter.Limiter())
+		buffer  = make([]byte, 1)
+	)
+
+	// Consume the initial burst, subsequent reads would be limited to a byte per second
+	_, err := reader.Read(buffer)
+	require.NoError(t, err)
+
+	limiter.SetBytesPerSecond(0)
+
+	start := time.Now()
+
+	_, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), time.Second)
+}
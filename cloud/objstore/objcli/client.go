@@ -1,10 +1,12 @@
-// Package objcli exposes a unified 'Client' interface for accessing/managing objects stored in the cloud.
+// Package objcli exposes a unified 'Client' interface for accessing/managing objects stored in the cloud, see
+// 'objutil.NewClientForURI' to create the client for the provider indicated by a URI.
 package objcli
 
 import (
 	"context"
 	"io"
 	"regexp"
+	"time"
 
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
 )
@@ -21,6 +23,50 @@ type GetObjectOptions struct {
 
 	// ByteRange allows specifying a start/end offset to be operated on.
 	ByteRange *objval.ByteRange
+
+	// Decompress the body of the object, where its content encoding (or a recognised extension of the key e.g. '.gz')
+	// indicates that it's compressed. The size of a decompressed object is not populated.
+	//
+	// NOTE: Byte ranges are applied to the compressed object, so should not be used in conjunction with this option.
+	Decompress bool
+
+	// BandwidthLimiter overrides the limiter used by a 'RateLimitedClient' for this operation.
+	//
+	// NOTE: Ignored by clients which don't limit bandwidth.
+	BandwidthLimiter *BandwidthLimiter
+
+	// IfModifiedSince causes 'objerr.ErrNotModified' to be returned, rather than the object, where the object hasn't
+	// been modified since the given time. Ignored when zero.
+	//
+	// NOTE: Modification times are compared with a granularity of one second, as with the 'If-Modified-Since' header.
+	IfModifiedSince time.Time
+
+	// IfNoneMatch causes 'objerr.ErrNotModified' to be returned, rather than the object, where the ETag of the object
+	// matches the given ETag (as returned by the same provider). Ignored when empty.
+	//
+	// NOTE: Takes precedence over 'IfModifiedSince' when both are provided.
+	IfNoneMatch string
+}
+
+// Conditional returns a boolean indicating whether the object should only be returned if it has been modified.
+func (g GetObjectOptions) Conditional() bool {
+	return !g.IfModifiedSince.IsZero() || g.IfNoneMatch != ""
+}
+
+// NotModified returns a boolean indicating whether the object with the given attributes should not be returned, because
+// it hasn't been modified according to the conditions in the options.
+//
+// NOTE: This is used by clients whose provider doesn't natively support conditional reads.
+func (g GetObjectOptions) NotModified(attrs *objval.ObjectAttrs) bool {
+	if g.IfNoneMatch != "" {
+		return attrs.ETag != nil && (g.IfNoneMatch == "*" || trimETag(g.IfNoneMatch) == trimETag(*attrs.ETag))
+	}
+
+	if !g.IfModifiedSince.IsZero() {
+		return attrs.LastModified != nil && !attrs.LastModified.Truncate(time.Second).After(g.IfModifiedSince)
+	}
+
+	return false
 }
 
 // GetObjectAttrsOptions encapsulates the options available when using the 'GetObjectAttrs' function.
@@ -44,6 +90,31 @@ type PutObjectOptions struct {
 	//
 	// NOTE: Required to be a 'ReadSeeker' to support checksum calculation/validation.
 	Body io.ReadSeeker
+
+	// Tags are attached to the object when it's created, see 'PutObjectTags' for details on how tags are stored by each
+	// cloud provider.
+	//
+	// NOTE: Ignored by clients which don't support tagging.
+	Tags map[string]string
+
+	// Metadata is user-defined metadata attached to the object, which is returned by 'GetObjectAttrs'.
+	//
+	// NOTE: Keys are case-insensitive, and should only contain lowercase letters, digits and underscores to be valid for
+	// all cloud providers; Google Storage stores tags as metadata with the prefix 'tag-', so keys shouldn't use it.
+	// Clients which don't support metadata (e.g. the local filesystem) return an 'objerr.ErrUnsupportedOperation'.
+	Metadata map[string]string
+
+	// Compress the body using the given compression before it's uploaded, setting the content encoding of the object so
+	// that it may be decompressed using 'GetObjectOptions.Decompress'.
+	//
+	// NOTE: The compressed body is buffered in memory. Clients which don't support content encodings (e.g. 'objfs') rely
+	// on the key having a recognised extension for the object to be decompressed.
+	Compress Compression
+
+	// BandwidthLimiter overrides the limiter used by a 'RateLimitedClient' for this operation.
+	//
+	// NOTE: Ignored by clients which don't limit bandwidth.
+	BandwidthLimiter *BandwidthLimiter
 }
 
 // CopyObjectOptions encapsulates the options available when using the 'CopyObject' function.
@@ -71,6 +142,41 @@ type AppendToObjectOptions struct {
 
 	// Body is the data that will be appended.
 	Body io.ReadSeeker
+
+	// BandwidthLimiter overrides the limiter used by a 'RateLimitedClient' for this operation.
+	//
+	// NOTE: Ignored by clients which don't limit bandwidth.
+	BandwidthLimiter *BandwidthLimiter
+}
+
+// GetObjectTagsOptions encapsulates the options available when using the 'GetObjectTags' function.
+type GetObjectTagsOptions struct {
+	// Bucket is the bucket being operated on.
+	Bucket string
+
+	// Key is the key (path) of the object/blob being operated on.
+	Key string
+}
+
+// PutObjectTagsOptions encapsulates the options available when using the 'PutObjectTags' function.
+type PutObjectTagsOptions struct {
+	// Bucket is the bucket being operated on.
+	Bucket string
+
+	// Key is the key (path) of the object/blob being operated on.
+	Key string
+
+	// Tags replace any existing tags on the object.
+	Tags map[string]string
+}
+
+// DeleteObjectTagsOptions encapsulates the options available when using the 'DeleteObjectTags' function.
+type DeleteObjectTagsOptions struct {
+	// Bucket is the bucket being operated on.
+	Bucket string
+
+	// Key is the key (path) of the object/blob being operated on.
+	Key string
 }
 
 // DeleteObjectsOptions encapsulates the options available when using the 'DeleteObjects' function.
@@ -80,6 +186,27 @@ type DeleteObjectsOptions struct {
 
 	// Keys are the keys that will be deleted.
 	Keys []string
+
+	// Objects are the objects that will be deleted, allowing specific versions of objects to be deleted; the latest
+	// version is deleted for objects without a version id. May be used in conjunction with 'Keys'.
+	//
+	// NOTE: Clients which don't support versioning return an 'objerr.ErrUnsupportedOperation' if a version id is given.
+	Objects []objval.ObjectVersion
+}
+
+// AllObjects returns the objects to be deleted, combining 'Keys' and 'Objects'.
+func (d DeleteObjectsOptions) AllObjects() []objval.ObjectVersion {
+	if len(d.Keys) == 0 {
+		return d.Objects
+	}
+
+	objects := make([]objval.ObjectVersion, 0, len(d.Keys)+len(d.Objects))
+
+	for _, key := range d.Keys {
+		objects = append(objects, objval.ObjectVersion{Key: key})
+	}
+
+	return append(objects, d.Objects...)
 }
 
 // DeleteDirectoryOptions encapsulates the options available when using the 'DeleteDirectory' function.
@@ -94,6 +221,14 @@ type DeleteDirectoryOptions struct {
 	//
 	/// NOTE: This has no effect if versioning is not enabled on the target bucket.
 	Versions bool
+
+	// Include only deletes objects where the keys match any of the given regular expressions.
+	Include []*regexp.Regexp
+
+	// Exclude skips deleting objects where the keys match any of the given regular expressions.
+	//
+	// NOTE: Mutually exclusive with 'Include', as with 'IterateObjects'.
+	Exclude []*regexp.Regexp
 }
 
 // IterateFunc is the function used when iterating over objects, this function will be called once for each object whose
@@ -117,10 +252,49 @@ type IterateObjectsOptions struct {
 	// Exclude objects where the keys match any of the given regular expressions.
 	Exclude []*regexp.Regexp
 
+	// ListInclude allows populating additional attributes of the listed objects, rather than requiring a request to
+	// fetch the attributes of each object individually.
+	//
+	// NOTE: Currently only supported by Azure, ignored by other clients.
+	ListInclude ListInclude
+
 	// Func is executed for each object listed.
 	Func IterateFunc
 }
 
+// ListInclude encapsulates the additional attributes/objects which may be included when iterating objects.
+type ListInclude struct {
+	// Metadata populates the user-defined metadata of each object.
+	Metadata bool
+
+	// Tags populates the tags of each object.
+	Tags bool
+
+	// Versions lists all the versions of each object, rather than only the latest version.
+	Versions bool
+
+	// Snapshots lists the snapshots of each object, in addition to the base object.
+	Snapshots bool
+}
+
+// ListDeletedObjectsOptions encapsulates the options available when using the 'ListDeletedObjects' function.
+type ListDeletedObjectsOptions struct {
+	// Bucket is the bucket containing the deleted objects.
+	Bucket string
+
+	// Prefix limits the deleted objects to those with keys which have the given prefix.
+	Prefix string
+}
+
+// UndeleteObjectOptions encapsulates the options available when using the 'UndeleteObject' function.
+type UndeleteObjectOptions struct {
+	// Bucket is the bucket containing the deleted object.
+	Bucket string
+
+	// Key is the key of the deleted object.
+	Key string
+}
+
 // CreateMultipartUploadOptions encapsulates the options available when using the 'CreateMultipartUpload' function.
 type CreateMultipartUploadOptions struct {
 	// Bucket is the bucket being operated on.
@@ -128,6 +302,12 @@ type CreateMultipartUploadOptions struct {
 
 	// Key is the key (path) of the object/blob being operated on.
 	Key string
+
+	// Metadata is user-defined metadata attached to the completed object, see 'PutObjectOptions.Metadata'.
+	//
+	// NOTE: AWS requires metadata when the upload is created, whilst other cloud providers set it upon completion; the
+	// same metadata should be provided to 'CompleteMultipartUpload'.
+	Metadata map[string]string
 }
 
 // ListPartsOptions encapsulates the options available when using the 'ListParts' function.
@@ -160,6 +340,11 @@ type UploadPartOptions struct {
 
 	// Body is the data that will be uploaded.
 	Body io.ReadSeeker
+
+	// BandwidthLimiter overrides the limiter used by a 'RateLimitedClient' for this operation.
+	//
+	// NOTE: Ignored by clients which don't limit bandwidth.
+	BandwidthLimiter *BandwidthLimiter
 }
 
 // UploadPartCopyOptions encapsulates the options available when using the 'UploadPartCopy' function.
@@ -203,6 +388,9 @@ type CompleteMultipartUploadOptions struct {
 
 	// Parts is an ordered list of parts that should be constructed into the completed object.
 	Parts []objval.Part
+
+	// Metadata is user-defined metadata attached to the completed object, see 'CreateMultipartUploadOptions.Metadata'.
+	Metadata map[string]string
 }
 
 // AbortMultipartUploadOptions encapsulates the options available when using the 'AbortMultipartUpload' function.
@@ -217,6 +405,96 @@ type AbortMultipartUploadOptions struct {
 	Key string
 }
 
+// ListMultipartUploadsOptions encapsulates the options available when using the 'ListMultipartUploads' function.
+type ListMultipartUploadsOptions struct {
+	// Bucket is the bucket being operated on.
+	Bucket string
+
+	// Prefix is the prefix of the keys for which uploads are listed, when omitted all uploads are listed.
+	Prefix string
+}
+
+// CreateBucketOptions encapsulates the options available when using the 'CreateBucket' function.
+type CreateBucketOptions struct {
+	// Bucket is the name of the bucket being created.
+	Bucket string
+
+	// Region is the region/location in which the bucket will be created, when omitted the default for the client is
+	// used.
+	//
+	// NOTE: Not supported by all cloud providers.
+	Region string
+}
+
+// DeleteBucketOptions encapsulates the options available when using the 'DeleteBucket' function.
+type DeleteBucketOptions struct {
+	// Bucket is the name of the bucket being deleted.
+	Bucket string
+}
+
+// GetBucketVersioningOptions encapsulates the options available when using the 'GetBucketVersioning' function.
+type GetBucketVersioningOptions struct {
+	// Bucket is the bucket being operated on.
+	Bucket string
+}
+
+// GetBucketRegionOptions encapsulates the options available when using the 'GetBucketRegion' function.
+type GetBucketRegionOptions struct {
+	// Bucket is the bucket being operated on.
+	Bucket string
+}
+
+// SetObjectLockOptions encapsulates the options available when using the 'SetObjectLock' function.
+type SetObjectLockOptions struct {
+	// Bucket is the bucket being operated on.
+	Bucket string
+
+	// Key is the key (path) of the object/blob being operated on.
+	Key string
+
+	// VersionID is the version of the object being locked, when omitted the latest version is locked.
+	//
+	// NOTE: Not supported by all cloud providers.
+	VersionID string
+
+	// Lock is the lock being applied to (or released from) the object.
+	Lock objval.ObjectLock
+
+	// Release indicates that the lock should be released rather than applied.
+	//
+	// NOTE: Only holds may be released, retention locks are only removed once they expire.
+	Release bool
+}
+
+// GetBucketLockingStatusOptions encapsulates the options available when using the 'GetBucketLockingStatus' function.
+type GetBucketLockingStatusOptions struct {
+	// Bucket is the bucket being operated on.
+	Bucket string
+}
+
+// QueryObjectOptions encapsulates the options available when using the 'QueryObject' function.
+type QueryObjectOptions struct {
+	// Bucket is the bucket being operated on.
+	Bucket string
+
+	// Key is the key (path) of the object/blob being operated on.
+	Key string
+
+	// Expression is the SQL expression used to query the object e.g. 'SELECT s.name FROM S3Object s'.
+	Expression string
+
+	// Input describes the format of the object being queried.
+	Input objval.QueryInput
+
+	// Output describes the format in which the results should be returned.
+	Output objval.QueryOutput
+
+	// BandwidthLimiter overrides the limiter used by a 'RateLimitedClient' for this operation.
+	//
+	// NOTE: Ignored by clients which don't limit bandwidth.
+	BandwidthLimiter *BandwidthLimiter
+}
+
 // Client is a unified interface for accessing/managing objects stored in the cloud.
 type Client interface {
 	// Provider returns the cloud provider this client is interfacing with.
@@ -224,6 +502,12 @@ type Client interface {
 	// NOTE: This may be used to change high level behavior which may be cloud provider specific.
 	Provider() objval.Provider
 
+	// Capabilities returns the functionality supported by this client, and the limits imposed by the underlying store.
+	//
+	// NOTE: This should be preferred over 'Provider' when changing behavior, as it accounts for stores which are only
+	// compatible with a given cloud provider.
+	Capabilities() objval.Capabilities
+
 	// GetObject retrieves an object form the cloud, an optional byte range argument may be supplied which causes only
 	// the requested byte range to be returned.
 	//
@@ -244,9 +528,27 @@ type Client interface {
 
 	// AppendToObject appends the provided data to the object with the given key, this is a binary concatenation.
 	//
-	// NOTE: If the given object does not already exist, it will be created.
+	// NOTE: If the given object does not already exist, it will be created. The metadata of an existing object is
+	// preserved.
 	AppendToObject(ctx context.Context, opts AppendToObjectOptions) error
 
+	// GetObjectTags returns the tags attached to the object with the given key.
+	//
+	// NOTE: Returns an 'objerr.ErrUnsupportedOperation' for clients which don't support tagging.
+	GetObjectTags(ctx context.Context, opts GetObjectTagsOptions) (map[string]string, error)
+
+	// PutObjectTags replaces the tags attached to the object with the given key. Tags are stored as S3 object tags, GCS
+	// custom metadata prefixed with 'tag-' and Azure blob index tags; each cloud provider imposes limits on the
+	// number/size of tags.
+	//
+	// NOTE: Returns an 'objerr.ErrUnsupportedOperation' for clients which don't support tagging.
+	PutObjectTags(ctx context.Context, opts PutObjectTagsOptions) error
+
+	// DeleteObjectTags removes all the tags attached to the object with the given key.
+	//
+	// NOTE: Returns an 'objerr.ErrUnsupportedOperation' for clients which don't support tagging.
+	DeleteObjectTags(ctx context.Context, opts DeleteObjectTagsOptions) error
+
 	// DeleteObjects deletes all the objects with the given keys ignoring any errors for keys which are not found.
 	//
 	// NOTE: Depending on the underlying client and support from its SDK, this function may batch operations into pages.
@@ -259,8 +561,22 @@ type Client interface {
 
 	// IterateObjects iterates through the objects a bucket running the provided iteration function for each object
 	// which matches the given filtering parameters.
+	//
+	// NOTE: Objects are not guaranteed to be visited in lexicographical order, for example, S3 Express One Zone
+	// directory buckets don't order their listings.
 	IterateObjects(ctx context.Context, opts IterateObjectsOptions) error
 
+	// ListDeletedObjects returns the soft-deleted objects which have the given prefix, and may still be recovered using
+	// 'UndeleteObject'.
+	//
+	// NOTE: Returns an 'objerr.ErrUnsupportedOperation' for clients which don't support soft delete, see 'Capabilities'.
+	ListDeletedObjects(ctx context.Context, opts ListDeletedObjectsOptions) ([]*objval.DeletedObject, error)
+
+	// UndeleteObject recovers the soft-deleted object with the given key.
+	//
+	// NOTE: Returns an 'objerr.ErrUnsupportedOperation' for clients which don't support soft delete, see 'Capabilities'.
+	UndeleteObject(ctx context.Context, opts UndeleteObjectOptions) error
+
 	// CreateMultipartUpload creates a new multipart upload for the given key.
 	//
 	// NOTE: Not all clients directly support multipart uploads, the interface exposed should be used as if they do. The
@@ -286,6 +602,50 @@ type Client interface {
 	// AbortMultipartUpload aborts the multipart upload with the given id whilst cleaning up any abandoned parts.
 	AbortMultipartUpload(ctx context.Context, opts AbortMultipartUploadOptions) error
 
+	// ListMultipartUploads returns the multipart uploads which have been created but not completed/aborted, this may
+	// be used to find uploads abandoned by a process which crashed.
+	//
+	// NOTE: Not all clients can list uploads, those which can't (e.g. 'objazure', where staged blocks are automatically
+	// garbage collected) return no uploads.
+	ListMultipartUploads(ctx context.Context, opts ListMultipartUploadsOptions) ([]objval.MultipartUpload, error)
+
+	// CreateBucket creates a new bucket with the given name.
+	CreateBucket(ctx context.Context, opts CreateBucketOptions) error
+
+	// DeleteBucket deletes the bucket with the given name.
+	//
+	// NOTE: Most cloud providers require the bucket to be empty before it can be deleted.
+	DeleteBucket(ctx context.Context, opts DeleteBucketOptions) error
+
+	// GetBucketVersioning returns the versioning status of the given bucket.
+	//
+	// NOTE: Returns an 'objerr.ErrUnsupportedOperation' for cloud providers which don't manage versioning at the bucket
+	// level.
+	GetBucketVersioning(ctx context.Context, opts GetBucketVersioningOptions) (objval.VersioningStatus, error)
+
+	// GetBucketRegion returns the region/location in which the given bucket resides.
+	//
+	// NOTE: Returns an 'objerr.ErrUnsupportedOperation' for cloud providers which don't expose the region of a bucket.
+	GetBucketRegion(ctx context.Context, opts GetBucketRegionOptions) (string, error)
+
+	// SetObjectLock applies (or releases) a lock on the given object, preventing it from being deleted/overwritten.
+	//
+	// NOTE: Returns an 'objerr.ErrUnsupportedOperation' for cloud providers which don't support object lock, see
+	// 'objval.Capabilities.SupportsObjectLock'.
+	SetObjectLock(ctx context.Context, opts SetObjectLockOptions) error
+
+	// GetBucketLockingStatus returns the object lock configuration of the given bucket.
+	//
+	// NOTE: Returns an 'objerr.ErrUnsupportedOperation' for cloud providers which don't support object lock.
+	GetBucketLockingStatus(ctx context.Context, opts GetBucketLockingStatusOptions) (objval.BucketLockingStatus, error)
+
+	// QueryObject runs the given SQL expression against the object with the given key, returning the results; this
+	// allows extracting a subset of a large object without downloading it entirely.
+	//
+	// NOTE: Returns an 'objerr.ErrUnsupportedOperation' for cloud providers which don't support querying objects. The
+	// returned body must be closed to avoid resource leaks.
+	QueryObject(ctx context.Context, opts QueryObjectOptions) (io.ReadCloser, error)
+
 	// Close the underlying client/SDK where applicable; use of the client, or the underlying SDK after a call to Close
 	// has undefined behavior. This is required to stop memory leaks in GCP.
 	Close() error
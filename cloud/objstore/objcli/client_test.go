@@ -0,0 +1,102 @@
+package objcli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+func TestGetObjectOptionsConditional(t *testing.T) {
+	require.False(t, GetObjectOptions{}.Conditional())
+	require.True(t, GetObjectOptions{IfModifiedSince: time.Now()}.Conditional())
+	require.True(t, GetObjectOptions{IfNoneMatch: "etag"}.Conditional())
+}
+
+func TestDeleteObjectsOptionsAllObjects(t *testing.T) {
+	require.Empty(t, DeleteObjectsOptions{}.AllObjects())
+
+	opts := DeleteObjectsOptions{
+		Keys:    []string{"key1"},
+		Objects: []objval.ObjectVersion{{Key: "key2", VersionID: "version"}, {Key: "key3"}},
+	}
+
+	expected := []objval.ObjectVersion{{Key: "key1"}, {Key: "key2", VersionID: "version"}, {Key: "key3"}}
+
+	require.Equal(t, expected, opts.AllObjects())
+}
+
+func TestGetObjectOptionsNotModified(t *testing.T) {
+	modified := time.Date(2024, 1, 1, 12, 0, 0, 500, time.UTC)
+
+	type test struct {
+		name     string
+		opts     GetObjectOptions
+		attrs    objval.ObjectAttrs
+		expected bool
+	}
+
+	tests := []*test{
+		{
+			name:  "Unconditional",
+			attrs: objval.ObjectAttrs{ETag: ptr.To("etag"), LastModified: &modified},
+		},
+		{
+			name:     "ETagMatches",
+			opts:     GetObjectOptions{IfNoneMatch: "etag"},
+			attrs:    objval.ObjectAttrs{ETag: ptr.To("etag")},
+			expected: true,
+		},
+		{
+			name:     "ETagMatchesQuoted",
+			opts:     GetObjectOptions{IfNoneMatch: `"etag"`},
+			attrs:    objval.ObjectAttrs{ETag: ptr.To("etag")},
+			expected: true,
+		},
+		{
+			name:  "ETagDiffers",
+			opts:  GetObjectOptions{IfNoneMatch: "etag"},
+			attrs: objval.ObjectAttrs{ETag: ptr.To("other")},
+		},
+		{
+			name: "ETagMissing",
+			opts: GetObjectOptions{IfNoneMatch: "etag"},
+		},
+		{
+			name:     "ETagWildcard",
+			opts:     GetObjectOptions{IfNoneMatch: "*"},
+			attrs:    objval.ObjectAttrs{ETag: ptr.To("etag")},
+			expected: true,
+		},
+		{
+			name:     "ETagTakesPrecedence",
+			opts:     GetObjectOptions{IfNoneMatch: "etag", IfModifiedSince: modified.Add(-time.Hour)},
+			attrs:    objval.ObjectAttrs{ETag: ptr.To("etag"), LastModified: &modified},
+			expected: true,
+		},
+		{
+			name:     "NotModifiedSince",
+			opts:     GetObjectOptions{IfModifiedSince: modified.Truncate(time.Second)},
+			attrs:    objval.ObjectAttrs{LastModified: &modified},
+			expected: true,
+		},
+		{
+			name:  "ModifiedSince",
+			opts:  GetObjectOptions{IfModifiedSince: modified.Add(-time.Second)},
+			attrs: objval.ObjectAttrs{LastModified: &modified},
+		},
+		{
+			name: "LastModifiedMissing",
+			opts: GetObjectOptions{IfModifiedSince: modified},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.opts.NotModified(&test.attrs))
+		})
+	}
+}
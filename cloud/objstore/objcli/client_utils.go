@@ -3,6 +3,7 @@ package objcli
 import (
 	"path"
 	"regexp"
+	"strings"
 )
 
 // ShouldIgnore uses the given regular expressions to determine if we should skip listing the provided file.
@@ -19,3 +20,25 @@ func ShouldIgnore(query string, include, exclude []*regexp.Regexp) bool {
 
 	return (include != nil && !ignore(include)) || (exclude != nil && ignore(exclude))
 }
+
+// trimETag returns the given ETag without its surrounding quotes (and weak validator prefix), some providers quote
+// ETags whilst others don't.
+func trimETag(etag string) string {
+	return strings.Trim(strings.TrimPrefix(etag, "W/"), `"`)
+}
+
+// NormalizeMetadata returns a copy of the given user-defined metadata with lowercase keys; cloud providers differ in
+// how they case metadata keys (e.g. canonical HTTP header casing) and treat them as case-insensitive.
+func NormalizeMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	normalized := make(map[string]string, len(metadata))
+
+	for key, value := range metadata {
+		normalized[strings.ToLower(key)] = value
+	}
+
+	return normalized
+}
@@ -57,3 +57,14 @@ func TestShouldIgnore(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeMetadata(t *testing.T) {
+	require.Nil(t, NormalizeMetadata(nil))
+	require.Nil(t, NormalizeMetadata(map[string]string{}))
+
+	require.Equal(
+		t,
+		map[string]string{"format_version": "2", "key": "Value"},
+		NormalizeMetadata(map[string]string{"Format_Version": "2", "key": "Value"}),
+	)
+}
@@ -0,0 +1,128 @@
+package objcli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// Compression represents a compression algorithm applied to an object, the value matches the HTTP 'Content-Encoding'.
+type Compression string
+
+const (
+	// CompressionNone indicates that the object is not compressed.
+	CompressionNone Compression = ""
+
+	// CompressionGZIP indicates that the object is gzip compressed.
+	CompressionGZIP Compression = "gzip"
+
+	// CompressionZSTD indicates that the object is zstd compressed.
+	//
+	// NOTE: Recognised, but not currently supported; operations using this compression will return an
+	// 'UnsupportedCompressionError'.
+	CompressionZSTD Compression = "zstd"
+)
+
+// compressionExtensions maps the key extensions which we recognise, to the compression they imply.
+var compressionExtensions = map[string]Compression{
+	".gz":   CompressionGZIP,
+	".gzip": CompressionGZIP,
+	".zst":  CompressionZSTD,
+}
+
+// DetectCompression returns the compression applied to the object with the given key, using the content encoding
+// where present and falling back to the extension of the key.
+//
+// NOTE: Volatile API that's subject to change/removal.
+func DetectCompression(key, encoding string) Compression {
+	for _, e := range strings.Split(encoding, ",") {
+		switch compression := Compression(strings.ToLower(strings.TrimSpace(e))); compression {
+		case CompressionGZIP, CompressionZSTD:
+			return compression
+		case "x-gzip":
+			return CompressionGZIP
+		}
+	}
+
+	return compressionExtensions[strings.ToLower(path.Ext(key))]
+}
+
+// DecompressObject wraps the body of the given object in a decompressor, if the given content encoding (or the key)
+// indicates that the object is compressed.
+//
+// NOTE: Volatile API that's subject to change/removal.
+func DecompressObject(object *objval.Object, encoding string) error {
+	compression := DetectCompression(object.Key, encoding)
+
+	switch compression {
+	case CompressionNone:
+		return nil
+	case CompressionGZIP:
+	default:
+		return &UnsupportedCompressionError{Compression: compression}
+	}
+
+	reader, err := gzip.NewReader(object.Body)
+	if err != nil {
+		return fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+
+	// The decompressed size isn't known until the object has been read
+	object.Size = nil
+	object.Body = &decompressingReader{Reader: reader, body: object.Body}
+
+	return nil
+}
+
+// CompressBody returns a seeker which reads the given body compressed using the given compression.
+//
+// NOTE: The compressed body is buffered in memory, so this should only be used for reasonably sized objects.
+//
+// NOTE: Volatile API that's subject to change/removal.
+func CompressBody(body io.ReadSeeker, compression Compression) (io.ReadSeeker, error) {
+	switch compression {
+	case CompressionNone:
+		return body, nil
+	case CompressionGZIP:
+	default:
+		return nil, &UnsupportedCompressionError{Compression: compression}
+	}
+
+	var (
+		buffer bytes.Buffer
+		writer = gzip.NewWriter(&buffer)
+	)
+
+	_, err := io.Copy(writer, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress body: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress body: %w", err)
+	}
+
+	return bytes.NewReader(buffer.Bytes()), nil
+}
+
+// decompressingReader reads decompressed data, closing both the decompressor and the underlying body.
+type decompressingReader struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (d *decompressingReader) Close() error {
+	err := d.Reader.Close()
+
+	if closeErr := d.body.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
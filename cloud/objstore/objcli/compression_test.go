@@ -0,0 +1,153 @@
+package objcli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+func gzipString(t *testing.T, data string) []byte {
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+
+	_, err := writer.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buffer.Bytes()
+}
+
+func TestDetectCompression(t *testing.T) {
+	type test struct {
+		name     string
+		key      string
+		encoding string
+		expected Compression
+	}
+
+	tests := []*test{
+		{
+			name: "None",
+			key:  "key.json",
+		},
+		{
+			name:     "GZIPEncoding",
+			key:      "key",
+			encoding: "gzip",
+			expected: CompressionGZIP,
+		},
+		{
+			name:     "LegacyGZIPEncoding",
+			key:      "key",
+			encoding: "x-gzip",
+			expected: CompressionGZIP,
+		},
+		{
+			name:     "ZSTDEncoding",
+			key:      "key",
+			encoding: "ZSTD",
+			expected: CompressionZSTD,
+		},
+		{
+			name:     "MultipleEncodings",
+			key:      "key",
+			encoding: "identity, gzip",
+			expected: CompressionGZIP,
+		},
+		{
+			name:     "GZIPExtension",
+			key:      "path/to/key.json.gz",
+			expected: CompressionGZIP,
+		},
+		{
+			name:     "ZSTDExtension",
+			key:      "path/to/key.zst",
+			expected: CompressionZSTD,
+		},
+		{
+			name:     "EncodingTakesPrecedence",
+			key:      "key.zst",
+			encoding: "gzip",
+			expected: CompressionGZIP,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, DetectCompression(test.key, test.encoding))
+		})
+	}
+}
+
+func TestDecompressObject(t *testing.T) {
+	object := &objval.Object{
+		ObjectAttrs: objval.ObjectAttrs{Key: "key", Size: ptr.To(int64(42))},
+		Body:        io.NopCloser(bytes.NewReader(gzipString(t, "value"))),
+	}
+
+	require.NoError(t, DecompressObject(object, "gzip"))
+	require.Nil(t, object.Size)
+
+	data, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+	require.Equal(t, "value", string(data))
+	require.NoError(t, object.Body.Close())
+}
+
+func TestDecompressObjectNotCompressed(t *testing.T) {
+	body := io.NopCloser(strings.NewReader("value"))
+
+	object := &objval.Object{
+		ObjectAttrs: objval.ObjectAttrs{Key: "key", Size: ptr.To(int64(len("value")))},
+		Body:        body,
+	}
+
+	require.NoError(t, DecompressObject(object, ""))
+	require.Equal(t, body, object.Body)
+	require.Equal(t, ptr.To(int64(len("value"))), object.Size)
+}
+
+func TestDecompressObjectUnsupported(t *testing.T) {
+	object := &objval.Object{
+		ObjectAttrs: objval.ObjectAttrs{Key: "key.zst"},
+		Body:        io.NopCloser(strings.NewReader("value")),
+	}
+
+	var unsupported *UnsupportedCompressionError
+
+	require.ErrorAs(t, DecompressObject(object, ""), &unsupported)
+	require.Equal(t, CompressionZSTD, unsupported.Compression)
+}
+
+func TestCompressBody(t *testing.T) {
+	body, err := CompressBody(strings.NewReader("value"), CompressionGZIP)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.Equal(t, gzipString(t, "value"), data)
+}
+
+func TestCompressBodyNone(t *testing.T) {
+	original := strings.NewReader("value")
+
+	body, err := CompressBody(original, CompressionNone)
+	require.NoError(t, err)
+	require.Equal(t, original, body)
+}
+
+func TestCompressBodyUnsupported(t *testing.T) {
+	_, err := CompressBody(strings.NewReader("value"), CompressionZSTD)
+
+	var unsupported *UnsupportedCompressionError
+
+	require.ErrorAs(t, err, &unsupported)
+}
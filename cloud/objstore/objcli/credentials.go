@@ -0,0 +1,37 @@
+package objcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+)
+
+// CredentialsRefresher is implemented by clients which cache temporary credentials, allowing long-running operations
+// (e.g. multipart uploads lasting hours) to recover when a request is rejected because the credentials have expired.
+//
+// NOTE: Clients whose credentials are always refreshed transparently by the SDK don't need to implement this interface,
+// they return 'objerr.ErrCredentialsExpired' once refreshing is no longer possible.
+type CredentialsRefresher interface {
+	// RefreshCredentials discards any cached credentials, so that new credentials are retrieved for the next request.
+	RefreshCredentials(ctx context.Context) error
+}
+
+// WithCredentialsRefresh runs the given function, where it fails because the credentials have expired, they're
+// refreshed and the function is run once more.
+//
+// NOTE: 'objerr.ErrCredentialsExpired' is only returned if the credentials couldn't be refreshed, or the refreshed
+// credentials were also rejected.
+func WithCredentialsRefresh(ctx context.Context, refresher CredentialsRefresher, fn func() error) error {
+	err := fn()
+	if refresher == nil || !errors.Is(err, objerr.ErrCredentialsExpired) {
+		return err
+	}
+
+	if err := refresher.RefreshCredentials(ctx); err != nil {
+		return fmt.Errorf("%w: %w", objerr.ErrCredentialsExpired, err)
+	}
+
+	return fn()
+}
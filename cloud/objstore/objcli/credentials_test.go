@@ -0,0 +1,86 @@
+package objcli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+)
+
+type testCredentialsRefresher struct {
+	refreshed int
+	err       error
+}
+
+func (t *testCredentialsRefresher) RefreshCredentials(_ context.Context) error {
+	t.refreshed++
+	return t.err
+}
+
+func TestWithCredentialsRefresh(t *testing.T) {
+	type test struct {
+		name       string
+		errs       []error
+		refreshErr error
+		expected   error
+		calls      int
+		refreshed  int
+	}
+
+	tests := []*test{
+		{
+			name:  "Success",
+			errs:  []error{nil},
+			calls: 1,
+		},
+		{
+			name:     "OtherError",
+			errs:     []error{assert.AnError},
+			expected: assert.AnError,
+			calls:    1,
+		},
+		{
+			name:      "Refreshed",
+			errs:      []error{objerr.ErrCredentialsExpired, nil},
+			calls:     2,
+			refreshed: 1,
+		},
+		{
+			name:      "ExpiredAfterRefresh",
+			errs:      []error{objerr.ErrCredentialsExpired, objerr.ErrCredentialsExpired},
+			expected:  objerr.ErrCredentialsExpired,
+			calls:     2,
+			refreshed: 1,
+		},
+		{
+			name:       "RefreshFailed",
+			errs:       []error{objerr.ErrCredentialsExpired},
+			refreshErr: assert.AnError,
+			expected:   objerr.ErrCredentialsExpired,
+			calls:      1,
+			refreshed:  1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				refresher = &testCredentialsRefresher{err: test.refreshErr}
+				calls     int
+			)
+
+			fn := func() error {
+				calls++
+				return test.errs[calls-1]
+			}
+
+			err := WithCredentialsRefresh(context.Background(), refresher, fn)
+			require.ErrorIs(t, err, test.expected)
+			require.Equal(t, test.calls, calls)
+			require.Equal(t, test.refreshed, refresher.refreshed)
+		})
+	}
+}
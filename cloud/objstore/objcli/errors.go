@@ -2,6 +2,7 @@ package objcli
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
@@ -12,4 +13,29 @@ var (
 	// ErrExpectedNoUploadID is returned if the user has provided an upload id for a client which doesn't generate or
 	// require upload ids.
 	ErrExpectedNoUploadID = errors.New("received an unexpected upload id, cloud provider doesn't required upload ids")
+
+	// ErrObjectWriterClosed is returned when attempting to write to an 'ObjectWriter' which has been closed.
+	ErrObjectWriterClosed = errors.New("object writer is closed")
+
+	// ErrExceededMaxParts is returned by an 'ObjectWriter' when the object would require more parts than the client
+	// supports, a larger part size should be used.
+	ErrExceededMaxParts = errors.New("exceeded maximum number of upload parts")
+
+	// ErrRangeReaderClosed is returned when attempting to read from a reader returned by 'GetObjectRangeReader' which
+	// has been closed.
+	ErrRangeReaderClosed = errors.New("range reader is closed")
+
+	// ErrObjectChanged is returned when resuming a broken read, if the object has been modified since the read began.
+	ErrObjectChanged = errors.New("object has been modified since the read began")
 )
+
+// UnsupportedCompressionError is returned when attempting to compress/decompress an object using a compression
+// algorithm which isn't supported.
+type UnsupportedCompressionError struct {
+	Compression Compression
+}
+
+// Error implements the 'error' interface.
+func (e *UnsupportedCompressionError) Error() string {
+	return fmt.Sprintf("unsupported compression '%s'", e.Compression)
+}
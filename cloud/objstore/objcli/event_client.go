@@ -0,0 +1,303 @@
+package objcli
+
+import (
+	"context"
+	"io"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// EventClient implements the 'objcli.Client' interface by deferring to the underlying client, emitting an event to the
+// given sink when each operation starts/completes; this may be used to wrap any client, for example, a provider client
+// which has been wrapped by a 'RateLimitedClient' or an 'objcrypt.Client'.
+//
+// NOTE: Retried events are emitted by the underlying provider client, where it supports reporting retries (see
+// 'objaws.NewS3Client').
+type EventClient struct {
+	c    Client
+	sink EventSink
+}
+
+var _ Client = (*EventClient)(nil)
+
+// NewEventClient returns an EventClient, which wraps the given client emitting events to the given sink.
+func NewEventClient(c Client, sink EventSink) *EventClient {
+	return &EventClient{c: c, sink: sink}
+}
+
+// start emits a started event for the given operation, see 'startOperation'.
+func (e *EventClient) start(
+	ctx context.Context,
+	operation, bucket, key string,
+) (context.Context, *OperationEvents) {
+	return startOperation(ctx, e.sink, e.c.Provider(), operation, bucket, key)
+}
+
+func (e *EventClient) Provider() objval.Provider {
+	return e.c.Provider()
+}
+
+func (e *EventClient) Capabilities() objval.Capabilities {
+	return e.c.Capabilities()
+}
+
+func (e *EventClient) GetObject(ctx context.Context, opts GetObjectOptions) (*objval.Object, error) {
+	ctx, op := e.start(ctx, "GetObject", opts.Bucket, opts.Key)
+
+	object, err := e.c.GetObject(ctx, opts)
+	op.Complete(objectSize(object), err)
+
+	return object, err
+}
+
+func (e *EventClient) GetObjectAttrs(ctx context.Context, opts GetObjectAttrsOptions) (*objval.ObjectAttrs, error) {
+	ctx, op := e.start(ctx, "GetObjectAttrs", opts.Bucket, opts.Key)
+
+	attrs, err := e.c.GetObjectAttrs(ctx, opts)
+	op.Complete(0, err)
+
+	return attrs, err
+}
+
+func (e *EventClient) PutObject(ctx context.Context, opts PutObjectOptions) error {
+	ctx, op := e.start(ctx, "PutObject", opts.Bucket, opts.Key)
+	size := bodySize(opts.Body)
+
+	err := e.c.PutObject(ctx, opts)
+	op.Complete(size, err)
+
+	return err
+}
+
+func (e *EventClient) CopyObject(ctx context.Context, opts CopyObjectOptions) error {
+	ctx, op := e.start(ctx, "CopyObject", opts.DestinationBucket, opts.DestinationKey)
+
+	err := e.c.CopyObject(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) AppendToObject(ctx context.Context, opts AppendToObjectOptions) error {
+	ctx, op := e.start(ctx, "AppendToObject", opts.Bucket, opts.Key)
+	size := bodySize(opts.Body)
+
+	err := e.c.AppendToObject(ctx, opts)
+	op.Complete(size, err)
+
+	return err
+}
+
+func (e *EventClient) DeleteObjects(ctx context.Context, opts DeleteObjectsOptions) error {
+	ctx, op := e.start(ctx, "DeleteObjects", opts.Bucket, "")
+
+	err := e.c.DeleteObjects(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) DeleteDirectory(ctx context.Context, opts DeleteDirectoryOptions) error {
+	ctx, op := e.start(ctx, "DeleteDirectory", opts.Bucket, opts.Prefix)
+
+	err := e.c.DeleteDirectory(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) IterateObjects(ctx context.Context, opts IterateObjectsOptions) error {
+	ctx, op := e.start(ctx, "IterateObjects", opts.Bucket, opts.Prefix)
+
+	err := e.c.IterateObjects(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) ListDeletedObjects(
+	ctx context.Context,
+	opts ListDeletedObjectsOptions,
+) ([]*objval.DeletedObject, error) {
+	ctx, op := e.start(ctx, "ListDeletedObjects", opts.Bucket, opts.Prefix)
+
+	objects, err := e.c.ListDeletedObjects(ctx, opts)
+	op.Complete(0, err)
+
+	return objects, err
+}
+
+func (e *EventClient) UndeleteObject(ctx context.Context, opts UndeleteObjectOptions) error {
+	ctx, op := e.start(ctx, "UndeleteObject", opts.Bucket, opts.Key)
+
+	err := e.c.UndeleteObject(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) CreateMultipartUpload(ctx context.Context, opts CreateMultipartUploadOptions) (string, error) {
+	ctx, op := e.start(ctx, "CreateMultipartUpload", opts.Bucket, opts.Key)
+
+	id, err := e.c.CreateMultipartUpload(ctx, opts)
+	op.Complete(0, err)
+
+	return id, err
+}
+
+func (e *EventClient) ListParts(ctx context.Context, opts ListPartsOptions) ([]objval.Part, error) {
+	ctx, op := e.start(ctx, "ListParts", opts.Bucket, opts.Key)
+
+	parts, err := e.c.ListParts(ctx, opts)
+	op.Complete(0, err)
+
+	return parts, err
+}
+
+func (e *EventClient) UploadPart(ctx context.Context, opts UploadPartOptions) (objval.Part, error) {
+	ctx, op := e.start(ctx, "UploadPart", opts.Bucket, opts.Key)
+
+	part, err := e.c.UploadPart(ctx, opts)
+	op.Complete(part.Size, err)
+
+	return part, err
+}
+
+func (e *EventClient) UploadPartCopy(ctx context.Context, opts UploadPartCopyOptions) (objval.Part, error) {
+	ctx, op := e.start(ctx, "UploadPartCopy", opts.DestinationBucket, opts.DestinationKey)
+
+	part, err := e.c.UploadPartCopy(ctx, opts)
+	op.Complete(0, err)
+
+	return part, err
+}
+
+func (e *EventClient) CompleteMultipartUpload(ctx context.Context, opts CompleteMultipartUploadOptions) error {
+	ctx, op := e.start(ctx, "CompleteMultipartUpload", opts.Bucket, opts.Key)
+
+	err := e.c.CompleteMultipartUpload(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) AbortMultipartUpload(ctx context.Context, opts AbortMultipartUploadOptions) error {
+	ctx, op := e.start(ctx, "AbortMultipartUpload", opts.Bucket, opts.Key)
+
+	err := e.c.AbortMultipartUpload(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) ListMultipartUploads(
+	ctx context.Context,
+	opts ListMultipartUploadsOptions,
+) ([]objval.MultipartUpload, error) {
+	ctx, op := e.start(ctx, "ListMultipartUploads", opts.Bucket, opts.Prefix)
+
+	uploads, err := e.c.ListMultipartUploads(ctx, opts)
+	op.Complete(0, err)
+
+	return uploads, err
+}
+
+func (e *EventClient) CreateBucket(ctx context.Context, opts CreateBucketOptions) error {
+	ctx, op := e.start(ctx, "CreateBucket", opts.Bucket, "")
+
+	err := e.c.CreateBucket(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) DeleteBucket(ctx context.Context, opts DeleteBucketOptions) error {
+	ctx, op := e.start(ctx, "DeleteBucket", opts.Bucket, "")
+
+	err := e.c.DeleteBucket(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) GetBucketVersioning(
+	ctx context.Context,
+	opts GetBucketVersioningOptions,
+) (objval.VersioningStatus, error) {
+	ctx, op := e.start(ctx, "GetBucketVersioning", opts.Bucket, "")
+
+	status, err := e.c.GetBucketVersioning(ctx, opts)
+	op.Complete(0, err)
+
+	return status, err
+}
+
+func (e *EventClient) GetObjectTags(ctx context.Context, opts GetObjectTagsOptions) (map[string]string, error) {
+	ctx, op := e.start(ctx, "GetObjectTags", opts.Bucket, opts.Key)
+
+	tags, err := e.c.GetObjectTags(ctx, opts)
+	op.Complete(0, err)
+
+	return tags, err
+}
+
+func (e *EventClient) PutObjectTags(ctx context.Context, opts PutObjectTagsOptions) error {
+	ctx, op := e.start(ctx, "PutObjectTags", opts.Bucket, opts.Key)
+
+	err := e.c.PutObjectTags(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) DeleteObjectTags(ctx context.Context, opts DeleteObjectTagsOptions) error {
+	ctx, op := e.start(ctx, "DeleteObjectTags", opts.Bucket, opts.Key)
+
+	err := e.c.DeleteObjectTags(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) GetBucketRegion(ctx context.Context, opts GetBucketRegionOptions) (string, error) {
+	ctx, op := e.start(ctx, "GetBucketRegion", opts.Bucket, "")
+
+	region, err := e.c.GetBucketRegion(ctx, opts)
+	op.Complete(0, err)
+
+	return region, err
+}
+
+func (e *EventClient) SetObjectLock(ctx context.Context, opts SetObjectLockOptions) error {
+	ctx, op := e.start(ctx, "SetObjectLock", opts.Bucket, opts.Key)
+
+	err := e.c.SetObjectLock(ctx, opts)
+	op.Complete(0, err)
+
+	return err
+}
+
+func (e *EventClient) GetBucketLockingStatus(
+	ctx context.Context,
+	opts GetBucketLockingStatusOptions,
+) (objval.BucketLockingStatus, error) {
+	ctx, op := e.start(ctx, "GetBucketLockingStatus", opts.Bucket, "")
+
+	status, err := e.c.GetBucketLockingStatus(ctx, opts)
+	op.Complete(0, err)
+
+	return status, err
+}
+
+func (e *EventClient) QueryObject(ctx context.Context, opts QueryObjectOptions) (io.ReadCloser, error) {
+	ctx, op := e.start(ctx, "QueryObject", opts.Bucket, opts.Key)
+
+	body, err := e.c.QueryObject(ctx, opts)
+	op.Complete(0, err)
+
+	return body, err
+}
+
+func (e *EventClient) Close() error {
+	return e.c.Close()
+}
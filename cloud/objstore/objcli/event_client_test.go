@@ -0,0 +1,86 @@
+package objcli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+func TestEventClient(t *testing.T) {
+	var events []Event
+
+	client := NewEventClient(
+		NewTestClient(t, objval.ProviderAWS),
+		EventSinkFunc(func(event Event) { events = append(events, event) }),
+	)
+
+	err := client.PutObject(context.Background(), PutObjectOptions{
+		Bucket: bucket,
+		Key:    key,
+		Body:   strings.NewReader("value"),
+	})
+	require.NoError(t, err)
+
+	object, err := client.GetObject(context.Background(), GetObjectOptions{Bucket: bucket, Key: key})
+	require.NoError(t, err)
+
+	defer object.Body.Close()
+
+	_, err = client.GetObjectAttrs(context.Background(), GetObjectAttrsOptions{Bucket: bucket, Key: "missing"})
+	require.True(t, objerr.IsNotFoundError(err))
+
+	type summary struct {
+		kind      EventKind
+		operation string
+		key       string
+		size      int64
+		failed    bool
+	}
+
+	summaries := make([]summary, 0, len(events))
+
+	for _, event := range events {
+		require.Equal(t, objval.ProviderAWS, event.Provider)
+		require.Equal(t, bucket, event.Bucket)
+
+		summaries = append(summaries, summary{
+			kind:      event.Kind,
+			operation: event.Operation,
+			key:       event.Key,
+			size:      event.Size,
+			failed:    event.Err != nil,
+		})
+	}
+
+	expected := []summary{
+		{kind: EventStarted, operation: "PutObject", key: key},
+		{kind: EventCompleted, operation: "PutObject", key: key, size: 5},
+		{kind: EventStarted, operation: "GetObject", key: key},
+		{kind: EventCompleted, operation: "GetObject", key: key, size: 5},
+		{kind: EventStarted, operation: "GetObjectAttrs", key: "missing"},
+		{kind: EventCompleted, operation: "GetObjectAttrs", key: "missing", failed: true},
+	}
+
+	require.Equal(t, expected, summaries)
+}
+
+func TestEventClientNested(t *testing.T) {
+	var events []Event
+
+	sink := EventSinkFunc(func(event Event) { events = append(events, event) })
+
+	// Wrapping an 'EventClient' e.g. with a 'RateLimitedClient' then another 'EventClient' shouldn't duplicate events
+	client := NewEventClient(NewRateLimitedClient(NewEventClient(NewTestClient(t, objval.ProviderGCP), sink), nil), sink)
+
+	err := client.CreateBucket(context.Background(), CreateBucketOptions{Bucket: bucket})
+	require.NoError(t, err)
+
+	require.Len(t, events, 2)
+	require.Equal(t, EventStarted, events[0].Kind)
+	require.Equal(t, EventCompleted, events[1].Kind)
+}
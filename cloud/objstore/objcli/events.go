@@ -0,0 +1,273 @@
+package objcli
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// EventKind represents the stage of an operation's lifecycle which resulted in an event.
+type EventKind int
+
+const (
+	// EventStarted is emitted before an operation begins.
+	EventStarted EventKind = iota
+
+	// EventRetried is emitted before a request made by an operation is retried, where the provider supports reporting
+	// retries.
+	EventRetried
+
+	// EventCompleted is emitted once an operation has completed, successfully or otherwise.
+	EventCompleted
+)
+
+// String returns the string representation of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case EventStarted:
+		return "started"
+	case EventRetried:
+		return "retried"
+	case EventCompleted:
+		return "completed"
+	}
+
+	return "unknown"
+}
+
+// Event represents a single stage in the lifecycle of an operation performed by a client.
+type Event struct {
+	// Kind is the stage of the operation which resulted in the event.
+	Kind EventKind
+
+	// ID uniquely identifies the operation within the process, all the events for an operation share the same ID.
+	ID uint64
+
+	// Provider is the cloud provider of the client which performed the operation.
+	Provider objval.Provider
+
+	// Operation is the name of the operation, matching the name of the method on the client e.g. 'GetObject'.
+	Operation string
+
+	// Bucket is the bucket the operation was performed against.
+	Bucket string
+
+	// Key is the key (or prefix) the operation was performed against, empty for bucket level operations.
+	Key string
+
+	// Size is the number of bytes uploaded/downloaded by the operation, where known.
+	//
+	// NOTE: Only populated for completed events, for 'GetObject' this is the length of the returned body, which may not
+	// have been read when the event is emitted.
+	Size int64
+
+	// Attempt is the attempt number of the request which is being retried, starting at two for the first retry.
+	//
+	// NOTE: Only populated for retried events.
+	Attempt int
+
+	// Duration is the time elapsed since the operation started.
+	Duration time.Duration
+
+	// Err is the error which caused a retry, or the error returned by the operation.
+	Err error
+}
+
+// EventSink receives the events emitted by an 'EventClient'.
+//
+// NOTE: May be called concurrently, and should not block since it's called inline with the operation.
+type EventSink interface {
+	Emit(event Event)
+}
+
+// EventSinkFunc allows using a function as an 'EventSink'.
+type EventSinkFunc func(event Event)
+
+// Emit calls the function with the given event.
+func (f EventSinkFunc) Emit(event Event) {
+	f(event)
+}
+
+// sampledEventSink is an 'EventSink' which only forwards the events for a subset of operations.
+type sampledEventSink struct {
+	sink  EventSink
+	every uint64
+
+	// pending contains the operations which weren't sampled, and are still in progress.
+	pending     map[uint64]*sampledOperation
+	pendingLock sync.Mutex
+}
+
+// sampledOperation tracks an in progress operation which wasn't sampled.
+type sampledOperation struct {
+	// started is the started event for the operation, which is forwarded if the operation encounters an error.
+	started Event
+
+	// forward indicates that the operation has encountered an error, so the remainder of its events are forwarded.
+	forward bool
+}
+
+// NewSampledEventSink returns a sink which forwards the events for one in every given number of operations. This may be
+// used to reduce the overhead of emitting events for chatty workloads, for example, uploading many small objects.
+//
+// Operations which encounter an error are always forwarded, starting from their started event; this ensures that every
+// forwarded completed/retried event is preceded by the started event for the same operation.
+//
+// NOTE: Sampling is performed per-operation, so the events for a sampled operation are always forwarded together.
+func NewSampledEventSink(sink EventSink, every uint64) EventSink {
+	return &sampledEventSink{sink: sink, every: max(1, every), pending: make(map[uint64]*sampledOperation)}
+}
+
+func (s *sampledEventSink) Emit(event Event) {
+	if (event.ID-1)%s.every == 0 {
+		s.sink.Emit(event)
+		return
+	}
+
+	s.pendingLock.Lock()
+
+	if event.Kind == EventStarted {
+		s.pending[event.ID] = &sampledOperation{started: event}
+		s.pendingLock.Unlock()
+
+		return
+	}
+
+	op, ok := s.pending[event.ID]
+	if !ok {
+		s.pendingLock.Unlock()
+		return
+	}
+
+	if event.Kind == EventCompleted {
+		delete(s.pending, event.ID)
+	}
+
+	var (
+		forward = op.forward || event.Err != nil
+		started = forward && !op.forward
+	)
+
+	op.forward = forward
+
+	s.pendingLock.Unlock()
+
+	if started {
+		s.sink.Emit(op.started)
+	}
+
+	if forward {
+		s.sink.Emit(event)
+	}
+}
+
+// operationID is used to assign a unique identifier to each operation.
+var operationID atomic.Uint64
+
+// operationKey is the context key used to store the events for the current operation.
+type operationKey struct{}
+
+// OperationEvents emits the events for a single operation, the events for the current operation may be retrieved using
+// 'OperationFromContext' e.g. so that a provider client may report retries.
+//
+// NOTE: The methods are safe to call on a <nil> value, in which case no events are emitted.
+type OperationEvents struct {
+	sink     EventSink
+	template Event
+	start    time.Time
+}
+
+// startOperation emits a started event for the given operation, returning a context which should be used to perform
+// the operation; a <nil> sink disables events, in which case the given context is returned.
+//
+// NOTE: Operations performed using a context which already belongs to an operation are considered part of that
+// operation and don't emit their own events, for example, when nesting an 'EventClient'.
+func startOperation(
+	ctx context.Context,
+	sink EventSink,
+	provider objval.Provider,
+	operation, bucket, key string,
+) (context.Context, *OperationEvents) {
+	if sink == nil || OperationFromContext(ctx) != nil {
+		return ctx, nil
+	}
+
+	op := &OperationEvents{
+		sink: sink,
+		template: Event{
+			ID:        operationID.Add(1),
+			Provider:  provider,
+			Operation: operation,
+			Bucket:    bucket,
+			Key:       key,
+		},
+		start: time.Now(),
+	}
+
+	op.emit(EventStarted, func(_ *Event) {})
+
+	return context.WithValue(ctx, operationKey{}, op), op
+}
+
+// OperationFromContext returns the events for the operation being performed using the given context, or <nil> if
+// events aren't enabled.
+func OperationFromContext(ctx context.Context) *OperationEvents {
+	op, _ := ctx.Value(operationKey{}).(*OperationEvents)
+	return op
+}
+
+// Retried emits a retried event, where the given attempt is about to be made after the previous attempt failed with
+// the given error.
+func (o *OperationEvents) Retried(attempt int, err error) {
+	if o == nil {
+		return
+	}
+
+	o.emit(EventRetried, func(event *Event) { event.Attempt, event.Err = attempt, err })
+}
+
+// Complete emits a completed event, where the operation transferred the given number of bytes.
+func (o *OperationEvents) Complete(size int64, err error) {
+	if o == nil {
+		return
+	}
+
+	o.emit(EventCompleted, func(event *Event) { event.Size, event.Err = size, err })
+}
+
+// emit emits an event of the given kind, populated using the given function.
+func (o *OperationEvents) emit(kind EventKind, fn func(event *Event)) {
+	event := o.template
+
+	event.Kind = kind
+	event.Duration = time.Since(o.start)
+
+	fn(&event)
+
+	o.sink.Emit(event)
+}
+
+// objectSize returns the size of the given object, or zero if the object is <nil> or its size is unknown.
+func objectSize(object *objval.Object) int64 {
+	if object == nil || object.Size == nil {
+		return 0
+	}
+
+	return *object.Size
+}
+
+// bodySize returns the number of bytes remaining in the given body, or zero if it's unknown; this should be called
+// before the body is read.
+func bodySize(body io.Seeker) int64 {
+	if body == nil {
+		return 0
+	}
+
+	n, _ := SeekerLength(body)
+
+	return n
+}
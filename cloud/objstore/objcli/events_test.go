@@ -0,0 +1,153 @@
+package objcli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+func TestStartOperationNilSink(t *testing.T) {
+	ctx := context.Background()
+
+	got, op := startOperation(ctx, nil, objval.ProviderAWS, "GetObject", bucket, key)
+	require.Equal(t, ctx, got)
+	require.Nil(t, op)
+	require.Nil(t, OperationFromContext(got))
+
+	// Must not panic
+	op.Retried(2, errors.New("failed"))
+	op.Complete(64, nil)
+}
+
+func TestOperationEvents(t *testing.T) {
+	var events []Event
+
+	sink := EventSinkFunc(func(event Event) { events = append(events, event) })
+
+	ctx, op := startOperation(context.Background(), sink, objval.ProviderAWS, "PutObject", bucket, key)
+	require.Equal(t, op, OperationFromContext(ctx))
+
+	failed := errors.New("failed")
+
+	op.Retried(2, failed)
+	op.Complete(64, nil)
+
+	require.Len(t, events, 3)
+
+	for _, event := range events {
+		require.Equal(t, events[0].ID, event.ID)
+		require.Equal(t, objval.ProviderAWS, event.Provider)
+		require.Equal(t, "PutObject", event.Operation)
+		require.Equal(t, bucket, event.Bucket)
+		require.Equal(t, key, event.Key)
+	}
+
+	require.Equal(t, EventStarted, events[0].Kind)
+
+	require.Equal(t, EventRetried, events[1].Kind)
+	require.Equal(t, 2, events[1].Attempt)
+	require.ErrorIs(t, events[1].Err, failed)
+
+	require.Equal(t, EventCompleted, events[2].Kind)
+	require.Equal(t, int64(64), events[2].Size)
+	require.NoError(t, events[2].Err)
+}
+
+func TestStartOperationNested(t *testing.T) {
+	var events []Event
+
+	sink := EventSinkFunc(func(event Event) { events = append(events, event) })
+
+	ctx, outer := startOperation(context.Background(), sink, objval.ProviderGCP, "ListParts", bucket, key)
+
+	_, inner := startOperation(ctx, sink, objval.ProviderGCP, "IterateObjects", bucket, key)
+	require.Nil(t, inner)
+
+	outer.Complete(0, nil)
+
+	require.Len(t, events, 2)
+	require.Equal(t, "ListParts", events[0].Operation)
+	require.Equal(t, "ListParts", events[1].Operation)
+}
+
+func TestSampledEventSink(t *testing.T) {
+	var events []Event
+
+	sink := NewSampledEventSink(EventSinkFunc(func(event Event) { events = append(events, event) }), 4)
+
+	failed := errors.New("failed")
+
+	for id := uint64(1); id <= 8; id++ {
+		sink.Emit(Event{Kind: EventStarted, ID: id})
+
+		// The first retry succeeds, so the operation is forwarded from the retry onwards
+		if id == 6 {
+			sink.Emit(Event{Kind: EventRetried, ID: id, Attempt: 2, Err: failed})
+		}
+
+		var err error
+		if id == 7 {
+			err = failed
+		}
+
+		sink.Emit(Event{Kind: EventCompleted, ID: id, Err: err})
+	}
+
+	type summary struct {
+		kind   EventKind
+		id     uint64
+		failed bool
+	}
+
+	summaries := make([]summary, 0, len(events))
+
+	for _, event := range events {
+		summaries = append(summaries, summary{kind: event.Kind, id: event.ID, failed: event.Err != nil})
+	}
+
+	expected := []summary{
+		{kind: EventStarted, id: 1},
+		{kind: EventCompleted, id: 1},
+		{kind: EventStarted, id: 5},
+		{kind: EventCompleted, id: 5},
+		{kind: EventStarted, id: 6},
+		{kind: EventRetried, id: 6, failed: true},
+		{kind: EventCompleted, id: 6},
+		{kind: EventStarted, id: 7},
+		{kind: EventCompleted, id: 7, failed: true},
+	}
+
+	require.Equal(t, expected, summaries)
+
+	// Completed operations should no longer be tracked
+	require.Empty(t, sink.(*sampledEventSink).pending)
+}
+
+func TestEventKindString(t *testing.T) {
+	require.Equal(t, "started", EventStarted.String())
+	require.Equal(t, "retried", EventRetried.String())
+	require.Equal(t, "completed", EventCompleted.String())
+	require.Equal(t, "unknown", EventKind(-1).String())
+}
+
+func TestObjectSize(t *testing.T) {
+	require.Zero(t, objectSize(nil))
+	require.Zero(t, objectSize(&objval.Object{}))
+	require.Equal(t, int64(64), objectSize(&objval.Object{ObjectAttrs: objval.ObjectAttrs{Size: ptr.To[int64](64)}}))
+}
+
+func TestBodySize(t *testing.T) {
+	require.Zero(t, bodySize(nil))
+
+	body := strings.NewReader("value")
+	require.Equal(t, int64(5), bodySize(body))
+
+	// The body must not have been consumed
+	require.Equal(t, 5, body.Len())
+}
@@ -5,6 +5,8 @@ package objcli
 import (
 	context "context"
 
+	io "io"
+
 	mock "github.com/stretchr/testify/mock"
 
 	objval "github.com/couchbase/tools-common/cloud/v6/objstore/objval"
@@ -51,6 +53,24 @@ func (_m *MockClient) AppendToObject(ctx context.Context, opts AppendToObjectOpt
 	return r0
 }
 
+// Capabilities provides a mock function with given fields:
+func (_m *MockClient) Capabilities() objval.Capabilities {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Capabilities")
+	}
+
+	var r0 objval.Capabilities
+	if rf, ok := ret.Get(0).(func() objval.Capabilities); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(objval.Capabilities)
+	}
+
+	return r0
+}
+
 // Close provides a mock function with given fields:
 func (_m *MockClient) Close() error {
 	ret := _m.Called()
@@ -105,6 +125,24 @@ func (_m *MockClient) CopyObject(ctx context.Context, opts CopyObjectOptions) er
 	return r0
 }
 
+// CreateBucket provides a mock function with given fields: ctx, opts
+func (_m *MockClient) CreateBucket(ctx context.Context, opts CreateBucketOptions) error {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBucket")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, CreateBucketOptions) error); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateMultipartUpload provides a mock function with given fields: ctx, opts
 func (_m *MockClient) CreateMultipartUpload(ctx context.Context, opts CreateMultipartUploadOptions) (string, error) {
 	ret := _m.Called(ctx, opts)
@@ -133,6 +171,24 @@ func (_m *MockClient) CreateMultipartUpload(ctx context.Context, opts CreateMult
 	return r0, r1
 }
 
+// DeleteBucket provides a mock function with given fields: ctx, opts
+func (_m *MockClient) DeleteBucket(ctx context.Context, opts DeleteBucketOptions) error {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBucket")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, DeleteBucketOptions) error); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteDirectory provides a mock function with given fields: ctx, opts
 func (_m *MockClient) DeleteDirectory(ctx context.Context, opts DeleteDirectoryOptions) error {
 	ret := _m.Called(ctx, opts)
@@ -151,6 +207,24 @@ func (_m *MockClient) DeleteDirectory(ctx context.Context, opts DeleteDirectoryO
 	return r0
 }
 
+// DeleteObjectTags provides a mock function with given fields: ctx, opts
+func (_m *MockClient) DeleteObjectTags(ctx context.Context, opts DeleteObjectTagsOptions) error {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteObjectTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, DeleteObjectTagsOptions) error); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteObjects provides a mock function with given fields: ctx, opts
 func (_m *MockClient) DeleteObjects(ctx context.Context, opts DeleteObjectsOptions) error {
 	ret := _m.Called(ctx, opts)
@@ -169,6 +243,90 @@ func (_m *MockClient) DeleteObjects(ctx context.Context, opts DeleteObjectsOptio
 	return r0
 }
 
+// GetBucketLockingStatus provides a mock function with given fields: ctx, opts
+func (_m *MockClient) GetBucketLockingStatus(ctx context.Context, opts GetBucketLockingStatusOptions) (objval.BucketLockingStatus, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBucketLockingStatus")
+	}
+
+	var r0 objval.BucketLockingStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, GetBucketLockingStatusOptions) (objval.BucketLockingStatus, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, GetBucketLockingStatusOptions) objval.BucketLockingStatus); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Get(0).(objval.BucketLockingStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, GetBucketLockingStatusOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBucketRegion provides a mock function with given fields: ctx, opts
+func (_m *MockClient) GetBucketRegion(ctx context.Context, opts GetBucketRegionOptions) (string, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBucketRegion")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, GetBucketRegionOptions) (string, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, GetBucketRegionOptions) string); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, GetBucketRegionOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBucketVersioning provides a mock function with given fields: ctx, opts
+func (_m *MockClient) GetBucketVersioning(ctx context.Context, opts GetBucketVersioningOptions) (objval.VersioningStatus, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBucketVersioning")
+	}
+
+	var r0 objval.VersioningStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, GetBucketVersioningOptions) (objval.VersioningStatus, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, GetBucketVersioningOptions) objval.VersioningStatus); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Get(0).(objval.VersioningStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, GetBucketVersioningOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetObject provides a mock function with given fields: ctx, opts
 func (_m *MockClient) GetObject(ctx context.Context, opts GetObjectOptions) (*objval.Object, error) {
 	ret := _m.Called(ctx, opts)
@@ -229,6 +387,36 @@ func (_m *MockClient) GetObjectAttrs(ctx context.Context, opts GetObjectAttrsOpt
 	return r0, r1
 }
 
+// GetObjectTags provides a mock function with given fields: ctx, opts
+func (_m *MockClient) GetObjectTags(ctx context.Context, opts GetObjectTagsOptions) (map[string]string, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetObjectTags")
+	}
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, GetObjectTagsOptions) (map[string]string, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, GetObjectTagsOptions) map[string]string); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, GetObjectTagsOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IterateObjects provides a mock function with given fields: ctx, opts
 func (_m *MockClient) IterateObjects(ctx context.Context, opts IterateObjectsOptions) error {
 	ret := _m.Called(ctx, opts)
@@ -247,6 +435,66 @@ func (_m *MockClient) IterateObjects(ctx context.Context, opts IterateObjectsOpt
 	return r0
 }
 
+// ListDeletedObjects provides a mock function with given fields: ctx, opts
+func (_m *MockClient) ListDeletedObjects(ctx context.Context, opts ListDeletedObjectsOptions) ([]*objval.DeletedObject, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeletedObjects")
+	}
+
+	var r0 []*objval.DeletedObject
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ListDeletedObjectsOptions) ([]*objval.DeletedObject, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ListDeletedObjectsOptions) []*objval.DeletedObject); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*objval.DeletedObject)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ListDeletedObjectsOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMultipartUploads provides a mock function with given fields: ctx, opts
+func (_m *MockClient) ListMultipartUploads(ctx context.Context, opts ListMultipartUploadsOptions) ([]objval.MultipartUpload, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMultipartUploads")
+	}
+
+	var r0 []objval.MultipartUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ListMultipartUploadsOptions) ([]objval.MultipartUpload, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ListMultipartUploadsOptions) []objval.MultipartUpload); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]objval.MultipartUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ListMultipartUploadsOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListParts provides a mock function with given fields: ctx, opts
 func (_m *MockClient) ListParts(ctx context.Context, opts ListPartsOptions) ([]objval.Part, error) {
 	ret := _m.Called(ctx, opts)
@@ -313,6 +561,90 @@ func (_m *MockClient) PutObject(ctx context.Context, opts PutObjectOptions) erro
 	return r0
 }
 
+// PutObjectTags provides a mock function with given fields: ctx, opts
+func (_m *MockClient) PutObjectTags(ctx context.Context, opts PutObjectTagsOptions) error {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutObjectTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, PutObjectTagsOptions) error); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueryObject provides a mock function with given fields: ctx, opts
+func (_m *MockClient) QueryObject(ctx context.Context, opts QueryObjectOptions) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryObject")
+	}
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, QueryObjectOptions) (io.ReadCloser, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, QueryObjectOptions) io.ReadCloser); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, QueryObjectOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetObjectLock provides a mock function with given fields: ctx, opts
+func (_m *MockClient) SetObjectLock(ctx context.Context, opts SetObjectLockOptions) error {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetObjectLock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, SetObjectLockOptions) error); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UndeleteObject provides a mock function with given fields: ctx, opts
+func (_m *MockClient) UndeleteObject(ctx context.Context, opts UndeleteObjectOptions) error {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UndeleteObject")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, UndeleteObjectOptions) error); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UploadPart provides a mock function with given fields: ctx, opts
 func (_m *MockClient) UploadPart(ctx context.Context, opts UploadPartOptions) (objval.Part, error) {
 	ret := _m.Called(ctx, opts)
@@ -0,0 +1,106 @@
+package objaws
+
+import (
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// newChecksumHash returns a new hash for the given checksum algorithm.
+func newChecksumHash(algorithm types.ChecksumAlgorithm) (hash.Hash, error) {
+	switch algorithm {
+	case types.ChecksumAlgorithmCrc32:
+		return crc32.NewIEEE(), nil
+	case types.ChecksumAlgorithmCrc32c:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
+	case types.ChecksumAlgorithmSha1:
+		return sha1.New(), nil //nolint:gosec
+	case types.ChecksumAlgorithmSha256:
+		return sha256.New(), nil
+	}
+
+	return nil, &UnsupportedChecksumAlgorithmError{Algorithm: algorithm}
+}
+
+// partChecksum returns the base64 encoded checksum of the given body, seeking back to the current position once done.
+func partChecksum(algorithm types.ChecksumAlgorithm, body io.ReadSeeker) (string, error) {
+	hasher, err := newChecksumHash(algorithm)
+	if err != nil {
+		return "", err // Purposefully not wrapped
+	}
+
+	_, err = objcli.CopyReadSeeker(hasher, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate checksum: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// compositeChecksum returns the checksum S3 is expected to calculate for an object created by completing a multipart
+// upload with the given parts; this is the checksum of the concatenated (decoded) part checksums, suffixed with the
+// number of parts. Returns an empty string if any of the parts are missing a checksum.
+func compositeChecksum(algorithm types.ChecksumAlgorithm, parts []objval.Part) (string, error) {
+	hasher, err := newChecksumHash(algorithm)
+	if err != nil {
+		return "", err // Purposefully not wrapped
+	}
+
+	for _, part := range parts {
+		if part.Checksum == "" {
+			return "", nil
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(part.Checksum)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode checksum for part %d: %w", part.Number, err)
+		}
+
+		_, _ = hasher.Write(decoded)
+	}
+
+	return fmt.Sprintf("%s-%d", base64.StdEncoding.EncodeToString(hasher.Sum(nil)), len(parts)), nil
+}
+
+// getChecksum returns the checksum for the given algorithm, from the given set of checksums returned by S3.
+func getChecksum(algorithm types.ChecksumAlgorithm, crc32, crc32c, sha1, sha256 *string) *string {
+	switch algorithm {
+	case types.ChecksumAlgorithmCrc32:
+		return crc32
+	case types.ChecksumAlgorithmCrc32c:
+		return crc32c
+	case types.ChecksumAlgorithmSha1:
+		return sha1
+	case types.ChecksumAlgorithmSha256:
+		return sha256
+	}
+
+	return nil
+}
+
+// setChecksum sets the given checksum in the field (from the given set of fields) for the given algorithm.
+func setChecksum(algorithm types.ChecksumAlgorithm, checksum string, crc32, crc32c, sha1, sha256 **string) {
+	if checksum == "" {
+		return
+	}
+
+	switch algorithm {
+	case types.ChecksumAlgorithmCrc32:
+		*crc32 = &checksum
+	case types.ChecksumAlgorithmCrc32c:
+		*crc32c = &checksum
+	case types.ChecksumAlgorithmSha1:
+		*sha1 = &checksum
+	case types.ChecksumAlgorithmSha256:
+		*sha256 = &checksum
+	}
+}
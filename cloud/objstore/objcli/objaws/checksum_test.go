@@ -0,0 +1,75 @@
+package objaws
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+func TestPartChecksum(t *testing.T) {
+	type test struct {
+		name      string
+		algorithm types.ChecksumAlgorithm
+		expected  string
+	}
+
+	tests := []*test{
+		{
+			name:      "CRC32C",
+			algorithm: types.ChecksumAlgorithmCrc32c,
+			expected:  "4oIZdg==",
+		},
+		{
+			name:      "SHA256",
+			algorithm: types.ChecksumAlgorithmSha256,
+			expected:  "PJaDAX+eS/M9D77dJr8UP9ct6bndFFRBt18GBAR+oo4=",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := strings.NewReader("value1")
+
+			checksum, err := partChecksum(test.algorithm, body)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, checksum)
+
+			// The body should be left at its original position, so that it may be uploaded
+			require.Equal(t, 6, body.Len())
+		})
+	}
+}
+
+func TestPartChecksumUnsupportedAlgorithm(t *testing.T) {
+	_, err := partChecksum("MD4", strings.NewReader("value"))
+
+	var unsupported *UnsupportedChecksumAlgorithmError
+
+	require.ErrorAs(t, err, &unsupported)
+}
+
+func TestCompositeChecksum(t *testing.T) {
+	parts := []objval.Part{
+		{Number: 1, Checksum: "4oIZdg=="},
+		{Number: 2, Checksum: "8dLqgg=="},
+	}
+
+	checksum, err := compositeChecksum(types.ChecksumAlgorithmCrc32c, parts)
+	require.NoError(t, err)
+	require.Equal(t, "r/GdeA==-2", checksum)
+}
+
+func TestCompositeChecksumMissingPartChecksum(t *testing.T) {
+	parts := []objval.Part{
+		{Number: 1, Checksum: "4oIZdg=="},
+		{Number: 2},
+	}
+
+	checksum, err := compositeChecksum(types.ChecksumAlgorithmCrc32c, parts)
+	require.NoError(t, err)
+	require.Empty(t, checksum)
+}
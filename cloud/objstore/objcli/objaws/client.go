@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"path"
 	"regexp"
+	"sync"
 
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
@@ -18,6 +19,7 @@ import (
 	"github.com/couchbase/tools-common/types/v2/ptr"
 	"github.com/couchbase/tools-common/utils/v3/system"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
@@ -25,11 +27,19 @@ import (
 
 // Client implements the 'objcli.Client' interface allowing the creation/management of objects stored in AWS S3.
 type Client struct {
-	serviceAPI serviceAPI
-	logger     *slog.Logger
+	serviceAPI        serviceAPI
+	checksumAlgorithm types.ChecksumAlgorithm
+	capabilities      objval.Capabilities
+	credentials       aws.CredentialsProvider
+	logger            *slog.Logger
+	listPageSize      int32
+	listPrefetch      int
 }
 
-var _ objcli.Client = (*Client)(nil)
+var (
+	_ objcli.Client               = (*Client)(nil)
+	_ objcli.CredentialsRefresher = (*Client)(nil)
+)
 
 // ClientOptions encapsulates the options for creating a new AWS Client.
 type ClientOptions struct {
@@ -39,6 +49,33 @@ type ClientOptions struct {
 	// NOTE: Required
 	ServiceAPI serviceAPI
 
+	// ChecksumAlgorithm is the algorithm used to calculate a checksum for each part of a multipart upload, which S3
+	// validates upon receipt. When completing the upload, the checksum calculated by S3 for the whole object is
+	// validated against the part checksums, a 'ChecksumMismatchError' is returned if they differ. When omitted, parts
+	// are only validated using their MD5 digest.
+	//
+	// NOTE: Only applies to multipart uploads created by this client.
+	ChecksumAlgorithm types.ChecksumAlgorithm
+
+	// Capabilities overrides the capabilities reported by the client, this should be used when interfacing with an S3
+	// compatible store which doesn't support the same functionality/limits as AWS. Defaults to 'Capabilities'.
+	Capabilities *objval.Capabilities
+
+	// Credentials are the credentials used by the 'ServiceAPI', these are invalidated when a request is rejected because
+	// they've expired, allowing long-running operations to continue using refreshed credentials.
+	//
+	// NOTE: Defaults to the credentials of the 'ServiceAPI' when it's an '*s3.Client'.
+	Credentials aws.CredentialsProvider
+
+	// ListPageSize is the maximum number of keys returned in each page when listing objects, defaults to 'PageSize'.
+	//
+	// NOTE: S3 compatible stores may return fewer keys than requested.
+	ListPageSize int32
+
+	// ListPrefetch is the number of pages which are fetched whilst the current page is being processed, when listing
+	// objects; defaults to 'DefaultListPrefetch', a negative value disables prefetching.
+	ListPrefetch int
+
 	// Logger is the passed logger which implements a custom Log method
 	Logger *slog.Logger
 }
@@ -48,6 +85,18 @@ func (c *ClientOptions) defaults() {
 	if c.Logger == nil {
 		c.Logger = slog.Default()
 	}
+
+	if c.Capabilities == nil {
+		c.Capabilities = &Capabilities
+	}
+
+	if c.ListPrefetch == 0 {
+		c.ListPrefetch = DefaultListPrefetch
+	}
+
+	if client, ok := c.ServiceAPI.(*s3.Client); ok && c.Credentials == nil {
+		c.Credentials = client.Options().Credentials
+	}
 }
 
 // NewClient returns a new client which uses the given 'serviceAPI', in general this should be the one created using the
@@ -57,8 +106,13 @@ func NewClient(options ClientOptions) *Client {
 	options.defaults()
 
 	client := Client{
-		serviceAPI: options.ServiceAPI,
-		logger:     options.Logger,
+		serviceAPI:        options.ServiceAPI,
+		checksumAlgorithm: options.ChecksumAlgorithm,
+		capabilities:      *options.Capabilities,
+		credentials:       options.Credentials,
+		logger:            options.Logger,
+		listPageSize:      options.ListPageSize,
+		listPrefetch:      options.ListPrefetch,
 	}
 
 	return &client
@@ -68,6 +122,23 @@ func (c *Client) Provider() objval.Provider {
 	return objval.ProviderAWS
 }
 
+func (c *Client) Capabilities() objval.Capabilities {
+	return c.capabilities
+}
+
+// RefreshCredentials invalidates the cached credentials (e.g. those for an assumed role), so that they're retrieved
+// again before the next request is sent.
+func (c *Client) RefreshCredentials(_ context.Context) error {
+	cache, ok := c.credentials.(*aws.CredentialsCache)
+	if !ok {
+		return nil
+	}
+
+	cache.Invalidate()
+
+	return nil
+}
+
 func (c *Client) GetObject(ctx context.Context, opts objcli.GetObjectOptions) (*objval.Object, error) {
 	if err := opts.ByteRange.Valid(false); err != nil {
 		return nil, err // Purposefully not wrapped
@@ -82,6 +153,14 @@ func (c *Client) GetObject(ctx context.Context, opts objcli.GetObjectOptions) (*
 		input.Range = ptr.To(opts.ByteRange.ToRangeHeader())
 	}
 
+	if !opts.IfModifiedSince.IsZero() {
+		input.IfModifiedSince = ptr.To(opts.IfModifiedSince)
+	}
+
+	if opts.IfNoneMatch != "" {
+		input.IfNoneMatch = ptr.To(opts.IfNoneMatch)
+	}
+
 	resp, err := c.serviceAPI.GetObject(ctx, input)
 	if err != nil {
 		return nil, handleError(input.Bucket, input.Key, err)
@@ -98,6 +177,16 @@ func (c *Client) GetObject(ctx context.Context, opts objcli.GetObjectOptions) (*
 		Body:        resp.Body,
 	}
 
+	if !opts.Decompress {
+		return object, nil
+	}
+
+	err = objcli.DecompressObject(object, ptr.From(resp.ContentEncoding))
+	if err != nil {
+		object.Body.Close()
+		return nil, fmt.Errorf("failed to decompress object: %w", err)
+	}
+
 	return object, nil
 }
 
@@ -117,35 +206,220 @@ func (c *Client) GetObjectAttrs(ctx context.Context, opts objcli.GetObjectAttrsO
 		ETag:         resp.ETag,
 		Size:         resp.ContentLength,
 		LastModified: resp.LastModified,
+		Metadata:     objcli.NormalizeMetadata(resp.Metadata),
 	}
 
 	return attrs, nil
 }
 
 func (c *Client) PutObject(ctx context.Context, opts objcli.PutObjectOptions) error {
+	body, err := objcli.CompressBody(opts.Body, opts.Compress)
+	if err != nil {
+		return err // Purposefully not wrapped
+	}
+
 	input := &s3.PutObjectInput{
-		Body:   opts.Body,
+		Body:   body,
+		Bucket: ptr.To(opts.Bucket),
+		Key:    ptr.To(opts.Key),
+	}
+
+	if len(opts.Tags) != 0 {
+		input.Tagging = ptr.To(encodeTags(opts.Tags))
+	}
+
+	if len(opts.Metadata) != 0 {
+		input.Metadata = opts.Metadata
+	}
+
+	if opts.Compress != objcli.CompressionNone {
+		input.ContentEncoding = ptr.To(string(opts.Compress))
+	}
+
+	_, err = c.serviceAPI.PutObject(ctx, input)
+
+	return handleError(input.Bucket, input.Key, err)
+}
+
+func (c *Client) GetObjectTags(ctx context.Context, opts objcli.GetObjectTagsOptions) (map[string]string, error) {
+	input := &s3.GetObjectTaggingInput{
 		Bucket: ptr.To(opts.Bucket),
 		Key:    ptr.To(opts.Key),
 	}
 
-	_, err := c.serviceAPI.PutObject(ctx, input)
+	resp, err := c.serviceAPI.GetObjectTagging(ctx, input)
+	if err != nil {
+		return nil, handleError(input.Bucket, input.Key, err)
+	}
+
+	tags := make(map[string]string, len(resp.TagSet))
+
+	for _, tag := range resp.TagSet {
+		tags[ptr.From(tag.Key)] = ptr.From(tag.Value)
+	}
+
+	return tags, nil
+}
+
+func (c *Client) PutObjectTags(ctx context.Context, opts objcli.PutObjectTagsOptions) error {
+	input := &s3.PutObjectTaggingInput{
+		Bucket:  ptr.To(opts.Bucket),
+		Key:     ptr.To(opts.Key),
+		Tagging: &types.Tagging{TagSet: tagSet(opts.Tags)},
+	}
+
+	_, err := c.serviceAPI.PutObjectTagging(ctx, input)
 
 	return handleError(input.Bucket, input.Key, err)
 }
 
+func (c *Client) DeleteObjectTags(ctx context.Context, opts objcli.DeleteObjectTagsOptions) error {
+	input := &s3.DeleteObjectTaggingInput{
+		Bucket: ptr.To(opts.Bucket),
+		Key:    ptr.To(opts.Key),
+	}
+
+	_, err := c.serviceAPI.DeleteObjectTagging(ctx, input)
+
+	return handleError(input.Bucket, input.Key, err)
+}
+
+// CopyObject copies the given object, objects larger than 'MaxCopySize' are copied using a multipart upload where the
+// parts are copied concurrently.
 func (c *Client) CopyObject(ctx context.Context, opts objcli.CopyObjectOptions) error {
+	attrs, err := c.GetObjectAttrs(ctx, objcli.GetObjectAttrsOptions{Bucket: opts.SourceBucket, Key: opts.SourceKey})
+	if err != nil {
+		return fmt.Errorf("failed to get object attributes: %w", err)
+	}
+
+	if ptr.From(attrs.Size) > MaxCopySize {
+		return c.createMPUThenCopy(ctx, opts, attrs)
+	}
+
 	input := &s3.CopyObjectInput{
 		Bucket:     ptr.To(opts.DestinationBucket),
 		Key:        ptr.To(opts.DestinationKey),
 		CopySource: ptr.To(url.PathEscape(opts.SourceBucket + "/" + opts.SourceKey)),
 	}
 
-	_, err := c.serviceAPI.CopyObject(ctx, input)
+	_, err = c.serviceAPI.CopyObject(ctx, input)
 
 	return handleError(nil, nil, err)
 }
 
+// createMPUThenCopy creates a multipart upload, then copies the source object into it; this should be used for objects
+// which are larger than 'MaxCopySize'.
+func (c *Client) createMPUThenCopy(
+	ctx context.Context,
+	opts objcli.CopyObjectOptions,
+	attrs *objval.ObjectAttrs,
+) error {
+	// Unlike 'CopyObject', a multipart copy doesn't preserve the metadata of the source object
+	id, err := c.CreateMultipartUpload(ctx, objcli.CreateMultipartUploadOptions{
+		Bucket:   opts.DestinationBucket,
+		Key:      opts.DestinationKey,
+		Metadata: attrs.Metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	err = c.copyParts(ctx, opts, id, attrs)
+	if err == nil {
+		return nil
+	}
+
+	aopts := objcli.AbortMultipartUploadOptions{
+		Bucket:   opts.DestinationBucket,
+		UploadID: id,
+		Key:      opts.DestinationKey,
+	}
+
+	if err := c.AbortMultipartUpload(ctx, aopts); err != nil {
+		c.logger.Error(
+			"failed to abort multipart upload, it should be aborted manually", "id", id, "key", opts.DestinationKey,
+		)
+	}
+
+	return err
+}
+
+// copyParts concurrently copies the source object into the given multipart upload, then completes it.
+func (c *Client) copyParts(
+	ctx context.Context,
+	opts objcli.CopyObjectOptions,
+	id string,
+	attrs *objval.ObjectAttrs,
+) error {
+	var (
+		// The attributes used here are obtained from 'GetObjectAttrs' so the 'Size' will be non-nil
+		size     = ptr.From(attrs.Size)
+		partSize = copyPartSize(size)
+		parts    = make([]objval.Part, (size+partSize-1)/partSize)
+	)
+
+	pool := hofp.NewPool(hofp.Options{
+		Context: ctx,
+		Size:    system.NumWorkers(len(parts)),
+	})
+
+	// cp copies the given part, each part is written to a distinct index so access to 'parts' doesn't need guarding
+	cp := func(ctx context.Context, index int) error {
+		start := int64(index) * partSize
+
+		part, err := c.UploadPartCopy(ctx, objcli.UploadPartCopyOptions{
+			DestinationBucket: opts.DestinationBucket,
+			UploadID:          id,
+			DestinationKey:    opts.DestinationKey,
+			SourceBucket:      opts.SourceBucket,
+			SourceKey:         opts.SourceKey,
+			Number:            index + 1,
+			ByteRange:         &objval.ByteRange{Start: start, End: min(start+partSize, size) - 1},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy part %d: %w", index+1, err)
+		}
+
+		parts[index] = part
+
+		return nil
+	}
+
+	queue := func(index int) error {
+		return pool.Queue(func(ctx context.Context) error { return cp(ctx, index) })
+	}
+
+	for index := range parts {
+		if queue(index) != nil {
+			break
+		}
+	}
+
+	err := pool.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to copy parts: %w", err)
+	}
+
+	err = c.CompleteMultipartUpload(ctx, objcli.CompleteMultipartUploadOptions{
+		Bucket:   opts.DestinationBucket,
+		UploadID: id,
+		Key:      opts.DestinationKey,
+		Parts:    parts,
+		Metadata: attrs.Metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	return nil
+}
+
+// copyPartSize returns the size of the parts used to copy an object of the given size, which is at least
+// 'MinCopyPartSize' and large enough that the object is copied in no more than 'MaxUploadParts' parts.
+func copyPartSize(size int64) int64 {
+	return max(MinCopyPartSize, (size+MaxUploadParts-1)/MaxUploadParts)
+}
+
 func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectOptions) error {
 	var (
 		bucket = opts.Bucket
@@ -157,7 +431,12 @@ func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectO
 
 	// As defined by the 'Client' interface, if the given object does not exist, we create it
 	if objerr.IsNotFoundError(err) {
-		return c.PutObject(ctx, objcli.PutObjectOptions(opts))
+		return c.PutObject(ctx, objcli.PutObjectOptions{
+			Bucket:           opts.Bucket,
+			Key:              opts.Key,
+			Body:             opts.Body,
+			BandwidthLimiter: opts.BandwidthLimiter,
+		})
 	}
 
 	if err != nil {
@@ -192,9 +471,10 @@ func (c *Client) downloadAndAppend(
 	}
 
 	err = c.PutObject(ctx, objcli.PutObjectOptions{
-		Bucket: bucket,
-		Key:    attrs.Key,
-		Body:   bytes.NewReader(buffer.Bytes()),
+		Bucket:   bucket,
+		Key:      attrs.Key,
+		Body:     bytes.NewReader(buffer.Bytes()),
+		Metadata: attrs.Metadata,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to upload updated object: %w", err)
@@ -210,9 +490,11 @@ func (c *Client) createMPUThenCopyAndAppend(
 	attrs *objval.ObjectAttrs,
 	data io.ReadSeeker,
 ) error {
+	// Unlike 'CopyObject', a multipart copy doesn't preserve the metadata of the source object
 	id, err := c.CreateMultipartUpload(ctx, objcli.CreateMultipartUploadOptions{
-		Bucket: bucket,
-		Key:    attrs.Key,
+		Bucket:   bucket,
+		Key:      attrs.Key,
+		Metadata: attrs.Metadata,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create multipart upload: %w", err)
@@ -276,6 +558,7 @@ func (c *Client) copyAndAppend(
 		UploadID: id,
 		Key:      attrs.Key,
 		Parts:    []objval.Part{copied, appended},
+		Metadata: attrs.Metadata,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to complete multipart upload: %w", err)
@@ -285,20 +568,34 @@ func (c *Client) copyAndAppend(
 }
 
 func (c *Client) DeleteObjects(ctx context.Context, opts objcli.DeleteObjectsOptions) error {
+	objects := opts.AllObjects()
+
+	identifiers := make([]types.ObjectIdentifier, 0, len(objects))
+
+	for _, object := range objects {
+		identifier := types.ObjectIdentifier{Key: ptr.To(object.Key)}
+
+		if object.VersionID != "" {
+			identifier.VersionId = ptr.To(object.VersionID)
+		}
+
+		identifiers = append(identifiers, identifier)
+	}
+
 	pool := hofp.NewPool(hofp.Options{
 		Context: ctx,
-		Size:    system.NumWorkers(len(opts.Keys)),
+		Size:    system.NumWorkers(len(identifiers)),
 	})
 
 	del := func(ctx context.Context, start, end int) error {
-		return c.deleteObjects(ctx, opts.Bucket, opts.Keys[start:min(end, len(opts.Keys))]...)
+		return c.deleteObjectVersions(ctx, opts.Bucket, identifiers[start:min(end, len(identifiers))]...)
 	}
 
 	queue := func(start, end int) error {
 		return pool.Queue(func(ctx context.Context) error { return del(ctx, start, end) })
 	}
 
-	for start, end := 0, PageSize; start < len(opts.Keys); start, end = start+PageSize, end+PageSize {
+	for start, end := 0, PageSize; start < len(identifiers); start, end = start+PageSize, end+PageSize {
 		if queue(start, end) != nil {
 			break
 		}
@@ -310,11 +607,38 @@ func (c *Client) DeleteObjects(ctx context.Context, opts objcli.DeleteObjectsOpt
 // DeleteDirectory deletes all objects in a specific directory of a bucket. This does not delete old versions of objects
 // if any.
 func (c *Client) DeleteDirectory(ctx context.Context, opts objcli.DeleteDirectoryOptions) error {
-	if opts.Versions {
-		return c.deleteDirectoryVersions(ctx, opts.Bucket, opts.Prefix, c.deleteObjectVersions)
+	if opts.Include != nil && opts.Exclude != nil {
+		return objcli.ErrIncludeAndExcludeAreMutuallyExclusive
+	}
+
+	// Directory buckets don't support versioning, and therefore can't list object versions
+	if opts.Versions && !IsDirectoryBucket(opts.Bucket) {
+		return c.deleteDirectoryVersions(
+			ctx, opts.Bucket, opts.Prefix, opts.Include, opts.Exclude, c.deleteObjectVersions,
+		)
+	}
+
+	return c.deleteDirectory(ctx, opts.Bucket, opts.Prefix, opts.Include, opts.Exclude, c.deleteObjects)
+}
+
+// QueryObject runs the given expression against the object using S3 Select, the results are streamed as they're
+// received.
+func (c *Client) QueryObject(ctx context.Context, opts objcli.QueryObjectOptions) (io.ReadCloser, error) {
+	input := &s3.SelectObjectContentInput{
+		Bucket:              ptr.To(opts.Bucket),
+		Key:                 ptr.To(opts.Key),
+		Expression:          ptr.To(opts.Expression),
+		ExpressionType:      types.ExpressionTypeSql,
+		InputSerialization:  newInputSerialization(opts.Input),
+		OutputSerialization: newOutputSerialization(opts.Output),
+	}
+
+	resp, err := c.serviceAPI.SelectObjectContent(ctx, input)
+	if err != nil {
+		return nil, handleError(input.Bucket, input.Key, err)
 	}
 
-	return c.deleteDirectory(ctx, opts.Bucket, opts.Prefix, c.deleteObjects)
+	return newSelectReader(resp.GetStream()), nil
 }
 
 // Close is a no-op for AWS as this won't result in a memory leak.
@@ -328,13 +652,22 @@ func (c *Client) Close() error {
 func (c *Client) deleteDirectory(
 	ctx context.Context,
 	bucket, prefix string,
+	include, exclude []*regexp.Regexp,
 	fn func(ctx context.Context, bucket string, keys ...string) error,
 ) error {
+	prefix, filter := listPrefix(bucket, prefix)
+
 	callback := func(page *s3.ListObjectsV2Output) error {
 		keys := make([]string, 0, len(page.Contents))
 
 		for _, object := range page.Contents {
-			keys = append(keys, *object.Key)
+			if hasPrefix(*object.Key, filter) && !objcli.ShouldIgnore(*object.Key, include, exclude) {
+				keys = append(keys, *object.Key)
+			}
+		}
+
+		if len(keys) == 0 {
+			return nil
 		}
 
 		return fn(ctx, bucket, keys...)
@@ -359,23 +692,24 @@ func (c *Client) deleteDirectory(
 func (c *Client) deleteDirectoryVersions(
 	ctx context.Context,
 	bucket, prefix string,
+	include, exclude []*regexp.Regexp,
 	fn func(ctx context.Context, bucket string, objects ...types.ObjectIdentifier) error,
 ) error {
 	callback := func(page *s3.ListObjectVersionsOutput) error {
 		objects := make([]types.ObjectIdentifier, 0, len(page.Versions)+len(page.DeleteMarkers))
 
+		add := func(key, versionID *string) {
+			if !objcli.ShouldIgnore(ptr.From(key), include, exclude) {
+				objects = append(objects, types.ObjectIdentifier{Key: key, VersionId: versionID})
+			}
+		}
+
 		for _, object := range page.Versions {
-			objects = append(objects, types.ObjectIdentifier{
-				Key:       object.Key,
-				VersionId: object.VersionId,
-			})
+			add(object.Key, object.VersionId)
 		}
 
 		for _, object := range page.DeleteMarkers {
-			objects = append(objects, types.ObjectIdentifier{
-				Key:       object.Key,
-				VersionId: object.VersionId,
-			})
+			add(object.Key, object.VersionId)
 		}
 
 		return fn(ctx, bucket, objects...)
@@ -453,13 +787,15 @@ func (c *Client) IterateObjects(ctx context.Context, opts objcli.IterateObjectsO
 		return objcli.ErrIncludeAndExcludeAreMutuallyExclusive
 	}
 
+	prefix, filter := listPrefix(opts.Bucket, opts.Prefix)
+
 	callback := func(page *s3.ListObjectsV2Output) error {
-		return c.handlePage(page, opts.Include, opts.Exclude, opts.Func)
+		return c.handlePage(page, filter, opts.Include, opts.Exclude, opts.Func)
 	}
 
 	input := &s3.ListObjectsV2Input{
 		Bucket:    ptr.To(opts.Bucket),
-		Prefix:    ptr.To(opts.Prefix),
+		Prefix:    ptr.To(prefix),
 		Delimiter: ptr.To(opts.Delimiter),
 	}
 
@@ -472,9 +808,10 @@ func (c *Client) IterateObjects(ctx context.Context, opts objcli.IterateObjectsO
 }
 
 // handlePage iterates over common prefixes/objects in the given page executing the given function for each object which
-// has not been explicitly ignored by the user.
+// has the given prefix, and has not been explicitly ignored by the user.
 func (c *Client) handlePage(
 	page *s3.ListObjectsV2Output,
+	prefix string,
 	include, exclude []*regexp.Regexp,
 	fn objcli.IterateFunc,
 ) error {
@@ -489,7 +826,7 @@ func (c *Client) handlePage(
 	}
 
 	for _, attrs := range converted {
-		if objcli.ShouldIgnore(attrs.Key, include, exclude) {
+		if !hasPrefix(attrs.Key, prefix) || objcli.ShouldIgnore(attrs.Key, include, exclude) {
 			continue
 		}
 
@@ -508,7 +845,16 @@ func (c *Client) listObjects(
 	input *s3.ListObjectsV2Input,
 	fn func(page *s3.ListObjectsV2Output) error,
 ) error {
-	return listObjects[*s3.ListObjectsV2Output](ctx, s3.NewListObjectsV2Paginator(c.serviceAPI, input), fn)
+	if input.MaxKeys == nil && c.listPageSize > 0 {
+		input.MaxKeys = ptr.To(c.listPageSize)
+	}
+
+	return listObjects[*s3.ListObjectsV2Output](
+		ctx,
+		s3.NewListObjectsV2Paginator(c.serviceAPI, input),
+		c.listPrefetch,
+		fn,
+	)
 }
 
 // listObjectVersions uses the SDK paginator to run the given function on pages of object versions.
@@ -517,13 +863,41 @@ func (c *Client) listObjectVersions(
 	input *s3.ListObjectVersionsInput,
 	fn func(page *s3.ListObjectVersionsOutput) error,
 ) error {
-	return listObjects[*s3.ListObjectVersionsOutput](ctx, s3.NewListObjectVersionsPaginator(c.serviceAPI, input), fn)
+	if input.MaxKeys == nil && c.listPageSize > 0 {
+		input.MaxKeys = ptr.To(c.listPageSize)
+	}
+
+	return listObjects[*s3.ListObjectVersionsOutput](
+		ctx,
+		s3.NewListObjectVersionsPaginator(c.serviceAPI, input),
+		c.listPrefetch,
+		fn,
+	)
+}
+
+// ListDeletedObjects is unsupported for AWS, deleted objects may only be recovered from versioned buckets by copying
+// a non-current version.
+func (c *Client) ListDeletedObjects(
+	_ context.Context,
+	_ objcli.ListDeletedObjectsOptions,
+) ([]*objval.DeletedObject, error) {
+	return nil, objerr.ErrUnsupportedOperation
+}
+
+// UndeleteObject is unsupported for AWS, see 'ListDeletedObjects'.
+func (c *Client) UndeleteObject(_ context.Context, _ objcli.UndeleteObjectOptions) error {
+	return objerr.ErrUnsupportedOperation
 }
 
 func (c *Client) CreateMultipartUpload(ctx context.Context, opts objcli.CreateMultipartUploadOptions) (string, error) {
 	input := &s3.CreateMultipartUploadInput{
-		Bucket: ptr.To(opts.Bucket),
-		Key:    ptr.To(opts.Key),
+		Bucket:            ptr.To(opts.Bucket),
+		ChecksumAlgorithm: c.checksumAlgorithm,
+		Key:               ptr.To(opts.Key),
+	}
+
+	if len(opts.Metadata) != 0 {
+		input.Metadata = opts.Metadata
 	}
 
 	resp, err := c.serviceAPI.CreateMultipartUpload(ctx, input)
@@ -568,7 +942,17 @@ func (c *Client) listParts(ctx context.Context, paginator *s3.ListPartsPaginator
 		}
 
 		for _, part := range page.Parts {
-			parts = append(parts, objval.Part{ID: *part.ETag, Size: *part.Size})
+			parts = append(parts, objval.Part{
+				ID:   *part.ETag,
+				Size: *part.Size,
+				Checksum: ptr.From(getChecksum(
+					c.checksumAlgorithm,
+					part.ChecksumCRC32,
+					part.ChecksumCRC32C,
+					part.ChecksumSHA1,
+					part.ChecksumSHA256,
+				)),
+			})
 		}
 	}
 
@@ -590,12 +974,52 @@ func (c *Client) UploadPart(ctx context.Context, opts objcli.UploadPartOptions)
 		UploadId:      ptr.To(opts.UploadID),
 	}
 
-	output, err := c.serviceAPI.UploadPart(ctx, input)
+	var checksum string
+
+	if c.checksumAlgorithm != "" {
+		checksum, err = partChecksum(c.checksumAlgorithm, opts.Body)
+		if err != nil {
+			return objval.Part{}, err // Purposefully not wrapped
+		}
+
+		setChecksum(
+			c.checksumAlgorithm,
+			checksum,
+			&input.ChecksumCRC32,
+			&input.ChecksumCRC32C,
+			&input.ChecksumSHA1,
+			&input.ChecksumSHA256,
+		)
+	}
+
+	start, err := opts.Body.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return objval.Part{}, fmt.Errorf("failed to determine body offset: %w", err)
+	}
+
+	var output *s3.UploadPartOutput
+
+	upload := func() error {
+		// The body may have been partially consumed by a previous attempt
+		_, err := opts.Body.Seek(start, io.SeekStart)
+		if err != nil {
+			return fmt.Errorf("failed to seek to start of body: %w", err)
+		}
+
+		output, err = c.serviceAPI.UploadPart(ctx, input)
+		if err != nil {
+			return handleError(input.Bucket, input.Key, err)
+		}
+
+		return nil
+	}
+
+	err = objcli.WithCredentialsRefresh(ctx, c, upload)
 	if err != nil {
-		return objval.Part{}, handleError(input.Bucket, input.Key, err)
+		return objval.Part{}, err // Purposefully not wrapped
 	}
 
-	return objval.Part{ID: *output.ETag, Number: opts.Number, Size: size}, nil
+	return objval.Part{ID: *output.ETag, Number: opts.Number, Size: size, Checksum: checksum}, nil
 }
 
 func (c *Client) UploadPartCopy(ctx context.Context, opts objcli.UploadPartCopyOptions) (objval.Part, error) {
@@ -612,15 +1036,35 @@ func (c *Client) UploadPartCopy(ctx context.Context, opts objcli.UploadPartCopyO
 		UploadId:        ptr.To(opts.UploadID),
 	}
 
-	output, err := c.serviceAPI.UploadPartCopy(ctx, input)
+	var output *s3.UploadPartCopyOutput
+
+	copyPart := func() error {
+		var err error
+
+		output, err = c.serviceAPI.UploadPartCopy(ctx, input)
+		if err != nil {
+			return handleError(input.Bucket, input.Key, err)
+		}
+
+		return nil
+	}
+
+	err := objcli.WithCredentialsRefresh(ctx, c, copyPart)
 	if err != nil {
-		return objval.Part{}, handleError(input.Bucket, input.Key, err)
+		return objval.Part{}, err // Purposefully not wrapped
 	}
 
 	part := objval.Part{
 		ID:     *output.CopyPartResult.ETag,
 		Number: opts.Number,
 		Size:   opts.ByteRange.End - opts.ByteRange.Start + 1,
+		Checksum: ptr.From(getChecksum(
+			c.checksumAlgorithm,
+			output.CopyPartResult.ChecksumCRC32,
+			output.CopyPartResult.ChecksumCRC32C,
+			output.CopyPartResult.ChecksumSHA1,
+			output.CopyPartResult.ChecksumSHA256,
+		)),
 	}
 
 	return part, nil
@@ -631,6 +1075,15 @@ func (c *Client) CompleteMultipartUpload(ctx context.Context, opts objcli.Comple
 
 	for index, part := range opts.Parts {
 		converted[index] = types.CompletedPart{ETag: ptr.To(part.ID), PartNumber: ptr.To(int32(part.Number))}
+
+		setChecksum(
+			c.checksumAlgorithm,
+			part.Checksum,
+			&converted[index].ChecksumCRC32,
+			&converted[index].ChecksumCRC32C,
+			&converted[index].ChecksumSHA1,
+			&converted[index].ChecksumSHA256,
+		)
 	}
 
 	input := &s3.CompleteMultipartUploadInput{
@@ -640,9 +1093,57 @@ func (c *Client) CompleteMultipartUpload(ctx context.Context, opts objcli.Comple
 		MultipartUpload: &types.CompletedMultipartUpload{Parts: converted},
 	}
 
-	_, err := c.serviceAPI.CompleteMultipartUpload(ctx, input)
+	var output *s3.CompleteMultipartUploadOutput
 
-	return handleError(input.Bucket, input.Key, err)
+	complete := func() error {
+		var err error
+
+		output, err = c.serviceAPI.CompleteMultipartUpload(ctx, input)
+		if err != nil {
+			return handleError(input.Bucket, input.Key, err)
+		}
+
+		return nil
+	}
+
+	err := objcli.WithCredentialsRefresh(ctx, c, complete)
+	if err != nil {
+		return err // Purposefully not wrapped
+	}
+
+	return c.validateCompositeChecksum(opts, output)
+}
+
+// validateCompositeChecksum validates that the checksum calculated by S3 for an object created by completing a
+// multipart upload matches the checksum calculated from the parts.
+//
+// NOTE: Validation is skipped if a checksum algorithm isn't in use, or any of the parts are missing a checksum.
+func (c *Client) validateCompositeChecksum(
+	opts objcli.CompleteMultipartUploadOptions,
+	output *s3.CompleteMultipartUploadOutput,
+) error {
+	if c.checksumAlgorithm == "" {
+		return nil
+	}
+
+	actual := getChecksum(
+		c.checksumAlgorithm,
+		output.ChecksumCRC32,
+		output.ChecksumCRC32C,
+		output.ChecksumSHA1,
+		output.ChecksumSHA256,
+	)
+
+	expected, err := compositeChecksum(c.checksumAlgorithm, opts.Parts)
+	if err != nil {
+		return fmt.Errorf("failed to calculate composite checksum: %w", err)
+	}
+
+	if actual == nil || expected == "" || *actual == expected {
+		return nil
+	}
+
+	return &ChecksumMismatchError{Key: opts.Key, Expected: expected, Actual: *actual}
 }
 
 func (c *Client) AbortMultipartUpload(ctx context.Context, opts objcli.AbortMultipartUploadOptions) error {
@@ -660,14 +1161,162 @@ func (c *Client) AbortMultipartUpload(ctx context.Context, opts objcli.AbortMult
 	return nil
 }
 
+func (c *Client) ListMultipartUploads(
+	ctx context.Context,
+	opts objcli.ListMultipartUploadsOptions,
+) ([]objval.MultipartUpload, error) {
+	prefix, filter := listPrefix(opts.Bucket, opts.Prefix)
+
+	input := &s3.ListMultipartUploadsInput{
+		Bucket: ptr.To(opts.Bucket),
+		Prefix: ptr.To(prefix),
+	}
+
+	uploads := make([]objval.MultipartUpload, 0)
+
+	for {
+		output, err := c.serviceAPI.ListMultipartUploads(ctx, input)
+		if err != nil {
+			return nil, handleError(input.Bucket, nil, err)
+		}
+
+		for _, upload := range output.Uploads {
+			if !hasPrefix(ptr.From(upload.Key), filter) {
+				continue
+			}
+
+			uploads = append(uploads, objval.MultipartUpload{
+				Key:       ptr.From(upload.Key),
+				UploadID:  ptr.From(upload.UploadId),
+				Initiated: ptr.From(upload.Initiated),
+			})
+		}
+
+		if !ptr.From(output.IsTruncated) {
+			return uploads, nil
+		}
+
+		input.KeyMarker = output.NextKeyMarker
+		input.UploadIdMarker = output.NextUploadIdMarker
+	}
+}
+
+func (c *Client) CreateBucket(ctx context.Context, opts objcli.CreateBucketOptions) error {
+	input := &s3.CreateBucketInput{
+		Bucket: ptr.To(opts.Bucket),
+	}
+
+	switch {
+	case IsDirectoryBucket(opts.Bucket):
+		// Directory buckets are created in the zone encoded in their name, rather than in a region
+		input.CreateBucketConfiguration = &types.CreateBucketConfiguration{
+			Location: &types.LocationInfo{
+				Name: ptr.To(directoryBucketZone(opts.Bucket)),
+				Type: types.LocationTypeAvailabilityZone,
+			},
+			Bucket: &types.BucketInfo{
+				Type:           types.BucketTypeDirectory,
+				DataRedundancy: types.DataRedundancySingleAvailabilityZone,
+			},
+		}
+	case opts.Region != "" && opts.Region != DefaultRegion:
+		// Buckets in 'us-east-1' must be created without a location constraint
+		input.CreateBucketConfiguration = &types.CreateBucketConfiguration{
+			LocationConstraint: types.BucketLocationConstraint(opts.Region),
+		}
+	}
+
+	_, err := c.serviceAPI.CreateBucket(ctx, input)
+
+	return handleError(input.Bucket, nil, err)
+}
+
+func (c *Client) DeleteBucket(ctx context.Context, opts objcli.DeleteBucketOptions) error {
+	input := &s3.DeleteBucketInput{
+		Bucket: ptr.To(opts.Bucket),
+	}
+
+	_, err := c.serviceAPI.DeleteBucket(ctx, input)
+
+	return handleError(input.Bucket, nil, err)
+}
+
+func (c *Client) GetBucketVersioning(
+	ctx context.Context,
+	opts objcli.GetBucketVersioningOptions,
+) (objval.VersioningStatus, error) {
+	// Directory buckets don't support versioning
+	if IsDirectoryBucket(opts.Bucket) {
+		return objval.VersioningStatusDisabled, nil
+	}
+
+	input := &s3.GetBucketVersioningInput{
+		Bucket: ptr.To(opts.Bucket),
+	}
+
+	output, err := c.serviceAPI.GetBucketVersioning(ctx, input)
+	if err != nil {
+		return "", handleError(input.Bucket, nil, err)
+	}
+
+	switch output.Status {
+	case types.BucketVersioningStatusEnabled:
+		return objval.VersioningStatusEnabled, nil
+	case types.BucketVersioningStatusSuspended:
+		return objval.VersioningStatusSuspended, nil
+	}
+
+	return objval.VersioningStatusDisabled, nil
+}
+
+func (c *Client) GetBucketRegion(ctx context.Context, opts objcli.GetBucketRegionOptions) (string, error) {
+	input := &s3.GetBucketLocationInput{
+		Bucket: ptr.To(opts.Bucket),
+	}
+
+	output, err := c.serviceAPI.GetBucketLocation(ctx, input)
+	if err != nil {
+		return "", handleError(input.Bucket, nil, err)
+	}
+
+	// AWS returns legacy values for some regions, see
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketLocation.html.
+	switch output.LocationConstraint {
+	case "":
+		return DefaultRegion, nil
+	case types.BucketLocationConstraintEu:
+		return "eu-west-1", nil
+	}
+
+	return string(output.LocationConstraint), nil
+}
+
 // paginator wraps the AWS paginator API in an interface.
 type paginator[T any] interface {
 	HasMorePages() bool
 	NextPage(context.Context, ...func(*s3.Options)) (T, error)
 }
 
-// listObjects runs the given function for each page in the paginator.
-func listObjects[O any](ctx context.Context, pgn paginator[O], fn func(O) error) error {
+// listObjects runs the given function for each page in the paginator, where 'prefetch' is positive, up to that many
+// pages are fetched in the background whilst the function processes the current page.
+// SetObjectLock is unsupported for AWS, S3 Object Lock isn't currently exposed by the client.
+func (c *Client) SetObjectLock(_ context.Context, _ objcli.SetObjectLockOptions) error {
+	return objerr.ErrUnsupportedOperation
+}
+
+// GetBucketLockingStatus is unsupported for AWS, S3 Object Lock isn't currently exposed by the client.
+func (c *Client) GetBucketLockingStatus(
+	_ context.Context,
+	_ objcli.GetBucketLockingStatusOptions,
+) (objval.BucketLockingStatus, error) {
+	return objval.BucketLockingStatus{}, objerr.ErrUnsupportedOperation
+}
+
+func listObjects[O any](ctx context.Context, pgn paginator[O], prefetch int, fn func(O) error) error {
+	if prefetch > 0 {
+		return listObjectsPrefetch(ctx, pgn, prefetch, fn)
+	}
+
 	for pgn.HasMorePages() {
 		page, err := pgn.NextPage(ctx)
 		if err != nil {
@@ -682,3 +1331,58 @@ func listObjects[O any](ctx context.Context, pgn paginator[O], fn func(O) error)
 
 	return nil
 }
+
+// listObjectsPrefetch runs the given function for each page in the paginator, whilst fetching the following pages in a
+// separate goroutine.
+func listObjectsPrefetch[O any](ctx context.Context, pgn paginator[O], prefetch int, fn func(O) error) error {
+	type result struct {
+		page O
+		err  error
+	}
+
+	var (
+		wg sync.WaitGroup
+		// The page being fetched is also "ahead", so only buffer the remaining pages
+		pages = make(chan result, prefetch-1)
+	)
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	// Ensure the goroutine has stopped using the paginator before returning, the cancellation must happen first
+	defer wg.Wait()
+	defer cancel()
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer close(pages)
+
+		for pgn.HasMorePages() {
+			page, err := pgn.NextPage(ctx)
+
+			select {
+			case pages <- result{page: page, err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for result := range pages {
+		if result.err != nil {
+			return fmt.Errorf("failed to get next page: %w", result.err)
+		}
+
+		err := fn(result.page)
+		if err != nil {
+			return fmt.Errorf("failed to process page: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package objaws
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -10,6 +11,8 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -20,8 +23,10 @@ import (
 	testutil "github.com/couchbase/tools-common/testing/util"
 	"github.com/couchbase/tools-common/types/v2/ptr"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -33,13 +38,28 @@ func TestNewClient(t *testing.T) {
 		logger = slog.Default()
 	)
 
-	require.Equal(t, &Client{serviceAPI: api, logger: logger}, NewClient(ClientOptions{ServiceAPI: api}))
+	require.Equal(
+		t,
+		&Client{serviceAPI: api, capabilities: Capabilities, logger: logger, listPrefetch: DefaultListPrefetch},
+		NewClient(ClientOptions{ServiceAPI: api}),
+	)
 }
 
 func TestClientProvider(t *testing.T) {
 	require.Equal(t, objval.ProviderAWS, (&Client{}).Provider())
 }
 
+func TestClientCapabilities(t *testing.T) {
+	client := NewClient(ClientOptions{})
+	require.Equal(t, Capabilities, client.Capabilities())
+	require.Equal(t, MaxUploadParts, client.Capabilities().MaxParts)
+
+	expected := objval.Capabilities{MaxParts: 1000, MinPartSize: MinUploadSize}
+
+	client = NewClient(ClientOptions{Capabilities: &expected})
+	require.Equal(t, expected, client.Capabilities())
+}
+
 func TestClientGetObject(t *testing.T) {
 	api := &mockServiceAPI{}
 
@@ -85,6 +105,40 @@ func TestClientGetObject(t *testing.T) {
 	api.AssertNumberOfCalls(t, "GetObject", 1)
 }
 
+func TestClientGetObjectDecompress(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+
+	_, err := writer.Write([]byte("value"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	output := &s3.GetObjectOutput{
+		Body:            io.NopCloser(&buffer),
+		ContentEncoding: ptr.To("gzip"),
+		ContentLength:   ptr.To(int64(buffer.Len())),
+	}
+
+	api.On("GetObject", matchers.Context, mock.Anything).Return(output, nil)
+
+	client := &Client{serviceAPI: api}
+
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:     "bucket",
+		Key:        "key",
+		Decompress: true,
+	})
+	require.NoError(t, err)
+	require.Nil(t, object.Size)
+	require.Equal(t, []byte("value"), testutil.ReadAll(t, object.Body))
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "GetObject", 1)
+}
+
 func TestClientGetObjectWithByteRange(t *testing.T) {
 	api := &mockServiceAPI{}
 
@@ -113,6 +167,32 @@ func TestClientGetObjectWithByteRange(t *testing.T) {
 	api.AssertNumberOfCalls(t, "GetObject", 1)
 }
 
+func TestClientGetObjectConditional(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	fn := func(input *s3.GetObjectInput) bool {
+		return input.IfModifiedSince != nil && input.IfModifiedSince.Equal(since) &&
+			input.IfNoneMatch != nil && *input.IfNoneMatch == `"etag"`
+	}
+
+	api.On("GetObject", matchers.Context, mock.MatchedBy(fn)).Return(nil, &smithy.GenericAPIError{Code: "NotModified"})
+
+	client := &Client{serviceAPI: api}
+
+	_, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:          "bucket",
+		Key:             "key",
+		IfModifiedSince: since,
+		IfNoneMatch:     `"etag"`,
+	})
+	require.ErrorIs(t, err, objerr.ErrNotModified)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "GetObject", 1)
+}
+
 func TestClientGetObjectWithInvalidByteRange(t *testing.T) {
 	client := &Client{}
 
@@ -196,145 +276,134 @@ func TestClientPutObject(t *testing.T) {
 	api.AssertNumberOfCalls(t, "PutObject", 1)
 }
 
-func TestClientAppendToObjectNotFound(t *testing.T) {
+func TestClientPutObjectWithTags(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn1 := func(input *s3.HeadObjectInput) bool {
-		var (
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			key    = input.Key != nil && *input.Key == "key"
-		)
-
-		return bucket && key
+	fn := func(input *s3.PutObjectInput) bool {
+		return input.Tagging != nil && *input.Tagging == "a=b&c=d+e"
 	}
 
-	api.On("HeadObject", matchers.Context, mock.MatchedBy(fn1)).
-		Return(nil, &types.NoSuchKey{})
+	api.On("PutObject", matchers.Context, mock.MatchedBy(fn)).Return(&s3.PutObjectOutput{}, nil)
 
-	fn2 := func(input *s3.PutObjectInput) bool {
-		var (
-			body   = input.Body != nil && bytes.Equal(testutil.ReadAll(t, input.Body), []byte("appended"))
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			key    = input.Key != nil && *input.Key == "key"
-		)
+	client := &Client{serviceAPI: api}
 
-		return body && bucket && key
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   strings.NewReader("value"),
+		Tags:   map[string]string{"a": "b", "c": "d e"},
+	})
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "PutObject", 1)
+}
+
+func TestClientGetObjectAttrsWithMetadata(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	output := &s3.HeadObjectOutput{
+		ETag:          ptr.To("etag"),
+		ContentLength: ptr.To[int64](5),
+		Metadata:      map[string]string{"Format_version": "2"},
 	}
 
-	api.On("PutObject", matchers.Context, mock.MatchedBy(fn2)).Return(&s3.PutObjectOutput{}, nil)
+	api.On("HeadObject", matchers.Context, mock.Anything).Return(output, nil)
 
 	client := &Client{serviceAPI: api}
 
-	err := client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
+	attrs, err := client.GetObjectAttrs(context.Background(), objcli.GetObjectAttrsOptions{
 		Bucket: "bucket",
 		Key:    "key",
-		Body:   strings.NewReader("appended"),
 	})
 	require.NoError(t, err)
+	require.Equal(t, map[string]string{"format_version": "2"}, attrs.Metadata)
 
 	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "HeadObject", 1)
-	api.AssertNumberOfCalls(t, "PutObject", 1)
 }
 
-func TestClientCopyObject(t *testing.T) {
+func TestClientPutObjectWithMetadata(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn1 := func(input *s3.CopyObjectInput) bool {
-		var (
-			bucket = ptr.From(input.Bucket) == "dstBucket"
-			key    = ptr.From(input.Key) == "dstKey"
-			source = ptr.From(input.CopySource) == url.PathEscape("srcBucket/srcKey")
-		)
-
-		return bucket && key && source
+	fn := func(input *s3.PutObjectInput) bool {
+		return reflect.DeepEqual(input.Metadata, map[string]string{"format_version": "2"})
 	}
 
-	api.On("CopyObject", matchers.Context, mock.MatchedBy(fn1)).Return(&s3.CopyObjectOutput{}, nil)
+	api.On("PutObject", matchers.Context, mock.MatchedBy(fn)).Return(&s3.PutObjectOutput{}, nil)
 
 	client := &Client{serviceAPI: api}
 
-	err := client.CopyObject(context.Background(), objcli.CopyObjectOptions{
-		DestinationBucket: "dstBucket",
-		DestinationKey:    "dstKey",
-		SourceBucket:      "srcBucket",
-		SourceKey:         "srcKey",
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket:   "bucket",
+		Key:      "key",
+		Body:     strings.NewReader("value"),
+		Metadata: map[string]string{"format_version": "2"},
 	})
 	require.NoError(t, err)
 
 	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "CopyObject", 1)
+	api.AssertNumberOfCalls(t, "PutObject", 1)
 }
 
-func TestClientAppendToObjectDownloadAndAdd(t *testing.T) {
+func TestClientCreateMultipartUploadWithMetadata(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn1 := func(input *s3.HeadObjectInput) bool {
-		var (
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			key    = input.Key != nil && *input.Key == "key"
-		)
-
-		return bucket && key
+	fn := func(input *s3.CreateMultipartUploadInput) bool {
+		return reflect.DeepEqual(input.Metadata, map[string]string{"format_version": "2"})
 	}
 
-	output1 := &s3.HeadObjectOutput{
-		ETag:          ptr.To("etag"),
-		ContentLength: ptr.To(int64(len("value"))),
-		LastModified:  ptr.To((time.Time{}).Add(24 * time.Hour)),
-	}
+	api.On("CreateMultipartUpload", matchers.Context, mock.MatchedBy(fn)).
+		Return(&s3.CreateMultipartUploadOutput{UploadId: ptr.To("id")}, nil)
 
-	api.On("HeadObject", matchers.Context, mock.MatchedBy(fn1)).Return(output1, nil)
+	client := &Client{serviceAPI: api}
 
-	fn2 := func(input *s3.GetObjectInput) bool {
-		var (
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			key    = input.Key != nil && *input.Key == "key"
-		)
+	id, err := client.CreateMultipartUpload(context.Background(), objcli.CreateMultipartUploadOptions{
+		Bucket:   "bucket",
+		Key:      "key",
+		Metadata: map[string]string{"format_version": "2"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "id", id)
 
-		return bucket && key
-	}
+	api.AssertExpectations(t)
+}
 
-	output2 := &s3.GetObjectOutput{
-		Body:          io.NopCloser(strings.NewReader("value")),
-		ETag:          ptr.To("etag"),
-		ContentLength: ptr.To(int64(len("value"))),
-		LastModified:  ptr.To((time.Time{}).Add(24 * time.Hour)),
-	}
+func TestClientPutObjectCompress(t *testing.T) {
+	api := &mockServiceAPI{}
 
-	api.On("GetObject", matchers.Context, mock.MatchedBy(fn2)).Return(output2, nil)
+	fn := func(input *s3.PutObjectInput) bool {
+		if input.ContentEncoding == nil || *input.ContentEncoding != "gzip" {
+			return false
+		}
 
-	fn3 := func(input *s3.PutObjectInput) bool {
-		var (
-			body   = input.Body != nil && bytes.Equal(testutil.ReadAll(t, input.Body), []byte("valueappended"))
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			key    = input.Key != nil && *input.Key == "key"
-		)
+		reader, err := gzip.NewReader(input.Body)
+		if err != nil {
+			return false
+		}
 
-		return body && bucket && key
+		return bytes.Equal(testutil.ReadAll(t, reader), []byte("value"))
 	}
 
-	api.On("PutObject", matchers.Context, mock.MatchedBy(fn3)).Return(&s3.PutObjectOutput{}, nil)
+	api.On("PutObject", matchers.Context, mock.MatchedBy(fn)).Return(&s3.PutObjectOutput{}, nil)
 
 	client := &Client{serviceAPI: api}
 
-	err := client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
-		Bucket: "bucket",
-		Key:    "key",
-		Body:   strings.NewReader("appended"),
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket:   "bucket",
+		Key:      "key",
+		Body:     strings.NewReader("value"),
+		Compress: objcli.CompressionGZIP,
 	})
 	require.NoError(t, err)
 
 	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "HeadObject", 1)
-	api.AssertNumberOfCalls(t, "GetObject", 1)
 	api.AssertNumberOfCalls(t, "PutObject", 1)
 }
 
-func TestClientAppendToObjectCreateMPUThenCopyAndAppend(t *testing.T) {
+func TestClientGetObjectTags(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn1 := func(input *s3.HeadObjectInput) bool {
+	fn := func(input *s3.GetObjectTaggingInput) bool {
 		var (
 			bucket = input.Bucket != nil && *input.Bucket == "bucket"
 			key    = input.Key != nil && *input.Key == "key"
@@ -343,81 +412,102 @@ func TestClientAppendToObjectCreateMPUThenCopyAndAppend(t *testing.T) {
 		return bucket && key
 	}
 
-	output1 := &s3.HeadObjectOutput{
-		ETag:          ptr.To("etag"),
-		ContentLength: ptr.To[int64](MinUploadSize),
-		LastModified:  ptr.To((time.Time{}).Add(24 * time.Hour)),
+	output := &s3.GetObjectTaggingOutput{
+		TagSet: []types.Tag{{Key: ptr.To("a"), Value: ptr.To("b")}, {Key: ptr.To("c"), Value: ptr.To("d")}},
 	}
 
-	api.On("HeadObject", matchers.Context, mock.MatchedBy(fn1)).Return(output1, nil)
+	api.On("GetObjectTagging", matchers.Context, mock.MatchedBy(fn)).Return(output, nil)
 
-	fn2 := func(input *s3.CreateMultipartUploadInput) bool {
+	client := &Client{serviceAPI: api}
+
+	tags, err := client.GetObjectTags(context.Background(), objcli.GetObjectTagsOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "b", "c": "d"}, tags)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "GetObjectTagging", 1)
+}
+
+func TestClientPutObjectTags(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn := func(input *s3.PutObjectTaggingInput) bool {
 		var (
 			bucket = input.Bucket != nil && *input.Bucket == "bucket"
 			key    = input.Key != nil && *input.Key == "key"
+			tags   = input.Tagging != nil && reflect.DeepEqual(
+				input.Tagging.TagSet,
+				[]types.Tag{{Key: ptr.To("a"), Value: ptr.To("b")}, {Key: ptr.To("c"), Value: ptr.To("d")}},
+			)
 		)
 
-		return bucket && key
+		return bucket && key && tags
 	}
 
-	output2 := &s3.CreateMultipartUploadOutput{
-		UploadId: ptr.To("id"),
-	}
+	api.On("PutObjectTagging", matchers.Context, mock.MatchedBy(fn)).Return(&s3.PutObjectTaggingOutput{}, nil)
 
-	api.On("CreateMultipartUpload", matchers.Context, mock.MatchedBy(fn2)).Return(output2, nil)
+	client := &Client{serviceAPI: api}
 
-	fn3 := func(input *s3.UploadPartCopyInput) bool {
+	err := client.PutObjectTags(context.Background(), objcli.PutObjectTagsOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Tags:   map[string]string{"c": "d", "a": "b"},
+	})
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "PutObjectTagging", 1)
+}
+
+func TestClientDeleteObjectTags(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn := func(input *s3.DeleteObjectTaggingInput) bool {
 		var (
 			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			src    = input.CopySource != nil && *input.CopySource == "bucket/key"
-			rnge   = input.CopySourceRange != nil && *input.CopySourceRange == fmt.Sprintf("bytes=0-%d", MinUploadSize-1)
 			key    = input.Key != nil && *input.Key == "key"
-			number = input.PartNumber != nil && *input.PartNumber == 1
-			id     = input.UploadId != nil && *input.UploadId == "id"
 		)
 
-		return bucket && src && rnge && key && number && id
+		return bucket && key
 	}
 
-	output3 := &s3.UploadPartCopyOutput{
-		CopyPartResult: &types.CopyPartResult{ETag: ptr.To("etag1")},
-	}
+	api.On("DeleteObjectTagging", matchers.Context, mock.MatchedBy(fn)).Return(&s3.DeleteObjectTaggingOutput{}, nil)
 
-	api.On("UploadPartCopy", matchers.Context, mock.MatchedBy(fn3)).Return(output3, nil)
+	client := &Client{serviceAPI: api}
 
-	fn4 := func(input *s3.UploadPartInput) bool {
+	err := client.DeleteObjectTags(context.Background(), objcli.DeleteObjectTagsOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "DeleteObjectTagging", 1)
+}
+
+func TestClientAppendToObjectNotFound(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn1 := func(input *s3.HeadObjectInput) bool {
 		var (
-			body   = input.Body != nil && bytes.Equal(testutil.ReadAll(t, input.Body), []byte("appended"))
 			bucket = input.Bucket != nil && *input.Bucket == "bucket"
 			key    = input.Key != nil && *input.Key == "key"
-			number = input.PartNumber != nil && *input.PartNumber == 2
-			id     = input.UploadId != nil && *input.UploadId == "id"
 		)
 
-		return body && bucket && key && number && id
-	}
-
-	output4 := &s3.UploadPartOutput{
-		ETag: ptr.To("etag2"),
+		return bucket && key
 	}
 
-	api.On("UploadPart", matchers.Context, mock.MatchedBy(fn4)).Return(output4, nil)
+	api.On("HeadObject", matchers.Context, mock.MatchedBy(fn1)).
+		Return(nil, &types.NoSuchKey{})
 
-	fn5 := func(input *s3.CompleteMultipartUploadInput) bool {
+	fn2 := func(input *s3.PutObjectInput) bool {
 		var (
+			body   = input.Body != nil && bytes.Equal(testutil.ReadAll(t, input.Body), []byte("appended"))
 			bucket = input.Bucket != nil && *input.Bucket == "bucket"
 			key    = input.Key != nil && *input.Key == "key"
-			id     = input.UploadId != nil && *input.UploadId == "id"
-			parts  = reflect.DeepEqual(input.MultipartUpload.Parts, []types.CompletedPart{
-				{ETag: ptr.To("etag1"), PartNumber: ptr.To[int32](1)},
-				{ETag: ptr.To("etag2"), PartNumber: ptr.To[int32](2)},
-			})
 		)
 
-		return bucket && key && id && parts
+		return body && bucket && key
 	}
 
-	api.On("CompleteMultipartUpload", matchers.Context, mock.MatchedBy(fn5)).Return(nil, nil)
+	api.On("PutObject", matchers.Context, mock.MatchedBy(fn2)).Return(&s3.PutObjectOutput{}, nil)
 
 	client := &Client{serviceAPI: api}
 
@@ -430,255 +520,655 @@ func TestClientAppendToObjectCreateMPUThenCopyAndAppend(t *testing.T) {
 
 	api.AssertExpectations(t)
 	api.AssertNumberOfCalls(t, "HeadObject", 1)
-	api.AssertNumberOfCalls(t, "CreateMultipartUpload", 1)
-	api.AssertNumberOfCalls(t, "UploadPartCopy", 1)
-	api.AssertNumberOfCalls(t, "UploadPart", 1)
-	api.AssertNumberOfCalls(t, "CompleteMultipartUpload", 1)
+	api.AssertNumberOfCalls(t, "PutObject", 1)
 }
 
-func TestClientAppendToObjectCreateMPUThenCopyAndAppendAbortOnFailure(t *testing.T) {
+func TestClientCopyObject(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn1 := func(input *s3.HeadObjectInput) bool {
-		var (
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			key    = input.Key != nil && *input.Key == "key"
+	fn0 := func(input *s3.HeadObjectInput) bool {
+		return ptr.From(input.Bucket) == "srcBucket" && ptr.From(input.Key) == "srcKey"
+	}
+
+	api.On("HeadObject", matchers.Context, mock.MatchedBy(fn0)).
+		Return(&s3.HeadObjectOutput{ContentLength: ptr.To[int64](MaxCopySize)}, nil)
+
+	fn1 := func(input *s3.CopyObjectInput) bool {
+		var (
+			bucket = ptr.From(input.Bucket) == "dstBucket"
+			key    = ptr.From(input.Key) == "dstKey"
+			source = ptr.From(input.CopySource) == url.PathEscape("srcBucket/srcKey")
 		)
 
-		return bucket && key
+		return bucket && key && source
 	}
 
+	api.On("CopyObject", matchers.Context, mock.MatchedBy(fn1)).Return(&s3.CopyObjectOutput{}, nil)
+
+	client := &Client{serviceAPI: api}
+
+	err := client.CopyObject(context.Background(), objcli.CopyObjectOptions{
+		DestinationBucket: "dstBucket",
+		DestinationKey:    "dstKey",
+		SourceBucket:      "srcBucket",
+		SourceKey:         "srcKey",
+	})
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "HeadObject", 1)
+	api.AssertNumberOfCalls(t, "CopyObject", 1)
+}
+
+func TestClientCopyObjectMultipart(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	var (
+		size     = int64(MaxCopySize + 1)
+		partSize = int64(MinCopyPartSize)
+		numParts = (size + partSize - 1) / partSize
+	)
+
 	output1 := &s3.HeadObjectOutput{
-		ETag:          ptr.To("etag"),
-		ContentLength: ptr.To[int64](MinUploadSize),
-		LastModified:  ptr.To((time.Time{}).Add(24 * time.Hour)),
+		ContentLength: ptr.To(size),
+		Metadata:      map[string]string{"key": "value"},
 	}
 
-	api.On("HeadObject", matchers.Context, mock.MatchedBy(fn1)).Return(output1, nil)
+	api.On("HeadObject", matchers.Context, mock.Anything).Return(output1, nil)
 
 	fn2 := func(input *s3.CreateMultipartUploadInput) bool {
 		var (
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			key    = input.Key != nil && *input.Key == "key"
+			bucket   = ptr.From(input.Bucket) == "dstBucket"
+			key      = ptr.From(input.Key) == "dstKey"
+			metadata = reflect.DeepEqual(input.Metadata, map[string]string{"key": "value"})
 		)
 
-		return bucket && key
+		return bucket && key && metadata
 	}
 
-	output2 := &s3.CreateMultipartUploadOutput{
-		UploadId: ptr.To("id"),
-	}
+	api.On("CreateMultipartUpload", matchers.Context, mock.MatchedBy(fn2)).
+		Return(&s3.CreateMultipartUploadOutput{UploadId: ptr.To("id")}, nil)
 
-	api.On("CreateMultipartUpload", matchers.Context, mock.MatchedBy(fn2)).Return(output2, nil)
+	var (
+		lock   sync.Mutex
+		ranges = make(map[int32]string)
+	)
 
 	fn3 := func(input *s3.UploadPartCopyInput) bool {
 		var (
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			src    = input.CopySource != nil && *input.CopySource == "bucket/key"
-			rnge   = input.CopySourceRange != nil && *input.CopySourceRange == fmt.Sprintf("bytes=0-%d", MinUploadSize-1)
-			key    = input.Key != nil && *input.Key == "key"
-			number = input.PartNumber != nil && *input.PartNumber == 1
-			id     = input.UploadId != nil && *input.UploadId == "id"
+			bucket = ptr.From(input.Bucket) == "dstBucket"
+			src    = ptr.From(input.CopySource) == "srcBucket/srcKey"
+			key    = ptr.From(input.Key) == "dstKey"
+			id     = ptr.From(input.UploadId) == "id"
 		)
 
-		return bucket && src && rnge && key && number && id
+		lock.Lock()
+		defer lock.Unlock()
+
+		ranges[ptr.From(input.PartNumber)] = ptr.From(input.CopySourceRange)
+
+		return bucket && src && key && id
 	}
 
-	api.On("UploadPartCopy", matchers.Context, mock.MatchedBy(fn3)).Return(nil, assert.AnError)
+	api.On("UploadPartCopy", matchers.Context, mock.MatchedBy(fn3)).Return(
+		func(_ context.Context, input *s3.UploadPartCopyInput, _ ...func(*s3.Options)) *s3.UploadPartCopyOutput {
+			etag := fmt.Sprintf("etag%d", ptr.From(input.PartNumber))
+			return &s3.UploadPartCopyOutput{CopyPartResult: &types.CopyPartResult{ETag: ptr.To(etag)}}
+		},
+		nil,
+	)
 
-	fn4 := func(input *s3.AbortMultipartUploadInput) bool {
-		var (
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			key    = input.Key != nil && *input.Key == "key"
-			id     = input.UploadId != nil && *input.UploadId == "id"
-		)
+	fn4 := func(input *s3.CompleteMultipartUploadInput) bool {
+		if ptr.From(input.UploadId) != "id" || len(input.MultipartUpload.Parts) != int(numParts) {
+			return false
+		}
 
-		return bucket && key && id
+		for index, part := range input.MultipartUpload.Parts {
+			if ptr.From(part.PartNumber) != int32(index+1) || ptr.From(part.ETag) != fmt.Sprintf("etag%d", index+1) {
+				return false
+			}
+		}
+
+		return true
 	}
 
-	api.On("AbortMultipartUpload", matchers.Context, mock.MatchedBy(fn4)).Return(nil, nil)
+	api.On("CompleteMultipartUpload", matchers.Context, mock.MatchedBy(fn4)).
+		Return(&s3.CompleteMultipartUploadOutput{}, nil)
 
 	client := &Client{serviceAPI: api}
 
-	err := client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
-		Bucket: "bucket",
-		Key:    "key",
-		Body:   strings.NewReader("appended"),
+	err := client.CopyObject(context.Background(), objcli.CopyObjectOptions{
+		DestinationBucket: "dstBucket",
+		DestinationKey:    "dstKey",
+		SourceBucket:      "srcBucket",
+		SourceKey:         "srcKey",
 	})
-	require.ErrorIs(t, err, assert.AnError)
+	require.NoError(t, err)
 
 	api.AssertExpectations(t)
 	api.AssertNumberOfCalls(t, "HeadObject", 1)
 	api.AssertNumberOfCalls(t, "CreateMultipartUpload", 1)
-	api.AssertNumberOfCalls(t, "UploadPartCopy", 1)
-	api.AssertNumberOfCalls(t, "AbortMultipartUpload", 1)
+	api.AssertNumberOfCalls(t, "UploadPartCopy", int(numParts))
+	api.AssertNumberOfCalls(t, "CompleteMultipartUpload", 1)
+	api.AssertNotCalled(t, "CopyObject", mock.Anything, mock.Anything)
+
+	require.Len(t, ranges, int(numParts))
+	require.Equal(t, fmt.Sprintf("bytes=0-%d", partSize-1), ranges[1])
+	require.Equal(t, fmt.Sprintf("bytes=%d-%d", (numParts-1)*partSize, size-1), ranges[int32(numParts)])
 }
 
-func TestClientDeleteObjectsNoKeys(t *testing.T) {
+func TestClientCopyObjectMultipartAbortOnFailure(t *testing.T) {
 	api := &mockServiceAPI{}
-	client := &Client{serviceAPI: api}
 
-	require.Equal(t, nil, client.deleteObjects(context.Background(), "bucket"))
+	api.On("HeadObject", matchers.Context, mock.Anything).
+		Return(&s3.HeadObjectOutput{ContentLength: ptr.To[int64](MaxCopySize + 1)}, nil)
 
-	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "DeleteObjects", 0)
-}
+	api.On("CreateMultipartUpload", matchers.Context, mock.Anything).
+		Return(&s3.CreateMultipartUploadOutput{UploadId: ptr.To("id")}, nil)
 
-func TestClientDeleteObjectsSinglePage(t *testing.T) {
-	api := &mockServiceAPI{}
+	api.On("UploadPartCopy", matchers.Context, mock.Anything).Return(nil, assert.AnError)
 
-	fn := func(input *s3.DeleteObjectsInput) bool {
+	fn := func(input *s3.AbortMultipartUploadInput) bool {
 		var (
-			bucket  = input.Bucket != nil && *input.Bucket == "bucket"
-			quiet   = input.Delete != nil && input.Delete.Quiet != nil && *input.Delete.Quiet
-			objects = input.Delete != nil && reflect.DeepEqual(input.Delete.Objects, []types.ObjectIdentifier{
-				{Key: ptr.To("key1")},
-				{Key: ptr.To("key2")},
-				{Key: ptr.To("key3")},
-			})
+			bucket = ptr.From(input.Bucket) == "dstBucket"
+			key    = ptr.From(input.Key) == "dstKey"
+			id     = ptr.From(input.UploadId) == "id"
 		)
 
-		return bucket && quiet && objects
+		return bucket && key && id
 	}
 
-	api.On("DeleteObjects", matchers.Context, mock.MatchedBy(fn)).
-		Return(&s3.DeleteObjectsOutput{}, nil)
+	api.On("AbortMultipartUpload", matchers.Context, mock.MatchedBy(fn)).Return(nil, nil)
 
 	client := &Client{serviceAPI: api}
 
-	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
-		Bucket: "bucket",
-		Keys:   []string{"key1", "key2", "key3"},
+	err := client.CopyObject(context.Background(), objcli.CopyObjectOptions{
+		DestinationBucket: "dstBucket",
+		DestinationKey:    "dstKey",
+		SourceBucket:      "srcBucket",
+		SourceKey:         "srcKey",
 	})
-	require.NoError(t, err)
+	require.ErrorIs(t, err, assert.AnError)
 
 	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "DeleteObjects", 1)
+	api.AssertNotCalled(t, "CompleteMultipartUpload", mock.Anything, mock.Anything)
+	api.AssertNumberOfCalls(t, "AbortMultipartUpload", 1)
 }
 
-func TestClientDeleteObjectsMultiplePages(t *testing.T) {
+func TestCopyPartSize(t *testing.T) {
+	require.Equal(t, int64(MinCopyPartSize), copyPartSize(MaxCopySize+1))
+	require.Equal(t, int64(MinCopyPartSize), copyPartSize(MinCopyPartSize*MaxUploadParts))
+	require.Equal(t, int64(MinCopyPartSize+1), copyPartSize(MinCopyPartSize*MaxUploadParts+1))
+}
+
+func TestClientAppendToObjectDownloadAndAdd(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn1 := func(input *s3.DeleteObjectsInput) bool {
+	fn1 := func(input *s3.HeadObjectInput) bool {
 		var (
-			bucket  = input.Bucket != nil && *input.Bucket == "bucket"
-			quiet   = input.Delete != nil && input.Delete.Quiet != nil && *input.Delete.Quiet
-			objects = input.Delete != nil && len(input.Delete.Objects) == PageSize
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
 		)
 
-		return bucket && quiet && objects
+		return bucket && key
 	}
 
-	api.On("DeleteObjects", matchers.Context, mock.MatchedBy(fn1)).
-		Return(&s3.DeleteObjectsOutput{}, nil)
+	output1 := &s3.HeadObjectOutput{
+		ETag:          ptr.To("etag"),
+		ContentLength: ptr.To(int64(len("value"))),
+		LastModified:  ptr.To((time.Time{}).Add(24 * time.Hour)),
+		Metadata:      map[string]string{"name": "value"},
+	}
 
-	fn2 := func(input *s3.DeleteObjectsInput) bool {
+	api.On("HeadObject", matchers.Context, mock.MatchedBy(fn1)).Return(output1, nil)
+
+	fn2 := func(input *s3.GetObjectInput) bool {
 		var (
-			bucket  = input.Bucket != nil && *input.Bucket == "bucket"
-			quiet   = input.Delete != nil && input.Delete.Quiet != nil && *input.Delete.Quiet
-			objects = input.Delete != nil && len(input.Delete.Objects) == 42
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
 		)
 
-		return bucket && quiet && objects
+		return bucket && key
 	}
 
-	api.On("DeleteObjects", matchers.Context, mock.MatchedBy(fn2)).
-		Return(&s3.DeleteObjectsOutput{}, nil)
-
-	client := &Client{serviceAPI: api}
-
-	keys := make([]string, 0, PageSize+42)
-
-	for i := 0; i < PageSize+42; i++ {
-		keys = append(keys, fmt.Sprintf("key%d", i))
+	output2 := &s3.GetObjectOutput{
+		Body:          io.NopCloser(strings.NewReader("value")),
+		ETag:          ptr.To("etag"),
+		ContentLength: ptr.To(int64(len("value"))),
+		LastModified:  ptr.To((time.Time{}).Add(24 * time.Hour)),
 	}
 
-	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
-		Bucket: "bucket",
-		Keys:   keys,
-	})
-	require.NoError(t, err)
-
-	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "DeleteObjects", 2)
-}
+	api.On("GetObject", matchers.Context, mock.MatchedBy(fn2)).Return(output2, nil)
 
-func TestClientDeleteObjectsIgnoreNotFoundError(t *testing.T) {
-	api := &mockServiceAPI{}
+	fn3 := func(input *s3.PutObjectInput) bool {
+		var (
+			body   = input.Body != nil && bytes.Equal(testutil.ReadAll(t, input.Body), []byte("valueappended"))
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
+		)
 
-	output := &s3.DeleteObjectsOutput{
-		Errors: []types.Error{{Code: ptr.To("NoSuchKey"), Message: ptr.To("")}},
+		// The metadata of the existing object should be preserved
+		return body && bucket && key && input.Metadata["name"] == "value"
 	}
 
-	api.On("DeleteObjects", matchers.Context, mock.Anything).Return(output, nil)
+	api.On("PutObject", matchers.Context, mock.MatchedBy(fn3)).Return(&s3.PutObjectOutput{}, nil)
 
 	client := &Client{serviceAPI: api}
 
-	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
+	err := client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
 		Bucket: "bucket",
-		Keys:   []string{"key"},
+		Key:    "key",
+		Body:   strings.NewReader("appended"),
 	})
 	require.NoError(t, err)
 
 	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "DeleteObjects", 1)
+	api.AssertNumberOfCalls(t, "HeadObject", 1)
+	api.AssertNumberOfCalls(t, "GetObject", 1)
+	api.AssertNumberOfCalls(t, "PutObject", 1)
 }
 
-func TestClientDeleteDirectory(t *testing.T) {
+func TestClientAppendToObjectCreateMPUThenCopyAndAppend(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn1 := func(input *s3.ListObjectsV2Input) bool {
+	fn1 := func(input *s3.HeadObjectInput) bool {
 		var (
 			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			prefix = input.Prefix != nil && *input.Prefix == "prefix"
+			key    = input.Key != nil && *input.Key == "key"
 		)
 
-		return bucket && prefix
+		return bucket && key
 	}
 
-	contents1 := []types.Object{
-		{
-			Key:          ptr.To("/path/to/key1"),
-			Size:         ptr.To[int64](64),
-			LastModified: ptr.To((time.Time{}).Add(24 * time.Hour)),
-		},
-		{
-			Key:          ptr.To("/path/to/key2"),
-			Size:         ptr.To[int64](128),
-			LastModified: ptr.To((time.Time{}).Add(48 * time.Hour)),
-		},
+	output1 := &s3.HeadObjectOutput{
+		ETag:          ptr.To("etag"),
+		ContentLength: ptr.To[int64](MinUploadSize),
+		LastModified:  ptr.To((time.Time{}).Add(24 * time.Hour)),
 	}
 
-	api.On("ListObjectsV2", matchers.Context, mock.MatchedBy(fn1), mock.Anything).
-		Return(&s3.ListObjectsV2Output{Contents: contents1}, nil)
-
-	client := &Client{serviceAPI: api}
+	api.On("HeadObject", matchers.Context, mock.MatchedBy(fn1)).Return(output1, nil)
 
-	callback := func(_ context.Context, bucket string, keys ...string) error {
-		require.Equal(t, "bucket", bucket)
-		require.Equal(t, []string{"/path/to/key1", "/path/to/key2"}, keys)
+	fn2 := func(input *s3.CreateMultipartUploadInput) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
+		)
 
-		return nil
+		return bucket && key
 	}
 
-	err := client.deleteDirectory(context.Background(), "bucket", "prefix", callback)
-	require.NoError(t, err)
-
-	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "ListObjectsV2", 1)
-}
+	output2 := &s3.CreateMultipartUploadOutput{
+		UploadId: ptr.To("id"),
+	}
 
-func TestClientDeleteDirectoryWithCallbackError(t *testing.T) {
-	api := &mockServiceAPI{}
+	api.On("CreateMultipartUpload", matchers.Context, mock.MatchedBy(fn2)).Return(output2, nil)
 
-	fn1 := func(input *s3.ListObjectsV2Input) bool {
+	fn3 := func(input *s3.UploadPartCopyInput) bool {
 		var (
 			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			prefix = input.Prefix != nil && *input.Prefix == "prefix"
-		)
-
-		return bucket && prefix
-	}
+			src    = input.CopySource != nil && *input.CopySource == "bucket/key"
+			rnge   = input.CopySourceRange != nil && *input.CopySourceRange == fmt.Sprintf("bytes=0-%d", MinUploadSize-1)
+			key    = input.Key != nil && *input.Key == "key"
+			number = input.PartNumber != nil && *input.PartNumber == 1
+			id     = input.UploadId != nil && *input.UploadId == "id"
+		)
+
+		return bucket && src && rnge && key && number && id
+	}
+
+	output3 := &s3.UploadPartCopyOutput{
+		CopyPartResult: &types.CopyPartResult{ETag: ptr.To("etag1")},
+	}
+
+	api.On("UploadPartCopy", matchers.Context, mock.MatchedBy(fn3)).Return(output3, nil)
+
+	fn4 := func(input *s3.UploadPartInput) bool {
+		var (
+			body   = input.Body != nil && bytes.Equal(testutil.ReadAll(t, input.Body), []byte("appended"))
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
+			number = input.PartNumber != nil && *input.PartNumber == 2
+			id     = input.UploadId != nil && *input.UploadId == "id"
+		)
+
+		return body && bucket && key && number && id
+	}
+
+	output4 := &s3.UploadPartOutput{
+		ETag: ptr.To("etag2"),
+	}
+
+	api.On("UploadPart", matchers.Context, mock.MatchedBy(fn4)).Return(output4, nil)
+
+	fn5 := func(input *s3.CompleteMultipartUploadInput) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
+			id     = input.UploadId != nil && *input.UploadId == "id"
+			parts  = reflect.DeepEqual(input.MultipartUpload.Parts, []types.CompletedPart{
+				{ETag: ptr.To("etag1"), PartNumber: ptr.To[int32](1)},
+				{ETag: ptr.To("etag2"), PartNumber: ptr.To[int32](2)},
+			})
+		)
+
+		return bucket && key && id && parts
+	}
+
+	api.On("CompleteMultipartUpload", matchers.Context, mock.MatchedBy(fn5)).Return(nil, nil)
+
+	client := &Client{serviceAPI: api}
+
+	err := client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   strings.NewReader("appended"),
+	})
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "HeadObject", 1)
+	api.AssertNumberOfCalls(t, "CreateMultipartUpload", 1)
+	api.AssertNumberOfCalls(t, "UploadPartCopy", 1)
+	api.AssertNumberOfCalls(t, "UploadPart", 1)
+	api.AssertNumberOfCalls(t, "CompleteMultipartUpload", 1)
+}
+
+func TestClientAppendToObjectCreateMPUThenCopyAndAppendAbortOnFailure(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn1 := func(input *s3.HeadObjectInput) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
+		)
+
+		return bucket && key
+	}
+
+	output1 := &s3.HeadObjectOutput{
+		ETag:          ptr.To("etag"),
+		ContentLength: ptr.To[int64](MinUploadSize),
+		LastModified:  ptr.To((time.Time{}).Add(24 * time.Hour)),
+	}
+
+	api.On("HeadObject", matchers.Context, mock.MatchedBy(fn1)).Return(output1, nil)
+
+	fn2 := func(input *s3.CreateMultipartUploadInput) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
+		)
+
+		return bucket && key
+	}
+
+	output2 := &s3.CreateMultipartUploadOutput{
+		UploadId: ptr.To("id"),
+	}
+
+	api.On("CreateMultipartUpload", matchers.Context, mock.MatchedBy(fn2)).Return(output2, nil)
+
+	fn3 := func(input *s3.UploadPartCopyInput) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			src    = input.CopySource != nil && *input.CopySource == "bucket/key"
+			rnge   = input.CopySourceRange != nil && *input.CopySourceRange == fmt.Sprintf("bytes=0-%d", MinUploadSize-1)
+			key    = input.Key != nil && *input.Key == "key"
+			number = input.PartNumber != nil && *input.PartNumber == 1
+			id     = input.UploadId != nil && *input.UploadId == "id"
+		)
+
+		return bucket && src && rnge && key && number && id
+	}
+
+	api.On("UploadPartCopy", matchers.Context, mock.MatchedBy(fn3)).Return(nil, assert.AnError)
+
+	fn4 := func(input *s3.AbortMultipartUploadInput) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
+			id     = input.UploadId != nil && *input.UploadId == "id"
+		)
+
+		return bucket && key && id
+	}
+
+	api.On("AbortMultipartUpload", matchers.Context, mock.MatchedBy(fn4)).Return(nil, nil)
+
+	client := &Client{serviceAPI: api}
+
+	err := client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   strings.NewReader("appended"),
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "HeadObject", 1)
+	api.AssertNumberOfCalls(t, "CreateMultipartUpload", 1)
+	api.AssertNumberOfCalls(t, "UploadPartCopy", 1)
+	api.AssertNumberOfCalls(t, "AbortMultipartUpload", 1)
+}
+
+func TestClientDeleteObjectsNoKeys(t *testing.T) {
+	api := &mockServiceAPI{}
+	client := &Client{serviceAPI: api}
+
+	require.Equal(t, nil, client.deleteObjects(context.Background(), "bucket"))
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "DeleteObjects", 0)
+}
+
+func TestClientDeleteObjectsSinglePage(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn := func(input *s3.DeleteObjectsInput) bool {
+		var (
+			bucket  = input.Bucket != nil && *input.Bucket == "bucket"
+			quiet   = input.Delete != nil && input.Delete.Quiet != nil && *input.Delete.Quiet
+			objects = input.Delete != nil && reflect.DeepEqual(input.Delete.Objects, []types.ObjectIdentifier{
+				{Key: ptr.To("key1")},
+				{Key: ptr.To("key2")},
+				{Key: ptr.To("key3")},
+			})
+		)
+
+		return bucket && quiet && objects
+	}
+
+	api.On("DeleteObjects", matchers.Context, mock.MatchedBy(fn)).
+		Return(&s3.DeleteObjectsOutput{}, nil)
+
+	client := &Client{serviceAPI: api}
+
+	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
+		Bucket: "bucket",
+		Keys:   []string{"key1", "key2", "key3"},
+	})
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "DeleteObjects", 1)
+}
+
+func TestClientDeleteObjectsWithVersions(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn := func(input *s3.DeleteObjectsInput) bool {
+		return input.Delete != nil && reflect.DeepEqual(input.Delete.Objects, []types.ObjectIdentifier{
+			{Key: ptr.To("key1")},
+			{Key: ptr.To("key2"), VersionId: ptr.To("version")},
+			{Key: ptr.To("key3")},
+		})
+	}
+
+	api.On("DeleteObjects", matchers.Context, mock.MatchedBy(fn)).
+		Return(&s3.DeleteObjectsOutput{}, nil)
+
+	client := &Client{serviceAPI: api}
+
+	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
+		Bucket:  "bucket",
+		Keys:    []string{"key1"},
+		Objects: []objval.ObjectVersion{{Key: "key2", VersionID: "version"}, {Key: "key3"}},
+	})
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "DeleteObjects", 1)
+}
+
+func TestClientDeleteObjectsMultiplePages(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn1 := func(input *s3.DeleteObjectsInput) bool {
+		var (
+			bucket  = input.Bucket != nil && *input.Bucket == "bucket"
+			quiet   = input.Delete != nil && input.Delete.Quiet != nil && *input.Delete.Quiet
+			objects = input.Delete != nil && len(input.Delete.Objects) == PageSize
+		)
+
+		return bucket && quiet && objects
+	}
+
+	api.On("DeleteObjects", matchers.Context, mock.MatchedBy(fn1)).
+		Return(&s3.DeleteObjectsOutput{}, nil)
+
+	fn2 := func(input *s3.DeleteObjectsInput) bool {
+		var (
+			bucket  = input.Bucket != nil && *input.Bucket == "bucket"
+			quiet   = input.Delete != nil && input.Delete.Quiet != nil && *input.Delete.Quiet
+			objects = input.Delete != nil && len(input.Delete.Objects) == 42
+		)
+
+		return bucket && quiet && objects
+	}
+
+	api.On("DeleteObjects", matchers.Context, mock.MatchedBy(fn2)).
+		Return(&s3.DeleteObjectsOutput{}, nil)
+
+	client := &Client{serviceAPI: api}
+
+	keys := make([]string, 0, PageSize+42)
+
+	for i := 0; i < PageSize+42; i++ {
+		keys = append(keys, fmt.Sprintf("key%d", i))
+	}
+
+	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
+		Bucket: "bucket",
+		Keys:   keys,
+	})
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "DeleteObjects", 2)
+}
+
+func TestClientDeleteObjectsIgnoreNotFoundError(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	output := &s3.DeleteObjectsOutput{
+		Errors: []types.Error{{Code: ptr.To("NoSuchKey"), Message: ptr.To("")}},
+	}
+
+	api.On("DeleteObjects", matchers.Context, mock.Anything).Return(output, nil)
+
+	client := &Client{serviceAPI: api}
+
+	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
+		Bucket: "bucket",
+		Keys:   []string{"key"},
+	})
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "DeleteObjects", 1)
+}
+
+func TestClientDeleteDirectory(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn1 := func(input *s3.ListObjectsV2Input) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			prefix = input.Prefix != nil && *input.Prefix == "prefix"
+		)
+
+		return bucket && prefix
+	}
+
+	contents1 := []types.Object{
+		{
+			Key:          ptr.To("/path/to/key1"),
+			Size:         ptr.To[int64](64),
+			LastModified: ptr.To((time.Time{}).Add(24 * time.Hour)),
+		},
+		{
+			Key:          ptr.To("/path/to/key2"),
+			Size:         ptr.To[int64](128),
+			LastModified: ptr.To((time.Time{}).Add(48 * time.Hour)),
+		},
+	}
+
+	api.On("ListObjectsV2", matchers.Context, mock.MatchedBy(fn1), mock.Anything).
+		Return(&s3.ListObjectsV2Output{Contents: contents1}, nil)
+
+	client := &Client{serviceAPI: api}
+
+	callback := func(_ context.Context, bucket string, keys ...string) error {
+		require.Equal(t, "bucket", bucket)
+		require.Equal(t, []string{"/path/to/key1", "/path/to/key2"}, keys)
+
+		return nil
+	}
+
+	err := client.deleteDirectory(context.Background(), "bucket", "prefix", nil, nil, callback)
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "ListObjectsV2", 1)
+}
+
+func TestClientDeleteDirectoryWithFilters(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	contents := []types.Object{
+		{Key: ptr.To("prefix/key1.tmp")},
+		{Key: ptr.To("prefix/key2")},
+	}
+
+	api.On("ListObjectsV2", matchers.Context, mock.Anything, mock.Anything).
+		Return(&s3.ListObjectsV2Output{Contents: contents}, nil)
+
+	client := &Client{serviceAPI: api}
+
+	callback := func(_ context.Context, _ string, keys ...string) error {
+		require.Equal(t, []string{"prefix/key2"}, keys)
+		return nil
+	}
+
+	exclude := []*regexp.Regexp{regexp.MustCompile(`\.tmp$`)}
+
+	err := client.deleteDirectory(context.Background(), "bucket", "prefix", nil, exclude, callback)
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+}
+
+func TestClientDeleteDirectoryWithCallbackError(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn1 := func(input *s3.ListObjectsV2Input) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			prefix = input.Prefix != nil && *input.Prefix == "prefix"
+		)
+
+		return bucket && prefix
+	}
 
 	contents1 := []types.Object{
 		{
@@ -702,7 +1192,7 @@ func TestClientDeleteDirectoryWithCallbackError(t *testing.T) {
 		return assert.AnError
 	}
 
-	err := client.deleteDirectory(context.Background(), "bucket", "prefix", callback)
+	err := client.deleteDirectory(context.Background(), "bucket", "prefix", nil, nil, callback)
 	require.ErrorIs(t, err, assert.AnError)
 
 	api.AssertExpectations(t)
@@ -756,7 +1246,7 @@ func TestClientDeleteDirectoryVersions(t *testing.T) {
 		return nil
 	}
 
-	err := client.deleteDirectoryVersions(context.Background(), "bucket", "", callback)
+	err := client.deleteDirectoryVersions(context.Background(), "bucket", "", nil, nil, callback)
 	require.NoError(t, err)
 
 	api.AssertExpectations(t)
@@ -794,7 +1284,7 @@ func TestClientEmptyBucketWithCallbackError(t *testing.T) {
 		return assert.AnError
 	}
 
-	err := client.deleteDirectoryVersions(context.Background(), "bucket", "", callback)
+	err := client.deleteDirectoryVersions(context.Background(), "bucket", "", nil, nil, callback)
 	require.ErrorIs(t, err, assert.AnError)
 
 	api.AssertExpectations(t)
@@ -894,11 +1384,95 @@ func TestClientIterateObjectsDirectoryStub(t *testing.T) {
 		Func:      fn,
 	})
 	require.NoError(t, err)
-	require.Equal(t, 1, dirs)
-	require.Equal(t, 1, objects)
+	require.Equal(t, 1, dirs)
+	require.Equal(t, 1, objects)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "ListObjectsV2", 1)
+}
+
+func TestClientIterateObjectsDirectoryBucket(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn1 := func(input *s3.ListObjectsV2Input) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "bucket--usw2-az1--x-s3"
+			prefix = input.Prefix != nil && *input.Prefix == "path/to/"
+		)
+
+		return bucket && prefix
+	}
+
+	output1 := &s3.ListObjectsV2Output{
+		Contents: []types.Object{
+			{Key: ptr.To("path/to/other")},
+			{Key: ptr.To("path/to/key2")},
+			{Key: ptr.To("path/to/key1")},
+		},
+	}
+
+	api.On("ListObjectsV2", matchers.Context, mock.MatchedBy(fn1), mock.Anything).
+		Return(output1, nil)
+
+	var (
+		client = &Client{serviceAPI: api}
+		keys   []string
+	)
+
+	fn := func(attrs *objval.ObjectAttrs) error {
+		keys = append(keys, attrs.Key)
+		return nil
+	}
+
+	err := client.IterateObjects(context.Background(), objcli.IterateObjectsOptions{
+		Bucket: "bucket--usw2-az1--x-s3",
+		Prefix: "path/to/key",
+		Func:   fn,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"path/to/key2", "path/to/key1"}, keys)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "ListObjectsV2", 1)
+}
+
+func TestClientIterateObjectsPrefetch(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn1 := func(input *s3.ListObjectsV2Input) bool {
+		return ptr.From(input.MaxKeys) == 1 && input.ContinuationToken == nil
+	}
+
+	api.On("ListObjectsV2", matchers.Context, mock.MatchedBy(fn1), mock.Anything).
+		Return(&s3.ListObjectsV2Output{
+			Contents:              []types.Object{{Key: ptr.To("key1")}},
+			IsTruncated:           ptr.To(true),
+			NextContinuationToken: ptr.To("token"),
+		}, nil)
+
+	fn2 := func(input *s3.ListObjectsV2Input) bool {
+		return ptr.From(input.MaxKeys) == 1 && ptr.From(input.ContinuationToken) == "token"
+	}
+
+	api.On("ListObjectsV2", matchers.Context, mock.MatchedBy(fn2), mock.Anything).
+		Return(&s3.ListObjectsV2Output{Contents: []types.Object{{Key: ptr.To("key2")}}}, nil)
+
+	client := &Client{serviceAPI: api, listPageSize: 1, listPrefetch: 2}
+
+	var keys []string
+
+	err := client.IterateObjects(context.Background(), objcli.IterateObjectsOptions{
+		Bucket: "bucket",
+		Func: func(attrs *objval.ObjectAttrs) error {
+			keys = append(keys, attrs.Key)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"key1", "key2"}, keys)
 
 	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "ListObjectsV2", 1)
+	api.AssertNumberOfCalls(t, "ListObjectsV2", 2)
 }
 
 func TestClientIterateObjectsPropagateUserError(t *testing.T) {
@@ -1190,203 +1764,738 @@ func TestClientListPartsUploadNotFound(t *testing.T) {
 		return bucket && id && key
 	}
 
-	api.On("ListParts", matchers.Context, mock.MatchedBy(fn1), mock.Anything).
-		Return(nil, &types.NoSuchUpload{})
+	api.On("ListParts", matchers.Context, mock.MatchedBy(fn1), mock.Anything).
+		Return(nil, &types.NoSuchUpload{})
+
+	client := &Client{serviceAPI: api}
+
+	_, err := client.ListParts(context.Background(), objcli.ListPartsOptions{
+		Bucket:   "bucket",
+		UploadID: "id",
+		Key:      "key",
+	})
+	require.True(t, objerr.IsNotFoundError(err))
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "ListParts", 1)
+}
+
+func TestClientUploadPart(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn := func(input *s3.UploadPartInput) bool {
+		var (
+			body   = input.Body != nil && bytes.Equal(testutil.ReadAll(t, input.Body), []byte("value"))
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
+			number = input.PartNumber != nil && *input.PartNumber == 1
+			id     = input.UploadId != nil && *input.UploadId == "id"
+		)
+
+		return body && bucket && key && number && id
+	}
+
+	output := &s3.UploadPartOutput{
+		ETag: ptr.To("etag"),
+	}
+
+	api.On("UploadPart", matchers.Context, mock.MatchedBy(fn)).Return(output, nil)
+
+	client := &Client{serviceAPI: api}
+
+	part, err := client.UploadPart(context.Background(), objcli.UploadPartOptions{
+		Bucket:   "bucket",
+		UploadID: "id",
+		Key:      "key",
+		Number:   1,
+		Body:     strings.NewReader("value"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, objval.Part{ID: "etag", Number: 1, Size: 5}, part)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "UploadPart", 1)
+}
+
+func TestClientUploadPartCredentialsExpired(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	var bodies []string
+
+	record := func(args mock.Arguments) {
+		bodies = append(bodies, string(testutil.ReadAll(t, args.Get(1).(*s3.UploadPartInput).Body)))
+	}
+
+	api.On("UploadPart", matchers.Context, mock.Anything).
+		Run(record).
+		Return(nil, &smithy.GenericAPIError{Code: "ExpiredToken"}).
+		Once()
+
+	api.On("UploadPart", matchers.Context, mock.Anything).
+		Run(record).
+		Return(&s3.UploadPartOutput{ETag: ptr.To("etag")}, nil).
+		Once()
+
+	var retrieved int
+
+	provider := aws.CredentialsProviderFunc(func(_ context.Context) (aws.Credentials, error) {
+		retrieved++
+		return aws.Credentials{AccessKeyID: "id", SecretAccessKey: "secret", CanExpire: true}, nil
+	})
+
+	cache := aws.NewCredentialsCache(provider)
+
+	_, err := cache.Retrieve(context.Background())
+	require.NoError(t, err)
+
+	client := &Client{serviceAPI: api, credentials: cache}
+
+	part, err := client.UploadPart(context.Background(), objcli.UploadPartOptions{
+		Bucket:   "bucket",
+		UploadID: "id",
+		Key:      "key",
+		Number:   1,
+		Body:     strings.NewReader("value"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, objval.Part{ID: "etag", Number: 1, Size: 5}, part)
+
+	// The body should have been rewound for the second attempt
+	require.Equal(t, []string{"value", "value"}, bodies)
+
+	// The cached credentials should have been invalidated
+	_, err = cache.Retrieve(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, retrieved)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "UploadPart", 2)
+}
+
+func TestClientUploadPartCredentialsExpiredAfterRefresh(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	api.On("UploadPart", matchers.Context, mock.Anything).Return(nil, &smithy.GenericAPIError{Code: "ExpiredToken"})
+
+	client := &Client{serviceAPI: api}
+
+	_, err := client.UploadPart(context.Background(), objcli.UploadPartOptions{
+		Bucket:   "bucket",
+		UploadID: "id",
+		Key:      "key",
+		Number:   1,
+		Body:     strings.NewReader("value"),
+	})
+	require.ErrorIs(t, err, objerr.ErrCredentialsExpired)
+
+	api.AssertNumberOfCalls(t, "UploadPart", 2)
+}
+
+func TestClientUploadPartCopy(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn := func(input *s3.UploadPartCopyInput) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "dstBucket"
+			src    = input.CopySource != nil && *input.CopySource == "srcBucket/key1"
+			rnge   = input.CopySourceRange != nil && *input.CopySourceRange == "bytes=64-128"
+			key    = input.Key != nil && *input.Key == "key2"
+			number = input.PartNumber != nil && *input.PartNumber == 1
+			id     = input.UploadId != nil && *input.UploadId == "id"
+		)
+
+		return bucket && src && rnge && key && number && id
+	}
+
+	output := &s3.UploadPartCopyOutput{
+		CopyPartResult: &types.CopyPartResult{ETag: ptr.To("etag")},
+	}
+
+	api.On("UploadPartCopy", matchers.Context, mock.MatchedBy(fn)).Return(output, nil)
+
+	client := &Client{serviceAPI: api}
+
+	part, err := client.UploadPartCopy(context.Background(), objcli.UploadPartCopyOptions{
+		DestinationBucket: "dstBucket",
+		UploadID:          "id",
+		DestinationKey:    "key2",
+		SourceBucket:      "srcBucket",
+		SourceKey:         "key1",
+		Number:            1,
+		ByteRange:         &objval.ByteRange{Start: 64, End: 128},
+	})
+	require.NoError(t, err)
+	require.Equal(t, objval.Part{ID: "etag", Number: 1, Size: 65}, part)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "UploadPartCopy", 1)
+}
+
+func TestClientUploadPartCopyInvalidByteRange(t *testing.T) {
+	client := &Client{}
+
+	_, err := client.UploadPartCopy(context.Background(), objcli.UploadPartCopyOptions{
+		DestinationBucket: "dstBucket",
+		UploadID:          "id",
+		DestinationKey:    "key2",
+		SourceBucket:      "srcBucket",
+		SourceKey:         "key1",
+		Number:            1,
+		ByteRange:         &objval.ByteRange{Start: 128, End: 64},
+	})
+
+	var invalidByteRange *objval.InvalidByteRangeError
+
+	require.ErrorAs(t, err, &invalidByteRange)
+}
+
+func TestClientCompleteMultipartUpload(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn := func(input *s3.CompleteMultipartUploadInput) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
+			id     = input.UploadId != nil && *input.UploadId == "id"
+			parts  = reflect.DeepEqual(input.MultipartUpload.Parts, []types.CompletedPart{
+				{ETag: ptr.To("etag1"), PartNumber: ptr.To[int32](1)},
+				{ETag: ptr.To("etag2"), PartNumber: ptr.To[int32](2)},
+			})
+		)
+
+		return bucket && key && id && parts
+	}
+
+	api.On("CompleteMultipartUpload", matchers.Context, mock.MatchedBy(fn)).Return(nil, nil)
+
+	client := &Client{serviceAPI: api}
+
+	err := client.CompleteMultipartUpload(context.Background(), objcli.CompleteMultipartUploadOptions{
+		Bucket:   "bucket",
+		UploadID: "id",
+		Key:      "key",
+		Parts:    []objval.Part{{ID: "etag1", Number: 1}, {ID: "etag2", Number: 2}},
+	})
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "CompleteMultipartUpload", 1)
+}
+
+func TestClientUploadPartWithChecksum(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn := func(input *s3.UploadPartInput) bool {
+		return input.ChecksumCRC32C != nil && *input.ChecksumCRC32C == "4oIZdg=="
+	}
+
+	api.On("UploadPart", matchers.Context, mock.MatchedBy(fn)).Return(&s3.UploadPartOutput{ETag: ptr.To("etag")}, nil)
+
+	client := &Client{serviceAPI: api, checksumAlgorithm: types.ChecksumAlgorithmCrc32c}
+
+	part, err := client.UploadPart(context.Background(), objcli.UploadPartOptions{
+		Bucket:   "bucket",
+		UploadID: "id",
+		Key:      "key",
+		Number:   1,
+		Body:     strings.NewReader("value1"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, objval.Part{ID: "etag", Number: 1, Size: 6, Checksum: "4oIZdg=="}, part)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "UploadPart", 1)
+}
+
+func TestClientCompleteMultipartUploadWithChecksum(t *testing.T) {
+	type test struct {
+		name     string
+		output   *s3.CompleteMultipartUploadOutput
+		mismatch bool
+	}
+
+	tests := []*test{
+		{
+			name:   "Match",
+			output: &s3.CompleteMultipartUploadOutput{ChecksumCRC32C: ptr.To("r/GdeA==-2")},
+		},
+		{
+			name:     "Mismatch",
+			output:   &s3.CompleteMultipartUploadOutput{ChecksumCRC32C: ptr.To("AAAAAA==-2")},
+			mismatch: true,
+		},
+		{
+			name:   "NotReturned",
+			output: &s3.CompleteMultipartUploadOutput{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &mockServiceAPI{}
+
+			fn := func(input *s3.CompleteMultipartUploadInput) bool {
+				return reflect.DeepEqual(input.MultipartUpload.Parts, []types.CompletedPart{
+					{ETag: ptr.To("etag1"), PartNumber: ptr.To[int32](1), ChecksumCRC32C: ptr.To("4oIZdg==")},
+					{ETag: ptr.To("etag2"), PartNumber: ptr.To[int32](2), ChecksumCRC32C: ptr.To("8dLqgg==")},
+				})
+			}
+
+			api.On("CompleteMultipartUpload", matchers.Context, mock.MatchedBy(fn)).Return(test.output, nil)
+
+			client := &Client{serviceAPI: api, checksumAlgorithm: types.ChecksumAlgorithmCrc32c}
+
+			err := client.CompleteMultipartUpload(context.Background(), objcli.CompleteMultipartUploadOptions{
+				Bucket:   "bucket",
+				UploadID: "id",
+				Key:      "key",
+				Parts: []objval.Part{
+					{ID: "etag1", Number: 1, Checksum: "4oIZdg=="},
+					{ID: "etag2", Number: 2, Checksum: "8dLqgg=="},
+				},
+			})
+
+			api.AssertExpectations(t)
+
+			if !test.mismatch {
+				require.NoError(t, err)
+				return
+			}
+
+			var mismatch *ChecksumMismatchError
+
+			require.ErrorAs(t, err, &mismatch)
+			require.Equal(t, &ChecksumMismatchError{Key: "key", Expected: "r/GdeA==-2", Actual: "AAAAAA==-2"}, mismatch)
+		})
+	}
+}
+
+func TestClientAbortMultipartUpload(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	fn := func(input *s3.AbortMultipartUploadInput) bool {
+		var (
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			key    = input.Key != nil && *input.Key == "key"
+			id     = input.UploadId != nil && *input.UploadId == "id"
+		)
+
+		return bucket && key && id
+	}
+
+	api.On("AbortMultipartUpload", matchers.Context, mock.MatchedBy(fn)).Return(nil, nil)
 
 	client := &Client{serviceAPI: api}
 
-	_, err := client.ListParts(context.Background(), objcli.ListPartsOptions{
+	err := client.AbortMultipartUpload(context.Background(), objcli.AbortMultipartUploadOptions{
 		Bucket:   "bucket",
 		UploadID: "id",
 		Key:      "key",
 	})
-	require.True(t, objerr.IsNotFoundError(err))
+	require.NoError(t, err)
 
 	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "ListParts", 1)
+	api.AssertNumberOfCalls(t, "AbortMultipartUpload", 1)
 }
 
-func TestClientUploadPart(t *testing.T) {
+func TestClientAbortMultipartUploadNoSuchUpload(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn := func(input *s3.UploadPartInput) bool {
+	fn := func(input *s3.AbortMultipartUploadInput) bool {
 		var (
-			body   = input.Body != nil && bytes.Equal(testutil.ReadAll(t, input.Body), []byte("value"))
 			bucket = input.Bucket != nil && *input.Bucket == "bucket"
 			key    = input.Key != nil && *input.Key == "key"
-			number = input.PartNumber != nil && *input.PartNumber == 1
 			id     = input.UploadId != nil && *input.UploadId == "id"
 		)
 
-		return body && bucket && key && number && id
-	}
-
-	output := &s3.UploadPartOutput{
-		ETag: ptr.To("etag"),
+		return bucket && key && id
 	}
 
-	api.On("UploadPart", matchers.Context, mock.MatchedBy(fn)).Return(output, nil)
+	api.On("AbortMultipartUpload", matchers.Context, mock.MatchedBy(fn)).
+		Return(nil, &types.NoSuchUpload{})
 
 	client := &Client{serviceAPI: api}
 
-	part, err := client.UploadPart(context.Background(), objcli.UploadPartOptions{
+	err := client.AbortMultipartUpload(context.Background(), objcli.AbortMultipartUploadOptions{
 		Bucket:   "bucket",
 		UploadID: "id",
 		Key:      "key",
-		Number:   1,
-		Body:     strings.NewReader("value"),
 	})
 	require.NoError(t, err)
-	require.Equal(t, objval.Part{ID: "etag", Number: 1, Size: 5}, part)
 
 	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "UploadPart", 1)
+	api.AssertNumberOfCalls(t, "AbortMultipartUpload", 1)
 }
 
-func TestClientUploadPartCopy(t *testing.T) {
+func TestClientListMultipartUploads(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn := func(input *s3.UploadPartCopyInput) bool {
+	var (
+		initiated1 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		initiated2 = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	)
+
+	fn1 := func(input *s3.ListMultipartUploadsInput) bool {
 		var (
-			bucket = input.Bucket != nil && *input.Bucket == "dstBucket"
-			src    = input.CopySource != nil && *input.CopySource == "srcBucket/key1"
-			rnge   = input.CopySourceRange != nil && *input.CopySourceRange == "bytes=64-128"
-			key    = input.Key != nil && *input.Key == "key2"
-			number = input.PartNumber != nil && *input.PartNumber == 1
-			id     = input.UploadId != nil && *input.UploadId == "id"
+			bucket = input.Bucket != nil && *input.Bucket == "bucket"
+			prefix = input.Prefix != nil && *input.Prefix == "prefix/"
+			marker = input.KeyMarker == nil && input.UploadIdMarker == nil
 		)
 
-		return bucket && src && rnge && key && number && id
+		return bucket && prefix && marker
 	}
 
-	output := &s3.UploadPartCopyOutput{
-		CopyPartResult: &types.CopyPartResult{ETag: ptr.To("etag")},
+	output1 := &s3.ListMultipartUploadsOutput{
+		Uploads: []types.MultipartUpload{
+			{Key: ptr.To("prefix/key1"), UploadId: ptr.To("id1"), Initiated: ptr.To(initiated1)},
+		},
+		IsTruncated:        ptr.To(true),
+		NextKeyMarker:      ptr.To("prefix/key1"),
+		NextUploadIdMarker: ptr.To("id1"),
 	}
 
-	api.On("UploadPartCopy", matchers.Context, mock.MatchedBy(fn)).Return(output, nil)
+	api.On("ListMultipartUploads", matchers.Context, mock.MatchedBy(fn1)).Return(output1, nil).Once()
+
+	fn2 := func(input *s3.ListMultipartUploadsInput) bool {
+		var (
+			key = input.KeyMarker != nil && *input.KeyMarker == "prefix/key1"
+			id  = input.UploadIdMarker != nil && *input.UploadIdMarker == "id1"
+		)
+
+		return key && id
+	}
+
+	output2 := &s3.ListMultipartUploadsOutput{
+		Uploads: []types.MultipartUpload{
+			{Key: ptr.To("prefix/key2"), UploadId: ptr.To("id2"), Initiated: ptr.To(initiated2)},
+		},
+		IsTruncated: ptr.To(false),
+	}
+
+	api.On("ListMultipartUploads", matchers.Context, mock.MatchedBy(fn2)).Return(output2, nil).Once()
 
 	client := &Client{serviceAPI: api}
 
-	part, err := client.UploadPartCopy(context.Background(), objcli.UploadPartCopyOptions{
-		DestinationBucket: "dstBucket",
-		UploadID:          "id",
-		DestinationKey:    "key2",
-		SourceBucket:      "srcBucket",
-		SourceKey:         "key1",
-		Number:            1,
-		ByteRange:         &objval.ByteRange{Start: 64, End: 128},
+	uploads, err := client.ListMultipartUploads(context.Background(), objcli.ListMultipartUploadsOptions{
+		Bucket: "bucket",
+		Prefix: "prefix/",
 	})
 	require.NoError(t, err)
-	require.Equal(t, objval.Part{ID: "etag", Number: 1, Size: 65}, part)
+
+	expected := []objval.MultipartUpload{
+		{Key: "prefix/key1", UploadID: "id1", Initiated: initiated1},
+		{Key: "prefix/key2", UploadID: "id2", Initiated: initiated2},
+	}
+
+	require.Equal(t, expected, uploads)
 
 	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "UploadPartCopy", 1)
+	api.AssertNumberOfCalls(t, "ListMultipartUploads", 2)
 }
 
-func TestClientUploadPartCopyInvalidByteRange(t *testing.T) {
-	client := &Client{}
+func TestClientCreateBucket(t *testing.T) {
+	type test struct {
+		name       string
+		region     string
+		constraint types.BucketLocationConstraint
+	}
 
-	_, err := client.UploadPartCopy(context.Background(), objcli.UploadPartCopyOptions{
-		DestinationBucket: "dstBucket",
-		UploadID:          "id",
-		DestinationKey:    "key2",
-		SourceBucket:      "srcBucket",
-		SourceKey:         "key1",
-		Number:            1,
-		ByteRange:         &objval.ByteRange{Start: 128, End: 64},
-	})
+	tests := []*test{
+		{
+			name: "NoRegion",
+		},
+		{
+			name:   "DefaultRegion",
+			region: DefaultRegion,
+		},
+		{
+			name:       "Region",
+			region:     "eu-west-2",
+			constraint: types.BucketLocationConstraintEuWest2,
+		},
+	}
 
-	var invalidByteRange *objval.InvalidByteRangeError
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &mockServiceAPI{}
 
-	require.ErrorAs(t, err, &invalidByteRange)
+			fn := func(input *s3.CreateBucketInput) bool {
+				if input.Bucket == nil || *input.Bucket != "bucket" {
+					return false
+				}
+
+				if test.constraint == "" {
+					return input.CreateBucketConfiguration == nil
+				}
+
+				return input.CreateBucketConfiguration != nil &&
+					input.CreateBucketConfiguration.LocationConstraint == test.constraint
+			}
+
+			api.On("CreateBucket", matchers.Context, mock.MatchedBy(fn)).Return(nil, nil)
+
+			client := &Client{serviceAPI: api}
+
+			err := client.CreateBucket(context.Background(), objcli.CreateBucketOptions{
+				Bucket: "bucket",
+				Region: test.region,
+			})
+			require.NoError(t, err)
+
+			api.AssertExpectations(t)
+			api.AssertNumberOfCalls(t, "CreateBucket", 1)
+		})
+	}
 }
 
-func TestClientCompleteMultipartUpload(t *testing.T) {
+func TestClientCreateBucketDirectoryBucket(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn := func(input *s3.CompleteMultipartUploadInput) bool {
+	fn := func(input *s3.CreateBucketInput) bool {
+		if input.Bucket == nil || *input.Bucket != "bucket--usw2-az1--x-s3" {
+			return false
+		}
+
+		config := input.CreateBucketConfiguration
+		if config == nil || config.Location == nil || config.Bucket == nil {
+			return false
+		}
+
 		var (
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			key    = input.Key != nil && *input.Key == "key"
-			id     = input.UploadId != nil && *input.UploadId == "id"
-			parts  = reflect.DeepEqual(input.MultipartUpload.Parts, []types.CompletedPart{
-				{ETag: ptr.To("etag1"), PartNumber: ptr.To[int32](1)},
-				{ETag: ptr.To("etag2"), PartNumber: ptr.To[int32](2)},
-			})
+			location = ptr.From(config.Location.Name) == "usw2-az1" &&
+				config.Location.Type == types.LocationTypeAvailabilityZone
+			bucket = config.Bucket.Type == types.BucketTypeDirectory &&
+				config.Bucket.DataRedundancy == types.DataRedundancySingleAvailabilityZone
 		)
 
-		return bucket && key && id && parts
+		return location && bucket && config.LocationConstraint == ""
 	}
 
-	api.On("CompleteMultipartUpload", matchers.Context, mock.MatchedBy(fn)).Return(nil, nil)
+	api.On("CreateBucket", matchers.Context, mock.MatchedBy(fn)).Return(nil, nil)
 
 	client := &Client{serviceAPI: api}
 
-	err := client.CompleteMultipartUpload(context.Background(), objcli.CompleteMultipartUploadOptions{
-		Bucket:   "bucket",
-		UploadID: "id",
-		Key:      "key",
-		Parts:    []objval.Part{{ID: "etag1", Number: 1}, {ID: "etag2", Number: 2}},
+	err := client.CreateBucket(context.Background(), objcli.CreateBucketOptions{
+		Bucket: "bucket--usw2-az1--x-s3",
+		Region: "us-west-2",
 	})
 	require.NoError(t, err)
 
 	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "CompleteMultipartUpload", 1)
+	api.AssertNumberOfCalls(t, "CreateBucket", 1)
 }
 
-func TestClientAbortMultipartUpload(t *testing.T) {
+func TestClientDeleteBucket(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn := func(input *s3.AbortMultipartUploadInput) bool {
-		var (
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			key    = input.Key != nil && *input.Key == "key"
-			id     = input.UploadId != nil && *input.UploadId == "id"
-		)
+	fn := func(input *s3.DeleteBucketInput) bool {
+		return input.Bucket != nil && *input.Bucket == "bucket"
+	}
 
-		return bucket && key && id
+	api.On("DeleteBucket", matchers.Context, mock.MatchedBy(fn)).Return(nil, nil)
+
+	client := &Client{serviceAPI: api}
+
+	err := client.DeleteBucket(context.Background(), objcli.DeleteBucketOptions{Bucket: "bucket"})
+	require.NoError(t, err)
+
+	api.AssertExpectations(t)
+	api.AssertNumberOfCalls(t, "DeleteBucket", 1)
+}
+
+func TestClientDeleteBucketNotFound(t *testing.T) {
+	api := &mockServiceAPI{}
+
+	api.On("DeleteBucket", matchers.Context, mock.Anything).Return(nil, &types.NoSuchBucket{})
+
+	client := &Client{serviceAPI: api}
+
+	err := client.DeleteBucket(context.Background(), objcli.DeleteBucketOptions{Bucket: "bucket"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestClientGetBucketVersioning(t *testing.T) {
+	type test struct {
+		name     string
+		status   types.BucketVersioningStatus
+		expected objval.VersioningStatus
 	}
 
-	api.On("AbortMultipartUpload", matchers.Context, mock.MatchedBy(fn)).Return(nil, nil)
+	tests := []*test{
+		{
+			name:     "Disabled",
+			expected: objval.VersioningStatusDisabled,
+		},
+		{
+			name:     "Enabled",
+			status:   types.BucketVersioningStatusEnabled,
+			expected: objval.VersioningStatusEnabled,
+		},
+		{
+			name:     "Suspended",
+			status:   types.BucketVersioningStatusSuspended,
+			expected: objval.VersioningStatusSuspended,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &mockServiceAPI{}
+
+			fn := func(input *s3.GetBucketVersioningInput) bool {
+				return input.Bucket != nil && *input.Bucket == "bucket"
+			}
+
+			api.On("GetBucketVersioning", matchers.Context, mock.MatchedBy(fn)).
+				Return(&s3.GetBucketVersioningOutput{Status: test.status}, nil)
+
+			client := &Client{serviceAPI: api}
+
+			status, err := client.GetBucketVersioning(context.Background(), objcli.GetBucketVersioningOptions{
+				Bucket: "bucket",
+			})
+			require.NoError(t, err)
+			require.Equal(t, test.expected, status)
+
+			api.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClientGetBucketVersioningDirectoryBucket(t *testing.T) {
+	api := &mockServiceAPI{}
 
 	client := &Client{serviceAPI: api}
 
-	err := client.AbortMultipartUpload(context.Background(), objcli.AbortMultipartUploadOptions{
-		Bucket:   "bucket",
-		UploadID: "id",
-		Key:      "key",
+	status, err := client.GetBucketVersioning(context.Background(), objcli.GetBucketVersioningOptions{
+		Bucket: "bucket--usw2-az1--x-s3",
 	})
 	require.NoError(t, err)
+	require.Equal(t, objval.VersioningStatusDisabled, status)
 
-	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "AbortMultipartUpload", 1)
+	api.AssertNotCalled(t, "GetBucketVersioning")
 }
 
-func TestClientAbortMultipartUploadNoSuchUpload(t *testing.T) {
+func TestClientGetBucketRegion(t *testing.T) {
+	type test struct {
+		name       string
+		constraint types.BucketLocationConstraint
+		expected   string
+	}
+
+	tests := []*test{
+		{
+			name:     "Default",
+			expected: DefaultRegion,
+		},
+		{
+			name:       "Legacy",
+			constraint: types.BucketLocationConstraintEu,
+			expected:   "eu-west-1",
+		},
+		{
+			name:       "Region",
+			constraint: types.BucketLocationConstraintEuWest2,
+			expected:   "eu-west-2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &mockServiceAPI{}
+
+			fn := func(input *s3.GetBucketLocationInput) bool {
+				return input.Bucket != nil && *input.Bucket == "bucket"
+			}
+
+			api.On("GetBucketLocation", matchers.Context, mock.MatchedBy(fn)).
+				Return(&s3.GetBucketLocationOutput{LocationConstraint: test.constraint}, nil)
+
+			client := &Client{serviceAPI: api}
+
+			region, err := client.GetBucketRegion(context.Background(), objcli.GetBucketRegionOptions{
+				Bucket: "bucket",
+			})
+			require.NoError(t, err)
+			require.Equal(t, test.expected, region)
+
+			api.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClientQueryObject(t *testing.T) {
 	api := &mockServiceAPI{}
 
-	fn := func(input *s3.AbortMultipartUploadInput) bool {
+	fn := func(input *s3.SelectObjectContentInput) bool {
 		var (
-			bucket = input.Bucket != nil && *input.Bucket == "bucket"
-			key    = input.Key != nil && *input.Key == "key"
-			id     = input.UploadId != nil && *input.UploadId == "id"
+			bucket     = input.Bucket != nil && *input.Bucket == "bucket"
+			key        = input.Key != nil && *input.Key == "key"
+			expression = input.Expression != nil && *input.Expression == "SELECT * FROM S3Object"
+			sql        = input.ExpressionType == types.ExpressionTypeSql
+			in         = input.InputSerialization.JSON != nil && input.InputSerialization.JSON.Type == types.JSONTypeLines
+			out        = input.OutputSerialization.CSV != nil
 		)
 
-		return bucket && key && id
+		return bucket && key && expression && sql && in && out
 	}
 
-	api.On("AbortMultipartUpload", matchers.Context, mock.MatchedBy(fn)).
-		Return(nil, &types.NoSuchUpload{})
+	api.On("SelectObjectContent", matchers.Context, mock.MatchedBy(fn)).Return(nil, &types.NoSuchKey{})
 
 	client := &Client{serviceAPI: api}
 
-	err := client.AbortMultipartUpload(context.Background(), objcli.AbortMultipartUploadOptions{
-		Bucket:   "bucket",
-		UploadID: "id",
-		Key:      "key",
+	_, err := client.QueryObject(context.Background(), objcli.QueryObjectOptions{
+		Bucket:     "bucket",
+		Key:        "key",
+		Expression: "SELECT * FROM S3Object",
+		Input:      objval.QueryInput{JSONLines: true},
+		Output:     objval.QueryOutput{Format: objval.QueryFormatCSV},
 	})
-	require.NoError(t, err)
+	require.True(t, objerr.IsNotFoundError(err))
 
 	api.AssertExpectations(t)
-	api.AssertNumberOfCalls(t, "AbortMultipartUpload", 1)
+	api.AssertNumberOfCalls(t, "SelectObjectContent", 1)
+}
+
+// testPaginator is a paginator which returns the given number of pages, each page is its page number.
+type testPaginator struct {
+	pages   int
+	fetched atomic.Int64
+}
+
+func (t *testPaginator) HasMorePages() bool {
+	return t.fetched.Load() < int64(t.pages)
+}
+
+func (t *testPaginator) NextPage(ctx context.Context, _ ...func(*s3.Options)) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return int(t.fetched.Add(1)), nil
+}
+
+func TestListObjects(t *testing.T) {
+	for _, prefetch := range []int{-1, 0, 1, 4} {
+		t.Run(fmt.Sprintf("Prefetch%d", prefetch), func(t *testing.T) {
+			var pages []int
+
+			err := listObjects[int](context.Background(), &testPaginator{pages: 10}, prefetch, func(page int) error {
+				pages = append(pages, page)
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, pages)
+		})
+	}
+}
+
+func TestListObjectsPrefetchStopOnError(t *testing.T) {
+	pgn := &testPaginator{pages: 1_000}
+
+	err := listObjects[int](context.Background(), pgn, 2, func(_ int) error { return assert.AnError })
+	require.ErrorIs(t, err, assert.AnError)
+
+	// The page being processed, the buffered page and the page being fetched when cancelled
+	require.LessOrEqual(t, pgn.fetched.Load(), int64(3))
 }
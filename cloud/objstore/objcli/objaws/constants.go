@@ -1,12 +1,43 @@
 package objaws
 
+import "github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+
 const (
 	// PageSize is the default page size used by AWS.
 	PageSize = 1_000
 
+	// DefaultListPrefetch is the default number of pages which are fetched ahead when listing objects.
+	DefaultListPrefetch = 1
+
 	// MaxUploadParts is the maximum number of parts for a multipart upload in AWS.
 	MaxUploadParts = 10_000
 
 	// MinUploadSize is the minimum size for a multipart upload in AWS.
 	MinUploadSize = 5 * 1024 * 1024
+
+	// MaxCopySize is the maximum size of an object which may be copied using a single 'CopyObject' request in AWS.
+	MaxCopySize = 5 * 1000 * 1000 * 1000
+
+	// MinCopyPartSize is the minimum size of each part copied by 'CopyObject' when the source object is larger than
+	// 'MaxCopySize'; larger parts will be used where required to remain within 'MaxUploadParts'.
+	MinCopyPartSize = 512 * 1024 * 1024
+
+	// DefaultRegion is the region in which buckets are created when no location constraint is given.
+	DefaultRegion = "us-east-1"
+
+	// DefaultMaxAttempts is the default maximum number of attempts made for each request by clients created using
+	// 'NewS3Client'.
+	DefaultMaxAttempts = 10
+
+	// DirectoryBucketSuffix is the suffix which all S3 Express One Zone directory bucket names must have.
+	DirectoryBucketSuffix = "--x-s3"
 )
+
+// Capabilities are the capabilities of AWS S3, which are reported by default.
+var Capabilities = objval.Capabilities{
+	SupportsVersioning: true,
+	SupportsCompose:    true,
+	MaxParts:           MaxUploadParts,
+	MinPartSize:        MinUploadSize,
+	MaxCopySize:        MaxCopySize,
+}
@@ -0,0 +1,182 @@
+package objaws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+)
+
+const (
+	// DefaultRoleSessionDuration is the default duration of the session created when assuming a role.
+	DefaultRoleSessionDuration = time.Hour
+
+	// DefaultRoleSessionName is the default name of the session created when assuming a role.
+	DefaultRoleSessionName = "couchbase-tools"
+
+	// roleCredentialsExpiryWindow is the duration before the assumed role credentials expire, that they're refreshed.
+	roleCredentialsExpiryWindow = 5 * time.Minute
+
+	// unknownErrorCode is the code used by the SDK when STS responds with an error which doesn't contain a code.
+	unknownErrorCode = "UnknownError"
+)
+
+// AssumeRoleOptions encapsulates the options available when assuming an IAM role, this allows writing to buckets owned
+// by another account.
+type AssumeRoleOptions struct {
+	// RoleARN is the ARN of the role to assume e.g. 'arn:aws:iam::123456789012:role/backup'.
+	//
+	// NOTE: Required
+	RoleARN string
+
+	// ExternalID is the external ID required by the trust policy of the role, this is generally used when assuming a
+	// role in an account owned by a third party.
+	//
+	// NOTE: Not applicable when assuming a role using a web identity token.
+	ExternalID string
+
+	// WebIdentityTokenFile is the path to a file containing an OpenID Connect token, when provided the role is assumed
+	// using the token rather than the credentials from the config e.g. when running in Kubernetes using IRSA.
+	//
+	// NOTE: The file is read each time the credentials are refreshed, allowing the token to be rotated.
+	WebIdentityTokenFile string
+
+	// SessionName is used to identify the session created when assuming the role, defaults to
+	// 'DefaultRoleSessionName'.
+	SessionName string
+
+	// Duration is the duration of the session created when assuming the role, the credentials are automatically
+	// refreshed before they expire. Defaults to 'DefaultRoleSessionDuration'.
+	Duration time.Duration
+
+	// Endpoint overrides the STS endpoint used to assume the role, by default the SDK resolves the regional endpoint
+	// for the region from the config.
+	Endpoint string
+}
+
+// defaults fills any missing attributes to a sane default.
+func (a *AssumeRoleOptions) defaults() {
+	if a.SessionName == "" {
+		a.SessionName = DefaultRoleSessionName
+	}
+
+	if a.Duration <= 0 {
+		a.Duration = DefaultRoleSessionDuration
+	}
+}
+
+// newAssumeRoleCredentials returns a credentials provider which assumes the configured role using the given config,
+// the returned credentials are cached and refreshed before they expire.
+func newAssumeRoleCredentials(cfg aws.Config, opts AssumeRoleOptions) aws.CredentialsProvider {
+	opts.defaults()
+
+	if cfg.Region == "" {
+		cfg.Region = DefaultRegion
+	}
+
+	client := sts.NewFromConfig(cfg, func(options *sts.Options) {
+		if opts.Endpoint != "" {
+			options.BaseEndpoint = aws.String(opts.Endpoint)
+		}
+	})
+
+	provider := &assumeRoleProvider{arn: opts.RoleARN, source: cfg.Credentials}
+
+	if opts.WebIdentityTokenFile != "" {
+		provider.provider = stscreds.NewWebIdentityRoleProvider(
+			client,
+			opts.RoleARN,
+			stscreds.IdentityTokenFile(opts.WebIdentityTokenFile),
+			func(options *stscreds.WebIdentityRoleOptions) {
+				options.RoleSessionName = opts.SessionName
+				options.Duration = opts.Duration
+			},
+		)
+	} else {
+		provider.provider = stscreds.NewAssumeRoleProvider(client, opts.RoleARN, func(options *stscreds.AssumeRoleOptions) {
+			options.RoleSessionName = opts.SessionName
+			options.Duration = opts.Duration
+
+			if opts.ExternalID != "" {
+				options.ExternalID = aws.String(opts.ExternalID)
+			}
+		})
+	}
+
+	return aws.NewCredentialsCache(provider, func(options *aws.CredentialsCacheOptions) {
+		options.ExpiryWindow = roleCredentialsExpiryWindow
+	})
+}
+
+// assumeRoleProvider wraps an STS credentials provider, validating the options and converting errors from STS into an
+// 'AssumeRoleError'.
+type assumeRoleProvider struct {
+	arn      string
+	source   aws.CredentialsProvider
+	provider aws.CredentialsProvider
+}
+
+var _ aws.CredentialsProvider = (*assumeRoleProvider)(nil)
+
+// Retrieve assumes the configured role, returning temporary credentials for the role.
+func (a *assumeRoleProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	if a.arn == "" {
+		return aws.Credentials{}, ErrRoleARNRequired
+	}
+
+	// Requests to assume a role using a web identity token aren't signed, so don't require any source credentials
+	if _, ok := a.provider.(*stscreds.AssumeRoleProvider); ok && a.source == nil {
+		return aws.Credentials{}, objerr.ErrNoCredentialsFound
+	}
+
+	credentials, err := a.provider.Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, newAssumeRoleError(a.arn, err)
+	}
+
+	return credentials, nil
+}
+
+// newAssumeRoleError returns an 'AssumeRoleError' for the given error, or the error itself when STS didn't respond
+// with an error e.g. the request failed to send.
+func newAssumeRoleError(arn string, err error) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	assumeErr := &AssumeRoleError{RoleARN: arn, Code: apiErr.ErrorCode(), Message: apiErr.ErrorMessage()}
+
+	var respErr *smithyhttp.ResponseError
+
+	// Not all errors will have a body (e.g. those returned by a proxy), fallback to the status code
+	if (assumeErr.Code == "" || assumeErr.Code == unknownErrorCode) && errors.As(err, &respErr) {
+		assumeErr.Code = http.StatusText(respErr.HTTPStatusCode())
+	}
+
+	return assumeErr
+}
+
+// mapAssumeRoleErrorCode returns the generic error for the given STS error code, or <nil> if there isn't one.
+func mapAssumeRoleErrorCode(code string) error {
+	switch code {
+	case "AccessDenied":
+		return objerr.ErrUnauthorized
+	case "ExpiredToken", "ExpiredTokenException":
+		return objerr.ErrCredentialsExpired
+	case "InvalidClientTokenId", "SignatureDoesNotMatch", "InvalidIdentityToken", "IDPRejectedClaim":
+		return objerr.ErrUnauthenticated
+	case "Throttling", "RequestLimitExceeded":
+		return objerr.ErrThrottled
+	}
+
+	return nil
+}
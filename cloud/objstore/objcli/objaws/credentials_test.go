@@ -0,0 +1,210 @@
+package objaws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+)
+
+const (
+	testRoleARN = "arn:aws:iam::123456789012:role/backup"
+
+	testAssumeRoleResponse = `<AssumeRoleResponse>
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2030-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+	testAssumeRoleWithWebIdentityResponse = `<AssumeRoleWithWebIdentityResponse>
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2030-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`
+
+	testAccessDeniedResponse = `<ErrorResponse>
+  <Error>
+    <Type>Sender</Type>
+    <Code>AccessDenied</Code>
+    <Message>User is not authorized to perform: sts:AssumeRole</Message>
+  </Error>
+</ErrorResponse>`
+)
+
+var testSourceCredentials = aws.CredentialsProviderFunc(func(_ context.Context) (aws.Credentials, error) {
+	return aws.Credentials{AccessKeyID: "AKIAEXAMPLE", SecretAccessKey: "secret"}, nil
+})
+
+// newTestSTSServer returns a server which responds to all requests using the given status/body, the parsed requests
+// are sent on the returned channel.
+func newTestSTSServer(t *testing.T, status int, body string) (*httptest.Server, <-chan *http.Request) {
+	requests := make(chan *http.Request, 16)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		require.NoError(t, request.ParseForm())
+
+		requests <- request
+
+		writer.WriteHeader(status)
+		_, _ = writer.Write([]byte(body))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func TestAssumeRoleOptionsDefaults(t *testing.T) {
+	opts := AssumeRoleOptions{}
+	opts.defaults()
+	require.Equal(t, AssumeRoleOptions{
+		SessionName: DefaultRoleSessionName,
+		Duration:    DefaultRoleSessionDuration,
+	}, opts)
+}
+
+func TestAssumeRoleCredentials(t *testing.T) {
+	server, requests := newTestSTSServer(t, http.StatusOK, testAssumeRoleResponse)
+
+	provider := newAssumeRoleCredentials(
+		aws.Config{Region: "eu-west-1", Credentials: testSourceCredentials},
+		AssumeRoleOptions{RoleARN: testRoleARN, ExternalID: "external", Endpoint: server.URL},
+	)
+
+	credentials, err := provider.Retrieve(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "ASIAEXAMPLE", credentials.AccessKeyID)
+	require.Equal(t, "secret", credentials.SecretAccessKey)
+	require.Equal(t, "token", credentials.SessionToken)
+	require.True(t, credentials.CanExpire)
+
+	// The credentials should be refreshed before they expire
+	require.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Add(-roleCredentialsExpiryWindow), credentials.Expires)
+
+	request := <-requests
+	require.Equal(t, "AssumeRole", request.PostForm.Get("Action"))
+	require.Equal(t, testRoleARN, request.PostForm.Get("RoleArn"))
+	require.Equal(t, "external", request.PostForm.Get("ExternalId"))
+	require.Equal(t, DefaultRoleSessionName, request.PostForm.Get("RoleSessionName"))
+	require.Equal(t, "3600", request.PostForm.Get("DurationSeconds"))
+
+	authorization := request.Header.Get("Authorization")
+	require.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIAEXAMPLE/"))
+	require.Contains(t, authorization, "/eu-west-1/sts/aws4_request")
+
+	// Subsequent calls should use the cached credentials
+	_, err = provider.Retrieve(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, requests)
+}
+
+func TestAssumeRoleCredentialsWithWebIdentity(t *testing.T) {
+	server, requests := newTestSTSServer(t, http.StatusOK, testAssumeRoleWithWebIdentityResponse)
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("oidc-token"), 0o600))
+
+	provider := newAssumeRoleCredentials(
+		aws.Config{},
+		AssumeRoleOptions{RoleARN: testRoleARN, WebIdentityTokenFile: path, Endpoint: server.URL},
+	)
+
+	credentials, err := provider.Retrieve(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "ASIAEXAMPLE", credentials.AccessKeyID)
+
+	request := <-requests
+	require.Equal(t, "AssumeRoleWithWebIdentity", request.PostForm.Get("Action"))
+	require.Equal(t, "oidc-token", request.PostForm.Get("WebIdentityToken"))
+	require.Empty(t, request.Header.Get("Authorization"))
+}
+
+func TestAssumeRoleCredentialsAccessDenied(t *testing.T) {
+	server, _ := newTestSTSServer(t, http.StatusForbidden, testAccessDeniedResponse)
+
+	provider := newAssumeRoleCredentials(
+		aws.Config{Credentials: testSourceCredentials, RetryMaxAttempts: 1},
+		AssumeRoleOptions{RoleARN: testRoleARN, Endpoint: server.URL},
+	)
+
+	_, err := provider.Retrieve(context.Background())
+	require.ErrorIs(t, err, objerr.ErrUnauthorized)
+
+	var assumeErr *AssumeRoleError
+
+	require.ErrorAs(t, err, &assumeErr)
+	require.Equal(t, testRoleARN, assumeErr.RoleARN)
+	require.Equal(t, "AccessDenied", assumeErr.Code)
+	require.Equal(t, "User is not authorized to perform: sts:AssumeRole", assumeErr.Message)
+}
+
+func TestAssumeRoleCredentialsErrorWithoutBody(t *testing.T) {
+	server, _ := newTestSTSServer(t, http.StatusBadGateway, "")
+
+	provider := newAssumeRoleCredentials(
+		aws.Config{Credentials: testSourceCredentials, RetryMaxAttempts: 1},
+		AssumeRoleOptions{RoleARN: testRoleARN, Endpoint: server.URL},
+	)
+
+	_, err := provider.Retrieve(context.Background())
+
+	var assumeErr *AssumeRoleError
+
+	require.ErrorAs(t, err, &assumeErr)
+	require.Equal(t, http.StatusText(http.StatusBadGateway), assumeErr.Code)
+}
+
+func TestAssumeRoleCredentialsMissingOptions(t *testing.T) {
+	_, err := newAssumeRoleCredentials(aws.Config{}, AssumeRoleOptions{}).Retrieve(context.Background())
+	require.ErrorIs(t, err, ErrRoleARNRequired)
+
+	_, err = newAssumeRoleCredentials(aws.Config{}, AssumeRoleOptions{RoleARN: testRoleARN}).
+		Retrieve(context.Background())
+	require.ErrorIs(t, err, objerr.ErrNoCredentialsFound)
+}
+
+func TestNewS3ClientAssumeRoleAccessDenied(t *testing.T) {
+	sts, _ := newTestSTSServer(t, http.StatusForbidden, testAccessDeniedResponse)
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("Expected no requests to be sent to S3")
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("oidc-token"), 0o600))
+
+	client := NewClient(ClientOptions{
+		ServiceAPI: newTestS3Client(server, S3ClientOptions{
+			AssumeRole: &AssumeRoleOptions{RoleARN: testRoleARN, WebIdentityTokenFile: path, Endpoint: sts.URL},
+		}),
+	})
+
+	_, err := client.GetObject(context.Background(), objcli.GetObjectOptions{Bucket: "bucket", Key: "key"})
+	require.ErrorIs(t, err, objerr.ErrUnauthorized)
+
+	var assumeErr *AssumeRoleError
+
+	require.ErrorAs(t, err, &assumeErr)
+	require.Equal(t, "AccessDenied", assumeErr.Code)
+}
@@ -0,0 +1,45 @@
+package objaws
+
+import (
+	"strings"
+)
+
+// IsDirectoryBucket returns a boolean indicating whether the given bucket is an S3 Express One Zone directory bucket,
+// which are identified by their name e.g. 'bucket-base-name--usw2-az1--x-s3'.
+//
+// NOTE: Requests to directory buckets are authenticated using the 'CreateSession' flow, which is handled by the SDK.
+func IsDirectoryBucket(bucket string) bool {
+	return strings.HasSuffix(bucket, DirectoryBucketSuffix)
+}
+
+// directoryBucketZone returns the id of the zone in which the given directory bucket resides, this is the second to last
+// component of the bucket name.
+func directoryBucketZone(bucket string) string {
+	trimmed := strings.TrimSuffix(bucket, DirectoryBucketSuffix)
+
+	idx := strings.LastIndex(trimmed, "--")
+	if idx == -1 {
+		return ""
+	}
+
+	return trimmed[idx+2:]
+}
+
+// listPrefix returns the prefix which should be used when listing the given bucket, and the prefix which the returned
+// keys must then be filtered by (empty if no filtering is required).
+//
+// Directory buckets only support listing using prefixes which end with a '/', so for any other prefix we list from the
+// parent "directory" and filter the results.
+func listPrefix(bucket, prefix string) (string, string) {
+	if !IsDirectoryBucket(bucket) || prefix == "" || strings.HasSuffix(prefix, "/") {
+		return prefix, ""
+	}
+
+	return prefix[:strings.LastIndex(prefix, "/")+1], prefix
+}
+
+// hasPrefix returns a boolean indicating whether the given key should be included when filtering by the given prefix,
+// an empty prefix includes all keys.
+func hasPrefix(key, prefix string) bool {
+	return prefix == "" || strings.HasPrefix(key, prefix)
+}
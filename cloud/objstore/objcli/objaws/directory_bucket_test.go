@@ -0,0 +1,67 @@
+package objaws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDirectoryBucket(t *testing.T) {
+	require.True(t, IsDirectoryBucket("bucket--usw2-az1--x-s3"))
+	require.False(t, IsDirectoryBucket("bucket"))
+	require.False(t, IsDirectoryBucket("bucket--x-s3-other"))
+}
+
+func TestDirectoryBucketZone(t *testing.T) {
+	require.Equal(t, "usw2-az1", directoryBucketZone("bucket--usw2-az1--x-s3"))
+	require.Equal(t, "use1-az4", directoryBucketZone("my--bucket--use1-az4--x-s3"))
+	require.Equal(t, "", directoryBucketZone("bucket--x-s3"))
+}
+
+func TestListPrefix(t *testing.T) {
+	type test struct {
+		name           string
+		bucket, prefix string
+		list, filter   string
+	}
+
+	tests := []*test{
+		{
+			name:   "GeneralPurpose",
+			bucket: "bucket",
+			prefix: "path/to/key",
+			list:   "path/to/key",
+		},
+		{
+			name:   "DirectoryBucketEmptyPrefix",
+			bucket: "bucket--usw2-az1--x-s3",
+		},
+		{
+			name:   "DirectoryBucketDirectoryPrefix",
+			bucket: "bucket--usw2-az1--x-s3",
+			prefix: "path/to/",
+			list:   "path/to/",
+		},
+		{
+			name:   "DirectoryBucketPartialPrefix",
+			bucket: "bucket--usw2-az1--x-s3",
+			prefix: "path/to/key",
+			list:   "path/to/",
+			filter: "path/to/key",
+		},
+		{
+			name:   "DirectoryBucketTopLevelPrefix",
+			bucket: "bucket--usw2-az1--x-s3",
+			prefix: "key",
+			filter: "key",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			list, filter := listPrefix(test.bucket, test.prefix)
+			require.Equal(t, test.list, list)
+			require.Equal(t, test.filter, filter)
+		})
+	}
+}
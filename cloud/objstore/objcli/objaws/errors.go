@@ -0,0 +1,68 @@
+package objaws
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+var (
+	// ErrQueryIncomplete is returned when the results of a query end before S3 indicates that the query has completed.
+	ErrQueryIncomplete = errors.New("query results ended before the query completed")
+
+	// ErrRoleARNRequired is returned when attempting to assume a role, without providing the ARN of the role.
+	ErrRoleARNRequired = errors.New("the ARN of the role to assume is required")
+)
+
+// UnsupportedChecksumAlgorithmError is returned when a client is configured to use a checksum algorithm which isn't
+// supported.
+type UnsupportedChecksumAlgorithmError struct {
+	Algorithm types.ChecksumAlgorithm
+}
+
+// Error implements the 'error' interface.
+func (e *UnsupportedChecksumAlgorithmError) Error() string {
+	return fmt.Sprintf("unsupported checksum algorithm '%s'", e.Algorithm)
+}
+
+// ChecksumMismatchError is returned when completing a multipart upload, if the checksum calculated by S3 doesn't match
+// the checksum calculated from the uploaded parts.
+//
+// NOTE: The multipart upload will have been completed, the object should be considered corrupt.
+type ChecksumMismatchError struct {
+	Key      string
+	Expected string
+	Actual   string
+}
+
+// Error implements the 'error' interface.
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum mismatch for object '%s', expected '%s' but got '%s'", e.Key, e.Expected, e.Actual)
+}
+
+// AssumeRoleError is returned when STS refuses to provide credentials for the configured role e.g. because the trust
+// policy of the role doesn't allow it to be assumed using the given credentials/external ID.
+//
+// NOTE: Where possible, this error wraps a generic error from 'objerr' e.g. 'objerr.ErrUnauthorized'.
+type AssumeRoleError struct {
+	RoleARN string
+	Code    string
+	Message string
+}
+
+// Error implements the 'error' interface.
+func (e *AssumeRoleError) Error() string {
+	msg := fmt.Sprintf("failed to assume role '%s': %s", e.RoleARN, e.Code)
+
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+
+	return msg
+}
+
+// Unwrap returns the generic error for the error code returned by STS, if there is one.
+func (e *AssumeRoleError) Unwrap() error {
+	return mapAssumeRoleErrorCode(e.Code)
+}
@@ -0,0 +1,109 @@
+package objaws
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// selectEventStream is the minimal subset of the event stream returned by 'SelectObjectContent' that we use.
+type selectEventStream interface {
+	Events() <-chan types.SelectObjectContentEventStream
+	Close() error
+	Err() error
+}
+
+// selectReader exposes the records from an S3 Select event stream as an 'io.ReadCloser'.
+type selectReader struct {
+	stream selectEventStream
+	buffer []byte
+	ended  bool
+}
+
+// newSelectReader returns a reader which reads the records from the given event stream.
+func newSelectReader(stream selectEventStream) *selectReader {
+	return &selectReader{stream: stream}
+}
+
+func (s *selectReader) Read(p []byte) (int, error) {
+	for len(s.buffer) == 0 {
+		event, ok := <-s.stream.Events()
+		if ok {
+			s.handle(event)
+			continue
+		}
+
+		if err := s.stream.Err(); err != nil {
+			return 0, fmt.Errorf("failed to read query results: %w", err)
+		}
+
+		// S3 sends an 'End' event once all the results have been sent, if we don't receive one the results are
+		// incomplete, for example due to a network failure.
+		if !s.ended {
+			return 0, ErrQueryIncomplete
+		}
+
+		return 0, io.EOF
+	}
+
+	n := copy(p, s.buffer)
+	s.buffer = s.buffer[n:]
+
+	return n, nil
+}
+
+// handle processes a single event, progress/stats events are ignored.
+func (s *selectReader) handle(event types.SelectObjectContentEventStream) {
+	switch e := event.(type) {
+	case *types.SelectObjectContentEventStreamMemberRecords:
+		s.buffer = e.Value.Payload
+	case *types.SelectObjectContentEventStreamMemberEnd:
+		s.ended = true
+	}
+}
+
+func (s *selectReader) Close() error {
+	return s.stream.Close()
+}
+
+// newInputSerialization converts the given query input into the format used by S3 Select.
+func newInputSerialization(input objval.QueryInput) *types.InputSerialization {
+	serialization := &types.InputSerialization{CompressionType: types.CompressionTypeNone}
+
+	if input.Compression != "" {
+		serialization.CompressionType = types.CompressionType(input.Compression)
+	}
+
+	if input.Format == objval.QueryFormatCSV {
+		header := types.FileHeaderInfoNone
+		if input.CSVHeader {
+			header = types.FileHeaderInfoUse
+		}
+
+		serialization.CSV = &types.CSVInput{FileHeaderInfo: header}
+
+		return serialization
+	}
+
+	jsonType := types.JSONTypeDocument
+	if input.JSONLines {
+		jsonType = types.JSONTypeLines
+	}
+
+	serialization.JSON = &types.JSONInput{Type: jsonType}
+
+	return serialization
+}
+
+// newOutputSerialization converts the given query output into the format used by S3 Select.
+func newOutputSerialization(output objval.QueryOutput) *types.OutputSerialization {
+	if output.Format == objval.QueryFormatCSV {
+		return &types.OutputSerialization{CSV: &types.CSVOutput{}}
+	}
+
+	return &types.OutputSerialization{JSON: &types.JSONOutput{RecordDelimiter: ptr.To("\n")}}
+}
@@ -0,0 +1,119 @@
+package objaws
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// testEventStream is an event stream which returns the given events.
+type testEventStream struct {
+	events chan types.SelectObjectContentEventStream
+	err    error
+	closed bool
+}
+
+func newTestEventStream(err error, events ...types.SelectObjectContentEventStream) *testEventStream {
+	stream := &testEventStream{events: make(chan types.SelectObjectContentEventStream, len(events)), err: err}
+
+	for _, event := range events {
+		stream.events <- event
+	}
+
+	close(stream.events)
+
+	return stream
+}
+
+func (t *testEventStream) Events() <-chan types.SelectObjectContentEventStream {
+	return t.events
+}
+
+func (t *testEventStream) Close() error {
+	t.closed = true
+	return nil
+}
+
+func (t *testEventStream) Err() error {
+	return t.err
+}
+
+func records(payload string) types.SelectObjectContentEventStream {
+	return &types.SelectObjectContentEventStreamMemberRecords{Value: types.RecordsEvent{Payload: []byte(payload)}}
+}
+
+func TestSelectReader(t *testing.T) {
+	stream := newTestEventStream(
+		nil,
+		records(`{"a":1}`+"\n"),
+		&types.SelectObjectContentEventStreamMemberStats{},
+		records(`{"a":2}`+"\n"),
+		&types.SelectObjectContentEventStreamMemberEnd{},
+	)
+
+	reader := newSelectReader(stream)
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1}`+"\n"+`{"a":2}`+"\n", string(data))
+
+	require.NoError(t, reader.Close())
+	require.True(t, stream.closed)
+}
+
+func TestSelectReaderIncomplete(t *testing.T) {
+	_, err := io.ReadAll(newSelectReader(newTestEventStream(nil, records("data"))))
+	require.ErrorIs(t, err, ErrQueryIncomplete)
+}
+
+func TestSelectReaderStreamError(t *testing.T) {
+	errStream := errors.New("stream error")
+
+	_, err := io.ReadAll(newSelectReader(newTestEventStream(errStream, records("data"))))
+	require.ErrorIs(t, err, errStream)
+}
+
+func TestNewInputSerialization(t *testing.T) {
+	type test struct {
+		name     string
+		input    objval.QueryInput
+		expected *types.InputSerialization
+	}
+
+	tests := []*test{
+		{
+			name: "Default",
+			expected: &types.InputSerialization{
+				CompressionType: types.CompressionTypeNone,
+				JSON:            &types.JSONInput{Type: types.JSONTypeDocument},
+			},
+		},
+		{
+			name:  "JSONLinesGZIP",
+			input: objval.QueryInput{Compression: objval.QueryCompressionGZIP, JSONLines: true},
+			expected: &types.InputSerialization{
+				CompressionType: types.CompressionTypeGzip,
+				JSON:            &types.JSONInput{Type: types.JSONTypeLines},
+			},
+		},
+		{
+			name:  "CSVWithHeader",
+			input: objval.QueryInput{Format: objval.QueryFormatCSV, CSVHeader: true},
+			expected: &types.InputSerialization{
+				CompressionType: types.CompressionTypeNone,
+				CSV:             &types.CSVInput{FileHeaderInfo: types.FileHeaderInfoUse},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, newInputSerialization(test.input))
+		})
+	}
+}
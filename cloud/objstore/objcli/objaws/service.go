@@ -0,0 +1,230 @@
+package objaws
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+)
+
+// Attempt contains information about a single attempt at performing an S3 request, requests which are retried will
+// result in multiple attempts.
+type Attempt struct {
+	// Operation is the name of the S3 operation e.g. 'PutObject'.
+	Operation string
+
+	// Number is the attempt number, starting at one.
+	Number int
+
+	// Latency is the amount of time taken by the attempt, this doesn't include any time spent waiting to retry.
+	Latency time.Duration
+
+	// StatusCode is the HTTP status code returned by S3, zero if no response was received.
+	StatusCode int
+
+	// Throttled indicates that the attempt failed because the request was throttled by S3.
+	Throttled bool
+
+	// Err is the error returned by the attempt, if any.
+	Err error
+}
+
+// AttemptFunc is called after each attempt at performing an S3 request.
+//
+// NOTE: May be called concurrently, and should not block.
+type AttemptFunc func(attempt Attempt)
+
+// S3ClientOptions encapsulates the options available when creating an S3 client using 'NewS3Client'.
+type S3ClientOptions struct {
+	// Config is the AWS configuration used to create the client, in general this should be the one loaded using the
+	// 'config.LoadDefaultConfig' function exposed by the SDK.
+	//
+	// NOTE: Any retry mode (or retryer) set in the config takes precedence over the adaptive retry mode, and any API
+	// options are preserved.
+	Config aws.Config
+
+	// MaxAttempts is the maximum number of attempts made for each request, including the first attempt. When omitted
+	// the value from the config is used, falling back to 'DefaultMaxAttempts' unless the config contains a retryer.
+	MaxAttempts int
+
+	// OnAttempt is called after each attempt at performing a request, allowing collection of metrics e.g. to determine
+	// whether throttling is limiting throughput.
+	OnAttempt AttemptFunc
+
+	// AssumeRole is used to assume an IAM role, where the role is assumed using the credentials from the config (or a
+	// web identity token); this allows writing to buckets owned by another account. The credentials for the role are
+	// automatically refreshed before they expire.
+	//
+	// NOTE: Failing to assume the role results in an 'AssumeRoleError' being returned by the operations of 'Client'.
+	AssumeRole *AssumeRoleOptions
+
+	// Options are applied to the options used to create the client e.g. to set a custom endpoint.
+	Options []func(*s3.Options)
+}
+
+// NewS3Client returns a new S3 client, suitable for use with 'NewClient', which uses the adaptive retry mode by
+// default; the adaptive retry mode rate limits requests when S3 begins throttling.
+//
+// NOTE: Retries are reported to the sink of an 'objcli.EventClient' wrapping a 'Client' which uses the returned client.
+// Requests to S3 Express One Zone directory buckets are authenticated using session credentials, which are
+// created/cached by the SDK.
+func NewS3Client(opts S3ClientOptions) *s3.Client {
+	cfg := opts.Config.Copy()
+
+	if cfg.Retryer == nil && cfg.RetryMode == "" {
+		cfg.RetryMode = aws.RetryModeAdaptive
+	}
+
+	if cfg.Retryer == nil && cfg.RetryMaxAttempts == 0 {
+		cfg.RetryMaxAttempts = DefaultMaxAttempts
+	}
+
+	if opts.MaxAttempts > 0 {
+		cfg.RetryMaxAttempts = opts.MaxAttempts
+	}
+
+	if opts.AssumeRole != nil {
+		cfg.Credentials = newAssumeRoleCredentials(cfg, *opts.AssumeRole)
+	}
+
+	cfg.APIOptions = append(cfg.APIOptions, addRetriedEventMiddleware)
+
+	if opts.OnAttempt != nil {
+		cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+			return addAttemptMiddleware(stack, opts.OnAttempt)
+		})
+	}
+
+	return s3.NewFromConfig(cfg, opts.Options...)
+}
+
+// attemptKey is the stack value key used to track the number of attempts made for a request.
+type attemptKey struct{}
+
+// addAttemptMiddleware adds middleware to the given stack which calls the given function after each attempt.
+func addAttemptMiddleware(stack *middleware.Stack, fn AttemptFunc) error {
+	initialize := middleware.InitializeMiddlewareFunc(
+		"AttemptCounter",
+		func(
+			ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+		) (middleware.InitializeOutput, middleware.Metadata, error) {
+			return next.HandleInitialize(middleware.WithStackValue(ctx, attemptKey{}, new(int)), in)
+		},
+	)
+
+	err := stack.Initialize.Add(initialize, middleware.Before)
+	if err != nil {
+		return err
+	}
+
+	// Added after the retry middleware, so that it's run for each attempt
+	finalize := middleware.FinalizeMiddlewareFunc(
+		"AttemptMetrics",
+		func(
+			ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+		) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			attempt := Attempt{Operation: middleware.GetOperationName(ctx)}
+
+			if counter, ok := middleware.GetStackValue(ctx, attemptKey{}).(*int); ok {
+				*counter++
+				attempt.Number = *counter
+			}
+
+			start := time.Now()
+
+			out, metadata, err := next.HandleFinalize(ctx, in)
+
+			attempt.Latency = time.Since(start)
+			attempt.StatusCode = statusCode(metadata, err)
+			attempt.Throttled = err != nil && retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err).Bool()
+			attempt.Err = err
+
+			fn(attempt)
+
+			return out, metadata, err
+		},
+	)
+
+	return stack.Finalize.Insert(finalize, (&retry.Attempt{}).ID(), middleware.After)
+}
+
+// retriedEventKey is the stack value key used to track the attempts made for a request, when emitting retried events.
+type retriedEventKey struct{}
+
+// retriedEventState tracks the previous attempt made for a request.
+type retriedEventState struct {
+	attempt int
+	err     error
+}
+
+// addRetriedEventMiddleware adds middleware to the given stack which emits a retried event, for requests made as part
+// of an operation which has events enabled.
+func addRetriedEventMiddleware(stack *middleware.Stack) error {
+	initialize := middleware.InitializeMiddlewareFunc(
+		"RetriedEventState",
+		func(
+			ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+		) (middleware.InitializeOutput, middleware.Metadata, error) {
+			if objcli.OperationFromContext(ctx) == nil {
+				return next.HandleInitialize(ctx, in)
+			}
+
+			return next.HandleInitialize(middleware.WithStackValue(ctx, retriedEventKey{}, &retriedEventState{}), in)
+		},
+	)
+
+	err := stack.Initialize.Add(initialize, middleware.Before)
+	if err != nil {
+		return err
+	}
+
+	// Added after the retry middleware, so that it's run for each attempt
+	finalize := middleware.FinalizeMiddlewareFunc(
+		"RetriedEvent",
+		func(
+			ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+		) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			state, ok := middleware.GetStackValue(ctx, retriedEventKey{}).(*retriedEventState)
+			if !ok {
+				return next.HandleFinalize(ctx, in)
+			}
+
+			state.attempt++
+
+			if state.attempt > 1 {
+				objcli.OperationFromContext(ctx).Retried(state.attempt, state.err)
+			}
+
+			out, metadata, err := next.HandleFinalize(ctx, in)
+
+			state.err = err
+
+			return out, metadata, err
+		},
+	)
+
+	return stack.Finalize.Insert(finalize, (&retry.Attempt{}).ID(), middleware.After)
+}
+
+// statusCode returns the HTTP status code from the given attempt metadata/error, or zero if there's no response.
+func statusCode(metadata middleware.Metadata, err error) int {
+	var respErr interface{ HTTPStatusCode() int }
+
+	if errors.As(err, &respErr) {
+		return respErr.HTTPStatusCode()
+	}
+
+	if resp, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response); ok && resp != nil {
+		return resp.StatusCode
+	}
+
+	return 0
+}
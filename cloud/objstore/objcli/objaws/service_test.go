@@ -0,0 +1,148 @@
+package objaws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// newTestS3Client returns an S3 client which sends requests to the given server.
+func newTestS3Client(server *httptest.Server, opts S3ClientOptions) *s3.Client {
+	opts.Config.Region = "us-east-1"
+	opts.Config.Credentials = aws.AnonymousCredentials{}
+
+	opts.Options = append(opts.Options, func(options *s3.Options) {
+		options.BaseEndpoint = ptr.To(server.URL)
+		options.UsePathStyle = true
+	})
+
+	return NewS3Client(opts)
+}
+
+func TestNewS3ClientRetryDefaults(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	options := newTestS3Client(server, S3ClientOptions{}).Options()
+	require.Equal(t, aws.RetryModeAdaptive, options.RetryMode)
+	require.Equal(t, DefaultMaxAttempts, options.Retryer.MaxAttempts())
+
+	options = newTestS3Client(server, S3ClientOptions{MaxAttempts: 3}).Options()
+	require.Equal(t, 3, options.Retryer.MaxAttempts())
+
+	// The retry mode from the config should be preserved
+	options = newTestS3Client(server, S3ClientOptions{Config: aws.Config{RetryMode: aws.RetryModeStandard}}).Options()
+	require.Equal(t, aws.RetryModeStandard, options.RetryMode)
+
+	// As should a user provided retryer
+	retryer := func() aws.Retryer { return retry.AddWithMaxAttempts(retry.NewStandard(), 2) }
+
+	options = newTestS3Client(server, S3ClientOptions{Config: aws.Config{Retryer: retryer}}).Options()
+	require.Equal(t, 2, options.Retryer.MaxAttempts())
+}
+
+func TestNewS3ClientOnAttempt(t *testing.T) {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		requests++
+
+		if requests == 1 {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = writer.Write([]byte(`<Error><Code>SlowDown</Code><Message>Reduce your request rate</Message></Error>`))
+
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var (
+		lock     sync.Mutex
+		attempts []Attempt
+	)
+
+	onAttempt := func(attempt Attempt) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		attempts = append(attempts, attempt)
+	}
+
+	client := newTestS3Client(server, S3ClientOptions{OnAttempt: onAttempt})
+
+	_, err := client.GetObject(context.Background(), &s3.GetObjectInput{Bucket: ptr.To("bucket"), Key: ptr.To("key")})
+	require.NoError(t, err)
+
+	require.Len(t, attempts, 2)
+
+	require.Equal(t, "GetObject", attempts[0].Operation)
+	require.Equal(t, 1, attempts[0].Number)
+	require.Equal(t, http.StatusServiceUnavailable, attempts[0].StatusCode)
+	require.True(t, attempts[0].Throttled)
+	require.Error(t, attempts[0].Err)
+
+	require.Equal(t, 2, attempts[1].Number)
+	require.Equal(t, http.StatusOK, attempts[1].StatusCode)
+	require.False(t, attempts[1].Throttled)
+	require.NoError(t, attempts[1].Err)
+	require.Positive(t, attempts[1].Latency)
+}
+
+func TestNewS3ClientRetriedEvents(t *testing.T) {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		requests++
+
+		if requests == 1 {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = writer.Write([]byte(`<Error><Code>SlowDown</Code><Message>Reduce your request rate</Message></Error>`))
+
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var (
+		lock   sync.Mutex
+		events []objcli.Event
+	)
+
+	sink := objcli.EventSinkFunc(func(event objcli.Event) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		events = append(events, event)
+	})
+
+	client := objcli.NewEventClient(NewClient(ClientOptions{ServiceAPI: newTestS3Client(server, S3ClientOptions{})}), sink)
+
+	err := client.DeleteBucket(context.Background(), objcli.DeleteBucketOptions{Bucket: "bucket"})
+	require.NoError(t, err)
+
+	require.Len(t, events, 3)
+
+	require.Equal(t, objcli.EventStarted, events[0].Kind)
+
+	require.Equal(t, objcli.EventRetried, events[1].Kind)
+	require.Equal(t, "DeleteBucket", events[1].Operation)
+	require.Equal(t, 2, events[1].Attempt)
+	require.Error(t, events[1].Err)
+
+	require.Equal(t, objcli.EventCompleted, events[2].Kind)
+	require.NoError(t, events[2].Err)
+}
@@ -2,7 +2,10 @@ package objaws
 
 import (
 	"errors"
+	"net/url"
+	"slices"
 
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
 
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
@@ -14,6 +17,12 @@ import (
 // For the full list of error codes supported by AWS S3, please see
 // https://docs.aws.amazon.com/AmazonS3/latest/API/ErrorResponses.html#ErrorCodeList.
 func handleError(bucket, key *string, err error) error {
+	// Failing to assume a role surfaces when retrieving credentials, before the request is sent to S3
+	var assumeErr *AssumeRoleError
+	if errors.As(err, &assumeErr) {
+		return assumeErr
+	}
+
 	errorCode := extractErrorCode(err)
 	if errorCode == "" {
 		return objerr.HandleError(err)
@@ -22,8 +31,19 @@ func handleError(bucket, key *string, err error) error {
 	switch errorCode {
 	case "InvalidAccessKeyId", "SignatureDoesNotMatch":
 		return objerr.ErrUnauthenticated
+	case "ExpiredToken", "TokenRefreshRequired":
+		return objerr.ErrCredentialsExpired
 	case "AccessDenied":
 		return objerr.ErrUnauthorized
+	case "SlowDown", "Throttling", "ThrottlingException", "RequestLimitExceeded", "TooManyRequestsException":
+		return objerr.ErrThrottled
+	case "TooManyBuckets", "ServiceQuotaExceededException":
+		return objerr.ErrQuotaExceeded
+	case "BadDigest", "InvalidDigest", "XAmzContentSHA256Mismatch":
+		return objerr.ErrChecksumMismatch
+	case "NotModified":
+		// Conditional reads are rejected with a '304 Not Modified', which has no body so the code is the status text
+		return objerr.ErrNotModified
 	case "NoSuchKey", "NotFound":
 		if key == nil {
 			key = ptr.To("<empty key name>")
@@ -85,3 +105,34 @@ func extractErrorCode(err error) string {
 
 	return awsErr.ErrorCode()
 }
+
+// encodeTags encodes the given tags as URL query parameters, which is the format expected by the 'Tagging' attribute
+// when creating an object.
+func encodeTags(tags map[string]string) string {
+	values := make(url.Values, len(tags))
+
+	for key, value := range tags {
+		values.Set(key, value)
+	}
+
+	return values.Encode()
+}
+
+// tagSet converts the given tags into a tag set, sorted by key.
+func tagSet(tags map[string]string) []types.Tag {
+	keys := make([]string, 0, len(tags))
+
+	for key := range tags {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	set := make([]types.Tag, 0, len(keys))
+
+	for _, key := range keys {
+		set = append(set, types.Tag{Key: ptr.To(key), Value: ptr.To(tags[key])})
+	}
+
+	return set
+}
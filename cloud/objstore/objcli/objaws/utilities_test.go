@@ -32,9 +32,25 @@ func TestHandleError(t *testing.T) {
 	err = handleError(ptr.To("bucket1"), ptr.To("key1"), &smithy.GenericAPIError{Code: "SignatureDoesNotMatch"})
 	require.ErrorIs(t, err, objerr.ErrUnauthenticated)
 
+	err = handleError(ptr.To("bucket1"), ptr.To("key1"), &smithy.GenericAPIError{Code: "ExpiredToken"})
+	require.ErrorIs(t, err, objerr.ErrCredentialsExpired)
+	require.ErrorIs(t, err, objerr.ErrUnauthenticated)
+
 	err = handleError(ptr.To("bucket1"), ptr.To("key1"), &smithy.GenericAPIError{Code: "AccessDenied"})
 	require.ErrorIs(t, err, objerr.ErrUnauthorized)
 
+	err = handleError(ptr.To("bucket1"), ptr.To("key1"), &smithy.GenericAPIError{Code: "SlowDown"})
+	require.ErrorIs(t, err, objerr.ErrThrottled)
+
+	err = handleError(ptr.To("bucket1"), ptr.To("key1"), &smithy.GenericAPIError{Code: "TooManyBuckets"})
+	require.ErrorIs(t, err, objerr.ErrQuotaExceeded)
+
+	err = handleError(ptr.To("bucket1"), ptr.To("key1"), &smithy.GenericAPIError{Code: "BadDigest"})
+	require.ErrorIs(t, err, objerr.ErrChecksumMismatch)
+
+	err = handleError(ptr.To("bucket1"), ptr.To("key1"), &smithy.GenericAPIError{Code: "NotModified"})
+	require.ErrorIs(t, err, objerr.ErrNotModified)
+
 	err = handleError(ptr.To("bucket1"), ptr.To("key1"), &s3types.NoSuchKey{})
 	require.ErrorAs(t, err, &notFound)
 	require.Equal(t, "key", notFound.Type)
@@ -15,6 +15,7 @@ import (
 
 //go:generate go run github.com/golang/mock/mockgen -source ./api.go -destination ./mock_api.go -package objazure
 type serviceAPI interface {
+	GetAccountInfo(ctx context.Context, o *service.GetAccountInfoOptions) (service.GetAccountInfoResponse, error)
 	NewContainerClient(containerName string) containerAPI
 }
 
@@ -24,11 +25,19 @@ type serviceClient struct {
 
 var _ serviceAPI = (*serviceClient)(nil)
 
+func (c *serviceClient) GetAccountInfo(
+	ctx context.Context, o *service.GetAccountInfoOptions,
+) (service.GetAccountInfoResponse, error) {
+	return c.client.GetAccountInfo(ctx, o)
+}
+
 func (c *serviceClient) NewContainerClient(containerName string) containerAPI {
 	return containerClient{c.client.NewContainerClient(containerName)}
 }
 
 type containerAPI interface {
+	Create(ctx context.Context, options *container.CreateOptions) (container.CreateResponse, error)
+	Delete(ctx context.Context, options *container.DeleteOptions) (container.DeleteResponse, error)
 	NewBlobClient(name string) blobAPI
 	NewBlockBlobClient(name string) blockBlobAPI
 	NewBlockBlobVersionClient(name, version string) (blockBlobAPI, error)
@@ -40,6 +49,18 @@ type containerClient struct {
 	client *container.Client
 }
 
+func (c containerClient) Create(
+	ctx context.Context, options *container.CreateOptions,
+) (container.CreateResponse, error) {
+	return c.client.Create(ctx, options)
+}
+
+func (c containerClient) Delete(
+	ctx context.Context, options *container.DeleteOptions,
+) (container.DeleteResponse, error) {
+	return c.client.Delete(ctx, options)
+}
+
 func (c containerClient) NewBlockBlobClient(name string) blockBlobAPI {
 	return c.client.NewBlockBlobClient(name)
 }
@@ -65,6 +86,7 @@ func (c containerClient) NewListBlobsHierarchyPager(
 type blobAPI interface {
 	CopyFromURL(ctx context.Context, copySource string, o *blob.CopyFromURLOptions) (blob.CopyFromURLResponse, error)
 	GetSASURL(permissions sas.BlobPermissions, expiry time.Time, options *blob.GetSASURLOptions) (string, error)
+	Undelete(ctx context.Context, o *blob.UndeleteOptions) (blob.UndeleteResponse, error)
 }
 
 var _ blobAPI = (*blob.Client)(nil)
@@ -86,6 +108,8 @@ type blockBlobAPI interface {
 	Delete(ctx context.Context, options *blob.DeleteOptions) (blob.DeleteResponse, error)
 	DownloadStream(ctx context.Context, o *blob.DownloadStreamOptions) (blob.DownloadStreamResponse, error)
 	GetProperties(ctx context.Context, options *blob.GetPropertiesOptions) (blob.GetPropertiesResponse, error)
+	GetTags(ctx context.Context, o *blob.GetTagsOptions) (blob.GetTagsResponse, error)
+	SetTags(ctx context.Context, tags map[string]string, o *blob.SetTagsOptions) (blob.SetTagsResponse, error)
 	CommitBlockList(ctx context.Context, base64BlockIDs []string, options *blockblob.CommitBlockListOptions) (blockblob.CommitBlockListResponse, error)
 	GetBlockList(ctx context.Context, listType blockblob.BlockListType, options *blockblob.GetBlockListOptions) (blockblob.GetBlockListResponse, error)
 	StageBlock(ctx context.Context, base64BlockID string, body io.ReadSeekCloser, options *blockblob.StageBlockOptions) (blockblob.StageBlockResponse, error)
@@ -95,3 +119,13 @@ type blockBlobAPI interface {
 }
 
 var _ blockBlobAPI = (*blockblob.Client)(nil)
+
+// pathAPI is an interface which allows interactions with paths stored in an Azure storage account with a hierarchical
+// namespace enabled, these operations are atomic for directories.
+type pathAPI interface {
+	IsDirectory(ctx context.Context, fileSystem, path string) (bool, error)
+	DeletePath(ctx context.Context, fileSystem, path string) error
+	RenamePath(ctx context.Context, fileSystem, source, destination string) error
+}
+
+var _ pathAPI = (*DataLakeClient)(nil)
@@ -6,7 +6,10 @@ import (
 	"crypto/md5"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"regexp"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
@@ -19,6 +22,7 @@ import (
 	"github.com/couchbase/tools-common/types/v2/ptr"
 	"github.com/couchbase/tools-common/utils/v3/system"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
@@ -46,9 +50,18 @@ type attrs struct {
 // Client implements the 'objcli.Client' interface allowing the creation/management of blobs stored in Azure blob store.
 type Client struct {
 	serviceAPI serviceAPI
+	pathAPI    pathAPI
+
+	// hns caches whether the storage account has a hierarchical namespace enabled, this is lazily populated the first
+	// time it's required.
+	hns     *bool
+	hnsLock sync.Mutex
 }
 
-var _ objcli.Client = (*Client)(nil)
+var (
+	_ objcli.Client               = (*Client)(nil)
+	_ objcli.CredentialsRefresher = (*Client)(nil)
+)
 
 // ClientOptions encapsulates the options for creating a new Azure Client.
 type ClientOptions struct {
@@ -56,12 +69,25 @@ type ClientOptions struct {
 	//
 	// NOTE: Required
 	Client *service.Client
+
+	// DataLake is used to perform directory operations for storage accounts with a hierarchical namespace (ADLS Gen2)
+	// enabled, these operations are atomic and significantly faster than operating on each blob.
+	//
+	// NOTE: When omitted, or when the storage account has a flat namespace, directory operations are performed using
+	// the blob API.
+	DataLake *DataLakeClient
 }
 
 // NewClient returns a new client which uses the given service client, in general this should be the one created using
 // the 'azblob.NewServiceClient' function exposed by the SDK.
 func NewClient(options ClientOptions) *Client {
-	return &Client{serviceAPI: &serviceClient{client: options.Client}}
+	client := &Client{serviceAPI: &serviceClient{client: options.Client}}
+
+	if options.DataLake != nil {
+		client.pathAPI = options.DataLake
+	}
+
+	return client
 }
 
 func (c *Client) getBlobBlockClient(bucket, key string) blockBlobAPI {
@@ -76,6 +102,18 @@ func (c *Client) Provider() objval.Provider {
 	return objval.ProviderAzure
 }
 
+// Capabilities returns the capabilities of Azure blob storage, note that versioning is configured at the storage
+// account level, so isn't reported as supported; soft delete is also configured at the storage account level, but is
+// reported as supported since no deleted objects will be listed where it's disabled.
+func (c *Client) Capabilities() objval.Capabilities {
+	return objval.Capabilities{
+		SupportsCompose:    true,
+		SupportsSoftDelete: true,
+		MaxParts:           MaxBlocks,
+		MaxCopySize:        MaxCopySize,
+	}
+}
+
 func (c *Client) GetObject(ctx context.Context, opts objcli.GetObjectOptions) (*objval.Object, error) {
 	if err := opts.ByteRange.Valid(false); err != nil {
 		return nil, err // Purposefully not wrapped
@@ -88,10 +126,21 @@ func (c *Client) GetObject(ctx context.Context, opts objcli.GetObjectOptions) (*
 
 	blobClient := c.getBlobBlockClient(opts.Bucket, opts.Key)
 
-	resp, err := blobClient.DownloadStream(
-		ctx,
-		&blob.DownloadStreamOptions{Range: blob.HTTPRange{Offset: offset, Count: length}},
-	)
+	options := &blob.DownloadStreamOptions{Range: blob.HTTPRange{Offset: offset, Count: length}}
+
+	if opts.Conditional() {
+		options.AccessConditions = &blob.AccessConditions{ModifiedAccessConditions: &blob.ModifiedAccessConditions{}}
+	}
+
+	if !opts.IfModifiedSince.IsZero() {
+		options.AccessConditions.ModifiedAccessConditions.IfModifiedSince = ptr.To(opts.IfModifiedSince)
+	}
+
+	if opts.IfNoneMatch != "" {
+		options.AccessConditions.ModifiedAccessConditions.IfNoneMatch = ptr.To(azcore.ETag(opts.IfNoneMatch))
+	}
+
+	resp, err := blobClient.DownloadStream(ctx, options)
 	if err != nil {
 		return nil, handleError(opts.Bucket, opts.Key, err)
 	}
@@ -107,6 +156,16 @@ func (c *Client) GetObject(ctx context.Context, opts objcli.GetObjectOptions) (*
 		Body:        resp.Body,
 	}
 
+	if !opts.Decompress {
+		return object, nil
+	}
+
+	err = objcli.DecompressObject(object, ptr.From(resp.ContentEncoding))
+	if err != nil {
+		object.Body.Close()
+		return nil, fmt.Errorf("failed to decompress object: %w", err)
+	}
+
 	return object, nil
 }
 
@@ -123,6 +182,7 @@ func (c *Client) GetObjectAttrs(ctx context.Context, opts objcli.GetObjectAttrsO
 		ETag:         (*string)(resp.ETag),
 		Size:         resp.ContentLength,
 		LastModified: resp.LastModified,
+		Metadata:     fromBlobMetadata(resp.Metadata),
 	}
 
 	return attrs, nil
@@ -131,18 +191,68 @@ func (c *Client) GetObjectAttrs(ctx context.Context, opts objcli.GetObjectAttrsO
 func (c *Client) PutObject(ctx context.Context, opts objcli.PutObjectOptions) error {
 	blobClient := c.getBlobBlockClient(opts.Bucket, opts.Key)
 
+	body, err := objcli.CompressBody(opts.Body, opts.Compress)
+	if err != nil {
+		return err // Purposefully not wrapped
+	}
+
 	md5sum := md5.New()
 
-	_, err := objcli.CopyReadSeeker(md5sum, opts.Body)
+	_, err = objcli.CopyReadSeeker(md5sum, body)
 	if err != nil {
 		return fmt.Errorf("failed to calculate checksums: %w", err)
 	}
 
-	_, err = blobClient.Upload(
-		ctx,
-		manager.ReadSeekCloser(opts.Body),
-		&blockblob.UploadOptions{TransactionalValidation: blob.TransferValidationTypeMD5(md5sum.Sum(nil))},
-	)
+	options := &blockblob.UploadOptions{TransactionalValidation: blob.TransferValidationTypeMD5(md5sum.Sum(nil))}
+
+	if len(opts.Tags) != 0 {
+		options.Tags = opts.Tags
+	}
+
+	if len(opts.Metadata) != 0 {
+		options.Metadata = toBlobMetadata(opts.Metadata)
+	}
+
+	if opts.Compress != objcli.CompressionNone {
+		options.HTTPHeaders = &blob.HTTPHeaders{BlobContentEncoding: ptr.To(string(opts.Compress))}
+	}
+
+	_, err = blobClient.Upload(ctx, manager.ReadSeekCloser(body), options)
+
+	return handleError(opts.Bucket, opts.Key, err)
+}
+
+// GetObjectTags returns the blob index tags for the given blob.
+func (c *Client) GetObjectTags(ctx context.Context, opts objcli.GetObjectTagsOptions) (map[string]string, error) {
+	resp, err := c.getBlobBlockClient(opts.Bucket, opts.Key).GetTags(ctx, nil)
+	if err != nil {
+		return nil, handleError(opts.Bucket, opts.Key, err)
+	}
+
+	tags := make(map[string]string, len(resp.BlobTagSet))
+
+	for _, tag := range resp.BlobTagSet {
+		tags[ptr.From(tag.Key)] = ptr.From(tag.Value)
+	}
+
+	return tags, nil
+}
+
+// PutObjectTags replaces the blob index tags for the given blob.
+func (c *Client) PutObjectTags(ctx context.Context, opts objcli.PutObjectTagsOptions) error {
+	tags := opts.Tags
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+
+	_, err := c.getBlobBlockClient(opts.Bucket, opts.Key).SetTags(ctx, tags, nil)
+
+	return handleError(opts.Bucket, opts.Key, err)
+}
+
+// DeleteObjectTags removes all the blob index tags for the given blob.
+func (c *Client) DeleteObjectTags(ctx context.Context, opts objcli.DeleteObjectTagsOptions) error {
+	_, err := c.getBlobBlockClient(opts.Bucket, opts.Key).SetTags(ctx, make(map[string]string), nil)
 
 	return handleError(opts.Bucket, opts.Key, err)
 }
@@ -150,14 +260,19 @@ func (c *Client) PutObject(ctx context.Context, opts objcli.PutObjectOptions) er
 func (c *Client) CopyObject(ctx context.Context, opts objcli.CopyObjectOptions) error {
 	dstClient := c.serviceAPI.NewContainerClient(opts.DestinationBucket).NewBlobClient(opts.DestinationKey)
 
-	srcURL, err := c.getSASURL(opts.SourceBucket, opts.SourceKey)
-	if err != nil {
-		return fmt.Errorf("failed to get the source object URL: %w", err)
-	}
+	copyObject := func() error {
+		// A new SAS URL is generated for each attempt, so that retrying with refreshed credentials uses a fresh token
+		srcURL, err := c.getSASURL(opts.SourceBucket, opts.SourceKey)
+		if err != nil {
+			return fmt.Errorf("failed to get the source object URL: %w", err)
+		}
 
-	_, err = dstClient.CopyFromURL(ctx, srcURL, &blob.CopyFromURLOptions{})
+		_, err = dstClient.CopyFromURL(ctx, srcURL, &blob.CopyFromURLOptions{})
 
-	return handleError("", "", err)
+		return handleError("", "", err)
+	}
+
+	return objcli.WithCredentialsRefresh(ctx, c, copyObject)
 }
 
 func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectOptions) error {
@@ -167,17 +282,34 @@ func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectO
 	})
 
 	// As defined by the 'Client' interface, if the given object does not exist, we create it
-	if objerr.IsNotFoundError(err) || attrs != nil && ptr.From(attrs.Size) == 0 {
-		return c.PutObject(ctx, objcli.PutObjectOptions(opts))
+	if objerr.IsNotFoundError(err) {
+		return c.PutObject(ctx, objcli.PutObjectOptions{
+			Bucket:           opts.Bucket,
+			Key:              opts.Key,
+			Body:             opts.Body,
+			BandwidthLimiter: opts.BandwidthLimiter,
+		})
 	}
 
 	if err != nil {
 		return fmt.Errorf("failed to get object attributes: %w", err)
 	}
 
+	// Appending to an empty object is the same as replacing it, the metadata of the existing object is preserved
+	if ptr.From(attrs.Size) == 0 {
+		return c.PutObject(ctx, objcli.PutObjectOptions{
+			Bucket:           opts.Bucket,
+			Key:              opts.Key,
+			Body:             opts.Body,
+			Metadata:         attrs.Metadata,
+			BandwidthLimiter: opts.BandwidthLimiter,
+		})
+	}
+
 	id, err := c.CreateMultipartUpload(ctx, objcli.CreateMultipartUploadOptions{
-		Bucket: opts.Bucket,
-		Key:    opts.Key,
+		Bucket:   opts.Bucket,
+		Key:      opts.Key,
+		Metadata: attrs.Metadata,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to start multipart upload: %w", err)
@@ -211,6 +343,7 @@ func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectO
 		UploadID: id,
 		Key:      opts.Key,
 		Parts:    []objval.Part{existing, intermediate},
+		Metadata: attrs.Metadata,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to complete multipart upload: %w", err)
@@ -220,28 +353,43 @@ func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectO
 }
 
 func (c *Client) DeleteObjects(ctx context.Context, opts objcli.DeleteObjectsOptions) error {
+	objects := opts.AllObjects()
+
 	pool := hofp.NewPool(hofp.Options{
 		Context: ctx,
-		Size:    system.NumWorkers(len(opts.Keys)),
+		Size:    system.NumWorkers(len(objects)),
 	})
 
-	del := func(ctx context.Context, key string) error {
-		blobClient := c.getBlobBlockClient(opts.Bucket, key)
+	del := func(ctx context.Context, object objval.ObjectVersion) error {
+		var (
+			blobClient blockBlobAPI
+			err        error
+		)
 
-		_, err := blobClient.Delete(ctx, &blob.DeleteOptions{})
+		if object.VersionID == "" {
+			blobClient = c.getBlobBlockClient(opts.Bucket, object.Key)
+		} else {
+			blobClient, err = c.getBlobBlockVersionClient(opts.Bucket, object.Key, object.VersionID)
+		}
+
+		if err != nil {
+			return handleError(opts.Bucket, object.Key, err)
+		}
+
+		_, err = blobClient.Delete(ctx, &blob.DeleteOptions{})
 		if err != nil && !isKeyNotFound(err) {
-			return handleError(opts.Bucket, key, err)
+			return handleError(opts.Bucket, object.Key, err)
 		}
 
 		return nil
 	}
 
-	queue := func(key string) error {
-		return pool.Queue(func(ctx context.Context) error { return del(ctx, key) })
+	queue := func(object objval.ObjectVersion) error {
+		return pool.Queue(func(ctx context.Context) error { return del(ctx, object) })
 	}
 
-	for _, key := range opts.Keys {
-		if queue(key) != nil {
+	for _, object := range objects {
+		if queue(object) != nil {
 			break
 		}
 	}
@@ -249,7 +397,56 @@ func (c *Client) DeleteObjects(ctx context.Context, opts objcli.DeleteObjectsOpt
 	return pool.Stop()
 }
 
+// DeleteDirectory deletes all the objects with the given prefix.
+//
+// NOTE: For storage accounts with a hierarchical namespace, a prefix which ends with a '/' and refers to a directory is
+// deleted atomically; any other prefix (e.g. 'backup/2024-') is deleted blob-by-blob, so that it matches every blob
+// with the prefix, not just those in the directory.
 func (c *Client) DeleteDirectory(ctx context.Context, opts objcli.DeleteDirectoryOptions) error {
+	if opts.Include != nil && opts.Exclude != nil {
+		return objcli.ErrIncludeAndExcludeAreMutuallyExclusive
+	}
+
+	path, err := c.deletableDirectory(ctx, opts)
+	if err != nil {
+		return handleError(opts.Bucket, opts.Prefix, err)
+	}
+
+	if path == "" {
+		return c.deleteDirectory(ctx, opts)
+	}
+
+	err = c.pathAPI.DeletePath(ctx, opts.Bucket, path)
+	if err != nil && !isPathNotFound(err) {
+		return handleError(opts.Bucket, path, err)
+	}
+
+	return nil
+}
+
+// deletableDirectory returns the path of the directory which may be deleted using the path API, or an empty string if
+// the blobs must be deleted using the blob API.
+func (c *Client) deletableDirectory(ctx context.Context, opts objcli.DeleteDirectoryOptions) (string, error) {
+	// Deleting the path removes everything beneath it, so can't be used when only deleting some of the blobs
+	filtered := opts.Include != nil || opts.Exclude != nil
+
+	path := strings.Trim(opts.Prefix, "/")
+
+	// Only a prefix ending with a separator is equivalent to a directory, 'foo' must also match 'foobar'
+	if path == "" || !strings.HasSuffix(opts.Prefix, "/") || filtered || !c.hierarchicalNamespace(ctx) {
+		return "", nil
+	}
+
+	directory, err := c.pathAPI.IsDirectory(ctx, opts.Bucket, path)
+	if err != nil || !directory {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// deleteDirectory deletes all the objects with the given prefix using the blob API.
+func (c *Client) deleteDirectory(ctx context.Context, opts objcli.DeleteDirectoryOptions) error {
 	var (
 		// size matches the batch deletion size in AWS/Azure.
 		size  = 1000
@@ -279,9 +476,9 @@ func (c *Client) DeleteDirectory(ctx context.Context, opts objcli.DeleteDirector
 		opts.Bucket,
 		opts.Prefix,
 		"",
-		opts.Versions,
-		nil,
-		nil,
+		container.ListBlobsInclude{Versions: opts.Versions},
+		opts.Include,
+		opts.Exclude,
 		fn,
 	)
 	if err != nil {
@@ -297,6 +494,106 @@ func (c *Client) DeleteDirectory(ctx context.Context, opts objcli.DeleteDirector
 	return nil
 }
 
+// RenameDirectoryOptions encapsulates the options available when using the 'RenameDirectory' function.
+type RenameDirectoryOptions struct {
+	// Bucket is the bucket containing the directory.
+	Bucket string
+
+	// Source is the directory which will be renamed.
+	Source string
+
+	// Destination is the new name of the directory.
+	Destination string
+}
+
+// RenameDirectory renames the given directory.
+//
+// NOTE: For storage accounts with a hierarchical namespace, this is an atomic operation, otherwise, each blob is copied
+// to the destination and then removed; in the event of a failure, the directory may be partially renamed.
+func (c *Client) RenameDirectory(ctx context.Context, opts RenameDirectoryOptions) error {
+	var (
+		source      = strings.Trim(opts.Source, "/")
+		destination = strings.Trim(opts.Destination, "/")
+	)
+
+	if source == "" || destination == "" {
+		return ErrInvalidDirectory
+	}
+
+	if !c.hierarchicalNamespace(ctx) {
+		return c.renameDirectory(ctx, opts.Bucket, source, destination)
+	}
+
+	err := c.pathAPI.RenamePath(ctx, opts.Bucket, source, destination)
+	if err != nil {
+		return handleError(opts.Bucket, source, err)
+	}
+
+	return nil
+}
+
+// renameDirectory renames the given directory by copying then deleting each blob using the blob API.
+func (c *Client) renameDirectory(ctx context.Context, bucket, source, destination string) error {
+	pool := hofp.NewPool(hofp.Options{Context: ctx, Size: system.NumWorkers(0)})
+
+	fn := func(obj attrs) error {
+		return pool.Queue(func(ctx context.Context) error {
+			return c.CopyObject(ctx, objcli.CopyObjectOptions{
+				DestinationBucket: bucket,
+				DestinationKey:    destination + strings.TrimPrefix(obj.Key, source),
+				SourceBucket:      bucket,
+				SourceKey:         obj.Key,
+			})
+		})
+	}
+
+	ierr := c.iterateObjects(ctx, bucket, source+"/", "", container.ListBlobsInclude{}, nil, nil, fn)
+
+	// Always stop the pool, the error from the pool takes precedence since it's the cause of any iteration failure
+	err := pool.Stop()
+	if err != nil {
+		return fmt.Errorf("failed to copy objects: %w", err)
+	}
+
+	if ierr != nil {
+		return fmt.Errorf("failed to iterate objects: %w", ierr)
+	}
+
+	err = c.deleteDirectory(ctx, objcli.DeleteDirectoryOptions{Bucket: bucket, Prefix: source + "/"})
+	if err != nil {
+		return fmt.Errorf("failed to delete source objects: %w", err)
+	}
+
+	return nil
+}
+
+// hierarchicalNamespace returns a boolean indicating whether directory operations should use the Data Lake Storage
+// path API; this is only the case when a 'DataLakeClient' was provided, and the storage account has a hierarchical
+// namespace enabled.
+//
+// NOTE: Failing to determine whether the namespace is hierarchical is not fatal, we fall back to using the blob API.
+func (c *Client) hierarchicalNamespace(ctx context.Context) bool {
+	if c.pathAPI == nil {
+		return false
+	}
+
+	c.hnsLock.Lock()
+	defer c.hnsLock.Unlock()
+
+	if c.hns != nil {
+		return *c.hns
+	}
+
+	resp, err := c.serviceAPI.GetAccountInfo(ctx, &service.GetAccountInfoOptions{})
+	if err != nil {
+		return false
+	}
+
+	c.hns = ptr.To(ptr.From(resp.IsHierarchicalNamespaceEnabled))
+
+	return *c.hns
+}
+
 func (c *Client) deleteObjects(ctx context.Context, bucket string, objects ...attrs) error {
 	pool := hofp.NewPool(hofp.Options{
 		Context: ctx,
@@ -337,24 +634,26 @@ func (c *Client) Close() error {
 
 func (c *Client) IterateObjects(ctx context.Context, opts objcli.IterateObjectsOptions) error {
 	var (
-		bucket    = opts.Bucket
-		prefix    = opts.Prefix
-		delimiter = opts.Delimiter
-		include   = opts.Include
-		exclude   = opts.Exclude
-		fn        = func(obj attrs) error { return opts.Func(&obj.ObjectAttrs) }
+		bucket      = opts.Bucket
+		prefix      = opts.Prefix
+		delimiter   = opts.Delimiter
+		listInclude = toListBlobsInclude(opts.ListInclude)
+		include     = opts.Include
+		exclude     = opts.Exclude
+		fn          = func(obj attrs) error { return opts.Func(&obj.ObjectAttrs) }
 	)
 
-	return c.iterateObjects(ctx, bucket, prefix, delimiter, false, include, exclude, fn)
+	return c.iterateObjects(ctx, bucket, prefix, delimiter, listInclude, include, exclude, fn)
 }
 
-// iterateObjects is an internal object iteration function which also support enabling listing object versions.
+// iterateObjects is an internal object iteration function which also supports including additional datasets (e.g.
+// object versions) in the listing.
 func (c *Client) iterateObjects(
 	ctx context.Context,
 	bucket string,
 	prefix string,
 	delimiter string,
-	versions bool,
+	listInclude container.ListBlobsInclude,
 	include []*regexp.Regexp,
 	exclude []*regexp.Regexp,
 	fn func(attrs) error,
@@ -366,10 +665,10 @@ func (c *Client) iterateObjects(
 	containerClient := c.serviceAPI.NewContainerClient(bucket)
 
 	if delimiter == "" {
-		return c.iterateObjectsFlat(ctx, containerClient, bucket, prefix, versions, include, exclude, fn)
+		return c.iterateObjectsFlat(ctx, containerClient, bucket, prefix, listInclude, include, exclude, fn)
 	}
 
-	return c.iterateObjectsHierarchy(ctx, containerClient, bucket, prefix, delimiter, versions, include, exclude, fn)
+	return c.iterateObjectsHierarchy(ctx, containerClient, bucket, prefix, delimiter, listInclude, include, exclude, fn)
 }
 
 // iterateObjectsFlat iterates the given prefix as if it were flat (e.g. recursively).
@@ -377,13 +676,13 @@ func (c *Client) iterateObjectsFlat(
 	ctx context.Context,
 	containerClient containerAPI,
 	bucket, prefix string,
-	versions bool,
+	listInclude container.ListBlobsInclude,
 	include, exclude []*regexp.Regexp,
 	fn func(attrs) error,
 ) error {
 	options := container.ListBlobsFlatOptions{
 		Prefix:  &prefix,
-		Include: container.ListBlobsInclude{Versions: versions},
+		Include: listInclude,
 	}
 
 	pager := containerClient.NewListBlobsFlatPager(&options)
@@ -412,13 +711,13 @@ func (c *Client) iterateObjectsHierarchy(
 	ctx context.Context,
 	containerClient containerAPI,
 	bucket, prefix, delimiter string,
-	versions bool,
+	listInclude container.ListBlobsInclude,
 	include, exclude []*regexp.Regexp,
 	fn func(attrs) error,
 ) error {
 	options := container.ListBlobsHierarchyOptions{
 		Prefix:  &prefix,
-		Include: container.ListBlobsInclude{Versions: versions},
+		Include: listInclude,
 	}
 
 	pager := containerClient.NewListBlobsHierarchyPager(delimiter, &options)
@@ -463,6 +762,14 @@ func (c *Client) blobsToAttrs(prefixes []*string, blobs []*container.BlobItem) [
 			Key:          *b.Name,
 			Size:         b.Properties.ContentLength,
 			LastModified: b.Properties.LastModified,
+			Tags:         fromBlobTags(b.BlobTags),
+			VersionID:    b.VersionID,
+			Metadata:     fromBlobMetadata(b.Metadata),
+		}
+
+		// Azure returns an empty snapshot for the base blob
+		if ptr.From(b.Snapshot) != "" {
+			oa.Snapshot = b.Snapshot
 		}
 
 		attrs := attrs{
@@ -497,6 +804,65 @@ func (c *Client) iterateSegment(
 	return nil
 }
 
+// ListDeletedObjects returns the soft-deleted blobs with the given prefix, which have not yet been permanently deleted.
+//
+// NOTE: Blob soft delete must be enabled for the storage account, otherwise no deleted blobs will be listed.
+func (c *Client) ListDeletedObjects(
+	ctx context.Context,
+	opts objcli.ListDeletedObjectsOptions,
+) ([]*objval.DeletedObject, error) {
+	options := container.ListBlobsFlatOptions{
+		Prefix:  &opts.Prefix,
+		Include: container.ListBlobsInclude{Deleted: true},
+	}
+
+	var (
+		pager   = c.serviceAPI.NewContainerClient(opts.Bucket).NewListBlobsFlatPager(&options)
+		deleted = make([]*objval.DeletedObject, 0)
+	)
+
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, handleError(opts.Bucket, "", err)
+		}
+
+		for _, b := range resp.Segment.BlobItems {
+			// Listing deleted blobs also lists those which haven't been deleted
+			if !ptr.From(b.Deleted) {
+				continue
+			}
+
+			object := &objval.DeletedObject{
+				ObjectAttrs: objval.ObjectAttrs{
+					Key:          *b.Name,
+					Size:         b.Properties.ContentLength,
+					LastModified: b.Properties.LastModified,
+				},
+				DeletedTime: b.Properties.DeletedTime,
+			}
+
+			if b.Properties.RemainingRetentionDays != nil {
+				object.RemainingRetentionDays = ptr.To(int(*b.Properties.RemainingRetentionDays))
+			}
+
+			deleted = append(deleted, object)
+		}
+	}
+
+	return deleted, nil
+}
+
+// UndeleteObject restores the soft-deleted blob with the given key, along with any of its soft-deleted snapshots.
+//
+// NOTE: For storage accounts with blob versioning enabled, deleted blobs are retained as previous versions and must
+// instead be restored by copying the version.
+func (c *Client) UndeleteObject(ctx context.Context, opts objcli.UndeleteObjectOptions) error {
+	_, err := c.serviceAPI.NewContainerClient(opts.Bucket).NewBlobClient(opts.Key).Undelete(ctx, nil)
+
+	return handleError(opts.Bucket, opts.Key, err)
+}
+
 func (c *Client) CreateMultipartUpload(_ context.Context, _ objcli.CreateMultipartUploadOptions) (string, error) {
 	return objcli.NoUploadID, nil
 }
@@ -593,26 +959,38 @@ func (c *Client) UploadPartCopy(ctx context.Context, opts objcli.UploadPartCopyO
 
 	blockID := base64.StdEncoding.EncodeToString([]byte(uuid.NewString()))
 
-	srcURL, err := c.getSASURL(opts.SourceBucket, opts.SourceKey)
-	if err != nil {
-		return objval.Part{}, fmt.Errorf("failed to get the source part URL: %w", err)
-	}
-
 	dstClient := c.getBlobBlockClient(opts.DestinationBucket, opts.DestinationKey)
 
-	_, err = dstClient.StageBlockFromURL(
-		ctx,
-		blockID,
-		srcURL,
-		&blockblob.StageBlockFromURLOptions{Range: blob.HTTPRange{Offset: offset, Count: length}},
-	)
+	stage := func() error {
+		srcURL, err := c.getSASURL(opts.SourceBucket, opts.SourceKey)
+		if err != nil {
+			return fmt.Errorf("failed to get the source part URL: %w", err)
+		}
+
+		_, err = dstClient.StageBlockFromURL(
+			ctx,
+			blockID,
+			srcURL,
+			&blockblob.StageBlockFromURLOptions{Range: blob.HTTPRange{Offset: offset, Count: length}},
+		)
+
+		return handleError(opts.DestinationBucket, opts.DestinationKey, err)
+	}
+
+	err := objcli.WithCredentialsRefresh(ctx, c, stage)
 	if err != nil {
-		return objval.Part{}, handleError(opts.DestinationBucket, opts.DestinationKey, err)
+		return objval.Part{}, err // Purposefully not wrapped
 	}
 
 	return objval.Part{ID: blockID, Number: opts.Number, Size: length}, nil
 }
 
+// RefreshCredentials is a no-op, tokens are refreshed by the SDK and SAS URLs are generated for each copy request; this
+// allows copies which were rejected using an expired SAS to be retried.
+func (c *Client) RefreshCredentials(_ context.Context) error {
+	return nil
+}
+
 func (c *Client) getSASURL(bucket, src string) (string, error) {
 	var (
 		srcContainerClient = c.serviceAPI.NewContainerClient(bucket)
@@ -653,11 +1031,13 @@ func (c *Client) CompleteMultipartUpload(ctx context.Context, opts objcli.Comple
 		converted = append(converted, part.ID)
 	}
 
-	_, err := blobClient.CommitBlockList(
-		ctx,
-		converted,
-		&blockblob.CommitBlockListOptions{},
-	)
+	options := &blockblob.CommitBlockListOptions{}
+
+	if len(opts.Metadata) != 0 {
+		options.Metadata = toBlobMetadata(opts.Metadata)
+	}
+
+	_, err := blobClient.CommitBlockList(ctx, converted, options)
 
 	return handleError(opts.Bucket, opts.Key, err)
 }
@@ -672,3 +1052,60 @@ func (c *Client) AbortMultipartUpload(_ context.Context, opts objcli.AbortMultip
 
 	return nil
 }
+
+// ListMultipartUploads returns no uploads, Azure doesn't support listing staged blocks for objects which have never
+// been committed; it automatically garbage collects them after a certain amount of time.
+func (c *Client) ListMultipartUploads(
+	_ context.Context,
+	_ objcli.ListMultipartUploadsOptions,
+) ([]objval.MultipartUpload, error) {
+	return nil, nil
+}
+
+func (c *Client) CreateBucket(ctx context.Context, opts objcli.CreateBucketOptions) error {
+	// NOTE: Azure containers reside in the same region as their storage account, so the region is ignored
+
+	_, err := c.serviceAPI.NewContainerClient(opts.Bucket).Create(ctx, nil)
+
+	return handleError(opts.Bucket, "", err)
+}
+
+func (c *Client) DeleteBucket(ctx context.Context, opts objcli.DeleteBucketOptions) error {
+	_, err := c.serviceAPI.NewContainerClient(opts.Bucket).Delete(ctx, nil)
+
+	return handleError(opts.Bucket, "", err)
+}
+
+// GetBucketVersioning is unsupported for Azure, versioning is configured at the storage account level and may only be
+// retrieved using the management API.
+func (c *Client) GetBucketVersioning(
+	_ context.Context,
+	_ objcli.GetBucketVersioningOptions,
+) (objval.VersioningStatus, error) {
+	return "", objerr.ErrUnsupportedOperation
+}
+
+// GetBucketRegion is unsupported for Azure, containers reside in the same region as their storage account which may
+// only be retrieved using the management API.
+func (c *Client) GetBucketRegion(_ context.Context, _ objcli.GetBucketRegionOptions) (string, error) {
+	return "", objerr.ErrUnsupportedOperation
+}
+
+// SetObjectLock is unsupported for Azure, immutability policies aren't currently exposed by the client.
+func (c *Client) SetObjectLock(_ context.Context, _ objcli.SetObjectLockOptions) error {
+	return objerr.ErrUnsupportedOperation
+}
+
+// GetBucketLockingStatus is unsupported for Azure, immutability policies aren't currently exposed by the client.
+func (c *Client) GetBucketLockingStatus(
+	_ context.Context,
+	_ objcli.GetBucketLockingStatusOptions,
+) (objval.BucketLockingStatus, error) {
+	return objval.BucketLockingStatus{}, objerr.ErrUnsupportedOperation
+}
+
+// QueryObject is unsupported for Azure, the blob query API is only available for accounts without hierarchical
+// namespaces and isn't exposed by the SDK client we use.
+func (c *Client) QueryObject(_ context.Context, _ objcli.QueryObjectOptions) (io.ReadCloser, error) {
+	return nil, objerr.ErrUnsupportedOperation
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -15,10 +16,14 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azdatalake/datalakeerror"
 	gomock "github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
 	"github.com/couchbase/tools-common/testing/mock/matchers"
 	"github.com/couchbase/tools-common/types/v2/ptr"
@@ -32,6 +37,15 @@ func TestClientProvider(t *testing.T) {
 	require.Equal(t, objval.ProviderAzure, (&Client{}).Provider())
 }
 
+func TestClientCapabilities(t *testing.T) {
+	capabilities := (&Client{}).Capabilities()
+	require.False(t, capabilities.SupportsVersioning)
+	require.True(t, capabilities.SupportsCompose)
+	require.True(t, capabilities.SupportsSoftDelete)
+	require.Equal(t, MaxBlocks, capabilities.MaxParts)
+	require.Equal(t, int64(MaxCopySize), capabilities.MaxCopySize)
+}
+
 func newTestClient(t *testing.T) (*Client, *MockcontainerAPI, *MockblockBlobAPI) {
 	var (
 		ctrl = gomock.NewController(t)
@@ -121,6 +135,31 @@ func TestClientGetObjectWithByteRange(t *testing.T) {
 	require.Equal(t, expected, object)
 }
 
+func TestClientGetObjectConditional(t *testing.T) {
+	client, _, bAPI := newTestClient(t)
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	bAPI.
+		EXPECT().
+		DownloadStream(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, opts *blob.DownloadStreamOptions) (blob.DownloadStreamResponse, error) {
+			require.NotNil(t, opts.AccessConditions)
+			require.Equal(t, &since, opts.AccessConditions.ModifiedAccessConditions.IfModifiedSince)
+			require.Equal(t, ptr.To(azcore.ETag(`"0x8D"`)), opts.AccessConditions.ModifiedAccessConditions.IfNoneMatch)
+
+			return blob.DownloadStreamResponse{}, &azcore.ResponseError{StatusCode: http.StatusNotModified}
+		})
+
+	_, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:          "container",
+		Key:             "blob",
+		IfModifiedSince: since,
+		IfNoneMatch:     `"0x8D"`,
+	})
+	require.ErrorIs(t, err, objerr.ErrNotModified)
+}
+
 func TestClientGetObjectWithInvalidByteRange(t *testing.T) {
 	client := &Client{}
 
@@ -189,6 +228,75 @@ func TestClientPutObject(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClientPutObjectWithTags(t *testing.T) {
+	client, _, bAPI := newTestClient(t)
+
+	fn := func(
+		_ context.Context, _ io.ReadSeekCloser, opts *blockblob.UploadOptions,
+	) (blockblob.UploadResponse, error) {
+		require.Equal(t, map[string]string{"a": "b"}, opts.Tags)
+		return blockblob.UploadResponse{}, nil
+	}
+
+	bAPI.
+		EXPECT().
+		Upload(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(fn)
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket: "container",
+		Key:    "blob",
+		Body:   strings.NewReader("value"),
+		Tags:   map[string]string{"a": "b"},
+	})
+	require.NoError(t, err)
+}
+
+func TestClientGetObjectTags(t *testing.T) {
+	client, _, bAPI := newTestClient(t)
+
+	var output blob.GetTagsResponse
+
+	output.BlobTagSet = []*blob.Tags{{Key: ptr.To("a"), Value: ptr.To("b")}, {Key: ptr.To("c"), Value: ptr.To("d")}}
+
+	bAPI.
+		EXPECT().
+		GetTags(gomock.Any(), gomock.Any()).
+		Return(output, nil)
+
+	tags, err := client.GetObjectTags(context.Background(), objcli.GetObjectTagsOptions{Bucket: "container", Key: "blob"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "b", "c": "d"}, tags)
+}
+
+func TestClientPutObjectTags(t *testing.T) {
+	client, _, bAPI := newTestClient(t)
+
+	bAPI.
+		EXPECT().
+		SetTags(gomock.Any(), map[string]string{"a": "b"}, gomock.Any()).
+		Return(blob.SetTagsResponse{}, nil)
+
+	err := client.PutObjectTags(context.Background(), objcli.PutObjectTagsOptions{
+		Bucket: "container",
+		Key:    "blob",
+		Tags:   map[string]string{"a": "b"},
+	})
+	require.NoError(t, err)
+}
+
+func TestClientDeleteObjectTags(t *testing.T) {
+	client, _, bAPI := newTestClient(t)
+
+	bAPI.
+		EXPECT().
+		SetTags(gomock.Any(), map[string]string{}, gomock.Any()).
+		Return(blob.SetTagsResponse{}, nil)
+
+	err := client.DeleteObjectTags(context.Background(), objcli.DeleteObjectTagsOptions{Bucket: "container", Key: "blob"})
+	require.NoError(t, err)
+}
+
 func TestClientAppendToObjectNotExists(t *testing.T) {
 	client, _, bAPI := newTestClient(t)
 
@@ -350,6 +458,223 @@ func TestClientDeleteObjectsKeyNotFound(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClientDeleteObjectsWithVersions(t *testing.T) {
+	var (
+		ctrl = gomock.NewController(t)
+		sAPI = NewMockserviceAPI(ctrl)
+		cAPI = NewMockcontainerAPI(ctrl)
+		bAPI = NewMockblockBlobAPI(ctrl)
+	)
+
+	sAPI.EXPECT().NewContainerClient("container").Return(cAPI).Times(2)
+	cAPI.EXPECT().NewBlockBlobClient("blob1").Return(bAPI)
+	cAPI.EXPECT().NewBlockBlobVersionClient("blob2", "version").Return(bAPI, nil)
+
+	bAPI.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(blob.DeleteResponse{}, nil).Times(2)
+
+	client := &Client{serviceAPI: sAPI}
+
+	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
+		Bucket:  "container",
+		Keys:    []string{"blob1"},
+		Objects: []objval.ObjectVersion{{Key: "blob2", VersionID: "version"}},
+	})
+	require.NoError(t, err)
+}
+
+func TestClientIterateObjectsListInclude(t *testing.T) {
+	var (
+		ctrl  = gomock.NewController(t)
+		sAPI  = NewMockserviceAPI(ctrl)
+		cAPI  = NewMockcontainerAPI(ctrl)
+		pager = NewMockflatBlobsPager(ctrl)
+
+		modified = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	sAPI.EXPECT().NewContainerClient("container").Return(cAPI)
+
+	cAPI.
+		EXPECT().
+		NewListBlobsFlatPager(gomock.Any()).
+		DoAndReturn(func(opts *container.ListBlobsFlatOptions) flatBlobsPager {
+			require.Equal(t, container.ListBlobsInclude{Metadata: true, Tags: true, Snapshots: true}, opts.Include)
+			return pager
+		})
+
+	items := []*container.BlobItem{
+		{
+			Name:       ptr.To("blob"),
+			Snapshot:   ptr.To(""),
+			VersionID:  ptr.To("version"),
+			Metadata:   map[string]*string{"Format_version": ptr.To("2")},
+			BlobTags:   &container.BlobTags{BlobTagSet: []*container.BlobTag{{Key: ptr.To("k"), Value: ptr.To("v")}}},
+			Properties: &container.BlobProperties{ContentLength: ptr.To[int64](64), LastModified: &modified},
+		},
+		{
+			Name:       ptr.To("blob"),
+			Snapshot:   ptr.To("2024-01-01T00:00:00.0000000Z"),
+			Properties: &container.BlobProperties{ContentLength: ptr.To[int64](32), LastModified: &modified},
+		},
+	}
+
+	gomock.InOrder(
+		pager.EXPECT().More().Return(true),
+		pager.EXPECT().NextPage(matchers.Context).Return(container.ListBlobsFlatResponse{
+			ListBlobsFlatSegmentResponse: container.ListBlobsFlatSegmentResponse{
+				Segment: &container.BlobFlatListSegment{BlobItems: items},
+			},
+		}, nil),
+		pager.EXPECT().More().Return(false),
+	)
+
+	var (
+		client = &Client{serviceAPI: sAPI}
+		actual []*objval.ObjectAttrs
+	)
+
+	err := client.IterateObjects(context.Background(), objcli.IterateObjectsOptions{
+		Bucket:      "container",
+		ListInclude: objcli.ListInclude{Metadata: true, Tags: true, Snapshots: true},
+		Func: func(attrs *objval.ObjectAttrs) error {
+			actual = append(actual, attrs)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	expected := []*objval.ObjectAttrs{
+		{
+			Key:          "blob",
+			Size:         ptr.To[int64](64),
+			LastModified: &modified,
+			Metadata:     map[string]string{"format_version": "2"},
+			Tags:         map[string]string{"k": "v"},
+			VersionID:    ptr.To("version"),
+		},
+		{
+			Key:          "blob",
+			Size:         ptr.To[int64](32),
+			LastModified: &modified,
+			Snapshot:     ptr.To("2024-01-01T00:00:00.0000000Z"),
+		},
+	}
+
+	require.Equal(t, expected, actual)
+}
+
+func TestClientListDeletedObjects(t *testing.T) {
+	var (
+		ctrl  = gomock.NewController(t)
+		sAPI  = NewMockserviceAPI(ctrl)
+		cAPI  = NewMockcontainerAPI(ctrl)
+		pager = NewMockflatBlobsPager(ctrl)
+
+		modified = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		deleted  = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	)
+
+	sAPI.EXPECT().NewContainerClient("container").Return(cAPI)
+
+	cAPI.
+		EXPECT().
+		NewListBlobsFlatPager(gomock.Any()).
+		DoAndReturn(func(opts *container.ListBlobsFlatOptions) flatBlobsPager {
+			require.Equal(t, "prefix/", *opts.Prefix)
+			require.True(t, opts.Include.Deleted)
+
+			return pager
+		})
+
+	items := []*container.BlobItem{
+		{
+			Name:       ptr.To("prefix/blob1"),
+			Properties: &container.BlobProperties{ContentLength: ptr.To[int64](64), LastModified: &modified},
+		},
+		{
+			Name:    ptr.To("prefix/blob2"),
+			Deleted: ptr.To(true),
+			Properties: &container.BlobProperties{
+				ContentLength:          ptr.To[int64](128),
+				LastModified:           &modified,
+				DeletedTime:            &deleted,
+				RemainingRetentionDays: ptr.To[int32](6),
+			},
+		},
+	}
+
+	gomock.InOrder(
+		pager.EXPECT().More().Return(true),
+		pager.EXPECT().NextPage(matchers.Context).Return(container.ListBlobsFlatResponse{
+			ListBlobsFlatSegmentResponse: container.ListBlobsFlatSegmentResponse{
+				Segment: &container.BlobFlatListSegment{BlobItems: items},
+			},
+		}, nil),
+		pager.EXPECT().More().Return(false),
+	)
+
+	client := &Client{serviceAPI: sAPI}
+
+	objects, err := client.ListDeletedObjects(context.Background(), objcli.ListDeletedObjectsOptions{
+		Bucket: "container",
+		Prefix: "prefix/",
+	})
+	require.NoError(t, err)
+
+	expected := []*objval.DeletedObject{
+		{
+			ObjectAttrs: objval.ObjectAttrs{
+				Key:          "prefix/blob2",
+				Size:         ptr.To[int64](128),
+				LastModified: &modified,
+			},
+			DeletedTime:            &deleted,
+			RemainingRetentionDays: ptr.To(6),
+		},
+	}
+
+	require.Equal(t, expected, objects)
+}
+
+func TestClientUndeleteObject(t *testing.T) {
+	var (
+		ctrl = gomock.NewController(t)
+		sAPI = NewMockserviceAPI(ctrl)
+		cAPI = NewMockcontainerAPI(ctrl)
+		bAPI = NewMockblobAPI(ctrl)
+	)
+
+	sAPI.EXPECT().NewContainerClient("container").Return(cAPI)
+	cAPI.EXPECT().NewBlobClient("blob").Return(bAPI)
+	bAPI.EXPECT().Undelete(matchers.Context, gomock.Any()).Return(blob.UndeleteResponse{}, nil)
+
+	client := &Client{serviceAPI: sAPI}
+
+	err := client.UndeleteObject(context.Background(), objcli.UndeleteObjectOptions{Bucket: "container", Key: "blob"})
+	require.NoError(t, err)
+}
+
+func TestClientUndeleteObjectNotFound(t *testing.T) {
+	var (
+		ctrl = gomock.NewController(t)
+		sAPI = NewMockserviceAPI(ctrl)
+		cAPI = NewMockcontainerAPI(ctrl)
+		bAPI = NewMockblobAPI(ctrl)
+	)
+
+	sAPI.EXPECT().NewContainerClient("container").Return(cAPI)
+	cAPI.EXPECT().NewBlobClient("blob").Return(bAPI)
+	bAPI.EXPECT().Undelete(matchers.Context, gomock.Any()).Return(
+		blob.UndeleteResponse{},
+		&azcore.ResponseError{ErrorCode: string(bloberror.BlobNotFound)},
+	)
+
+	client := &Client{serviceAPI: sAPI}
+
+	err := client.UndeleteObject(context.Background(), objcli.UndeleteObjectOptions{Bucket: "container", Key: "blob"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
 func TestClientCreateMultipartUpload(t *testing.T) {
 	client := &Client{}
 
@@ -664,3 +989,252 @@ func TestClientAbortMultipartUploadWithUploadID(t *testing.T) {
 	})
 	require.ErrorIs(t, err, objcli.ErrExpectedNoUploadID)
 }
+
+func TestClientCreateBucket(t *testing.T) {
+	var (
+		ctrl = gomock.NewController(t)
+		sAPI = NewMockserviceAPI(ctrl)
+		cAPI = NewMockcontainerAPI(ctrl)
+	)
+
+	sAPI.EXPECT().NewContainerClient("container").Return(cAPI)
+	cAPI.EXPECT().Create(gomock.Any(), gomock.Any()).Return(container.CreateResponse{}, nil)
+
+	client := &Client{serviceAPI: sAPI}
+
+	err := client.CreateBucket(context.Background(), objcli.CreateBucketOptions{Bucket: "container"})
+	require.NoError(t, err)
+}
+
+func TestClientDeleteBucket(t *testing.T) {
+	var (
+		ctrl = gomock.NewController(t)
+		sAPI = NewMockserviceAPI(ctrl)
+		cAPI = NewMockcontainerAPI(ctrl)
+	)
+
+	sAPI.EXPECT().NewContainerClient("container").Return(cAPI)
+	cAPI.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(
+		container.DeleteResponse{},
+		&azcore.ResponseError{ErrorCode: string(bloberror.ContainerNotFound)},
+	)
+
+	client := &Client{serviceAPI: sAPI}
+
+	err := client.DeleteBucket(context.Background(), objcli.DeleteBucketOptions{Bucket: "container"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestClientGetBucketVersioning(t *testing.T) {
+	client := &Client{}
+
+	_, err := client.GetBucketVersioning(context.Background(), objcli.GetBucketVersioningOptions{Bucket: "container"})
+	require.ErrorIs(t, err, objerr.ErrUnsupportedOperation)
+}
+
+func TestClientGetBucketRegion(t *testing.T) {
+	client := &Client{}
+
+	_, err := client.GetBucketRegion(context.Background(), objcli.GetBucketRegionOptions{Bucket: "container"})
+	require.ErrorIs(t, err, objerr.ErrUnsupportedOperation)
+}
+
+func newTestHNSClient(t *testing.T, hns bool) (*Client, *MockserviceAPI, *MockpathAPI) {
+	var (
+		ctrl = gomock.NewController(t)
+		sAPI = NewMockserviceAPI(ctrl)
+		pAPI = NewMockpathAPI(ctrl)
+	)
+
+	sAPI.
+		EXPECT().
+		GetAccountInfo(matchers.Context, gomock.Any()).
+		Return(service.GetAccountInfoResponse{IsHierarchicalNamespaceEnabled: ptr.To(hns)}, nil).
+		MaxTimes(1)
+
+	return &Client{serviceAPI: sAPI, pathAPI: pAPI}, sAPI, pAPI
+}
+
+func TestClientDeleteDirectoryHierarchicalNamespace(t *testing.T) {
+	client, _, pAPI := newTestHNSClient(t, true)
+
+	pAPI.EXPECT().IsDirectory(matchers.Context, "container", "path/to/dir").Return(true, nil).Times(2)
+	pAPI.EXPECT().DeletePath(matchers.Context, "container", "path/to/dir").Return(nil).Times(2)
+
+	// The account info should be cached, and the trailing separator removed
+	for range 2 {
+		err := client.DeleteDirectory(context.Background(), objcli.DeleteDirectoryOptions{
+			Bucket: "container",
+			Prefix: "path/to/dir/",
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestClientDeleteDirectoryHierarchicalNamespacePathNotFound(t *testing.T) {
+	client, _, pAPI := newTestHNSClient(t, true)
+
+	pAPI.EXPECT().IsDirectory(matchers.Context, "container", "dir").Return(true, nil)
+
+	// The directory may be removed after it was found, which isn't an error
+	pAPI.
+		EXPECT().
+		DeletePath(matchers.Context, "container", "dir").
+		Return(respError(bloberror.Code(datalakeerror.PathNotFound)))
+
+	err := client.DeleteDirectory(context.Background(), objcli.DeleteDirectoryOptions{
+		Bucket: "container",
+		Prefix: "dir/",
+	})
+	require.NoError(t, err)
+}
+
+func TestClientDeleteDirectoryHierarchicalNamespaceFileSystemNotFound(t *testing.T) {
+	client, _, pAPI := newTestHNSClient(t, true)
+
+	pAPI.
+		EXPECT().
+		IsDirectory(matchers.Context, "container", "dir").
+		Return(false, respError(bloberror.Code(datalakeerror.FileSystemNotFound)))
+
+	err := client.DeleteDirectory(context.Background(), objcli.DeleteDirectoryOptions{
+		Bucket: "container",
+		Prefix: "dir/",
+	})
+
+	var notFound *objerr.NotFoundError
+
+	require.ErrorAs(t, err, &notFound)
+	require.Equal(t, "container", notFound.Type)
+}
+
+func TestClientDeleteDirectoryHierarchicalNamespaceNotDirectory(t *testing.T) {
+	type test struct {
+		name   string
+		prefix string
+	}
+
+	tests := []test{
+		{
+			name:   "NoTrailingSeparator",
+			prefix: "backup/2024-",
+		},
+		{
+			name:   "DirectoryWithoutTrailingSeparator",
+			prefix: "dir",
+		},
+		{
+			name:   "NotFound",
+			prefix: "dir/",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				client, sAPI, pAPI = newTestHNSClient(t, true)
+				ctrl               = gomock.NewController(t)
+				cAPI               = NewMockcontainerAPI(ctrl)
+				pager              = NewMockflatBlobsPager(ctrl)
+			)
+
+			// Only a prefix with a trailing separator may be a directory
+			if strings.HasSuffix(test.prefix, "/") {
+				pAPI.EXPECT().IsDirectory(matchers.Context, "container", strings.Trim(test.prefix, "/")).Return(false, nil)
+			}
+
+			sAPI.EXPECT().NewContainerClient("container").Return(cAPI)
+
+			cAPI.
+				EXPECT().
+				NewListBlobsFlatPager(gomock.Any()).
+				DoAndReturn(func(opts *container.ListBlobsFlatOptions) flatBlobsPager {
+					require.Equal(t, test.prefix, *opts.Prefix)
+					return pager
+				})
+
+			pager.EXPECT().More().Return(false)
+
+			err := client.DeleteDirectory(context.Background(), objcli.DeleteDirectoryOptions{
+				Bucket: "container",
+				Prefix: test.prefix,
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClientDeleteDirectoryFlatNamespace(t *testing.T) {
+	var (
+		client, sAPI, _ = newTestHNSClient(t, false)
+		ctrl            = gomock.NewController(t)
+		cAPI            = NewMockcontainerAPI(ctrl)
+		pager           = NewMockflatBlobsPager(ctrl)
+	)
+
+	sAPI.EXPECT().NewContainerClient("container").Return(cAPI)
+
+	cAPI.
+		EXPECT().
+		NewListBlobsFlatPager(gomock.Any()).
+		DoAndReturn(func(opts *container.ListBlobsFlatOptions) flatBlobsPager {
+			require.Equal(t, "dir/", *opts.Prefix)
+			return pager
+		})
+
+	pager.EXPECT().More().Return(false)
+
+	err := client.DeleteDirectory(context.Background(), objcli.DeleteDirectoryOptions{
+		Bucket: "container",
+		Prefix: "dir/",
+	})
+	require.NoError(t, err)
+}
+
+func TestClientRenameDirectoryHierarchicalNamespace(t *testing.T) {
+	client, _, pAPI := newTestHNSClient(t, true)
+
+	pAPI.EXPECT().RenamePath(matchers.Context, "container", "src/dir", "dst").Return(nil)
+
+	err := client.RenameDirectory(context.Background(), RenameDirectoryOptions{
+		Bucket:      "container",
+		Source:      "src/dir/",
+		Destination: "/dst/",
+	})
+	require.NoError(t, err)
+}
+
+func TestClientRenameDirectoryInvalidDirectory(t *testing.T) {
+	client := &Client{}
+
+	err := client.RenameDirectory(context.Background(), RenameDirectoryOptions{Source: "/", Destination: "dst"})
+	require.ErrorIs(t, err, ErrInvalidDirectory)
+
+	err = client.RenameDirectory(context.Background(), RenameDirectoryOptions{Source: "src", Destination: ""})
+	require.ErrorIs(t, err, ErrInvalidDirectory)
+}
+
+func TestClientRenameDirectoryWithoutDataLakeClient(t *testing.T) {
+	var (
+		ctrl  = gomock.NewController(t)
+		sAPI  = NewMockserviceAPI(ctrl)
+		cAPI  = NewMockcontainerAPI(ctrl)
+		pager = NewMockflatBlobsPager(ctrl)
+	)
+
+	// Without a data lake client, we shouldn't check whether the namespace is hierarchical
+	sAPI.EXPECT().NewContainerClient("container").Return(cAPI).Times(2)
+
+	cAPI.EXPECT().NewListBlobsFlatPager(gomock.Any()).Return(pager).Times(2)
+
+	pager.EXPECT().More().Return(false).Times(2)
+
+	client := &Client{serviceAPI: sAPI}
+
+	err := client.RenameDirectory(context.Background(), RenameDirectoryOptions{
+		Bucket:      "container",
+		Source:      "src",
+		Destination: "dst",
+	})
+	require.NoError(t, err)
+}
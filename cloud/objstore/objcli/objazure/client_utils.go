@@ -5,6 +5,9 @@ import (
 	"os"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
@@ -19,12 +22,135 @@ const (
 	azureEndpointSuffix = "endpointsuffix"
 )
 
+// Cloud represents an Azure cloud, the public cloud or a sovereign cloud, which determines where tokens are acquired
+// from and the suffix of the storage account endpoints.
+type Cloud struct {
+	// Configuration is the SDK configuration for the cloud, used when acquiring tokens.
+	Configuration cloud.Configuration
+
+	// EndpointSuffix is the suffix of the storage account endpoints e.g. 'core.windows.net'.
+	EndpointSuffix string
+}
+
+var (
+	// CloudPublic is the Azure public cloud.
+	CloudPublic = Cloud{Configuration: cloud.AzurePublic, EndpointSuffix: "core.windows.net"}
+
+	// CloudChina is the Azure China cloud.
+	CloudChina = Cloud{Configuration: cloud.AzureChina, EndpointSuffix: "core.chinacloudapi.cn"}
+
+	// CloudGovernment is the Azure US Government cloud.
+	CloudGovernment = Cloud{Configuration: cloud.AzureGovernment, EndpointSuffix: "core.usgovcloudapi.net"}
+)
+
+// CredentialChain represents the chain of credentials tried when authenticating using a token credential, where no
+// credential has been explicitly provided.
+type CredentialChain int
+
+const (
+	// CredentialChainEnvironment tries an environment credential followed by a managed identity, this is the chain
+	// used by 'NewTokenCredential'.
+	CredentialChainEnvironment CredentialChain = iota
+
+	// CredentialChainDefault uses the SDK 'DefaultAzureCredential' chain, which tries an environment credential,
+	// workload identity, managed identity then the Azure CLI.
+	CredentialChainDefault
+)
+
+// ServiceClientOptions encapsulates the options available when creating a service client using 'NewServiceClient'.
+type ServiceClientOptions struct {
+	// AccountName is the name of the storage account, used to build the endpoint when one isn't provided.
+	//
+	// NOTE: When omitted, the account name is read from the environment.
+	AccountName string
+
+	// Endpoint is the URL of the storage account e.g. 'https://account.blob.core.windows.net'.
+	Endpoint string
+
+	// Cloud is the cloud which the storage account belongs to, defaults to 'CloudPublic'.
+	Cloud *Cloud
+
+	// Credential is used to authenticate requests, allowing the use of any credential supported by the SDK e.g. a
+	// 'WorkloadIdentityCredential' or a 'ClientCertificateCredential'.
+	//
+	// NOTE: When omitted, a credential is created using the given chain.
+	Credential azcore.TokenCredential
+
+	// Chain is the chain of credentials used when no credential is provided, defaults to 'CredentialChainEnvironment'.
+	Chain CredentialChain
+
+	// TenantID is the tenant used by workload identity and the Azure CLI.
+	//
+	// NOTE: Only applicable when using 'CredentialChainDefault'.
+	TenantID string
+
+	// Options are used when creating the service client.
+	Options *service.ClientOptions
+}
+
+// defaults fills any missing attributes to a sane default.
+func (s *ServiceClientOptions) defaults() {
+	if s.Cloud == nil {
+		s.Cloud = &CloudPublic
+	}
+}
+
+// NewServiceClient returns a new service client, suitable for use with 'NewClient', which authenticates using a token
+// credential (OAuth) rather than an account key; this supports deployments where account keys are disabled.
+func NewServiceClient(opts ServiceClientOptions) (*service.Client, error) {
+	opts.defaults()
+
+	serviceURL, err := getServiceURL(opts.Endpoint, opts.AccountName, opts.Cloud.EndpointSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service URL: %w", err)
+	}
+
+	credential := opts.Credential
+
+	if credential == nil {
+		credential, err = newChainedCredential(opts)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token credential: %w", err)
+	}
+
+	var options service.ClientOptions
+
+	if opts.Options != nil {
+		options = *opts.Options
+	}
+
+	if options.Cloud.ActiveDirectoryAuthorityHost == "" {
+		options.Cloud = opts.Cloud.Configuration
+	}
+
+	client, err := service.NewClient(serviceURL, credential, &options)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	return client, nil
+}
+
+// newChainedCredential returns a token credential using the chain from the given options.
+func newChainedCredential(opts ServiceClientOptions) (azcore.TokenCredential, error) {
+	if opts.Chain != CredentialChainDefault {
+		return NewTokenCredential()
+	}
+
+	return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+		ClientOptions: azcore.ClientOptions{Cloud: opts.Cloud.Configuration},
+		TenantID:      opts.TenantID,
+	})
+}
+
 // GetServiceClient returns the Azure Service Client that facilitates all the necessary interactions with the Azure
 // blob storage.
 func GetServiceClient(accessKeyID, secretAccessKey, endpoint string, options *service.ClientOptions) (
 	*service.Client, error,
 ) {
-	serviceURL, err := getServiceURL(endpoint, accessKeyID)
+	serviceURL, err := getServiceURL(endpoint, accessKeyID, CloudPublic.EndpointSuffix)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service URL: %w", err)
 	}
@@ -94,8 +220,9 @@ func getServiceClientWithTokenCredential(
 	return client, nil
 }
 
-// getServiceURL returns the URL which should be used when communicating with the Azure storage service.
-func getServiceURL(endpoint, accessKeyID string) (string, error) {
+// getServiceURL returns the URL which should be used when communicating with the Azure storage service, the given
+// suffix is used unless it's overridden by the connection string.
+func getServiceURL(endpoint, accessKeyID, suffix string) (string, error) {
 	if endpoint != "" {
 		return endpoint, nil
 	}
@@ -111,7 +238,6 @@ func getServiceURL(endpoint, accessKeyID string) (string, error) {
 		return "", err // Purposefully not wrapped
 	}
 
-	suffix := "core.windows.net"
 	if values != nil && values[azureEndpointSuffix] != "" {
 		suffix = values[azureEndpointSuffix]
 	}
@@ -160,6 +160,7 @@ func TestAzureGetEndpoint(t *testing.T) {
 		name          string
 		endpoint      string
 		accessKeyID   string
+		suffix        string
 		env           map[string]string
 		expected      string
 		expectedError error
@@ -199,6 +200,18 @@ func TestAzureGetEndpoint(t *testing.T) {
 			env:      map[string]string{"AZURE_STORAGE_CONNECTION_STRING": "AccountName=account;EndpointSuffix=suffix"},
 			expected: "https://account.blob.suffix",
 		},
+		{
+			name:        "SovereignCloudSuffix",
+			accessKeyID: "account",
+			suffix:      CloudChina.EndpointSuffix,
+			expected:    "https://account.blob.core.chinacloudapi.cn",
+		},
+		{
+			name:     "OverrideSovereignCloudSuffixViaConnectionString",
+			suffix:   CloudChina.EndpointSuffix,
+			env:      map[string]string{"AZURE_STORAGE_CONNECTION_STRING": "AccountName=account;EndpointSuffix=suffix"},
+			expected: "https://account.blob.suffix",
+		},
 		{
 			name:     "EmptyOverrideSuffixViaConnectionString",
 			env:      map[string]string{"AZURE_STORAGE_CONNECTION_STRING": "AccountName=account;EndpointSuffix="},
@@ -214,7 +227,12 @@ func TestAzureGetEndpoint(t *testing.T) {
 				defer os.Unsetenv(key)
 			}
 
-			actual, err := getServiceURL(test.endpoint, test.accessKeyID)
+			suffix := test.suffix
+			if suffix == "" {
+				suffix = CloudPublic.EndpointSuffix
+			}
+
+			actual, err := getServiceURL(test.endpoint, test.accessKeyID, suffix)
 
 			if test.expectedError != nil {
 				require.ErrorIs(t, err, test.expectedError)
@@ -227,6 +245,54 @@ func TestAzureGetEndpoint(t *testing.T) {
 	}
 }
 
+func TestNewServiceClient(t *testing.T) {
+	type test struct {
+		name     string
+		opts     ServiceClientOptions
+		expected string
+	}
+
+	tests := []*test{
+		{
+			name:     "AccountName",
+			opts:     ServiceClientOptions{AccountName: "account", Credential: staticTokenCredential{}},
+			expected: "https://account.blob.core.windows.net",
+		},
+		{
+			name:     "Endpoint",
+			opts:     ServiceClientOptions{Endpoint: "https://endpoint/", Credential: staticTokenCredential{}},
+			expected: "https://endpoint/",
+		},
+		{
+			name: "SovereignCloud",
+			opts: ServiceClientOptions{
+				AccountName: "account",
+				Cloud:       &CloudGovernment,
+				Credential:  staticTokenCredential{},
+			},
+			expected: "https://account.blob.core.usgovcloudapi.net",
+		},
+		{
+			name:     "DefaultCredentialChain",
+			opts:     ServiceClientOptions{AccountName: "account", Chain: CredentialChainDefault},
+			expected: "https://account.blob.core.windows.net",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := NewServiceClient(test.opts)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, client.URL())
+		})
+	}
+}
+
+func TestNewServiceClientNoAccount(t *testing.T) {
+	_, err := NewServiceClient(ServiceClientOptions{Credential: staticTokenCredential{}})
+	require.ErrorIs(t, err, ErrFailedToDetermineAccountName)
+}
+
 func TestHandleCredsError(t *testing.T) {
 	type test struct {
 		name     string
@@ -1,4 +1,12 @@
 package objazure
 
-// PageSize is the default page size used by Azure.
-const PageSize = 5000
+const (
+	// PageSize is the default page size used by Azure.
+	PageSize = 5000
+
+	// MaxBlocks is the maximum number of uncommitted blocks which may be committed to a block blob in Azure.
+	MaxBlocks = 50_000
+
+	// MaxCopySize is the maximum size of a blob which may be copied synchronously using a single request in Azure.
+	MaxCopySize = 256 * 1000 * 1000
+)
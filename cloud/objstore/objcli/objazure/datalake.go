@@ -0,0 +1,111 @@
+package objazure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azdatalake/directory"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azdatalake/service"
+
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+const (
+	// resourceTypeDirectory is the resource type reported by Data Lake Storage for directories.
+	resourceTypeDirectory = "directory"
+
+	// metadataIsFolder is the metadata key used by Data Lake Storage to mark a blob as a directory.
+	metadataIsFolder = "hdi_isfolder"
+)
+
+// DataLakeClient is a client for the Azure Data Lake Storage Gen2 path API, which is only available for storage
+// accounts with a hierarchical namespace enabled. Unlike the blob API, directory deletes/renames are atomic.
+type DataLakeClient struct {
+	client *service.Client
+}
+
+// NewDataLakeClient returns a new client for the given Data Lake Storage endpoint (e.g.
+// https://<account>.dfs.core.windows.net) which authenticates using the given token credential.
+//
+// NOTE: Shared key credentials are not supported by the 'DataLakeClient', clients authenticated using a shared key
+// will continue to delete directories blob-by-blob.
+func NewDataLakeClient(
+	endpoint string, credential azcore.TokenCredential, options *policy.ClientOptions,
+) (*DataLakeClient, error) {
+	if credential == nil {
+		return nil, ErrDataLakeCredentialRequired
+	}
+
+	var serviceOptions *service.ClientOptions
+	if options != nil {
+		serviceOptions = &service.ClientOptions{ClientOptions: *options}
+	}
+
+	client, err := service.NewClient(endpoint, credential, serviceOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create service client: %w", err)
+	}
+
+	return &DataLakeClient{client: client}, nil
+}
+
+// GetDataLakeClient returns a 'DataLakeClient' for the same storage account as would be used by 'GetServiceClient',
+// authenticated using a 'TokenCredential'.
+func GetDataLakeClient(accessKeyID, endpoint string, options *policy.ClientOptions) (*DataLakeClient, error) {
+	serviceURL, err := getServiceURL(endpoint, accessKeyID, CloudPublic.EndpointSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service URL: %w", err)
+	}
+
+	credential, err := NewTokenCredential()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token credential: %w", err)
+	}
+
+	return NewDataLakeClient(strings.Replace(serviceURL, ".blob.", ".dfs.", 1), credential, options)
+}
+
+// IsDirectory returns a boolean indicating whether the given path exists, and is a directory.
+func (d *DataLakeClient) IsDirectory(ctx context.Context, fileSystem, path string) (bool, error) {
+	resp, err := d.directoryClient(fileSystem, path).GetProperties(ctx, nil)
+	if isPathNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err // Purposefully not wrapped
+	}
+
+	if ptr.From(resp.ResourceType) == resourceTypeDirectory {
+		return true, nil
+	}
+
+	for key, value := range resp.Metadata {
+		if strings.EqualFold(key, metadataIsFolder) && strings.EqualFold(ptr.From(value), "true") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// DeletePath recursively deletes the given path, for directories this is an atomic operation.
+func (d *DataLakeClient) DeletePath(ctx context.Context, fileSystem, path string) error {
+	_, err := d.directoryClient(fileSystem, path).Delete(ctx, nil)
+	return err
+}
+
+// RenamePath atomically renames the given source path to the destination, the destination must be in the same file
+// system.
+func (d *DataLakeClient) RenamePath(ctx context.Context, fileSystem, source, destination string) error {
+	_, err := d.directoryClient(fileSystem, source).Rename(ctx, destination, nil)
+	return err
+}
+
+// directoryClient returns a client for the given path in the given file system.
+func (d *DataLakeClient) directoryClient(fileSystem, path string) *directory.Client {
+	return d.client.NewFileSystemClient(fileSystem).NewDirectoryClient(path)
+}
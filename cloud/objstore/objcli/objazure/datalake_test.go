@@ -0,0 +1,171 @@
+package objazure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azdatalake/datalakeerror"
+	"github.com/stretchr/testify/require"
+)
+
+type staticTokenCredential struct{}
+
+func (staticTokenCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// testTransport redirects all requests to a test server, the SDK parses the account name from the endpoint so the
+// test server address can't be used directly.
+type testTransport struct {
+	server *httptest.Server
+}
+
+func (t testTransport) Do(req *http.Request) (*http.Response, error) {
+	address, _ := url.Parse(t.server.URL)
+
+	req.URL.Scheme = address.Scheme
+	req.URL.Host = address.Host
+
+	return t.server.Client().Do(req)
+}
+
+func newTestDataLakeClient(t *testing.T, handler http.HandlerFunc) *DataLakeClient {
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	options := &policy.ClientOptions{
+		Transport: testTransport{server: server},
+		Retry:     policy.RetryOptions{MaxRetries: -1},
+	}
+
+	client, err := NewDataLakeClient("https://account.dfs.core.windows.net/", staticTokenCredential{}, options)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestNewDataLakeClientCredentialRequired(t *testing.T) {
+	_, err := NewDataLakeClient("https://account.dfs.core.windows.net", nil, nil)
+	require.ErrorIs(t, err, ErrDataLakeCredentialRequired)
+}
+
+func TestDataLakeClientIsDirectory(t *testing.T) {
+	type test struct {
+		name     string
+		header   http.Header
+		expected bool
+	}
+
+	tests := []test{
+		{
+			name:     "Directory",
+			header:   http.Header{"X-Ms-Resource-Type": {"directory"}},
+			expected: true,
+		},
+		{
+			name:     "FolderMetadata",
+			header:   http.Header{"X-Ms-Meta-Hdi_isfolder": {"true"}},
+			expected: true,
+		},
+		{
+			name:   "File",
+			header: http.Header{"X-Ms-Resource-Type": {"file"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newTestDataLakeClient(t, func(writer http.ResponseWriter, request *http.Request) {
+				require.Equal(t, http.MethodHead, request.Method)
+				require.Equal(t, "/fs/path/to/a dir", request.URL.Path)
+				require.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+
+				for key, values := range test.header {
+					writer.Header()[key] = values
+				}
+
+				writer.WriteHeader(http.StatusOK)
+			})
+
+			directory, err := client.IsDirectory(context.Background(), "fs", "path/to/a dir")
+			require.NoError(t, err)
+			require.Equal(t, test.expected, directory)
+		})
+	}
+}
+
+func TestDataLakeClientIsDirectoryNotFound(t *testing.T) {
+	client := newTestDataLakeClient(t, func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("x-ms-error-code", string(bloberror.BlobNotFound))
+		writer.WriteHeader(http.StatusNotFound)
+	})
+
+	directory, err := client.IsDirectory(context.Background(), "fs", "dir")
+	require.NoError(t, err)
+	require.False(t, directory)
+}
+
+func TestDataLakeClientDeletePath(t *testing.T) {
+	var requests int
+
+	client := newTestDataLakeClient(t, func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+
+		require.Equal(t, http.MethodDelete, request.Method)
+		require.Equal(t, "/fs/path/to/a%20dir", request.URL.EscapedPath())
+		require.Equal(t, "true", request.URL.Query().Get("recursive"))
+		require.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+
+		// The first request should return a continuation token which must be used in the next request
+		if requests == 1 {
+			require.Empty(t, request.URL.Query().Get("continuation"))
+			writer.Header().Set("x-ms-continuation", "next")
+		} else {
+			require.Equal(t, "next", request.URL.Query().Get("continuation"))
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	require.NoError(t, client.DeletePath(context.Background(), "fs", "path/to/a dir"))
+	require.Equal(t, 2, requests)
+}
+
+func TestDataLakeClientDeletePathNotFound(t *testing.T) {
+	client := newTestDataLakeClient(t, func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("x-ms-error-code", string(datalakeerror.PathNotFound))
+		writer.WriteHeader(http.StatusNotFound)
+	})
+
+	err := client.DeletePath(context.Background(), "fs", "dir")
+	require.True(t, isPathNotFound(err))
+}
+
+func TestDataLakeClientRenamePath(t *testing.T) {
+	client := newTestDataLakeClient(t, func(writer http.ResponseWriter, request *http.Request) {
+		require.Equal(t, http.MethodPut, request.Method)
+		require.Equal(t, "/fs/dst/dir", request.URL.EscapedPath())
+		require.Equal(t, "/fs/src/dir", request.Header.Get("x-ms-rename-source"))
+
+		writer.WriteHeader(http.StatusCreated)
+	})
+
+	require.NoError(t, client.RenamePath(context.Background(), "fs", "src/dir", "dst/dir"))
+}
+
+func TestDataLakeClientRenamePathUnauthorized(t *testing.T) {
+	client := newTestDataLakeClient(t, func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("x-ms-error-code", string(bloberror.AuthorizationFailure))
+		writer.WriteHeader(http.StatusForbidden)
+	})
+
+	err := client.RenamePath(context.Background(), "fs", "src", "dst")
+	require.True(t, bloberror.HasCode(err, bloberror.AuthorizationFailure))
+}
@@ -5,3 +5,9 @@ import "errors"
 // ErrFailedToDetermineAccountName is returned in the event that we fail to determine the Azure account name using both
 // the static credentials or the environment.
 var ErrFailedToDetermineAccountName = errors.New("failed to determine account name")
+
+// ErrDataLakeCredentialRequired is returned when attempting to create a 'DataLakeClient' without a token credential.
+var ErrDataLakeCredentialRequired = errors.New("a token credential is required to create a data lake client")
+
+// ErrInvalidDirectory is returned when attempting to rename the root directory, or to rename a directory to the root.
+var ErrInvalidDirectory = errors.New("directory must not be empty or the root directory")
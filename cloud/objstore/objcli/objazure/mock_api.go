@@ -15,6 +15,7 @@ import (
 	blockblob "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
 	container "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	sas "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	service "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -41,6 +42,21 @@ func (m *MockserviceAPI) EXPECT() *MockserviceAPIMockRecorder {
 	return m.recorder
 }
 
+// GetAccountInfo mocks base method.
+func (m *MockserviceAPI) GetAccountInfo(ctx context.Context, o *service.GetAccountInfoOptions) (service.GetAccountInfoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountInfo", ctx, o)
+	ret0, _ := ret[0].(service.GetAccountInfoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountInfo indicates an expected call of GetAccountInfo.
+func (mr *MockserviceAPIMockRecorder) GetAccountInfo(ctx, o interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountInfo", reflect.TypeOf((*MockserviceAPI)(nil).GetAccountInfo), ctx, o)
+}
+
 // NewContainerClient mocks base method.
 func (m *MockserviceAPI) NewContainerClient(containerName string) containerAPI {
 	m.ctrl.T.Helper()
@@ -78,47 +94,77 @@ func (m *MockcontainerAPI) EXPECT() *MockcontainerAPIMockRecorder {
 	return m.recorder
 }
 
+// Create mocks base method.
+func (m *MockcontainerAPI) Create(ctx context.Context, options *container.CreateOptions) (container.CreateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, options)
+	ret0, _ := ret[0].(container.CreateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockcontainerAPIMockRecorder) Create(ctx, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockcontainerAPI)(nil).Create), ctx, options)
+}
+
+// Delete mocks base method.
+func (m *MockcontainerAPI) Delete(ctx context.Context, options *container.DeleteOptions) (container.DeleteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, options)
+	ret0, _ := ret[0].(container.DeleteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockcontainerAPIMockRecorder) Delete(ctx, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockcontainerAPI)(nil).Delete), ctx, options)
+}
+
 // NewBlobClient mocks base method.
-func (m *MockcontainerAPI) NewBlobClient(blobName string) blobAPI {
+func (m *MockcontainerAPI) NewBlobClient(name string) blobAPI {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewBlobClient", blobName)
+	ret := m.ctrl.Call(m, "NewBlobClient", name)
 	ret0, _ := ret[0].(blobAPI)
 	return ret0
 }
 
 // NewBlobClient indicates an expected call of NewBlobClient.
-func (mr *MockcontainerAPIMockRecorder) NewBlobClient(blobName interface{}) *gomock.Call {
+func (mr *MockcontainerAPIMockRecorder) NewBlobClient(name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewBlobClient", reflect.TypeOf((*MockcontainerAPI)(nil).NewBlobClient), blobName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewBlobClient", reflect.TypeOf((*MockcontainerAPI)(nil).NewBlobClient), name)
 }
 
 // NewBlockBlobClient mocks base method.
-func (m *MockcontainerAPI) NewBlockBlobClient(blobName string) blockBlobAPI {
+func (m *MockcontainerAPI) NewBlockBlobClient(name string) blockBlobAPI {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewBlockBlobClient", blobName)
+	ret := m.ctrl.Call(m, "NewBlockBlobClient", name)
 	ret0, _ := ret[0].(blockBlobAPI)
 	return ret0
 }
 
 // NewBlockBlobClient indicates an expected call of NewBlockBlobClient.
-func (mr *MockcontainerAPIMockRecorder) NewBlockBlobClient(blobName interface{}) *gomock.Call {
+func (mr *MockcontainerAPIMockRecorder) NewBlockBlobClient(name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewBlockBlobClient", reflect.TypeOf((*MockcontainerAPI)(nil).NewBlockBlobClient), blobName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewBlockBlobClient", reflect.TypeOf((*MockcontainerAPI)(nil).NewBlockBlobClient), name)
 }
 
 // NewBlockBlobVersionClient mocks base method.
-func (m *MockcontainerAPI) NewBlockBlobVersionClient(blobName, versionID string) (blockBlobAPI, error) {
+func (m *MockcontainerAPI) NewBlockBlobVersionClient(name, version string) (blockBlobAPI, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewBlockBlobVersionClient", blobName, versionID)
+	ret := m.ctrl.Call(m, "NewBlockBlobVersionClient", name, version)
 	ret0, _ := ret[0].(blockBlobAPI)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // NewBlockBlobVersionClient indicates an expected call of NewBlockBlobVersionClient.
-func (mr *MockcontainerAPIMockRecorder) NewBlockBlobVersionClient(blobName, versionID interface{}) *gomock.Call {
+func (mr *MockcontainerAPIMockRecorder) NewBlockBlobVersionClient(name, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewBlockBlobVersionClient", reflect.TypeOf((*MockcontainerAPI)(nil).NewBlockBlobVersionClient), blobName, versionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewBlockBlobVersionClient", reflect.TypeOf((*MockcontainerAPI)(nil).NewBlockBlobVersionClient), name, version)
 }
 
 // NewListBlobsFlatPager mocks base method.
@@ -202,6 +248,21 @@ func (mr *MockblobAPIMockRecorder) GetSASURL(permissions, expiry, options interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSASURL", reflect.TypeOf((*MockblobAPI)(nil).GetSASURL), permissions, expiry, options)
 }
 
+// Undelete mocks base method.
+func (m *MockblobAPI) Undelete(ctx context.Context, o *blob.UndeleteOptions) (blob.UndeleteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undelete", ctx, o)
+	ret0, _ := ret[0].(blob.UndeleteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undelete indicates an expected call of Undelete.
+func (mr *MockblobAPIMockRecorder) Undelete(ctx, o interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockblobAPI)(nil).Undelete), ctx, o)
+}
+
 // MockflatBlobsPager is a mock of flatBlobsPager interface.
 type MockflatBlobsPager struct {
 	ctrl     *gomock.Controller
@@ -404,6 +465,36 @@ func (mr *MockblockBlobAPIMockRecorder) GetProperties(ctx, options interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProperties", reflect.TypeOf((*MockblockBlobAPI)(nil).GetProperties), ctx, options)
 }
 
+// GetTags mocks base method.
+func (m *MockblockBlobAPI) GetTags(ctx context.Context, o *blob.GetTagsOptions) (blob.GetTagsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx, o)
+	ret0, _ := ret[0].(blob.GetTagsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockblockBlobAPIMockRecorder) GetTags(ctx, o interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockblockBlobAPI)(nil).GetTags), ctx, o)
+}
+
+// SetTags mocks base method.
+func (m *MockblockBlobAPI) SetTags(ctx context.Context, tags map[string]string, o *blob.SetTagsOptions) (blob.SetTagsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTags", ctx, tags, o)
+	ret0, _ := ret[0].(blob.SetTagsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTags indicates an expected call of SetTags.
+func (mr *MockblockBlobAPIMockRecorder) SetTags(ctx, tags, o interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTags", reflect.TypeOf((*MockblockBlobAPI)(nil).SetTags), ctx, tags, o)
+}
+
 // StageBlock mocks base method.
 func (m *MockblockBlobAPI) StageBlock(ctx context.Context, base64BlockID string, body io.ReadSeekCloser, options *blockblob.StageBlockOptions) (blockblob.StageBlockResponse, error) {
 	m.ctrl.T.Helper()
@@ -462,3 +553,69 @@ func (mr *MockblockBlobAPIMockRecorder) Upload(ctx, body, options interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockblockBlobAPI)(nil).Upload), ctx, body, options)
 }
+
+// MockpathAPI is a mock of pathAPI interface.
+type MockpathAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockpathAPIMockRecorder
+}
+
+// MockpathAPIMockRecorder is the mock recorder for MockpathAPI.
+type MockpathAPIMockRecorder struct {
+	mock *MockpathAPI
+}
+
+// NewMockpathAPI creates a new mock instance.
+func NewMockpathAPI(ctrl *gomock.Controller) *MockpathAPI {
+	mock := &MockpathAPI{ctrl: ctrl}
+	mock.recorder = &MockpathAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpathAPI) EXPECT() *MockpathAPIMockRecorder {
+	return m.recorder
+}
+
+// DeletePath mocks base method.
+func (m *MockpathAPI) DeletePath(ctx context.Context, fileSystem, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePath", ctx, fileSystem, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePath indicates an expected call of DeletePath.
+func (mr *MockpathAPIMockRecorder) DeletePath(ctx, fileSystem, path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePath", reflect.TypeOf((*MockpathAPI)(nil).DeletePath), ctx, fileSystem, path)
+}
+
+// IsDirectory mocks base method.
+func (m *MockpathAPI) IsDirectory(ctx context.Context, fileSystem, path string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDirectory", ctx, fileSystem, path)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDirectory indicates an expected call of IsDirectory.
+func (mr *MockpathAPIMockRecorder) IsDirectory(ctx, fileSystem, path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDirectory", reflect.TypeOf((*MockpathAPI)(nil).IsDirectory), ctx, fileSystem, path)
+}
+
+// RenamePath mocks base method.
+func (m *MockpathAPI) RenamePath(ctx context.Context, fileSystem, source, destination string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenamePath", ctx, fileSystem, source, destination)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenamePath indicates an expected call of RenamePath.
+func (mr *MockpathAPIMockRecorder) RenamePath(ctx, fileSystem, source, destination interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenamePath", reflect.TypeOf((*MockpathAPI)(nil).RenamePath), ctx, fileSystem, source, destination)
+}
@@ -1,22 +1,53 @@
 package objazure
 
 import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azdatalake/datalakeerror"
 
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/types/v2/ptr"
 )
 
+// fileSystemNotFound is the code returned by the Data Lake Storage REST API when a file system doesn't exist, the SDK
+// only converts 'bloberror.ContainerNotFound' to 'datalakeerror.FileSystemNotFound'.
+const fileSystemNotFound bloberror.Code = "FilesystemNotFound"
+
 // handleError converts an error relating accessing an object via its key into a user friendly error where possible.
 func handleError(bucket, key string, err error) error {
+	if isCredentialsExpired(err) {
+		return objerr.ErrCredentialsExpired
+	}
+
 	if bloberror.HasCode(err, bloberror.AuthenticationFailed) {
 		return objerr.ErrUnauthenticated
 	}
 
-	if bloberror.HasCode(err, bloberror.AuthorizationFailure) {
+	if bloberror.HasCode(
+		err,
+		bloberror.AuthorizationFailure,
+		bloberror.AuthorizationPermissionMismatch,
+		bloberror.InsufficientAccountPermissions,
+	) {
 		return objerr.ErrUnauthorized
 	}
 
-	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+	// Azure reports that account ingress/egress limits have been exceeded using 'ServerBusy'
+	if bloberror.HasCode(err, bloberror.ServerBusy) {
+		return objerr.ErrThrottled
+	}
+
+	if bloberror.HasCode(err, bloberror.MD5Mismatch, bloberror.CRC64Mismatch) {
+		return objerr.ErrChecksumMismatch
+	}
+
+	if bloberror.HasCode(err, bloberror.BlobNotFound) || isPathNotFound(err) {
 		// This shouldn't trigger but may aid in debugging in the future
 		if key == "" {
 			key = "<empty blob name>"
@@ -25,7 +56,8 @@ func handleError(bucket, key string, err error) error {
 		return &objerr.NotFoundError{Type: "blob", Name: key}
 	}
 
-	if bloberror.HasCode(err, bloberror.ContainerNotFound) {
+	if bloberror.HasCode(err, bloberror.ContainerNotFound, fileSystemNotFound) ||
+		datalakeerror.HasCode(err, datalakeerror.FileSystemNotFound) {
 		// This shouldn't trigger but may aid in debugging in the future
 		if bucket == "" {
 			bucket = "<empty container name>"
@@ -43,10 +75,86 @@ func handleError(bucket, key string, err error) error {
 		return &objerr.ErrArchiveStorage{Key: key}
 	}
 
+	// Conditional reads are rejected with a '304 Not Modified', which has no body and therefore no error code
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotModified {
+		return objerr.ErrNotModified
+	}
+
 	return objerr.HandleError(err)
 }
 
+// isCredentialsExpired returns a boolean indicating whether the given error was returned because the token/SAS used to
+// authenticate the request (or the copy source) has expired, Azure doesn't use a distinct code so we check the message.
+func isCredentialsExpired(err error) bool {
+	if !bloberror.HasCode(
+		err,
+		bloberror.AuthenticationFailed,
+		bloberror.InvalidAuthenticationInfo,
+		bloberror.CannotVerifyCopySource,
+	) {
+		return false
+	}
+
+	message := strings.ToLower(err.Error())
+
+	return strings.Contains(message, "expired") || strings.Contains(message, "not valid in the specified time frame")
+}
+
 // isKeyNotFound returns a boolean indicating whether the given error is a 'ServiceCodeBlobNotFound' error.
 func isKeyNotFound(err error) bool {
 	return bloberror.HasCode(err, bloberror.BlobNotFound)
 }
+
+// isPathNotFound returns a boolean indicating whether the given error is a Data Lake Storage 'PathNotFound' error.
+func isPathNotFound(err error) bool {
+	return datalakeerror.HasCode(err, datalakeerror.PathNotFound)
+}
+
+// toBlobMetadata converts the given user-defined metadata into the format expected by the Azure SDK.
+func toBlobMetadata(metadata map[string]string) map[string]*string {
+	converted := make(map[string]*string, len(metadata))
+
+	for key, value := range metadata {
+		converted[key] = ptr.To(value)
+	}
+
+	return converted
+}
+
+// toListBlobsInclude converts the given list include options into those used by the Azure SDK.
+func toListBlobsInclude(include objcli.ListInclude) container.ListBlobsInclude {
+	return container.ListBlobsInclude{
+		Metadata:  include.Metadata,
+		Tags:      include.Tags,
+		Versions:  include.Versions,
+		Snapshots: include.Snapshots,
+	}
+}
+
+// fromBlobTags converts the given blob tags returned when listing blobs, returning <nil> if no tags were returned.
+func fromBlobTags(tags *container.BlobTags) map[string]string {
+	if tags == nil || len(tags.BlobTagSet) == 0 {
+		return nil
+	}
+
+	converted := make(map[string]string, len(tags.BlobTagSet))
+
+	for _, tag := range tags.BlobTagSet {
+		converted[ptr.From(tag.Key)] = ptr.From(tag.Value)
+	}
+
+	return converted
+}
+
+// fromBlobMetadata converts the given blob metadata, keys are lowercased since they're returned using the canonical
+// HTTP header casing (e.g. 'Format_version').
+func fromBlobMetadata(metadata map[string]*string) map[string]string {
+	converted := make(map[string]string, len(metadata))
+
+	for key, value := range metadata {
+		converted[key] = ptr.From(value)
+	}
+
+	return objcli.NormalizeMetadata(converted)
+}
@@ -1,21 +1,42 @@
 package objazure
 
 import (
+	"io"
 	"net"
+	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/types/v2/ptr"
 )
 
 func respError(code bloberror.Code) *azcore.ResponseError {
 	return &azcore.ResponseError{ErrorCode: string(code)}
 }
 
+// expiredError returns an error with the given code, whose message indicates that the SAS used has expired.
+func expiredError(code bloberror.Code) error {
+	body := "<Error><Code>" + string(code) + "</Code><Message>Signature not valid in the specified time frame: " +
+		"Start [Mon, 01 Jan 2024 00:00:00 GMT] - Expiry [Mon, 01 Jan 2024 01:00:00 GMT]</Message></Error>"
+
+	return runtime.NewResponseError(&http.Response{
+		StatusCode: http.StatusForbidden,
+		Header:     http.Header{"X-Ms-Error-Code": []string{string(code)}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{}},
+	})
+}
+
 func TestHandleError(t *testing.T) {
 	err := handleError("", "", &net.DNSError{IsNotFound: true})
 	require.ErrorIs(t, err, objerr.ErrEndpointResolutionFailed)
@@ -32,12 +53,30 @@ func TestHandleError(t *testing.T) {
 	err = handleError("container1", "blob1", respError(bloberror.BlobBeingRehydrated))
 	require.Error(t, err)
 
+	err = handleError("container1", "blob1", &azcore.ResponseError{StatusCode: http.StatusNotModified})
+	require.ErrorIs(t, err, objerr.ErrNotModified)
+
 	err = handleError("container1", "blob1", respError(bloberror.AuthenticationFailed))
 	require.ErrorIs(t, err, objerr.ErrUnauthenticated)
 
+	err = handleError("container1", "blob1", expiredError(bloberror.AuthenticationFailed))
+	require.ErrorIs(t, err, objerr.ErrCredentialsExpired)
+
+	err = handleError("container1", "blob1", expiredError(bloberror.CannotVerifyCopySource))
+	require.ErrorIs(t, err, objerr.ErrCredentialsExpired)
+
 	err = handleError("container1", "blob1", respError(bloberror.AuthorizationFailure))
 	require.ErrorIs(t, err, objerr.ErrUnauthorized)
 
+	err = handleError("container1", "blob1", respError(bloberror.AuthorizationPermissionMismatch))
+	require.ErrorIs(t, err, objerr.ErrForbidden)
+
+	err = handleError("container1", "blob1", respError(bloberror.ServerBusy))
+	require.ErrorIs(t, err, objerr.ErrThrottled)
+
+	err = handleError("container1", "blob1", respError(bloberror.MD5Mismatch))
+	require.ErrorIs(t, err, objerr.ErrChecksumMismatch)
+
 	err = handleError("container1", "blob1", respError(bloberror.BlobNotFound))
 	require.ErrorAs(t, err, &notFound)
 	require.Equal(t, "blob", notFound.Type)
@@ -67,3 +106,38 @@ func TestIsKeyNotFound(t *testing.T) {
 	require.False(t, isKeyNotFound(assert.AnError))
 	require.True(t, isKeyNotFound(respError(bloberror.BlobNotFound)))
 }
+
+func TestBlobMetadata(t *testing.T) {
+	converted := toBlobMetadata(map[string]string{"format_version": "2"})
+	require.Equal(t, map[string]*string{"format_version": ptr.To("2")}, converted)
+
+	// Azure returns metadata keys using the canonical HTTP header casing
+	require.Equal(
+		t,
+		map[string]string{"format_version": "2"},
+		fromBlobMetadata(map[string]*string{"Format_version": ptr.To("2")}),
+	)
+
+	require.Nil(t, fromBlobMetadata(nil))
+}
+
+func TestFromBlobTags(t *testing.T) {
+	require.Nil(t, fromBlobTags(nil))
+	require.Nil(t, fromBlobTags(&container.BlobTags{}))
+
+	tags := &container.BlobTags{
+		BlobTagSet: []*container.BlobTag{{Key: ptr.To("key"), Value: ptr.To("value")}},
+	}
+
+	require.Equal(t, map[string]string{"key": "value"}, fromBlobTags(tags))
+}
+
+func TestToListBlobsInclude(t *testing.T) {
+	require.Equal(t, container.ListBlobsInclude{}, toListBlobsInclude(objcli.ListInclude{}))
+
+	require.Equal(
+		t,
+		container.ListBlobsInclude{Metadata: true, Tags: true, Versions: true, Snapshots: true},
+		toListBlobsInclude(objcli.ListInclude{Metadata: true, Tags: true, Versions: true, Snapshots: true}),
+	)
+}
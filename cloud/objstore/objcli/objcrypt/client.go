@@ -0,0 +1,601 @@
+// Package objcrypt provides an implementation of 'objcli.Client' which transparently encrypts/decrypts objects stored
+// using another client, this may be used where server side encryption is unavailable or inadequate.
+package objcrypt
+
+import (
+	"context"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// Client implements the 'objcli.Client' interface, encrypting objects using envelope encryption. Each object is
+// encrypted using AES-GCM with a unique data encryption key, which is itself wrapped by the 'KeyProvider' and stored in
+// the metadata of the object.
+//
+// NOTE: Compression is applied to the plaintext, prior to it being encrypted. The use of encryption has the following
+// implications:
+//  1. Sizes returned by 'IterateObjects' are the encrypted sizes, 'GetObjectAttrs' should be used to get the size of
+//     the decrypted object.
+//  2. 'GetObject' requires an additional request to read the metadata of the object, and 'GetObjectAttrs'/ranged
+//     'GetObject' requests require additional requests to read the header of each segment.
+//  3. Upload ids returned by 'ListMultipartUploads' may only be used to abort the upload, as they don't include the
+//     data encryption key used to encrypt the parts.
+//  4. 'UploadPartCopy' is unsupported, encrypted objects may still be copied in their entirety using 'CopyObject'.
+type Client struct {
+	client   objcli.Client
+	provider KeyProvider
+}
+
+var _ objcli.Client = (*Client)(nil)
+
+// ClientOptions encapsulates the options for creating a new encrypting client.
+type ClientOptions struct {
+	// Client is the client used to store the encrypted objects.
+	//
+	// NOTE: Required, and must support object metadata.
+	Client objcli.Client
+
+	// KeyProvider is used to wrap/unwrap the data encryption keys.
+	//
+	// NOTE: Required
+	KeyProvider KeyProvider
+}
+
+// NewClient returns a new client which encrypts objects before storing them using the given client.
+func NewClient(options ClientOptions) *Client {
+	return &Client{client: options.Client, provider: options.KeyProvider}
+}
+
+func (c *Client) Provider() objval.Provider {
+	return c.client.Provider()
+}
+
+// Capabilities returns the capabilities of the underlying client, with the exception that objects may not be composed
+// server-side because each part must be encrypted.
+func (c *Client) Capabilities() objval.Capabilities {
+	capabilities := c.client.Capabilities()
+	capabilities.SupportsCompose = false
+
+	return capabilities
+}
+
+// GetObject returns the decrypted object, where 'Decompress' is provided the decrypted object is decompressed.
+//
+// NOTE: The metadata of the object is read prior to reading the object, if the object is replaced in between, reading
+// the body will fail authentication.
+func (c *Client) GetObject(ctx context.Context, opts objcli.GetObjectOptions) (*objval.Object, error) {
+	if err := opts.ByteRange.Valid(false); err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	attrs, err := c.client.GetObjectAttrs(ctx, objcli.GetObjectAttrsOptions{Bucket: opts.Bucket, Key: opts.Key})
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	// The metadata has already been read, so conditional reads are handled here
+	if opts.NotModified(attrs) {
+		return nil, objerr.ErrNotModified
+	}
+
+	aead, err := unwrapObjectKey(ctx, c.provider, attrs.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var object *objval.Object
+
+	if opts.ByteRange != nil {
+		object, err = c.getObjectRange(ctx, opts, attrs, aead)
+	} else {
+		object, err = c.getObjectBody(ctx, opts, aead)
+	}
+
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	if !opts.Decompress {
+		return object, nil
+	}
+
+	err = objcli.DecompressObject(object, attrs.Metadata[metadataCompression])
+	if err != nil {
+		object.Body.Close()
+		return nil, err // Purposefully not wrapped
+	}
+
+	return object, nil
+}
+
+// getObjectBody returns the whole decrypted object.
+func (c *Client) getObjectBody(
+	ctx context.Context,
+	opts objcli.GetObjectOptions,
+	aead cipher.AEAD,
+) (*objval.Object, error) {
+	object, err := c.client.GetObject(ctx, objcli.GetObjectOptions{
+		Bucket:           opts.Bucket,
+		Key:              opts.Key,
+		BandwidthLimiter: opts.BandwidthLimiter,
+	})
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	// The decrypted size isn't known until the object has been read
+	object.Size = nil
+	object.Body = newDecryptingReader(aead, object.Body)
+
+	return object, nil
+}
+
+// getObjectRange returns the requested byte range of the decrypted object, only downloading the chunks which contain
+// the requested data.
+func (c *Client) getObjectRange(
+	ctx context.Context,
+	opts objcli.GetObjectOptions,
+	attrs *objval.ObjectAttrs,
+	aead cipher.AEAD,
+) (*objval.Object, error) {
+	segments, err := c.layout(ctx, opts.Bucket, opts.Key, ptr.From(attrs.Size), aead)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	var total int64
+	for _, located := range segments {
+		total += located.size
+	}
+
+	start, end := opts.ByteRange.Start, opts.ByteRange.End
+	if end == 0 || end >= total {
+		end = total - 1
+	}
+
+	if start > end && !(start == 0 && total == 0) {
+		return nil, &objval.InvalidByteRangeError{ByteRange: opts.ByteRange}
+	}
+
+	var (
+		readers = make([]func() (io.ReadCloser, error), 0, len(segments))
+		offset  int64
+	)
+
+	for _, located := range segments {
+		lo, hi := max(start, offset)-offset, min(end, offset+located.size-1)-offset
+
+		if lo <= hi {
+			readers = append(readers, c.segmentRangeReader(ctx, opts, located, lo, hi))
+		}
+
+		offset += located.size
+	}
+
+	object := &objval.Object{
+		ObjectAttrs: *attrs,
+		Body:        &multiReader{readers: readers},
+	}
+
+	object.Size = ptr.To(max(0, end-start+1))
+	object.Metadata = withoutMetadata(maps.Clone(attrs.Metadata))
+
+	return object, nil
+}
+
+// segmentRangeReader returns a function which opens a reader for the given range of decrypted data (relative to the
+// start of the segment) from the given segment.
+func (c *Client) segmentRangeReader(
+	ctx context.Context,
+	opts objcli.GetObjectOptions,
+	located locatedSegment,
+	lo, hi int64,
+) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		var (
+			first = lo / ChunkSize
+			last  = hi / ChunkSize
+			base  = located.offset + headerSize
+		)
+
+		object, err := c.client.GetObject(ctx, objcli.GetObjectOptions{
+			Bucket: opts.Bucket,
+			Key:    opts.Key,
+			ByteRange: &objval.ByteRange{
+				Start: base + first*(ChunkSize+tagSize),
+				End:   base + last*(ChunkSize+tagSize) + located.chunkSize(last) + tagSize - 1,
+			},
+			BandwidthLimiter: opts.BandwidthLimiter,
+		})
+		if err != nil {
+			return nil, err // Purposefully not wrapped
+		}
+
+		reader := newChunkReader(located.segment, object.Body, first, last+1)
+
+		_, err = io.CopyN(io.Discard, reader, lo-first*ChunkSize)
+		if err != nil {
+			_ = reader.Close()
+			return nil, fmt.Errorf("failed to skip to start of range: %w", err)
+		}
+
+		return readCloser{Reader: io.LimitReader(reader, hi-lo+1), Closer: reader}, nil
+	}
+}
+
+// locatedSegment is a segment, and its offset in the encrypted object.
+type locatedSegment struct {
+	*segment
+	offset int64
+}
+
+// layout returns the segments which make up the given object, of the given encrypted size, by reading each of their
+// headers.
+func (c *Client) layout(
+	ctx context.Context,
+	bucket, key string,
+	total int64,
+	aead cipher.AEAD,
+) ([]locatedSegment, error) {
+	segments := make([]locatedSegment, 0, 1)
+
+	for offset := int64(0); offset < total; {
+		if offset+headerSize > total {
+			return nil, ErrTruncated
+		}
+
+		data, err := c.readRange(ctx, bucket, key, offset, offset+headerSize-1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read segment header at offset %d: %w", offset, err)
+		}
+
+		seg, err := decodeSegmentHeader(aead, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode segment header at offset %d: %w", offset, err)
+		}
+
+		if offset+seg.encryptedSize() > total {
+			return nil, ErrTruncated
+		}
+
+		segments = append(segments, locatedSegment{segment: seg, offset: offset})
+
+		offset += seg.encryptedSize()
+	}
+
+	return segments, nil
+}
+
+// readRange reads the given (inclusive) byte range of the encrypted object.
+func (c *Client) readRange(ctx context.Context, bucket, key string, start, end int64) ([]byte, error) {
+	object, err := c.client.GetObject(ctx, objcli.GetObjectOptions{
+		Bucket:    bucket,
+		Key:       key,
+		ByteRange: &objval.ByteRange{Start: start, End: end},
+	})
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+	defer object.Body.Close()
+
+	return io.ReadAll(object.Body)
+}
+
+// GetObjectAttrs returns the attributes of the object, where the size is the size of the decrypted object (prior to
+// decompression), and the metadata excludes that used for encryption.
+func (c *Client) GetObjectAttrs(ctx context.Context, opts objcli.GetObjectAttrsOptions) (*objval.ObjectAttrs, error) {
+	attrs, err := c.client.GetObjectAttrs(ctx, opts)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	aead, err := unwrapObjectKey(ctx, c.provider, attrs.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := c.layout(ctx, opts.Bucket, opts.Key, ptr.From(attrs.Size), aead)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	var size int64
+	for _, located := range segments {
+		size += located.size
+	}
+
+	attrs.Size = ptr.To(size)
+	attrs.Metadata = withoutMetadata(attrs.Metadata)
+
+	return attrs, nil
+}
+
+// PutObject compresses (where requested) and encrypts the body, before uploading it using a new data encryption key.
+func (c *Client) PutObject(ctx context.Context, opts objcli.PutObjectOptions) error {
+	aead, wrapped, err := newKey(ctx, c.provider)
+	if err != nil {
+		return err
+	}
+
+	body, err := objcli.CompressBody(opts.Body, opts.Compress)
+	if err != nil {
+		return err // Purposefully not wrapped
+	}
+
+	encrypted, err := newEncryptingReader(aead, body)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt body: %w", err)
+	}
+
+	opts.Body = encrypted
+	opts.Metadata = withMetadata(opts.Metadata, wrapped, opts.Compress)
+
+	// The ciphertext is incompressible, and the content encoding would indicate that the encrypted data is compressed
+	opts.Compress = objcli.CompressionNone
+
+	return c.client.PutObject(ctx, opts)
+}
+
+func (c *Client) GetObjectTags(ctx context.Context, opts objcli.GetObjectTagsOptions) (map[string]string, error) {
+	return c.client.GetObjectTags(ctx, opts)
+}
+
+func (c *Client) PutObjectTags(ctx context.Context, opts objcli.PutObjectTagsOptions) error {
+	return c.client.PutObjectTags(ctx, opts)
+}
+
+func (c *Client) DeleteObjectTags(ctx context.Context, opts objcli.DeleteObjectTagsOptions) error {
+	return c.client.DeleteObjectTags(ctx, opts)
+}
+
+func (c *Client) CopyObject(ctx context.Context, opts objcli.CopyObjectOptions) error {
+	return c.client.CopyObject(ctx, opts)
+}
+
+// AppendToObject appends the provided data to the object with the given key, the data is compressed using the same
+// compression as the existing object (gzip members concatenate) and encrypted as a new segment using its data
+// encryption key.
+func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectOptions) error {
+	attrs, err := c.client.GetObjectAttrs(ctx, objcli.GetObjectAttrsOptions{Bucket: opts.Bucket, Key: opts.Key})
+
+	// As defined by the 'Client' interface, if the given object does not exist, we create it
+	if objerr.IsNotFoundError(err) {
+		return c.PutObject(ctx, objcli.PutObjectOptions{
+			Bucket:           opts.Bucket,
+			Key:              opts.Key,
+			Body:             opts.Body,
+			BandwidthLimiter: opts.BandwidthLimiter,
+		})
+	}
+
+	if err != nil {
+		return err // Purposefully not wrapped
+	}
+
+	aead, err := unwrapObjectKey(ctx, c.provider, attrs.Metadata)
+	if err != nil {
+		return err
+	}
+
+	body, err := objcli.CompressBody(opts.Body, objcli.Compression(attrs.Metadata[metadataCompression]))
+	if err != nil {
+		return err // Purposefully not wrapped
+	}
+
+	encrypted, err := newEncryptingReader(aead, body)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt body: %w", err)
+	}
+
+	opts.Body = encrypted
+
+	return c.client.AppendToObject(ctx, opts)
+}
+
+func (c *Client) DeleteObjects(ctx context.Context, opts objcli.DeleteObjectsOptions) error {
+	return c.client.DeleteObjects(ctx, opts)
+}
+
+func (c *Client) DeleteDirectory(ctx context.Context, opts objcli.DeleteDirectoryOptions) error {
+	return c.client.DeleteDirectory(ctx, opts)
+}
+
+// IterateObjects iterates through the objects in the bucket.
+//
+// NOTE: The sizes of the objects are the encrypted sizes.
+func (c *Client) IterateObjects(ctx context.Context, opts objcli.IterateObjectsOptions) error {
+	return c.client.IterateObjects(ctx, opts)
+}
+
+// ListDeletedObjects returns the deleted objects from the underlying client.
+//
+// NOTE: The size of the returned objects is the encrypted size.
+func (c *Client) ListDeletedObjects(
+	ctx context.Context,
+	opts objcli.ListDeletedObjectsOptions,
+) ([]*objval.DeletedObject, error) {
+	return c.client.ListDeletedObjects(ctx, opts)
+}
+
+func (c *Client) UndeleteObject(ctx context.Context, opts objcli.UndeleteObjectOptions) error {
+	return c.client.UndeleteObject(ctx, opts)
+}
+
+// CreateMultipartUpload creates a multipart upload using a new data encryption key, the returned upload id includes the
+// wrapped key so that it's used to encrypt each part.
+func (c *Client) CreateMultipartUpload(ctx context.Context, opts objcli.CreateMultipartUploadOptions) (string, error) {
+	_, wrapped, err := newKey(ctx, c.provider)
+	if err != nil {
+		return "", err
+	}
+
+	opts.Metadata = withMetadata(opts.Metadata, wrapped, objcli.CompressionNone)
+
+	id, err := c.client.CreateMultipartUpload(ctx, opts)
+	if err != nil {
+		return "", err // Purposefully not wrapped
+	}
+
+	return encodeUploadID(wrapped, id), nil
+}
+
+// ListParts lists the parts of the given upload.
+//
+// NOTE: The size of the returned parts is the encrypted size.
+func (c *Client) ListParts(ctx context.Context, opts objcli.ListPartsOptions) ([]objval.Part, error) {
+	_, opts.UploadID = decodeUploadID(opts.UploadID)
+
+	return c.client.ListParts(ctx, opts)
+}
+
+// UploadPart encrypts and uploads the given part, each part is encrypted as a separate segment.
+//
+// NOTE: The size of the returned part is the encrypted size.
+func (c *Client) UploadPart(ctx context.Context, opts objcli.UploadPartOptions) (objval.Part, error) {
+	wrapped, id := decodeUploadID(opts.UploadID)
+	if wrapped == "" {
+		return objval.Part{}, ErrInvalidUploadID
+	}
+
+	aead, err := unwrapKey(ctx, c.provider, wrapped)
+	if err != nil {
+		return objval.Part{}, err
+	}
+
+	body, err := newEncryptingReader(aead, opts.Body)
+	if err != nil {
+		return objval.Part{}, fmt.Errorf("failed to encrypt body: %w", err)
+	}
+
+	opts.UploadID, opts.Body = id, body
+
+	return c.client.UploadPart(ctx, opts)
+}
+
+// UploadPartCopy is unsupported, byte ranges of the decrypted object don't map onto whole segments of the encrypted
+// object.
+func (c *Client) UploadPartCopy(_ context.Context, _ objcli.UploadPartCopyOptions) (objval.Part, error) {
+	return objval.Part{}, objerr.ErrUnsupportedOperation
+}
+
+// CompleteMultipartUpload completes the given upload, storing the wrapped data encryption key in the metadata of the
+// completed object.
+func (c *Client) CompleteMultipartUpload(ctx context.Context, opts objcli.CompleteMultipartUploadOptions) error {
+	wrapped, id := decodeUploadID(opts.UploadID)
+	if wrapped == "" {
+		return ErrInvalidUploadID
+	}
+
+	opts.UploadID = id
+	opts.Metadata = withMetadata(opts.Metadata, wrapped, objcli.CompressionNone)
+
+	return c.client.CompleteMultipartUpload(ctx, opts)
+}
+
+func (c *Client) AbortMultipartUpload(ctx context.Context, opts objcli.AbortMultipartUploadOptions) error {
+	_, opts.UploadID = decodeUploadID(opts.UploadID)
+
+	return c.client.AbortMultipartUpload(ctx, opts)
+}
+
+// ListMultipartUploads lists the uploads from the underlying client.
+//
+// NOTE: The returned upload ids don't include the data encryption key, so may only be used to abort the uploads.
+func (c *Client) ListMultipartUploads(
+	ctx context.Context,
+	opts objcli.ListMultipartUploadsOptions,
+) ([]objval.MultipartUpload, error) {
+	return c.client.ListMultipartUploads(ctx, opts)
+}
+
+func (c *Client) CreateBucket(ctx context.Context, opts objcli.CreateBucketOptions) error {
+	return c.client.CreateBucket(ctx, opts)
+}
+
+func (c *Client) DeleteBucket(ctx context.Context, opts objcli.DeleteBucketOptions) error {
+	return c.client.DeleteBucket(ctx, opts)
+}
+
+func (c *Client) GetBucketVersioning(
+	ctx context.Context,
+	opts objcli.GetBucketVersioningOptions,
+) (objval.VersioningStatus, error) {
+	return c.client.GetBucketVersioning(ctx, opts)
+}
+
+func (c *Client) GetBucketRegion(ctx context.Context, opts objcli.GetBucketRegionOptions) (string, error) {
+	return c.client.GetBucketRegion(ctx, opts)
+}
+
+func (c *Client) SetObjectLock(ctx context.Context, opts objcli.SetObjectLockOptions) error {
+	return c.client.SetObjectLock(ctx, opts)
+}
+
+func (c *Client) GetBucketLockingStatus(
+	ctx context.Context,
+	opts objcli.GetBucketLockingStatusOptions,
+) (objval.BucketLockingStatus, error) {
+	return c.client.GetBucketLockingStatus(ctx, opts)
+}
+
+// QueryObject is unsupported, the cloud provider can't query objects which it's unable to decrypt.
+func (c *Client) QueryObject(_ context.Context, _ objcli.QueryObjectOptions) (io.ReadCloser, error) {
+	return nil, objerr.ErrUnsupportedOperation
+}
+
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// readCloser combines a reader and a closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// multiReader lazily opens and reads from each of the given readers in turn.
+type multiReader struct {
+	readers []func() (io.ReadCloser, error)
+	current io.ReadCloser
+}
+
+func (m *multiReader) Read(p []byte) (int, error) {
+	for len(m.readers) != 0 || m.current != nil {
+		if m.current == nil {
+			reader, err := m.readers[0]()
+			if err != nil {
+				return 0, err
+			}
+
+			m.current, m.readers = reader, m.readers[1:]
+		}
+
+		n, err := m.current.Read(p)
+		if errors.Is(err, io.EOF) {
+			err = m.current.Close()
+			m.current = nil
+		}
+
+		if n != 0 || err != nil {
+			return n, err
+		}
+	}
+
+	return 0, io.EOF
+}
+
+func (m *multiReader) Close() error {
+	if m.current == nil {
+		return nil
+	}
+
+	return m.current.Close()
+}
@@ -0,0 +1,400 @@
+package objcrypt
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+func newTestClient(t *testing.T) (*Client, *objcli.TestClient) {
+	provider, err := NewStaticKeyProvider(make([]byte, 32))
+	require.NoError(t, err)
+
+	inner := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	return NewClient(ClientOptions{Client: inner, KeyProvider: provider}), inner
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	data := make([]byte, n)
+
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	return data
+}
+
+func getObject(t *testing.T, client objcli.Client, br *objval.ByteRange) []byte {
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:    "bucket",
+		Key:       "key",
+		ByteRange: br,
+	})
+	require.NoError(t, err)
+
+	defer object.Body.Close()
+
+	data, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestClientCapabilities(t *testing.T) {
+	client, inner := newTestClient(t)
+
+	expected := inner.Capabilities()
+	expected.SupportsCompose = false
+
+	require.Equal(t, expected, client.Capabilities())
+}
+
+func TestClientPutGetObject(t *testing.T) {
+	for _, size := range []int{0, 1, ChunkSize - 1, ChunkSize, ChunkSize + 1, 3*ChunkSize + 42} {
+		client, inner := newTestClient(t)
+
+		data := randomBytes(t, size)
+
+		err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+			Bucket: "bucket",
+			Key:    "key",
+			Body:   bytes.NewReader(data),
+		})
+		require.NoError(t, err)
+
+		stored := inner.Buckets["bucket"]["key"].Body
+		require.NotEqual(t, data, stored)
+		require.False(t, size > 16 && bytes.Contains(stored, data[:16]))
+
+		require.Equal(t, data, append([]byte{}, getObject(t, client, nil)...))
+
+		attrs, err := client.GetObjectAttrs(context.Background(), objcli.GetObjectAttrsOptions{
+			Bucket: "bucket",
+			Key:    "key",
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(size), ptr.From(attrs.Size))
+	}
+}
+
+func TestClientGetObjectRange(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	var (
+		first  = randomBytes(t, 2*ChunkSize+10)
+		second = randomBytes(t, ChunkSize/2)
+		data   = append(append([]byte{}, first...), second...)
+	)
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   bytes.NewReader(first),
+	})
+	require.NoError(t, err)
+
+	err = client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   bytes.NewReader(second),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, data, getObject(t, client, nil))
+
+	ranges := []objval.ByteRange{
+		{Start: 0, End: 0},
+		{Start: 5, End: 10},
+		{Start: ChunkSize - 5, End: ChunkSize + 5},
+		{Start: 2*ChunkSize + 5, End: 2*ChunkSize + 20},
+		{Start: 100, End: int64(len(data) - 1)},
+		{Start: int64(len(first)), End: int64(len(data) + 100)},
+	}
+
+	for _, br := range ranges {
+		end := min(br.End, int64(len(data)-1))
+		if br.End == 0 {
+			end = int64(len(data) - 1)
+		}
+
+		require.Equal(t, data[br.Start:end+1], getObject(t, client, &br))
+	}
+}
+
+func TestClientMultipartUpload(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	id, err := client.CreateMultipartUpload(context.Background(), objcli.CreateMultipartUploadOptions{
+		Bucket: "bucket",
+		Key:    "key",
+	})
+	require.NoError(t, err)
+
+	var (
+		data  []byte
+		parts []objval.Part
+	)
+
+	for number := 1; number <= 3; number++ {
+		body := randomBytes(t, ChunkSize+number)
+		data = append(data, body...)
+
+		part, err := client.UploadPart(context.Background(), objcli.UploadPartOptions{
+			Bucket:   "bucket",
+			UploadID: id,
+			Key:      "key",
+			Number:   number,
+			Body:     bytes.NewReader(body),
+		})
+		require.NoError(t, err)
+
+		parts = append(parts, part)
+	}
+
+	err = client.CompleteMultipartUpload(context.Background(), objcli.CompleteMultipartUploadOptions{
+		Bucket:   "bucket",
+		UploadID: id,
+		Key:      "key",
+		Parts:    parts,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, data, getObject(t, client, nil))
+	require.Equal(t, data[ChunkSize-1:2*ChunkSize+3], getObject(t, client, &objval.ByteRange{
+		Start: ChunkSize - 1,
+		End:   2*ChunkSize + 2,
+	}))
+}
+
+func TestClientGetObjectTampered(t *testing.T) {
+	client, inner := newTestClient(t)
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   bytes.NewReader(randomBytes(t, 1024)),
+	})
+	require.NoError(t, err)
+
+	stored := inner.Buckets["bucket"]["key"].Body
+	stored[len(stored)-1] ^= 0xff
+
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+
+	defer object.Body.Close()
+
+	_, err = io.ReadAll(object.Body)
+	require.ErrorIs(t, err, ErrAuthenticationFailed)
+}
+
+func TestClientGetObjectTruncated(t *testing.T) {
+	client, inner := newTestClient(t)
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   bytes.NewReader(randomBytes(t, 1024)),
+	})
+	require.NoError(t, err)
+
+	object := inner.Buckets["bucket"]["key"]
+	object.Body = object.Body[:len(object.Body)-10]
+
+	body, err := client.GetObject(context.Background(), objcli.GetObjectOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+
+	defer body.Body.Close()
+
+	_, err = io.ReadAll(body.Body)
+	require.ErrorIs(t, err, ErrTruncated)
+}
+
+func TestClientGetObjectNotEncrypted(t *testing.T) {
+	client, inner := newTestClient(t)
+
+	objcli.TestUploadRAW(t, inner, "key", []byte("plaintext"))
+
+	_, err := client.GetObject(context.Background(), objcli.GetObjectOptions{Bucket: "bucket", Key: "key"})
+	require.ErrorIs(t, err, ErrMissingKey)
+}
+
+func TestClientGetObjectReorderedChunks(t *testing.T) {
+	client, inner := newTestClient(t)
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   bytes.NewReader(randomBytes(t, 2*ChunkSize)),
+	})
+	require.NoError(t, err)
+
+	var (
+		stored = inner.Buckets["bucket"]["key"].Body
+		first  = stored[headerSize : headerSize+ChunkSize+tagSize]
+		second = stored[headerSize+ChunkSize+tagSize:]
+	)
+
+	swapped := append(append(append([]byte{}, stored[:headerSize]...), second...), first...)
+	copy(stored, swapped)
+
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+
+	defer object.Body.Close()
+
+	_, err = io.ReadAll(object.Body)
+	require.ErrorIs(t, err, ErrAuthenticationFailed)
+}
+
+func TestClientPutObjectMetadata(t *testing.T) {
+	client, inner := newTestClient(t)
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket:   "bucket",
+		Key:      "key",
+		Body:     bytes.NewReader([]byte("value")),
+		Metadata: map[string]string{"user": "value"},
+	})
+	require.NoError(t, err)
+
+	// The wrapped key is stored in the metadata, rather than alongside the encrypted data
+	stored := inner.Buckets["bucket"]["key"]
+	require.Contains(t, stored.Metadata, metadataKey)
+	require.Equal(t, "value", stored.Metadata["user"])
+	require.Len(t, stored.Body, int(headerSize)+len("value")+tagSize)
+
+	attrs, err := client.GetObjectAttrs(context.Background(), objcli.GetObjectAttrsOptions{
+		Bucket: "bucket",
+		Key:    "key",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"user": "value"}, attrs.Metadata)
+}
+
+func TestClientPutObjectCompressed(t *testing.T) {
+	client, inner := newTestClient(t)
+
+	data := bytes.Repeat([]byte("compressible "), ChunkSize)
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket:   "bucket",
+		Key:      "key",
+		Body:     bytes.NewReader(data),
+		Compress: objcli.CompressionGZIP,
+	})
+	require.NoError(t, err)
+
+	// The plaintext should be compressed prior to encryption, so the ciphertext isn't compressed by the inner client
+	stored := inner.Buckets["bucket"]["key"]
+	require.Empty(t, stored.ContentEncoding)
+	require.Less(t, len(stored.Body), len(data)/10)
+	require.Equal(t, string(objcli.CompressionGZIP), stored.Metadata[metadataCompression])
+
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:     "bucket",
+		Key:        "key",
+		Decompress: true,
+	})
+	require.NoError(t, err)
+
+	defer object.Body.Close()
+
+	decompressed, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+	require.Equal(t, data, decompressed)
+}
+
+func TestClientAppendToObjectSharesKey(t *testing.T) {
+	client, inner := newTestClient(t)
+
+	err := client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   bytes.NewReader([]byte("hello, ")),
+	})
+	require.NoError(t, err)
+
+	wrapped := inner.Buckets["bucket"]["key"].Metadata[metadataKey]
+	require.NotEmpty(t, wrapped)
+
+	err = client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   bytes.NewReader([]byte("world")),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, wrapped, inner.Buckets["bucket"]["key"].Metadata[metadataKey])
+	require.Equal(t, []byte("hello, world"), getObject(t, client, nil))
+}
+
+func TestClientAppendToObjectCompressed(t *testing.T) {
+	client, inner := newTestClient(t)
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket:   "bucket",
+		Key:      "key",
+		Body:     bytes.NewReader([]byte("hello, ")),
+		Compress: objcli.CompressionGZIP,
+	})
+	require.NoError(t, err)
+
+	err = client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   bytes.NewReader([]byte("world")),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, string(objcli.CompressionGZIP), inner.Buckets["bucket"]["key"].Metadata[metadataCompression])
+
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:     "bucket",
+		Key:        "key",
+		Decompress: true,
+	})
+	require.NoError(t, err)
+
+	defer object.Body.Close()
+
+	data, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello, world"), data)
+}
+
+func TestClientMultipartUploadInvalidUploadID(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	_, err := client.UploadPart(context.Background(), objcli.UploadPartOptions{
+		Bucket:   "bucket",
+		UploadID: "id",
+		Key:      "key",
+		Number:   1,
+		Body:     bytes.NewReader([]byte("value")),
+	})
+	require.ErrorIs(t, err, ErrInvalidUploadID)
+
+	err = client.CompleteMultipartUpload(context.Background(), objcli.CompleteMultipartUploadOptions{
+		Bucket:   "bucket",
+		UploadID: "id",
+		Key:      "key",
+	})
+	require.ErrorIs(t, err, ErrInvalidUploadID)
+}
+
+func TestClientUploadPartCopy(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	_, err := client.UploadPartCopy(context.Background(), objcli.UploadPartCopyOptions{})
+	require.ErrorIs(t, err, objerr.ErrUnsupportedOperation)
+}
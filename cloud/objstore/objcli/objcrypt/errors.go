@@ -0,0 +1,32 @@
+package objcrypt
+
+import "errors"
+
+var (
+	// ErrInvalidHeader is returned when attempting to decrypt an object which wasn't encrypted by this package, or
+	// whose header has been corrupted.
+	ErrInvalidHeader = errors.New("invalid encryption header, object may not be encrypted")
+
+	// ErrUnsupportedVersion is returned when attempting to decrypt an object which was encrypted using a newer version
+	// of the encryption format.
+	ErrUnsupportedVersion = errors.New("unsupported encryption format version")
+
+	// ErrInvalidWrappedKey is returned by the 'StaticKeyProvider' when a wrapped key can't be unwrapped, this usually
+	// indicates that a different key encryption key was used to wrap the key.
+	ErrInvalidWrappedKey = errors.New("failed to unwrap data encryption key")
+
+	// ErrAuthenticationFailed is returned when a chunk of an object fails authentication, indicating that the object
+	// has been tampered with or corrupted.
+	ErrAuthenticationFailed = errors.New("failed to authenticate encrypted data")
+
+	// ErrTruncated is returned when an encrypted object is shorter than indicated by its header.
+	ErrTruncated = errors.New("encrypted object is truncated")
+
+	// ErrMissingKey is returned when attempting to decrypt an object whose metadata doesn't contain a wrapped data
+	// encryption key, this usually indicates that the object wasn't encrypted by this package.
+	ErrMissingKey = errors.New("object has no data encryption key, object may not be encrypted")
+
+	// ErrInvalidUploadID is returned when uploading/completing a part using an upload id which wasn't returned by
+	// 'CreateMultipartUpload' e.g. one returned by 'ListMultipartUploads'.
+	ErrInvalidUploadID = errors.New("upload id has no data encryption key, it may only be used to abort the upload")
+)
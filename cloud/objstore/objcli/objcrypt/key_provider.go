@@ -0,0 +1,77 @@
+package objcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+)
+
+// KeyProvider is used to wrap/unwrap the data encryption keys used to encrypt objects, generally this will be backed by
+// a key management service so that the key encryption key never leaves the service.
+type KeyProvider interface {
+	// WrapKey encrypts the given data encryption key, the returned wrapped key is stored in the metadata of the object.
+	WrapKey(ctx context.Context, key []byte) ([]byte, error)
+
+	// UnwrapKey decrypts a data encryption key previously wrapped using 'WrapKey'.
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// StaticKeyProvider implements the 'KeyProvider' interface, wrapping data encryption keys with AES-GCM using a static
+// key encryption key.
+type StaticKeyProvider struct {
+	aead cipher.AEAD
+}
+
+var _ KeyProvider = (*StaticKeyProvider)(nil)
+
+// NewStaticKeyProvider returns a new key provider which uses the given key encryption key, which must be a valid AES
+// key (16, 24 or 32 bytes).
+func NewStaticKeyProvider(key []byte) (*StaticKeyProvider, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StaticKeyProvider{aead: aead}, nil
+}
+
+func (s *StaticKeyProvider) WrapKey(_ context.Context, key []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return s.aead.Seal(nonce, nonce, key, nil), nil
+}
+
+func (s *StaticKeyProvider) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < s.aead.NonceSize() {
+		return nil, ErrInvalidWrappedKey
+	}
+
+	key, err := s.aead.Open(nil, wrapped[:s.aead.NonceSize()], wrapped[s.aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrInvalidWrappedKey
+	}
+
+	return key, nil
+}
+
+// newAEAD returns a new AES-GCM cipher using the given key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM cipher: %w", err)
+	}
+
+	return aead, nil
+}
@@ -0,0 +1,45 @@
+package objcrypt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticKeyProviderWrapUnwrap(t *testing.T) {
+	provider, err := NewStaticKeyProvider(make([]byte, 32))
+	require.NoError(t, err)
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	wrapped, err := provider.WrapKey(context.Background(), key)
+	require.NoError(t, err)
+	require.NotContains(t, string(wrapped), string(key))
+
+	unwrapped, err := provider.UnwrapKey(context.Background(), wrapped)
+	require.NoError(t, err)
+	require.Equal(t, key, unwrapped)
+}
+
+func TestStaticKeyProviderUnwrapWrongKey(t *testing.T) {
+	provider, err := NewStaticKeyProvider(make([]byte, 32))
+	require.NoError(t, err)
+
+	wrapped, err := provider.WrapKey(context.Background(), make([]byte, KeySize))
+	require.NoError(t, err)
+
+	other, err := NewStaticKeyProvider([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+
+	_, err = other.UnwrapKey(context.Background(), wrapped)
+	require.ErrorIs(t, err, ErrInvalidWrappedKey)
+
+	_, err = other.UnwrapKey(context.Background(), []byte("short"))
+	require.ErrorIs(t, err, ErrInvalidWrappedKey)
+}
+
+func TestNewStaticKeyProviderInvalidKey(t *testing.T) {
+	_, err := NewStaticKeyProvider([]byte("invalid"))
+	require.Error(t, err)
+}
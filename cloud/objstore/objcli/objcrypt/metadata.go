@@ -0,0 +1,118 @@
+package objcrypt
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+)
+
+const (
+	// metadataKey is the metadata key under which the wrapped data encryption key of an object is stored.
+	metadataKey = "objcrypt_key"
+
+	// metadataCompression is the metadata key under which the compression applied to the plaintext of an object, prior
+	// to it being encrypted, is stored.
+	metadataCompression = "objcrypt_compression"
+
+	// uploadIDSeparator separates the wrapped data encryption key from the upload id of the underlying client, it's not
+	// a valid character in the encoded key.
+	uploadIDSeparator = "~"
+)
+
+// newKey generates a new data encryption key, returning it along with the encoded wrapped key.
+func newKey(ctx context.Context, provider KeyProvider) (cipher.AEAD, string, error) {
+	key := make([]byte, KeySize)
+
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate data encryption key: %w", err)
+	}
+
+	wrapped, err := provider.WrapKey(ctx, key)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to wrap data encryption key: %w", err)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return aead, base64.RawURLEncoding.EncodeToString(wrapped), nil
+}
+
+// unwrapKey returns the data encryption key for the given encoded wrapped key.
+func unwrapKey(ctx context.Context, provider KeyProvider, encoded string) (cipher.AEAD, error) {
+	wrapped, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode wrapped data encryption key: %w", err)
+	}
+
+	key, err := provider.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data encryption key: %w", err)
+	}
+
+	return newAEAD(key)
+}
+
+// unwrapObjectKey returns the data encryption key stored in the given object metadata.
+func unwrapObjectKey(ctx context.Context, provider KeyProvider, metadata map[string]string) (cipher.AEAD, error) {
+	encoded, ok := metadata[metadataKey]
+	if !ok {
+		return nil, ErrMissingKey
+	}
+
+	return unwrapKey(ctx, provider, encoded)
+}
+
+// withMetadata returns a copy of the given user-defined metadata, including the encryption metadata.
+func withMetadata(metadata map[string]string, wrapped string, compression objcli.Compression) map[string]string {
+	metadata = maps.Clone(metadata)
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+
+	metadata[metadataKey] = wrapped
+
+	if compression != objcli.CompressionNone {
+		metadata[metadataCompression] = string(compression)
+	}
+
+	return metadata
+}
+
+// withoutMetadata removes the encryption metadata from the given object metadata.
+func withoutMetadata(metadata map[string]string) map[string]string {
+	delete(metadata, metadataKey)
+	delete(metadata, metadataCompression)
+
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	return metadata
+}
+
+// encodeUploadID returns the upload id returned to the caller, which includes the wrapped data encryption key so that
+// each part of the upload is encrypted using the same key.
+func encodeUploadID(wrapped, id string) string {
+	return wrapped + uploadIDSeparator + id
+}
+
+// decodeUploadID returns the encoded wrapped data encryption key, and the upload id of the underlying client from the
+// given upload id. The key is empty where the upload id was returned by the underlying client.
+func decodeUploadID(id string) (string, string) {
+	wrapped, underlying, ok := strings.Cut(id, uploadIDSeparator)
+	if !ok {
+		return "", id
+	}
+
+	return wrapped, underlying
+}
@@ -0,0 +1,373 @@
+package objcrypt
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// ChunkSize is the size of the plaintext chunks which are individually encrypted/authenticated, this allows
+	// streaming and efficient byte range access.
+	ChunkSize = 64 * 1024
+
+	// KeySize is the size of the generated data encryption keys, resulting in AES-256 being used.
+	KeySize = 32
+
+	// magic identifies an encrypted segment.
+	magic = "CBOE"
+
+	// version is the current version of the encryption format.
+	version byte = 1
+
+	// nonceSize is the size of the GCM nonce.
+	nonceSize = 12
+
+	// tagSize is the size of the GCM authentication tag appended to each chunk.
+	tagSize = 16
+
+	// headerSize is the size of a segment header.
+	headerSize int64 = int64(len(magic) + 1 + 8 + nonceSize)
+)
+
+// segment is a unit of encrypted data, each segment has its own header. An object is made up of one or more segments;
+// multiple segments are the result of multipart uploads and appends, which are concatenated by the cloud provider. All
+// the segments of an object are encrypted using the data encryption key stored in the metadata of the object.
+//
+// The format of a segment is as follows:
+//
+//	| magic (4) | version (1) | size (8) | nonce (12) | chunk... |
+//
+// Where each chunk is up to 'ChunkSize' bytes of plaintext, encrypted using AES-GCM with a nonce derived from the
+// segment nonce and the index of the chunk. The additional authenticated data for each chunk is the header, followed
+// by the index of the chunk and a marker indicating whether it's the final chunk of the segment; chunks may therefore
+// not be reordered, or removed from the end of a segment, without failing authentication.
+type segment struct {
+	// size is the size of the plaintext in the segment.
+	size int64
+
+	nonce [nonceSize]byte
+
+	// header is the encoded header, which prefixes the additional authenticated data of each chunk.
+	header []byte
+
+	aead cipher.AEAD
+}
+
+// newSegment returns a new segment for the given amount of plaintext, which is encrypted using the given key.
+func newSegment(aead cipher.AEAD, size int64) (*segment, error) {
+	seg := &segment{size: size, aead: aead}
+
+	_, err := rand.Read(seg.nonce[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	seg.header = seg.encode()
+
+	return seg, nil
+}
+
+// decodeSegmentHeader decodes the given segment header, the segment is decrypted using the given key.
+func decodeSegmentHeader(aead cipher.AEAD, data []byte) (*segment, error) {
+	if int64(len(data)) < headerSize || string(data[:len(magic)]) != magic {
+		return nil, ErrInvalidHeader
+	}
+
+	data = data[len(magic):]
+
+	if data[0] != version {
+		return nil, ErrUnsupportedVersion
+	}
+
+	data = data[1:]
+
+	seg := &segment{size: int64(binary.BigEndian.Uint64(data)), aead: aead}
+	if seg.size < 0 {
+		return nil, ErrInvalidHeader
+	}
+
+	copy(seg.nonce[:], data[8:])
+
+	seg.header = seg.encode()
+
+	return seg, nil
+}
+
+// readSegmentHeader reads and decodes a segment header from the given reader, an 'io.EOF' is returned if the reader
+// is exhausted before any of the header is read.
+func readSegmentHeader(aead cipher.AEAD, reader io.Reader) (*segment, error) {
+	header := make([]byte, headerSize)
+
+	_, err := io.ReadFull(reader, header)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, ErrTruncated
+	}
+
+	if err != nil {
+		return nil, err // Purposefully not wrapped, may be 'io.EOF'
+	}
+
+	return decodeSegmentHeader(aead, header)
+}
+
+// encode returns the encoded header for the segment.
+func (s *segment) encode() []byte {
+	header := make([]byte, 0, headerSize)
+
+	header = append(header, magic...)
+	header = append(header, version)
+	header = binary.BigEndian.AppendUint64(header, uint64(s.size))
+	header = append(header, s.nonce[:]...)
+
+	return header
+}
+
+// chunks returns the number of chunks in the segment.
+func (s *segment) chunks() int64 {
+	return (s.size + ChunkSize - 1) / ChunkSize
+}
+
+// chunkSize returns the size of the plaintext for the chunk with the given index.
+func (s *segment) chunkSize(index int64) int64 {
+	return min(ChunkSize, s.size-index*ChunkSize)
+}
+
+// encryptedSize returns the size of the segment once encrypted, including the header.
+func (s *segment) encryptedSize() int64 {
+	return headerSize + s.size + s.chunks()*tagSize
+}
+
+// chunkNonce returns the nonce for the chunk with the given index.
+func (s *segment) chunkNonce(index int64) []byte {
+	nonce := s.nonce
+
+	counter := binary.BigEndian.Uint64(nonce[nonceSize-8:]) ^ uint64(index)
+	binary.BigEndian.PutUint64(nonce[nonceSize-8:], counter)
+
+	return nonce[:]
+}
+
+// chunkAAD returns the additional authenticated data for the chunk with the given index.
+func (s *segment) chunkAAD(index int64) []byte {
+	aad := make([]byte, 0, headerSize+8+1)
+
+	aad = append(aad, s.header...)
+	aad = binary.BigEndian.AppendUint64(aad, uint64(index))
+
+	var final byte
+	if index == s.chunks()-1 {
+		final = 1
+	}
+
+	return append(aad, final)
+}
+
+// seal encrypts the chunk with the given index.
+func (s *segment) seal(index int64, plaintext []byte) []byte {
+	return s.aead.Seal(nil, s.chunkNonce(index), plaintext, s.chunkAAD(index))
+}
+
+// open decrypts the chunk with the given index.
+func (s *segment) open(index int64, ciphertext []byte) ([]byte, error) {
+	plaintext, err := s.aead.Open(nil, s.chunkNonce(index), ciphertext, s.chunkAAD(index))
+	if err != nil {
+		return nil, ErrAuthenticationFailed
+	}
+
+	return plaintext, nil
+}
+
+// encryptingReader is a seekable reader which lazily encrypts the given plaintext as a single segment.
+type encryptingReader struct {
+	seg *segment
+	src io.ReadSeeker
+
+	// start is the position of the plaintext in the source.
+	start int64
+
+	// offset is the current position in the encrypted segment.
+	offset int64
+
+	index int64
+	chunk []byte
+}
+
+// newEncryptingReader returns a reader which encrypts the remaining data in the given reader using the given key.
+func newEncryptingReader(aead cipher.AEAD, src io.ReadSeeker) (*encryptingReader, error) {
+	start, err := src.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current position: %w", err)
+	}
+
+	end, err := src.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get length: %w", err)
+	}
+
+	seg, err := newSegment(aead, end-start)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptingReader{seg: seg, src: src, start: start, index: -1}, nil
+}
+
+func (e *encryptingReader) Read(p []byte) (int, error) {
+	if e.offset >= e.seg.encryptedSize() {
+		return 0, io.EOF
+	}
+
+	if e.offset < headerSize {
+		n := copy(p, e.seg.header[e.offset:])
+		e.offset += int64(n)
+
+		return n, nil
+	}
+
+	var (
+		relative = e.offset - headerSize
+		index    = relative / (ChunkSize + tagSize)
+		within   = relative % (ChunkSize + tagSize)
+	)
+
+	if index != e.index {
+		err := e.load(index)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, e.chunk[within:])
+	e.offset += int64(n)
+
+	return n, nil
+}
+
+// load reads and encrypts the chunk with the given index.
+func (e *encryptingReader) load(index int64) error {
+	_, err := e.src.Seek(e.start+index*ChunkSize, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to seek to chunk: %w", err)
+	}
+
+	plaintext := make([]byte, e.seg.chunkSize(index))
+
+	_, err = io.ReadFull(e.src, plaintext)
+	if err != nil {
+		return fmt.Errorf("failed to read chunk: %w", err)
+	}
+
+	e.index, e.chunk = index, e.seg.seal(index, plaintext)
+
+	return nil
+}
+
+func (e *encryptingReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += e.offset
+	case io.SeekEnd:
+		offset += e.seg.encryptedSize()
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+
+	if offset < 0 {
+		return 0, fmt.Errorf("invalid offset %d", offset)
+	}
+
+	e.offset = offset
+
+	return offset, nil
+}
+
+// decryptingReader decrypts one or more segments read from the given source.
+type decryptingReader struct {
+	aead cipher.AEAD
+	src  io.ReadCloser
+
+	seg *segment
+
+	// index is the index of the next chunk to be read from the current segment, and end is the index after the last
+	// chunk which should be read from the current segment.
+	index int64
+	end   int64
+
+	// single indicates that only the current segment should be read, rather than reading segments until the source is
+	// exhausted.
+	single bool
+
+	buffer []byte
+}
+
+// newDecryptingReader returns a reader which decrypts all the segments in the given source using the given key.
+func newDecryptingReader(aead cipher.AEAD, src io.ReadCloser) *decryptingReader {
+	return &decryptingReader{aead: aead, src: src}
+}
+
+// newChunkReader returns a reader which decrypts the given range of chunks from a segment, the source should be
+// positioned at the start of the first chunk.
+func newChunkReader(seg *segment, src io.ReadCloser, start, end int64) *decryptingReader {
+	return &decryptingReader{src: src, seg: seg, index: start, end: end, single: true}
+}
+
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	for len(d.buffer) == 0 {
+		if d.seg == nil || d.index >= d.end {
+			if d.single {
+				return 0, io.EOF
+			}
+
+			err := d.next()
+			if err != nil {
+				return 0, err // Purposefully not wrapped, may be 'io.EOF'
+			}
+
+			continue
+		}
+
+		ciphertext := make([]byte, d.seg.chunkSize(d.index)+tagSize)
+
+		_, err := io.ReadFull(d.src, ciphertext)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, ErrTruncated
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("failed to read chunk: %w", err)
+		}
+
+		d.buffer, err = d.seg.open(d.index, ciphertext)
+		if err != nil {
+			return 0, err
+		}
+
+		d.index++
+	}
+
+	n := copy(p, d.buffer)
+	d.buffer = d.buffer[n:]
+
+	return n, nil
+}
+
+// next reads the header of the next segment.
+func (d *decryptingReader) next() error {
+	seg, err := readSegmentHeader(d.aead, d.src)
+	if err != nil {
+		return err // Purposefully not wrapped, may be 'io.EOF'
+	}
+
+	d.seg, d.index, d.end = seg, 0, seg.chunks()
+
+	return nil
+}
+
+func (d *decryptingReader) Close() error {
+	return d.src.Close()
+}
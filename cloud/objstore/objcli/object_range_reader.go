@@ -0,0 +1,216 @@
+package objcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// DefaultRangeReaderMaxResumes is the default number of consecutive attempts made to resume a broken stream.
+const DefaultRangeReaderMaxResumes = 3
+
+// GetObjectRangeReaderOptions encapsulates the options available when using the 'GetObjectRangeReader' function.
+type GetObjectRangeReaderOptions struct {
+	// Bucket is the bucket being operated on.
+	Bucket string
+
+	// Key is the key (path) of the object/blob being operated on.
+	Key string
+
+	// ByteRange allows specifying a start/end offset to be read, the entire object is read when <nil>.
+	ByteRange *objval.ByteRange
+
+	// MaxResumes is the maximum number of consecutive attempts made to resume a broken stream, without any data being
+	// read in between. Defaults to 'DefaultRangeReaderMaxResumes', a negative value disables resuming.
+	MaxResumes int
+
+	// BandwidthLimiter overrides the limiter used by a 'RateLimitedClient' for this operation.
+	//
+	// NOTE: Ignored by clients which don't limit bandwidth.
+	BandwidthLimiter *BandwidthLimiter
+}
+
+// defaults fills any missing attributes to a sane default.
+func (o *GetObjectRangeReaderOptions) defaults() {
+	if o.MaxResumes == 0 {
+		o.MaxResumes = DefaultRangeReaderMaxResumes
+	}
+
+	o.MaxResumes = max(o.MaxResumes, 0)
+}
+
+// rangeReader is an 'io.ReadCloser' which reads the body of an object, transparently re-issuing a ranged 'GetObject'
+// from the last delivered offset should the body break part way through being read (e.g. the connection is reset).
+type rangeReader struct {
+	ctx    context.Context
+	client Client
+	opts   GetObjectRangeReaderOptions
+
+	etag      *string
+	offset    int64
+	remaining *int64
+	body      io.ReadCloser
+	resumes   int
+
+	err    error
+	closed bool
+}
+
+// GetObjectRangeReader returns a reader for the body of the object with the given key which, unlike the body returned
+// by 'GetObject', resumes reading from the last delivered offset when the stream breaks, rather than failing.
+//
+// The ETag of the object is validated upon resuming, 'ErrObjectChanged' is returned if the object has been modified
+// since the read started.
+//
+// NOTE: The reader is not thread safe, and must be closed to avoid leaking resources.
+func GetObjectRangeReader(
+	ctx context.Context,
+	client Client,
+	opts GetObjectRangeReaderOptions,
+) (io.ReadCloser, error) {
+	err := opts.ByteRange.Valid(false)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	opts.defaults()
+
+	reader := &rangeReader{ctx: ctx, client: client, opts: opts}
+
+	if opts.ByteRange != nil {
+		reader.offset = opts.ByteRange.Start
+	}
+
+	object, err := reader.get()
+	if err != nil {
+		return nil, err
+	}
+
+	reader.etag, reader.body = object.ETag, object.Body
+
+	if object.Size != nil {
+		reader.remaining = ptr.To(*object.Size)
+	}
+
+	return reader, nil
+}
+
+// Read reads from the body of the object, resuming the stream when it breaks.
+func (r *rangeReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, ErrRangeReaderClosed
+	}
+
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	for {
+		n, err := r.body.Read(p)
+
+		r.advance(n)
+
+		if err == nil || errors.Is(err, io.EOF) {
+			return n, err
+		}
+
+		// The stream broke after the entire range was delivered, so there's nothing left to resume
+		if r.remaining != nil && *r.remaining == 0 {
+			return n, io.EOF
+		}
+
+		if r.resumes >= r.opts.MaxResumes || r.ctx.Err() != nil {
+			return n, err
+		}
+
+		rErr := r.resume()
+		if rErr != nil {
+			r.err = errors.Join(err, rErr)
+			return n, r.err
+		}
+
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// Close closes the body of the object, any subsequent reads will fail.
+func (r *rangeReader) Close() error {
+	if r.closed {
+		return nil
+	}
+
+	r.closed = true
+
+	if r.body == nil {
+		return nil
+	}
+
+	return r.body.Close()
+}
+
+// advance records that the given number of bytes have been delivered to the caller.
+func (r *rangeReader) advance(n int) {
+	if n <= 0 {
+		return
+	}
+
+	r.offset += int64(n)
+	r.resumes = 0
+
+	if r.remaining != nil {
+		*r.remaining -= int64(n)
+	}
+}
+
+// resume replaces the broken body, with one which starts at the current offset.
+func (r *rangeReader) resume() error {
+	r.resumes++
+
+	_ = r.body.Close()
+	r.body = nil
+
+	object, err := r.get()
+	if err != nil {
+		return err
+	}
+
+	if r.etag != nil && (object.ETag == nil || trimETag(*object.ETag) != trimETag(*r.etag)) {
+		_ = object.Body.Close()
+		return ErrObjectChanged
+	}
+
+	r.body = object.Body
+
+	return nil
+}
+
+// get the object from the current offset, until the end of the requested range.
+func (r *rangeReader) get() (*objval.Object, error) {
+	var br *objval.ByteRange
+
+	if r.opts.ByteRange != nil || r.offset != 0 {
+		br = &objval.ByteRange{Start: r.offset}
+	}
+
+	if r.opts.ByteRange != nil {
+		br.End = r.opts.ByteRange.End
+	}
+
+	object, err := r.client.GetObject(r.ctx, GetObjectOptions{
+		Bucket:           r.opts.Bucket,
+		Key:              r.opts.Key,
+		ByteRange:        br,
+		BandwidthLimiter: r.opts.BandwidthLimiter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+
+	return object, nil
+}
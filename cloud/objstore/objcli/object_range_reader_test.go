@@ -0,0 +1,202 @@
+package objcli
+
+import (
+	"context"
+	"io"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// brokenBody is a body which breaks after the given number of bytes have been read.
+type brokenBody struct {
+	io.ReadCloser
+	remaining int
+}
+
+func (b *brokenBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		return 0, syscall.ECONNRESET
+	}
+
+	n, err := b.ReadCloser.Read(p[:min(len(p), b.remaining)])
+
+	b.remaining -= n
+
+	return n, err
+}
+
+// flakyClient is a client whose object bodies break after the given number of bytes, for each of the given breaks.
+type flakyClient struct {
+	*TestClient
+	breaks []int
+	ranges []*objval.ByteRange
+	before func()
+}
+
+func (f *flakyClient) GetObject(ctx context.Context, opts GetObjectOptions) (*objval.Object, error) {
+	f.ranges = append(f.ranges, opts.ByteRange)
+
+	if f.before != nil {
+		f.before()
+	}
+
+	object, err := f.TestClient.GetObject(ctx, opts)
+	if err != nil || len(f.breaks) == 0 {
+		return object, err
+	}
+
+	object.Body, f.breaks = &brokenBody{ReadCloser: object.Body, remaining: f.breaks[0]}, f.breaks[1:]
+
+	return object, nil
+}
+
+func TestGetObjectRangeReader(t *testing.T) {
+	type test struct {
+		name      string
+		byteRange *objval.ByteRange
+		breaks    []int
+		expected  string
+		ranges    []*objval.ByteRange
+	}
+
+	tests := []*test{
+		{
+			name:     "NoBreaks",
+			expected: "Hello, World!",
+			ranges:   []*objval.ByteRange{nil},
+		},
+		{
+			name:     "SingleBreak",
+			breaks:   []int{5},
+			expected: "Hello, World!",
+			ranges:   []*objval.ByteRange{nil, {Start: 5}},
+		},
+		{
+			name:     "MultipleBreaks",
+			breaks:   []int{2, 0, 3, 4},
+			expected: "Hello, World!",
+			ranges:   []*objval.ByteRange{nil, {Start: 2}, {Start: 2}, {Start: 5}, {Start: 9}},
+		},
+		{
+			name:      "WithByteRange",
+			byteRange: &objval.ByteRange{Start: 2, End: 8},
+			breaks:    []int{3},
+			expected:  "llo, Wo",
+			ranges:    []*objval.ByteRange{{Start: 2, End: 8}, {Start: 5, End: 8}},
+		},
+		{
+			name:     "BreakAfterEntireObject",
+			breaks:   []int{13},
+			expected: "Hello, World!",
+			ranges:   []*objval.ByteRange{nil},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &flakyClient{TestClient: NewTestClient(t, objval.ProviderAWS), breaks: test.breaks}
+
+			TestUploadRAW(t, client, key, []byte("Hello, World!"))
+
+			reader, err := GetObjectRangeReader(context.Background(), client, GetObjectRangeReaderOptions{
+				Bucket:    bucket,
+				Key:       key,
+				ByteRange: test.byteRange,
+			})
+			require.NoError(t, err)
+
+			data, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, string(data))
+			require.Equal(t, test.ranges, client.ranges)
+
+			require.NoError(t, reader.Close())
+
+			_, err = reader.Read(make([]byte, 1))
+			require.ErrorIs(t, err, ErrRangeReaderClosed)
+		})
+	}
+}
+
+func TestGetObjectRangeReaderExceededMaxResumes(t *testing.T) {
+	client := &flakyClient{TestClient: NewTestClient(t, objval.ProviderAWS), breaks: []int{5, 0, 0}}
+
+	TestUploadRAW(t, client, key, []byte("Hello, World!"))
+
+	reader, err := GetObjectRangeReader(context.Background(), client, GetObjectRangeReaderOptions{
+		Bucket:     bucket,
+		Key:        key,
+		MaxResumes: 1,
+	})
+	require.NoError(t, err)
+
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	require.ErrorIs(t, err, syscall.ECONNRESET)
+	require.Equal(t, "Hello", string(data))
+	require.Len(t, client.ranges, 2)
+}
+
+func TestGetObjectRangeReaderObjectChanged(t *testing.T) {
+	client := &flakyClient{TestClient: NewTestClient(t, objval.ProviderAWS), breaks: []int{5}}
+
+	TestUploadRAW(t, client, key, []byte("Hello, World!"))
+
+	reader, err := GetObjectRangeReader(context.Background(), client, GetObjectRangeReaderOptions{
+		Bucket: bucket,
+		Key:    key,
+	})
+	require.NoError(t, err)
+
+	defer reader.Close()
+
+	// Overwrite the object before the broken stream is resumed
+	client.before = func() { TestUploadRAW(t, client.TestClient, key, []byte("Goodbye, World!")) }
+
+	data, err := io.ReadAll(reader)
+	require.ErrorIs(t, err, ErrObjectChanged)
+	require.Equal(t, "Hello", string(data))
+
+	// The error should be sticky, rather than the reader reporting EOF
+	_, err = reader.Read(make([]byte, 1))
+	require.ErrorIs(t, err, ErrObjectChanged)
+}
+
+func TestGetObjectRangeReaderContextCancelled(t *testing.T) {
+	client := &flakyClient{TestClient: NewTestClient(t, objval.ProviderAWS), breaks: []int{5}}
+
+	TestUploadRAW(t, client, key, []byte("Hello, World!"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader, err := GetObjectRangeReader(ctx, client, GetObjectRangeReaderOptions{Bucket: bucket, Key: key})
+	require.NoError(t, err)
+
+	defer reader.Close()
+
+	cancel()
+
+	_, err = io.ReadAll(reader)
+	require.ErrorIs(t, err, syscall.ECONNRESET)
+	require.Len(t, client.ranges, 1)
+}
+
+func TestGetObjectRangeReaderInvalidByteRange(t *testing.T) {
+	client := NewTestClient(t, objval.ProviderAWS)
+
+	_, err := GetObjectRangeReader(context.Background(), client, GetObjectRangeReaderOptions{
+		Bucket:    bucket,
+		Key:       key,
+		ByteRange: &objval.ByteRange{Start: 2, End: 1},
+	})
+
+	var invalid *objval.InvalidByteRangeError
+
+	require.ErrorAs(t, err, &invalid)
+}
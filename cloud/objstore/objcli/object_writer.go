@@ -0,0 +1,249 @@
+package objcli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// DefaultObjectWriterPartSize is the default size of the parts uploaded by an 'ObjectWriter'.
+const DefaultObjectWriterPartSize = 8 * 1024 * 1024
+
+// ObjectWriterOptions encapsulates the options available when creating an 'ObjectWriter'.
+type ObjectWriterOptions struct {
+	// PartSize is the size of the parts uploaded by the writer, which is also the amount of data buffered in memory.
+	// Defaults to 'DefaultObjectWriterPartSize', and is raised to the minimum part size supported by the client.
+	PartSize int64
+
+	// BandwidthLimiter overrides the limiter used by a 'RateLimitedClient' for the upload.
+	//
+	// NOTE: Ignored by clients which don't limit bandwidth.
+	BandwidthLimiter *BandwidthLimiter
+}
+
+// defaults fills any missing attributes to a sane default.
+func (o *ObjectWriterOptions) defaults(capabilities objval.Capabilities) {
+	if o.PartSize <= 0 {
+		o.PartSize = DefaultObjectWriterPartSize
+	}
+
+	o.PartSize = max(o.PartSize, capabilities.MinPartSize)
+}
+
+// ObjectWriter is an 'io.WriteCloser' which uploads the data written to it as an object, this allows producers (e.g.
+// archive writers) to stream data into an object, rather than having to provide an 'io.ReadSeeker' to 'PutObject'.
+//
+// Data is buffered in memory, and uploaded in parts using a multipart upload, which is completed upon 'Close'. Objects
+// which fit in a single part are uploaded using 'PutObject' instead. The object doesn't exist until the writer has been
+// successfully closed.
+//
+// NOTE: The writer is not thread safe, and must be closed using either 'Close' or 'CloseWithError' to avoid leaving
+// behind an incomplete multipart upload.
+type ObjectWriter struct {
+	ctx          context.Context
+	client       Client
+	bucket       string
+	key          string
+	opts         ObjectWriterOptions
+	capabilities objval.Capabilities
+
+	buf      []byte
+	uploadID string
+	parts    []objval.Part
+	started  bool
+
+	err    error
+	closed bool
+}
+
+// NewObjectWriter returns a new writer which uploads the data written to it to the object with the given key.
+func NewObjectWriter(ctx context.Context, client Client, bucket, key string, opts ObjectWriterOptions) *ObjectWriter {
+	capabilities := client.Capabilities()
+
+	opts.defaults(capabilities)
+
+	return &ObjectWriter{
+		ctx:          ctx,
+		client:       client,
+		bucket:       bucket,
+		key:          key,
+		opts:         opts,
+		capabilities: capabilities,
+	}
+}
+
+// Write buffers the given data, uploading a part each time the buffer is filled. Once an error has been returned, all
+// subsequent writes will fail, and the writer should be closed using 'CloseWithError'.
+func (w *ObjectWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrObjectWriterClosed
+	}
+
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	var written int
+
+	for len(p) > 0 {
+		// Full parts are only uploaded once there's more data, so that objects which fit in a single part may be
+		// uploaded using a single request.
+		if int64(len(w.buf)) == w.opts.PartSize {
+			w.err = w.flush()
+			if w.err != nil {
+				return written, w.err
+			}
+		}
+
+		if w.buf == nil {
+			w.buf = make([]byte, 0, w.opts.PartSize)
+		}
+
+		n := min(len(p), cap(w.buf)-len(w.buf))
+
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+// Close uploads any buffered data and completes the upload, creating the object. Should the upload fail, it's aborted.
+func (w *ObjectWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+
+	if w.err != nil {
+		return w.abort(w.err)
+	}
+
+	if !w.started {
+		return w.put()
+	}
+
+	err := w.complete()
+	if err != nil {
+		return w.abort(err)
+	}
+
+	return nil
+}
+
+// CloseWithError aborts the upload, discarding any data which has been written; the object is not created. The given
+// error is returned by any subsequent calls to 'Write'.
+func (w *ObjectWriter) CloseWithError(err error) error {
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+
+	if err != nil {
+		w.err = err
+	}
+
+	if !w.started {
+		return nil
+	}
+
+	return w.client.AbortMultipartUpload(w.ctx, AbortMultipartUploadOptions{
+		Bucket:   w.bucket,
+		UploadID: w.uploadID,
+		Key:      w.key,
+	})
+}
+
+// put uploads the buffered data using a single request.
+func (w *ObjectWriter) put() error {
+	err := w.client.PutObject(w.ctx, PutObjectOptions{
+		Bucket:           w.bucket,
+		Key:              w.key,
+		Body:             bytes.NewReader(w.buf),
+		BandwidthLimiter: w.opts.BandwidthLimiter,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+
+	return nil
+}
+
+// flush uploads the buffered data as the next part of the multipart upload, creating the upload if required.
+func (w *ObjectWriter) flush() error {
+	if w.capabilities.MaxParts > 0 && len(w.parts) >= w.capabilities.MaxParts {
+		return ErrExceededMaxParts
+	}
+
+	if !w.started {
+		id, err := w.client.CreateMultipartUpload(w.ctx, CreateMultipartUploadOptions{Bucket: w.bucket, Key: w.key})
+		if err != nil {
+			return fmt.Errorf("failed to create multipart upload: %w", err)
+		}
+
+		w.uploadID, w.started = id, true
+	}
+
+	part, err := w.client.UploadPart(w.ctx, UploadPartOptions{
+		Bucket:           w.bucket,
+		UploadID:         w.uploadID,
+		Key:              w.key,
+		Number:           len(w.parts) + 1,
+		Body:             bytes.NewReader(w.buf),
+		BandwidthLimiter: w.opts.BandwidthLimiter,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload part: %w", err)
+	}
+
+	w.parts = append(w.parts, part)
+	w.buf = w.buf[:0]
+
+	return nil
+}
+
+// complete uploads any remaining data, and completes the multipart upload.
+func (w *ObjectWriter) complete() error {
+	if len(w.buf) != 0 {
+		err := w.flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	err := w.client.CompleteMultipartUpload(w.ctx, CompleteMultipartUploadOptions{
+		Bucket:   w.bucket,
+		UploadID: w.uploadID,
+		Key:      w.key,
+		Parts:    w.parts,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	return nil
+}
+
+// abort aborts the multipart upload (if one was created), returning the given error.
+func (w *ObjectWriter) abort(err error) error {
+	if !w.started {
+		return err
+	}
+
+	abortErr := w.client.AbortMultipartUpload(w.ctx, AbortMultipartUploadOptions{
+		Bucket:   w.bucket,
+		UploadID: w.uploadID,
+		Key:      w.key,
+	})
+	if abortErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to abort multipart upload: %w", abortErr))
+	}
+
+	return err
+}
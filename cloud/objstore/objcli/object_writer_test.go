@@ -0,0 +1,126 @@
+package objcli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	testutil "github.com/couchbase/tools-common/testing/util"
+)
+
+func TestObjectWriter(t *testing.T) {
+	type test struct {
+		name     string
+		partSize int64
+		writes   []string
+	}
+
+	tests := []*test{
+		{
+			name:   "Empty",
+			writes: []string{},
+		},
+		{
+			name:     "SinglePart",
+			partSize: 13,
+			writes:   []string{"Hello, ", "World!"},
+		},
+		{
+			name:     "MultipleParts",
+			partSize: 4,
+			writes:   []string{"Hello, ", "World!"},
+		},
+		{
+			name:     "MultiplePartsExact",
+			partSize: 3,
+			writes:   []string{"abc", "def", "ghi"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				client   = NewTestClient(t, objval.ProviderGCP)
+				writer   = NewObjectWriter(context.Background(), client, bucket, key, ObjectWriterOptions{PartSize: test.partSize})
+				expected []byte
+			)
+
+			for _, data := range test.writes {
+				n, err := writer.Write([]byte(data))
+				require.NoError(t, err)
+				require.Equal(t, len(data), n)
+
+				expected = append(expected, data...)
+			}
+
+			require.NoError(t, writer.Close())
+
+			object, err := client.GetObject(context.Background(), GetObjectOptions{Bucket: bucket, Key: key})
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(testutil.ReadAll(t, object.Body)))
+
+			// There should be no remaining parts
+			require.Len(t, client.Buckets[bucket], 1)
+
+			_, err = writer.Write([]byte("a"))
+			require.ErrorIs(t, err, ErrObjectWriterClosed)
+		})
+	}
+}
+
+func TestObjectWriterCloseWithError(t *testing.T) {
+	var (
+		client = NewTestClient(t, objval.ProviderGCP)
+		writer = NewObjectWriter(context.Background(), client, bucket, key, ObjectWriterOptions{PartSize: 4})
+		cause  = errors.New("cause")
+	)
+
+	_, err := writer.Write([]byte("Hello, World!"))
+	require.NoError(t, err)
+
+	require.NoError(t, writer.CloseWithError(cause))
+	require.Empty(t, client.Buckets[bucket])
+
+	_, err = writer.Write([]byte("a"))
+	require.ErrorIs(t, err, ErrObjectWriterClosed)
+}
+
+func TestObjectWriterPartSize(t *testing.T) {
+	client := NewTestClient(t, objval.ProviderAWS)
+
+	writer := NewObjectWriter(context.Background(), client, bucket, key, ObjectWriterOptions{})
+	require.Equal(t, int64(DefaultObjectWriterPartSize), writer.opts.PartSize)
+
+	writer = NewObjectWriter(context.Background(), client, bucket, key, ObjectWriterOptions{PartSize: 1024})
+	require.Equal(t, client.Capabilities().MinPartSize, writer.opts.PartSize)
+}
+
+func TestObjectWriterExceededMaxParts(t *testing.T) {
+	client := &MockClient{}
+
+	client.On("Capabilities").Return(objval.Capabilities{MaxParts: 1})
+	client.On("CreateMultipartUpload", mock.Anything, mock.Anything).Return("id", nil)
+	client.On("UploadPart", mock.Anything, mock.Anything).Return(objval.Part{ID: "part", Number: 1}, nil)
+	client.On("AbortMultipartUpload", mock.Anything, AbortMultipartUploadOptions{
+		Bucket:   bucket,
+		UploadID: "id",
+		Key:      key,
+	}).Return(nil)
+
+	writer := NewObjectWriter(context.Background(), client, bucket, key, ObjectWriterOptions{PartSize: 4})
+
+	_, err := writer.Write([]byte("Hello, World!"))
+	require.ErrorIs(t, err, ErrExceededMaxParts)
+
+	_, err = writer.Write([]byte("a"))
+	require.ErrorIs(t, err, ErrExceededMaxParts)
+
+	require.ErrorIs(t, writer.Close(), ErrExceededMaxParts)
+
+	client.AssertExpectations(t)
+	client.AssertNumberOfCalls(t, "UploadPart", 1)
+}
@@ -0,0 +1,920 @@
+// Package objfs provides an implementation of 'objcli.Client' which stores objects in a directory on the local
+// filesystem, this allows using the same code paths for local and cloud backed storage.
+//
+// Buckets are sub-directories of the root directory, and keys are paths within those sub-directories. Multipart uploads
+// are staged as individual part files, and are concatenated upon completion. When versioning is enabled, non-current
+// versions are retained using a timestamp suffix.
+package objfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+const (
+	// metaDir is the directory (within the root) used to store temporary files, multipart uploads and non-current
+	// versions. It's hidden, which is not a valid bucket name, so it can't clash with a bucket.
+	metaDir = ".objfs"
+
+	// tmpDir is the directory used to stage objects before they're atomically moved into place.
+	tmpDir = "tmp"
+
+	// uploadsDir is the directory used to stage the parts for multipart uploads.
+	uploadsDir = "uploads"
+
+	// versionsDir is the directory used to store non-current object versions.
+	versionsDir = "versions"
+
+	// uploadFile is the file (within an upload directory) which stores the bucket/key of the upload.
+	uploadFile = "upload"
+)
+
+// Client implements the 'objcli.Client' interface allowing the creation/management of objects stored on the local
+// filesystem.
+type Client struct {
+	root       string
+	versioning bool
+}
+
+var _ objcli.Client = (*Client)(nil)
+
+// ClientOptions encapsulates the options for creating a new filesystem Client.
+type ClientOptions struct {
+	// Root is the directory in which buckets are stored, each bucket being a sub-directory of the root.
+	//
+	// NOTE: Required
+	Root string
+
+	// Versioning enables object versioning, meaning overwritten/deleted objects are retained as non-current versions
+	// until they're deleted using 'DeleteDirectory' with 'Versions' set.
+	Versioning bool
+}
+
+// NewClient returns a new client which stores objects in the given root directory, which will be created if it doesn't
+// already exist.
+func NewClient(options ClientOptions) (*Client, error) {
+	if options.Root == "" {
+		return nil, ErrRootRequired
+	}
+
+	root, err := filepath.Abs(options.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for root: %w", err)
+	}
+
+	for _, dir := range []string{tmpDir, uploadsDir, versionsDir} {
+		err = os.MkdirAll(filepath.Join(root, metaDir, dir), 0o755)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
+	client := Client{
+		root:       root,
+		versioning: options.Versioning,
+	}
+
+	return &client, nil
+}
+
+func (c *Client) Provider() objval.Provider {
+	return objval.ProviderNone
+}
+
+// Capabilities returns the capabilities of the local filesystem, which doesn't impose any limits on multipart uploads
+// or copies.
+func (c *Client) Capabilities() objval.Capabilities {
+	return objval.Capabilities{
+		SupportsVersioning: true,
+		SupportsCompose:    true,
+		MaxCopySize:        math.MaxInt64,
+	}
+}
+
+func (c *Client) GetObject(_ context.Context, opts objcli.GetObjectOptions) (*objval.Object, error) {
+	if err := opts.ByteRange.Valid(false); err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	file, info, err := c.open(opts.Bucket, opts.Key)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	attrs := newObjectAttrs(opts.Key, info)
+
+	if opts.NotModified(attrs) {
+		file.Close()
+		return nil, objerr.ErrNotModified
+	}
+
+	offset, length := int64(0), info.Size()
+
+	if opts.ByteRange != nil {
+		if opts.ByteRange.Start != 0 && opts.ByteRange.Start >= info.Size() {
+			file.Close()
+			return nil, &objval.InvalidByteRangeError{ByteRange: opts.ByteRange}
+		}
+
+		offset, length = opts.ByteRange.ToOffsetLength(info.Size() - opts.ByteRange.Start)
+		length = min(length, info.Size()-offset)
+	}
+
+	attrs.Size = &length
+
+	object := &objval.Object{
+		ObjectAttrs: *attrs,
+		Body:        &readCloser{Reader: io.NewSectionReader(file, offset, length), Closer: file},
+	}
+
+	if !opts.Decompress {
+		return object, nil
+	}
+
+	// Files don't have a content encoding, so compression is detected using the extension
+	err = objcli.DecompressObject(object, "")
+	if err != nil {
+		object.Body.Close()
+		return nil, fmt.Errorf("failed to decompress object: %w", err)
+	}
+
+	return object, nil
+}
+
+func (c *Client) GetObjectAttrs(_ context.Context, opts objcli.GetObjectAttrsOptions) (*objval.ObjectAttrs, error) {
+	path, err := c.objectPath(opts.Bucket, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return nil, c.handleError(opts.Bucket, opts.Key, err)
+	}
+
+	return newObjectAttrs(opts.Key, info), nil
+}
+
+func (c *Client) PutObject(_ context.Context, opts objcli.PutObjectOptions) error {
+	if len(opts.Metadata) != 0 {
+		return objerr.ErrUnsupportedOperation
+	}
+
+	body, err := objcli.CompressBody(opts.Body, opts.Compress)
+	if err != nil {
+		return err // Purposefully not wrapped
+	}
+
+	return c.write(opts.Bucket, opts.Key, func(w io.Writer) error {
+		_, err := io.Copy(w, body)
+		return err
+	})
+}
+
+func (c *Client) GetObjectTags(_ context.Context, _ objcli.GetObjectTagsOptions) (map[string]string, error) {
+	return nil, objerr.ErrUnsupportedOperation
+}
+
+func (c *Client) PutObjectTags(_ context.Context, _ objcli.PutObjectTagsOptions) error {
+	return objerr.ErrUnsupportedOperation
+}
+
+func (c *Client) DeleteObjectTags(_ context.Context, _ objcli.DeleteObjectTagsOptions) error {
+	return objerr.ErrUnsupportedOperation
+}
+
+func (c *Client) CopyObject(_ context.Context, opts objcli.CopyObjectOptions) error {
+	file, _, err := c.open(opts.SourceBucket, opts.SourceKey)
+	if err != nil {
+		return err // Purposefully not wrapped
+	}
+	defer file.Close()
+
+	return c.write(opts.DestinationBucket, opts.DestinationKey, func(w io.Writer) error {
+		_, err := io.Copy(w, file)
+		return err
+	})
+}
+
+func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectOptions) error {
+	// When versioning is enabled, we must create a new version of the object rather than modifying it in place
+	if c.versioning {
+		return c.appendToObjectVersioned(ctx, opts)
+	}
+
+	path, err := c.objectPath(opts.Bucket, opts.Key)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkBucket(opts.Bucket)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open object: %w", err)
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, opts.Body)
+	if err != nil {
+		return fmt.Errorf("failed to append to object: %w", err)
+	}
+
+	return file.Close()
+}
+
+// appendToObjectVersioned appends to the given object by creating a new version containing the existing data followed
+// by the appended data.
+func (c *Client) appendToObjectVersioned(_ context.Context, opts objcli.AppendToObjectOptions) error {
+	file, _, err := c.open(opts.Bucket, opts.Key)
+	if err != nil && !objerr.IsNotFoundError(err) {
+		return err // Purposefully not wrapped
+	}
+
+	if file != nil {
+		defer file.Close()
+	}
+
+	return c.write(opts.Bucket, opts.Key, func(w io.Writer) error {
+		if file != nil {
+			_, err := io.Copy(w, file)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := io.Copy(w, opts.Body)
+
+		return err
+	})
+}
+
+func (c *Client) DeleteObjects(_ context.Context, opts objcli.DeleteObjectsOptions) error {
+	objects := opts.AllObjects()
+
+	for _, object := range objects {
+		if object.VersionID != "" {
+			return objerr.ErrUnsupportedOperation
+		}
+	}
+
+	for _, object := range objects {
+		err := c.deleteObject(opts.Bucket, object.Key, false)
+		if err != nil {
+			return fmt.Errorf("failed to delete object '%s': %w", object.Key, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteDirectory(ctx context.Context, opts objcli.DeleteDirectoryOptions) error {
+	if opts.Include != nil && opts.Exclude != nil {
+		return objcli.ErrIncludeAndExcludeAreMutuallyExclusive
+	}
+
+	keys := make([]string, 0)
+
+	err := c.walk(ctx, c.bucketDir(opts.Bucket), opts.Prefix, func(key string, _ fs.FileInfo) error {
+		if !objcli.ShouldIgnore(key, opts.Include, opts.Exclude) {
+			keys = append(keys, key)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		err = c.deleteObject(opts.Bucket, key, opts.Versions)
+		if err != nil {
+			return fmt.Errorf("failed to delete object '%s': %w", key, err)
+		}
+	}
+
+	if !opts.Versions {
+		return nil
+	}
+
+	return c.deleteVersions(ctx, opts.Bucket, opts.Prefix, opts.Include, opts.Exclude)
+}
+
+func (c *Client) IterateObjects(ctx context.Context, opts objcli.IterateObjectsOptions) error {
+	if opts.Include != nil && opts.Exclude != nil {
+		return objcli.ErrIncludeAndExcludeAreMutuallyExclusive
+	}
+
+	err := c.checkBucket(opts.Bucket)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{})
+
+	fn := func(key string, info fs.FileInfo) error {
+		attrs := newObjectAttrs(key, info)
+
+		// Emulate the behavior of cloud providers, where keys containing the delimiter after the prefix are grouped
+		// into a single synthetic directory.
+		idx := strings.Index(strings.TrimPrefix(key, opts.Prefix), opts.Delimiter)
+		if opts.Delimiter != "" && idx != -1 {
+			attrs = &objval.ObjectAttrs{Key: key[:len(opts.Prefix)+idx+len(opts.Delimiter)]}
+		}
+
+		if _, ok := seen[attrs.Key]; ok || objcli.ShouldIgnore(attrs.Key, opts.Include, opts.Exclude) {
+			return nil
+		}
+
+		seen[attrs.Key] = struct{}{}
+
+		return opts.Func(attrs)
+	}
+
+	return c.walk(ctx, c.bucketDir(opts.Bucket), opts.Prefix, fn)
+}
+
+// ListDeletedObjects is unsupported, deleted objects are only retained when versioning is enabled.
+func (c *Client) ListDeletedObjects(
+	_ context.Context,
+	_ objcli.ListDeletedObjectsOptions,
+) ([]*objval.DeletedObject, error) {
+	return nil, objerr.ErrUnsupportedOperation
+}
+
+// UndeleteObject is unsupported, see 'ListDeletedObjects'.
+func (c *Client) UndeleteObject(_ context.Context, _ objcli.UndeleteObjectOptions) error {
+	return objerr.ErrUnsupportedOperation
+}
+
+func (c *Client) CreateMultipartUpload(_ context.Context, opts objcli.CreateMultipartUploadOptions) (string, error) {
+	if len(opts.Metadata) != 0 {
+		return "", objerr.ErrUnsupportedOperation
+	}
+
+	_, err := c.objectPath(opts.Bucket, opts.Key)
+	if err != nil {
+		return "", err
+	}
+
+	err = c.checkBucket(opts.Bucket)
+	if err != nil {
+		return "", err
+	}
+
+	id := uuid.NewString()
+
+	err = os.Mkdir(c.uploadDir(id), 0o755)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(c.uploadDir(id), uploadFile), []byte(uploadName(opts.Bucket, opts.Key)), 0o644)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	return id, nil
+}
+
+func (c *Client) ListParts(_ context.Context, opts objcli.ListPartsOptions) ([]objval.Part, error) {
+	dir, err := c.upload(opts.Bucket, opts.Key, opts.UploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list parts: %w", err)
+	}
+
+	parts := make([]objval.Part, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.Name() == uploadFile {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get part info: %w", err)
+		}
+
+		parts = append(parts, objval.Part{ID: entry.Name(), Size: info.Size()})
+	}
+
+	return parts, nil
+}
+
+func (c *Client) UploadPart(_ context.Context, opts objcli.UploadPartOptions) (objval.Part, error) {
+	return c.writePart(opts.Bucket, opts.Key, opts.UploadID, opts.Number, opts.Body)
+}
+
+func (c *Client) UploadPartCopy(_ context.Context, opts objcli.UploadPartCopyOptions) (objval.Part, error) {
+	if err := opts.ByteRange.Valid(false); err != nil {
+		return objval.Part{}, err // Purposefully not wrapped
+	}
+
+	file, info, err := c.open(opts.SourceBucket, opts.SourceKey)
+	if err != nil {
+		return objval.Part{}, err // Purposefully not wrapped
+	}
+	defer file.Close()
+
+	offset, length := int64(0), info.Size()
+	if opts.ByteRange != nil {
+		offset, length = opts.ByteRange.ToOffsetLength(info.Size() - opts.ByteRange.Start)
+	}
+
+	return c.writePart(
+		opts.DestinationBucket,
+		opts.DestinationKey,
+		opts.UploadID,
+		opts.Number,
+		io.NewSectionReader(file, offset, length),
+	)
+}
+
+func (c *Client) CompleteMultipartUpload(_ context.Context, opts objcli.CompleteMultipartUploadOptions) error {
+	if len(opts.Metadata) != 0 {
+		return objerr.ErrUnsupportedOperation
+	}
+
+	dir, err := c.upload(opts.Bucket, opts.Key, opts.UploadID)
+	if err != nil {
+		return err
+	}
+
+	err = c.write(opts.Bucket, opts.Key, func(w io.Writer) error {
+		for _, part := range opts.Parts {
+			err := copyFile(w, filepath.Join(dir, filepath.Base(part.ID)))
+			if err != nil {
+				return fmt.Errorf("failed to copy part %d: %w", part.Number, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(dir)
+}
+
+func (c *Client) AbortMultipartUpload(_ context.Context, opts objcli.AbortMultipartUploadOptions) error {
+	dir, err := c.upload(opts.Bucket, opts.Key, opts.UploadID)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(dir)
+}
+
+// ListMultipartUploads returns the uploads for objects in the given bucket, the upload is considered initiated when its
+// metadata file was written.
+func (c *Client) ListMultipartUploads(
+	_ context.Context,
+	opts objcli.ListMultipartUploadsOptions,
+) ([]objval.MultipartUpload, error) {
+	err := c.checkBucket(opts.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(c.root, metaDir, uploadsDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list uploads: %w", err)
+	}
+
+	uploads := make([]objval.MultipartUpload, 0)
+
+	for _, entry := range entries {
+		path := filepath.Join(c.uploadDir(entry.Name()), uploadFile)
+
+		name, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read upload: %w", err)
+		}
+
+		key, ok := strings.CutPrefix(string(name), opts.Bucket+"/")
+		if !ok || !strings.HasPrefix(key, opts.Prefix) {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get upload info: %w", err)
+		}
+
+		uploads = append(uploads, objval.MultipartUpload{Key: key, UploadID: entry.Name(), Initiated: info.ModTime()})
+	}
+
+	return uploads, nil
+}
+
+func (c *Client) CreateBucket(_ context.Context, opts objcli.CreateBucketOptions) error {
+	err := validBucket(opts.Bucket)
+	if err != nil {
+		return err
+	}
+
+	err = os.Mkdir(c.bucketDir(opts.Bucket), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create bucket: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteBucket(_ context.Context, opts objcli.DeleteBucketOptions) error {
+	err := c.checkBucket(opts.Bucket)
+	if err != nil {
+		return err
+	}
+
+	// Like cloud providers, non-current versions must be deleted before the bucket can be deleted
+	for _, dir := range []string{c.versionsDir(opts.Bucket), c.bucketDir(opts.Bucket)} {
+		err = os.Remove(dir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to delete bucket: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) GetBucketVersioning(
+	_ context.Context,
+	opts objcli.GetBucketVersioningOptions,
+) (objval.VersioningStatus, error) {
+	err := c.checkBucket(opts.Bucket)
+	if err != nil {
+		return "", err
+	}
+
+	if c.versioning {
+		return objval.VersioningStatusEnabled, nil
+	}
+
+	return objval.VersioningStatusDisabled, nil
+}
+
+func (c *Client) GetBucketRegion(_ context.Context, _ objcli.GetBucketRegionOptions) (string, error) {
+	return "", objerr.ErrUnsupportedOperation
+}
+
+func (c *Client) SetObjectLock(_ context.Context, _ objcli.SetObjectLockOptions) error {
+	return objerr.ErrUnsupportedOperation
+}
+
+func (c *Client) GetBucketLockingStatus(
+	_ context.Context,
+	_ objcli.GetBucketLockingStatusOptions,
+) (objval.BucketLockingStatus, error) {
+	return objval.BucketLockingStatus{}, objerr.ErrUnsupportedOperation
+}
+
+func (c *Client) QueryObject(_ context.Context, _ objcli.QueryObjectOptions) (io.ReadCloser, error) {
+	return nil, objerr.ErrUnsupportedOperation
+}
+
+// Close is a no-op for the filesystem client as there are no resources to release.
+func (c *Client) Close() error {
+	return nil
+}
+
+// open opens the object with the given key for reading.
+func (c *Client) open(bucket, key string) (*os.File, fs.FileInfo, error) {
+	path, err := c.objectPath(bucket, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, c.handleError(bucket, key, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return nil, nil, c.handleError(bucket, key, err)
+	}
+
+	return file, info, nil
+}
+
+// write atomically creates/replaces the object with the given key, with the data written by the given function.
+func (c *Client) write(bucket, key string, fn func(w io.Writer) error) error {
+	path, err := c.objectPath(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkBucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Join(c.root, metaDir, tmpDir), "")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	err = fn(file)
+	if err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	err = c.archive(bucket, key, path)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return fmt.Errorf("failed to move object into place: %w", err)
+	}
+
+	return nil
+}
+
+// writePart writes a new part for the given upload.
+func (c *Client) writePart(bucket, key, id string, number int, body io.Reader) (objval.Part, error) {
+	dir, err := c.upload(bucket, key, id)
+	if err != nil {
+		return objval.Part{}, err
+	}
+
+	part := objval.Part{ID: uuid.NewString(), Number: number}
+
+	file, err := os.Create(filepath.Join(dir, part.ID))
+	if err != nil {
+		return objval.Part{}, fmt.Errorf("failed to create part: %w", err)
+	}
+	defer file.Close()
+
+	part.Size, err = io.Copy(file, body)
+	if err != nil {
+		return objval.Part{}, fmt.Errorf("failed to write part: %w", err)
+	}
+
+	return part, file.Close()
+}
+
+// upload returns the directory for the given upload, ensuring it exists and belongs to the given bucket/key.
+func (c *Client) upload(bucket, key, id string) (string, error) {
+	if err := uuid.Validate(id); err != nil {
+		return "", &objerr.NotFoundError{Type: "upload", Name: id}
+	}
+
+	dir := c.uploadDir(id)
+
+	name, err := os.ReadFile(filepath.Join(dir, uploadFile))
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && string(name) != uploadName(bucket, key)) {
+		return "", &objerr.NotFoundError{Type: "upload", Name: id}
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to read upload: %w", err)
+	}
+
+	return dir, nil
+}
+
+// deleteObject deletes the given object, retaining it as a non-current version if versioning is enabled and all
+// versions aren't being deleted.
+func (c *Client) deleteObject(bucket, key string, versions bool) error {
+	path, err := c.objectPath(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	if c.versioning && !versions {
+		err = c.archive(bucket, key, path)
+	} else {
+		err = os.Remove(path)
+	}
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	prune(c.bucketDir(bucket), filepath.Dir(path))
+
+	return nil
+}
+
+// deleteVersions deletes all the non-current versions of objects with the given prefix, which aren't ignored by the
+// given include/exclude filters.
+func (c *Client) deleteVersions(
+	ctx context.Context,
+	bucket, prefix string,
+	include, exclude []*regexp.Regexp,
+) error {
+	paths := make([]string, 0)
+
+	err := c.walk(ctx, c.versionsDir(bucket), prefix, func(key string, _ fs.FileInfo) error {
+		if !objcli.ShouldIgnore(versionedKey(key), include, exclude) {
+			paths = append(paths, filepath.Join(c.versionsDir(bucket), filepath.FromSlash(key)))
+		}
+
+		return nil
+	})
+	if err != nil && !objerr.IsNotFoundError(err) {
+		return err
+	}
+
+	for _, path := range paths {
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to delete version: %w", err)
+		}
+
+		prune(c.versionsDir(bucket), filepath.Dir(path))
+	}
+
+	return nil
+}
+
+// archive moves the current version of the given object into the versions directory, this is a no-op if versioning is
+// disabled or the object doesn't exist.
+func (c *Client) archive(bucket, key, path string) error {
+	if !c.versioning {
+		return nil
+	}
+
+	_, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to stat object: %w", err)
+	}
+
+	version := filepath.Join(c.versionsDir(bucket), filepath.FromSlash(versionKey(key, time.Now())))
+
+	err = os.MkdirAll(filepath.Dir(version), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create version directory: %w", err)
+	}
+
+	err = os.Rename(path, version)
+	if err != nil {
+		return fmt.Errorf("failed to archive object: %w", err)
+	}
+
+	return nil
+}
+
+// walk runs the given function for each file in the given directory whose key has the given prefix, in lexical order.
+func (c *Client) walk(ctx context.Context, root, prefix string, fn func(key string, info fs.FileInfo) error) error {
+	_, err := os.Stat(root)
+	if err != nil {
+		return c.handleError(filepath.Base(root), "", err)
+	}
+
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+
+		// Avoid walking directories which can't contain any matching keys
+		if entry.IsDir() {
+			if !strings.HasPrefix(key+"/", prefix) && !strings.HasPrefix(prefix, key+"/") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return fn(key, info)
+	})
+}
+
+// checkBucket returns an error if the given bucket doesn't exist.
+func (c *Client) checkBucket(bucket string) error {
+	err := validBucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(c.bucketDir(bucket))
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && !info.IsDir()) {
+		return &objerr.NotFoundError{Type: "bucket", Name: bucket}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to stat bucket: %w", err)
+	}
+
+	return nil
+}
+
+// handleError converts the given error, returned when accessing the given object, into a more useful error.
+func (c *Client) handleError(bucket, key string, err error) error {
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if err := c.checkBucket(bucket); err != nil {
+		return err
+	}
+
+	return &objerr.NotFoundError{Type: "key", Name: key}
+}
+
+// bucketDir returns the directory for the given bucket.
+func (c *Client) bucketDir(bucket string) string {
+	return filepath.Join(c.root, bucket)
+}
+
+// versionsDir returns the directory used to store non-current versions for the given bucket.
+func (c *Client) versionsDir(bucket string) string {
+	return filepath.Join(c.root, metaDir, versionsDir, bucket)
+}
+
+// uploadDir returns the directory used to store the parts for the given upload.
+func (c *Client) uploadDir(id string) string {
+	return filepath.Join(c.root, metaDir, uploadsDir, id)
+}
+
+// objectPath returns the path for the object with the given key.
+func (c *Client) objectPath(bucket, key string) (string, error) {
+	err := validBucket(bucket)
+	if err != nil {
+		return "", err
+	}
+
+	err = validKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(c.bucketDir(bucket), filepath.FromSlash(key)), nil
+}
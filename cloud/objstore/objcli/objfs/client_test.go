@@ -0,0 +1,645 @@
+package objfs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+func newTestClient(t *testing.T, versioning bool) *Client {
+	client, err := NewClient(ClientOptions{Root: t.TempDir(), Versioning: versioning})
+	require.NoError(t, err)
+
+	err = client.CreateBucket(context.Background(), objcli.CreateBucketOptions{Bucket: "bucket"})
+	require.NoError(t, err)
+
+	return client
+}
+
+func putObject(t *testing.T, client *Client, key, body string) {
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket: "bucket",
+		Key:    key,
+		Body:   bytes.NewReader([]byte(body)),
+	})
+	require.NoError(t, err)
+}
+
+func getObject(t *testing.T, client *Client, key string, br *objval.ByteRange) string {
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:    "bucket",
+		Key:       key,
+		ByteRange: br,
+	})
+	require.NoError(t, err)
+
+	defer object.Body.Close()
+
+	data, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(data)), ptr.From(object.Size))
+
+	return string(data)
+}
+
+func listObjects(t *testing.T, client *Client, opts objcli.IterateObjectsOptions) []string {
+	keys := make([]string, 0)
+
+	opts.Bucket = "bucket"
+	opts.Func = func(attrs *objval.ObjectAttrs) error {
+		keys = append(keys, attrs.Key)
+		return nil
+	}
+
+	err := client.IterateObjects(context.Background(), opts)
+	require.NoError(t, err)
+
+	return keys
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient(ClientOptions{})
+	require.ErrorIs(t, err, ErrRootRequired)
+
+	root := filepath.Join(t.TempDir(), "root")
+
+	client, err := NewClient(ClientOptions{Root: root})
+	require.NoError(t, err)
+	require.Equal(t, objval.ProviderNone, client.Provider())
+	require.True(t, client.Capabilities().SupportsCompose)
+	require.DirExists(t, filepath.Join(root, metaDir, uploadsDir))
+}
+
+func TestClientPutGetObject(t *testing.T) {
+	client := newTestClient(t, false)
+
+	putObject(t, client, "path/to/key", "value")
+	require.Equal(t, "value", getObject(t, client, "path/to/key", nil))
+	require.FileExists(t, filepath.Join(client.root, "bucket", "path", "to", "key"))
+
+	putObject(t, client, "path/to/key", "overwritten")
+	require.Equal(t, "overwritten", getObject(t, client, "path/to/key", nil))
+
+	attrs, err := client.GetObjectAttrs(context.Background(), objcli.GetObjectAttrsOptions{
+		Bucket: "bucket",
+		Key:    "path/to/key",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "path/to/key", attrs.Key)
+	require.Equal(t, int64(len("overwritten")), ptr.From(attrs.Size))
+	require.NotNil(t, attrs.ETag)
+	require.NotNil(t, attrs.LastModified)
+}
+
+func TestClientPutGetObjectCompressed(t *testing.T) {
+	client := newTestClient(t, false)
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket:   "bucket",
+		Key:      "key.gz",
+		Body:     bytes.NewReader([]byte("value")),
+		Compress: objcli.CompressionGZIP,
+	})
+	require.NoError(t, err)
+
+	require.NotEqual(t, "value", getObject(t, client, "key.gz", nil))
+
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:     "bucket",
+		Key:        "key.gz",
+		Decompress: true,
+	})
+	require.NoError(t, err)
+
+	defer object.Body.Close()
+
+	data, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+	require.Equal(t, "value", string(data))
+}
+
+func TestClientGetObjectRange(t *testing.T) {
+	client := newTestClient(t, false)
+
+	putObject(t, client, "key", "0123456789")
+
+	require.Equal(t, "234", getObject(t, client, "key", &objval.ByteRange{Start: 2, End: 4}))
+	require.Equal(t, "56789", getObject(t, client, "key", &objval.ByteRange{Start: 5}))
+	require.Equal(t, "89", getObject(t, client, "key", &objval.ByteRange{Start: 8, End: 100}))
+
+	_, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:    "bucket",
+		Key:       "key",
+		ByteRange: &objval.ByteRange{Start: 10},
+	})
+
+	var invalid *objval.InvalidByteRangeError
+
+	require.ErrorAs(t, err, &invalid)
+}
+
+func TestClientGetObjectConditional(t *testing.T) {
+	client := newTestClient(t, false)
+
+	putObject(t, client, "key", "value")
+
+	attrs, err := client.GetObjectAttrs(context.Background(), objcli.GetObjectAttrsOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+
+	_, err = client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:      "bucket",
+		Key:         "key",
+		IfNoneMatch: *attrs.ETag,
+	})
+	require.ErrorIs(t, err, objerr.ErrNotModified)
+
+	_, err = client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:          "bucket",
+		Key:             "key",
+		IfModifiedSince: attrs.LastModified.Add(time.Second),
+	})
+	require.ErrorIs(t, err, objerr.ErrNotModified)
+
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:          "bucket",
+		Key:             "key",
+		IfModifiedSince: attrs.LastModified.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.NoError(t, object.Body.Close())
+}
+
+func TestClientGetObjectNotFound(t *testing.T) {
+	client := newTestClient(t, false)
+
+	_, err := client.GetObject(context.Background(), objcli.GetObjectOptions{Bucket: "bucket", Key: "key"})
+	require.Equal(t, &objerr.NotFoundError{Type: "key", Name: "key"}, err)
+
+	_, err = client.GetObject(context.Background(), objcli.GetObjectOptions{Bucket: "missing", Key: "key"})
+	require.Equal(t, &objerr.NotFoundError{Type: "bucket", Name: "missing"}, err)
+
+	putObject(t, client, "dir/key", "value")
+
+	_, err = client.GetObjectAttrs(context.Background(), objcli.GetObjectAttrsOptions{Bucket: "bucket", Key: "dir"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestClientInvalidKey(t *testing.T) {
+	client := newTestClient(t, false)
+
+	for _, key := range []string{"", "../key", "dir/../../key", "dir//key", "dir/", `dir\key`} {
+		err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+			Bucket: "bucket",
+			Key:    key,
+			Body:   bytes.NewReader(nil),
+		})
+		require.ErrorIs(t, err, ErrInvalidKey)
+	}
+
+	for _, bucket := range []string{"", ".objfs", "..", "a/b"} {
+		err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+			Bucket: bucket,
+			Key:    "key",
+			Body:   bytes.NewReader(nil),
+		})
+		require.ErrorIs(t, err, ErrInvalidBucket)
+	}
+}
+
+func TestClientMetadataUnsupported(t *testing.T) {
+	var (
+		client   = newTestClient(t, false)
+		metadata = map[string]string{"key": "value"}
+	)
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket:   "bucket",
+		Key:      "key",
+		Body:     bytes.NewReader(nil),
+		Metadata: metadata,
+	})
+	require.ErrorIs(t, err, objerr.ErrUnsupportedOperation)
+
+	_, err = client.CreateMultipartUpload(context.Background(), objcli.CreateMultipartUploadOptions{
+		Bucket:   "bucket",
+		Key:      "key",
+		Metadata: metadata,
+	})
+	require.ErrorIs(t, err, objerr.ErrUnsupportedOperation)
+
+	err = client.CompleteMultipartUpload(context.Background(), objcli.CompleteMultipartUploadOptions{
+		Bucket:   "bucket",
+		Key:      "key",
+		UploadID: "id",
+		Metadata: metadata,
+	})
+	require.ErrorIs(t, err, objerr.ErrUnsupportedOperation)
+}
+
+func TestClientCopyObject(t *testing.T) {
+	client := newTestClient(t, false)
+
+	putObject(t, client, "src", "value")
+
+	err := client.CopyObject(context.Background(), objcli.CopyObjectOptions{
+		DestinationBucket: "bucket",
+		DestinationKey:    "dst/key",
+		SourceBucket:      "bucket",
+		SourceKey:         "src",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "value", getObject(t, client, "dst/key", nil))
+
+	err = client.CopyObject(context.Background(), objcli.CopyObjectOptions{
+		DestinationBucket: "bucket",
+		DestinationKey:    "dst/key",
+		SourceBucket:      "bucket",
+		SourceKey:         "missing",
+	})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestClientAppendToObject(t *testing.T) {
+	for _, versioning := range []bool{false, true} {
+		client := newTestClient(t, versioning)
+
+		for _, data := range []string{"hello", ", ", "world"} {
+			err := client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
+				Bucket: "bucket",
+				Key:    "key",
+				Body:   bytes.NewReader([]byte(data)),
+			})
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, "hello, world", getObject(t, client, "key", nil))
+	}
+}
+
+func TestClientDeleteObjects(t *testing.T) {
+	client := newTestClient(t, false)
+
+	putObject(t, client, "dir/nested/key1", "value")
+	putObject(t, client, "dir/key2", "value")
+
+	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
+		Bucket: "bucket",
+		Keys:   []string{"dir/nested/key1", "missing"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"dir/key2"}, listObjects(t, client, objcli.IterateObjectsOptions{}))
+
+	// Empty directories should have been cleaned up
+	require.NoDirExists(t, filepath.Join(client.root, "bucket", "dir", "nested"))
+}
+
+func TestClientDeleteObjectsWithVersions(t *testing.T) {
+	client := newTestClient(t, false)
+
+	putObject(t, client, "key", "value")
+
+	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
+		Bucket:  "bucket",
+		Objects: []objval.ObjectVersion{{Key: "key", VersionID: "version"}},
+	})
+	require.ErrorIs(t, err, objerr.ErrUnsupportedOperation)
+
+	// No objects should be deleted, when any of the versions are unsupported
+	require.Equal(t, []string{"key"}, listObjects(t, client, objcli.IterateObjectsOptions{}))
+}
+
+func TestClientDeleteDirectory(t *testing.T) {
+	client := newTestClient(t, false)
+
+	for _, key := range []string{"dir1/key1", "dir1/nested/key2", "dir10/key3", "dir2/key4"} {
+		putObject(t, client, key, "value")
+	}
+
+	err := client.DeleteDirectory(context.Background(), objcli.DeleteDirectoryOptions{
+		Bucket: "bucket",
+		Prefix: "dir1/",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"dir10/key3", "dir2/key4"}, listObjects(t, client, objcli.IterateObjectsOptions{}))
+}
+
+func TestClientDeleteDirectoryWithFilters(t *testing.T) {
+	client := newTestClient(t, false)
+
+	for _, key := range []string{"dir/key1.tmp", "dir/key2", "dir/nested/key3.tmp"} {
+		putObject(t, client, key, "value")
+	}
+
+	err := client.DeleteDirectory(context.Background(), objcli.DeleteDirectoryOptions{
+		Bucket:  "bucket",
+		Prefix:  "dir/",
+		Include: []*regexp.Regexp{regexp.MustCompile(`\.tmp$`)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"dir/key2"}, listObjects(t, client, objcli.IterateObjectsOptions{}))
+
+	err = client.DeleteDirectory(context.Background(), objcli.DeleteDirectoryOptions{
+		Bucket:  "bucket",
+		Include: []*regexp.Regexp{regexp.MustCompile(`.*`)},
+		Exclude: []*regexp.Regexp{regexp.MustCompile(`.*`)},
+	})
+	require.ErrorIs(t, err, objcli.ErrIncludeAndExcludeAreMutuallyExclusive)
+}
+
+func TestClientIterateObjects(t *testing.T) {
+	client := newTestClient(t, false)
+
+	for _, key := range []string{"a/b/c", "a/b/d", "a/e", "a/f.json", "g"} {
+		putObject(t, client, key, "value")
+	}
+
+	type test struct {
+		name     string
+		opts     objcli.IterateObjectsOptions
+		expected []string
+	}
+
+	tests := []test{
+		{
+			name:     "All",
+			expected: []string{"a/b/c", "a/b/d", "a/e", "a/f.json", "g"},
+		},
+		{
+			name:     "Prefix",
+			opts:     objcli.IterateObjectsOptions{Prefix: "a/b"},
+			expected: []string{"a/b/c", "a/b/d"},
+		},
+		{
+			name:     "Delimiter",
+			opts:     objcli.IterateObjectsOptions{Delimiter: "/"},
+			expected: []string{"a/", "g"},
+		},
+		{
+			name:     "PrefixAndDelimiter",
+			opts:     objcli.IterateObjectsOptions{Prefix: "a/", Delimiter: "/"},
+			expected: []string{"a/b/", "a/e", "a/f.json"},
+		},
+		{
+			name:     "Include",
+			opts:     objcli.IterateObjectsOptions{Include: []*regexp.Regexp{regexp.MustCompile(`\.json$`)}},
+			expected: []string{"a/f.json"},
+		},
+		{
+			name:     "Exclude",
+			opts:     objcli.IterateObjectsOptions{Exclude: []*regexp.Regexp{regexp.MustCompile(`^a/`)}},
+			expected: []string{"g"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, listObjects(t, client, test.opts))
+		})
+	}
+}
+
+func TestClientIterateObjectsBucketNotFound(t *testing.T) {
+	client := newTestClient(t, false)
+
+	err := client.IterateObjects(context.Background(), objcli.IterateObjectsOptions{
+		Bucket: "missing",
+		Func:   func(_ *objval.ObjectAttrs) error { return nil },
+	})
+	require.Equal(t, &objerr.NotFoundError{Type: "bucket", Name: "missing"}, err)
+}
+
+func TestClientMultipartUpload(t *testing.T) {
+	client := newTestClient(t, false)
+
+	putObject(t, client, "src", "0123456789")
+
+	id, err := client.CreateMultipartUpload(context.Background(), objcli.CreateMultipartUploadOptions{
+		Bucket: "bucket",
+		Key:    "key",
+	})
+	require.NoError(t, err)
+
+	part1, err := client.UploadPart(context.Background(), objcli.UploadPartOptions{
+		Bucket:   "bucket",
+		UploadID: id,
+		Key:      "key",
+		Number:   1,
+		Body:     bytes.NewReader([]byte("hello, ")),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(7), part1.Size)
+
+	part2, err := client.UploadPartCopy(context.Background(), objcli.UploadPartCopyOptions{
+		DestinationBucket: "bucket",
+		UploadID:          id,
+		DestinationKey:    "key",
+		SourceBucket:      "bucket",
+		SourceKey:         "src",
+		Number:            2,
+		ByteRange:         &objval.ByteRange{Start: 2, End: 4},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), part2.Size)
+
+	parts, err := client.ListParts(context.Background(), objcli.ListPartsOptions{
+		Bucket:   "bucket",
+		UploadID: id,
+		Key:      "key",
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(
+		t,
+		[]objval.Part{{ID: part1.ID, Size: part1.Size}, {ID: part2.ID, Size: part2.Size}},
+		parts,
+	)
+
+	// Parts may only be listed using the key they were uploaded for
+	_, err = client.ListParts(context.Background(), objcli.ListPartsOptions{
+		Bucket:   "bucket",
+		UploadID: id,
+		Key:      "other",
+	})
+	require.Equal(t, &objerr.NotFoundError{Type: "upload", Name: id}, err)
+
+	err = client.CompleteMultipartUpload(context.Background(), objcli.CompleteMultipartUploadOptions{
+		Bucket:   "bucket",
+		UploadID: id,
+		Key:      "key",
+		Parts:    []objval.Part{part2, part1},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "234hello, ", getObject(t, client, "key", nil))
+	require.NoDirExists(t, client.uploadDir(id))
+}
+
+func TestClientListMultipartUploads(t *testing.T) {
+	client := newTestClient(t, false)
+
+	err := client.CreateBucket(context.Background(), objcli.CreateBucketOptions{Bucket: "other"})
+	require.NoError(t, err)
+
+	create := func(bucket, key string) string {
+		id, err := client.CreateMultipartUpload(context.Background(), objcli.CreateMultipartUploadOptions{
+			Bucket: bucket,
+			Key:    key,
+		})
+		require.NoError(t, err)
+
+		return id
+	}
+
+	id := create("bucket", "prefix/key")
+	create("bucket", "key")
+	create("other", "prefix/key")
+
+	uploads, err := client.ListMultipartUploads(context.Background(), objcli.ListMultipartUploadsOptions{
+		Bucket: "bucket",
+		Prefix: "prefix/",
+	})
+	require.NoError(t, err)
+	require.Len(t, uploads, 1)
+	require.Equal(t, "prefix/key", uploads[0].Key)
+	require.Equal(t, id, uploads[0].UploadID)
+	require.False(t, uploads[0].Initiated.IsZero())
+
+	err = client.AbortMultipartUpload(context.Background(), objcli.AbortMultipartUploadOptions{
+		Bucket:   "bucket",
+		UploadID: id,
+		Key:      "prefix/key",
+	})
+	require.NoError(t, err)
+
+	uploads, err = client.ListMultipartUploads(context.Background(), objcli.ListMultipartUploadsOptions{
+		Bucket: "bucket",
+		Prefix: "prefix/",
+	})
+	require.NoError(t, err)
+	require.Empty(t, uploads)
+}
+
+func TestClientAbortMultipartUpload(t *testing.T) {
+	client := newTestClient(t, false)
+
+	id, err := client.CreateMultipartUpload(context.Background(), objcli.CreateMultipartUploadOptions{
+		Bucket: "bucket",
+		Key:    "key",
+	})
+	require.NoError(t, err)
+
+	_, err = client.UploadPart(context.Background(), objcli.UploadPartOptions{
+		Bucket:   "bucket",
+		UploadID: id,
+		Key:      "key",
+		Number:   1,
+		Body:     bytes.NewReader([]byte("value")),
+	})
+	require.NoError(t, err)
+
+	err = client.AbortMultipartUpload(context.Background(), objcli.AbortMultipartUploadOptions{
+		Bucket:   "bucket",
+		UploadID: id,
+		Key:      "key",
+	})
+	require.NoError(t, err)
+	require.NoDirExists(t, client.uploadDir(id))
+
+	_, err = client.UploadPart(context.Background(), objcli.UploadPartOptions{
+		Bucket:   "bucket",
+		UploadID: id,
+		Key:      "key",
+		Number:   2,
+		Body:     bytes.NewReader([]byte("value")),
+	})
+	require.True(t, objerr.IsNotFoundError(err))
+
+	_, err = client.ListParts(context.Background(), objcli.ListPartsOptions{
+		Bucket:   "bucket",
+		UploadID: "../../bucket",
+		Key:      "key",
+	})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestClientBuckets(t *testing.T) {
+	client := newTestClient(t, false)
+
+	err := client.CreateBucket(context.Background(), objcli.CreateBucketOptions{Bucket: "bucket"})
+	require.ErrorIs(t, err, os.ErrExist)
+
+	status, err := client.GetBucketVersioning(
+		context.Background(),
+		objcli.GetBucketVersioningOptions{Bucket: "bucket"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, objval.VersioningStatusDisabled, status)
+
+	_, err = client.GetBucketRegion(context.Background(), objcli.GetBucketRegionOptions{Bucket: "bucket"})
+	require.ErrorIs(t, err, objerr.ErrUnsupportedOperation)
+
+	_, err = client.GetObjectTags(context.Background(), objcli.GetObjectTagsOptions{Bucket: "bucket", Key: "key"})
+	require.ErrorIs(t, err, objerr.ErrUnsupportedOperation)
+
+	putObject(t, client, "key", "value")
+
+	err = client.DeleteBucket(context.Background(), objcli.DeleteBucketOptions{Bucket: "bucket"})
+	require.Error(t, err)
+
+	err = client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{Bucket: "bucket", Keys: []string{"key"}})
+	require.NoError(t, err)
+
+	err = client.DeleteBucket(context.Background(), objcli.DeleteBucketOptions{Bucket: "bucket"})
+	require.NoError(t, err)
+
+	err = client.DeleteBucket(context.Background(), objcli.DeleteBucketOptions{Bucket: "bucket"})
+	require.Equal(t, &objerr.NotFoundError{Type: "bucket", Name: "bucket"}, err)
+}
+
+func TestClientVersioning(t *testing.T) {
+	client := newTestClient(t, true)
+
+	status, err := client.GetBucketVersioning(
+		context.Background(),
+		objcli.GetBucketVersioningOptions{Bucket: "bucket"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, objval.VersioningStatusEnabled, status)
+
+	putObject(t, client, "dir/key", "v1")
+	putObject(t, client, "dir/key", "v2")
+
+	err = client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
+		Bucket: "bucket",
+		Keys:   []string{"dir/key"},
+	})
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(client.versionsDir("bucket"), "dir"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// Non-current versions must be removed before the bucket can be deleted
+	err = client.DeleteBucket(context.Background(), objcli.DeleteBucketOptions{Bucket: "bucket"})
+	require.Error(t, err)
+
+	err = client.DeleteDirectory(context.Background(), objcli.DeleteDirectoryOptions{
+		Bucket:   "bucket",
+		Versions: true,
+	})
+	require.NoError(t, err)
+	require.NoDirExists(t, filepath.Join(client.versionsDir("bucket"), "dir"))
+
+	err = client.DeleteBucket(context.Background(), objcli.DeleteBucketOptions{Bucket: "bucket"})
+	require.NoError(t, err)
+}
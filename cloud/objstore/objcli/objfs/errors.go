@@ -0,0 +1,16 @@
+package objfs
+
+import "errors"
+
+var (
+	// ErrRootRequired is returned when attempting to create a client without providing a root directory.
+	ErrRootRequired = errors.New("a root directory is required")
+
+	// ErrInvalidBucket is returned when a bucket name can't be safely mapped to a directory, for example because it
+	// contains a path separator.
+	ErrInvalidBucket = errors.New("invalid bucket name")
+
+	// ErrInvalidKey is returned when a key can't be safely mapped to a path within a bucket, for example because it
+	// contains '..' elements which would escape the bucket.
+	ErrInvalidKey = errors.New("invalid key")
+)
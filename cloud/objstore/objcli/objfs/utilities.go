@@ -0,0 +1,104 @@
+package objfs
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// readCloser combines a reader with the closer for the underlying file.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// newObjectAttrs returns the attributes for the object with the given key/file info.
+//
+// NOTE: The entity tag is derived from the modification time/size, rather than the contents of the object.
+func newObjectAttrs(key string, info fs.FileInfo) *objval.ObjectAttrs {
+	return &objval.ObjectAttrs{
+		Key:          key,
+		ETag:         ptr.To(fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size())),
+		Size:         ptr.To(info.Size()),
+		LastModified: ptr.To(info.ModTime()),
+	}
+}
+
+// validBucket returns an error if the given bucket name can't be used as a directory name.
+func validBucket(bucket string) error {
+	if bucket == "" || strings.HasPrefix(bucket, ".") || strings.ContainsAny(bucket, `/\`) {
+		return fmt.Errorf("%w '%s'", ErrInvalidBucket, bucket)
+	}
+
+	return nil
+}
+
+// validKey returns an error if the given key can't be mapped to a path within a bucket.
+func validKey(key string) error {
+	if key == "" || strings.Contains(key, `\`) {
+		return fmt.Errorf("%w '%s'", ErrInvalidKey, key)
+	}
+
+	for _, elem := range strings.Split(key, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return fmt.Errorf("%w '%s'", ErrInvalidKey, key)
+		}
+	}
+
+	return nil
+}
+
+// uploadName returns the name stored for an upload, used to ensure parts are only uploaded to the correct upload.
+func uploadName(bucket, key string) string {
+	return path.Join(bucket, key)
+}
+
+// versionKey returns the key used to store a non-current version of the given object, the suffix is the time at which
+// the version became non-current.
+func versionKey(key string, archived time.Time) string {
+	return fmt.Sprintf("%s.%020d", key, archived.UnixNano())
+}
+
+// versionedKey returns the key of the object which the given version key belongs to, this is the inverse of
+// 'versionKey'.
+func versionedKey(version string) string {
+	idx := strings.LastIndex(version, ".")
+	if idx == -1 {
+		return version
+	}
+
+	return version[:idx]
+}
+
+// copyFile copies the contents of the file at the given path to the given writer.
+func copyFile(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+
+	return err
+}
+
+// prune removes empty directories, starting at the given directory and working upwards until the given stop directory
+// is reached.
+func prune(stop, dir string) {
+	for dir != stop && strings.HasPrefix(dir, stop+string(filepath.Separator)) {
+		if os.Remove(dir) != nil {
+			return
+		}
+
+		dir = filepath.Dir(dir)
+	}
+}
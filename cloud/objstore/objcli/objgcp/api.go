@@ -18,10 +18,20 @@ type serviceAPI interface {
 // serviceClient implements the 'storageAPI' interface and encapsulates the Google SDK into a unit testable interface.
 type serviceClient struct {
 	c *storage.Client
+
+	// userProject is the project billed for requests, when non-empty it's propagated to every bucket handle (and
+	// therefore every object handle) created by the client.
+	userProject string
 }
 
 func (s serviceClient) Bucket(name string) bucketAPI {
-	return bucketHandle{h: s.c.Bucket(name)}
+	handle := s.c.Bucket(name)
+
+	if s.userProject != "" {
+		handle = handle.UserProject(s.userProject)
+	}
+
+	return bucketHandle{h: handle}
 }
 
 func (s serviceClient) Close() error {
@@ -30,6 +40,9 @@ func (s serviceClient) Close() error {
 
 // bucketAPI is a bucket level interface which allows interactions with a Google Storage bucket.
 type bucketAPI interface {
+	Attrs(ctx context.Context) (*storage.BucketAttrs, error)
+	Create(ctx context.Context, projectID string, attrs *storage.BucketAttrs) error
+	Delete(ctx context.Context) error
 	Object(key string) objectAPI
 	Objects(ctx context.Context, query *storage.Query) objectIteratorAPI
 }
@@ -40,6 +53,18 @@ type bucketHandle struct {
 	h *storage.BucketHandle
 }
 
+func (b bucketHandle) Attrs(ctx context.Context) (*storage.BucketAttrs, error) {
+	return b.h.Attrs(ctx)
+}
+
+func (b bucketHandle) Create(ctx context.Context, projectID string, attrs *storage.BucketAttrs) error {
+	return b.h.Create(ctx, projectID, attrs)
+}
+
+func (b bucketHandle) Delete(ctx context.Context) error {
+	return b.h.Delete(ctx)
+}
+
 func (b bucketHandle) Object(key string) objectAPI {
 	return objectHandle{h: b.h.Object(key)}
 }
@@ -58,6 +83,7 @@ type objectAPI interface {
 	CopierFrom(src objectAPI) copierAPI
 	Retryer(opts ...storage.RetryOption) objectAPI
 	Generation(gen int64) objectAPI
+	Update(ctx context.Context, attrs storage.ObjectAttrsToUpdate) (*storage.ObjectAttrs, error)
 }
 
 // objectHandle implements the 'objectAPI' interface and encapsulates the Google Storage SDK into a unit testable
@@ -126,6 +152,10 @@ func (o objectHandle) Generation(gen int64) objectAPI {
 	return objectHandle{h: o.h.Generation(gen)}
 }
 
+func (o objectHandle) Update(ctx context.Context, attrs storage.ObjectAttrsToUpdate) (*storage.ObjectAttrs, error) {
+	return o.h.Update(ctx, attrs)
+}
+
 // readerAPI is a range aware reader API which is used to stream object data from Google Storage.
 type readerAPI interface {
 	io.ReadCloser
@@ -154,6 +184,8 @@ type writerAPI interface {
 	io.WriteCloser
 	SendMD5(md5 []byte)
 	SendCRC(crc uint32)
+	SetMetadata(metadata map[string]string)
+	SetContentEncoding(encoding string)
 }
 
 // writer implements the 'writerAPI' and encapsulates the Google Storage SDK into a unit testable interface.
@@ -178,6 +210,14 @@ func (w writer) SendCRC(crc uint32) {
 	w.w.ObjectAttrs.CRC32C = crc
 }
 
+func (w writer) SetMetadata(metadata map[string]string) {
+	w.w.ObjectAttrs.Metadata = metadata
+}
+
+func (w writer) SetContentEncoding(encoding string) {
+	w.w.ObjectAttrs.ContentEncoding = encoding
+}
+
 // objectIteratorAPI is an object level iterator API which can be used to list objects in Google Storage.
 type objectIteratorAPI interface {
 	Next() (*storage.ObjectAttrs, error)
@@ -190,6 +230,7 @@ type objectIteratorAPI interface {
 // a way which would work in a way which we'd desire. For example, no API is exposed to save/maintain upload state to
 // allow resuming after a process has died (required for resume).
 type composeAPI interface {
+	SetMetadata(metadata map[string]string)
 	Run(ctx context.Context) (*storage.ObjectAttrs, error)
 }
 
@@ -198,6 +239,10 @@ type composer struct {
 	c *storage.Composer
 }
 
+func (c composer) SetMetadata(metadata map[string]string) {
+	c.c.ObjectAttrs.Metadata = metadata
+}
+
 func (c composer) Run(ctx context.Context) (*storage.ObjectAttrs, error) {
 	return c.c.Run(ctx)
 }
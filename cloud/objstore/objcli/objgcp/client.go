@@ -9,7 +9,10 @@ import (
 	"hash/crc32"
 	"io"
 	"log/slog"
+	"math"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
@@ -34,6 +37,7 @@ type attrs struct {
 // Client implements the 'objcli.Client' interface allowing the creation/management of objects stored in Google Storage.
 type Client struct {
 	serviceAPI serviceAPI
+	projectID  string
 	logger     *slog.Logger
 }
 
@@ -48,6 +52,17 @@ type ClientOptions struct {
 
 	// Logger is the passed logger which implements a custom Log method
 	Logger *slog.Logger
+
+	// UserProject is the project which will be billed for all the operations performed by the client, this is required
+	// when accessing requester pays buckets.
+	//
+	// NOTE: The user must have the 'serviceusage.services.use' permission for the given project.
+	UserProject string
+
+	// ProjectID is the project in which buckets will be created.
+	//
+	// NOTE: Only required when using 'CreateBucket'.
+	ProjectID string
 }
 
 // defaults fills any missing attributes to a sane default.
@@ -64,7 +79,8 @@ func NewClient(options ClientOptions) *Client {
 	options.defaults()
 
 	client := Client{
-		serviceAPI: serviceClient{options.Client},
+		serviceAPI: serviceClient{c: options.Client, userProject: options.UserProject},
+		projectID:  options.ProjectID,
 		logger:     options.Logger,
 	}
 
@@ -75,6 +91,18 @@ func (c *Client) Provider() objval.Provider {
 	return objval.ProviderGCP
 }
 
+// Capabilities returns the capabilities of Google Storage, note that there's no limit on the number of parts because
+// composed objects may themselves be composed.
+func (c *Client) Capabilities() objval.Capabilities {
+	return objval.Capabilities{
+		SupportsVersioning: true,
+		SupportsCompose:    true,
+		SupportsObjectLock: true,
+		// Don't trigger the multipart copy behavior for GCP; that's already handled by the SDK.
+		MaxCopySize: math.MaxInt64,
+	}
+}
+
 func (c *Client) GetObject(ctx context.Context, opts objcli.GetObjectOptions) (*objval.Object, error) {
 	if err := opts.ByteRange.Valid(false); err != nil {
 		return nil, err // Purposefully not wrapped
@@ -85,7 +113,24 @@ func (c *Client) GetObject(ctx context.Context, opts objcli.GetObjectOptions) (*
 		offset, length = opts.ByteRange.ToOffsetLength(length)
 	}
 
-	reader, err := c.serviceAPI.Bucket(opts.Bucket).Object(opts.Key).NewRangeReader(ctx, offset, length)
+	handle := c.serviceAPI.Bucket(opts.Bucket).Object(opts.Key)
+
+	// Google Storage doesn't support preconditions on the modification time/ETag of an object when reading it, so we
+	// check them ourselves and pin the generation to ensure the object isn't replaced before it's read.
+	if opts.Conditional() {
+		remote, err := handle.Attrs(ctx)
+		if err != nil {
+			return nil, handleError(opts.Bucket, opts.Key, err)
+		}
+
+		if opts.NotModified(&objval.ObjectAttrs{ETag: &remote.Etag, LastModified: &remote.Updated}) {
+			return nil, objerr.ErrNotModified
+		}
+
+		handle = handle.Generation(remote.Generation)
+	}
+
+	reader, err := handle.NewRangeReader(ctx, offset, length)
 	if err != nil {
 		return nil, handleError(opts.Bucket, opts.Key, err)
 	}
@@ -103,6 +148,18 @@ func (c *Client) GetObject(ctx context.Context, opts objcli.GetObjectOptions) (*
 		Body:        reader,
 	}
 
+	// Google Storage transparently decompresses gzip encoded objects (decompressive transcoding), in which case the body
+	// has already been decompressed.
+	if !opts.Decompress || remote.Decompressed {
+		return object, nil
+	}
+
+	err = objcli.DecompressObject(object, remote.ContentEncoding)
+	if err != nil {
+		object.Body.Close()
+		return nil, fmt.Errorf("failed to decompress object: %w", err)
+	}
+
 	return object, nil
 }
 
@@ -117,6 +174,7 @@ func (c *Client) GetObjectAttrs(ctx context.Context, opts objcli.GetObjectAttrsO
 		ETag:         ptr.To(remote.Etag),
 		Size:         ptr.To(remote.Size),
 		LastModified: &remote.Updated,
+		Metadata:     objcli.NormalizeMetadata(decodeMetadata(remote.Metadata)),
 	}
 
 	return attrs, nil
@@ -136,7 +194,12 @@ func (c *Client) PutObject(ctx context.Context, opts objcli.PutObjectOptions) er
 		writer = object.NewWriter(ctx)
 	)
 
-	_, err := objcli.CopyReadSeeker(io.MultiWriter(md5sum, crc32c), opts.Body)
+	body, err := objcli.CompressBody(opts.Body, opts.Compress)
+	if err != nil {
+		return err // Purposefully not wrapped
+	}
+
+	_, err = objcli.CopyReadSeeker(io.MultiWriter(md5sum, crc32c), body)
 	if err != nil {
 		return fmt.Errorf("failed to calculate checksums: %w", err)
 	}
@@ -144,7 +207,15 @@ func (c *Client) PutObject(ctx context.Context, opts objcli.PutObjectOptions) er
 	writer.SendMD5(md5sum.Sum(nil))
 	writer.SendCRC(crc32c.Sum32())
 
-	_, err = io.Copy(writer, opts.Body)
+	if metadata := encodeMetadata(opts.Tags, opts.Metadata); len(metadata) != 0 {
+		writer.SetMetadata(metadata)
+	}
+
+	if opts.Compress != objcli.CompressionNone {
+		writer.SetContentEncoding(string(opts.Compress))
+	}
+
+	_, err = io.Copy(writer, body)
 	if err != nil {
 		return handleError(opts.Bucket, opts.Key, err)
 	}
@@ -152,6 +223,57 @@ func (c *Client) PutObject(ctx context.Context, opts objcli.PutObjectOptions) er
 	return handleError(opts.Bucket, opts.Key, writer.Close())
 }
 
+// GetObjectTags returns the tags for the given object, which are stored as custom metadata with the prefix 'tag-'.
+func (c *Client) GetObjectTags(ctx context.Context, opts objcli.GetObjectTagsOptions) (map[string]string, error) {
+	remote, err := c.serviceAPI.Bucket(opts.Bucket).Object(opts.Key).Attrs(ctx)
+	if err != nil {
+		return nil, handleError(opts.Bucket, opts.Key, err)
+	}
+
+	return decodeTags(remote.Metadata), nil
+}
+
+// PutObjectTags replaces the tags for the given object, any user-defined metadata is left unchanged.
+func (c *Client) PutObjectTags(ctx context.Context, opts objcli.PutObjectTagsOptions) error {
+	return c.replaceTags(ctx, opts.Bucket, opts.Key, opts.Tags)
+}
+
+// DeleteObjectTags removes all the tags for the given object, any user-defined metadata is left unchanged.
+func (c *Client) DeleteObjectTags(ctx context.Context, opts objcli.DeleteObjectTagsOptions) error {
+	return c.replaceTags(ctx, opts.Bucket, opts.Key, nil)
+}
+
+// replaceTags replaces the tags stored in the custom metadata of the given object, only keys with the tag prefix are
+// updated.
+func (c *Client) replaceTags(ctx context.Context, bucket, key string, tags map[string]string) error {
+	object := c.serviceAPI.Bucket(bucket).Object(key)
+
+	remote, err := object.Attrs(ctx)
+	if err != nil {
+		return handleError(bucket, key, err)
+	}
+
+	// Updates are merged with the existing metadata, keys are removed by setting them to the empty string
+	metadata := make(map[string]string, len(tags))
+
+	for name := range decodeTags(remote.Metadata) {
+		metadata[tagPrefix+name] = ""
+	}
+
+	for name, value := range tags {
+		metadata[tagPrefix+name] = value
+	}
+
+	// An empty (non-nil) map would remove all the custom metadata, including any user-defined metadata
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	_, err = object.Update(ctx, storage.ObjectAttrsToUpdate{Metadata: metadata})
+
+	return handleError(bucket, key, err)
+}
+
 func (c *Client) CopyObject(ctx context.Context, opts objcli.CopyObjectOptions) error {
 	var (
 		srcHdle = c.serviceAPI.Bucket(opts.SourceBucket).Object(opts.SourceKey)
@@ -172,17 +294,34 @@ func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectO
 	})
 
 	// As defined by the 'Client' interface, if the given object does not exist, we create it
-	if objerr.IsNotFoundError(err) || ptr.From(attrs.Size) == 0 {
-		return c.PutObject(ctx, objcli.PutObjectOptions(opts))
+	if objerr.IsNotFoundError(err) {
+		return c.PutObject(ctx, objcli.PutObjectOptions{
+			Bucket:           opts.Bucket,
+			Key:              opts.Key,
+			Body:             opts.Body,
+			BandwidthLimiter: opts.BandwidthLimiter,
+		})
 	}
 
 	if err != nil {
 		return fmt.Errorf("failed to get object attributes: %w", err)
 	}
 
+	// Appending to an empty object is the same as replacing it, the metadata of the existing object is preserved
+	if ptr.From(attrs.Size) == 0 {
+		return c.PutObject(ctx, objcli.PutObjectOptions{
+			Bucket:           opts.Bucket,
+			Key:              opts.Key,
+			Body:             opts.Body,
+			Metadata:         attrs.Metadata,
+			BandwidthLimiter: opts.BandwidthLimiter,
+		})
+	}
+
 	id, err := c.CreateMultipartUpload(ctx, objcli.CreateMultipartUploadOptions{
-		Bucket: opts.Bucket,
-		Key:    opts.Key,
+		Bucket:   opts.Bucket,
+		Key:      opts.Key,
+		Metadata: attrs.Metadata,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to start multipart upload: %w", err)
@@ -199,6 +338,13 @@ func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectO
 		return fmt.Errorf("failed to upload part: %w", err)
 	}
 
+	// The upload is never exposed to the caller, so the uploaded part must be removed if we fail to complete it
+	defer func() {
+		if err != nil {
+			c.cleanup(ctx, opts.Bucket, intermediate.ID)
+		}
+	}()
+
 	part := objval.Part{
 		ID:     opts.Key,
 		Number: 1,
@@ -210,6 +356,7 @@ func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectO
 		UploadID: id,
 		Key:      opts.Key,
 		Parts:    []objval.Part{part, intermediate},
+		Metadata: attrs.Metadata,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to complete multipart upload: %w", err)
@@ -219,10 +366,23 @@ func (c *Client) AppendToObject(ctx context.Context, opts objcli.AppendToObjectO
 }
 
 func (c *Client) DeleteObjects(ctx context.Context, opts objcli.DeleteObjectsOptions) error {
-	objects := make([]attrs, 0, len(opts.Keys))
+	all := opts.AllObjects()
+
+	objects := make([]attrs, 0, len(all))
+
+	for _, object := range all {
+		converted := attrs{ObjectAttrs: objval.ObjectAttrs{Key: object.Key}}
 
-	for _, key := range opts.Keys {
-		objects = append(objects, attrs{ObjectAttrs: objval.ObjectAttrs{Key: key}})
+		if object.VersionID != "" {
+			generation, err := strconv.ParseInt(object.VersionID, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid version id '%s' for object '%s', expected a generation", object.VersionID, object.Key)
+			}
+
+			converted.Version = ptr.To(generation)
+		}
+
+		objects = append(objects, converted)
 	}
 
 	return c.deleteObjects(ctx, opts.Bucket, objects...)
@@ -267,6 +427,10 @@ func (c *Client) deleteObjects(ctx context.Context, bucket string, objects ...at
 }
 
 func (c *Client) DeleteDirectory(ctx context.Context, opts objcli.DeleteDirectoryOptions) error {
+	if opts.Include != nil && opts.Exclude != nil {
+		return objcli.ErrIncludeAndExcludeAreMutuallyExclusive
+	}
+
 	var (
 		// size matches the batch deletion size in AWS/Azure.
 		size  = 1000
@@ -297,8 +461,8 @@ func (c *Client) DeleteDirectory(ctx context.Context, opts objcli.DeleteDirector
 		opts.Prefix,
 		"",
 		opts.Versions,
-		nil,
-		nil,
+		opts.Include,
+		opts.Exclude,
 		fn,
 	)
 	if err != nil {
@@ -410,15 +574,28 @@ func (c *Client) iterateObjects(
 	return nil
 }
 
+// ListDeletedObjects is unsupported for Google Storage, deleted objects are only retained as non-current versions in
+// versioned buckets.
+func (c *Client) ListDeletedObjects(
+	_ context.Context,
+	_ objcli.ListDeletedObjectsOptions,
+) ([]*objval.DeletedObject, error) {
+	return nil, objerr.ErrUnsupportedOperation
+}
+
+// UndeleteObject is unsupported for Google Storage, see 'ListDeletedObjects'.
+func (c *Client) UndeleteObject(_ context.Context, _ objcli.UndeleteObjectOptions) error {
+	return objerr.ErrUnsupportedOperation
+}
+
 func (c *Client) CreateMultipartUpload(_ context.Context, _ objcli.CreateMultipartUploadOptions) (string, error) {
 	return uuid.NewString(), nil
 }
 
 func (c *Client) ListParts(ctx context.Context, opts objcli.ListPartsOptions) ([]objval.Part, error) {
-	var (
-		prefix = partPrefix(opts.UploadID, opts.Key)
-		parts  = make([]objval.Part, 0)
-	)
+	prefix := partPrefix(opts.UploadID, opts.Key)
+
+	parts := make([]objval.Part, 0)
 
 	fn := func(attrs *objval.ObjectAttrs) error {
 		parts = append(parts, objval.Part{
@@ -505,7 +682,7 @@ func (c *Client) CompleteMultipartUpload(ctx context.Context, opts objcli.Comple
 		converted = append(converted, part.ID)
 	}
 
-	err := c.complete(ctx, opts.Bucket, opts.Key, converted...)
+	err := c.complete(ctx, opts.Bucket, opts.UploadID, opts.Key, opts.Metadata, converted...)
 	if err != nil {
 		return err
 	}
@@ -524,25 +701,37 @@ func (c *Client) Close() error {
 	return c.serviceAPI.Close()
 }
 
-// complete recursively composes the object in chunks of 32 eventually resulting in a single complete object.
-func (c *Client) complete(ctx context.Context, bucket, key string, parts ...string) error {
-	if len(parts) <= MaxComposable {
-		return c.compose(ctx, bucket, key, parts...)
-	}
+// complete composes the object in chunks of 32 eventually resulting in a single complete object, with the given
+// metadata; the intermediate objects created along the way are always removed, even if composition fails.
+//
+// NOTE: Intermediate objects are named as parts of the given upload, so that any left behind are listed/aborted along
+// with the upload itself.
+func (c *Client) complete(
+	ctx context.Context,
+	bucket, id, key string,
+	metadata map[string]string,
+	parts ...string,
+) error {
+	manifest := newPartManifest(bucket)
+	defer manifest.cleanup(ctx, c)
 
-	intermediate := partKey(uuid.NewString(), key)
-	defer c.cleanup(ctx, bucket, intermediate)
+	for len(parts) > MaxComposable {
+		intermediate := partKey(id, key)
+		manifest.add(intermediate)
 
-	err := c.compose(ctx, bucket, intermediate, parts[:MaxComposable]...)
-	if err != nil {
-		return err
+		err := c.compose(ctx, bucket, intermediate, nil, parts[:MaxComposable]...)
+		if err != nil {
+			return err
+		}
+
+		parts = append([]string{intermediate}, parts[MaxComposable:]...)
 	}
 
-	return c.complete(ctx, bucket, key, append([]string{intermediate}, parts[MaxComposable:]...)...)
+	return c.compose(ctx, bucket, key, metadata, parts...)
 }
 
-// compose the given parts into a single object.
-func (c *Client) compose(ctx context.Context, bucket, key string, parts ...string) error {
+// compose the given parts into a single object, optionally setting its metadata.
+func (c *Client) compose(ctx context.Context, bucket, key string, metadata map[string]string, parts ...string) error {
 	handles := make([]objectAPI, 0, len(parts))
 
 	for _, part := range parts {
@@ -552,16 +741,30 @@ func (c *Client) compose(ctx context.Context, bucket, key string, parts ...strin
 	var (
 		// Object composition is non-destructive from the source perspective and we don't mind potentially "overwriting"
 		// the destination object, always retry.
-		dst    = c.serviceAPI.Bucket(bucket).Object(key).Retryer(storage.WithPolicy(storage.RetryAlways))
-		_, err = dst.ComposerFrom(handles...).Run(ctx)
+		dst      = c.serviceAPI.Bucket(bucket).Object(key).Retryer(storage.WithPolicy(storage.RetryAlways))
+		composer = dst.ComposerFrom(handles...)
 	)
 
+	if len(metadata) != 0 {
+		composer.SetMetadata(metadata)
+	}
+
+	_, err := composer.Run(ctx)
+
 	return handleError(bucket, key, err)
 }
 
 // cleanup attempts to remove the given keys, logging them if we receive an error.
+//
+// NOTE: Cleanup is still attempted if the given context has been cancelled, so that failed operations don't leave
+// objects behind; any remaining objects may be removed using 'RecoverOrphanedParts' or
+// 'objutil.AbortStaleMultipartUploads'.
 func (c *Client) cleanup(ctx context.Context, bucket string, keys ...string) {
-	err := c.DeleteObjects(ctx, objcli.DeleteObjectsOptions{
+	if len(keys) == 0 {
+		return
+	}
+
+	err := c.DeleteObjects(context.WithoutCancel(ctx), objcli.DeleteObjectsOptions{
 		Bucket: bucket,
 		Keys:   keys,
 	})
@@ -569,7 +772,8 @@ func (c *Client) cleanup(ctx context.Context, bucket string, keys ...string) {
 		return
 	}
 
-	c.logger.Error("failed to cleanup intermediate keys, they should be removed manually", "keys", keys, "error", err)
+	c.logger.Error("failed to cleanup intermediate keys, they should be removed manually or using "+
+		"'objutil.AbortStaleMultipartUploads'", "keys", keys, "error", err)
 }
 
 func (c *Client) AbortMultipartUpload(ctx context.Context, opts objcli.AbortMultipartUploadOptions) error {
@@ -580,3 +784,165 @@ func (c *Client) AbortMultipartUpload(ctx context.Context, opts objcli.AbortMult
 
 	return err
 }
+
+// ListMultipartUploads returns the uploads which have parts stored in the given bucket, the intermediate objects
+// created when composing large uploads are attributed to the upload being completed.
+//
+// NOTE: Multipart uploads are emulated by uploading each part as an object, so the upload is considered initiated when
+// its earliest part was uploaded; uploads without any parts aren't returned.
+func (c *Client) ListMultipartUploads(
+	ctx context.Context,
+	opts objcli.ListMultipartUploadsOptions,
+) ([]objval.MultipartUpload, error) {
+	uploads := make([]objval.MultipartUpload, 0)
+	indexes := make(map[string]int)
+
+	fn := func(attrs *objval.ObjectAttrs) error {
+		matches := RegexUploadPart.FindStringSubmatch(attrs.Key)
+		if matches == nil || !strings.HasPrefix(matches[1], opts.Prefix) {
+			return nil
+		}
+
+		idx, ok := indexes[matches[2]]
+		if !ok {
+			idx = len(uploads)
+			indexes[matches[2]] = idx
+			uploads = append(uploads, objval.MultipartUpload{Key: matches[1], UploadID: matches[2]})
+		}
+
+		modified := ptr.From(attrs.LastModified)
+
+		if uploads[idx].Initiated.IsZero() || modified.Before(uploads[idx].Initiated) {
+			uploads[idx].Initiated = modified
+		}
+
+		return nil
+	}
+
+	err := c.IterateObjects(ctx, objcli.IterateObjectsOptions{
+		Bucket: opts.Bucket,
+		Prefix: opts.Prefix,
+		Func:   fn,
+	})
+	if err != nil {
+		return nil, handleError(opts.Bucket, "", err)
+	}
+
+	return uploads, nil
+}
+
+func (c *Client) CreateBucket(ctx context.Context, opts objcli.CreateBucketOptions) error {
+	if c.projectID == "" {
+		return ErrProjectIDRequired
+	}
+
+	var attrs *storage.BucketAttrs
+
+	if opts.Region != "" {
+		attrs = &storage.BucketAttrs{Location: opts.Region}
+	}
+
+	err := c.serviceAPI.Bucket(opts.Bucket).Create(ctx, c.projectID, attrs)
+
+	return handleError(opts.Bucket, "", err)
+}
+
+func (c *Client) DeleteBucket(ctx context.Context, opts objcli.DeleteBucketOptions) error {
+	err := c.serviceAPI.Bucket(opts.Bucket).Delete(ctx)
+
+	return handleError(opts.Bucket, "", err)
+}
+
+// GetBucketVersioning returns the versioning status of the given bucket.
+//
+// NOTE: Google Storage doesn't support suspending versioning, so buckets are reported as disabled even if they
+// previously had versioning enabled.
+func (c *Client) GetBucketVersioning(
+	ctx context.Context,
+	opts objcli.GetBucketVersioningOptions,
+) (objval.VersioningStatus, error) {
+	attrs, err := c.serviceAPI.Bucket(opts.Bucket).Attrs(ctx)
+	if err != nil {
+		return "", handleError(opts.Bucket, "", err)
+	}
+
+	if attrs.VersioningEnabled {
+		return objval.VersioningStatusEnabled, nil
+	}
+
+	return objval.VersioningStatusDisabled, nil
+}
+
+// GetBucketRegion returns the location of the given bucket, this may be a region (e.g. 'us-east1') or a multi-region
+// (e.g. 'us').
+func (c *Client) GetBucketRegion(ctx context.Context, opts objcli.GetBucketRegionOptions) (string, error) {
+	attrs, err := c.serviceAPI.Bucket(opts.Bucket).Attrs(ctx)
+	if err != nil {
+		return "", handleError(opts.Bucket, "", err)
+	}
+
+	// Locations are returned in upper case, normalize them to match the format used elsewhere
+	return strings.ToLower(attrs.Location), nil
+}
+
+// SetObjectLock applies (or releases) the given lock, retention locks are applied using object retention (which must be
+// enabled for the bucket) whilst holds are applied using temporary/event-based holds.
+//
+// NOTE: Retention may only be released by users with the required permissions once it has expired, therefore, releasing
+// a retention lock is unsupported.
+func (c *Client) SetObjectLock(ctx context.Context, opts objcli.SetObjectLockOptions) error {
+	var update storage.ObjectAttrsToUpdate
+
+	switch opts.Lock.Type {
+	case objval.LockTypeCompliance, objval.LockTypeGovernance:
+		if opts.Release {
+			return objerr.ErrUnsupportedOperation
+		}
+
+		mode := "Unlocked"
+		if opts.Lock.Type == objval.LockTypeCompliance {
+			mode = "Locked"
+		}
+
+		update.Retention = &storage.ObjectRetention{Mode: mode, RetainUntil: opts.Lock.Expiration}
+	case objval.LockTypeTemporaryHold:
+		update.TemporaryHold = !opts.Release
+	case objval.LockTypeEventBasedHold:
+		update.EventBasedHold = !opts.Release
+	default:
+		return fmt.Errorf("unknown lock type '%s'", opts.Lock.Type)
+	}
+
+	object := c.serviceAPI.Bucket(opts.Bucket).Object(opts.Key)
+
+	if opts.VersionID != "" {
+		generation, err := strconv.ParseInt(opts.VersionID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version id '%s' for object '%s', expected a generation", opts.VersionID, opts.Key)
+		}
+
+		object = object.Generation(generation)
+	}
+
+	_, err := object.Update(ctx, update)
+
+	return handleError(opts.Bucket, opts.Key, err)
+}
+
+// GetBucketLockingStatus returns whether object retention is enabled for the given bucket.
+func (c *Client) GetBucketLockingStatus(
+	ctx context.Context,
+	opts objcli.GetBucketLockingStatusOptions,
+) (objval.BucketLockingStatus, error) {
+	attrs, err := c.serviceAPI.Bucket(opts.Bucket).Attrs(ctx)
+	if err != nil {
+		return objval.BucketLockingStatus{}, handleError(opts.Bucket, "", err)
+	}
+
+	return objval.BucketLockingStatus{Enabled: attrs.ObjectRetentionMode == "Enabled"}, nil
+}
+
+// QueryObject is unsupported for GCP, which doesn't support querying objects in place.
+func (c *Client) QueryObject(_ context.Context, _ objcli.QueryObjectOptions) (io.ReadCloser, error) {
+	return nil, objerr.ErrUnsupportedOperation
+}
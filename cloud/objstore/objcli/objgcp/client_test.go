@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"hash/crc32"
 	"log/slog"
+	"maps"
+	"math"
 	"os"
 	"reflect"
 	"regexp"
@@ -32,7 +34,17 @@ func TestNewClient(t *testing.T) {
 	require.Equal(
 		t,
 		&Client{serviceAPI: serviceClient{c: &storage.Client{}}, logger: logger},
-		NewClient(ClientOptions{&storage.Client{}, logger}),
+		NewClient(ClientOptions{Client: &storage.Client{}, Logger: logger}),
+	)
+}
+
+func TestNewClientWithUserProject(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	require.Equal(
+		t,
+		&Client{serviceAPI: serviceClient{c: &storage.Client{}, userProject: "project"}, logger: logger},
+		NewClient(ClientOptions{Client: &storage.Client{}, Logger: logger, UserProject: "project"}),
 	)
 }
 
@@ -40,6 +52,14 @@ func TestClientProvider(t *testing.T) {
 	require.Equal(t, objval.ProviderGCP, (&Client{}).Provider())
 }
 
+func TestClientCapabilities(t *testing.T) {
+	capabilities := (&Client{}).Capabilities()
+	require.True(t, capabilities.SupportsVersioning)
+	require.True(t, capabilities.SupportsCompose)
+	require.Zero(t, capabilities.MaxParts)
+	require.Equal(t, int64(math.MaxInt64), capabilities.MaxCopySize)
+}
+
 func TestClientGetObject(t *testing.T) {
 	var (
 		msAPI = &mockServiceAPI{}
@@ -157,6 +177,60 @@ func TestClientGetObjectWithByteRange(t *testing.T) {
 	mrAPI.AssertNumberOfCalls(t, "Attrs", 1)
 }
 
+func TestClientGetObjectConditionalNotModified(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		moAPI = &mockObjectAPI{}
+	)
+
+	msAPI.On("Bucket", "bucket").Return(mbAPI)
+	mbAPI.On("Object", "key").Return(moAPI)
+	moAPI.On("Attrs", mock.Anything).Return(&storage.ObjectAttrs{Etag: "etag", Generation: 42}, nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	_, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:      "bucket",
+		Key:         "key",
+		IfNoneMatch: `"etag"`,
+	})
+	require.ErrorIs(t, err, objerr.ErrNotModified)
+
+	moAPI.AssertNotCalled(t, "NewRangeReader", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestClientGetObjectConditionalModified(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		moAPI = &mockObjectAPI{}
+		mgAPI = &mockObjectAPI{}
+		mrAPI = &mockReaderAPI{}
+	)
+
+	updated := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	msAPI.On("Bucket", "bucket").Return(mbAPI)
+	mbAPI.On("Object", "key").Return(moAPI)
+	moAPI.On("Attrs", mock.Anything).Return(&storage.ObjectAttrs{Updated: updated, Generation: 42}, nil)
+	moAPI.On("Generation", int64(42)).Return(mgAPI)
+	mgAPI.On("NewRangeReader", mock.Anything, int64(0), int64(-1)).Return(mrAPI, nil)
+	mrAPI.On("Attrs", mock.Anything).Return(storage.ReaderObjectAttrs{Size: 64, LastModified: updated}, nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket:          "bucket",
+		Key:             "key",
+		IfModifiedSince: updated.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, mrAPI, object.Body)
+
+	mgAPI.AssertExpectations(t)
+}
+
 func TestClientGetObjectWithInvalidByteRange(t *testing.T) {
 	client := &Client{}
 
@@ -287,6 +361,174 @@ func TestClientPutObject(t *testing.T) {
 	mwAPI.AssertNumberOfCalls(t, "Close", 1)
 }
 
+func TestClientGetObjectTags(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		moAPI = &mockObjectAPI{}
+	)
+
+	msAPI.On("Bucket", mock.MatchedBy(func(bucket string) bool { return bucket == "bucket" })).Return(mbAPI)
+
+	mbAPI.On("Object", mock.MatchedBy(func(key string) bool { return key == "key" })).Return(moAPI)
+
+	moAPI.On("Attrs", mock.Anything).
+		Return(&storage.ObjectAttrs{Metadata: map[string]string{"tag-a": "b", "c": "d"}}, nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	tags, err := client.GetObjectTags(context.Background(), objcli.GetObjectTagsOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "b"}, tags)
+
+	msAPI.AssertExpectations(t)
+	mbAPI.AssertExpectations(t)
+	moAPI.AssertExpectations(t)
+}
+
+func TestClientPutObjectTags(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		moAPI = &mockObjectAPI{}
+	)
+
+	msAPI.On("Bucket", mock.MatchedBy(func(bucket string) bool { return bucket == "bucket" })).Return(mbAPI)
+
+	mbAPI.On("Object", mock.MatchedBy(func(key string) bool { return key == "key" })).Return(moAPI)
+
+	moAPI.On("Attrs", mock.Anything).
+		Return(&storage.ObjectAttrs{Metadata: map[string]string{"tag-a": "b", "tag-c": "d", "h": "i"}}, nil)
+
+	// User-defined metadata must not be updated
+	fn := func(attrs storage.ObjectAttrsToUpdate) bool {
+		return reflect.DeepEqual(attrs.Metadata, map[string]string{"tag-a": "", "tag-c": "e", "tag-f": "g"})
+	}
+
+	moAPI.On("Update", mock.Anything, mock.MatchedBy(fn)).Return(&storage.ObjectAttrs{}, nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	err := client.PutObjectTags(context.Background(), objcli.PutObjectTagsOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Tags:   map[string]string{"c": "e", "f": "g"},
+	})
+	require.NoError(t, err)
+
+	msAPI.AssertExpectations(t)
+	mbAPI.AssertExpectations(t)
+	moAPI.AssertExpectations(t)
+	moAPI.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestClientDeleteObjectTags(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		moAPI = &mockObjectAPI{}
+	)
+
+	msAPI.On("Bucket", mock.MatchedBy(func(bucket string) bool { return bucket == "bucket" })).Return(mbAPI)
+
+	mbAPI.On("Object", mock.MatchedBy(func(key string) bool { return key == "key" })).Return(moAPI)
+
+	moAPI.On("Attrs", mock.Anything).
+		Return(&storage.ObjectAttrs{Metadata: map[string]string{"tag-a": "b", "c": "d"}}, nil)
+
+	fn := func(attrs storage.ObjectAttrsToUpdate) bool {
+		return reflect.DeepEqual(attrs.Metadata, map[string]string{"tag-a": ""})
+	}
+
+	moAPI.On("Update", mock.Anything, mock.MatchedBy(fn)).Return(&storage.ObjectAttrs{}, nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	err := client.DeleteObjectTags(context.Background(), objcli.DeleteObjectTagsOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+
+	msAPI.AssertExpectations(t)
+	mbAPI.AssertExpectations(t)
+	moAPI.AssertExpectations(t)
+}
+
+func TestClientDeleteObjectTagsNoTags(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		moAPI = &mockObjectAPI{}
+	)
+
+	msAPI.On("Bucket", mock.Anything).Return(mbAPI)
+
+	mbAPI.On("Object", mock.Anything).Return(moAPI)
+
+	moAPI.On("Attrs", mock.Anything).Return(&storage.ObjectAttrs{Metadata: map[string]string{"c": "d"}}, nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	err := client.DeleteObjectTags(context.Background(), objcli.DeleteObjectTagsOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+
+	// Updating with an empty map would remove the user-defined metadata
+	moAPI.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestClientObjectTagsPreserveMetadata(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		moAPI = &mockObjectAPI{}
+	)
+
+	msAPI.On("Bucket", mock.Anything).Return(mbAPI)
+
+	mbAPI.On("Object", mock.Anything).Return(moAPI)
+
+	// Emulate Google Storage, where updates are merged with the existing metadata and empty values remove the key
+	remote := map[string]string{"format_version": "2"}
+
+	moAPI.On("Attrs", mock.Anything).Return(func(_ context.Context) *storage.ObjectAttrs {
+		return &storage.ObjectAttrs{Metadata: maps.Clone(remote)}
+	}, nil)
+
+	update := func(_ context.Context, attrs storage.ObjectAttrsToUpdate) *storage.ObjectAttrs {
+		for key, value := range attrs.Metadata {
+			if value == "" {
+				delete(remote, key)
+			} else {
+				remote[key] = value
+			}
+		}
+
+		return &storage.ObjectAttrs{Metadata: maps.Clone(remote)}
+	}
+
+	moAPI.On("Update", mock.Anything, mock.Anything).Return(update, nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	err := client.PutObjectTags(context.Background(), objcli.PutObjectTagsOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Tags:   map[string]string{"a": "b"},
+	})
+	require.NoError(t, err)
+
+	tags, err := client.GetObjectTags(context.Background(), objcli.GetObjectTagsOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "b"}, tags)
+
+	attrs, err := client.GetObjectAttrs(context.Background(), objcli.GetObjectAttrsOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"format_version": "2"}, attrs.Metadata)
+
+	err = client.DeleteObjectTags(context.Background(), objcli.DeleteObjectTagsOptions{Bucket: "bucket", Key: "key"})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{"format_version": "2"}, remote)
+}
+
 func TestClientAppendToObjectNotFoundOrEmpty(t *testing.T) {
 	type test struct {
 		name  string
@@ -437,6 +679,46 @@ func TestClientAppendToObjectUsingObjectComposition(t *testing.T) {
 	mcAPI.AssertNumberOfCalls(t, "Run", 1)
 }
 
+func TestClientAppendToObjectComposeFailedCleanup(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		moAPI = &mockObjectAPI{}
+		mwAPI = &mockWriterAPI{}
+		mcAPI = &mockComposeAPI{}
+	)
+
+	msAPI.On("Bucket", mock.Anything).Return(mbAPI)
+	mbAPI.On("Object", mock.Anything).Return(moAPI)
+
+	moAPI.On("Retryer", mock.Anything).Return(moAPI)
+	moAPI.On("Attrs", mock.Anything).Return(&storage.ObjectAttrs{Size: 5}, nil)
+	moAPI.On("NewWriter", mock.Anything).Return(mwAPI, nil)
+
+	mwAPI.On("SendMD5", mock.Anything)
+	mwAPI.On("SendCRC", mock.Anything)
+	mwAPI.On("Write", mock.Anything).Return(5, nil)
+	mwAPI.On("Close").Return(nil)
+
+	moAPI.On("ComposerFrom", mock.Anything, mock.Anything).Return(mcAPI)
+
+	mcAPI.On("Run", mock.Anything).Return(nil, assert.AnError)
+
+	moAPI.On("Delete", mock.Anything).Return(nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	err := client.AppendToObject(context.Background(), objcli.AppendToObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   strings.NewReader("value"),
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	// The part uploaded for the append should have been removed
+	moAPI.AssertNumberOfCalls(t, "Delete", 1)
+}
+
 func TestClientDeleteObjects(t *testing.T) {
 	var (
 		msAPI = &mockServiceAPI{}
@@ -473,6 +755,41 @@ func TestClientDeleteObjects(t *testing.T) {
 	moAPI.AssertNumberOfCalls(t, "Delete", 3)
 }
 
+func TestClientDeleteObjectsWithVersions(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		moAPI = &mockObjectAPI{}
+	)
+
+	msAPI.On("Bucket", "bucket").Return(mbAPI)
+	mbAPI.On("Object", "key").Return(moAPI)
+	moAPI.On("Retryer", mock.Anything).Return(moAPI)
+	moAPI.On("Generation", int64(42)).Return(moAPI)
+	moAPI.On("Delete", mock.Anything).Return(nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
+		Bucket:  "bucket",
+		Objects: []objval.ObjectVersion{{Key: "key", VersionID: "42"}},
+	})
+	require.NoError(t, err)
+
+	moAPI.AssertExpectations(t)
+	moAPI.AssertNumberOfCalls(t, "Generation", 1)
+}
+
+func TestClientDeleteObjectsInvalidVersion(t *testing.T) {
+	client := &Client{serviceAPI: &mockServiceAPI{}}
+
+	err := client.DeleteObjects(context.Background(), objcli.DeleteObjectsOptions{
+		Bucket:  "bucket",
+		Objects: []objval.ObjectVersion{{Key: "key", VersionID: "version"}},
+	})
+	require.ErrorContains(t, err, "expected a generation")
+}
+
 func TestClientDeleteDirectory(t *testing.T) {
 	var (
 		msAPI = &mockServiceAPI{}
@@ -1005,6 +1322,73 @@ func TestClientListParts(t *testing.T) {
 	mbAPI.AssertNumberOfCalls(t, "Objects", 1)
 }
 
+func TestClientListMultipartUploads(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		miAPI = &mockObjectIteratorAPI{}
+	)
+
+	msAPI.On("Bucket", mock.MatchedBy(func(bucket string) bool { return bucket == "bucket" })).Return(mbAPI)
+
+	mbAPI.On("Objects", mock.Anything, mock.MatchedBy(
+		func(query *storage.Query) bool { return query.Prefix == "prefix/" && query.Delimiter == "" },
+	)).Return(miAPI)
+
+	objects := []*storage.ObjectAttrs{
+		{
+			Name:    "prefix/key-mpu-f2be662e-458f-4e26-b2d7-74e7cf78edc7-8a1d4a5b-3a6f-4b7e-9a55-0d5c1f0c6f10",
+			Updated: (time.Time{}).Add(48 * time.Hour),
+		},
+		{
+			Name:    "prefix/key-mpu-f2be662e-458f-4e26-b2d7-74e7cf78edc7-1c0f5b4e-8d7a-4a2b-b1a3-5e6f7d8c9b0a",
+			Updated: (time.Time{}).Add(24 * time.Hour),
+		},
+		{
+			Name:    "prefix/key",
+			Updated: (time.Time{}).Add(24 * time.Hour),
+		},
+		{
+			Name:    "prefix/other-mpu-0e3c8a7d-6b5f-4c2e-9d1a-7f8e9a0b1c2d-4d3c2b1a-0f9e-4d8c-a7b6-5e4d3c2b1a0f",
+			Updated: (time.Time{}).Add(72 * time.Hour),
+		},
+	}
+
+	for _, object := range objects {
+		call := miAPI.On("Next").Return(object, nil)
+		call.Repeatability = 1
+	}
+
+	miAPI.On("Next").Return(nil, iterator.Done)
+
+	client := &Client{serviceAPI: msAPI}
+
+	uploads, err := client.ListMultipartUploads(context.Background(), objcli.ListMultipartUploadsOptions{
+		Bucket: "bucket",
+		Prefix: "prefix/",
+	})
+	require.NoError(t, err)
+
+	expected := []objval.MultipartUpload{
+		{
+			Key:       "prefix/key",
+			UploadID:  "f2be662e-458f-4e26-b2d7-74e7cf78edc7",
+			Initiated: (time.Time{}).Add(24 * time.Hour),
+		},
+		{
+			Key:       "prefix/other",
+			UploadID:  "0e3c8a7d-6b5f-4c2e-9d1a-7f8e9a0b1c2d",
+			Initiated: (time.Time{}).Add(72 * time.Hour),
+		},
+	}
+
+	require.Equal(t, expected, uploads)
+
+	msAPI.AssertExpectations(t)
+	mbAPI.AssertExpectations(t)
+	mbAPI.AssertNumberOfCalls(t, "Objects", 1)
+}
+
 func TestClientUploadPart(t *testing.T) {
 	var (
 		msAPI = &mockServiceAPI{}
@@ -1225,6 +1609,129 @@ func TestClientCompleteMultipartUploadOverMaxComposable(t *testing.T) {
 	mbAPI.AssertExpectations(t)
 	moAPI.AssertExpectations(t)
 	mcAPI.AssertExpectations(t)
+
+	// Intermediate objects should be attributed to the upload, so that they're aborted along with it
+	var intermediates int
+
+	for _, call := range mbAPI.Calls {
+		if call.Method == "Object" && strings.HasPrefix(call.Arguments.String(0), partPrefix("id", "key")) {
+			intermediates++
+		}
+	}
+
+	require.NotZero(t, intermediates)
+}
+
+func TestClientCompleteMultipartUploadWithMetadata(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		moAPI = &mockObjectAPI{}
+		mcAPI = &mockComposeAPI{}
+	)
+
+	msAPI.On("Bucket", mock.MatchedBy(func(bucket string) bool { return bucket == "bucket" })).Return(mbAPI)
+
+	mbAPI.On("Object", mock.MatchedBy(
+		func(key string) bool { return key == "key" || strings.HasPrefix(key, "key-") },
+	)).Return(moAPI)
+
+	moAPI.On("Retryer", mock.Anything).Return(moAPI)
+
+	expected := make([]any, 0, MaxComposable)
+
+	for i := 0; i < MaxComposable; i++ {
+		expected = append(expected, mock.Anything)
+	}
+
+	moAPI.On("ComposerFrom", expected...).Return(mcAPI)
+	moAPI.On("ComposerFrom", mock.Anything, mock.Anything).Return(mcAPI)
+
+	mcAPI.On("SetMetadata", map[string]string{"format_version": "2"})
+	mcAPI.On("Run", mock.Anything).Return(nil, nil)
+
+	moAPI.On("Delete", mock.Anything).Return(nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	parts := make([]objval.Part, 0)
+
+	for i := 1; i <= MaxComposable+1; i++ {
+		parts = append(parts, objval.Part{ID: fmt.Sprintf("key-%d", i), Number: i})
+	}
+
+	err := client.CompleteMultipartUpload(context.Background(), objcli.CompleteMultipartUploadOptions{
+		Bucket:   "bucket",
+		UploadID: "id",
+		Key:      "key",
+		Parts:    parts,
+		Metadata: map[string]string{"format_version": "2"},
+	})
+	require.NoError(t, err)
+
+	// Only the final object should have the metadata set, not the intermediate objects
+	mcAPI.AssertExpectations(t)
+	mcAPI.AssertNumberOfCalls(t, "SetMetadata", 1)
+	mcAPI.AssertNumberOfCalls(t, "Run", 2)
+}
+
+func TestClientCompleteMultipartUploadComposeFailedCleanup(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		moAPI = &mockObjectAPI{}
+		mcAPI = &mockComposeAPI{}
+	)
+
+	msAPI.On("Bucket", mock.MatchedBy(func(bucket string) bool { return bucket == "bucket" })).Return(mbAPI)
+
+	var intermediate string
+
+	mbAPI.On("Object", mock.MatchedBy(func(key string) bool {
+		if strings.HasPrefix(key, partPrefix("id", "key")) {
+			intermediate = key
+		}
+
+		return key == "key" || strings.HasPrefix(key, "key-")
+	})).Return(moAPI)
+
+	moAPI.On("Retryer", mock.Anything).Return(moAPI)
+
+	expected := make([]any, 0, MaxComposable)
+
+	for i := 0; i < MaxComposable; i++ {
+		expected = append(expected, mock.Anything)
+	}
+
+	moAPI.On("ComposerFrom", expected...).Return(mcAPI)
+	moAPI.On("ComposerFrom", mock.Anything, mock.Anything).Return(mcAPI)
+
+	mcAPI.On("Run", mock.Anything).Return(nil, nil).Once()
+	mcAPI.On("Run", mock.Anything).Return(nil, assert.AnError).Once()
+
+	moAPI.On("Delete", mock.Anything).Return(nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	parts := make([]objval.Part, 0)
+
+	for i := 1; i <= MaxComposable+1; i++ {
+		parts = append(parts, objval.Part{ID: fmt.Sprintf("key-%d", i), Number: i})
+	}
+
+	err := client.CompleteMultipartUpload(context.Background(), objcli.CompleteMultipartUploadOptions{
+		Bucket:   "bucket",
+		UploadID: "id",
+		Key:      "key",
+		Parts:    parts,
+	})
+	require.ErrorIs(t, err, assert.AnError)
+	require.NotEmpty(t, intermediate)
+
+	mcAPI.AssertNumberOfCalls(t, "Run", 2)
+
+	// Only the intermediate object should be removed, the uploaded parts are left so that the upload may be retried
+	moAPI.AssertNumberOfCalls(t, "Delete", 1)
 }
 
 func TestClientAbortMultipartUpload(t *testing.T) {
@@ -1278,3 +1785,196 @@ func TestClientAbortMultipartUpload(t *testing.T) {
 	moAPI.AssertNumberOfCalls(t, "Retryer", 1)
 	moAPI.AssertNumberOfCalls(t, "Delete", 1)
 }
+
+func TestClientCreateBucket(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+	)
+
+	msAPI.On("Bucket", "bucket").Return(mbAPI)
+	mbAPI.On("Create", mock.Anything, "project", &storage.BucketAttrs{Location: "us-east1"}).Return(nil)
+
+	client := &Client{serviceAPI: msAPI, projectID: "project"}
+
+	err := client.CreateBucket(context.Background(), objcli.CreateBucketOptions{Bucket: "bucket", Region: "us-east1"})
+	require.NoError(t, err)
+
+	msAPI.AssertExpectations(t)
+	mbAPI.AssertExpectations(t)
+}
+
+func TestClientCreateBucketNoProjectID(t *testing.T) {
+	client := &Client{}
+
+	err := client.CreateBucket(context.Background(), objcli.CreateBucketOptions{Bucket: "bucket"})
+	require.ErrorIs(t, err, ErrProjectIDRequired)
+}
+
+func TestClientDeleteBucket(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+	)
+
+	msAPI.On("Bucket", "bucket").Return(mbAPI)
+	mbAPI.On("Delete", mock.Anything).Return(storage.ErrBucketNotExist)
+
+	client := &Client{serviceAPI: msAPI}
+
+	err := client.DeleteBucket(context.Background(), objcli.DeleteBucketOptions{Bucket: "bucket"})
+	require.True(t, objerr.IsNotFoundError(err))
+
+	msAPI.AssertExpectations(t)
+	mbAPI.AssertExpectations(t)
+}
+
+func TestClientGetBucketVersioning(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("%t", enabled), func(t *testing.T) {
+			var (
+				msAPI = &mockServiceAPI{}
+				mbAPI = &mockBucketAPI{}
+			)
+
+			msAPI.On("Bucket", "bucket").Return(mbAPI)
+			mbAPI.On("Attrs", mock.Anything).Return(&storage.BucketAttrs{VersioningEnabled: enabled}, nil)
+
+			client := &Client{serviceAPI: msAPI}
+
+			status, err := client.GetBucketVersioning(context.Background(), objcli.GetBucketVersioningOptions{
+				Bucket: "bucket",
+			})
+			require.NoError(t, err)
+
+			expected := objval.VersioningStatusDisabled
+			if enabled {
+				expected = objval.VersioningStatusEnabled
+			}
+
+			require.Equal(t, expected, status)
+		})
+	}
+}
+
+func TestClientGetBucketRegion(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+	)
+
+	msAPI.On("Bucket", "bucket").Return(mbAPI)
+	mbAPI.On("Attrs", mock.Anything).Return(&storage.BucketAttrs{Location: "US-EAST1"}, nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	region, err := client.GetBucketRegion(context.Background(), objcli.GetBucketRegionOptions{Bucket: "bucket"})
+	require.NoError(t, err)
+	require.Equal(t, "us-east1", region)
+}
+
+func TestClientSetObjectLock(t *testing.T) {
+	expiration := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type test struct {
+		name     string
+		lock     objval.ObjectLock
+		release  bool
+		expected storage.ObjectAttrsToUpdate
+	}
+
+	tests := []test{
+		{
+			name:     "Compliance",
+			lock:     objval.ObjectLock{Type: objval.LockTypeCompliance, Expiration: expiration},
+			expected: storage.ObjectAttrsToUpdate{Retention: &storage.ObjectRetention{Mode: "Locked", RetainUntil: expiration}},
+		},
+		{
+			name: "Governance",
+			lock: objval.ObjectLock{Type: objval.LockTypeGovernance, Expiration: expiration},
+			expected: storage.ObjectAttrsToUpdate{
+				Retention: &storage.ObjectRetention{Mode: "Unlocked", RetainUntil: expiration},
+			},
+		},
+		{
+			name:     "TemporaryHold",
+			lock:     objval.ObjectLock{Type: objval.LockTypeTemporaryHold},
+			expected: storage.ObjectAttrsToUpdate{TemporaryHold: true},
+		},
+		{
+			name:     "ReleaseTemporaryHold",
+			lock:     objval.ObjectLock{Type: objval.LockTypeTemporaryHold},
+			release:  true,
+			expected: storage.ObjectAttrsToUpdate{TemporaryHold: false},
+		},
+		{
+			name:     "EventBasedHold",
+			lock:     objval.ObjectLock{Type: objval.LockTypeEventBasedHold},
+			expected: storage.ObjectAttrsToUpdate{EventBasedHold: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				msAPI = &mockServiceAPI{}
+				mbAPI = &mockBucketAPI{}
+				moAPI = &mockObjectAPI{}
+			)
+
+			msAPI.On("Bucket", "bucket").Return(mbAPI)
+			mbAPI.On("Object", "key").Return(moAPI)
+			moAPI.On("Generation", int64(42)).Return(moAPI)
+			moAPI.On("Update", mock.Anything, test.expected).Return(&storage.ObjectAttrs{}, nil)
+
+			client := &Client{serviceAPI: msAPI}
+
+			err := client.SetObjectLock(context.Background(), objcli.SetObjectLockOptions{
+				Bucket:    "bucket",
+				Key:       "key",
+				VersionID: "42",
+				Lock:      test.lock,
+				Release:   test.release,
+			})
+			require.NoError(t, err)
+
+			msAPI.AssertExpectations(t)
+			mbAPI.AssertExpectations(t)
+			moAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClientSetObjectLockReleaseRetention(t *testing.T) {
+	client := &Client{serviceAPI: &mockServiceAPI{}}
+
+	err := client.SetObjectLock(context.Background(), objcli.SetObjectLockOptions{
+		Bucket:  "bucket",
+		Key:     "key",
+		Lock:    objval.ObjectLock{Type: objval.LockTypeCompliance},
+		Release: true,
+	})
+	require.ErrorIs(t, err, objerr.ErrUnsupportedOperation)
+}
+
+func TestClientGetBucketLockingStatus(t *testing.T) {
+	for _, mode := range []string{"Enabled", ""} {
+		t.Run(mode, func(t *testing.T) {
+			var (
+				msAPI = &mockServiceAPI{}
+				mbAPI = &mockBucketAPI{}
+			)
+
+			msAPI.On("Bucket", "bucket").Return(mbAPI)
+			mbAPI.On("Attrs", mock.Anything).Return(&storage.BucketAttrs{ObjectRetentionMode: mode}, nil)
+
+			client := &Client{serviceAPI: msAPI}
+
+			status, err := client.GetBucketLockingStatus(context.Background(), objcli.GetBucketLockingStatusOptions{
+				Bucket: "bucket",
+			})
+			require.NoError(t, err)
+			require.Equal(t, mode == "Enabled", status.Enabled)
+		})
+	}
+}
@@ -16,4 +16,13 @@ const (
 	// ChunkRetryDeadline is the timeout for uploading a single chunk to GCP, this matches the timeout used in
 	// 'cbbackupmgr' for the object storage HTTP client timeout.
 	ChunkRetryDeadline = 30 * time.Minute
+
+	// DefaultReaderBlockSize is the default size of the ranges requested by an 'ObjectReader'.
+	DefaultReaderBlockSize = 8 * 1024 * 1024
+
+	// DefaultReaderCacheSize is the default number of blocks cached by an 'ObjectReader'.
+	DefaultReaderCacheSize = 4
+
+	// DefaultOrphanedPartAge is the default age after which 'RecoverOrphanedParts' considers a part to be orphaned.
+	DefaultOrphanedPartAge = 24 * time.Hour
 )
@@ -0,0 +1,16 @@
+package objgcp
+
+import "errors"
+
+var (
+	// ErrUserProjectRequired is returned when attempting to access a requester pays bucket without providing a user
+	// project which can be billed for the request.
+	ErrUserProjectRequired = errors.New("bucket is a requester pays bucket, a user project must be provided")
+
+	// ErrProjectIDRequired is returned when attempting to create a bucket using a client which wasn't provided a project
+	// id.
+	ErrProjectIDRequired = errors.New("a project id must be provided to create buckets")
+
+	// ErrNegativeOffset is returned when attempting to read from (or seek to) a negative offset in an object.
+	ErrNegativeOffset = errors.New("offset must not be negative")
+)
@@ -0,0 +1,101 @@
+package objgcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// partManifest tracks the temporary part objects created whilst performing an operation, allowing them to be removed
+// once they're no longer required, regardless of whether the operation was successful.
+type partManifest struct {
+	bucket string
+	keys   []string
+}
+
+// newPartManifest returns a new empty manifest for temporary objects in the given bucket.
+func newPartManifest(bucket string) *partManifest {
+	return &partManifest{bucket: bucket}
+}
+
+// add records the given key in the manifest.
+//
+// NOTE: Keys should be added before the object is created, so that it's removed even if creation fails midway.
+func (m *partManifest) add(key string) {
+	m.keys = append(m.keys, key)
+}
+
+// cleanup removes all the objects recorded in the manifest, any objects which couldn't be removed are logged.
+func (m *partManifest) cleanup(ctx context.Context, c *Client) {
+	c.cleanup(ctx, m.bucket, m.keys...)
+	m.keys = nil
+}
+
+// RecoverOrphanedPartsOptions encapsulates the options available when using the 'RecoverOrphanedParts' function.
+type RecoverOrphanedPartsOptions struct {
+	// Bucket is the bucket to remove orphaned parts from.
+	Bucket string
+
+	// Prefix limits the parts removed to those for objects with the given prefix.
+	Prefix string
+
+	// OlderThan is the minimum age of the parts which are removed, parts newer than this are assumed to belong to an
+	// in-progress upload; defaults to 'DefaultOrphanedPartAge'.
+	OlderThan time.Duration
+}
+
+// defaults fills any missing attributes to a sane default.
+func (r *RecoverOrphanedPartsOptions) defaults() {
+	if r.OlderThan == 0 {
+		r.OlderThan = DefaultOrphanedPartAge
+	}
+}
+
+// RecoverOrphanedParts removes the temporary part objects (e.g. 'key-mpu-*') left behind by multipart uploads or
+// appends which were never completed/aborted, for example, because the process was killed, returning the keys of the
+// objects which were removed.
+//
+// NOTE: Any upload which is in-progress, and has parts older than the given age, will fail to complete.
+func (c *Client) RecoverOrphanedParts(ctx context.Context, opts RecoverOrphanedPartsOptions) ([]string, error) {
+	opts.defaults()
+
+	var (
+		cutoff = time.Now().Add(-opts.OlderThan)
+		keys   = make([]string, 0)
+	)
+
+	fn := func(attrs attrs) error {
+		matches := RegexUploadPart.FindStringSubmatch(attrs.Key)
+		if matches == nil || !strings.HasPrefix(matches[1], opts.Prefix) {
+			return nil
+		}
+
+		if ptr.From(attrs.LastModified).Before(cutoff) {
+			keys = append(keys, attrs.Key)
+		}
+
+		return nil
+	}
+
+	err := c.iterateObjects(ctx, opts.Bucket, opts.Prefix, "", false, nil, nil, fn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate objects: %w", err)
+	}
+
+	objects := make([]attrs, 0, len(keys))
+
+	for _, key := range keys {
+		objects = append(objects, attrs{ObjectAttrs: objval.ObjectAttrs{Key: key}})
+	}
+
+	err = c.deleteObjects(ctx, opts.Bucket, objects...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete orphaned parts: %w", err)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,68 @@
+package objgcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/iterator"
+)
+
+func TestClientRecoverOrphanedParts(t *testing.T) {
+	var (
+		msAPI = &mockServiceAPI{}
+		mbAPI = &mockBucketAPI{}
+		miAPI = &mockObjectIteratorAPI{}
+		moAPI = &mockObjectAPI{}
+	)
+
+	msAPI.On("Bucket", mock.MatchedBy(func(bucket string) bool { return bucket == "bucket" })).Return(mbAPI)
+
+	mbAPI.On("Objects", mock.Anything, mock.MatchedBy(
+		func(query *storage.Query) bool { return query.Prefix == "prefix/" && query.Delimiter == "" },
+	)).Return(miAPI)
+
+	const orphaned = "prefix/key-mpu-f2be662e-458f-4e26-b2d7-74e7cf78edc7-8a1d4a5b-3a6f-4b7e-9a55-0d5c1f0c6f10"
+
+	objects := []*storage.ObjectAttrs{
+		{
+			Name:    orphaned,
+			Updated: time.Now().Add(-48 * time.Hour),
+		},
+		{
+			Name:    "prefix/key-mpu-0e3c8a7d-6b5f-4c2e-9d1a-7f8e9a0b1c2d-1c0f5b4e-8d7a-4a2b-b1a3-5e6f7d8c9b0a",
+			Updated: time.Now(),
+		},
+		{
+			Name:    "prefix/key",
+			Updated: time.Now().Add(-48 * time.Hour),
+		},
+	}
+
+	for _, object := range objects {
+		call := miAPI.On("Next").Return(object, nil)
+		call.Repeatability = 1
+	}
+
+	miAPI.On("Next").Return(nil, iterator.Done)
+
+	mbAPI.On("Object", orphaned).Return(moAPI)
+
+	moAPI.On("Retryer", mock.Anything).Return(moAPI)
+	moAPI.On("Delete", mock.Anything).Return(nil)
+
+	client := &Client{serviceAPI: msAPI}
+
+	keys, err := client.RecoverOrphanedParts(context.Background(), RecoverOrphanedPartsOptions{
+		Bucket: "bucket",
+		Prefix: "prefix/",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{orphaned}, keys)
+
+	mbAPI.AssertExpectations(t)
+	moAPI.AssertNumberOfCalls(t, "Delete", 1)
+}
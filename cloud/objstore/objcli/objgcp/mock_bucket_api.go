@@ -14,6 +14,72 @@ type mockBucketAPI struct {
 	mock.Mock
 }
 
+// Attrs provides a mock function with given fields: ctx
+func (_m *mockBucketAPI) Attrs(ctx context.Context) (*storage.BucketAttrs, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Attrs")
+	}
+
+	var r0 *storage.BucketAttrs
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*storage.BucketAttrs, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *storage.BucketAttrs); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.BucketAttrs)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, projectID, attrs
+func (_m *mockBucketAPI) Create(ctx context.Context, projectID string, attrs *storage.BucketAttrs) error {
+	ret := _m.Called(ctx, projectID, attrs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *storage.BucketAttrs) error); ok {
+		r0 = rf(ctx, projectID, attrs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx
+func (_m *mockBucketAPI) Delete(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Object provides a mock function with given fields: key
 func (_m *mockBucketAPI) Object(key string) objectAPI {
 	ret := _m.Called(key)
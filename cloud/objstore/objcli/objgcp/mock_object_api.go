@@ -204,6 +204,36 @@ func (_m *mockObjectAPI) Retryer(opts ...storage.RetryOption) objectAPI {
 	return r0
 }
 
+// Update provides a mock function with given fields: ctx, attrs
+func (_m *mockObjectAPI) Update(ctx context.Context, attrs storage.ObjectAttrsToUpdate) (*storage.ObjectAttrs, error) {
+	ret := _m.Called(ctx, attrs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *storage.ObjectAttrs
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, storage.ObjectAttrsToUpdate) (*storage.ObjectAttrs, error)); ok {
+		return rf(ctx, attrs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, storage.ObjectAttrsToUpdate) *storage.ObjectAttrs); ok {
+		r0 = rf(ctx, attrs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.ObjectAttrs)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, storage.ObjectAttrsToUpdate) error); ok {
+		r1 = rf(ctx, attrs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // newMockObjectAPI creates a new instance of mockObjectAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockObjectAPI(t interface {
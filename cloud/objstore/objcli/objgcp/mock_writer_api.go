@@ -37,6 +37,16 @@ func (_m *mockWriterAPI) SendMD5(md5 []byte) {
 	_m.Called(md5)
 }
 
+// SetContentEncoding provides a mock function with given fields: encoding
+func (_m *mockWriterAPI) SetContentEncoding(encoding string) {
+	_m.Called(encoding)
+}
+
+// SetMetadata provides a mock function with given fields: metadata
+func (_m *mockWriterAPI) SetMetadata(metadata map[string]string) {
+	_m.Called(metadata)
+}
+
 // Write provides a mock function with given fields: p
 func (_m *mockWriterAPI) Write(p []byte) (int, error) {
 	ret := _m.Called(p)
@@ -0,0 +1,290 @@
+package objgcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/couchbase/tools-common/types/v2/lru"
+)
+
+// ObjectReaderOptions encapsulates the options available when using the 'NewObjectReader' function.
+type ObjectReaderOptions struct {
+	// Bucket is the bucket being operated on.
+	Bucket string
+
+	// Key is the key (path) of the object/blob being operated on.
+	Key string
+
+	// BlockSize is the size of the ranges requested from Google Storage, reads are served from cached blocks of this
+	// size. Defaults to 'DefaultReaderBlockSize'.
+	BlockSize int64
+
+	// CacheSize is the number of blocks which are cached in memory, the least recently used blocks are evicted first.
+	// Defaults to 'DefaultReaderCacheSize'.
+	//
+	// NOTE: The cache is always large enough to hold the blocks which are being read ahead.
+	CacheSize uint
+
+	// ReadAhead is the number of subsequent blocks fetched in the background once a block has been read, this improves
+	// the throughput of sequential reads.
+	ReadAhead int
+}
+
+// defaults fills any missing attributes to a sane default.
+func (o *ObjectReaderOptions) defaults() {
+	if o.BlockSize <= 0 {
+		o.BlockSize = DefaultReaderBlockSize
+	}
+
+	if o.CacheSize == 0 {
+		o.CacheSize = DefaultReaderCacheSize
+	}
+
+	o.ReadAhead = max(o.ReadAhead, 0)
+	o.CacheSize = max(o.CacheSize, uint(o.ReadAhead)+1)
+}
+
+// block is a range of an object, which may still be being fetched.
+type block struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// ObjectReader provides random access to an object stored in Google Storage, without downloading the entire object.
+// Ranges of the object are fetched on demand, and recently used ranges are cached.
+//
+// NOTE: The reader is pinned to the generation of the object when it was opened, reads will fail if the object is
+// overwritten. 'ReadAt' is safe for concurrent use, whilst 'Read' and 'Seek' are not.
+type ObjectReader struct {
+	object objectAPI
+	bucket string
+	key    string
+	size   int64
+
+	opts ObjectReaderOptions
+
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+	wg         sync.WaitGroup
+
+	lock   sync.Mutex
+	blocks *lru.Cache[int64, *block]
+
+	offset int64
+}
+
+var (
+	_ io.ReaderAt   = (*ObjectReader)(nil)
+	_ io.ReadSeeker = (*ObjectReader)(nil)
+	_ io.Closer     = (*ObjectReader)(nil)
+)
+
+// NewObjectReader returns a reader which allows random access to the given object. The given context is used for
+// all the requests made by the reader.
+//
+// NOTE: The reader must be closed to stop any background reads.
+func (c *Client) NewObjectReader(ctx context.Context, opts ObjectReaderOptions) (*ObjectReader, error) {
+	opts.defaults()
+
+	object := c.serviceAPI.Bucket(opts.Bucket).Object(opts.Key)
+
+	remote, err := object.Attrs(ctx)
+	if err != nil {
+		return nil, handleError(opts.Bucket, opts.Key, err)
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+
+	reader := &ObjectReader{
+		object:     object.Generation(remote.Generation),
+		bucket:     opts.Bucket,
+		key:        opts.Key,
+		size:       remote.Size,
+		opts:       opts,
+		ctx:        ctx,
+		cancelFunc: cancelFunc,
+		blocks:     lru.New[int64, *block](opts.CacheSize),
+	}
+
+	return reader, nil
+}
+
+// Size returns the size of the object.
+func (o *ObjectReader) Size() int64 {
+	return o.size
+}
+
+// ReadAt reads len(p) bytes from the object starting at the given offset.
+func (o *ObjectReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, ErrNegativeOffset
+	}
+
+	var (
+		n    int
+		last = int64(-1)
+	)
+
+	for n < len(p) && off < o.size {
+		last = off / o.opts.BlockSize
+
+		data, err := o.block(last)
+		if err != nil {
+			return n, err
+		}
+
+		read := copy(p[n:], data[off-last*o.opts.BlockSize:])
+
+		n += read
+		off += int64(read)
+	}
+
+	if last >= 0 {
+		o.readAhead(last)
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// Read reads up to len(p) bytes from the current offset.
+func (o *ObjectReader) Read(p []byte) (int, error) {
+	n, err := o.ReadAt(p, o.offset)
+
+	o.offset += int64(n)
+
+	// Partial reads are expected at the end of the object, EOF is returned by the next read
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+
+	return n, err
+}
+
+// Seek sets the offset for the next 'Read'.
+func (o *ObjectReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+
+	if offset < 0 {
+		return 0, ErrNegativeOffset
+	}
+
+	o.offset = offset
+
+	return offset, nil
+}
+
+// Close stops any background reads, and releases the cached blocks.
+func (o *ObjectReader) Close() error {
+	o.cancelFunc()
+	o.wg.Wait()
+
+	o.lock.Lock()
+	o.blocks = lru.New[int64, *block](o.opts.CacheSize)
+	o.lock.Unlock()
+
+	return nil
+}
+
+// block returns the data for the block with the given index, waiting for it to be fetched if required.
+func (o *ObjectReader) block(idx int64) ([]byte, error) {
+	if err := o.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b := o.load(idx)
+
+	select {
+	case <-b.done:
+	case <-o.ctx.Done():
+		return nil, o.ctx.Err()
+	}
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return b.data, nil
+}
+
+// readAhead begins fetching the blocks following the given block.
+func (o *ObjectReader) readAhead(idx int64) {
+	blocks := (o.size + o.opts.BlockSize - 1) / o.opts.BlockSize
+
+	for next := idx + 1; next <= idx+int64(o.opts.ReadAhead) && next < blocks; next++ {
+		o.load(next)
+	}
+}
+
+// load returns the block with the given index, beginning to fetch it if it's not already cached (or being fetched).
+func (o *ObjectReader) load(idx int64) *block {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	// Set cached blocks again, this marks them as the most recently used
+	b, ok := o.blocks.Get(idx)
+	if ok {
+		o.blocks.Set(idx, b)
+		return b
+	}
+
+	b = &block{done: make(chan struct{})}
+
+	o.blocks.Set(idx, b)
+	o.wg.Add(1)
+
+	go o.fetch(idx, b)
+
+	return b
+}
+
+// fetch downloads the block with the given index, failed blocks are removed from the cache so that they're fetched
+// again by subsequent reads.
+func (o *ObjectReader) fetch(idx int64, b *block) {
+	defer o.wg.Done()
+	defer close(b.done)
+
+	b.data, b.err = o.download(idx*o.opts.BlockSize, min(o.opts.BlockSize, o.size-idx*o.opts.BlockSize))
+	if b.err == nil {
+		return
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if cached, ok := o.blocks.Get(idx); ok && cached == b {
+		o.blocks.Delete(idx)
+	}
+}
+
+// download the given range of the object.
+func (o *ObjectReader) download(offset, length int64) ([]byte, error) {
+	reader, err := o.object.NewRangeReader(o.ctx, offset, length)
+	if err != nil {
+		return nil, handleError(o.bucket, o.key, err)
+	}
+	defer reader.Close()
+
+	data := make([]byte, length)
+
+	_, err = io.ReadFull(reader, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read range %d-%d: %w", offset, offset+length-1, err)
+	}
+
+	return data, nil
+}
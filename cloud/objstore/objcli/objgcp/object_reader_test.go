@@ -0,0 +1,225 @@
+package objgcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testRangeReader implements the 'readerAPI' interface, returning a range of an in-memory object.
+type testRangeReader struct {
+	*bytes.Reader
+}
+
+func (t testRangeReader) Close() error {
+	return nil
+}
+
+func (t testRangeReader) Attrs() storage.ReaderObjectAttrs {
+	return storage.ReaderObjectAttrs{}
+}
+
+// newTestObjectReader returns an object reader for the given data, along with a counter for the number of ranges which
+// have been requested. The given function may be used to fail requests for a given offset.
+func newTestObjectReader(
+	t *testing.T,
+	data []byte,
+	opts ObjectReaderOptions,
+	fail func(offset int64) error,
+) (*ObjectReader, *atomic.Int64) {
+	var (
+		msAPI    = &mockServiceAPI{}
+		mbAPI    = &mockBucketAPI{}
+		moAPI    = &mockObjectAPI{}
+		requests = &atomic.Int64{}
+	)
+
+	msAPI.On("Bucket", "bucket").Return(mbAPI)
+	mbAPI.On("Object", "key").Return(moAPI)
+	moAPI.On("Attrs", mock.Anything).Return(&storage.ObjectAttrs{Size: int64(len(data)), Generation: 42}, nil)
+	moAPI.On("Generation", int64(42)).Return(moAPI)
+
+	moAPI.On("NewRangeReader", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, offset, length int64) (readerAPI, error) {
+			requests.Add(1)
+
+			if fail != nil {
+				if err := fail(offset); err != nil {
+					return nil, err
+				}
+			}
+
+			return testRangeReader{Reader: bytes.NewReader(data[offset : offset+length])}, nil
+		},
+	)
+
+	client := &Client{serviceAPI: msAPI}
+
+	opts.Bucket, opts.Key = "bucket", "key"
+
+	reader, err := client.NewObjectReader(context.Background(), opts)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { reader.Close() })
+
+	return reader, requests
+}
+
+func TestObjectReaderOptionsDefaults(t *testing.T) {
+	opts := ObjectReaderOptions{}
+	opts.defaults()
+	require.Equal(t, ObjectReaderOptions{BlockSize: DefaultReaderBlockSize, CacheSize: DefaultReaderCacheSize}, opts)
+
+	opts = ObjectReaderOptions{ReadAhead: 8}
+	opts.defaults()
+	require.Equal(t, uint(9), opts.CacheSize)
+}
+
+func TestObjectReaderReadAt(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+
+	reader, requests := newTestObjectReader(t, data, ObjectReaderOptions{BlockSize: 4, CacheSize: 8}, nil)
+
+	require.Equal(t, int64(len(data)), reader.Size())
+
+	// Spans multiple blocks
+	buffer := make([]byte, 6)
+
+	n, err := reader.ReadAt(buffer, 3)
+	require.NoError(t, err)
+	require.Equal(t, 6, n)
+	require.Equal(t, []byte("345678"), buffer)
+	require.Equal(t, int64(3), requests.Load())
+
+	// Served from the cache
+	n, err = reader.ReadAt(buffer[:2], 4)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, []byte("45"), buffer[:2])
+	require.Equal(t, int64(3), requests.Load())
+
+	// Reads past the end of the object are truncated
+	n, err = reader.ReadAt(buffer, 17)
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, 3, n)
+	require.Equal(t, []byte("hij"), buffer[:3])
+
+	_, err = reader.ReadAt(buffer, 20)
+	require.ErrorIs(t, err, io.EOF)
+
+	_, err = reader.ReadAt(buffer, -1)
+	require.ErrorIs(t, err, ErrNegativeOffset)
+}
+
+func TestObjectReaderEvictsLeastRecentlyUsed(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+
+	reader, requests := newTestObjectReader(t, data, ObjectReaderOptions{BlockSize: 4, CacheSize: 2}, nil)
+
+	buffer := make([]byte, 1)
+
+	for _, offset := range []int64{0, 4, 0, 8, 0} {
+		_, err := reader.ReadAt(buffer, offset)
+		require.NoError(t, err)
+	}
+
+	// The block at offset zero remains cached as it's the most recently used
+	require.Equal(t, int64(3), requests.Load())
+
+	_, err := reader.ReadAt(buffer, 4)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), requests.Load())
+}
+
+func TestObjectReaderReadAhead(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+
+	reader, requests := newTestObjectReader(t, data, ObjectReaderOptions{BlockSize: 4, ReadAhead: 2}, nil)
+
+	_, err := reader.ReadAt(make([]byte, 1), 0)
+	require.NoError(t, err)
+
+	// The blocks being read ahead should be reused, the last two blocks are then read ahead
+	_, err = reader.ReadAt(make([]byte, 8), 4)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, int64(5), requests.Load())
+
+	// Doesn't read ahead past the end of the object
+	reader, requests = newTestObjectReader(t, data, ObjectReaderOptions{BlockSize: 4, ReadAhead: 2}, nil)
+
+	_, err = reader.ReadAt(make([]byte, 1), 16)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, int64(1), requests.Load())
+}
+
+func TestObjectReaderReadSeek(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+
+	reader, _ := newTestObjectReader(t, data, ObjectReaderOptions{BlockSize: 3, ReadAhead: 1}, nil)
+
+	read, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, data, read)
+
+	offset, err := reader.Seek(-5, io.SeekEnd)
+	require.NoError(t, err)
+	require.Equal(t, int64(15), offset)
+
+	offset, err = reader.Seek(2, io.SeekCurrent)
+	require.NoError(t, err)
+	require.Equal(t, int64(17), offset)
+
+	read, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hij"), read)
+
+	_, err = reader.Seek(-1, io.SeekStart)
+	require.ErrorIs(t, err, ErrNegativeOffset)
+}
+
+func TestObjectReaderRetriesFailedBlocks(t *testing.T) {
+	var (
+		data   = []byte("0123456789abcdefghij")
+		failed atomic.Bool
+	)
+
+	fail := func(offset int64) error {
+		if offset == 4 && failed.CompareAndSwap(false, true) {
+			return errors.New("failed")
+		}
+
+		return nil
+	}
+
+	reader, requests := newTestObjectReader(t, data, ObjectReaderOptions{BlockSize: 4}, fail)
+
+	buffer := make([]byte, 8)
+
+	n, err := reader.ReadAt(buffer, 0)
+	require.Error(t, err)
+	require.Equal(t, 4, n)
+
+	n, err = reader.ReadAt(buffer, 0)
+	require.NoError(t, err)
+	require.Equal(t, 8, n)
+	require.Equal(t, []byte("01234567"), buffer)
+	require.Equal(t, int64(3), requests.Load())
+}
+
+func TestObjectReaderClosed(t *testing.T) {
+	reader, _ := newTestObjectReader(t, []byte("data"), ObjectReaderOptions{}, nil)
+
+	require.NoError(t, reader.Close())
+
+	_, err := reader.ReadAt(make([]byte, 1), 0)
+	require.ErrorIs(t, err, context.Canceled)
+}
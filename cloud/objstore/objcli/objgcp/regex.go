@@ -8,5 +8,6 @@ import (
 // regexUUID is an uncompiled regular expression which matches a standard uuid.
 const regexUUID = `[0-9a-fA-F]{8}\-[0-9a-fA-F]{4}\-[0-9a-fA-F]{4}\-[0-9a-fA-F]{4}\-[0-9a-fA-F]{12}`
 
-// RegexUploadPart matches the key for an object created by the GCP client as part of emulating multipart uploads.
-var RegexUploadPart = regexp.MustCompile(fmt.Sprintf(`^.*-mpu-%s-%s$`, regexUUID, regexUUID))
+// RegexUploadPart matches the key for an object created by the GCP client as part of emulating multipart uploads; the
+// first submatch is the key of the object being uploaded, and the second is the id of the upload.
+var RegexUploadPart = regexp.MustCompile(fmt.Sprintf(`^(.*)-mpu-(%s)-%s$`, regexUUID, regexUUID))
@@ -3,8 +3,11 @@ package objgcp
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"path"
+	"slices"
+	"strings"
 
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
 
@@ -29,10 +32,33 @@ func handleError(bucket, key string, err error) error {
 	}
 
 	switch statusCode {
+	case http.StatusBadRequest:
+		if isUserProjectMissing(gerr) {
+			return ErrUserProjectRequired
+		}
+
+		if isChecksumMismatch(gerr) {
+			return objerr.ErrChecksumMismatch
+		}
 	case http.StatusUnauthorized:
+		// Tokens are refreshed by the SDK, an expired token is only reported when it couldn't be refreshed
+		if strings.Contains(strings.ToLower(gerr.Message), "expired") {
+			return objerr.ErrCredentialsExpired
+		}
+
 		return objerr.ErrUnauthenticated
 	case http.StatusForbidden:
+		// Rate limits and quotas are also reported using a 403, with the reason indicating which was exceeded
+		switch {
+		case hasReason(gerr, "rateLimitExceeded", "userRateLimitExceeded"):
+			return objerr.ErrThrottled
+		case hasReason(gerr, "quotaExceeded"):
+			return objerr.ErrQuotaExceeded
+		}
+
 		return objerr.ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return objerr.ErrThrottled
 	}
 
 	if errors.Is(err, storage.ErrBucketNotExist) {
@@ -56,6 +82,39 @@ func handleError(bucket, key string, err error) error {
 	return objerr.HandleError(err)
 }
 
+// hasReason returns a boolean indicating whether any of the errors in the given error have one of the given reasons.
+func hasReason(err *googleapi.Error, reasons ...string) bool {
+	for _, item := range err.Errors {
+		if slices.Contains(reasons, item.Reason) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isChecksumMismatch returns a boolean indicating whether the given error indicates that the checksum provided with an
+// upload doesn't match the data received e.g. 'Provided MD5 hash "..." doesn't match calculated MD5 hash "...".'.
+func isChecksumMismatch(err *googleapi.Error) bool {
+	return strings.Contains(strings.ToLower(err.Message), "doesn't match calculated")
+}
+
+// isUserProjectMissing returns a boolean indicating whether the given error was returned because a requester pays
+// bucket was accessed without providing a user project.
+func isUserProjectMissing(err *googleapi.Error) bool {
+	if strings.Contains(strings.ToLower(err.Message), "requester pays") {
+		return true
+	}
+
+	for _, item := range err.Errors {
+		if item.Reason == "required" && strings.Contains(strings.ToLower(item.Message), "requester pays") {
+			return true
+		}
+	}
+
+	return false
+}
+
 // partKey returns a key which should be used for an in-progress multipart upload. This function should be used to
 // generate key names since they'll be prefixed with 'basename(key)-mpu-' allowing efficient listing upon completion.
 func partKey(id, key string) string {
@@ -66,3 +125,47 @@ func partKey(id, key string) string {
 func partPrefix(id, key string) string {
 	return fmt.Sprintf("%s-mpu-%s", key, id)
 }
+
+// tagPrefix is the prefix of the custom metadata keys used to store tags, Google Storage doesn't support tagging objects
+// so they're stored alongside any user-defined metadata.
+const tagPrefix = "tag-"
+
+// encodeMetadata returns the custom metadata used to store the given tags and user-defined metadata.
+func encodeMetadata(tags, metadata map[string]string) map[string]string {
+	if len(tags) == 0 {
+		return metadata
+	}
+
+	encoded := maps.Clone(metadata)
+	if encoded == nil {
+		encoded = make(map[string]string, len(tags))
+	}
+
+	for key, value := range tags {
+		encoded[tagPrefix+key] = value
+	}
+
+	return encoded
+}
+
+// decodeTags returns the tags stored in the given custom metadata.
+func decodeTags(custom map[string]string) map[string]string {
+	tags := make(map[string]string)
+
+	for key, value := range custom {
+		if trimmed, ok := strings.CutPrefix(key, tagPrefix); ok {
+			tags[trimmed] = value
+		}
+	}
+
+	return tags
+}
+
+// decodeMetadata returns the user-defined metadata stored in the given custom metadata, excluding any tags.
+func decodeMetadata(custom map[string]string) map[string]string {
+	metadata := maps.Clone(custom)
+
+	maps.DeleteFunc(metadata, func(key, _ string) bool { return strings.HasPrefix(key, tagPrefix) })
+
+	return metadata
+}
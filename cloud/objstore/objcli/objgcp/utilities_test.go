@@ -23,9 +23,32 @@ func TestHandleError(t *testing.T) {
 	require.ErrorIs(t,
 		handleError("bucket", "key", &googleapi.Error{Code: http.StatusUnauthorized}), objerr.ErrUnauthenticated)
 
+	require.ErrorIs(t, handleError("bucket", "key", &googleapi.Error{
+		Code:    http.StatusUnauthorized,
+		Message: "The access token has expired",
+	}), objerr.ErrCredentialsExpired)
+
 	require.ErrorIs(t,
 		handleError("bucket", "key", &googleapi.Error{Code: http.StatusForbidden}), objerr.ErrUnauthorized)
 
+	require.ErrorIs(t,
+		handleError("bucket", "key", &googleapi.Error{Code: http.StatusTooManyRequests}), objerr.ErrThrottled)
+
+	require.ErrorIs(t, handleError("bucket", "key", &googleapi.Error{
+		Code:   http.StatusForbidden,
+		Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}},
+	}), objerr.ErrThrottled)
+
+	require.ErrorIs(t, handleError("bucket", "key", &googleapi.Error{
+		Code:   http.StatusForbidden,
+		Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}},
+	}), objerr.ErrQuotaExceeded)
+
+	require.ErrorIs(t, handleError("bucket", "key", &googleapi.Error{
+		Code:    http.StatusBadRequest,
+		Message: `Provided MD5 hash "a" doesn't match calculated MD5 hash "b".`,
+	}), objerr.ErrChecksumMismatch)
+
 	require.ErrorAs(t, handleError("", "", storage.ErrBucketNotExist), &notFound)
 	require.Equal(t, "bucket", notFound.Type)
 	require.Equal(t, "<empty bucket name>", notFound.Name)
@@ -45,6 +68,24 @@ func TestHandleError(t *testing.T) {
 	require.ErrorIs(t, handleError("", "", &net.DNSError{IsNotFound: true}), objerr.ErrEndpointResolutionFailed)
 }
 
+func TestHandleErrorUserProjectRequired(t *testing.T) {
+	require.ErrorIs(t, handleError("bucket", "key", &googleapi.Error{
+		Code:    http.StatusBadRequest,
+		Message: "Bucket is a requester pays bucket but no user project provided.",
+	}), ErrUserProjectRequired)
+
+	require.ErrorIs(t, handleError("bucket", "key", &googleapi.Error{
+		Code: http.StatusBadRequest,
+		Errors: []googleapi.ErrorItem{{
+			Reason:  "required",
+			Message: "Bucket is a requester pays bucket but no user project provided.",
+		}},
+	}), ErrUserProjectRequired)
+
+	err := &googleapi.Error{Code: http.StatusBadRequest, Message: "Invalid argument."}
+	require.ErrorIs(t, handleError("bucket", "key", err), err)
+}
+
 func TestPartKey(t *testing.T) {
 	require.True(t, strings.HasPrefix(partKey("id", "key"), "key-"))
 	require.NotEqual(t, partKey("id", "key"), partKey("id", "key"))
@@ -53,3 +94,25 @@ func TestPartKey(t *testing.T) {
 func TestPartPrefix(t *testing.T) {
 	require.Equal(t, "/path/to/key-mpu-id", partPrefix("id", "/path/to/key"))
 }
+
+func TestEncodeMetadata(t *testing.T) {
+	require.Nil(t, encodeMetadata(nil, nil))
+	require.Equal(t, map[string]string{"a": "b"}, encodeMetadata(nil, map[string]string{"a": "b"}))
+	require.Equal(t, map[string]string{"tag-a": "b"}, encodeMetadata(map[string]string{"a": "b"}, nil))
+
+	var (
+		tags     = map[string]string{"a": "b", "c": "d"}
+		metadata = map[string]string{"c": "e"}
+	)
+
+	require.Equal(t, map[string]string{"tag-a": "b", "tag-c": "d", "c": "e"}, encodeMetadata(tags, metadata))
+	require.Equal(t, map[string]string{"c": "e"}, metadata)
+}
+
+func TestDecodeMetadata(t *testing.T) {
+	custom := map[string]string{"tag-a": "b", "c": "d"}
+
+	require.Equal(t, map[string]string{"a": "b"}, decodeTags(custom))
+	require.Equal(t, map[string]string{"c": "d"}, decodeMetadata(custom))
+	require.Equal(t, map[string]string{"tag-a": "b", "c": "d"}, custom)
+}
@@ -2,6 +2,7 @@ package objcli
 
 import (
 	"context"
+	"io"
 
 	"golang.org/x/time/rate"
 
@@ -18,27 +19,51 @@ import (
 // - PutObject
 // - AppendToObject
 // - UploadPart
+// - QueryObject
+//
+// NOTE: The limiter may be overridden for a single operation by setting the 'BandwidthLimiter' option.
 type RateLimitedClient struct {
 	c  Client
 	rl *rate.Limiter
 }
 
+var _ Client = (*RateLimitedClient)(nil)
+
 // NewRateLimitedClient returns a RateLimitedClient.
 func NewRateLimitedClient(c Client, rl *rate.Limiter) *RateLimitedClient {
 	return &RateLimitedClient{c: c, rl: rl}
 }
 
+// NewBandwidthLimitedClient returns a RateLimitedClient which uses the given bandwidth limiter, the limiter may be shared
+// between clients to limit their combined throughput.
+func NewBandwidthLimitedClient(c Client, limiter *BandwidthLimiter) *RateLimitedClient {
+	return NewRateLimitedClient(c, limiter.Limiter())
+}
+
+// limiter returns the limiter to use for an operation, preferring the given override.
+func (r *RateLimitedClient) limiter(override *BandwidthLimiter) *rate.Limiter {
+	if override != nil {
+		return override.Limiter()
+	}
+
+	return r.rl
+}
+
 func (r *RateLimitedClient) Provider() objval.Provider {
 	return r.c.Provider()
 }
 
+func (r *RateLimitedClient) Capabilities() objval.Capabilities {
+	return r.c.Capabilities()
+}
+
 func (r *RateLimitedClient) GetObject(ctx context.Context, opts GetObjectOptions) (*objval.Object, error) {
 	obj, err := r.c.GetObject(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	obj.Body = ratelimit.NewRateLimitedReadCloser(ctx, obj.Body, r.rl)
+	obj.Body = ratelimit.NewRateLimitedReadCloser(ctx, obj.Body, r.limiter(opts.BandwidthLimiter))
 
 	return obj, nil
 }
@@ -51,12 +76,16 @@ func (r *RateLimitedClient) GetObjectAttrs(
 }
 
 func (r *RateLimitedClient) PutObject(ctx context.Context, opts PutObjectOptions) error {
-	opts.Body = ratelimit.NewRateLimitedReadSeeker(ctx, opts.Body, r.rl)
+	opts.Body = ratelimit.NewRateLimitedReadSeeker(ctx, opts.Body, r.limiter(opts.BandwidthLimiter))
 	return r.c.PutObject(ctx, opts)
 }
 
+func (r *RateLimitedClient) CopyObject(ctx context.Context, opts CopyObjectOptions) error {
+	return r.c.CopyObject(ctx, opts)
+}
+
 func (r *RateLimitedClient) AppendToObject(ctx context.Context, opts AppendToObjectOptions) error {
-	opts.Body = ratelimit.NewRateLimitedReadSeeker(ctx, opts.Body, r.rl)
+	opts.Body = ratelimit.NewRateLimitedReadSeeker(ctx, opts.Body, r.limiter(opts.BandwidthLimiter))
 	return r.c.AppendToObject(ctx, opts)
 }
 
@@ -72,6 +101,17 @@ func (r *RateLimitedClient) IterateObjects(ctx context.Context, opts IterateObje
 	return r.c.IterateObjects(ctx, opts)
 }
 
+func (r *RateLimitedClient) ListDeletedObjects(
+	ctx context.Context,
+	opts ListDeletedObjectsOptions,
+) ([]*objval.DeletedObject, error) {
+	return r.c.ListDeletedObjects(ctx, opts)
+}
+
+func (r *RateLimitedClient) UndeleteObject(ctx context.Context, opts UndeleteObjectOptions) error {
+	return r.c.UndeleteObject(ctx, opts)
+}
+
 func (r *RateLimitedClient) CreateMultipartUpload(
 	ctx context.Context,
 	opts CreateMultipartUploadOptions,
@@ -84,7 +124,7 @@ func (r *RateLimitedClient) ListParts(ctx context.Context, opts ListPartsOptions
 }
 
 func (r *RateLimitedClient) UploadPart(ctx context.Context, opts UploadPartOptions) (objval.Part, error) {
-	opts.Body = ratelimit.NewRateLimitedReadSeeker(ctx, opts.Body, r.rl)
+	opts.Body = ratelimit.NewRateLimitedReadSeeker(ctx, opts.Body, r.limiter(opts.BandwidthLimiter))
 	return r.c.UploadPart(ctx, opts)
 }
 
@@ -99,3 +139,65 @@ func (r *RateLimitedClient) CompleteMultipartUpload(ctx context.Context, opts Co
 func (r *RateLimitedClient) AbortMultipartUpload(ctx context.Context, opts AbortMultipartUploadOptions) error {
 	return r.c.AbortMultipartUpload(ctx, opts)
 }
+
+func (r *RateLimitedClient) ListMultipartUploads(
+	ctx context.Context,
+	opts ListMultipartUploadsOptions,
+) ([]objval.MultipartUpload, error) {
+	return r.c.ListMultipartUploads(ctx, opts)
+}
+
+func (r *RateLimitedClient) CreateBucket(ctx context.Context, opts CreateBucketOptions) error {
+	return r.c.CreateBucket(ctx, opts)
+}
+
+func (r *RateLimitedClient) DeleteBucket(ctx context.Context, opts DeleteBucketOptions) error {
+	return r.c.DeleteBucket(ctx, opts)
+}
+
+func (r *RateLimitedClient) GetBucketVersioning(
+	ctx context.Context,
+	opts GetBucketVersioningOptions,
+) (objval.VersioningStatus, error) {
+	return r.c.GetBucketVersioning(ctx, opts)
+}
+
+func (r *RateLimitedClient) GetObjectTags(ctx context.Context, opts GetObjectTagsOptions) (map[string]string, error) {
+	return r.c.GetObjectTags(ctx, opts)
+}
+
+func (r *RateLimitedClient) PutObjectTags(ctx context.Context, opts PutObjectTagsOptions) error {
+	return r.c.PutObjectTags(ctx, opts)
+}
+
+func (r *RateLimitedClient) DeleteObjectTags(ctx context.Context, opts DeleteObjectTagsOptions) error {
+	return r.c.DeleteObjectTags(ctx, opts)
+}
+
+func (r *RateLimitedClient) GetBucketRegion(ctx context.Context, opts GetBucketRegionOptions) (string, error) {
+	return r.c.GetBucketRegion(ctx, opts)
+}
+
+func (r *RateLimitedClient) SetObjectLock(ctx context.Context, opts SetObjectLockOptions) error {
+	return r.c.SetObjectLock(ctx, opts)
+}
+
+func (r *RateLimitedClient) GetBucketLockingStatus(
+	ctx context.Context,
+	opts GetBucketLockingStatusOptions,
+) (objval.BucketLockingStatus, error) {
+	return r.c.GetBucketLockingStatus(ctx, opts)
+}
+
+func (r *RateLimitedClient) QueryObject(ctx context.Context, opts QueryObjectOptions) (io.ReadCloser, error) {
+	body, err := r.c.QueryObject(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return ratelimit.NewRateLimitedReadCloser(ctx, body, r.limiter(opts.BandwidthLimiter)), nil
+}
+
+func (r *RateLimitedClient) Close() error {
+	return r.c.Close()
+}
@@ -147,3 +147,31 @@ func TestRateLimitedClientUploadPart(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestRateLimitedClientBandwidthLimiterOverride(t *testing.T) {
+	// The client limiter would take ~4s to transfer the test data, the override disables limiting
+	rlClient := NewBandwidthLimitedClient(NewTestClient(t, objval.ProviderAWS), NewBandwidthLimiter(bytesPerSecond))
+
+	start := time.Now()
+
+	err := rlClient.PutObject(context.Background(), PutObjectOptions{
+		Bucket:           bucket,
+		Key:              key,
+		Body:             bytes.NewReader(testData),
+		BandwidthLimiter: NewBandwidthLimiter(0),
+	})
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), time.Second)
+
+	obj, err := rlClient.GetObject(context.Background(), GetObjectOptions{
+		Bucket:           bucket,
+		Key:              key,
+		BandwidthLimiter: NewBandwidthLimiter(0),
+	})
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(obj.Body)
+	require.NoError(t, err)
+	require.Equal(t, testData, data)
+	require.Less(t, time.Since(start), time.Second)
+}
@@ -2,11 +2,14 @@ package objcli
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"path"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -37,6 +40,10 @@ type TestClient struct {
 
 var _ Client = (*TestClient)(nil)
 
+// regexTestUploadPart matches the key of a part uploaded using the test client, capturing the key of the object being
+// uploaded and the id of the upload.
+var regexTestUploadPart = regexp.MustCompile(`^(.*)-mpu-([0-9a-fA-F-]{36})-[0-9a-fA-F-]{36}$`)
+
 // NewTestClient returns a new test client, which has no buckets/objects.
 func NewTestClient(t *testing.T, provider objval.Provider) *TestClient {
 	return &TestClient{
@@ -50,6 +57,37 @@ func (t *TestClient) Provider() objval.Provider {
 	return t.provider
 }
 
+// Capabilities returns the capabilities of the cloud provider being emulated, or no capabilities where the provider is
+// unknown.
+func (t *TestClient) Capabilities() objval.Capabilities {
+	switch t.provider {
+	case objval.ProviderNone:
+		return objval.Capabilities{SupportsVersioning: true, SupportsCompose: true, MaxCopySize: math.MaxInt64}
+	case objval.ProviderAWS:
+		return objval.Capabilities{
+			SupportsVersioning: true,
+			SupportsCompose:    true,
+			MaxParts:           10_000,
+			MinPartSize:        5 * 1024 * 1024,
+			MaxCopySize:        5 * 1000 * 1000 * 1000,
+		}
+	case objval.ProviderAzure:
+		return objval.Capabilities{
+			SupportsCompose: true,
+			MaxParts:        50_000,
+			MaxCopySize:     256 * 1000 * 1000,
+		}
+	case objval.ProviderGCP:
+		return objval.Capabilities{
+			SupportsVersioning: true,
+			SupportsCompose:    true,
+			MaxCopySize:        math.MaxInt64,
+		}
+	}
+
+	return objval.Capabilities{}
+}
+
 func (t *TestClient) GetObject(_ context.Context, opts GetObjectOptions) (*objval.Object, error) {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
@@ -59,15 +97,30 @@ func (t *TestClient) GetObject(_ context.Context, opts GetObjectOptions) (*objva
 		return nil, err
 	}
 
+	if opts.NotModified(&object.ObjectAttrs) {
+		return nil, objerr.ErrNotModified
+	}
+
 	var offset, length int64 = 0, int64(len(object.Body) + 1)
 	if opts.ByteRange != nil {
 		offset, length = opts.ByteRange.ToOffsetLength(length)
 	}
 
-	return &objval.Object{
+	obj := &objval.Object{
 		ObjectAttrs: object.ObjectAttrs,
 		Body:        io.NopCloser(io.NewSectionReader(bytes.NewReader(object.Body), offset, length)),
-	}, nil
+	}
+
+	if !opts.Decompress {
+		return obj, nil
+	}
+
+	err = DecompressObject(obj, object.ContentEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	return obj, nil
 }
 
 func (t *TestClient) GetObjectAttrs(_ context.Context, opts GetObjectAttrsOptions) (*objval.ObjectAttrs, error) {
@@ -79,14 +132,75 @@ func (t *TestClient) GetObjectAttrs(_ context.Context, opts GetObjectAttrsOption
 		return nil, err
 	}
 
-	return &object.ObjectAttrs, nil
+	attrs := object.ObjectAttrs
+	attrs.Metadata = NormalizeMetadata(object.Metadata)
+
+	return &attrs, nil
 }
 
 func (t *TestClient) PutObject(_ context.Context, opts PutObjectOptions) error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	_ = t.putObjectLocked(opts.Bucket, opts.Key, opts.Body)
+	body, err := CompressBody(opts.Body, opts.Compress)
+	if err != nil {
+		return err
+	}
+
+	_ = t.putObjectLocked(opts.Bucket, opts.Key, body)
+
+	t.Buckets[opts.Bucket][opts.Key].ContentEncoding = string(opts.Compress)
+
+	if len(opts.Tags) != 0 {
+		t.Buckets[opts.Bucket][opts.Key].Tags = maps.Clone(opts.Tags)
+	}
+
+	t.Buckets[opts.Bucket][opts.Key].Metadata = maps.Clone(opts.Metadata)
+
+	return nil
+}
+
+func (t *TestClient) GetObjectTags(_ context.Context, opts GetObjectTagsOptions) (map[string]string, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	object, err := t.getObjectRLocked(opts.Bucket, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := maps.Clone(object.Tags)
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+
+	return tags, nil
+}
+
+func (t *TestClient) PutObjectTags(_ context.Context, opts PutObjectTagsOptions) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	object, err := t.getObjectRLocked(opts.Bucket, opts.Key)
+	if err != nil {
+		return err
+	}
+
+	object.Tags = maps.Clone(opts.Tags)
+
+	return nil
+}
+
+func (t *TestClient) DeleteObjectTags(_ context.Context, opts DeleteObjectTagsOptions) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	object, err := t.getObjectRLocked(opts.Bucket, opts.Key)
+	if err != nil {
+		return err
+	}
+
+	object.Tags = nil
 
 	return nil
 }
@@ -102,6 +216,8 @@ func (t *TestClient) CopyObject(_ context.Context, opts CopyObjectOptions) error
 
 	_ = t.putObjectLocked(opts.DestinationBucket, opts.DestinationKey, bytes.NewReader(src.Body))
 
+	t.Buckets[opts.DestinationBucket][opts.DestinationKey].Metadata = maps.Clone(src.Metadata)
+
 	return nil
 }
 
@@ -112,6 +228,7 @@ func (t *TestClient) AppendToObject(_ context.Context, opts AppendToObjectOption
 	object, ok := t.getBucketLocked(opts.Bucket)[opts.Key]
 	if ok {
 		object.Body = append(object.Body, testutil.ReadAll(t.t, opts.Body)...)
+		object.Size = ptr.To(int64(len(object.Body)))
 	} else {
 		_ = t.putObjectLocked(opts.Bucket, opts.Key, opts.Body)
 	}
@@ -125,8 +242,14 @@ func (t *TestClient) DeleteObjects(_ context.Context, opts DeleteObjectsOptions)
 
 	b := t.getBucketLocked(opts.Bucket)
 
-	for _, key := range opts.Keys {
-		delete(b, key)
+	for _, object := range opts.AllObjects() {
+		if object.VersionID != "" {
+			return objerr.ErrUnsupportedOperation
+		}
+	}
+
+	for _, object := range opts.AllObjects() {
+		delete(b, object.Key)
 	}
 
 	return nil
@@ -209,6 +332,19 @@ func (t *TestClient) Close() error {
 	return nil
 }
 
+// ListDeletedObjects is unsupported, soft delete isn't emulated by the test client.
+func (t *TestClient) ListDeletedObjects(
+	_ context.Context,
+	_ ListDeletedObjectsOptions,
+) ([]*objval.DeletedObject, error) {
+	return nil, objerr.ErrUnsupportedOperation
+}
+
+// UndeleteObject is unsupported, soft delete isn't emulated by the test client.
+func (t *TestClient) UndeleteObject(_ context.Context, _ UndeleteObjectOptions) error {
+	return objerr.ErrUnsupportedOperation
+}
+
 func (t *TestClient) CreateMultipartUpload(_ context.Context, _ CreateMultipartUploadOptions) (string, error) {
 	return uuid.NewString(), nil
 }
@@ -300,6 +436,8 @@ func (t *TestClient) CompleteMultipartUpload(_ context.Context, opts CompleteMul
 
 	_ = t.putObjectLocked(opts.Bucket, opts.Key, bytes.NewReader(buffer.Bytes()))
 
+	t.Buckets[opts.Bucket][opts.Key].Metadata = maps.Clone(opts.Metadata)
+
 	t.deleteKeysLocked(opts.Bucket, partPrefix(opts.UploadID, opts.Key), nil, nil)
 
 	return nil
@@ -314,6 +452,102 @@ func (t *TestClient) AbortMultipartUpload(_ context.Context, opts AbortMultipart
 	return nil
 }
 
+// ListMultipartUploads returns the uploads which have parts stored in the given bucket, uploads which have been created
+// but have no parts aren't returned.
+func (t *TestClient) ListMultipartUploads(
+	_ context.Context,
+	opts ListMultipartUploadsOptions,
+) ([]objval.MultipartUpload, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	uploads := make(map[string]*objval.MultipartUpload)
+
+	for key, object := range t.Buckets[opts.Bucket] {
+		matches := regexTestUploadPart.FindStringSubmatch(key)
+		if matches == nil || !strings.HasPrefix(matches[1], opts.Prefix) {
+			continue
+		}
+
+		upload, ok := uploads[matches[2]]
+		if !ok {
+			upload = &objval.MultipartUpload{Key: matches[1], UploadID: matches[2]}
+			uploads[matches[2]] = upload
+		}
+
+		modified := ptr.From(object.LastModified)
+
+		if upload.Initiated.IsZero() || modified.Before(upload.Initiated) {
+			upload.Initiated = modified
+		}
+	}
+
+	listed := make([]objval.MultipartUpload, 0, len(uploads))
+
+	for _, upload := range uploads {
+		listed = append(listed, *upload)
+	}
+
+	slices.SortFunc(listed, func(a, b objval.MultipartUpload) int {
+		return cmp.Or(strings.Compare(a.Key, b.Key), strings.Compare(a.UploadID, b.UploadID))
+	})
+
+	return listed, nil
+}
+
+func (t *TestClient) CreateBucket(_ context.Context, opts CreateBucketOptions) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	_ = t.getBucketLocked(opts.Bucket)
+
+	return nil
+}
+
+func (t *TestClient) DeleteBucket(_ context.Context, opts DeleteBucketOptions) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	b, ok := t.Buckets[opts.Bucket]
+	if !ok {
+		return &objerr.NotFoundError{Type: "bucket", Name: opts.Bucket}
+	}
+
+	if len(b) != 0 {
+		return fmt.Errorf("bucket '%s' is not empty", opts.Bucket)
+	}
+
+	delete(t.Buckets, opts.Bucket)
+
+	return nil
+}
+
+func (t *TestClient) GetBucketVersioning(
+	_ context.Context,
+	_ GetBucketVersioningOptions,
+) (objval.VersioningStatus, error) {
+	return objval.VersioningStatusDisabled, nil
+}
+
+func (t *TestClient) GetBucketRegion(_ context.Context, _ GetBucketRegionOptions) (string, error) {
+	return "", objerr.ErrUnsupportedOperation
+}
+
+func (t *TestClient) SetObjectLock(_ context.Context, _ SetObjectLockOptions) error {
+	return objerr.ErrUnsupportedOperation
+}
+
+func (t *TestClient) GetBucketLockingStatus(
+	_ context.Context,
+	_ GetBucketLockingStatusOptions,
+) (objval.BucketLockingStatus, error) {
+	return objval.BucketLockingStatus{}, objerr.ErrUnsupportedOperation
+}
+
+func (t *TestClient) QueryObject(_ context.Context, _ QueryObjectOptions) (io.ReadCloser, error) {
+	return nil, objerr.ErrUnsupportedOperation
+}
+
 func (t *TestClient) getBucketLocked(bucket string) objval.TestBucket {
 	_, ok := t.Buckets[bucket]
 	if !ok {
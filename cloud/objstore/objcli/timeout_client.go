@@ -0,0 +1,361 @@
+package objcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// TimeoutOptions encapsulates the options available when creating a 'TimeoutClient'.
+type TimeoutOptions struct {
+	// PerOperation is the maximum duration of a single operation, after which its context is cancelled. Zero/negative
+	// values disable the timeout.
+	//
+	// NOTE: Uploads are limited by this timeout, so it must allow for uploading the largest expected object/part.
+	PerOperation time.Duration
+
+	// List overrides 'PerOperation' for paginated operations, which may need to perform many requests e.g. iterating
+	// over a large bucket. Defaults to 'PerOperation', a negative value disables the timeout.
+	//
+	// NOTE: This includes the time spent in the callback provided to 'IterateObjects'.
+	List time.Duration
+}
+
+// defaults fills any missing attributes to a sane default.
+func (t *TimeoutOptions) defaults() {
+	if t.List == 0 {
+		t.List = t.PerOperation
+	}
+}
+
+// TimeoutClient implements the 'objcli.Client' interface by deferring to the underlying client, where each operation is
+// performed using a derived context which is cancelled once the operation times out; this ensures that a stuck network
+// call can't block the caller indefinitely.
+//
+// The methods which return a body (GetObject/QueryObject) use the timeout until the body is returned, then for each
+// read of the body; the duration of the download as a whole isn't limited.
+//
+// NOTE: Timeouts are reported using an error which wraps 'context.DeadlineExceeded'.
+type TimeoutClient struct {
+	c       Client
+	timeout time.Duration
+	list    time.Duration
+}
+
+var _ Client = (*TimeoutClient)(nil)
+
+// NewTimeoutClient returns a TimeoutClient, which wraps the given client using the given timeouts.
+func NewTimeoutClient(c Client, options TimeoutOptions) *TimeoutClient {
+	// Fill out any missing fields with the sane defaults
+	options.defaults()
+
+	return &TimeoutClient{c: c, timeout: options.PerOperation, list: options.List}
+}
+
+// withTimeout returns a context which is cancelled after the given timeout, where it's positive.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (t *TimeoutClient) Provider() objval.Provider {
+	return t.c.Provider()
+}
+
+func (t *TimeoutClient) Capabilities() objval.Capabilities {
+	return t.c.Capabilities()
+}
+
+func (t *TimeoutClient) GetObject(ctx context.Context, opts GetObjectOptions) (*objval.Object, error) {
+	var object *objval.Object
+
+	body, err := t.stream(ctx, func(ctx context.Context) (io.ReadCloser, error) {
+		var err error
+
+		object, err = t.c.GetObject(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return object.Body, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	object.Body = body
+
+	return object, nil
+}
+
+func (t *TimeoutClient) GetObjectAttrs(ctx context.Context, opts GetObjectAttrsOptions) (*objval.ObjectAttrs, error) {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.GetObjectAttrs(ctx, opts)
+}
+
+func (t *TimeoutClient) PutObject(ctx context.Context, opts PutObjectOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.PutObject(ctx, opts)
+}
+
+func (t *TimeoutClient) CopyObject(ctx context.Context, opts CopyObjectOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.CopyObject(ctx, opts)
+}
+
+func (t *TimeoutClient) AppendToObject(ctx context.Context, opts AppendToObjectOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.AppendToObject(ctx, opts)
+}
+
+func (t *TimeoutClient) GetObjectTags(ctx context.Context, opts GetObjectTagsOptions) (map[string]string, error) {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.GetObjectTags(ctx, opts)
+}
+
+func (t *TimeoutClient) PutObjectTags(ctx context.Context, opts PutObjectTagsOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.PutObjectTags(ctx, opts)
+}
+
+func (t *TimeoutClient) DeleteObjectTags(ctx context.Context, opts DeleteObjectTagsOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.DeleteObjectTags(ctx, opts)
+}
+
+func (t *TimeoutClient) DeleteObjects(ctx context.Context, opts DeleteObjectsOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.DeleteObjects(ctx, opts)
+}
+
+func (t *TimeoutClient) DeleteDirectory(ctx context.Context, opts DeleteDirectoryOptions) error {
+	ctx, cancel := withTimeout(ctx, t.list)
+	defer cancel()
+
+	return t.c.DeleteDirectory(ctx, opts)
+}
+
+func (t *TimeoutClient) IterateObjects(ctx context.Context, opts IterateObjectsOptions) error {
+	ctx, cancel := withTimeout(ctx, t.list)
+	defer cancel()
+
+	return t.c.IterateObjects(ctx, opts)
+}
+
+func (t *TimeoutClient) ListDeletedObjects(
+	ctx context.Context,
+	opts ListDeletedObjectsOptions,
+) ([]*objval.DeletedObject, error) {
+	ctx, cancel := withTimeout(ctx, t.list)
+	defer cancel()
+
+	return t.c.ListDeletedObjects(ctx, opts)
+}
+
+func (t *TimeoutClient) UndeleteObject(ctx context.Context, opts UndeleteObjectOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.UndeleteObject(ctx, opts)
+}
+
+func (t *TimeoutClient) CreateMultipartUpload(ctx context.Context, opts CreateMultipartUploadOptions) (string, error) {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.CreateMultipartUpload(ctx, opts)
+}
+
+func (t *TimeoutClient) ListParts(ctx context.Context, opts ListPartsOptions) ([]objval.Part, error) {
+	ctx, cancel := withTimeout(ctx, t.list)
+	defer cancel()
+
+	return t.c.ListParts(ctx, opts)
+}
+
+func (t *TimeoutClient) UploadPart(ctx context.Context, opts UploadPartOptions) (objval.Part, error) {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.UploadPart(ctx, opts)
+}
+
+func (t *TimeoutClient) UploadPartCopy(ctx context.Context, opts UploadPartCopyOptions) (objval.Part, error) {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.UploadPartCopy(ctx, opts)
+}
+
+func (t *TimeoutClient) CompleteMultipartUpload(ctx context.Context, opts CompleteMultipartUploadOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.CompleteMultipartUpload(ctx, opts)
+}
+
+func (t *TimeoutClient) AbortMultipartUpload(ctx context.Context, opts AbortMultipartUploadOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.AbortMultipartUpload(ctx, opts)
+}
+
+func (t *TimeoutClient) ListMultipartUploads(
+	ctx context.Context,
+	opts ListMultipartUploadsOptions,
+) ([]objval.MultipartUpload, error) {
+	ctx, cancel := withTimeout(ctx, t.list)
+	defer cancel()
+
+	return t.c.ListMultipartUploads(ctx, opts)
+}
+
+func (t *TimeoutClient) CreateBucket(ctx context.Context, opts CreateBucketOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.CreateBucket(ctx, opts)
+}
+
+func (t *TimeoutClient) DeleteBucket(ctx context.Context, opts DeleteBucketOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.DeleteBucket(ctx, opts)
+}
+
+func (t *TimeoutClient) GetBucketVersioning(
+	ctx context.Context,
+	opts GetBucketVersioningOptions,
+) (objval.VersioningStatus, error) {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.GetBucketVersioning(ctx, opts)
+}
+
+func (t *TimeoutClient) GetBucketRegion(ctx context.Context, opts GetBucketRegionOptions) (string, error) {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.GetBucketRegion(ctx, opts)
+}
+
+func (t *TimeoutClient) SetObjectLock(ctx context.Context, opts SetObjectLockOptions) error {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.SetObjectLock(ctx, opts)
+}
+
+func (t *TimeoutClient) GetBucketLockingStatus(
+	ctx context.Context,
+	opts GetBucketLockingStatusOptions,
+) (objval.BucketLockingStatus, error) {
+	ctx, cancel := withTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.c.GetBucketLockingStatus(ctx, opts)
+}
+
+func (t *TimeoutClient) QueryObject(ctx context.Context, opts QueryObjectOptions) (io.ReadCloser, error) {
+	return t.stream(ctx, func(ctx context.Context) (io.ReadCloser, error) { return t.c.QueryObject(ctx, opts) })
+}
+
+func (t *TimeoutClient) Close() error {
+	return t.c.Close()
+}
+
+// stream runs the given function which returns a body, applying the timeout until the body is returned, then to each
+// read of the body. The context used by the function remains valid until the returned body is closed.
+func (t *TimeoutClient) stream(
+	ctx context.Context,
+	fn func(ctx context.Context) (io.ReadCloser, error),
+) (io.ReadCloser, error) {
+	if t.timeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	timer := time.AfterFunc(t.timeout, func() { cancel(context.DeadlineExceeded) })
+
+	body, err := fn(ctx)
+
+	timer.Stop()
+
+	if err != nil {
+		cancel(nil)
+		return nil, timeoutError(ctx, err)
+	}
+
+	return &timeoutReadCloser{rc: body, ctx: ctx, cancel: cancel, timer: timer, timeout: t.timeout}, nil
+}
+
+// timeoutReadCloser is a body which cancels its context when a single read exceeds the timeout.
+type timeoutReadCloser struct {
+	rc      io.ReadCloser
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	timer   *time.Timer
+	timeout time.Duration
+	once    sync.Once
+}
+
+func (t *timeoutReadCloser) Read(p []byte) (int, error) {
+	t.timer.Reset(t.timeout)
+	n, err := t.rc.Read(p)
+	t.timer.Stop()
+
+	return n, timeoutError(t.ctx, err)
+}
+
+func (t *timeoutReadCloser) Close() error {
+	err := t.rc.Close()
+
+	t.once.Do(func() {
+		t.timer.Stop()
+		t.cancel(nil)
+	})
+
+	return err
+}
+
+// timeoutError returns the given error, wrapping it so that it's identifiable as a timeout where the given context was
+// cancelled because an operation timed out.
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, io.EOF) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	if !errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+}
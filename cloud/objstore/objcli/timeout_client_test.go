@@ -0,0 +1,132 @@
+package objcli
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// blockingReadCloser is a body which blocks reads until the given context is cancelled.
+type blockingReadCloser struct {
+	ctx context.Context
+}
+
+func (b blockingReadCloser) Read(_ []byte) (int, error) {
+	<-b.ctx.Done()
+	return 0, b.ctx.Err()
+}
+
+func (b blockingReadCloser) Close() error {
+	return nil
+}
+
+func TestTimeoutOptionsDefaults(t *testing.T) {
+	options := TimeoutOptions{PerOperation: time.Minute}
+	options.defaults()
+	require.Equal(t, time.Minute, options.List)
+
+	options = TimeoutOptions{PerOperation: time.Minute, List: -1}
+	options.defaults()
+	require.Equal(t, time.Duration(-1), options.List)
+}
+
+func TestTimeoutClientTimesOut(t *testing.T) {
+	client := &MockClient{}
+
+	client.
+		On("GetObjectAttrs", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, _ GetObjectAttrsOptions) (*objval.ObjectAttrs, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	_, err := NewTimeoutClient(client, TimeoutOptions{PerOperation: 10 * time.Millisecond}).
+		GetObjectAttrs(context.Background(), GetObjectAttrsOptions{Bucket: bucket, Key: key})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTimeoutClientListOverride(t *testing.T) {
+	client := &MockClient{}
+
+	var deadline time.Time
+
+	client.
+		On("IterateObjects", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, _ IterateObjectsOptions) error {
+			deadline, _ = ctx.Deadline()
+			return nil
+		})
+
+	start := time.Now()
+
+	err := NewTimeoutClient(client, TimeoutOptions{PerOperation: time.Second, List: time.Hour}).
+		IterateObjects(context.Background(), IterateObjectsOptions{Bucket: bucket})
+	require.NoError(t, err)
+	require.WithinDuration(t, start.Add(time.Hour), deadline, time.Minute)
+}
+
+func TestTimeoutClientDisabled(t *testing.T) {
+	client := &MockClient{}
+
+	client.
+		On("DeleteObjects", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, _ DeleteObjectsOptions) error {
+			_, ok := ctx.Deadline()
+			require.False(t, ok)
+
+			return nil
+		})
+
+	err := NewTimeoutClient(client, TimeoutOptions{}).
+		DeleteObjects(context.Background(), DeleteObjectsOptions{Bucket: bucket, Keys: []string{key}})
+	require.NoError(t, err)
+}
+
+func TestTimeoutClientGetObject(t *testing.T) {
+	client := NewTimeoutClient(NewTestClient(t, objval.ProviderAWS), TimeoutOptions{PerOperation: 50 * time.Millisecond})
+
+	err := client.PutObject(context.Background(), PutObjectOptions{
+		Bucket: bucket,
+		Key:    key,
+		Body:   strings.NewReader("value"),
+	})
+	require.NoError(t, err)
+
+	object, err := client.GetObject(context.Background(), GetObjectOptions{Bucket: bucket, Key: key})
+	require.NoError(t, err)
+
+	defer object.Body.Close()
+
+	// The body must remain readable after the timeout, providing each read completes within it
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+	require.Equal(t, "value", string(data))
+}
+
+func TestTimeoutClientGetObjectReadTimesOut(t *testing.T) {
+	client := &MockClient{}
+
+	client.
+		On("GetObject", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, _ GetObjectOptions) (*objval.Object, error) {
+			return &objval.Object{Body: blockingReadCloser{ctx: ctx}}, nil
+		})
+
+	object, err := NewTimeoutClient(client, TimeoutOptions{PerOperation: 10 * time.Millisecond}).
+		GetObject(context.Background(), GetObjectOptions{Bucket: bucket, Key: key})
+	require.NoError(t, err)
+
+	defer object.Body.Close()
+
+	_, err = io.ReadAll(object.Body)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
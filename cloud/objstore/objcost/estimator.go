@@ -0,0 +1,120 @@
+// Package objcost provides utilities to estimate the cost of transferring data to/from cloud providers, allowing tools
+// to warn users before performing expensive operations (e.g. restoring from archive storage).
+package objcost
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// bytesPerGB is the number of bytes in a GB, as used by cloud providers for billing.
+const bytesPerGB = 1024 * 1024 * 1024
+
+// PricingNotFoundError is returned when estimating the cost of a plan, for which there's no pricing.
+type PricingNotFoundError struct {
+	Provider     objval.Provider
+	Region       string
+	StorageClass StorageClass
+}
+
+// Error implements the 'error' interface.
+func (e *PricingNotFoundError) Error() string {
+	return fmt.Sprintf("no pricing for storage class '%s' in region '%s' of %s", e.StorageClass, e.Region, e.Provider)
+}
+
+// Plan describes a planned transfer, for which the cost should be estimated.
+type Plan struct {
+	// Provider is the cloud provider the data is being transferred to/from.
+	Provider objval.Provider
+
+	// Region is the region the data is stored in, the default pricing for the provider is used when there's no pricing
+	// for the region.
+	Region string
+
+	// StorageClass is the class of storage the data is stored in. Defaults to 'StorageClassStandard'.
+	StorageClass StorageClass
+
+	// BytesUploaded is the number of bytes which will be uploaded.
+	BytesUploaded int64
+
+	// BytesDownloaded is the number of bytes which will be downloaded.
+	BytesDownloaded int64
+
+	// WriteRequests is the number of write requests (e.g. PUT/POST/LIST) which will be made.
+	WriteRequests int64
+
+	// ReadRequests is the number of read requests (e.g. GET/HEAD) which will be made.
+	ReadRequests int64
+}
+
+// Estimate is the estimated cost of a plan, all costs are in USD.
+type Estimate struct {
+	// Requests is the cost of the requests made.
+	Requests float64
+
+	// Retrieval is the cost of retrieving the downloaded data from its storage class.
+	Retrieval float64
+
+	// Egress is the cost of transferring the downloaded data out of the provider.
+	//
+	// NOTE: Uploads are free of charge for all the supported providers.
+	Egress float64
+}
+
+// Total returns the total estimated cost.
+func (e Estimate) Total() float64 {
+	return e.Requests + e.Retrieval + e.Egress
+}
+
+// EstimatorOptions encapsulates the options available when creating an 'Estimator'.
+type EstimatorOptions struct {
+	// Overrides replaces entries in the bundled pricing table 'DefaultPricing', or adds pricing for specific regions.
+	Overrides PricingTable
+}
+
+// Estimator estimates the cost of planned transfers, using the bundled pricing table and any overrides.
+type Estimator struct {
+	pricing PricingTable
+}
+
+// NewEstimator returns a new estimator using the given options.
+func NewEstimator(options EstimatorOptions) *Estimator {
+	pricing := maps.Clone(DefaultPricing)
+
+	maps.Copy(pricing, options.Overrides)
+
+	return &Estimator{pricing: pricing}
+}
+
+// Estimate returns the estimated cost of the given plan, a 'PricingNotFoundError' is returned if there's no pricing for
+// the provider/storage class of the plan.
+func (e *Estimator) Estimate(plan Plan) (Estimate, error) {
+	if plan.StorageClass == "" {
+		plan.StorageClass = StorageClassStandard
+	}
+
+	pricing, ok := e.pricing.lookup(plan.Provider, plan.Region, plan.StorageClass)
+	if !ok {
+		return Estimate{}, &PricingNotFoundError{
+			Provider:     plan.Provider,
+			Region:       plan.Region,
+			StorageClass: plan.StorageClass,
+		}
+	}
+
+	var (
+		downloaded = float64(plan.BytesDownloaded) / bytesPerGB
+		writes     = float64(plan.WriteRequests) / 1000 * pricing.WriteRequests
+		reads      = float64(plan.ReadRequests) / 1000 * pricing.ReadRequests
+	)
+
+	estimate := Estimate{
+		Requests:  writes + reads,
+		Retrieval: downloaded * pricing.RetrievalPerGB,
+		Egress:    downloaded * pricing.EgressPerGB,
+	}
+
+	return estimate, nil
+}
@@ -0,0 +1,101 @@
+package objcost
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+func TestEstimatorEstimate(t *testing.T) {
+	type test struct {
+		name     string
+		plan     Plan
+		expected Estimate
+	}
+
+	tests := []*test{
+		{
+			name: "Empty",
+			plan: Plan{Provider: objval.ProviderAWS},
+		},
+		{
+			name: "UploadIsOnlyRequests",
+			plan: Plan{Provider: objval.ProviderAWS, BytesUploaded: 10 * bytesPerGB, WriteRequests: 2000},
+			expected: Estimate{
+				Requests: 0.01,
+			},
+		},
+		{
+			name: "Download",
+			plan: Plan{Provider: objval.ProviderGCP, BytesDownloaded: 10 * bytesPerGB, ReadRequests: 10_000},
+			expected: Estimate{
+				Requests: 0.004,
+				Egress:   1.2,
+			},
+		},
+		{
+			name: "DownloadFromArchive",
+			plan: Plan{
+				Provider:        objval.ProviderAzure,
+				Region:          "eastus",
+				StorageClass:    StorageClassArchive,
+				BytesDownloaded: 100 * bytesPerGB,
+				ReadRequests:    1000,
+			},
+			expected: Estimate{
+				Requests:  0.55,
+				Retrieval: 2.2,
+				Egress:    8.7,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := NewEstimator(EstimatorOptions{}).Estimate(test.plan)
+			require.NoError(t, err)
+			require.InDelta(t, test.expected.Requests, actual.Requests, 1e-9)
+			require.InDelta(t, test.expected.Retrieval, actual.Retrieval, 1e-9)
+			require.InDelta(t, test.expected.Egress, actual.Egress, 1e-9)
+			require.InDelta(t, test.expected.Total(), actual.Total(), 1e-9)
+		})
+	}
+}
+
+func TestEstimatorEstimateWithOverrides(t *testing.T) {
+	estimator := NewEstimator(EstimatorOptions{
+		Overrides: PricingTable{
+			{Provider: objval.ProviderAWS, Region: "eu-west-2", StorageClass: StorageClassStandard}: {EgressPerGB: 0.5},
+		},
+	})
+
+	plan := Plan{Provider: objval.ProviderAWS, Region: "eu-west-2", BytesDownloaded: 2 * bytesPerGB}
+
+	actual, err := estimator.Estimate(plan)
+	require.NoError(t, err)
+	require.InDelta(t, 1.0, actual.Total(), 1e-9)
+
+	// Other regions should still use the bundled pricing
+	plan.Region = "us-west-2"
+
+	actual, err = estimator.Estimate(plan)
+	require.NoError(t, err)
+	require.InDelta(t, 0.18, actual.Total(), 1e-9)
+
+	// The bundled pricing table must not be modified
+	key := PricingKey{Provider: objval.ProviderAWS, Region: "eu-west-2", StorageClass: StorageClassStandard}
+
+	_, ok := DefaultPricing[key]
+	require.False(t, ok)
+}
+
+func TestEstimatorEstimatePricingNotFound(t *testing.T) {
+	_, err := NewEstimator(EstimatorOptions{}).Estimate(Plan{Provider: objval.ProviderAWS, StorageClass: "unknown"})
+
+	var notFound *PricingNotFoundError
+
+	require.ErrorAs(t, err, &notFound)
+	require.Equal(t, StorageClass("unknown"), notFound.StorageClass)
+}
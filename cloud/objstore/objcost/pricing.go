@@ -0,0 +1,116 @@
+package objcost
+
+import "github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+
+// StorageClass represents a class of storage, generalized across cloud providers.
+type StorageClass string
+
+const (
+	// StorageClassStandard is the default class of storage e.g. S3 Standard, GCS Standard, or the Azure hot tier.
+	StorageClassStandard StorageClass = "standard"
+
+	// StorageClassInfrequent is storage for infrequently accessed data, which is cheaper to store but more expensive to
+	// access e.g. S3 Standard-IA, GCS Nearline, or the Azure cool tier.
+	StorageClassInfrequent StorageClass = "infrequent"
+
+	// StorageClassArchive is storage for archived data, which must be retrieved before it can be accessed e.g. S3 Glacier
+	// Flexible Retrieval, GCS Archive, or the Azure archive tier.
+	StorageClassArchive StorageClass = "archive"
+)
+
+// Pricing is the cost of accessing data in a storage class, all prices are in USD.
+type Pricing struct {
+	// WriteRequests is the cost per 1,000 write requests (e.g. PUT/POST/LIST, or "Class A" operations in GCS).
+	WriteRequests float64
+
+	// ReadRequests is the cost per 1,000 read requests (e.g. GET/HEAD, or "Class B" operations in GCS).
+	ReadRequests float64
+
+	// RetrievalPerGB is the cost per GB of data read from the storage class, in addition to any egress.
+	RetrievalPerGB float64
+
+	// EgressPerGB is the cost per GB of data transferred out of the provider to the internet.
+	EgressPerGB float64
+}
+
+// PricingKey identifies the pricing for a storage class, in a region of a cloud provider.
+type PricingKey struct {
+	Provider     objval.Provider
+	Region       string
+	StorageClass StorageClass
+}
+
+// PricingTable maps storage classes to their pricing. Entries with an empty region are the default for the provider,
+// which is used when there's no entry for a specific region.
+type PricingTable map[PricingKey]Pricing
+
+// DefaultPricing is the bundled pricing table, which contains the default pricing for each provider.
+//
+// NOTE: These are approximate list prices for a typical region (e.g. us-east-1), which don't account for free tiers,
+// volume discounts or negotiated rates; they should be overridden where accurate estimates are required.
+var DefaultPricing = PricingTable{
+	{Provider: objval.ProviderAWS, StorageClass: StorageClassStandard}: {
+		WriteRequests: 0.005,
+		ReadRequests:  0.0004,
+		EgressPerGB:   0.09,
+	},
+	{Provider: objval.ProviderAWS, StorageClass: StorageClassInfrequent}: {
+		WriteRequests:  0.01,
+		ReadRequests:   0.001,
+		RetrievalPerGB: 0.01,
+		EgressPerGB:    0.09,
+	},
+	{Provider: objval.ProviderAWS, StorageClass: StorageClassArchive}: {
+		WriteRequests:  0.03,
+		ReadRequests:   0.05,
+		RetrievalPerGB: 0.01,
+		EgressPerGB:    0.09,
+	},
+	{Provider: objval.ProviderGCP, StorageClass: StorageClassStandard}: {
+		WriteRequests: 0.005,
+		ReadRequests:  0.0004,
+		EgressPerGB:   0.12,
+	},
+	{Provider: objval.ProviderGCP, StorageClass: StorageClassInfrequent}: {
+		WriteRequests:  0.01,
+		ReadRequests:   0.001,
+		RetrievalPerGB: 0.01,
+		EgressPerGB:    0.12,
+	},
+	{Provider: objval.ProviderGCP, StorageClass: StorageClassArchive}: {
+		WriteRequests:  0.05,
+		ReadRequests:   0.05,
+		RetrievalPerGB: 0.05,
+		EgressPerGB:    0.12,
+	},
+	{Provider: objval.ProviderAzure, StorageClass: StorageClassStandard}: {
+		WriteRequests: 0.0065,
+		ReadRequests:  0.0005,
+		EgressPerGB:   0.087,
+	},
+	{Provider: objval.ProviderAzure, StorageClass: StorageClassInfrequent}: {
+		WriteRequests:  0.013,
+		ReadRequests:   0.001,
+		RetrievalPerGB: 0.01,
+		EgressPerGB:    0.087,
+	},
+	{Provider: objval.ProviderAzure, StorageClass: StorageClassArchive}: {
+		WriteRequests:  0.013,
+		ReadRequests:   0.55,
+		RetrievalPerGB: 0.022,
+		EgressPerGB:    0.087,
+	},
+}
+
+// lookup returns the pricing for the given storage class, falling back to the default for the provider where there's
+// no entry for the given region.
+func (p PricingTable) lookup(provider objval.Provider, region string, class StorageClass) (Pricing, bool) {
+	pricing, ok := p[PricingKey{Provider: provider, Region: region, StorageClass: class}]
+	if ok || region == "" {
+		return pricing, ok
+	}
+
+	pricing, ok = p[PricingKey{Provider: provider, StorageClass: class}]
+
+	return pricing, ok
+}
@@ -1,7 +1,10 @@
 // Package objerr provides error definitions used in 'objstore'.
 package objerr
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrUnauthenticated is returned if we've sent a request to a cloud provider and received a response indicating
@@ -12,4 +15,12 @@ var (
 	// attempted an operation where we don't have the valid permissions. This is typically a result of not having the
 	// correct RBAC permissions.
 	ErrUnauthorized = errors.New("authenticated user does not have the permission to access this resource")
+
+	// ErrForbidden is an alias of 'ErrUnauthorized', cloud providers typically respond with a 403 (Forbidden) in this
+	// case.
+	ErrForbidden = ErrUnauthorized
+
+	// ErrCredentialsExpired is returned if a request was rejected because the credentials (e.g. a session token) have
+	// expired, and they couldn't be refreshed. This wraps 'ErrUnauthenticated', so existing checks continue to match.
+	ErrCredentialsExpired = fmt.Errorf("credentials have expired and could not be refreshed: %w", ErrUnauthenticated)
 )
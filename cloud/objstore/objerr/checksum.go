@@ -0,0 +1,7 @@
+package objerr
+
+import "errors"
+
+// ErrChecksumMismatch is returned if the cloud provider has rejected an upload because the data received doesn't match
+// the checksum provided with the request, this indicates that the data was corrupted in transit.
+var ErrChecksumMismatch = errors.New("checksum of the uploaded data does not match the checksum provided")
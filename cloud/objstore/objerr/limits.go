@@ -0,0 +1,13 @@
+package objerr
+
+import "errors"
+
+var (
+	// ErrThrottled is returned if the cloud provider has rejected a request because we're sending requests too quickly,
+	// the request may succeed if retried after backing off.
+	ErrThrottled = errors.New("request was throttled by the cloud provider, please reduce the request rate")
+
+	// ErrQuotaExceeded is returned if the cloud provider has rejected a request because it would exceed a quota/limit
+	// on the account e.g. the maximum number of buckets; retrying the request is unlikely to succeed.
+	ErrQuotaExceeded = errors.New("request would exceed a quota imposed by the cloud provider")
+)
@@ -0,0 +1,7 @@
+package objerr
+
+import "errors"
+
+// ErrNotModified is returned by a conditional read, if the object hasn't been modified since the time/ETag provided;
+// the caller may use the copy of the object they already have.
+var ErrNotModified = errors.New("object has not been modified")
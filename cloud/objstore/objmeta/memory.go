@@ -0,0 +1,109 @@
+package objmeta
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+)
+
+// MemoryStore is an in-memory implementation of 'Store', which is useful for testing or where metadata doesn't need to
+// outlive the process.
+type MemoryStore struct {
+	lock    sync.Mutex
+	records map[string]*Record
+	version uint64
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns a new empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]*Record)}
+}
+
+func (m *MemoryStore) Get(_ context.Context, opts GetOptions) (*Record, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	record, ok := m.records[opts.Key]
+	if !ok {
+		return nil, &objerr.NotFoundError{Type: "record", Name: opts.Key}
+	}
+
+	return clone(record), nil
+}
+
+func (m *MemoryStore) Put(_ context.Context, opts PutOptions) (*Record, error) {
+	if opts.IfVersion != "" && opts.IfNotExists {
+		return nil, ErrIfVersionAndIfNotExistsAreMutuallyExclusive
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	existing, ok := m.records[opts.Key]
+
+	if opts.IfNotExists && ok {
+		return nil, ErrVersionMismatch
+	}
+
+	if opts.IfVersion != "" && (!ok || existing.Version != opts.IfVersion) {
+		return nil, ErrVersionMismatch
+	}
+
+	m.version++
+
+	record := &Record{
+		Key:     opts.Key,
+		Value:   slices.Clone(opts.Value),
+		Version: strconv.FormatUint(m.version, 10),
+	}
+
+	m.records[opts.Key] = record
+
+	return clone(record), nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, opts DeleteOptions) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	existing, ok := m.records[opts.Key]
+	if !ok {
+		return &objerr.NotFoundError{Type: "record", Name: opts.Key}
+	}
+
+	if opts.IfVersion != "" && existing.Version != opts.IfVersion {
+		return ErrVersionMismatch
+	}
+
+	delete(m.records, opts.Key)
+
+	return nil
+}
+
+func (m *MemoryStore) Query(_ context.Context, opts QueryOptions) ([]*Record, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	records := make([]*Record, 0)
+
+	for key, record := range m.records {
+		if strings.HasPrefix(key, opts.Prefix) {
+			records = append(records, clone(record))
+		}
+	}
+
+	slices.SortFunc(records, func(a, b *Record) int { return strings.Compare(a.Key, b.Key) })
+
+	return records, nil
+}
+
+// clone returns a deep copy of the given record, so that callers can't modify the stored record.
+func clone(record *Record) *Record {
+	return &Record{Key: record.Key, Value: slices.Clone(record.Value), Version: record.Version}
+}
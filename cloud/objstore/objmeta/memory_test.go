@@ -0,0 +1,90 @@
+package objmeta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+)
+
+func TestMemoryStoreGetNotFound(t *testing.T) {
+	_, err := NewMemoryStore().Get(context.Background(), GetOptions{Key: "key"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestMemoryStorePutGet(t *testing.T) {
+	store := NewMemoryStore()
+
+	put, err := store.Put(context.Background(), PutOptions{Key: "key", Value: []byte("value")})
+	require.NoError(t, err)
+	require.NotEmpty(t, put.Version)
+
+	got, err := store.Get(context.Background(), GetOptions{Key: "key"})
+	require.NoError(t, err)
+	require.Equal(t, put, got)
+
+	// Modifying the returned record must not modify the stored record
+	got.Value[0] = 'V'
+
+	got, err = store.Get(context.Background(), GetOptions{Key: "key"})
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), got.Value)
+}
+
+func TestMemoryStorePutPreconditions(t *testing.T) {
+	store := NewMemoryStore()
+
+	first, err := store.Put(context.Background(), PutOptions{Key: "key", Value: []byte("1"), IfNotExists: true})
+	require.NoError(t, err)
+
+	_, err = store.Put(context.Background(), PutOptions{Key: "key", Value: []byte("2"), IfNotExists: true})
+	require.ErrorIs(t, err, ErrVersionMismatch)
+
+	second, err := store.Put(context.Background(), PutOptions{Key: "key", Value: []byte("2"), IfVersion: first.Version})
+	require.NoError(t, err)
+	require.NotEqual(t, first.Version, second.Version)
+
+	// The first version is now stale
+	_, err = store.Put(context.Background(), PutOptions{Key: "key", Value: []byte("3"), IfVersion: first.Version})
+	require.ErrorIs(t, err, ErrVersionMismatch)
+
+	_, err = store.Put(context.Background(), PutOptions{Key: "key", IfVersion: second.Version, IfNotExists: true})
+	require.ErrorIs(t, err, ErrIfVersionAndIfNotExistsAreMutuallyExclusive)
+}
+
+func TestMemoryStoreDelete(t *testing.T) {
+	store := NewMemoryStore()
+
+	record, err := store.Put(context.Background(), PutOptions{Key: "key", Value: []byte("value")})
+	require.NoError(t, err)
+
+	err = store.Delete(context.Background(), DeleteOptions{Key: "key", IfVersion: "stale"})
+	require.ErrorIs(t, err, ErrVersionMismatch)
+
+	err = store.Delete(context.Background(), DeleteOptions{Key: "key", IfVersion: record.Version})
+	require.NoError(t, err)
+
+	err = store.Delete(context.Background(), DeleteOptions{Key: "key"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestMemoryStoreQuery(t *testing.T) {
+	store := NewMemoryStore()
+
+	for _, key := range []string{"journal/2", "manifest/1", "journal/1"} {
+		_, err := store.Put(context.Background(), PutOptions{Key: key, Value: []byte(key)})
+		require.NoError(t, err)
+	}
+
+	records, err := store.Query(context.Background(), QueryOptions{Prefix: "journal/"})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, "journal/1", records[0].Key)
+	require.Equal(t, "journal/2", records[1].Key)
+
+	records, err = store.Query(context.Background(), QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+}
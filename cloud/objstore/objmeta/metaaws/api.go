@@ -0,0 +1,22 @@
+package metaaws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+//go:generate mockery --all --case underscore --inpackage
+
+// serviceAPI is the minimal subset of functions that we use from the DynamoDB SDK, this allows for a greatly reduce
+// surface area for mock generation.
+//
+//nolint:lll
+type serviceAPI interface {
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+var _ serviceAPI = (*dynamodb.Client)(nil)
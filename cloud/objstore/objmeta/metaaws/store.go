@@ -0,0 +1,254 @@
+// Package metaaws provides an implementation of 'objmeta.Store' which stores records in an AWS DynamoDB table.
+package metaaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objmeta"
+)
+
+const (
+	// DefaultPartition is the partition key used for all records, when no partition is provided.
+	DefaultPartition = "objmeta"
+
+	// AttributePartition is the name of the string partition key of the table.
+	AttributePartition = "partition"
+
+	// AttributeKey is the name of the string sort key of the table, which contains the key of the record.
+	AttributeKey = "key"
+
+	// attributeValue is the name of the binary attribute containing the value of the record.
+	attributeValue = "value"
+
+	// attributeVersion is the name of the string attribute containing the version of the record.
+	attributeVersion = "version"
+)
+
+// StoreOptions encapsulates the options for creating a new DynamoDB metadata store.
+type StoreOptions struct {
+	// ServiceAPI is the minimal subset of functions that we use from the DynamoDB SDK, in general this should be the
+	// client created using 'dynamodb.NewFromConfig'.
+	//
+	// NOTE: Required
+	ServiceAPI serviceAPI
+
+	// Table is the name of the table in which the records are stored, it must have a string partition key named
+	// 'AttributePartition' and a string sort key named 'AttributeKey'.
+	//
+	// NOTE: Required
+	Table string
+
+	// Partition is the partition key used for all records, allowing multiple stores to share a table. Defaults to
+	// 'DefaultPartition'.
+	Partition string
+}
+
+// defaults fills any missing attributes to a sane default.
+func (s *StoreOptions) defaults() {
+	if s.Partition == "" {
+		s.Partition = DefaultPartition
+	}
+}
+
+// Store is an implementation of 'objmeta.Store' which stores records in a DynamoDB table, the records of a store are
+// all stored in a single partition so that they may be queried by prefix.
+//
+// NOTE: All reads are strongly consistent, and the value of each record is limited by the maximum DynamoDB item size
+// of 400KiB.
+type Store struct {
+	serviceAPI serviceAPI
+	table      string
+	partition  string
+}
+
+var _ objmeta.Store = (*Store)(nil)
+
+// NewStore returns a new store which uses the given 'serviceAPI'.
+func NewStore(opts StoreOptions) *Store {
+	// Fill out any missing fields with the sane defaults
+	opts.defaults()
+
+	return &Store{serviceAPI: opts.ServiceAPI, table: opts.Table, partition: opts.Partition}
+}
+
+func (s *Store) Get(ctx context.Context, opts objmeta.GetOptions) (*objmeta.Record, error) {
+	output, err := s.serviceAPI.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.table),
+		Key:            s.key(opts.Key),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, s.handleError(opts.Key, err)
+	}
+
+	if output.Item == nil {
+		return nil, &objerr.NotFoundError{Type: "record", Name: opts.Key}
+	}
+
+	return decode(output.Item), nil
+}
+
+func (s *Store) Put(ctx context.Context, opts objmeta.PutOptions) (*objmeta.Record, error) {
+	if opts.IfVersion != "" && opts.IfNotExists {
+		return nil, objmeta.ErrIfVersionAndIfNotExistsAreMutuallyExclusive
+	}
+
+	record := &objmeta.Record{Key: opts.Key, Value: opts.Value, Version: uuid.NewString()}
+
+	item := s.key(opts.Key)
+	item[attributeValue] = &types.AttributeValueMemberB{Value: record.Value}
+	item[attributeVersion] = &types.AttributeValueMemberS{Value: record.Version}
+
+	input := &dynamodb.PutItemInput{TableName: aws.String(s.table), Item: item}
+
+	switch {
+	case opts.IfNotExists:
+		input.ConditionExpression = aws.String("attribute_not_exists(#key)")
+		input.ExpressionAttributeNames = map[string]string{"#key": AttributeKey}
+	case opts.IfVersion != "":
+		input.ConditionExpression = aws.String("#version = :version")
+		input.ExpressionAttributeNames = map[string]string{"#version": attributeVersion}
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberS{Value: opts.IfVersion},
+		}
+	}
+
+	_, err := s.serviceAPI.PutItem(ctx, input)
+	if isConditionalCheckFailed(err) {
+		return nil, objmeta.ErrVersionMismatch
+	}
+
+	if err != nil {
+		return nil, s.handleError(opts.Key, err)
+	}
+
+	return record, nil
+}
+
+func (s *Store) Delete(ctx context.Context, opts objmeta.DeleteOptions) error {
+	input := &dynamodb.DeleteItemInput{
+		TableName:                           aws.String(s.table),
+		Key:                                 s.key(opts.Key),
+		ConditionExpression:                 aws.String("attribute_exists(#key)"),
+		ExpressionAttributeNames:            map[string]string{"#key": AttributeKey},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+
+	if opts.IfVersion != "" {
+		input.ConditionExpression = aws.String("attribute_exists(#key) AND #version = :version")
+		input.ExpressionAttributeNames["#version"] = attributeVersion
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberS{Value: opts.IfVersion},
+		}
+	}
+
+	_, err := s.serviceAPI.DeleteItem(ctx, input)
+
+	var failed *types.ConditionalCheckFailedException
+
+	// The existing item is returned when the condition fails, allowing us to distinguish a missing record from one
+	// with a different version
+	if errors.As(err, &failed) {
+		if failed.Item == nil {
+			return &objerr.NotFoundError{Type: "record", Name: opts.Key}
+		}
+
+		return objmeta.ErrVersionMismatch
+	}
+
+	if err != nil {
+		return s.handleError(opts.Key, err)
+	}
+
+	return nil
+}
+
+func (s *Store) Query(ctx context.Context, opts objmeta.QueryOptions) ([]*objmeta.Record, error) {
+	input := &dynamodb.QueryInput{
+		TableName:                aws.String(s.table),
+		KeyConditionExpression:   aws.String("#partition = :partition"),
+		ExpressionAttributeNames: map[string]string{"#partition": AttributePartition},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":partition": &types.AttributeValueMemberS{Value: s.partition},
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+
+	// DynamoDB rejects empty key condition values, so the condition is only added when there's a prefix
+	if opts.Prefix != "" {
+		input.KeyConditionExpression = aws.String("#partition = :partition AND begins_with(#key, :prefix)")
+		input.ExpressionAttributeNames["#key"] = AttributeKey
+		input.ExpressionAttributeValues[":prefix"] = &types.AttributeValueMemberS{Value: opts.Prefix}
+	}
+
+	records := make([]*objmeta.Record, 0)
+
+	for {
+		output, err := s.serviceAPI.Query(ctx, input)
+		if err != nil {
+			return nil, s.handleError(opts.Prefix, err)
+		}
+
+		// Items are returned in ascending order of their sort key, which is the key of the record
+		for _, item := range output.Items {
+			records = append(records, decode(item))
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return records, nil
+		}
+
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// key returns the primary key of the item for the record with the given key.
+func (s *Store) key(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		AttributePartition: &types.AttributeValueMemberS{Value: s.partition},
+		AttributeKey:       &types.AttributeValueMemberS{Value: key},
+	}
+}
+
+// handleError converts the given error into a user friendly error where possible.
+func (s *Store) handleError(key string, err error) error {
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return &objerr.NotFoundError{Type: "table", Name: s.table}
+	}
+
+	return fmt.Errorf("failed to access record '%s': %w", key, objerr.HandleError(err))
+}
+
+// isConditionalCheckFailed returns a boolean indicating whether the given error is because a condition wasn't met.
+func isConditionalCheckFailed(err error) bool {
+	var failed *types.ConditionalCheckFailedException
+	return errors.As(err, &failed)
+}
+
+// decode returns the record stored in the given item.
+func decode(item map[string]types.AttributeValue) *objmeta.Record {
+	var record objmeta.Record
+
+	if key, ok := item[AttributeKey].(*types.AttributeValueMemberS); ok {
+		record.Key = key.Value
+	}
+
+	if value, ok := item[attributeValue].(*types.AttributeValueMemberB); ok {
+		record.Value = value.Value
+	}
+
+	if version, ok := item[attributeVersion].(*types.AttributeValueMemberS); ok {
+		record.Version = version.Value
+	}
+
+	return &record
+}
@@ -0,0 +1,235 @@
+package metaaws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objmeta"
+	"github.com/couchbase/tools-common/testing/mock/matchers"
+)
+
+// item returns the DynamoDB item for the given record.
+func item(key, value, version string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		AttributePartition: &types.AttributeValueMemberS{Value: DefaultPartition},
+		AttributeKey:       &types.AttributeValueMemberS{Value: key},
+		attributeValue:     &types.AttributeValueMemberB{Value: []byte(value)},
+		attributeVersion:   &types.AttributeValueMemberS{Value: version},
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	api := newMockServiceAPI(t)
+
+	require.Equal(
+		t,
+		&Store{serviceAPI: api, table: "table", partition: DefaultPartition},
+		NewStore(StoreOptions{ServiceAPI: api, Table: "table"}),
+	)
+}
+
+func TestStoreGet(t *testing.T) {
+	api := newMockServiceAPI(t)
+
+	fn := func(input *dynamodb.GetItemInput) bool {
+		return *input.TableName == "table" &&
+			*input.ConsistentRead &&
+			input.Key[AttributePartition].(*types.AttributeValueMemberS).Value == DefaultPartition &&
+			input.Key[AttributeKey].(*types.AttributeValueMemberS).Value == "key"
+	}
+
+	api.
+		On("GetItem", matchers.Context, mock.MatchedBy(fn)).
+		Return(&dynamodb.GetItemOutput{Item: item("key", "value", "1")}, nil)
+
+	record, err := NewStore(StoreOptions{ServiceAPI: api, Table: "table"}).
+		Get(context.Background(), objmeta.GetOptions{Key: "key"})
+	require.NoError(t, err)
+	require.Equal(t, &objmeta.Record{Key: "key", Value: []byte("value"), Version: "1"}, record)
+}
+
+func TestStoreGetNotFound(t *testing.T) {
+	api := newMockServiceAPI(t)
+
+	api.On("GetItem", matchers.Context, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil)
+
+	_, err := NewStore(StoreOptions{ServiceAPI: api, Table: "table"}).
+		Get(context.Background(), objmeta.GetOptions{Key: "key"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestStoreGetTableNotFound(t *testing.T) {
+	api := newMockServiceAPI(t)
+
+	api.On("GetItem", matchers.Context, mock.Anything).Return(nil, &types.ResourceNotFoundException{})
+
+	_, err := NewStore(StoreOptions{ServiceAPI: api, Table: "table"}).
+		Get(context.Background(), objmeta.GetOptions{Key: "key"})
+
+	var notFound *objerr.NotFoundError
+
+	require.ErrorAs(t, err, &notFound)
+	require.Equal(t, &objerr.NotFoundError{Type: "table", Name: "table"}, notFound)
+}
+
+func TestStorePut(t *testing.T) {
+	type test struct {
+		name       string
+		opts       objmeta.PutOptions
+		condition  string
+		attributes map[string]string
+	}
+
+	tests := []test{
+		{
+			name: "Unconditional",
+			opts: objmeta.PutOptions{Key: "key", Value: []byte("value")},
+		},
+		{
+			name:       "IfNotExists",
+			opts:       objmeta.PutOptions{Key: "key", Value: []byte("value"), IfNotExists: true},
+			condition:  "attribute_not_exists(#key)",
+			attributes: map[string]string{"#key": AttributeKey},
+		},
+		{
+			name:       "IfVersion",
+			opts:       objmeta.PutOptions{Key: "key", Value: []byte("value"), IfVersion: "1"},
+			condition:  "#version = :version",
+			attributes: map[string]string{"#version": attributeVersion},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := newMockServiceAPI(t)
+
+			fn := func(input *dynamodb.PutItemInput) bool {
+				var condition string
+				if input.ConditionExpression != nil {
+					condition = *input.ConditionExpression
+				}
+
+				return *input.TableName == "table" &&
+					condition == test.condition &&
+					len(input.ExpressionAttributeNames) == len(test.attributes) &&
+					input.Item[AttributeKey].(*types.AttributeValueMemberS).Value == "key" &&
+					string(input.Item[attributeValue].(*types.AttributeValueMemberB).Value) == "value"
+			}
+
+			api.On("PutItem", matchers.Context, mock.MatchedBy(fn)).Return(&dynamodb.PutItemOutput{}, nil)
+
+			record, err := NewStore(StoreOptions{ServiceAPI: api, Table: "table"}).Put(context.Background(), test.opts)
+			require.NoError(t, err)
+			require.Equal(t, "key", record.Key)
+			require.Equal(t, []byte("value"), record.Value)
+			require.NotEmpty(t, record.Version)
+			require.NotEqual(t, test.opts.IfVersion, record.Version)
+		})
+	}
+}
+
+func TestStorePutVersionMismatch(t *testing.T) {
+	api := newMockServiceAPI(t)
+
+	api.On("PutItem", matchers.Context, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{})
+
+	_, err := NewStore(StoreOptions{ServiceAPI: api, Table: "table"}).
+		Put(context.Background(), objmeta.PutOptions{Key: "key", IfVersion: "1"})
+	require.ErrorIs(t, err, objmeta.ErrVersionMismatch)
+}
+
+func TestStorePutMutuallyExclusive(t *testing.T) {
+	_, err := NewStore(StoreOptions{}).
+		Put(context.Background(), objmeta.PutOptions{Key: "key", IfVersion: "1", IfNotExists: true})
+	require.ErrorIs(t, err, objmeta.ErrIfVersionAndIfNotExistsAreMutuallyExclusive)
+}
+
+func TestStoreDelete(t *testing.T) {
+	api := newMockServiceAPI(t)
+
+	fn := func(input *dynamodb.DeleteItemInput) bool {
+		return *input.ConditionExpression == "attribute_exists(#key) AND #version = :version" &&
+			input.ExpressionAttributeValues[":version"].(*types.AttributeValueMemberS).Value == "1" &&
+			input.ReturnValuesOnConditionCheckFailure == types.ReturnValuesOnConditionCheckFailureAllOld
+	}
+
+	api.On("DeleteItem", matchers.Context, mock.MatchedBy(fn)).Return(&dynamodb.DeleteItemOutput{}, nil)
+
+	err := NewStore(StoreOptions{ServiceAPI: api, Table: "table"}).
+		Delete(context.Background(), objmeta.DeleteOptions{Key: "key", IfVersion: "1"})
+	require.NoError(t, err)
+}
+
+func TestStoreDeleteConditionFailed(t *testing.T) {
+	api := newMockServiceAPI(t)
+
+	// The existing item is only returned when the record exists, but has a different version
+	api.
+		On("DeleteItem", matchers.Context, mock.Anything).
+		Return(nil, &types.ConditionalCheckFailedException{Item: item("key", "value", "2")}).
+		Once()
+
+	api.On("DeleteItem", matchers.Context, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+
+	store := NewStore(StoreOptions{ServiceAPI: api, Table: "table"})
+
+	err := store.Delete(context.Background(), objmeta.DeleteOptions{Key: "key", IfVersion: "1"})
+	require.ErrorIs(t, err, objmeta.ErrVersionMismatch)
+
+	err = store.Delete(context.Background(), objmeta.DeleteOptions{Key: "key"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestStoreQuery(t *testing.T) {
+	api := newMockServiceAPI(t)
+
+	first := func(input *dynamodb.QueryInput) bool {
+		return *input.KeyConditionExpression == "#partition = :partition AND begins_with(#key, :prefix)" &&
+			input.ExpressionAttributeValues[":prefix"].(*types.AttributeValueMemberS).Value == "journal/" &&
+			input.ExclusiveStartKey == nil
+	}
+
+	api.On("Query", matchers.Context, mock.MatchedBy(first)).Return(&dynamodb.QueryOutput{
+		Items:            []map[string]types.AttributeValue{item("journal/1", "1", "1")},
+		LastEvaluatedKey: item("journal/1", "1", "1"),
+	}, nil).Once()
+
+	// The next page should start after the last evaluated key
+	next := func(input *dynamodb.QueryInput) bool {
+		return input.ExclusiveStartKey != nil
+	}
+
+	api.On("Query", matchers.Context, mock.MatchedBy(next)).Return(&dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{item("journal/2", "2", "2")},
+	}, nil).Once()
+
+	records, err := NewStore(StoreOptions{ServiceAPI: api, Table: "table"}).
+		Query(context.Background(), objmeta.QueryOptions{Prefix: "journal/"})
+	require.NoError(t, err)
+	require.Equal(t, []*objmeta.Record{
+		{Key: "journal/1", Value: []byte("1"), Version: "1"},
+		{Key: "journal/2", Value: []byte("2"), Version: "2"},
+	}, records)
+}
+
+func TestStoreQueryWithoutPrefix(t *testing.T) {
+	api := newMockServiceAPI(t)
+
+	fn := func(input *dynamodb.QueryInput) bool {
+		_, ok := input.ExpressionAttributeValues[":prefix"]
+		return *input.KeyConditionExpression == "#partition = :partition" && !ok
+	}
+
+	api.On("Query", matchers.Context, mock.MatchedBy(fn)).Return(&dynamodb.QueryOutput{}, nil)
+
+	records, err := NewStore(StoreOptions{ServiceAPI: api, Table: "table"}).
+		Query(context.Background(), objmeta.QueryOptions{})
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
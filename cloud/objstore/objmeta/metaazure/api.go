@@ -0,0 +1,43 @@
+package metaazure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/aztables"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source ./api.go -destination ./mock_api.go -package metaazure
+
+// tableAPI is the minimal subset of functions that we use from the Azure Table storage SDK, this allows for a greatly
+// reduce surface area for mock generation.
+type tableAPI interface {
+	AddEntity(ctx context.Context, entity []byte, options *aztables.AddEntityOptions) (aztables.AddEntityResponse, error)
+	DeleteEntity(
+		ctx context.Context, partitionKey, rowKey string, options *aztables.DeleteEntityOptions,
+	) (aztables.DeleteEntityResponse, error)
+	GetEntity(
+		ctx context.Context, partitionKey, rowKey string, options *aztables.GetEntityOptions,
+	) (aztables.GetEntityResponse, error)
+	NewListEntitiesPager(options *aztables.ListEntitiesOptions) entitiesPager
+	UpdateEntity(
+		ctx context.Context, entity []byte, options *aztables.UpdateEntityOptions,
+	) (aztables.UpdateEntityResponse, error)
+	UpsertEntity(
+		ctx context.Context, entity []byte, options *aztables.UpsertEntityOptions,
+	) (aztables.UpsertEntityResponse, error)
+}
+
+type tableClient struct {
+	*aztables.Client
+}
+
+var _ tableAPI = tableClient{}
+
+func (t tableClient) NewListEntitiesPager(options *aztables.ListEntitiesOptions) entitiesPager {
+	return t.Client.NewListEntitiesPager(options)
+}
+
+type entitiesPager interface {
+	More() bool
+	NextPage(ctx context.Context) (aztables.ListEntitiesResponse, error)
+}
@@ -0,0 +1,260 @@
+// Package metaazure provides an implementation of 'objmeta.Store' which stores records in an Azure Table storage table.
+package metaazure
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/data/aztables"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objmeta"
+)
+
+const (
+	// DefaultPartition is the partition key used for all records, when no partition is provided.
+	DefaultPartition = "objmeta"
+
+	// propertyValue is the name of the binary property containing the value of the record.
+	propertyValue = "Value"
+)
+
+// StoreOptions encapsulates the options for creating a new Azure Table storage metadata store.
+type StoreOptions struct {
+	// Client is the client for the table in which the records are stored, in general this should be created using the
+	// 'aztables.NewServiceClient' function exposed by the SDK.
+	//
+	// NOTE: Required
+	Client *aztables.Client
+
+	// Partition is the partition key used for all records, allowing multiple stores to share a table. Defaults to
+	// 'DefaultPartition'.
+	//
+	// NOTE: Must not contain any of the characters disallowed in a partition key e.g. '/', '\', '#' or '?'.
+	Partition string
+}
+
+// defaults fills any missing attributes to a sane default.
+func (s *StoreOptions) defaults() {
+	if s.Partition == "" {
+		s.Partition = DefaultPartition
+	}
+}
+
+// Store is an implementation of 'objmeta.Store' which stores records in an Azure Table storage table, the records of a
+// store are all stored in a single partition so that they may be queried by prefix. The entity ETag is used as the
+// version of each record.
+//
+// NOTE: Row keys may not contain '/', so they're the hex encoded key of the record; as such, keys are limited to 512
+// bytes and values are limited by the maximum property size of 64KiB.
+type Store struct {
+	tableAPI  tableAPI
+	partition string
+}
+
+var _ objmeta.Store = (*Store)(nil)
+
+// NewStore returns a new store which uses the given table client.
+func NewStore(opts StoreOptions) *Store {
+	// Fill out any missing fields with the sane defaults
+	opts.defaults()
+
+	return &Store{tableAPI: tableClient{Client: opts.Client}, partition: opts.Partition}
+}
+
+func (s *Store) Get(ctx context.Context, opts objmeta.GetOptions) (*objmeta.Record, error) {
+	resp, err := s.tableAPI.GetEntity(ctx, s.partition, encodeKey(opts.Key), nil)
+	if err != nil {
+		return nil, handleError(opts.Key, err)
+	}
+
+	var entity aztables.EDMEntity
+
+	err = json.Unmarshal(resp.Value, &entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal entity: %w", err)
+	}
+
+	record, err := decode(entity)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	record.Version = string(resp.ETag)
+
+	return record, nil
+}
+
+func (s *Store) Put(ctx context.Context, opts objmeta.PutOptions) (*objmeta.Record, error) {
+	if opts.IfVersion != "" && opts.IfNotExists {
+		return nil, objmeta.ErrIfVersionAndIfNotExistsAreMutuallyExclusive
+	}
+
+	entity, err := json.Marshal(aztables.EDMEntity{
+		Entity:     aztables.Entity{PartitionKey: s.partition, RowKey: encodeKey(opts.Key)},
+		Properties: map[string]any{propertyValue: aztables.EDMBinary(opts.Value)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entity: %w", err)
+	}
+
+	var etag azcore.ETag
+
+	switch {
+	case opts.IfNotExists:
+		var resp aztables.AddEntityResponse
+
+		resp, err = s.tableAPI.AddEntity(ctx, entity, nil)
+		etag = resp.ETag
+	case opts.IfVersion != "":
+		var resp aztables.UpdateEntityResponse
+
+		resp, err = s.tableAPI.UpdateEntity(ctx, entity, &aztables.UpdateEntityOptions{
+			IfMatch:    (*azcore.ETag)(&opts.IfVersion),
+			UpdateMode: aztables.UpdateModeReplace,
+		})
+		etag = resp.ETag
+	default:
+		var resp aztables.UpsertEntityResponse
+
+		resp, err = s.tableAPI.UpsertEntity(
+			ctx,
+			entity,
+			&aztables.UpsertEntityOptions{UpdateMode: aztables.UpdateModeReplace},
+		)
+		etag = resp.ETag
+	}
+
+	// A missing entity is a version mismatch when the record is expected to exist
+	if isPreconditionFailed(err) || (opts.IfVersion != "" && isNotFound(err)) {
+		return nil, objmeta.ErrVersionMismatch
+	}
+
+	if err != nil {
+		return nil, handleError(opts.Key, err)
+	}
+
+	return &objmeta.Record{Key: opts.Key, Value: opts.Value, Version: string(etag)}, nil
+}
+
+func (s *Store) Delete(ctx context.Context, opts objmeta.DeleteOptions) error {
+	var options *aztables.DeleteEntityOptions
+
+	if opts.IfVersion != "" {
+		options = &aztables.DeleteEntityOptions{IfMatch: (*azcore.ETag)(&opts.IfVersion)}
+	}
+
+	_, err := s.tableAPI.DeleteEntity(ctx, s.partition, encodeKey(opts.Key), options)
+	if isPreconditionFailed(err) {
+		return objmeta.ErrVersionMismatch
+	}
+
+	if err != nil {
+		return handleError(opts.Key, err)
+	}
+
+	return nil
+}
+
+func (s *Store) Query(ctx context.Context, opts objmeta.QueryOptions) ([]*objmeta.Record, error) {
+	prefix := encodeKey(opts.Prefix)
+
+	// Hex encoding preserves the ordering of keys, and all the row keys with the prefix sort before the prefix followed
+	// by a character which isn't a hex digit
+	filter := fmt.Sprintf(
+		"PartitionKey eq '%s' and RowKey ge '%s' and RowKey lt '%sg'",
+		strings.ReplaceAll(s.partition, "'", "''"),
+		prefix,
+		prefix,
+	)
+
+	var (
+		pager   = s.tableAPI.NewListEntitiesPager(&aztables.ListEntitiesOptions{Filter: &filter})
+		records = make([]*objmeta.Record, 0)
+	)
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, handleError(opts.Prefix, err)
+		}
+
+		for _, data := range page.Entities {
+			var entity aztables.EDMEntity
+
+			err = json.Unmarshal(data, &entity)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unmarshal entity: %w", err)
+			}
+
+			record, err := decode(entity)
+			if err != nil {
+				return nil, err // Purposefully not wrapped
+			}
+
+			record.Version = entity.ETag
+
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+// encodeKey returns the row key for the record with the given key.
+func encodeKey(key string) string {
+	return hex.EncodeToString([]byte(key))
+}
+
+// decode returns the record stored in the given entity, excluding its version.
+func decode(entity aztables.EDMEntity) (*objmeta.Record, error) {
+	key, err := hex.DecodeString(entity.RowKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode row key '%s': %w", entity.RowKey, err)
+	}
+
+	value, _ := entity.Properties[propertyValue].(aztables.EDMBinary)
+
+	return &objmeta.Record{Key: string(key), Value: value}, nil
+}
+
+// handleError converts the given error into a user friendly error where possible.
+func handleError(key string, err error) error {
+	if isNotFound(err) {
+		return &objerr.NotFoundError{Type: "record", Name: key}
+	}
+
+	return fmt.Errorf("failed to access record '%s': %w", key, objerr.HandleError(err))
+}
+
+// isNotFound returns a boolean indicating whether the given error is because the entity doesn't exist.
+func isNotFound(err error) bool {
+	return hasCode(err, aztables.ResourceNotFound, aztables.EntityNotFound)
+}
+
+// isPreconditionFailed returns a boolean indicating whether the given error is because the entity already exists, or
+// has a different ETag.
+func isPreconditionFailed(err error) bool {
+	return hasCode(err, aztables.EntityAlreadyExists, aztables.UpdateConditionNotSatisfied)
+}
+
+// hasCode returns a boolean indicating whether the given error is a response error with one of the given codes.
+func hasCode(err error, codes ...aztables.TableErrorCode) bool {
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+
+	for _, code := range codes {
+		if respErr.ErrorCode == string(code) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,216 @@
+package metaazure
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/data/aztables"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objmeta"
+	"github.com/couchbase/tools-common/testing/mock/matchers"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// newTestStore returns a store which uses a mock table API.
+func newTestStore(t *testing.T) (*Store, *MocktableAPI) {
+	tAPI := NewMocktableAPI(gomock.NewController(t))
+	return &Store{tableAPI: tAPI, partition: DefaultPartition}, tAPI
+}
+
+// entity returns the marshalled entity for the given record.
+func entity(t *testing.T, key, value, etag string) []byte {
+	data, err := json.Marshal(aztables.EDMEntity{
+		Entity:     aztables.Entity{PartitionKey: DefaultPartition, RowKey: encodeKey(key)},
+		Properties: map[string]any{propertyValue: aztables.EDMBinary(value)},
+	})
+	require.NoError(t, err)
+
+	// The ETag is returned by the service using its odata annotation
+	var decoded map[string]any
+
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	decoded["odata.etag"] = etag
+
+	data, err = json.Marshal(decoded)
+	require.NoError(t, err)
+
+	return data
+}
+
+func respError(code aztables.TableErrorCode) error {
+	return &azcore.ResponseError{ErrorCode: string(code)}
+}
+
+func TestEncodeKey(t *testing.T) {
+	require.Equal(t, "6a6f75726e616c2f31", encodeKey("journal/1"))
+	require.Empty(t, encodeKey(""))
+}
+
+func TestNewStore(t *testing.T) {
+	store := NewStore(StoreOptions{})
+	require.Equal(t, DefaultPartition, store.partition)
+}
+
+func TestStoreGet(t *testing.T) {
+	store, tAPI := newTestStore(t)
+
+	tAPI.
+		EXPECT().
+		GetEntity(matchers.Context, DefaultPartition, encodeKey("key"), nil).
+		Return(aztables.GetEntityResponse{ETag: "etag", Value: entity(t, "key", "value", "etag")}, nil)
+
+	record, err := store.Get(context.Background(), objmeta.GetOptions{Key: "key"})
+	require.NoError(t, err)
+	require.Equal(t, &objmeta.Record{Key: "key", Value: []byte("value"), Version: "etag"}, record)
+}
+
+func TestStoreGetNotFound(t *testing.T) {
+	store, tAPI := newTestStore(t)
+
+	tAPI.
+		EXPECT().
+		GetEntity(matchers.Context, DefaultPartition, encodeKey("key"), nil).
+		Return(aztables.GetEntityResponse{}, respError(aztables.ResourceNotFound))
+
+	_, err := store.Get(context.Background(), objmeta.GetOptions{Key: "key"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestStorePut(t *testing.T) {
+	store, tAPI := newTestStore(t)
+
+	tAPI.
+		EXPECT().
+		UpsertEntity(matchers.Context, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context, data []byte, options *aztables.UpsertEntityOptions,
+		) (aztables.UpsertEntityResponse, error) {
+			var decoded aztables.EDMEntity
+
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			require.Equal(t, DefaultPartition, decoded.PartitionKey)
+			require.Equal(t, encodeKey("key"), decoded.RowKey)
+			require.Equal(t, aztables.EDMBinary("value"), decoded.Properties[propertyValue])
+			require.Equal(t, aztables.UpdateModeReplace, options.UpdateMode)
+
+			return aztables.UpsertEntityResponse{ETag: "etag"}, nil
+		})
+
+	record, err := store.Put(context.Background(), objmeta.PutOptions{Key: "key", Value: []byte("value")})
+	require.NoError(t, err)
+	require.Equal(t, &objmeta.Record{Key: "key", Value: []byte("value"), Version: "etag"}, record)
+}
+
+func TestStorePutIfNotExists(t *testing.T) {
+	store, tAPI := newTestStore(t)
+
+	tAPI.EXPECT().AddEntity(matchers.Context, gomock.Any(), nil).Return(aztables.AddEntityResponse{ETag: "1"}, nil)
+
+	tAPI.
+		EXPECT().
+		AddEntity(matchers.Context, gomock.Any(), nil).
+		Return(aztables.AddEntityResponse{}, respError(aztables.EntityAlreadyExists))
+
+	record, err := store.Put(context.Background(), objmeta.PutOptions{Key: "key", IfNotExists: true})
+	require.NoError(t, err)
+	require.Equal(t, "1", record.Version)
+
+	_, err = store.Put(context.Background(), objmeta.PutOptions{Key: "key", IfNotExists: true})
+	require.ErrorIs(t, err, objmeta.ErrVersionMismatch)
+}
+
+func TestStorePutIfVersion(t *testing.T) {
+	store, tAPI := newTestStore(t)
+
+	options := &aztables.UpdateEntityOptions{IfMatch: ptr.To(azcore.ETag("1")), UpdateMode: aztables.UpdateModeReplace}
+
+	tAPI.
+		EXPECT().
+		UpdateEntity(matchers.Context, gomock.Any(), options).
+		Return(aztables.UpdateEntityResponse{ETag: "2"}, nil)
+
+	// Both a stale ETag and a missing entity should be reported as a version mismatch
+	tAPI.
+		EXPECT().
+		UpdateEntity(matchers.Context, gomock.Any(), options).
+		Return(aztables.UpdateEntityResponse{}, respError(aztables.UpdateConditionNotSatisfied))
+
+	tAPI.
+		EXPECT().
+		UpdateEntity(matchers.Context, gomock.Any(), options).
+		Return(aztables.UpdateEntityResponse{}, respError(aztables.ResourceNotFound))
+
+	record, err := store.Put(context.Background(), objmeta.PutOptions{Key: "key", IfVersion: "1"})
+	require.NoError(t, err)
+	require.Equal(t, "2", record.Version)
+
+	for range 2 {
+		_, err = store.Put(context.Background(), objmeta.PutOptions{Key: "key", IfVersion: "1"})
+		require.ErrorIs(t, err, objmeta.ErrVersionMismatch)
+	}
+
+	_, err = store.Put(context.Background(), objmeta.PutOptions{Key: "key", IfVersion: "1", IfNotExists: true})
+	require.ErrorIs(t, err, objmeta.ErrIfVersionAndIfNotExistsAreMutuallyExclusive)
+}
+
+func TestStoreDelete(t *testing.T) {
+	store, tAPI := newTestStore(t)
+
+	options := &aztables.DeleteEntityOptions{IfMatch: ptr.To(azcore.ETag("1"))}
+
+	tAPI.
+		EXPECT().
+		DeleteEntity(matchers.Context, DefaultPartition, encodeKey("key"), options).
+		Return(aztables.DeleteEntityResponse{}, respError(aztables.UpdateConditionNotSatisfied))
+
+	tAPI.
+		EXPECT().
+		DeleteEntity(matchers.Context, DefaultPartition, encodeKey("key"), nil).
+		Return(aztables.DeleteEntityResponse{}, respError(aztables.ResourceNotFound))
+
+	err := store.Delete(context.Background(), objmeta.DeleteOptions{Key: "key", IfVersion: "1"})
+	require.ErrorIs(t, err, objmeta.ErrVersionMismatch)
+
+	err = store.Delete(context.Background(), objmeta.DeleteOptions{Key: "key"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestStoreQuery(t *testing.T) {
+	var (
+		store, tAPI = newTestStore(t)
+		pager       = NewMockentitiesPager(gomock.NewController(t))
+	)
+
+	tAPI.
+		EXPECT().
+		NewListEntitiesPager(gomock.Any()).
+		DoAndReturn(func(options *aztables.ListEntitiesOptions) entitiesPager {
+			require.Equal(
+				t,
+				"PartitionKey eq 'objmeta' and RowKey ge '6a6f75726e616c2f' and RowKey lt '6a6f75726e616c2fg'",
+				*options.Filter,
+			)
+
+			return pager
+		})
+
+	gomock.InOrder(
+		pager.EXPECT().More().Return(true),
+		pager.EXPECT().NextPage(matchers.Context).Return(aztables.ListEntitiesResponse{
+			Entities: [][]byte{entity(t, "journal/1", "1", "a"), entity(t, "journal/2", "2", "b")},
+		}, nil),
+		pager.EXPECT().More().Return(false),
+	)
+
+	records, err := store.Query(context.Background(), objmeta.QueryOptions{Prefix: "journal/"})
+	require.NoError(t, err)
+	require.Equal(t, []*objmeta.Record{
+		{Key: "journal/1", Value: []byte("1"), Version: "a"},
+		{Key: "journal/2", Value: []byte("2"), Version: "b"},
+	}, records)
+}
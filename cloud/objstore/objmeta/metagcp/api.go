@@ -0,0 +1,135 @@
+package metagcp
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+//go:generate mockery --all --case underscore --inpackage
+
+// document is the data stored in the Firestore document for a record.
+type document struct {
+	Key   string `firestore:"key"`
+	Value []byte `firestore:"value"`
+}
+
+// snapshot is a document, and the time it was last updated.
+type snapshot struct {
+	document
+	UpdateTime time.Time
+}
+
+// collectionAPI is the minimal subset of functions that we use from the Firestore SDK, documents are identified by
+// their ID within the collection.
+type collectionAPI interface {
+	Create(ctx context.Context, id string, doc document) (time.Time, error)
+	Delete(ctx context.Context, id string, updateTime time.Time) error
+	Get(ctx context.Context, id string) (*snapshot, error)
+	Query(ctx context.Context, start string) documentIteratorAPI
+	Set(ctx context.Context, id string, doc document) (time.Time, error)
+	Update(ctx context.Context, id string, doc document, updateTime time.Time) (time.Time, error)
+}
+
+// collectionClient implements the 'collectionAPI' interface and encapsulates the Firestore SDK into a unit testable
+// interface.
+type collectionClient struct {
+	ref *firestore.CollectionRef
+}
+
+var _ collectionAPI = collectionClient{}
+
+func (c collectionClient) Create(ctx context.Context, id string, doc document) (time.Time, error) {
+	result, err := c.ref.Doc(id).Create(ctx, doc)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return result.UpdateTime, nil
+}
+
+// Delete deletes the document with the given ID, only if it was last updated at the given time; a zero time only
+// requires that the document exists.
+func (c collectionClient) Delete(ctx context.Context, id string, updateTime time.Time) error {
+	precondition := firestore.Exists
+
+	if !updateTime.IsZero() {
+		precondition = firestore.LastUpdateTime(updateTime)
+	}
+
+	_, err := c.ref.Doc(id).Delete(ctx, precondition)
+
+	return err
+}
+
+func (c collectionClient) Get(ctx context.Context, id string) (*snapshot, error) {
+	return toSnapshot(c.ref.Doc(id).Get(ctx))
+}
+
+// Query returns an iterator over the documents whose key is greater than or equal to the given key, ordered by key.
+func (c collectionClient) Query(ctx context.Context, start string) documentIteratorAPI {
+	return documentIterator{
+		i: c.ref.Where("key", ">=", start).OrderBy("key", firestore.Asc).Documents(ctx),
+	}
+}
+
+func (c collectionClient) Set(ctx context.Context, id string, doc document) (time.Time, error) {
+	result, err := c.ref.Doc(id).Set(ctx, doc)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return result.UpdateTime, nil
+}
+
+// Update replaces the given document, only if it was last updated at the given time.
+func (c collectionClient) Update(
+	ctx context.Context, id string, doc document, updateTime time.Time,
+) (time.Time, error) {
+	updates := []firestore.Update{{Path: "key", Value: doc.Key}, {Path: "value", Value: doc.Value}}
+
+	result, err := c.ref.Doc(id).Update(ctx, updates, firestore.LastUpdateTime(updateTime))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return result.UpdateTime, nil
+}
+
+// documentIteratorAPI is an iterator over the documents returned by a query, 'iterator.Done' is returned once all the
+// documents have been returned.
+type documentIteratorAPI interface {
+	Next() (*snapshot, error)
+	Stop()
+}
+
+// documentIterator implements the 'documentIteratorAPI' interface and encapsulates the Firestore SDK into a unit
+// testable interface.
+type documentIterator struct {
+	i *firestore.DocumentIterator
+}
+
+func (d documentIterator) Next() (*snapshot, error) {
+	return toSnapshot(d.i.Next())
+}
+
+func (d documentIterator) Stop() {
+	d.i.Stop()
+}
+
+// toSnapshot converts the given Firestore snapshot into a 'snapshot'.
+func toSnapshot(remote *firestore.DocumentSnapshot, err error) (*snapshot, error) {
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	local := &snapshot{UpdateTime: remote.UpdateTime}
+
+	err = remote.DataTo(&local.document)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	return local, nil
+}
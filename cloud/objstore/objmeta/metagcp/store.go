@@ -0,0 +1,193 @@
+// Package metagcp provides an implementation of 'objmeta.Store' which stores records in a GCP Firestore collection.
+package metagcp
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objmeta"
+)
+
+// StoreOptions encapsulates the options for creating a new Firestore metadata store.
+type StoreOptions struct {
+	// Client is the Firestore client, in general this should be created using the 'firestore.NewClient' function
+	// exposed by the SDK.
+	//
+	// NOTE: Required
+	Client *firestore.Client
+
+	// Collection is the path of the collection in which the records are stored, allowing multiple stores to share a
+	// database.
+	//
+	// NOTE: Required
+	Collection string
+}
+
+// Store is an implementation of 'objmeta.Store' which stores records as documents in a Firestore collection, the update
+// time of each document is used as the version of the record.
+//
+// NOTE: Document IDs may not contain '/', so they're the hex encoded key of the record; as such, keys are limited to
+// 750 bytes and values are limited by the maximum document size of 1MiB.
+type Store struct {
+	collectionAPI collectionAPI
+}
+
+var _ objmeta.Store = (*Store)(nil)
+
+// NewStore returns a new store which uses the given Firestore client.
+func NewStore(opts StoreOptions) *Store {
+	return &Store{collectionAPI: collectionClient{ref: opts.Client.Collection(opts.Collection)}}
+}
+
+func (s *Store) Get(ctx context.Context, opts objmeta.GetOptions) (*objmeta.Record, error) {
+	snapshot, err := s.collectionAPI.Get(ctx, encodeKey(opts.Key))
+	if err != nil {
+		return nil, handleError(opts.Key, err)
+	}
+
+	return decode(snapshot), nil
+}
+
+func (s *Store) Put(ctx context.Context, opts objmeta.PutOptions) (*objmeta.Record, error) {
+	if opts.IfVersion != "" && opts.IfNotExists {
+		return nil, objmeta.ErrIfVersionAndIfNotExistsAreMutuallyExclusive
+	}
+
+	var (
+		id         = encodeKey(opts.Key)
+		doc        = document{Key: opts.Key, Value: opts.Value}
+		updateTime time.Time
+		err        error
+	)
+
+	switch {
+	case opts.IfNotExists:
+		updateTime, err = s.collectionAPI.Create(ctx, id, doc)
+	case opts.IfVersion != "":
+		var expected time.Time
+
+		expected, err = decodeVersion(opts.IfVersion)
+		if err != nil {
+			return nil, err // Purposefully not wrapped
+		}
+
+		updateTime, err = s.collectionAPI.Update(ctx, id, doc, expected)
+	default:
+		updateTime, err = s.collectionAPI.Set(ctx, id, doc)
+	}
+
+	// A missing document is a version mismatch when the record is expected to exist
+	if isPreconditionFailed(err) || (opts.IfVersion != "" && status.Code(err) == codes.NotFound) {
+		return nil, objmeta.ErrVersionMismatch
+	}
+
+	if err != nil {
+		return nil, handleError(opts.Key, err)
+	}
+
+	return &objmeta.Record{Key: opts.Key, Value: opts.Value, Version: encodeVersion(updateTime)}, nil
+}
+
+func (s *Store) Delete(ctx context.Context, opts objmeta.DeleteOptions) error {
+	var expected time.Time
+
+	if opts.IfVersion != "" {
+		var err error
+
+		expected, err = decodeVersion(opts.IfVersion)
+		if err != nil {
+			return err // Purposefully not wrapped
+		}
+	}
+
+	err := s.collectionAPI.Delete(ctx, encodeKey(opts.Key), expected)
+	if isPreconditionFailed(err) {
+		return objmeta.ErrVersionMismatch
+	}
+
+	if err != nil {
+		return handleError(opts.Key, err)
+	}
+
+	return nil
+}
+
+func (s *Store) Query(ctx context.Context, opts objmeta.QueryOptions) ([]*objmeta.Record, error) {
+	var (
+		iter    = s.collectionAPI.Query(ctx, opts.Prefix)
+		records = make([]*objmeta.Record, 0)
+	)
+
+	defer iter.Stop()
+
+	for {
+		snapshot, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			return records, nil
+		}
+
+		if err != nil {
+			return nil, handleError(opts.Prefix, err)
+		}
+
+		// Documents are returned in ascending order of their key, so the first one without the prefix is past the end of
+		// the range
+		if !strings.HasPrefix(snapshot.Key, opts.Prefix) {
+			return records, nil
+		}
+
+		records = append(records, decode(snapshot))
+	}
+}
+
+// encodeKey returns the document ID for the record with the given key.
+func encodeKey(key string) string {
+	return hex.EncodeToString([]byte(key))
+}
+
+// encodeVersion returns the version of a record last updated at the given time.
+func encodeVersion(updateTime time.Time) string {
+	return updateTime.UTC().Format(time.RFC3339Nano)
+}
+
+// decodeVersion returns the update time encoded in the given version, a version which wasn't returned by this store
+// can't match any record so is reported as a mismatch.
+func decodeVersion(version string) (time.Time, error) {
+	updateTime, err := time.Parse(time.RFC3339Nano, version)
+	if err != nil {
+		return time.Time{}, objmeta.ErrVersionMismatch
+	}
+
+	return updateTime, nil
+}
+
+// decode returns the record stored in the given snapshot.
+func decode(snapshot *snapshot) *objmeta.Record {
+	return &objmeta.Record{Key: snapshot.Key, Value: snapshot.Value, Version: encodeVersion(snapshot.UpdateTime)}
+}
+
+// handleError converts the given error into a user friendly error where possible.
+func handleError(key string, err error) error {
+	if status.Code(err) == codes.NotFound {
+		return &objerr.NotFoundError{Type: "record", Name: key}
+	}
+
+	return fmt.Errorf("failed to access record '%s': %w", key, objerr.HandleError(err))
+}
+
+// isPreconditionFailed returns a boolean indicating whether the given error is because the document already exists, or
+// has a different update time.
+func isPreconditionFailed(err error) bool {
+	code := status.Code(err)
+	return code == codes.AlreadyExists || code == codes.FailedPrecondition
+}
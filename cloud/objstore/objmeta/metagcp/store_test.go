@@ -0,0 +1,192 @@
+package metagcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objmeta"
+	"github.com/couchbase/tools-common/testing/mock/matchers"
+)
+
+var (
+	first  = time.Date(2024, 1, 1, 0, 0, 0, 1, time.UTC)
+	second = time.Date(2024, 1, 1, 0, 0, 0, 2, time.UTC)
+)
+
+func TestEncodeKey(t *testing.T) {
+	require.Equal(t, "6a6f75726e616c2f31", encodeKey("journal/1"))
+}
+
+func TestVersion(t *testing.T) {
+	decoded, err := decodeVersion(encodeVersion(first))
+	require.NoError(t, err)
+	require.True(t, first.Equal(decoded))
+
+	_, err = decodeVersion("etag")
+	require.ErrorIs(t, err, objmeta.ErrVersionMismatch)
+}
+
+func TestStoreGet(t *testing.T) {
+	api := newMockCollectionAPI(t)
+
+	api.
+		On("Get", matchers.Context, encodeKey("key")).
+		Return(&snapshot{document: document{Key: "key", Value: []byte("value")}, UpdateTime: first}, nil)
+
+	record, err := (&Store{collectionAPI: api}).Get(context.Background(), objmeta.GetOptions{Key: "key"})
+	require.NoError(t, err)
+	require.Equal(t, &objmeta.Record{Key: "key", Value: []byte("value"), Version: encodeVersion(first)}, record)
+}
+
+func TestStoreGetNotFound(t *testing.T) {
+	api := newMockCollectionAPI(t)
+
+	api.On("Get", matchers.Context, encodeKey("key")).Return(nil, status.Error(codes.NotFound, "not found"))
+
+	_, err := (&Store{collectionAPI: api}).Get(context.Background(), objmeta.GetOptions{Key: "key"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestStorePut(t *testing.T) {
+	api := newMockCollectionAPI(t)
+
+	doc := document{Key: "key", Value: []byte("value")}
+
+	api.On("Set", matchers.Context, encodeKey("key"), doc).Return(first, nil)
+
+	record, err := (&Store{collectionAPI: api}).
+		Put(context.Background(), objmeta.PutOptions{Key: "key", Value: []byte("value")})
+	require.NoError(t, err)
+	require.Equal(t, &objmeta.Record{Key: "key", Value: []byte("value"), Version: encodeVersion(first)}, record)
+}
+
+func TestStorePutIfNotExists(t *testing.T) {
+	api := newMockCollectionAPI(t)
+
+	api.On("Create", matchers.Context, encodeKey("key"), mock.Anything).Return(first, nil).Once()
+
+	api.
+		On("Create", matchers.Context, encodeKey("key"), mock.Anything).
+		Return(time.Time{}, status.Error(codes.AlreadyExists, "exists")).
+		Once()
+
+	store := &Store{collectionAPI: api}
+
+	record, err := store.Put(context.Background(), objmeta.PutOptions{Key: "key", IfNotExists: true})
+	require.NoError(t, err)
+	require.Equal(t, encodeVersion(first), record.Version)
+
+	_, err = store.Put(context.Background(), objmeta.PutOptions{Key: "key", IfNotExists: true})
+	require.ErrorIs(t, err, objmeta.ErrVersionMismatch)
+}
+
+func TestStorePutIfVersion(t *testing.T) {
+	api := newMockCollectionAPI(t)
+
+	fn := func(updateTime time.Time) bool { return updateTime.Equal(first) }
+
+	api.On("Update", matchers.Context, encodeKey("key"), mock.Anything, mock.MatchedBy(fn)).Return(second, nil).Once()
+
+	// Both a stale update time and a missing document should be reported as a version mismatch
+	api.
+		On("Update", matchers.Context, encodeKey("key"), mock.Anything, mock.MatchedBy(fn)).
+		Return(time.Time{}, status.Error(codes.FailedPrecondition, "precondition")).
+		Once()
+
+	api.
+		On("Update", matchers.Context, encodeKey("key"), mock.Anything, mock.MatchedBy(fn)).
+		Return(time.Time{}, status.Error(codes.NotFound, "not found")).
+		Once()
+
+	store := &Store{collectionAPI: api}
+
+	record, err := store.Put(context.Background(), objmeta.PutOptions{Key: "key", IfVersion: encodeVersion(first)})
+	require.NoError(t, err)
+	require.Equal(t, encodeVersion(second), record.Version)
+
+	for range 2 {
+		_, err = store.Put(context.Background(), objmeta.PutOptions{Key: "key", IfVersion: encodeVersion(first)})
+		require.ErrorIs(t, err, objmeta.ErrVersionMismatch)
+	}
+
+	// Versions which weren't returned by the store can never match
+	_, err = store.Put(context.Background(), objmeta.PutOptions{Key: "key", IfVersion: "etag"})
+	require.ErrorIs(t, err, objmeta.ErrVersionMismatch)
+
+	_, err = store.Put(context.Background(), objmeta.PutOptions{Key: "key", IfVersion: "1", IfNotExists: true})
+	require.ErrorIs(t, err, objmeta.ErrIfVersionAndIfNotExistsAreMutuallyExclusive)
+}
+
+func TestStoreDelete(t *testing.T) {
+	api := newMockCollectionAPI(t)
+
+	fn := func(updateTime time.Time) bool { return updateTime.Equal(first) }
+
+	api.
+		On("Delete", matchers.Context, encodeKey("key"), mock.MatchedBy(fn)).
+		Return(status.Error(codes.FailedPrecondition, "precondition"))
+
+	api.On("Delete", matchers.Context, encodeKey("key"), time.Time{}).Return(status.Error(codes.NotFound, "not found"))
+
+	store := &Store{collectionAPI: api}
+
+	err := store.Delete(context.Background(), objmeta.DeleteOptions{Key: "key", IfVersion: encodeVersion(first)})
+	require.ErrorIs(t, err, objmeta.ErrVersionMismatch)
+
+	err = store.Delete(context.Background(), objmeta.DeleteOptions{Key: "key"})
+	require.True(t, objerr.IsNotFoundError(err))
+}
+
+func TestStoreQuery(t *testing.T) {
+	var (
+		api  = newMockCollectionAPI(t)
+		iter = newMockDocumentIteratorAPI(t)
+	)
+
+	api.On("Query", matchers.Context, "journal/").Return(iter)
+
+	iter.
+		On("Next").
+		Return(&snapshot{document: document{Key: "journal/1", Value: []byte("1")}, UpdateTime: first}, nil).
+		Once()
+
+	iter.
+		On("Next").
+		Return(&snapshot{document: document{Key: "journal/2", Value: []byte("2")}, UpdateTime: second}, nil).
+		Once()
+
+	// The query is unbounded, so iteration should stop at the first key without the prefix
+	iter.On("Next").Return(&snapshot{document: document{Key: "journam"}}, nil).Once()
+	iter.On("Stop").Return().Once()
+
+	records, err := (&Store{collectionAPI: api}).Query(context.Background(), objmeta.QueryOptions{Prefix: "journal/"})
+	require.NoError(t, err)
+	require.Equal(t, []*objmeta.Record{
+		{Key: "journal/1", Value: []byte("1"), Version: encodeVersion(first)},
+		{Key: "journal/2", Value: []byte("2"), Version: encodeVersion(second)},
+	}, records)
+}
+
+func TestStoreQueryDone(t *testing.T) {
+	var (
+		api  = newMockCollectionAPI(t)
+		iter = newMockDocumentIteratorAPI(t)
+	)
+
+	api.On("Query", matchers.Context, "").Return(iter)
+
+	iter.On("Next").Return(nil, iterator.Done).Once()
+	iter.On("Stop").Return().Once()
+
+	records, err := (&Store{collectionAPI: api}).Query(context.Background(), objmeta.QueryOptions{})
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
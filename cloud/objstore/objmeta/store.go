@@ -0,0 +1,89 @@
+// Package objmeta provides a small keyed metadata store abstraction, which may be used to track state (e.g. backup
+// manifests or multipart upload journals) without relying on listing objects in object storage.
+package objmeta
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrVersionMismatch is returned when a conditional write is rejected because the record has been modified since
+	// it was read, or already exists when it was expected not to.
+	ErrVersionMismatch = errors.New("record version does not match the expected version")
+
+	// ErrIfVersionAndIfNotExistsAreMutuallyExclusive is returned if the user attempts to put a record using both the
+	// 'IfVersion' and 'IfNotExists' preconditions.
+	ErrIfVersionAndIfNotExistsAreMutuallyExclusive = errors.New("'IfVersion' and 'IfNotExists' are mutually exclusive")
+)
+
+// Record is a single keyed value in a metadata store.
+type Record struct {
+	// Key uniquely identifies the record within the store.
+	Key string
+
+	// Value is the opaque value of the record.
+	Value []byte
+
+	// Version identifies the revision of the record, it changes each time the record is written and should be treated
+	// as opaque; it may be used to perform conditional writes.
+	Version string
+}
+
+// GetOptions encapsulates the options available when using the 'Get' function.
+type GetOptions struct {
+	// Key is the key of the record to get.
+	Key string
+}
+
+// PutOptions encapsulates the options available when using the 'Put' function.
+type PutOptions struct {
+	// Key is the key of the record to write.
+	Key string
+
+	// Value is the value to write.
+	Value []byte
+
+	// IfVersion only writes the record if its current version matches the given version.
+	IfVersion string
+
+	// IfNotExists only writes the record if it doesn't already exist.
+	IfNotExists bool
+}
+
+// DeleteOptions encapsulates the options available when using the 'Delete' function.
+type DeleteOptions struct {
+	// Key is the key of the record to delete.
+	Key string
+
+	// IfVersion only deletes the record if its current version matches the given version.
+	IfVersion string
+}
+
+// QueryOptions encapsulates the options available when using the 'Query' function.
+type QueryOptions struct {
+	// Prefix limits the returned records to those whose key has the given prefix, an empty prefix returns all the
+	// records in the store.
+	Prefix string
+}
+
+// Store is a keyed metadata store, which supports optimistic concurrency control using record versions.
+//
+// NOTE: Implementations are expected to be safe for concurrent use, and to return an 'objerr.NotFoundError' when
+// accessing a record which doesn't exist. See the 'metaaws', 'metaazure' and 'metagcp' packages for implementations
+// backed by DynamoDB, Table storage and Firestore respectively.
+type Store interface {
+	// Get returns the record with the given key.
+	Get(ctx context.Context, opts GetOptions) (*Record, error)
+
+	// Put writes the given record, returning it with its new version; 'ErrVersionMismatch' is returned if any of the
+	// given preconditions aren't met.
+	Put(ctx context.Context, opts PutOptions) (*Record, error)
+
+	// Delete removes the record with the given key; 'ErrVersionMismatch' is returned if the given precondition isn't
+	// met.
+	Delete(ctx context.Context, opts DeleteOptions) error
+
+	// Query returns the records whose key has the given prefix, ordered by key.
+	Query(ctx context.Context, opts QueryOptions) ([]*Record, error)
+}
@@ -0,0 +1,191 @@
+package objutil
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"google.golang.org/api/option"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli/objaws"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli/objazure"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli/objgcp"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// ClientForURIOptions encapsulates the options available when using the 'NewClientForURI' function.
+type ClientForURIOptions struct {
+	// Logger is passed to the created client.
+	Logger *slog.Logger
+
+	// AWSConfig is the configuration used to create S3 clients.
+	//
+	// NOTE: When omitted, the config is loaded using the 'config.LoadDefaultConfig' function exposed by the SDK, which
+	// resolves the region and credentials from the environment, shared config files, and the instance metadata service.
+	AWSConfig *aws.Config
+
+	// AWSOptions are applied to the options used to create S3 clients e.g. to set a custom endpoint.
+	AWSOptions []func(*s3.Options)
+
+	// GCPOptions are passed when creating Google Storage clients, application default credentials are used unless
+	// credentials are provided.
+	GCPOptions []option.ClientOption
+
+	// GCPUserProject is the project which will be billed for all the operations performed by the client.
+	GCPUserProject string
+
+	// GCPProjectID is the project in which buckets will be created.
+	GCPProjectID string
+
+	// AzureAccountURL is the URL of the Azure Blob Storage service e.g. 'https://account.blob.core.windows.net/'.
+	//
+	// NOTE: When omitted, the URL is built using the 'AZURE_STORAGE_ACCOUNT' environment variable.
+	AzureAccountURL string
+
+	// AzureCredential is used to authenticate requests to Azure Blob Storage.
+	//
+	// NOTE: When omitted, a shared key is used if the 'AZURE_STORAGE_ACCOUNT' and 'AZURE_STORAGE_KEY' environment
+	// variables are set, otherwise an 'objazure.TokenCredential' is used.
+	AzureCredential azcore.TokenCredential
+}
+
+// defaults fills any missing attributes to a sane default.
+func (o *ClientForURIOptions) defaults() {
+	if o.Logger == nil {
+		o.Logger = slog.Default()
+	}
+}
+
+// NewClientForURI returns a client for the cloud provider indicated by the scheme of the given URI (e.g. 's3://',
+// 'gs://' or 'az://') along with the parsed URI, which contains the bucket and prefix.
+//
+// NOTE: This lives in 'objutil' rather than 'objcli' because the provider specific clients depend upon 'objcli', so it
+// can't construct them without creating an import cycle. The returned client should be closed once it's no longer
+// required.
+func NewClientForURI(
+	ctx context.Context,
+	uri string,
+	opts ClientForURIOptions,
+) (objcli.Client, *CloudOrFileURL, error) {
+	opts.defaults()
+
+	parsed, err := ParseCloudOrFileURL(uri)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse URI: %w", err)
+	}
+
+	var client objcli.Client
+
+	switch parsed.Provider {
+	case objval.ProviderAWS:
+		client, err = newAWSClient(ctx, opts)
+	case objval.ProviderGCP:
+		client, err = newGCPClient(ctx, opts)
+	case objval.ProviderAzure:
+		client, err = newAzureClient(opts)
+	default:
+		return nil, nil, ErrNotCloudURI
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s client: %w", parsed.Provider, err)
+	}
+
+	return client, parsed, nil
+}
+
+// newAWSClient returns a client for S3, loading the default config if none is given.
+func newAWSClient(ctx context.Context, opts ClientForURIOptions) (objcli.Client, error) {
+	cfg := opts.AWSConfig
+	if cfg == nil {
+		loaded, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load default config: %w", err)
+		}
+
+		cfg = &loaded
+	}
+
+	if cfg.Region == "" {
+		return nil, ErrAWSRegionRequired
+	}
+
+	return objaws.NewClient(objaws.ClientOptions{
+		ServiceAPI: objaws.NewS3Client(objaws.S3ClientOptions{Config: *cfg, Options: opts.AWSOptions}),
+		Logger:     opts.Logger,
+	}), nil
+}
+
+// newGCPClient returns a client for Google Storage.
+func newGCPClient(ctx context.Context, opts ClientForURIOptions) (objcli.Client, error) {
+	client, err := storage.NewClient(ctx, opts.GCPOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return objgcp.NewClient(objgcp.ClientOptions{
+		Client:      client,
+		Logger:      opts.Logger,
+		UserProject: opts.GCPUserProject,
+		ProjectID:   opts.GCPProjectID,
+	}), nil
+}
+
+// newAzureClient returns a client for Azure Blob Storage.
+func newAzureClient(opts ClientForURIOptions) (objcli.Client, error) {
+	var (
+		account = os.Getenv("AZURE_STORAGE_ACCOUNT")
+		key     = os.Getenv("AZURE_STORAGE_KEY")
+		url     = opts.AzureAccountURL
+	)
+
+	if url == "" && account == "" {
+		return nil, ErrAzureAccountRequired
+	}
+
+	if url == "" {
+		url = fmt.Sprintf("https://%s.blob.core.windows.net/", account)
+	}
+
+	var (
+		client *service.Client
+		err    error
+	)
+
+	switch {
+	case opts.AzureCredential != nil:
+		client, err = service.NewClient(url, opts.AzureCredential, nil)
+	case account != "" && key != "":
+		var credential *service.SharedKeyCredential
+
+		credential, err = service.NewSharedKeyCredential(account, key)
+		if err != nil {
+			return nil, err
+		}
+
+		client, err = service.NewClientWithSharedKeyCredential(url, credential, nil)
+	default:
+		var credential *objazure.TokenCredential
+
+		credential, err = objazure.NewTokenCredential()
+		if err != nil {
+			return nil, err
+		}
+
+		client, err = service.NewClient(url, credential, nil)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return objazure.NewClient(objazure.ClientOptions{Client: client}), nil
+}
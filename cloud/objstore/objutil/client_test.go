@@ -0,0 +1,109 @@
+package objutil
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+func TestNewClientForURI(t *testing.T) {
+	type test struct {
+		name             string
+		uri              string
+		expectedProvider objval.Provider
+		expectedBucket   string
+		expectedPath     string
+	}
+
+	tests := []test{
+		{
+			name:             "AWS",
+			uri:              "s3://bucket/path/to/prefix/",
+			expectedProvider: objval.ProviderAWS,
+			expectedBucket:   "bucket",
+			expectedPath:     "path/to/prefix/",
+		},
+		{
+			name:             "GCP",
+			uri:              "gs://bucket/prefix",
+			expectedProvider: objval.ProviderGCP,
+			expectedBucket:   "bucket",
+			expectedPath:     "prefix",
+		},
+		{
+			name:             "Azure",
+			uri:              "az://container",
+			expectedProvider: objval.ProviderAzure,
+			expectedBucket:   "container",
+		},
+	}
+
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "access-key-id")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret-access-key")
+	t.Setenv("AZURE_STORAGE_ACCOUNT", "account")
+	t.Setenv("AZURE_STORAGE_KEY", "a2V5")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	opts := ClientForURIOptions{GCPOptions: []option.ClientOption{option.WithoutAuthentication()}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, url, err := NewClientForURI(context.Background(), test.uri, opts)
+			require.NoError(t, err)
+
+			defer client.Close()
+
+			require.Equal(t, test.expectedProvider, client.Provider())
+			require.Equal(t, test.expectedProvider, url.Provider)
+			require.Equal(t, test.expectedBucket, url.Bucket)
+			require.Equal(t, test.expectedPath, url.Path)
+		})
+	}
+}
+
+func TestNewClientForURIMissingCredentials(t *testing.T) {
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION", "AWS_ACCESS_KEY_ID", "AZURE_STORAGE_ACCOUNT"} {
+		t.Setenv(name, "")
+	}
+
+	// Ensure the shared config files for the current user aren't loaded
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	_, _, err := NewClientForURI(context.Background(), "s3://bucket", ClientForURIOptions{})
+	require.ErrorIs(t, err, ErrAWSRegionRequired)
+
+	_, _, err = NewClientForURI(context.Background(), "az://container", ClientForURIOptions{})
+	require.ErrorIs(t, err, ErrAzureAccountRequired)
+}
+
+func TestNewClientForURIWithAWSConfig(t *testing.T) {
+	// The given config should be used, rather than one loaded from the environment
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	client, _, err := NewClientForURI(context.Background(), "s3://bucket", ClientForURIOptions{
+		AWSConfig: &aws.Config{Region: "us-east-1"},
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.Equal(t, objval.ProviderAWS, client.Provider())
+}
+
+func TestNewClientForURINotCloud(t *testing.T) {
+	_, _, err := NewClientForURI(context.Background(), "/path/to/archive", ClientForURIOptions{})
+	require.ErrorIs(t, err, ErrNotCloudURI)
+
+	_, _, err = NewClientForURI(context.Background(), "ftp://bucket", ClientForURIOptions{})
+	require.Error(t, err)
+}
@@ -0,0 +1,318 @@
+package objutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+const (
+	// DefaultCompactThreshold is the default size at which objects are considered large enough that they shouldn't be
+	// compacted.
+	DefaultCompactThreshold = MinPartSize
+
+	// DefaultCompactMaxObjectSize is the default maximum size of the objects created when compacting.
+	DefaultCompactMaxObjectSize = 1024 * 1024 * 1024
+
+	// CompactIndexName is the name of the index object, written to the destination prefix by 'Compact'.
+	CompactIndexName = "index.json"
+)
+
+// CompactIndexEntry is the location of an original object, within a compacted object.
+type CompactIndexEntry struct {
+	// Object is the key of the compacted object which contains the original object.
+	Object string `json:"object"`
+
+	// Offset is the offset of the original object, within the compacted object.
+	Offset int64 `json:"offset"`
+
+	// Length is the length of the original object.
+	Length int64 `json:"length"`
+}
+
+// CompactIndex maps the keys of the original objects, to their location within the compacted objects.
+type CompactIndex map[string]CompactIndexEntry
+
+// CompactOptions encapsulates the available options which can be used when compacting the objects under a prefix.
+type CompactOptions struct {
+	Options
+
+	// Client is the client used to perform the operation.
+	//
+	// NOTE: This attribute is required.
+	Client objcli.Client
+
+	// Bucket is the bucket containing the objects being compacted, the compacted objects are written to the same
+	// bucket.
+	//
+	// NOTE: This attribute is required.
+	Bucket string
+
+	// Prefix is the prefix containing the objects which will be compacted.
+	//
+	// NOTE: This attribute is required.
+	Prefix string
+
+	// Include allows selecting keys which only match any of the given expressions.
+	Include []*regexp.Regexp
+
+	// Exclude allows skipping keys which may any of the given expressions.
+	Exclude []*regexp.Regexp
+
+	// DestinationPrefix is the prefix under which the compacted objects, and the index ('CompactIndexName') are
+	// written.
+	//
+	// NOTE: This attribute is required, and must not be within 'Prefix'.
+	DestinationPrefix string
+
+	// Threshold is the size at which objects are not compacted, and are left in place. Defaults to
+	// 'DefaultCompactThreshold'.
+	Threshold int64
+
+	// MaxObjectSize is the maximum size of a compacted object, defaults to 'DefaultCompactMaxObjectSize'.
+	MaxObjectSize int64
+
+	// DeleteSource deletes the original objects, once they've been compacted and the index has been written.
+	DeleteSource bool
+
+	// Logger is the logger that'll be used.
+	Logger *slog.Logger
+}
+
+// defaults fills any missing attributes to a sane default.
+func (c *CompactOptions) defaults() {
+	c.Options.defaults()
+
+	if c.Threshold <= 0 {
+		c.Threshold = DefaultCompactThreshold
+	}
+
+	if c.MaxObjectSize <= 0 {
+		c.MaxObjectSize = DefaultCompactMaxObjectSize
+	}
+
+	if c.Logger == nil {
+		c.Logger = slog.Default()
+	}
+}
+
+// Compact concatenates the small objects under a prefix into fewer, larger, objects using multipart uploads; an index
+// mapping the original keys to their location within the compacted objects is written alongside them. This may be used
+// to reduce the cost of listing/storing a prefix which contains many tiny objects.
+//
+// NOTE: Objects are downloaded and re-uploaded, rather than copied, because cloud providers have a minimum part size.
+func Compact(opts CompactOptions) (CompactIndex, error) {
+	// Fill out any missing fields with the sane defaults
+	opts.defaults()
+
+	if strings.HasPrefix(opts.DestinationPrefix, opts.Prefix) {
+		return nil, ErrCompactDestinationWithinPrefix
+	}
+
+	var keys []string
+
+	fn := func(attrs *objval.ObjectAttrs) error {
+		if !attrs.IsDir() && ptr.From(attrs.Size) < opts.Threshold {
+			keys = append(keys, attrs.Key)
+		}
+
+		return nil
+	}
+
+	err := opts.Client.IterateObjects(opts.Context, objcli.IterateObjectsOptions{
+		Bucket:  opts.Bucket,
+		Prefix:  opts.Prefix,
+		Include: opts.Include,
+		Exclude: opts.Exclude,
+		Func:    fn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate objects: %w", err)
+	}
+
+	// Not all clients list objects in lexicographical order, sort them so that the compacted objects are deterministic
+	slices.Sort(keys)
+
+	compactor := &compactor{opts: opts, index: make(CompactIndex)}
+
+	for _, key := range keys {
+		err = compactor.add(key)
+		if err != nil {
+			compactor.abort()
+			return nil, fmt.Errorf("failed to compact object '%s': %w", key, err)
+		}
+	}
+
+	err = compactor.commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit compacted object: %w", err)
+	}
+
+	err = writeCompactIndex(opts, compactor.index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write index: %w", err)
+	}
+
+	if !opts.DeleteSource || len(keys) == 0 {
+		return compactor.index, nil
+	}
+
+	err = opts.Client.DeleteObjects(opts.Context, objcli.DeleteObjectsOptions{Bucket: opts.Bucket, Keys: keys})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete compacted objects: %w", err)
+	}
+
+	return compactor.index, nil
+}
+
+// compactor concatenates objects into a compacted object, starting a new compacted object once the maximum size is
+// reached.
+type compactor struct {
+	opts  CompactOptions
+	index CompactIndex
+
+	uploader *MPUploader
+	key      string
+	count    int
+	offset   int64
+	buffer   bytes.Buffer
+}
+
+// add appends the object with the given key to the current compacted object.
+func (c *compactor) add(key string) error {
+	object, err := c.opts.Client.GetObject(c.opts.Context, objcli.GetObjectOptions{
+		Bucket:           c.opts.Bucket,
+		Key:              key,
+		BandwidthLimiter: c.opts.BandwidthLimiter,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get object: %w", err)
+	}
+	defer object.Body.Close()
+
+	if c.uploader != nil && c.offset+ptr.From(object.Size) > c.opts.MaxObjectSize {
+		err = c.commit()
+		if err != nil {
+			return fmt.Errorf("failed to commit compacted object: %w", err)
+		}
+	}
+
+	if c.uploader == nil {
+		err = c.begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin compacted object: %w", err)
+		}
+	}
+
+	n, err := io.Copy(&c.buffer, object.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read object: %w", err)
+	}
+
+	c.index[key] = CompactIndexEntry{Object: c.key, Offset: c.offset, Length: n}
+	c.offset += n
+
+	for int64(c.buffer.Len()) >= c.opts.PartSize {
+		err = c.upload(c.opts.PartSize)
+		if err != nil {
+			return err // Purposefully not wrapped
+		}
+	}
+
+	return nil
+}
+
+// begin starts a new compacted object.
+func (c *compactor) begin() error {
+	c.count++
+	c.key = path.Join(c.opts.DestinationPrefix, fmt.Sprintf("%08d", c.count))
+	c.offset = 0
+
+	uploader, err := NewMPUploader(MPUploaderOptions{
+		Options: c.opts.Options,
+		Client:  c.opts.Client,
+		Bucket:  c.opts.Bucket,
+		Key:     c.key,
+	})
+	if err != nil {
+		return err // Purposefully not wrapped
+	}
+
+	c.uploader = uploader
+
+	return nil
+}
+
+// upload the given number of bytes from the buffer, as a part of the current compacted object.
+func (c *compactor) upload(n int64) error {
+	// Parts are uploaded concurrently, so must not share the buffers memory
+	part := bytes.Clone(c.buffer.Next(int(n)))
+
+	err := c.uploader.Upload(bytes.NewReader(part))
+	if err != nil {
+		return fmt.Errorf("failed to upload part: %w", err)
+	}
+
+	return nil
+}
+
+// commit uploads any remaining data, and completes the current compacted object.
+func (c *compactor) commit() error {
+	if c.uploader == nil {
+		return nil
+	}
+
+	// The remaining data forms the final part, which is allowed to be smaller than the part size. An empty part is only
+	// uploaded when all the compacted objects were empty, because a multipart upload requires at least one part.
+	if c.buffer.Len() > 0 || c.offset == 0 {
+		err := c.upload(int64(c.buffer.Len()))
+		if err != nil {
+			c.abort()
+			return err // Purposefully not wrapped
+		}
+	}
+
+	err := c.uploader.Commit()
+	c.uploader = nil
+
+	return err
+}
+
+// abort the current compacted object, if any.
+func (c *compactor) abort() {
+	if c.uploader == nil {
+		return
+	}
+
+	err := c.uploader.Abort()
+	if err != nil {
+		c.opts.Logger.Warn("failed to abort compacted object", "key", c.key, "error", err)
+	}
+
+	c.uploader = nil
+}
+
+// writeCompactIndex writes the given index to the destination prefix.
+func writeCompactIndex(opts CompactOptions, index CompactIndex) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+
+	return opts.Client.PutObject(opts.Context, objcli.PutObjectOptions{
+		Bucket:           opts.Bucket,
+		Key:              path.Join(opts.DestinationPrefix, CompactIndexName),
+		Body:             bytes.NewReader(data),
+		BandwidthLimiter: opts.BandwidthLimiter,
+	})
+}
@@ -0,0 +1,166 @@
+package objutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	testutil "github.com/couchbase/tools-common/testing/util"
+)
+
+func putCompactTestObjects(t *testing.T, client objcli.Client, objects map[string]string) {
+	for key, body := range objects {
+		err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+			Bucket: "bucket",
+			Key:    key,
+			Body:   bytes.NewReader([]byte(body)),
+		})
+		require.NoError(t, err)
+	}
+}
+
+func readCompactedObject(t *testing.T, client objcli.Client, entry CompactIndexEntry) string {
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket: "bucket",
+		Key:    entry.Object,
+	})
+	require.NoError(t, err)
+
+	return string(testutil.ReadAll(t, object.Body)[entry.Offset : entry.Offset+entry.Length])
+}
+
+func TestCompactDestinationWithinPrefix(t *testing.T) {
+	_, err := Compact(CompactOptions{Prefix: "prefix", DestinationPrefix: "prefix/compacted"})
+	require.ErrorIs(t, err, ErrCompactDestinationWithinPrefix)
+}
+
+func TestCompact(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	objects := map[string]string{
+		"src/key1":       "1",
+		"src/key2":       "22",
+		"src/nested/key": "333",
+	}
+
+	putCompactTestObjects(t, client, objects)
+
+	index, err := Compact(CompactOptions{
+		Client:            client,
+		Bucket:            "bucket",
+		Prefix:            "src",
+		DestinationPrefix: "dst",
+	})
+	require.NoError(t, err)
+
+	expected := CompactIndex{
+		"src/key1":       {Object: "dst/00000001", Offset: 0, Length: 1},
+		"src/key2":       {Object: "dst/00000001", Offset: 1, Length: 2},
+		"src/nested/key": {Object: "dst/00000001", Offset: 3, Length: 3},
+	}
+
+	require.Equal(t, expected, index)
+
+	for key, body := range objects {
+		require.Equal(t, body, readCompactedObject(t, client, index[key]))
+	}
+
+	// The index should have been written alongside the compacted objects
+	object, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+		Bucket: "bucket",
+		Key:    "dst/" + CompactIndexName,
+	})
+	require.NoError(t, err)
+
+	var written CompactIndex
+
+	require.NoError(t, json.Unmarshal(testutil.ReadAll(t, object.Body), &written))
+	require.Equal(t, expected, written)
+
+	// The original objects should not have been deleted
+	for key := range objects {
+		_, err = client.GetObjectAttrs(context.Background(), objcli.GetObjectAttrsOptions{Bucket: "bucket", Key: key})
+		require.NoError(t, err)
+	}
+}
+
+func TestCompactMaxObjectSize(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	putCompactTestObjects(t, client, map[string]string{
+		"src/key1": "11",
+		"src/key2": "22",
+		"src/key3": "33",
+	})
+
+	index, err := Compact(CompactOptions{
+		Client:            client,
+		Bucket:            "bucket",
+		Prefix:            "src",
+		DestinationPrefix: "dst",
+		MaxObjectSize:     4,
+	})
+	require.NoError(t, err)
+
+	expected := CompactIndex{
+		"src/key1": {Object: "dst/00000001", Offset: 0, Length: 2},
+		"src/key2": {Object: "dst/00000001", Offset: 2, Length: 2},
+		"src/key3": {Object: "dst/00000002", Offset: 0, Length: 2},
+	}
+
+	require.Equal(t, expected, index)
+	require.Equal(t, "33", readCompactedObject(t, client, index["src/key3"]))
+}
+
+func TestCompactThreshold(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	putCompactTestObjects(t, client, map[string]string{
+		"src/small": "1",
+		"src/large": "1234567890",
+	})
+
+	index, err := Compact(CompactOptions{
+		Client:            client,
+		Bucket:            "bucket",
+		Prefix:            "src",
+		DestinationPrefix: "dst",
+		Threshold:         5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, CompactIndex{"src/small": {Object: "dst/00000001", Length: 1}}, index)
+}
+
+func TestCompactDeleteSource(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	putCompactTestObjects(t, client, map[string]string{
+		"src/key1": "1",
+		"src/key2": "2",
+	})
+
+	index, err := Compact(CompactOptions{
+		Client:            client,
+		Bucket:            "bucket",
+		Prefix:            "src",
+		DestinationPrefix: "dst",
+		DeleteSource:      true,
+	})
+	require.NoError(t, err)
+	require.Len(t, index, 2)
+
+	var notFound *objerr.NotFoundError
+
+	for key := range index {
+		_, err = client.GetObjectAttrs(context.Background(), objcli.GetObjectAttrsOptions{Bucket: "bucket", Key: key})
+		require.ErrorAs(t, err, &notFound)
+	}
+
+	require.Equal(t, "2", readCompactedObject(t, client, index["src/key2"]))
+}
@@ -159,8 +159,9 @@ func download(
 	}
 
 	obj, err := opts.Client.GetObject(ctx, objcli.GetObjectOptions{
-		Bucket: opts.SourceBucket,
-		Key:    key,
+		Bucket:           opts.SourceBucket,
+		Key:              key,
+		BandwidthLimiter: opts.BandwidthLimiter,
 	})
 	if err != nil {
 		return fmt.Errorf("could not get object '%s': %w", key, err)
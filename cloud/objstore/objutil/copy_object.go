@@ -2,7 +2,6 @@ package objutil
 
 import (
 	"fmt"
-	"math"
 
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
@@ -47,6 +46,8 @@ func CopyObject(opts CopyObjectOptions) error {
 	// Fill out any missing fields with the sane defaults
 	opts.defaults()
 
+	defer opts.Progress.Done()
+
 	attrs, err := opts.Client.GetObjectAttrs(opts.Context, objcli.GetObjectAttrsOptions{
 		Bucket: opts.SourceBucket,
 		Key:    opts.SourceKey,
@@ -57,9 +58,11 @@ func CopyObject(opts CopyObjectOptions) error {
 
 	var (
 		size = ptr.From(attrs.Size)
-		max  = maxSingleOperationCopySize(opts.Client.Provider())
+		max  = opts.Client.Capabilities().MaxCopySize
 	)
 
+	opts.Progress.SetTotal(size)
+
 	// If we're able to perform this operation with a single request, do that instead.
 	if size <= max {
 		copts := objcli.CopyObjectOptions{
@@ -69,12 +72,21 @@ func CopyObject(opts CopyObjectOptions) error {
 			SourceKey:         opts.SourceKey,
 		}
 
-		return opts.Client.CopyObject(opts.Context, copts)
+		err = opts.Client.CopyObject(opts.Context, copts)
+		if err != nil {
+			return err // Purposefully not wrapped
+		}
+
+		opts.Progress.Add(size)
+
+		return nil
 	}
 
+	// Unlike 'CopyObject', a multipart copy doesn't preserve the metadata of the source object
 	id, err := opts.Client.CreateMultipartUpload(opts.Context, objcli.CreateMultipartUploadOptions{
-		Bucket: opts.DestinationBucket,
-		Key:    opts.DestinationKey,
+		Bucket:   opts.DestinationBucket,
+		Key:      opts.DestinationKey,
+		Metadata: attrs.Metadata,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to start multipart upload: %w", err)
@@ -110,6 +122,8 @@ func CopyObject(opts CopyObjectOptions) error {
 
 		parts = append(parts, part)
 
+		opts.Progress.Add(part.Size)
+
 		return nil
 	}
 
@@ -124,6 +138,7 @@ func CopyObject(opts CopyObjectOptions) error {
 		UploadID: id,
 		Key:      opts.DestinationKey,
 		Parts:    parts,
+		Metadata: attrs.Metadata,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to complete multipart upload: %w", err)
@@ -131,22 +146,3 @@ func CopyObject(opts CopyObjectOptions) error {
 
 	return nil
 }
-
-// maxSingleOperationCopySize returns an integer representing the point at which copying must be broken down into a
-// multipart upload; this is required as some cloud providers have limits on copying objects.
-//
-// NOTE: If the provider is unknown, a zero value is returned which will trigger multipart uploads which should always
-// be valid if only slightly sub-optimal.
-func maxSingleOperationCopySize(provider objval.Provider) int64 {
-	switch provider {
-	case objval.ProviderAWS:
-		return 5 * 1000 * 1000 * 1000
-	case objval.ProviderAzure:
-		return 256 * 1000 * 1000
-	case objval.ProviderGCP:
-		// Don't trigger the multipart copy behavior for GCP; that's already handled by the SDK.
-		return math.MaxInt64
-	}
-
-	return 0
-}
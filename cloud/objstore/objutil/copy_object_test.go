@@ -3,7 +3,6 @@ package objutil
 import (
 	"bytes"
 	"context"
-	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -38,9 +37,10 @@ func TestCopyObject(t *testing.T) {
 			)
 
 			err := client.PutObject(context.Background(), objcli.PutObjectOptions{
-				Bucket: "srcBucket",
-				Key:    "srcKey",
-				Body:   bytes.NewReader(body),
+				Bucket:   "srcBucket",
+				Key:      "srcKey",
+				Body:     bytes.NewReader(body),
+				Metadata: map[string]string{"format_version": "2"},
 			})
 			require.NoError(t, err)
 
@@ -61,42 +61,13 @@ func TestCopyObject(t *testing.T) {
 			})
 			require.NoError(t, err)
 			require.Equal(t, body, testutil.ReadAll(t, dst.Body))
-		})
-	}
-}
-
-func TestMaxSingleOperationCopySize(t *testing.T) {
-	type test struct {
-		name     string
-		provider objval.Provider
-		expected int64
-	}
 
-	tests := []test{
-		{
-			name:     "AWS",
-			provider: objval.ProviderAWS,
-			expected: 5 * 1000 * 1000 * 1000,
-		},
-		{
-			name:     "Azure",
-			provider: objval.ProviderAzure,
-			expected: 256 * 1000 * 1000,
-		},
-		{
-			name:     "GCP",
-			provider: objval.ProviderGCP,
-			expected: math.MaxInt64,
-		},
-		{
-			name:     "Other",
-			provider: 42,
-		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			require.Equal(t, test.expected, maxSingleOperationCopySize(test.provider))
+			attrs, err := client.GetObjectAttrs(context.Background(), objcli.GetObjectAttrsOptions{
+				Bucket: "dstBucket",
+				Key:    "dstKey",
+			})
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"format_version": "2"}, attrs.Metadata)
 		})
 	}
 }
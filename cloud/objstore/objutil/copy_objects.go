@@ -10,6 +10,7 @@ import (
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
 	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
 	"github.com/couchbase/tools-common/sync/v2/hofp"
+	"github.com/couchbase/tools-common/types/v2/ptr"
 )
 
 // CopyObjectsOptions encapsulates the available options which can be used when copying objects from one prefix to
@@ -51,6 +52,13 @@ type CopyObjectsOptions struct {
 	// SourceExclude allows skipping keys which may any of the given expressions.
 	SourceExclude []*regexp.Regexp
 
+	// Concurrency is the number of objects which may be copied concurrently, defaults to the number of vCPUs.
+	Concurrency int
+
+	// Resume skips copying objects which already exist at the destination with the same size, allowing an interrupted
+	// copy to be resumed without copying every object again.
+	Resume bool
+
 	// Logger is the logger that'll be used.
 	Logger *slog.Logger
 }
@@ -76,18 +84,26 @@ func CopyObjects(opts CopyObjectsOptions) error {
 		return ErrCopyToSamePrefix
 	}
 
+	existing, err := existingObjects(opts)
+	if err != nil {
+		return fmt.Errorf("failed to list existing objects: %w", err)
+	}
+
 	pool := hofp.NewPool(hofp.Options{
 		Context: opts.Context,
+		Size:    opts.Concurrency,
 		Logger:  opts.Logger,
 	})
 
-	cp := func(ctx context.Context, attrs *objval.ObjectAttrs) error {
-		if attrs.IsDir() {
-			return nil
-		}
+	defer opts.Progress.Done()
 
+	// Each copy reports the bytes it transfers, the total isn't known upfront since objects are copied whilst listing
+	options := opts.Options
+	options.Progress = partialProgress{Progress: opts.Progress}
+
+	cp := func(ctx context.Context, attrs *objval.ObjectAttrs) error {
 		options := CopyObjectOptions{
-			Options:           opts.Options.WithContext(ctx),
+			Options:           options.WithContext(ctx),
 			Client:            opts.Client,
 			DestinationBucket: opts.DestinationBucket,
 			DestinationKey:    strings.Replace(attrs.Key, opts.SourcePrefix, opts.DestinationPrefix, 1),
@@ -99,10 +115,20 @@ func CopyObjects(opts CopyObjectsOptions) error {
 	}
 
 	queue := func(attrs *objval.ObjectAttrs) error {
+		if attrs.IsDir() {
+			return nil
+		}
+
+		size, ok := existing[strings.Replace(attrs.Key, opts.SourcePrefix, opts.DestinationPrefix, 1)]
+		if ok && size == ptr.From(attrs.Size) {
+			opts.Logger.Debug("skipping object which has already been copied", "key", attrs.Key)
+			return nil
+		}
+
 		return pool.Queue(func(ctx context.Context) error { return cp(ctx, attrs) })
 	}
 
-	err := opts.Client.IterateObjects(opts.Context, objcli.IterateObjectsOptions{
+	err = opts.Client.IterateObjects(opts.Context, objcli.IterateObjectsOptions{
 		Bucket:    opts.SourceBucket,
 		Prefix:    opts.SourcePrefix,
 		Delimiter: opts.SourceDelimiter,
@@ -121,3 +147,31 @@ func CopyObjects(opts CopyObjectsOptions) error {
 
 	return nil
 }
+
+// existingObjects returns the size of the objects which already exist at the destination, when resuming.
+func existingObjects(opts CopyObjectsOptions) (map[string]int64, error) {
+	if !opts.Resume {
+		return nil, nil
+	}
+
+	existing := make(map[string]int64)
+
+	fn := func(attrs *objval.ObjectAttrs) error {
+		if !attrs.IsDir() {
+			existing[attrs.Key] = ptr.From(attrs.Size)
+		}
+
+		return nil
+	}
+
+	err := opts.Client.IterateObjects(opts.Context, objcli.IterateObjectsOptions{
+		Bucket: opts.DestinationBucket,
+		Prefix: opts.DestinationPrefix,
+		Func:   fn,
+	})
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	return existing, nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -252,3 +253,46 @@ func TestCopyObjectsSingleObject(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, body, testutil.ReadAll(t, dst.Body))
 }
+
+func TestCopyObjectsResume(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	put := func(bucket, key, body string) {
+		err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+			Bucket: bucket,
+			Key:    key,
+			Body:   strings.NewReader(body),
+		})
+		require.NoError(t, err)
+	}
+
+	put("srcBucket", "src/key1", "1")
+	put("srcBucket", "src/key2", "22")
+	put("srcBucket", "src/key3", "333")
+
+	// The first object has already been copied, the second was only partially copied
+	put("dstBucket", "dst/key1", "x")
+	put("dstBucket", "dst/key2", "2")
+
+	options := CopyObjectsOptions{
+		Client:            client,
+		DestinationBucket: "dstBucket",
+		DestinationPrefix: "dst",
+		SourceBucket:      "srcBucket",
+		SourcePrefix:      "src",
+		Concurrency:       1,
+		Resume:            true,
+	}
+
+	err := CopyObjects(options)
+	require.NoError(t, err)
+
+	for key, expected := range map[string]string{"dst/key1": "x", "dst/key2": "22", "dst/key3": "333"} {
+		dst, err := client.GetObject(context.Background(), objcli.GetObjectOptions{
+			Bucket: "dstBucket",
+			Key:    key,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []byte(expected), testutil.ReadAll(t, dst.Body))
+	}
+}
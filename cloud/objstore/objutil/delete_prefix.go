@@ -0,0 +1,196 @@
+package objutil
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sync"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/sync/v2/hofp"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// DefaultDeleteBatchSize is the default number of keys deleted in a single batch, this matches the maximum batch size
+// supported by AWS/Azure.
+const DefaultDeleteBatchSize = 1000
+
+// DeletePrefixProgress is a running total of the objects which have been deleted by 'DeletePrefix'.
+type DeletePrefixProgress struct {
+	// Objects is the number of objects which have been deleted.
+	Objects uint64
+
+	// Bytes is the combined size of the objects which have been deleted.
+	Bytes uint64
+}
+
+// DeletePrefixOptions encapsulates the options available when using the 'DeletePrefix' function.
+type DeletePrefixOptions struct {
+	// Context is the 'context.Context' that can be used to cancel all requests.
+	Context context.Context
+
+	// Client is the client used to perform the operation.
+	//
+	// NOTE: This attribute is required.
+	Client objcli.Client
+
+	// Bucket is the bucket containing the objects being deleted.
+	//
+	// NOTE: This attribute is required.
+	Bucket string
+
+	// Prefix is the prefix under which all objects will be deleted.
+	//
+	// NOTE: This attribute is required.
+	Prefix string
+
+	// Include allows deleting only the keys which match any of the given expressions.
+	Include []*regexp.Regexp
+
+	// Exclude allows skipping keys which match any of the given expressions.
+	Exclude []*regexp.Regexp
+
+	// BatchSize is the number of keys which will be deleted in a single batch, defaults to 'DefaultDeleteBatchSize'.
+	BatchSize int
+
+	// Concurrency is the number of batches which may be deleted concurrently, defaults to the number of vCPUs.
+	Concurrency int
+
+	// DryRun lists the objects which would be deleted, without deleting them.
+	DryRun bool
+
+	// Func is run for each object which is going to be deleted (or would be deleted in the case of 'DryRun'), this
+	// function should not block for extended periods of time as it's run synchronously during listing.
+	Func objcli.IterateFunc
+
+	// Progress is run after each batch has been deleted (or would have been deleted in the case of 'DryRun') with the
+	// running total. Calls to 'Progress' are serialized, so it need not be thread safe.
+	Progress func(progress DeletePrefixProgress)
+
+	// Logger is the logger that'll be used.
+	Logger *slog.Logger
+}
+
+// defaults fills any missing attributes to a sane default.
+func (d *DeletePrefixOptions) defaults() {
+	if d.Context == nil {
+		d.Context = context.Background()
+	}
+
+	if d.BatchSize <= 0 {
+		d.BatchSize = DefaultDeleteBatchSize
+	}
+
+	if d.Logger == nil {
+		d.Logger = slog.Default()
+	}
+}
+
+// DeletePrefix deletes all the objects under the given prefix using batched deletes, with bounded concurrency.
+//
+// NOTE: The deletion is not atomic, in the event of a failure some objects may have been deleted, and some not.
+func DeletePrefix(opts DeletePrefixOptions) (DeletePrefixProgress, error) {
+	// Fill out any missing fields with the sane defaults
+	opts.defaults()
+
+	var (
+		progress DeletePrefixProgress
+		lock     sync.Mutex
+		batch    = make([]*objval.ObjectAttrs, 0, opts.BatchSize)
+	)
+
+	pool := hofp.NewPool(hofp.Options{
+		Context: opts.Context,
+		Size:    opts.Concurrency,
+		Logger:  opts.Logger,
+	})
+
+	// report updates the running total, serializing calls to the progress function
+	report := func(batch []*objval.ObjectAttrs) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		for _, attrs := range batch {
+			progress.Objects++
+			progress.Bytes += uint64(ptr.From(attrs.Size))
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+	}
+
+	del := func(ctx context.Context, batch []*objval.ObjectAttrs) error {
+		if !opts.DryRun {
+			keys := make([]string, 0, len(batch))
+			for _, attrs := range batch {
+				keys = append(keys, attrs.Key)
+			}
+
+			err := opts.Client.DeleteObjects(ctx, objcli.DeleteObjectsOptions{Bucket: opts.Bucket, Keys: keys})
+			if err != nil {
+				return fmt.Errorf("failed to delete batch: %w", err)
+			}
+		}
+
+		report(batch)
+
+		return nil
+	}
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		queued := batch
+		batch = make([]*objval.ObjectAttrs, 0, opts.BatchSize)
+
+		return pool.Queue(func(ctx context.Context) error { return del(ctx, queued) })
+	}
+
+	fn := func(attrs *objval.ObjectAttrs) error {
+		if attrs.IsDir() {
+			return nil
+		}
+
+		if opts.Func != nil {
+			if err := opts.Func(attrs); err != nil {
+				return err
+			}
+		}
+
+		batch = append(batch, attrs)
+
+		if len(batch) < opts.BatchSize {
+			return nil
+		}
+
+		return flush()
+	}
+
+	err := opts.Client.IterateObjects(opts.Context, objcli.IterateObjectsOptions{
+		Bucket:  opts.Bucket,
+		Prefix:  opts.Prefix,
+		Include: opts.Include,
+		Exclude: opts.Exclude,
+		Func:    fn,
+	})
+
+	// Ensure we flush the last batch, if iteration failed we still stop the pool to avoid leaking goroutines
+	if err == nil {
+		err = flush()
+	}
+
+	if stopErr := pool.Stop(); err == nil && stopErr != nil {
+		err = fmt.Errorf("failed to stop worker pool: %w", stopErr)
+	}
+
+	if err != nil {
+		return progress, fmt.Errorf("failed to delete prefix: %w", err)
+	}
+
+	return progress, nil
+}
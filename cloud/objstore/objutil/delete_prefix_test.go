@@ -0,0 +1,99 @@
+package objutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+func putDeletePrefixObjects(t *testing.T, client objcli.Client) {
+	for i := 0; i < 10; i++ {
+		err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+			Bucket: "bucket",
+			Key:    fmt.Sprintf("prefix/key%d", i),
+			Body:   bytes.NewReader([]byte("value")),
+		})
+		require.NoError(t, err)
+	}
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket: "bucket",
+		Key:    "other/key",
+		Body:   bytes.NewReader([]byte("value")),
+	})
+	require.NoError(t, err)
+}
+
+func TestDeletePrefix(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	putDeletePrefixObjects(t, client)
+
+	var (
+		calls int
+		last  DeletePrefixProgress
+	)
+
+	progress, err := DeletePrefix(DeletePrefixOptions{
+		Client:    client,
+		Bucket:    "bucket",
+		Prefix:    "prefix/",
+		BatchSize: 3,
+		Progress: func(progress DeletePrefixProgress) {
+			calls++
+			last = progress
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, DeletePrefixProgress{Objects: 10, Bytes: 50}, progress)
+	require.Equal(t, progress, last)
+	require.Equal(t, 4, calls)
+
+	require.Len(t, client.Buckets["bucket"], 1)
+	require.Contains(t, client.Buckets["bucket"], "other/key")
+}
+
+func TestDeletePrefixDryRun(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	putDeletePrefixObjects(t, client)
+
+	var keys []string
+
+	progress, err := DeletePrefix(DeletePrefixOptions{
+		Client: client,
+		Bucket: "bucket",
+		Prefix: "prefix/",
+		DryRun: true,
+		Func: func(attrs *objval.ObjectAttrs) error {
+			keys = append(keys, attrs.Key)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, DeletePrefixProgress{Objects: 10, Bytes: 50}, progress)
+	require.Len(t, keys, 10)
+	require.Len(t, client.Buckets["bucket"], 11)
+}
+
+func TestDeletePrefixFuncError(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	putDeletePrefixObjects(t, client)
+
+	_, err := DeletePrefix(DeletePrefixOptions{
+		Client: client,
+		Bucket: "bucket",
+		Prefix: "prefix/",
+		Func:   func(_ *objval.ObjectAttrs) error { return assert.AnError },
+	})
+	require.ErrorIs(t, err, assert.AnError)
+	require.Len(t, client.Buckets["bucket"], 11)
+}
@@ -65,11 +65,15 @@ func NewMPDownloader(opts MPDownloaderOptions) *MPDownloader {
 //
 // NOTE: If no byte range is provided, the whole object will be downloaded.
 func (m *MPDownloader) Download() error {
+	defer m.opts.Progress.Done()
+
 	br, err := m.byteRange()
 	if err != nil {
 		return fmt.Errorf("failed to get object byte range: %w", err)
 	}
 
+	m.opts.Progress.SetTotal(br.End - br.Start + 1)
+
 	return m.download(br)
 }
 
@@ -119,9 +123,10 @@ func (m *MPDownloader) download(br *objval.ByteRange) error {
 // downloadChunk downloads the given byte range and writes it to the underlying write.
 func (m *MPDownloader) downloadChunk(ctx context.Context, br *objval.ByteRange) error {
 	object, err := m.opts.Client.GetObject(ctx, objcli.GetObjectOptions{
-		Bucket:    m.opts.Bucket,
-		Key:       m.opts.Key,
-		ByteRange: br,
+		Bucket:           m.opts.Bucket,
+		Key:              m.opts.Key,
+		ByteRange:        br,
+		BandwidthLimiter: m.opts.BandwidthLimiter,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to get object range: %w", err)
@@ -146,5 +151,7 @@ func (m *MPDownloader) downloadChunk(ctx context.Context, br *objval.ByteRange)
 		return fmt.Errorf("failed to write chunk: %w", err)
 	}
 
+	m.opts.Progress.Add(int64(len(data)))
+
 	return nil
 }
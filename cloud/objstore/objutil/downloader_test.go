@@ -44,6 +44,7 @@ func TestNewMPDownloader(t *testing.T) {
 	options.PartSize = MinPartSize
 
 	options.Context = context.Background()
+	options.Progress = noopProgress{}
 
 	require.Equal(t, options, downloader.opts)
 }
@@ -5,3 +5,23 @@ import "errors"
 // ErrCopyToSamePrefix is returned if the user provides a destination/source prefix which is the same, within the same
 // bucket when using `CopyObjects`.
 var ErrCopyToSamePrefix = errors.New("copying to the same prefix within a bucket is not supported")
+
+// ErrNotCloudURI is returned when attempting to create a client for a URI which doesn't have a cloud scheme prefix e.g.
+// a local path.
+var ErrNotCloudURI = errors.New("expected a URI with a cloud scheme prefix e.g. 's3://', 'gs://' or 'az://'")
+
+// ErrCompactDestinationWithinPrefix is returned if the user provides a destination prefix which is within the prefix
+// being compacted when using 'Compact'.
+var ErrCompactDestinationWithinPrefix = errors.New("destination prefix must not be within the compacted prefix")
+
+// ErrNegativeOlderThan is returned if the user provides a negative 'OlderThan' when listing/aborting stale multipart
+// uploads, which would consider uploads which are still running to be stale.
+var ErrNegativeOlderThan = errors.New("'OlderThan' must not be negative")
+
+// ErrAWSRegionRequired is returned when creating an S3 client using 'NewClientForURI' and no region could be resolved,
+// either from the given config or the environment e.g. using 'AWS_REGION'.
+var ErrAWSRegionRequired = errors.New("no region found, 'AWS_REGION' must be set")
+
+// ErrAzureAccountRequired is returned when creating an Azure client using 'NewClientForURI' and neither an account URL
+// nor the 'AZURE_STORAGE_ACCOUNT' environment variable are provided.
+var ErrAzureAccountRequired = errors.New("no account URL found, 'AZURE_STORAGE_ACCOUNT' must be set")
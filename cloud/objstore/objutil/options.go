@@ -1,6 +1,10 @@
 package objutil
 
-import "context"
+import (
+	"context"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+)
 
 // Options contains common options for upload/download of objects.
 type Options struct {
@@ -9,6 +13,15 @@ type Options struct {
 
 	// ParseSize is the size in bytes of individual parts in multipart up/download.
 	PartSize int64
+
+	// BandwidthLimiter limits the throughput of the operation, overriding the limiter used by a bandwidth limited
+	// client.
+	//
+	// NOTE: Only has an effect when using an 'objcli.RateLimitedClient'.
+	BandwidthLimiter *objcli.BandwidthLimiter
+
+	// Progress receives updates as data is transferred, for example, a 'ProgressTracker'.
+	Progress Progress
 }
 
 // defaults fills any missing attributes to a sane default.
@@ -18,6 +31,10 @@ func (o *Options) defaults() {
 	}
 
 	o.PartSize = max(o.PartSize, MinPartSize)
+
+	if o.Progress == nil {
+		o.Progress = noopProgress{}
+	}
 }
 
 // WithContext returns a copy of the options using the given context.
@@ -0,0 +1,137 @@
+package objutil
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress receives updates as data is transferred by the upload/download/copy helpers, allowing progress to be
+// reported to the user.
+//
+// NOTE: Parts may be transferred concurrently, implementations must be thread safe.
+type Progress interface {
+	// SetTotal sets the total number of bytes which will be transferred.
+	SetTotal(total int64)
+
+	// Add records that the given number of bytes have been transferred; bytes are only reported once a part has been
+	// transferred successfully, so retried requests aren't counted more than once.
+	Add(n int64)
+
+	// Done is called once the transfer has completed, whether it was successful or not.
+	Done()
+}
+
+// noopProgress is a 'Progress' which discards all updates, used when the user hasn't provided one.
+type noopProgress struct{}
+
+func (noopProgress) SetTotal(_ int64) {}
+
+func (noopProgress) Add(_ int64) {}
+
+func (noopProgress) Done() {}
+
+// partialProgress is used where a helper performs multiple transfers (e.g. 'CopyObjects'), only the bytes transferred
+// are forwarded, the helper itself is responsible for reporting the total/completion.
+type partialProgress struct {
+	Progress
+}
+
+func (partialProgress) SetTotal(_ int64) {}
+
+func (partialProgress) Done() {}
+
+// ProgressStats is a snapshot of the progress of a transfer.
+type ProgressStats struct {
+	// Total is the total number of bytes to be transferred, zero if unknown.
+	Total int64
+
+	// Transferred is the number of bytes transferred so far.
+	Transferred int64
+
+	// Elapsed is the amount of time since the first update was received.
+	Elapsed time.Duration
+
+	// Rate is the average transfer rate, in bytes per second.
+	Rate float64
+
+	// ETA is the estimated amount of time until the transfer completes, zero if it can't be estimated.
+	ETA time.Duration
+
+	// Done indicates whether the transfer has completed.
+	Done bool
+}
+
+// ProgressTracker is a 'Progress' which tracks the transfer rate and estimated time remaining, suitable for driving a
+// progress bar by periodically calling 'Stats'.
+type ProgressTracker struct {
+	lock        sync.Mutex
+	total       int64
+	transferred int64
+	start       time.Time
+	finish      time.Time
+	done        bool
+	now         func() time.Time
+}
+
+var _ Progress = (*ProgressTracker)(nil)
+
+// NewProgressTracker returns a new tracker, the elapsed time is measured from its creation.
+func NewProgressTracker() *ProgressTracker {
+	return newProgressTracker(time.Now)
+}
+
+// newProgressTracker returns a new tracker which uses the given function to get the current time.
+func newProgressTracker(now func() time.Time) *ProgressTracker {
+	return &ProgressTracker{start: now(), now: now}
+}
+
+func (p *ProgressTracker) SetTotal(total int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.total = total
+}
+
+func (p *ProgressTracker) Add(n int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.transferred += n
+}
+
+func (p *ProgressTracker) Done() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !p.done {
+		p.done, p.finish = true, p.now()
+	}
+}
+
+// Stats returns a snapshot of the progress of the transfer.
+func (p *ProgressTracker) Stats() ProgressStats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	end := p.now()
+	if p.done {
+		end = p.finish
+	}
+
+	stats := ProgressStats{
+		Total:       p.total,
+		Transferred: p.transferred,
+		Elapsed:     end.Sub(p.start),
+		Done:        p.done,
+	}
+
+	if stats.Elapsed > 0 {
+		stats.Rate = float64(stats.Transferred) / stats.Elapsed.Seconds()
+	}
+
+	if !stats.Done && stats.Rate > 0 && stats.Total > stats.Transferred {
+		stats.ETA = time.Duration(float64(stats.Total-stats.Transferred) / stats.Rate * float64(time.Second))
+	}
+
+	return stats
+}
@@ -0,0 +1,100 @@
+package objutil
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressTrackerStats(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	tracker := newProgressTracker(func() time.Time { return now })
+	require.Equal(t, ProgressStats{}, tracker.Stats())
+
+	tracker.SetTotal(1000)
+	tracker.Add(250)
+
+	now = now.Add(5 * time.Second)
+
+	require.Equal(t, ProgressStats{
+		Total:       1000,
+		Transferred: 250,
+		Elapsed:     5 * time.Second,
+		Rate:        50,
+		ETA:         15 * time.Second,
+	}, tracker.Stats())
+
+	tracker.Add(750)
+
+	now = now.Add(5 * time.Second)
+
+	tracker.Done()
+
+	// The elapsed time should stop once the transfer is done
+	now = now.Add(time.Hour)
+
+	require.Equal(t, ProgressStats{
+		Total:       1000,
+		Transferred: 1000,
+		Elapsed:     10 * time.Second,
+		Rate:        100,
+		Done:        true,
+	}, tracker.Stats())
+}
+
+func TestUploadProgress(t *testing.T) {
+	for _, size := range []int{64, MPUThreshold + 1} {
+		client := objcli.NewTestClient(t, objval.ProviderAWS)
+		progress := NewProgressTracker()
+
+		options := UploadOptions{
+			Options: Options{Progress: progress},
+			Client:  client,
+			Bucket:  "bucket",
+			Key:     "key",
+			Body:    bytes.NewReader(make([]byte, size)),
+		}
+
+		require.NoError(t, Upload(options))
+
+		stats := progress.Stats()
+		require.Equal(t, int64(size), stats.Total)
+		require.Equal(t, int64(size), stats.Transferred)
+		require.True(t, stats.Done)
+	}
+}
+
+func TestDownloadProgress(t *testing.T) {
+	var (
+		client   = objcli.NewTestClient(t, objval.ProviderAWS)
+		progress = NewProgressTracker()
+		size     = int64(MinPartSize*2 + 1)
+	)
+
+	err := client.PutObject(context.Background(), objcli.PutObjectOptions{
+		Bucket: "bucket",
+		Key:    "key",
+		Body:   bytes.NewReader(make([]byte, size)),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, Download(DownloadOptions{
+		Options: Options{Progress: progress},
+		Client:  client,
+		Bucket:  "bucket",
+		Key:     "key",
+		Writer:  &tracker{},
+	}))
+
+	stats := progress.Stats()
+	require.Equal(t, size, stats.Total)
+	require.Equal(t, size, stats.Transferred)
+	require.True(t, stats.Done)
+}
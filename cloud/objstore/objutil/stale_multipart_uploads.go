@@ -0,0 +1,148 @@
+package objutil
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+)
+
+// DefaultStaleMultipartUploadAge is the default amount of time since an upload was initiated, after which it's
+// considered stale.
+const DefaultStaleMultipartUploadAge = 24 * time.Hour
+
+// StaleMultipartUploadsOptions encapsulates the options available when using the 'ListStaleMultipartUploads' and
+// 'AbortStaleMultipartUploads' functions.
+type StaleMultipartUploadsOptions struct {
+	// Context is the 'context.Context' that can be used to cancel all requests.
+	Context context.Context
+
+	// Client is the client used to perform the operation.
+	//
+	// NOTE: This attribute is required.
+	Client objcli.Client
+
+	// Bucket is the bucket containing the multipart uploads.
+	//
+	// NOTE: This attribute is required.
+	Bucket string
+
+	// Prefix limits the uploads to those for keys with the given prefix.
+	Prefix string
+
+	// OlderThan is the minimum amount of time since an upload was initiated, for it to be considered stale; this should
+	// be greater than the longest expected duration of an upload, to avoid aborting uploads which are still running.
+	// Defaults to 'DefaultStaleMultipartUploadAge'.
+	//
+	// NOTE: Must not be negative.
+	OlderThan time.Duration
+
+	// Logger is the logger that'll be used.
+	Logger *slog.Logger
+}
+
+// defaults fills any missing attributes to a sane default.
+func (s *StaleMultipartUploadsOptions) defaults() {
+	if s.Context == nil {
+		s.Context = context.Background()
+	}
+
+	if s.OlderThan == 0 {
+		s.OlderThan = DefaultStaleMultipartUploadAge
+	}
+
+	if s.Logger == nil {
+		s.Logger = slog.Default()
+	}
+}
+
+// ListStaleMultipartUploads returns the multipart uploads which were initiated more than 'OlderThan' ago, and have
+// neither been completed nor aborted; these are generally left behind by processes which crashed mid-upload.
+//
+// NOTE: For GCP, this includes the intermediate objects left behind when failing to compose a large upload; these are
+// attributed to the upload being completed.
+func ListStaleMultipartUploads(opts StaleMultipartUploadsOptions) ([]objval.MultipartUpload, error) {
+	// Fill out any missing fields with the sane defaults
+	opts.defaults()
+
+	return listStaleMultipartUploads(opts)
+}
+
+// AbortStaleMultipartUploads aborts the multipart uploads which were initiated more than 'OlderThan' ago, reclaiming
+// the storage used by their parts. The uploads which were aborted are returned.
+//
+// NOTE: Uploads which are completed/aborted concurrently are ignored.
+func AbortStaleMultipartUploads(opts StaleMultipartUploadsOptions) ([]objval.MultipartUpload, error) {
+	// Fill out any missing fields with the sane defaults
+	opts.defaults()
+
+	uploads, err := listStaleMultipartUploads(opts)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	aborted := make([]objval.MultipartUpload, 0, len(uploads))
+
+	for _, upload := range uploads {
+		err := opts.Client.AbortMultipartUpload(opts.Context, objcli.AbortMultipartUploadOptions{
+			Bucket:   opts.Bucket,
+			Key:      upload.Key,
+			UploadID: upload.UploadID,
+		})
+
+		if objerr.IsNotFoundError(err) {
+			continue
+		}
+
+		if err != nil {
+			return aborted, fmt.Errorf("failed to abort upload '%s' for key '%s': %w", upload.UploadID, upload.Key, err)
+		}
+
+		opts.Logger.Info(
+			"aborted stale multipart upload",
+			"bucket", opts.Bucket,
+			"key", upload.Key,
+			"upload_id", upload.UploadID,
+			"initiated", upload.Initiated,
+		)
+
+		aborted = append(aborted, upload)
+	}
+
+	return aborted, nil
+}
+
+// listStaleMultipartUploads returns the uploads which are considered stale, using the given defaulted options.
+func listStaleMultipartUploads(opts StaleMultipartUploadsOptions) ([]objval.MultipartUpload, error) {
+	if opts.OlderThan < 0 {
+		return nil, ErrNegativeOlderThan
+	}
+
+	uploads, err := opts.Client.ListMultipartUploads(opts.Context, objcli.ListMultipartUploadsOptions{
+		Bucket: opts.Bucket,
+		Prefix: opts.Prefix,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list multipart uploads: %w", err)
+	}
+
+	var (
+		cutoff = time.Now().Add(-opts.OlderThan)
+		stale  = make([]objval.MultipartUpload, 0)
+	)
+
+	for _, upload := range uploads {
+		// Uploads without an initiated time can't be considered stale, as they may still be running
+		if upload.Initiated.IsZero() || upload.Initiated.After(cutoff) {
+			continue
+		}
+
+		stale = append(stale, upload)
+	}
+
+	return stale, nil
+}
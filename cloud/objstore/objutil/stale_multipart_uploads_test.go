@@ -0,0 +1,113 @@
+package objutil
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+func createStaleMultipartUpload(t *testing.T, client *objcli.TestClient, key string, age time.Duration) string {
+	id, err := client.CreateMultipartUpload(context.Background(), objcli.CreateMultipartUploadOptions{
+		Bucket: "bucket",
+		Key:    key,
+	})
+	require.NoError(t, err)
+
+	part, err := client.UploadPart(context.Background(), objcli.UploadPartOptions{
+		Bucket:   "bucket",
+		UploadID: id,
+		Key:      key,
+		Number:   1,
+		Body:     bytes.NewReader([]byte("value")),
+	})
+	require.NoError(t, err)
+
+	client.Buckets["bucket"][part.ID].LastModified = ptr.To(time.Now().Add(-age))
+
+	return id
+}
+
+func TestListStaleMultipartUploads(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	var (
+		stale = createStaleMultipartUpload(t, client, "prefix/key1", 48*time.Hour)
+		_     = createStaleMultipartUpload(t, client, "prefix/key2", time.Minute)
+		_     = createStaleMultipartUpload(t, client, "other/key", 48*time.Hour)
+	)
+
+	uploads, err := ListStaleMultipartUploads(StaleMultipartUploadsOptions{
+		Client:    client,
+		Bucket:    "bucket",
+		Prefix:    "prefix/",
+		OlderThan: 24 * time.Hour,
+	})
+	require.NoError(t, err)
+	require.Len(t, uploads, 1)
+	require.Equal(t, "prefix/key1", uploads[0].Key)
+	require.Equal(t, stale, uploads[0].UploadID)
+
+	// Listing shouldn't abort any uploads
+	require.Len(t, client.Buckets["bucket"], 3)
+}
+
+func TestAbortStaleMultipartUploads(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	var (
+		stale  = createStaleMultipartUpload(t, client, "prefix/key1", 48*time.Hour)
+		active = createStaleMultipartUpload(t, client, "prefix/key2", time.Minute)
+	)
+
+	aborted, err := AbortStaleMultipartUploads(StaleMultipartUploadsOptions{
+		Client:    client,
+		Bucket:    "bucket",
+		OlderThan: 24 * time.Hour,
+	})
+	require.NoError(t, err)
+	require.Len(t, aborted, 1)
+	require.Equal(t, stale, aborted[0].UploadID)
+
+	uploads, err := client.ListMultipartUploads(context.Background(), objcli.ListMultipartUploadsOptions{
+		Bucket: "bucket",
+	})
+	require.NoError(t, err)
+	require.Len(t, uploads, 1)
+	require.Equal(t, active, uploads[0].UploadID)
+}
+
+func TestListStaleMultipartUploadsDefaultOlderThan(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	var (
+		stale = createStaleMultipartUpload(t, client, "key1", DefaultStaleMultipartUploadAge+time.Hour)
+		_     = createStaleMultipartUpload(t, client, "key2", time.Minute)
+	)
+
+	// Recently initiated uploads should never be considered stale by default
+	uploads, err := ListStaleMultipartUploads(StaleMultipartUploadsOptions{Client: client, Bucket: "bucket"})
+	require.NoError(t, err)
+	require.Len(t, uploads, 1)
+	require.Equal(t, stale, uploads[0].UploadID)
+}
+
+func TestAbortStaleMultipartUploadsNegativeOlderThan(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	_ = createStaleMultipartUpload(t, client, "key", time.Minute)
+
+	_, err := AbortStaleMultipartUploads(StaleMultipartUploadsOptions{
+		Client:    client,
+		Bucket:    "bucket",
+		OlderThan: -time.Hour,
+	})
+	require.ErrorIs(t, err, ErrNegativeOlderThan)
+	require.Len(t, client.Buckets["bucket"], 1)
+}
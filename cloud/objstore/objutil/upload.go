@@ -46,6 +46,9 @@ type UploadOptions struct {
 	// NOTE: This attribute is required.
 	Body ioiface.ReadAtSeeker
 
+	// Metadata is user-defined metadata attached to the uploaded object.
+	Metadata map[string]string
+
 	// MPUThreshold is a threshold at which point objects which broken down into multipart uploads.
 	MPUThreshold int64
 }
@@ -62,32 +65,44 @@ func Upload(opts UploadOptions) error {
 	// Fill out any missing fields with the sane defaults
 	opts.defaults()
 
+	defer opts.Progress.Done()
+
 	length, err := objcli.SeekerLength(opts.Body)
 	if err != nil {
 		return fmt.Errorf("failed to determine length of body: %w", err)
 	}
 
+	opts.Progress.SetTotal(length)
+
 	// Under the threshold, upload using a single request
 	if length > opts.MPUThreshold {
 		return upload(opts)
 	}
 
 	err = opts.Client.PutObject(opts.Context, objcli.PutObjectOptions{
-		Bucket: opts.Bucket,
-		Key:    opts.Key,
-		Body:   opts.Body,
+		Bucket:           opts.Bucket,
+		Key:              opts.Key,
+		Body:             opts.Body,
+		Metadata:         opts.Metadata,
+		BandwidthLimiter: opts.BandwidthLimiter,
 	})
+	if err != nil {
+		return err // Purposefully not wrapped
+	}
+
+	opts.Progress.Add(length)
 
-	return err
+	return nil
 }
 
 // upload an object to a remote cloud by breaking it down into individual chunks and uploading them concurrently.
 func upload(opts UploadOptions) error {
 	mpu, err := NewMPUploader(MPUploaderOptions{
-		Client:  opts.Client,
-		Bucket:  opts.Bucket,
-		Key:     opts.Key,
-		Options: opts.Options,
+		Client:   opts.Client,
+		Bucket:   opts.Bucket,
+		Key:      opts.Key,
+		Metadata: opts.Metadata,
+		Options:  opts.Options,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create uploader: %w", err)
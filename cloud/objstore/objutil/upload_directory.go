@@ -0,0 +1,315 @@
+package objutil
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	fsutil "github.com/couchbase/tools-common/fs/util"
+	"github.com/couchbase/tools-common/sync/v2/hofp"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// UploadDirectoryOptions encapsulates the options available when using the 'UploadDirectory' function to mirror a local
+// directory to a prefix in a remote cloud.
+type UploadDirectoryOptions struct {
+	Options
+
+	// Client is the client used to perform the operation.
+	//
+	// NOTE: This attribute is required.
+	Client objcli.Client
+
+	// Source is the local directory which is uploaded, the files it contains are uploaded using their path relative to
+	// this directory.
+	//
+	// NOTE: This attribute is required.
+	Source string
+
+	// Bucket is the bucket to upload the objects to.
+	//
+	// NOTE: This attribute is required.
+	Bucket string
+
+	// Prefix is the prefix under which the objects are uploaded e.g. given the prefix 'backups/' the file
+	// 'data/index.json' will be uploaded to 'backups/data/index.json'.
+	Prefix string
+
+	// SkipUnchanged skips uploading files which already exist at the destination and have the same size, and haven't
+	// been modified since the object was last modified.
+	SkipUnchanged bool
+
+	// CompareETag additionally skips files which have been modified locally, where the MD5 checksum of the file matches
+	// the ETag of the existing object.
+	//
+	// NOTE: Only has an effect when using 'SkipUnchanged'. The ETag of an object uploaded using a multipart upload isn't
+	// the MD5 checksum of the object, so such objects are always uploaded again when modified locally.
+	CompareETag bool
+
+	// Concurrency is the number of files which may be uploaded concurrently, defaults to the number of vCPUs.
+	Concurrency int
+
+	// MPUThreshold is a threshold at which point objects which broken down into multipart uploads.
+	MPUThreshold int64
+
+	// Logger is the logger that'll be used.
+	Logger *slog.Logger
+}
+
+// defaults fills any missing attributes to a sane default.
+func (u *UploadDirectoryOptions) defaults() {
+	u.Options.defaults()
+
+	if u.Logger == nil {
+		u.Logger = slog.Default()
+	}
+}
+
+// UploadedObject describes a single file handled by 'UploadDirectory'.
+type UploadedObject struct {
+	// Path is the path of the file, relative to the source directory.
+	Path string
+
+	// Key is the key of the object the file was uploaded to.
+	Key string
+
+	// Size is the size of the file in bytes.
+	Size int64
+
+	// Skipped indicates that the file wasn't uploaded, because it hasn't changed since it was last uploaded.
+	Skipped bool
+}
+
+// UploadManifest describes the objects created/updated by 'UploadDirectory'.
+type UploadManifest struct {
+	// Objects is the objects handled by the upload, sorted by key.
+	Objects []UploadedObject
+}
+
+// Uploaded returns the objects which were uploaded, excluding those which were skipped.
+func (u *UploadManifest) Uploaded() []UploadedObject {
+	uploaded := make([]UploadedObject, 0, len(u.Objects))
+
+	for _, object := range u.Objects {
+		if !object.Skipped {
+			uploaded = append(uploaded, object)
+		}
+	}
+
+	return uploaded
+}
+
+// UploadDirectory walks the given local directory, concurrently uploading each file it contains to the destination
+// prefix whilst preserving its relative path. A manifest of the uploaded objects is returned, which is complete only
+// when the upload is successful.
+//
+// NOTE: Empty directories aren't uploaded, object stores don't have directories.
+func UploadDirectory(opts UploadDirectoryOptions) (*UploadManifest, error) {
+	// Fill out any missing fields with the sane defaults
+	opts.defaults()
+
+	existing, err := existingDirectoryObjects(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list existing objects: %w", err)
+	}
+
+	pool := hofp.NewPool(hofp.Options{
+		Context: opts.Context,
+		Size:    opts.Concurrency,
+		Logger:  opts.Logger,
+	})
+
+	defer opts.Progress.Done()
+
+	// Each upload reports the bytes it transfers, the total isn't known upfront since files are uploaded whilst walking
+	options := opts.Options
+	options.Progress = partialProgress{Progress: opts.Progress}
+
+	var (
+		manifest = &UploadManifest{}
+		lock     sync.Mutex
+	)
+
+	record := func(object UploadedObject) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		manifest.Objects = append(manifest.Objects, object)
+	}
+
+	ul := func(ctx context.Context, object UploadedObject, source string) error {
+		err := uploadDirectoryFile(opts, options.WithContext(ctx), object.Key, source)
+		if err != nil {
+			return fmt.Errorf("failed to upload '%s': %w", object.Path, err)
+		}
+
+		record(object)
+
+		return nil
+	}
+
+	walk := func(source string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat '%s': %w", source, err)
+		}
+
+		// Only regular files are uploaded, symlinks/devices etc. are skipped
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(opts.Source, source)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path for '%s': %w", source, err)
+		}
+
+		object := UploadedObject{
+			Path: filepath.ToSlash(rel),
+			Key:  path.Join(opts.Prefix, filepath.ToSlash(rel)),
+			Size: info.Size(),
+		}
+
+		unchanged, err := isUnchanged(opts, source, info, existing[object.Key])
+		if err != nil {
+			return fmt.Errorf("failed to check whether '%s' has changed: %w", object.Path, err)
+		}
+
+		if unchanged {
+			opts.Logger.Debug("skipping file which hasn't changed", "path", object.Path, "key", object.Key)
+
+			object.Skipped = true
+			record(object)
+
+			return nil
+		}
+
+		return pool.Queue(func(ctx context.Context) error { return ul(ctx, object, source) })
+	}
+
+	err = filepath.WalkDir(opts.Source, walk)
+	if err != nil {
+		// Purposefully ignored, we're already returning the walk error
+		_ = pool.Stop()
+
+		return nil, fmt.Errorf("failed to walk directory: %w", err)
+	}
+
+	err = pool.Stop()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop worker pool: %w", err)
+	}
+
+	slices.SortFunc(manifest.Objects, func(a, b UploadedObject) int { return strings.Compare(a.Key, b.Key) })
+
+	return manifest, nil
+}
+
+// uploadDirectoryFile uploads the given local file to the given key.
+func uploadDirectoryFile(opts UploadDirectoryOptions, options Options, key, source string) error {
+	file, err := fsutil.OpenRandAccess(source, 0, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	return Upload(UploadOptions{
+		Options:      options,
+		Client:       opts.Client,
+		Bucket:       opts.Bucket,
+		Key:          key,
+		Body:         file,
+		MPUThreshold: opts.MPUThreshold,
+	})
+}
+
+// existingDirectoryObjects returns the attributes of the objects which already exist under the destination prefix,
+// when skipping unchanged files.
+func existingDirectoryObjects(opts UploadDirectoryOptions) (map[string]*objval.ObjectAttrs, error) {
+	if !opts.SkipUnchanged {
+		return nil, nil
+	}
+
+	existing := make(map[string]*objval.ObjectAttrs)
+
+	fn := func(attrs *objval.ObjectAttrs) error {
+		if !attrs.IsDir() {
+			existing[attrs.Key] = attrs
+		}
+
+		return nil
+	}
+
+	err := opts.Client.IterateObjects(opts.Context, objcli.IterateObjectsOptions{
+		Bucket: opts.Bucket,
+		Prefix: opts.Prefix,
+		Func:   fn,
+	})
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	return existing, nil
+}
+
+// isUnchanged returns a boolean indicating whether the given local file is the same as the existing object, and may
+// therefore be skipped.
+func isUnchanged(
+	opts UploadDirectoryOptions,
+	source string,
+	info fs.FileInfo,
+	attrs *objval.ObjectAttrs,
+) (bool, error) {
+	if attrs == nil || ptr.From(attrs.Size) != info.Size() {
+		return false, nil
+	}
+
+	if attrs.LastModified != nil && !info.ModTime().After(*attrs.LastModified) {
+		return true, nil
+	}
+
+	if !opts.CompareETag || attrs.ETag == nil {
+		return false, nil
+	}
+
+	checksum, err := md5File(source)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.Trim(*attrs.ETag, `"`) == checksum, nil
+}
+
+// md5File returns the hex encoded MD5 checksum of the given file.
+func md5File(source string) (string, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	hash := md5.New()
+
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,138 @@
+package objutil
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// writeTestDirectory creates the given files (relative path to contents) in a temporary directory.
+func writeTestDirectory(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	}
+
+	return dir
+}
+
+func TestUploadDirectoryPreservesRelativePaths(t *testing.T) {
+	var (
+		client = objcli.NewTestClient(t, objval.ProviderAWS)
+		dir    = writeTestDirectory(t, map[string]string{
+			"a.txt":         "a",
+			"data/b.json":   "bb",
+			"data/sub/c.db": "ccc",
+		})
+	)
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "empty"), 0o755))
+
+	manifest, err := UploadDirectory(UploadDirectoryOptions{
+		Client: client,
+		Source: dir,
+		Bucket: "bucket",
+		Prefix: "backups",
+	})
+	require.NoError(t, err)
+
+	expected := []UploadedObject{
+		{Path: "a.txt", Key: "backups/a.txt", Size: 1},
+		{Path: "data/b.json", Key: "backups/data/b.json", Size: 2},
+		{Path: "data/sub/c.db", Key: "backups/data/sub/c.db", Size: 3},
+	}
+
+	require.Equal(t, expected, manifest.Objects)
+	require.Equal(t, expected, manifest.Uploaded())
+
+	require.Len(t, client.Buckets["bucket"], 3)
+	require.Equal(t, []byte("a"), client.Buckets["bucket"]["backups/a.txt"].Body)
+	require.Equal(t, []byte("bb"), client.Buckets["bucket"]["backups/data/b.json"].Body)
+	require.Equal(t, []byte("ccc"), client.Buckets["bucket"]["backups/data/sub/c.db"].Body)
+}
+
+func TestUploadDirectorySkipUnchanged(t *testing.T) {
+	var (
+		client = objcli.NewTestClient(t, objval.ProviderAWS)
+		dir    = writeTestDirectory(t, map[string]string{"same": "1", "resized": "2", "modified": "3", "new": "4"})
+	)
+
+	_, err := UploadDirectory(UploadDirectoryOptions{Client: client, Source: dir, Bucket: "bucket"})
+	require.NoError(t, err)
+
+	delete(client.Buckets["bucket"], "new")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "resized"), []byte("22"), 0o600))
+
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "modified"), future, future))
+
+	manifest, err := UploadDirectory(UploadDirectoryOptions{
+		Client:        client,
+		Source:        dir,
+		Bucket:        "bucket",
+		SkipUnchanged: true,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []UploadedObject{
+		{Path: "modified", Key: "modified", Size: 1},
+		{Path: "new", Key: "new", Size: 1},
+		{Path: "resized", Key: "resized", Size: 2},
+		{Path: "same", Key: "same", Size: 1, Skipped: true},
+	}, manifest.Objects)
+
+	require.Equal(t, []byte("22"), client.Buckets["bucket"]["resized"].Body)
+}
+
+func TestUploadDirectoryCompareETag(t *testing.T) {
+	var (
+		client = objcli.NewTestClient(t, objval.ProviderAWS)
+		dir    = writeTestDirectory(t, map[string]string{"matches": "1", "differs": "2"})
+	)
+
+	_, err := UploadDirectory(UploadDirectoryOptions{Client: client, Source: dir, Bucket: "bucket"})
+	require.NoError(t, err)
+
+	checksum := md5.Sum([]byte("1"))
+	client.Buckets["bucket"]["matches"].ETag = ptr.To(`"` + hex.EncodeToString(checksum[:]) + `"`)
+
+	future := time.Now().Add(time.Hour)
+
+	for _, name := range []string{"matches", "differs"} {
+		require.NoError(t, os.Chtimes(filepath.Join(dir, name), future, future))
+	}
+
+	manifest, err := UploadDirectory(UploadDirectoryOptions{
+		Client:        client,
+		Source:        dir,
+		Bucket:        "bucket",
+		SkipUnchanged: true,
+		CompareETag:   true,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []UploadedObject{{Path: "differs", Key: "differs", Size: 1}}, manifest.Uploaded())
+}
+
+func TestUploadDirectoryNotFound(t *testing.T) {
+	_, err := UploadDirectory(UploadDirectoryOptions{
+		Client: objcli.NewTestClient(t, objval.ProviderAWS),
+		Source: filepath.Join(t.TempDir(), "missing"),
+		Bucket: "bucket",
+	})
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
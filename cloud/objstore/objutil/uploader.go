@@ -60,6 +60,11 @@ type MPUploaderOptions struct {
 	// NOTE: Here be dragons, no validation takes place to ensure these parts are still available.
 	Parts []objval.Part
 
+	// Metadata is user-defined metadata attached to the completed object.
+	//
+	// NOTE: When continuing an upload, the same metadata must be provided as when it was created.
+	Metadata map[string]string
+
 	// OnPartComplete is a callback which is run after successfully uploading each part.
 	//
 	// This function:
@@ -127,8 +132,9 @@ func (m *MPUploader) createMPU() error {
 	var err error
 
 	m.opts.ID, err = m.opts.Client.CreateMultipartUpload(m.opts.Context, objcli.CreateMultipartUploadOptions{
-		Bucket: m.opts.Bucket,
-		Key:    m.opts.Key,
+		Bucket:   m.opts.Bucket,
+		Key:      m.opts.Key,
+		Metadata: m.opts.Metadata,
 	})
 
 	return err
@@ -170,16 +176,19 @@ func (m *MPUploader) UploadWithMeta(metadata any, body io.ReadSeeker) error {
 // upload a new part with the given number/body.
 func (m *MPUploader) upload(ctx context.Context, number int, metadata any, body io.ReadSeeker) error {
 	part, err := m.opts.Client.UploadPart(ctx, objcli.UploadPartOptions{
-		Bucket:   m.opts.Bucket,
-		UploadID: m.opts.ID,
-		Key:      m.opts.Key,
-		Number:   number,
-		Body:     body,
+		Bucket:           m.opts.Bucket,
+		UploadID:         m.opts.ID,
+		Key:              m.opts.Key,
+		Number:           number,
+		Body:             body,
+		BandwidthLimiter: m.opts.BandwidthLimiter,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to upload part: %w", err)
 	}
 
+	m.opts.Progress.Add(part.Size)
+
 	// Parts may be uploaded concurrently, but must be marked as completed one at a time
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -241,6 +250,7 @@ func (m *MPUploader) Commit() error {
 		UploadID: m.opts.ID,
 		Key:      m.opts.Key,
 		Parts:    m.opts.Parts,
+		Metadata: m.opts.Metadata,
 	})
 
 	return err
@@ -0,0 +1,289 @@
+package objutil
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objerr"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/sync/v2/hofp"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// md5ETag matches an ETag which is the hex encoded MD5 checksum of an object, multipart uploads have an ETag suffixed
+// with the number of parts e.g. '<checksum>-16' so won't match.
+var md5ETag = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// VerifyManifestEntry is the expected state of a single object.
+type VerifyManifestEntry struct {
+	// Size is the expected size of the object in bytes.
+	Size int64 `json:"size"`
+
+	// MD5 is the expected hex encoded MD5 checksum of the object, when empty only the size of the object is verified.
+	MD5 string `json:"md5,omitempty"`
+}
+
+// VerifyManifest maps the keys of objects, to their expected state.
+type VerifyManifest map[string]VerifyManifestEntry
+
+// VerifyOptions encapsulates the available options which can be used when verifying the objects in a manifest.
+type VerifyOptions struct {
+	Options
+
+	// Client is the client used to perform the operation.
+	//
+	// NOTE: This attribute is required.
+	Client objcli.Client
+
+	// Bucket is the bucket containing the objects being verified.
+	//
+	// NOTE: This attribute is required.
+	Bucket string
+
+	// Manifest is the objects which are verified.
+	Manifest VerifyManifest
+
+	// AlwaysRead disables using the ETag of an object in place of its checksum, forcing the object to be read.
+	//
+	// NOTE: In AWS, the ETag of an object which is encrypted using SSE-KMS/SSE-C isn't its MD5 checksum, such objects
+	// must be read to avoid reporting false mismatches.
+	AlwaysRead bool
+
+	// Concurrency is the number of objects which may be verified concurrently, defaults to the number of vCPUs.
+	Concurrency int
+
+	// Logger is the logger that'll be used.
+	Logger *slog.Logger
+}
+
+// defaults fills any missing attributes to a sane default.
+func (v *VerifyOptions) defaults() {
+	v.Options.defaults()
+
+	if v.Logger == nil {
+		v.Logger = slog.Default()
+	}
+}
+
+// VerifyMismatchReason is the reason an object failed verification.
+type VerifyMismatchReason string
+
+const (
+	// VerifyMismatchMissing indicates that the object doesn't exist.
+	VerifyMismatchMissing VerifyMismatchReason = "missing"
+
+	// VerifyMismatchSize indicates that the size of the object differs from the manifest.
+	VerifyMismatchSize VerifyMismatchReason = "size"
+
+	// VerifyMismatchChecksum indicates that the checksum of the object differs from the manifest.
+	VerifyMismatchChecksum VerifyMismatchReason = "checksum"
+)
+
+// VerifyMismatch describes a single object which failed verification.
+type VerifyMismatch struct {
+	// Key is the key of the object.
+	Key string `json:"key"`
+
+	// Reason is the reason the object failed verification.
+	Reason VerifyMismatchReason `json:"reason"`
+
+	// Expected/Actual are the expected/actual size or checksum of the object, depending on the reason; both are empty
+	// for missing objects.
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// VerifyReport is the result of verifying the objects in a manifest.
+type VerifyReport struct {
+	// Objects is the number of objects which were verified.
+	Objects int `json:"objects"`
+
+	// Read is the number of objects which were read to calculate their checksum, because the provider attributes
+	// didn't include a usable checksum.
+	Read int `json:"read"`
+
+	// Mismatches is the objects which failed verification, sorted by key.
+	Mismatches []VerifyMismatch `json:"mismatches"`
+}
+
+// OK returns a boolean indicating whether all the objects passed verification.
+func (v *VerifyReport) OK() bool {
+	return len(v.Mismatches) == 0
+}
+
+// Verify concurrently checks that each object in the manifest exists, and has the expected size/checksum. Checksums are
+// compared against the attributes returned by the provider where possible, otherwise the object is read using ranged
+// requests of 'PartSize' bytes.
+//
+// NOTE: Mismatches are reported, rather than returned as an error; an error is only returned when an object couldn't be
+// verified e.g. due to a permissions issue.
+func Verify(opts VerifyOptions) (*VerifyReport, error) {
+	// Fill out any missing fields with the sane defaults
+	opts.defaults()
+
+	defer opts.Progress.Done()
+
+	var total int64
+	for _, entry := range opts.Manifest {
+		total += entry.Size
+	}
+
+	opts.Progress.SetTotal(total)
+
+	pool := hofp.NewPool(hofp.Options{
+		Context: opts.Context,
+		Size:    opts.Concurrency,
+		Logger:  opts.Logger,
+	})
+
+	var (
+		report = &VerifyReport{Mismatches: make([]VerifyMismatch, 0)}
+		lock   sync.Mutex
+	)
+
+	record := func(mismatch *VerifyMismatch, read bool) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		report.Objects++
+
+		if read {
+			report.Read++
+		}
+
+		if mismatch != nil {
+			report.Mismatches = append(report.Mismatches, *mismatch)
+		}
+	}
+
+	verify := func(ctx context.Context, key string, entry VerifyManifestEntry) error {
+		options := opts
+		options.Options = opts.WithContext(ctx)
+
+		mismatch, read, err := verifyObject(options, key, entry)
+		if err != nil {
+			return fmt.Errorf("failed to verify '%s': %w", key, err)
+		}
+
+		if mismatch != nil {
+			opts.Logger.Warn("object failed verification", "key", key, "reason", mismatch.Reason)
+		}
+
+		record(mismatch, read)
+		opts.Progress.Add(entry.Size)
+
+		return nil
+	}
+
+	for key, entry := range opts.Manifest {
+		if pool.Queue(func(ctx context.Context) error { return verify(ctx, key, entry) }) != nil {
+			break
+		}
+	}
+
+	err := pool.Stop()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop worker pool: %w", err)
+	}
+
+	slices.SortFunc(report.Mismatches, func(a, b VerifyMismatch) int { return strings.Compare(a.Key, b.Key) })
+
+	return report, nil
+}
+
+// verifyObject verifies a single object, returning a mismatch if it fails verification and a boolean indicating whether
+// the object was read.
+func verifyObject(opts VerifyOptions, key string, entry VerifyManifestEntry) (*VerifyMismatch, bool, error) {
+	attrs, err := opts.Client.GetObjectAttrs(opts.Context, objcli.GetObjectAttrsOptions{Bucket: opts.Bucket, Key: key})
+	if objerr.IsNotFoundError(err) {
+		return &VerifyMismatch{Key: key, Reason: VerifyMismatchMissing}, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get object attributes: %w", err)
+	}
+
+	if size := ptr.From(attrs.Size); size != entry.Size {
+		return &VerifyMismatch{
+			Key:      key,
+			Reason:   VerifyMismatchSize,
+			Expected: strconv.FormatInt(entry.Size, 10),
+			Actual:   strconv.FormatInt(size, 10),
+		}, false, nil
+	}
+
+	if entry.MD5 == "" {
+		return nil, false, nil
+	}
+
+	expected := strings.ToLower(entry.MD5)
+
+	actual, ok := etagChecksum(opts, attrs)
+	if !ok {
+		actual, err = md5Object(opts, key, entry.Size)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to read object: %w", err)
+		}
+	}
+
+	if actual != expected {
+		return &VerifyMismatch{Key: key, Reason: VerifyMismatchChecksum, Expected: expected, Actual: actual}, !ok, nil
+	}
+
+	return nil, !ok, nil
+}
+
+// etagChecksum returns the MD5 checksum of an object using its ETag, and a boolean indicating whether the ETag is
+// usable as a checksum.
+//
+// NOTE: Only AWS uses the MD5 checksum of an object as its ETag, other providers use an opaque value.
+func etagChecksum(opts VerifyOptions, attrs *objval.ObjectAttrs) (string, bool) {
+	if opts.AlwaysRead || attrs.ETag == nil || opts.Client.Provider() != objval.ProviderAWS {
+		return "", false
+	}
+
+	etag := strings.ToLower(strings.Trim(*attrs.ETag, `"`))
+
+	return etag, md5ETag.MatchString(etag)
+}
+
+// md5Object returns the hex encoded MD5 checksum of the given object, which is read using ranged requests.
+func md5Object(opts VerifyOptions, key string, size int64) (string, error) {
+	hash := md5.New()
+
+	read := func(start, end int64) error {
+		object, err := opts.Client.GetObject(opts.Context, objcli.GetObjectOptions{
+			Bucket:    opts.Bucket,
+			Key:       key,
+			ByteRange: &objval.ByteRange{Start: start, End: end},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get object range %d-%d: %w", start, end, err)
+		}
+		defer object.Body.Close()
+
+		_, err = io.Copy(hash, object.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read object range %d-%d: %w", start, end, err)
+		}
+
+		return nil
+	}
+
+	err := chunk(size, opts.PartSize, read)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,114 @@
+package objutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objcli"
+	"github.com/couchbase/tools-common/cloud/v6/objstore/objval"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// md5Hex returns the hex encoded MD5 checksum of the given data.
+func md5Hex(data []byte) string {
+	checksum := md5.Sum(data)
+	return hex.EncodeToString(checksum[:])
+}
+
+// putVerifyObjects uploads the given objects (key to body) to the test client.
+func putVerifyObjects(t *testing.T, client objcli.Client, objects map[string][]byte) {
+	for key, body := range objects {
+		require.NoError(t, client.PutObject(context.Background(), objcli.PutObjectOptions{
+			Bucket: "bucket",
+			Key:    key,
+			Body:   bytes.NewReader(body),
+		}))
+	}
+}
+
+func TestVerify(t *testing.T) {
+	var (
+		client = objcli.NewTestClient(t, objval.ProviderGCP)
+		large  = bytes.Repeat([]byte("a"), 2*MinPartSize+1)
+	)
+
+	putVerifyObjects(t, client, map[string][]byte{"small": []byte("small"), "large": large, "unchecked": []byte("1")})
+
+	report, err := Verify(VerifyOptions{
+		Client: client,
+		Bucket: "bucket",
+		Manifest: VerifyManifest{
+			"small":     {Size: 5, MD5: md5Hex([]byte("small"))},
+			"large":     {Size: int64(len(large)), MD5: md5Hex(large)},
+			"unchecked": {Size: 1},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, report.OK())
+	require.Equal(t, &VerifyReport{Objects: 3, Read: 2, Mismatches: make([]VerifyMismatch, 0)}, report)
+}
+
+func TestVerifyMismatches(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderGCP)
+
+	putVerifyObjects(t, client, map[string][]byte{"resized": []byte("22"), "modified": []byte("3")})
+
+	report, err := Verify(VerifyOptions{
+		Client: client,
+		Bucket: "bucket",
+		Manifest: VerifyManifest{
+			"missing":  {Size: 1},
+			"resized":  {Size: 1, MD5: md5Hex([]byte("2"))},
+			"modified": {Size: 1, MD5: md5Hex([]byte("1"))},
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, report.OK())
+
+	expected := []VerifyMismatch{
+		{Key: "missing", Reason: VerifyMismatchMissing},
+		{
+			Key:      "modified",
+			Reason:   VerifyMismatchChecksum,
+			Expected: md5Hex([]byte("1")),
+			Actual:   md5Hex([]byte("3")),
+		},
+		{Key: "resized", Reason: VerifyMismatchSize, Expected: "1", Actual: "2"},
+	}
+
+	require.Equal(t, 3, report.Objects)
+	require.Equal(t, 1, report.Read)
+	require.Equal(t, expected, report.Mismatches)
+}
+
+func TestVerifyUsesETag(t *testing.T) {
+	client := objcli.NewTestClient(t, objval.ProviderAWS)
+
+	putVerifyObjects(t, client, map[string][]byte{"etag": []byte("1"), "multipart": []byte("2")})
+
+	// The body is never read when the ETag is the checksum of the object, so the ETag takes precedence
+	client.Buckets["bucket"]["etag"].ETag = ptr.To(`"` + md5Hex([]byte("0")) + `"`)
+	client.Buckets["bucket"]["multipart"].ETag = ptr.To(`"` + md5Hex([]byte("0")) + `-2"`)
+
+	manifest := VerifyManifest{
+		"etag":      {Size: 1, MD5: md5Hex([]byte("0"))},
+		"multipart": {Size: 1, MD5: md5Hex([]byte("2"))},
+	}
+
+	report, err := Verify(VerifyOptions{Client: client, Bucket: "bucket", Manifest: manifest})
+	require.NoError(t, err)
+	require.True(t, report.OK())
+	require.Equal(t, 1, report.Read)
+
+	report, err = Verify(VerifyOptions{Client: client, Bucket: "bucket", Manifest: manifest, AlwaysRead: true})
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Read)
+	require.Equal(t, []VerifyMismatch{
+		{Key: "etag", Reason: VerifyMismatchChecksum, Expected: md5Hex([]byte("0")), Actual: md5Hex([]byte("1"))},
+	}, report.Mismatches)
+}
@@ -0,0 +1,36 @@
+package objval
+
+// Capabilities describes the functionality supported by a client, and the limits imposed by the underlying store; this
+// allows generic code to change its behavior without inferring support from the 'Provider', which isn't accurate for
+// stores which are only compatible with a provider e.g. S3 compatible stores.
+type Capabilities struct {
+	// SupportsVersioning indicates whether the versioning status of a bucket may be retrieved.
+	SupportsVersioning bool
+
+	// SupportsCompose indicates whether objects may be created server-side from existing objects, using
+	// 'UploadPartCopy'.
+	SupportsCompose bool
+
+	// SupportsObjectLock indicates whether write-once-read-many retention of objects may be managed using the client
+	// e.g. using 'SetObjectLock' to apply GCS object retention or holds.
+	//
+	// NOTE: This is only set for clients which implement the object lock operations, even where the underlying store
+	// supports object lock.
+	SupportsObjectLock bool
+
+	// SupportsSoftDelete indicates whether deleted objects may be listed/recovered, for stores which retain them for a
+	// period of time after deletion e.g. Azure storage accounts with blob soft delete enabled.
+	//
+	// NOTE: Soft delete may need to be enabled in the store, where it's not, no deleted objects will be listed.
+	SupportsSoftDelete bool
+
+	// MaxParts is the maximum number of parts which may be uploaded in a multipart upload, zero means there's no limit.
+	MaxParts int
+
+	// MinPartSize is the minimum size of each part of a multipart upload, excluding the last part.
+	MinPartSize int64
+
+	// MaxCopySize is the maximum size of an object which may be copied using a single request, larger objects must be
+	// copied using a multipart upload; zero means single request copies should not be used.
+	MaxCopySize int64
+}
@@ -0,0 +1,15 @@
+package objval
+
+import "time"
+
+// DeletedObject represents an object which has been soft-deleted, and may still be recovered.
+type DeletedObject struct {
+	ObjectAttrs
+
+	// DeletedTime is the time at which the object was deleted.
+	DeletedTime *time.Time
+
+	// RemainingRetentionDays is the number of days until the object is permanently deleted, after which it may no
+	// longer be recovered.
+	RemainingRetentionDays *int
+}
@@ -0,0 +1,46 @@
+package objval
+
+import "time"
+
+// LockType represents the type of lock applied to an object, locks either retain an object until a given time or hold
+// it until the lock is released.
+type LockType string
+
+const (
+	// LockTypeCompliance retains the object until the lock expires, the lock may be extended but may not be shortened or
+	// removed by any user.
+	LockTypeCompliance LockType = "Compliance"
+
+	// LockTypeGovernance retains the object until the lock expires, users with the required permissions may shorten or
+	// remove the lock.
+	LockTypeGovernance LockType = "Governance"
+
+	// LockTypeTemporaryHold holds the object until the lock is released, it has no expiration.
+	LockTypeTemporaryHold LockType = "TemporaryHold"
+
+	// LockTypeEventBasedHold holds the object until the lock is released, once released the bucket retention period (if
+	// any) starts from the time of release rather than the time the object was created.
+	LockTypeEventBasedHold LockType = "EventBasedHold"
+)
+
+// IsHold returns a boolean indicating whether the lock type is a hold, which has no expiration.
+func (l LockType) IsHold() bool {
+	return l == LockTypeTemporaryHold || l == LockTypeEventBasedHold
+}
+
+// ObjectLock represents a lock applied to an object.
+type ObjectLock struct {
+	// Type is the type of the lock.
+	Type LockType
+
+	// Expiration is the time until which the object is retained, this is ignored for holds.
+	Expiration time.Time
+}
+
+// BucketLockingStatus represents the object lock configuration of a bucket.
+type BucketLockingStatus struct {
+	// Enabled indicates whether retention locks may be applied to objects in the bucket.
+	//
+	// NOTE: Some cloud providers (e.g. GCP) support holds even where this is false.
+	Enabled bool
+}
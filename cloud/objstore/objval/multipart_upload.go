@@ -0,0 +1,16 @@
+package objval
+
+import "time"
+
+// MultipartUpload represents a multipart upload which has been created, but not yet completed or aborted.
+type MultipartUpload struct {
+	// Key is the key (path) of the object being uploaded.
+	Key string
+
+	// UploadID is the id of the upload, which should be used when aborting the upload.
+	UploadID string
+
+	// Initiated is the time at which the upload was created; for clients which emulate multipart uploads, this is the
+	// time at which the earliest part was uploaded.
+	Initiated time.Time
+}
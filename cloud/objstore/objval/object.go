@@ -27,6 +27,27 @@ type ObjectAttrs struct {
 	// NOTE: The semantics of this attribute may differ between cloud providers (e.g. an change of metadata might bump
 	// the last modified time).
 	LastModified *time.Time
+
+	// Metadata is the user-defined metadata attached to the object when it was created.
+	//
+	// NOTE: Only guaranteed to be populated by 'GetObjectAttrs', or when iterating objects with 'ListInclude.Metadata'
+	// for clients which support it; keys are returned in lowercase.
+	Metadata map[string]string
+
+	// Tags are the tags attached to the object.
+	//
+	// NOTE: Only populated when iterating objects with 'ListInclude.Tags', for clients which support it.
+	Tags map[string]string
+
+	// VersionID is the identifier of this version of the object.
+	//
+	// NOTE: Only populated when iterating objects with 'ListInclude.Versions', for clients which support it.
+	VersionID *string
+
+	// Snapshot is the identifier of the snapshot this object represents, <nil> for the base object.
+	//
+	// NOTE: Only populated when iterating objects with 'ListInclude.Snapshots', for clients which support it.
+	Snapshot *string
 }
 
 // IsDir returns a boolean indicating whether these attributes represent a synthetic directory, created by the library
@@ -38,6 +59,17 @@ func (o *ObjectAttrs) IsDir() bool {
 	return o.Size == nil && o.ETag == nil && o.LastModified == nil
 }
 
+// ObjectVersion identifies a version of an object.
+type ObjectVersion struct {
+	// Key is the key (path) of the object.
+	Key string
+
+	// VersionID is the identifier of the version, the latest version of the object is identified when empty.
+	//
+	// NOTE: Version ids are provider specific e.g. the generation of the object in GCP.
+	VersionID string
+}
+
 // Object represents an object stored in the cloud, simply the attributes and it's body.
 type Object struct {
 	ObjectAttrs
@@ -58,4 +90,8 @@ type TestBucket map[string]*TestObject
 type TestObject struct {
 	ObjectAttrs
 	Body []byte
+	Tags map[string]string
+
+	// ContentEncoding is the encoding of the body e.g. 'gzip' when it's compressed.
+	ContentEncoding string
 }
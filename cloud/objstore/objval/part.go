@@ -14,6 +14,10 @@ type Part struct {
 
 	// Size is the size of the part in bytes.
 	Size int64
+
+	// Checksum is the base64 encoded checksum of the part, only populated by clients which calculate part checksums
+	// e.g. 'objaws' when configured with a checksum algorithm.
+	Checksum string
 }
 
 // Equal returns a boolean indicating whether this part is equal to the given part.
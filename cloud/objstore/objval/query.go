@@ -0,0 +1,47 @@
+package objval
+
+// QueryFormat represents the format of the data being queried, or the format of the query results.
+type QueryFormat string
+
+const (
+	// QueryFormatJSON indicates that the data is JSON.
+	QueryFormatJSON QueryFormat = "JSON"
+
+	// QueryFormatCSV indicates that the data is CSV.
+	QueryFormatCSV QueryFormat = "CSV"
+)
+
+// QueryCompression represents the compression applied to the object being queried.
+type QueryCompression string
+
+const (
+	// QueryCompressionNone indicates that the object is not compressed.
+	QueryCompressionNone QueryCompression = "NONE"
+
+	// QueryCompressionGZIP indicates that the object is gzip compressed.
+	QueryCompressionGZIP QueryCompression = "GZIP"
+
+	// QueryCompressionBZIP2 indicates that the object is bzip2 compressed.
+	QueryCompressionBZIP2 QueryCompression = "BZIP2"
+)
+
+// QueryInput describes the object being queried.
+type QueryInput struct {
+	// Format is the format of the object, defaults to JSON.
+	Format QueryFormat
+
+	// Compression is the compression applied to the object, defaults to none.
+	Compression QueryCompression
+
+	// JSONLines indicates that a JSON object contains one document per line, rather than a single document.
+	JSONLines bool
+
+	// CSVHeader indicates that the first line of a CSV object is a header, allowing columns to be referenced by name.
+	CSVHeader bool
+}
+
+// QueryOutput describes the format of the query results.
+type QueryOutput struct {
+	// Format is the format of the results, defaults to JSON; JSON results are returned as one document per line.
+	Format QueryFormat
+}
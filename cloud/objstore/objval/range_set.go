@@ -0,0 +1,164 @@
+package objval
+
+import (
+	"slices"
+)
+
+// RangeSet is a set of bytes within an object, represented as sorted, non-overlapping byte ranges; overlapping and
+// adjacent ranges are coalesced as they're added. This may be used to plan ranged downloads or sparse restores, for
+// example determining which parts of an object haven't yet been downloaded.
+//
+// NOTE: Unlike when using a single 'ByteRange' with a client, an 'End' of zero isn't treated as open-ended; ranges in
+// a set are always bounded, inclusive of both 'Start' and 'End'.
+type RangeSet struct {
+	ranges []ByteRange
+}
+
+// NewRangeSet returns a new set containing the given ranges, ranges where 'End' is before 'Start' are ignored.
+func NewRangeSet(ranges ...ByteRange) *RangeSet {
+	set := &RangeSet{}
+	set.Add(ranges...)
+
+	return set
+}
+
+// Add adds the given ranges to the set, ranges where 'End' is before 'Start' are ignored.
+func (r *RangeSet) Add(ranges ...ByteRange) {
+	r.ranges = coalesce(append(r.ranges, ranges...), 0)
+}
+
+// Ranges returns a copy of the ranges in the set, in ascending order.
+func (r *RangeSet) Ranges() []ByteRange {
+	return slices.Clone(r.ranges)
+}
+
+// Empty returns a boolean indicating whether the set contains no bytes.
+func (r *RangeSet) Empty() bool {
+	return len(r.ranges) == 0
+}
+
+// Size returns the total number of bytes in the set.
+func (r *RangeSet) Size() int64 {
+	var size int64
+
+	for _, br := range r.ranges {
+		size += br.End - br.Start + 1
+	}
+
+	return size
+}
+
+// Contains returns a boolean indicating whether the byte at the given offset is in the set.
+func (r *RangeSet) Contains(offset int64) bool {
+	_, found := slices.BinarySearchFunc(r.ranges, offset, func(br ByteRange, offset int64) int {
+		switch {
+		case br.End < offset:
+			return -1
+		case br.Start > offset:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return found
+}
+
+// Union returns a new set containing the bytes which are in either set.
+func (r *RangeSet) Union(other *RangeSet) *RangeSet {
+	return &RangeSet{ranges: coalesce(append(slices.Clone(r.ranges), other.ranges...), 0)}
+}
+
+// Intersect returns a new set containing the bytes which are in both sets.
+func (r *RangeSet) Intersect(other *RangeSet) *RangeSet {
+	var (
+		intersection = make([]ByteRange, 0)
+		i, j         int
+	)
+
+	for i < len(r.ranges) && j < len(other.ranges) {
+		a, b := r.ranges[i], other.ranges[j]
+
+		if start, end := max(a.Start, b.Start), min(a.End, b.End); start <= end {
+			intersection = append(intersection, ByteRange{Start: start, End: end})
+		}
+
+		// Advance whichever range ends first, as it can't intersect with any subsequent ranges in the other set
+		if a.End < b.End {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return &RangeSet{ranges: intersection}
+}
+
+// Gaps returns the ranges within the given bounds which aren't in the set, for example, the ranges of an object which
+// still need to be downloaded.
+func (r *RangeSet) Gaps(bounds ByteRange) []ByteRange {
+	gaps := make([]ByteRange, 0)
+
+	if bounds.End < bounds.Start {
+		return gaps
+	}
+
+	next := bounds.Start
+
+	for _, br := range r.ranges {
+		if br.End < next {
+			continue
+		}
+
+		if br.Start > bounds.End {
+			break
+		}
+
+		if br.Start > next {
+			gaps = append(gaps, ByteRange{Start: next, End: br.Start - 1})
+		}
+
+		next = br.End + 1
+	}
+
+	if next <= bounds.End {
+		gaps = append(gaps, ByteRange{Start: next, End: bounds.End})
+	}
+
+	return gaps
+}
+
+// Coalesce returns a new set where ranges separated by at most the given number of bytes are merged. This may be used
+// to reduce the number of requests needed to download the ranges, at the cost of downloading the bytes in the gaps.
+func (r *RangeSet) Coalesce(maxGap int64) *RangeSet {
+	return &RangeSet{ranges: coalesce(slices.Clone(r.ranges), max(0, maxGap))}
+}
+
+// coalesce sorts the given ranges, merging those which overlap or are separated by at most the given gap.
+func coalesce(ranges []ByteRange, gap int64) []ByteRange {
+	ranges = slices.DeleteFunc(ranges, func(br ByteRange) bool { return br.End < br.Start })
+
+	slices.SortFunc(ranges, func(a, b ByteRange) int {
+		switch {
+		case a.Start < b.Start:
+			return -1
+		case a.Start > b.Start:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	merged := make([]ByteRange, 0, len(ranges))
+
+	for _, br := range ranges {
+		if n := len(merged); n != 0 && br.Start <= merged[n-1].End+1+gap {
+			merged[n-1].End = max(merged[n-1].End, br.End)
+			continue
+		}
+
+		merged = append(merged, br)
+	}
+
+	return merged
+}
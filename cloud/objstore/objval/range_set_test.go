@@ -0,0 +1,242 @@
+package objval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRangeSet(t *testing.T) {
+	type test struct {
+		name     string
+		ranges   []ByteRange
+		expected []ByteRange
+	}
+
+	tests := []*test{
+		{
+			name:     "Empty",
+			expected: []ByteRange{},
+		},
+		{
+			name:     "Single",
+			ranges:   []ByteRange{{Start: 0, End: 64}},
+			expected: []ByteRange{{Start: 0, End: 64}},
+		},
+		{
+			name:     "Unsorted",
+			ranges:   []ByteRange{{Start: 128, End: 256}, {Start: 0, End: 64}},
+			expected: []ByteRange{{Start: 0, End: 64}, {Start: 128, End: 256}},
+		},
+		{
+			name:     "Overlapping",
+			ranges:   []ByteRange{{Start: 0, End: 64}, {Start: 32, End: 128}},
+			expected: []ByteRange{{Start: 0, End: 128}},
+		},
+		{
+			name:     "Contained",
+			ranges:   []ByteRange{{Start: 0, End: 128}, {Start: 32, End: 64}},
+			expected: []ByteRange{{Start: 0, End: 128}},
+		},
+		{
+			name:     "Adjacent",
+			ranges:   []ByteRange{{Start: 0, End: 63}, {Start: 64, End: 128}},
+			expected: []ByteRange{{Start: 0, End: 128}},
+		},
+		{
+			name:     "SingleByte",
+			ranges:   []ByteRange{{Start: 64, End: 64}},
+			expected: []ByteRange{{Start: 64, End: 64}},
+		},
+		{
+			name:     "Invalid",
+			ranges:   []ByteRange{{Start: 128, End: 64}, {Start: 0, End: 32}},
+			expected: []ByteRange{{Start: 0, End: 32}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, NewRangeSet(test.ranges...).Ranges())
+		})
+	}
+}
+
+func TestRangeSetAdd(t *testing.T) {
+	set := NewRangeSet()
+	require.True(t, set.Empty())
+
+	set.Add(ByteRange{Start: 128, End: 255})
+	set.Add(ByteRange{Start: 0, End: 63})
+	set.Add(ByteRange{Start: 64, End: 127})
+
+	require.False(t, set.Empty())
+	require.Equal(t, []ByteRange{{Start: 0, End: 255}}, set.Ranges())
+}
+
+func TestRangeSetSize(t *testing.T) {
+	require.Zero(t, NewRangeSet().Size())
+	require.Equal(t, int64(1), NewRangeSet(ByteRange{Start: 64, End: 64}).Size())
+	require.Equal(t, int64(96), NewRangeSet(ByteRange{Start: 0, End: 63}, ByteRange{Start: 32, End: 95}).Size())
+	require.Equal(t, int64(20), NewRangeSet(ByteRange{Start: 0, End: 9}, ByteRange{Start: 20, End: 29}).Size())
+}
+
+func TestRangeSetContains(t *testing.T) {
+	set := NewRangeSet(ByteRange{Start: 10, End: 19}, ByteRange{Start: 30, End: 39})
+
+	for offset, expected := range map[int64]bool{
+		0:  false,
+		9:  false,
+		10: true,
+		15: true,
+		19: true,
+		20: false,
+		29: false,
+		30: true,
+		39: true,
+		40: false,
+	} {
+		require.Equal(t, expected, set.Contains(offset), "offset %d", offset)
+	}
+
+	require.False(t, NewRangeSet().Contains(0))
+}
+
+func TestRangeSetUnion(t *testing.T) {
+	var (
+		a = NewRangeSet(ByteRange{Start: 0, End: 9}, ByteRange{Start: 40, End: 49})
+		b = NewRangeSet(ByteRange{Start: 5, End: 19}, ByteRange{Start: 60, End: 69})
+	)
+
+	expected := []ByteRange{{Start: 0, End: 19}, {Start: 40, End: 49}, {Start: 60, End: 69}}
+
+	require.Equal(t, expected, a.Union(b).Ranges())
+	require.Equal(t, expected, b.Union(a).Ranges())
+
+	// Neither set should be modified
+	require.Equal(t, []ByteRange{{Start: 0, End: 9}, {Start: 40, End: 49}}, a.Ranges())
+	require.Equal(t, []ByteRange{{Start: 5, End: 19}, {Start: 60, End: 69}}, b.Ranges())
+}
+
+func TestRangeSetIntersect(t *testing.T) {
+	type test struct {
+		name     string
+		a, b     []ByteRange
+		expected []ByteRange
+	}
+
+	tests := []*test{
+		{
+			name:     "Empty",
+			a:        []ByteRange{{Start: 0, End: 9}},
+			expected: []ByteRange{},
+		},
+		{
+			name:     "Disjoint",
+			a:        []ByteRange{{Start: 0, End: 9}},
+			b:        []ByteRange{{Start: 10, End: 19}},
+			expected: []ByteRange{},
+		},
+		{
+			name:     "Overlapping",
+			a:        []ByteRange{{Start: 0, End: 9}},
+			b:        []ByteRange{{Start: 5, End: 19}},
+			expected: []ByteRange{{Start: 5, End: 9}},
+		},
+		{
+			name:     "SpansMultiple",
+			a:        []ByteRange{{Start: 0, End: 99}},
+			b:        []ByteRange{{Start: 10, End: 19}, {Start: 30, End: 39}, {Start: 95, End: 120}},
+			expected: []ByteRange{{Start: 10, End: 19}, {Start: 30, End: 39}, {Start: 95, End: 99}},
+		},
+		{
+			name:     "Interleaved",
+			a:        []ByteRange{{Start: 0, End: 9}, {Start: 20, End: 29}},
+			b:        []ByteRange{{Start: 5, End: 24}},
+			expected: []ByteRange{{Start: 5, End: 9}, {Start: 20, End: 24}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, b := NewRangeSet(test.a...), NewRangeSet(test.b...)
+
+			require.Equal(t, test.expected, a.Intersect(b).Ranges())
+			require.Equal(t, test.expected, b.Intersect(a).Ranges())
+		})
+	}
+}
+
+func TestRangeSetGaps(t *testing.T) {
+	set := NewRangeSet(ByteRange{Start: 10, End: 19}, ByteRange{Start: 30, End: 39})
+
+	type test struct {
+		name     string
+		bounds   ByteRange
+		expected []ByteRange
+	}
+
+	tests := []*test{
+		{
+			name:     "CoversSet",
+			bounds:   ByteRange{Start: 0, End: 49},
+			expected: []ByteRange{{Start: 0, End: 9}, {Start: 20, End: 29}, {Start: 40, End: 49}},
+		},
+		{
+			name:     "MatchesSet",
+			bounds:   ByteRange{Start: 10, End: 39},
+			expected: []ByteRange{{Start: 20, End: 29}},
+		},
+		{
+			name:     "WithinRange",
+			bounds:   ByteRange{Start: 12, End: 18},
+			expected: []ByteRange{},
+		},
+		{
+			name:     "WithinGap",
+			bounds:   ByteRange{Start: 22, End: 28},
+			expected: []ByteRange{{Start: 22, End: 28}},
+		},
+		{
+			name:     "BeforeSet",
+			bounds:   ByteRange{Start: 0, End: 5},
+			expected: []ByteRange{{Start: 0, End: 5}},
+		},
+		{
+			name:     "AfterSet",
+			bounds:   ByteRange{Start: 45, End: 50},
+			expected: []ByteRange{{Start: 45, End: 50}},
+		},
+		{
+			name:     "PartialOverlap",
+			bounds:   ByteRange{Start: 15, End: 34},
+			expected: []ByteRange{{Start: 20, End: 29}},
+		},
+		{
+			name:     "Invalid",
+			bounds:   ByteRange{Start: 50, End: 0},
+			expected: []ByteRange{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, set.Gaps(test.bounds))
+		})
+	}
+
+	require.Equal(t, []ByteRange{{Start: 0, End: 9}}, NewRangeSet().Gaps(ByteRange{Start: 0, End: 9}))
+}
+
+func TestRangeSetCoalesce(t *testing.T) {
+	set := NewRangeSet(ByteRange{Start: 0, End: 9}, ByteRange{Start: 15, End: 19}, ByteRange{Start: 40, End: 49})
+
+	require.Equal(t, set.Ranges(), set.Coalesce(0).Ranges())
+	require.Equal(t, set.Ranges(), set.Coalesce(-1).Ranges())
+	require.Equal(t, set.Ranges(), set.Coalesce(4).Ranges())
+	require.Equal(t, []ByteRange{{Start: 0, End: 19}, {Start: 40, End: 49}}, set.Coalesce(5).Ranges())
+	require.Equal(t, []ByteRange{{Start: 0, End: 49}}, set.Coalesce(20).Ranges())
+
+	// The original set should not be modified
+	require.Len(t, set.Ranges(), 3)
+}
@@ -0,0 +1,17 @@
+package objval
+
+// VersioningStatus represents the versioning state of a bucket.
+type VersioningStatus string
+
+const (
+	// VersioningStatusDisabled means versioning has never been enabled for the bucket.
+	VersioningStatusDisabled VersioningStatus = "Disabled"
+
+	// VersioningStatusEnabled means versioning is enabled for the bucket, overwritten/deleted objects are retained as
+	// non-current versions.
+	VersioningStatusEnabled VersioningStatus = "Enabled"
+
+	// VersioningStatusSuspended means versioning was previously enabled for the bucket, but has since been suspended;
+	// existing versions are retained, but new versions are not created.
+	VersioningStatusSuspended VersioningStatus = "Suspended"
+)
@@ -64,6 +64,10 @@ type ResolvedConnectionString struct {
 
 	// Params are any parsed query parameters, will be <nil> if none were parsed.
 	Params url.Values
+
+	// SRV indicates whether the addresses were resolved using a DNS SRV record, in which case they may change over time
+	// and should periodically be re-resolved by long-running clients.
+	SRV bool
 }
 
 // Parse the given connection string and perform first tier validation i.e. it's possible for a parsed connection string
@@ -244,6 +248,7 @@ func (c *ConnectionString) resolveSRV() *ResolvedConnectionString {
 
 	resolved := &ResolvedConnectionString{
 		UseSSL: c.Scheme == "couchbases",
+		SRV:    true,
 	}
 
 	for _, server := range servers {
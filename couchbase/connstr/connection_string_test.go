@@ -345,6 +345,7 @@ func TestConnectionStringResolve(t *testing.T) {
 			name:  "ValidSRVNoTLS",
 			input: "couchbase://example.com",
 			expected: &ResolvedConnectionString{
+				SRV:       true,
 				Addresses: []Address{{Host: "example.org", Port: DefaultHTTPPort}},
 			},
 			zones: map[string]mockdns.Zone{
@@ -355,6 +356,7 @@ func TestConnectionStringResolve(t *testing.T) {
 			name:  "ValidSRVTLS",
 			input: "couchbases://example.com",
 			expected: &ResolvedConnectionString{
+				SRV:       true,
 				UseSSL:    true,
 				Addresses: []Address{{Host: "example.org", Port: DefaultHTTPSPort}},
 			},
@@ -366,6 +368,7 @@ func TestConnectionStringResolve(t *testing.T) {
 			name:  "ValidSRVMultipleHostsNoTLS",
 			input: "couchbase://example.com",
 			expected: &ResolvedConnectionString{
+				SRV: true,
 				Addresses: []Address{
 					{Host: "example1.org", Port: DefaultHTTPPort},
 					{Host: "example2.org", Port: DefaultHTTPPort},
@@ -382,6 +385,7 @@ func TestConnectionStringResolve(t *testing.T) {
 			name:  "ValidSRVMultipleHostsTLS",
 			input: "couchbases://example.com",
 			expected: &ResolvedConnectionString{
+				SRV:    true,
 				UseSSL: true,
 				Addresses: []Address{
 					{Host: "example1.org", Port: DefaultHTTPSPort},
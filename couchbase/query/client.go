@@ -0,0 +1,245 @@
+// Package query provides a client for executing statements against the Query/Analytics Services, streaming the rows
+// returned rather than buffering the entire result set.
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	cbrest "github.com/couchbase/tools-common/couchbase/v3/rest"
+)
+
+const (
+	// EndpointQueryService is the endpoint used to execute statements using the Query Service.
+	EndpointQueryService cbrest.Endpoint = "/query/service"
+
+	// EndpointAnalyticsService is the endpoint used to execute statements using the Analytics Service.
+	EndpointAnalyticsService cbrest.Endpoint = "/analytics/service"
+)
+
+// preparedNotFoundCodes are the error codes returned by the Query Service when a prepared statement can't be used, and
+// must be prepared again.
+var preparedNotFoundCodes = []int{4040, 4050, 4070}
+
+// ClientOptions encapsulates the options for creating a new query client.
+type ClientOptions struct {
+	// Client is the REST client used to dispatch requests.
+	//
+	// NOTE: This attribute is required.
+	Client *cbrest.Client
+
+	// Service is the service statements are executed against, defaults to the Query Service.
+	//
+	// NOTE: Only 'cbrest.ServiceQuery' and 'cbrest.ServiceAnalytics' are supported.
+	Service cbrest.Service
+}
+
+// defaults fills any missing attributes to a sane default.
+func (c *ClientOptions) defaults() {
+	if c.Service == "" {
+		c.Service = cbrest.ServiceQuery
+	}
+}
+
+// Client is a wrapper around the 'cbrest' client which allows executing statements using the Query/Analytics Services.
+type Client struct {
+	client  *cbrest.Client
+	service cbrest.Service
+
+	lock     sync.Mutex
+	prepared map[string]string
+}
+
+// NewClient returns a new client using the given options.
+func NewClient(options ClientOptions) (*Client, error) {
+	options.defaults()
+
+	if options.Service != cbrest.ServiceQuery && options.Service != cbrest.ServiceAnalytics {
+		return nil, ErrUnsupportedService
+	}
+
+	return &Client{client: options.Client, service: options.Service, prepared: make(map[string]string)}, nil
+}
+
+// Options encapsulates the options available when executing a statement.
+type Options struct {
+	// Statement is the statement to execute.
+	//
+	// NOTE: This attribute is required.
+	Statement string
+
+	// PositionalParameters are the values for positional parameters e.g. '$1'.
+	PositionalParameters []any
+
+	// NamedParameters are the values for named parameters e.g. '$name', the leading '$' may be omitted.
+	NamedParameters map[string]any
+
+	// Prepared indicates that the statement should be prepared, with the prepared statement being reused by subsequent
+	// executions of the same statement by this client.
+	//
+	// NOTE: Only supported by the Query Service.
+	Prepared bool
+
+	// ReadOnly indicates that the statement doesn't modify any data, allowing the request to be safely retried.
+	ReadOnly bool
+
+	// Timeout is the server-side timeout for the statement, when omitted the service default is used.
+	Timeout time.Duration
+
+	// ClientContextID is an identifier which is returned in the metadata, and may be used to correlate requests.
+	ClientContextID string
+}
+
+// Query executes the given statement, returning the rows as a stream. An error is returned if the statement fails
+// before returning any rows, errors which occur afterwards are returned by 'Rows.Err'.
+func (c *Client) Query(ctx context.Context, opts Options) (*Rows, error) {
+	if !opts.Prepared {
+		return c.execute(ctx, opts, c.body(opts))
+	}
+
+	if c.service != cbrest.ServiceQuery {
+		return nil, ErrPreparedNotSupported
+	}
+
+	rows, err := c.executePrepared(ctx, opts)
+
+	var queryError *Error
+
+	// The prepared statement may have been removed from the cache of the Query Service, prepare it again
+	if !errors.As(err, &queryError) || !queryError.HasCode(preparedNotFoundCodes...) {
+		return rows, err
+	}
+
+	c.lock.Lock()
+	delete(c.prepared, opts.Statement)
+	c.lock.Unlock()
+
+	return c.executePrepared(ctx, opts)
+}
+
+// executePrepared executes the prepared version of the given statement, preparing it if required.
+func (c *Client) executePrepared(ctx context.Context, opts Options) (*Rows, error) {
+	name, err := c.prepare(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+
+	body := c.body(opts)
+	body["prepared"] = name
+
+	delete(body, "statement")
+
+	return c.execute(ctx, opts, body)
+}
+
+// prepare returns the name of the prepared statement for the given statement, preparing it if it hasn't been already.
+func (c *Client) prepare(ctx context.Context, opts Options) (string, error) {
+	c.lock.Lock()
+	name, ok := c.prepared[opts.Statement]
+	c.lock.Unlock()
+
+	if ok {
+		return name, nil
+	}
+
+	rows, err := c.execute(ctx, Options{ReadOnly: true}, map[string]any{"statement": "PREPARE " + opts.Statement})
+	if err != nil {
+		return "", err // Purposefully not wrapped
+	}
+	defer rows.Close()
+
+	var prepared struct {
+		Name string `json:"name"`
+	}
+
+	for rows.Next() {
+		if prepared.Name != "" {
+			continue
+		}
+
+		err = rows.Decode(&prepared)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode prepared statement: %w", err)
+		}
+	}
+
+	if rows.Err() != nil {
+		return "", rows.Err()
+	}
+
+	if prepared.Name == "" {
+		return "", fmt.Errorf("prepared statement has no name")
+	}
+
+	c.lock.Lock()
+	c.prepared[opts.Statement] = prepared.Name
+	c.lock.Unlock()
+
+	return prepared.Name, nil
+}
+
+// body returns the request body for the given options.
+func (c *Client) body(opts Options) map[string]any {
+	body := map[string]any{"statement": opts.Statement}
+
+	if len(opts.PositionalParameters) != 0 {
+		body["args"] = opts.PositionalParameters
+	}
+
+	for key, value := range opts.NamedParameters {
+		body["$"+strings.TrimPrefix(key, "$")] = value
+	}
+
+	if opts.ReadOnly {
+		body["readonly"] = true
+	}
+
+	if opts.Timeout != 0 {
+		body["timeout"] = opts.Timeout.String()
+	}
+
+	if opts.ClientContextID != "" {
+		body["client_context_id"] = opts.ClientContextID
+	}
+
+	return body
+}
+
+// execute dispatches the given request body, returning the rows from the response.
+func (c *Client) execute(ctx context.Context, opts Options, body map[string]any) (*Rows, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	endpoint := EndpointQueryService
+	if c.service == cbrest.ServiceAnalytics {
+		endpoint = EndpointAnalyticsService
+	}
+
+	request := &cbrest.Request{
+		Body:               encoded,
+		ContentType:        cbrest.ContentTypeJSON,
+		Endpoint:           endpoint,
+		ExpectedStatusCode: http.StatusOK,
+		Idempotent:         opts.ReadOnly,
+		Method:             http.MethodPost,
+		Service:            c.service,
+		// The server-side timeout should be used to limit the duration of the statement, rows are streamed so the client
+		// timeout may otherwise be exceeded.
+		Timeout: -1,
+	}
+
+	resp, err := c.client.Do(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	return newRows(resp.StatusCode, resp.Body)
+}
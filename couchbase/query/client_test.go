@@ -0,0 +1,181 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	aprov "github.com/couchbase/tools-common/auth/v2/provider"
+	cbrest "github.com/couchbase/tools-common/couchbase/v3/rest"
+	testutil "github.com/couchbase/tools-common/testing/util"
+)
+
+// newTestClient returns a query client for a test cluster which runs the Query/Analytics Services, requests to the
+// service endpoint are handled by the given function.
+func newTestClient(
+	t *testing.T,
+	service cbrest.Service,
+	handler func(body map[string]any) (int, string),
+) *Client {
+	handlers := make(cbrest.TestHandlers)
+
+	fn := func(writer http.ResponseWriter, request *http.Request) {
+		var body map[string]any
+
+		require.NoError(t, json.Unmarshal(testutil.ReadAll(t, request.Body), &body))
+
+		status, response := handler(body)
+
+		writer.WriteHeader(status)
+
+		_, err := writer.Write([]byte(response))
+		require.NoError(t, err)
+	}
+
+	handlers.Add(http.MethodPost, string(EndpointQueryService), fn)
+	handlers.Add(http.MethodPost, string(EndpointAnalyticsService), fn)
+
+	cluster := cbrest.NewTestCluster(t, cbrest.TestClusterOptions{
+		Nodes:    cbrest.TestNodes{{Services: []cbrest.Service{cbrest.ServiceQuery, cbrest.ServiceAnalytics}}},
+		Handlers: handlers,
+	})
+	t.Cleanup(cluster.Close)
+
+	rest, err := cbrest.NewClient(cbrest.ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         &aprov.Static{},
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(rest.Close)
+
+	client, err := NewClient(ClientOptions{Client: rest, Service: service})
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestNewClientUnsupportedService(t *testing.T) {
+	_, err := NewClient(ClientOptions{Service: cbrest.ServiceData})
+	require.ErrorIs(t, err, ErrUnsupportedService)
+}
+
+func TestClientQuery(t *testing.T) {
+	handler := func(body map[string]any) (int, string) {
+		expected := map[string]any{
+			"statement":         "SELECT * FROM b WHERE a = $1 AND b = $b",
+			"args":              []any{1.0},
+			"$b":                "b",
+			"readonly":          true,
+			"timeout":           "1m0s",
+			"client_context_id": "ctx",
+		}
+
+		require.Equal(t, expected, body)
+
+		return http.StatusOK, `{"results":[{"a":1},{"a":2}],"status":"success"}`
+	}
+
+	client := newTestClient(t, cbrest.ServiceQuery, handler)
+
+	rows, err := client.Query(context.Background(), Options{
+		Statement:            "SELECT * FROM b WHERE a = $1 AND b = $b",
+		PositionalParameters: []any{1},
+		NamedParameters:      map[string]any{"b": "b"},
+		ReadOnly:             true,
+		Timeout:              time.Minute,
+		ClientContextID:      "ctx",
+	})
+	require.NoError(t, err)
+
+	defer rows.Close()
+
+	var count int
+
+	for rows.Next() {
+		count++
+	}
+
+	require.NoError(t, rows.Err())
+	require.Equal(t, 2, count)
+}
+
+func TestClientQueryAnalytics(t *testing.T) {
+	handler := func(_ map[string]any) (int, string) {
+		return http.StatusOK, `{"results":[1],"status":"success"}`
+	}
+
+	client := newTestClient(t, cbrest.ServiceAnalytics, handler)
+
+	rows, err := client.Query(context.Background(), Options{Statement: "SELECT 1"})
+	require.NoError(t, err)
+
+	defer rows.Close()
+
+	require.True(t, rows.Next())
+	require.False(t, rows.Next())
+	require.NoError(t, rows.Err())
+
+	_, err = client.Query(context.Background(), Options{Statement: "SELECT 1", Prepared: true})
+	require.ErrorIs(t, err, ErrPreparedNotSupported)
+}
+
+func TestClientQueryError(t *testing.T) {
+	handler := func(_ map[string]any) (int, string) {
+		return http.StatusBadRequest, `{"errors":[{"code":3000,"msg":"syntax error"}],"status":"fatal"}`
+	}
+
+	client := newTestClient(t, cbrest.ServiceQuery, handler)
+
+	_, err := client.Query(context.Background(), Options{Statement: "SELEC 1"})
+	require.True(t, IsError(err))
+}
+
+func TestClientQueryPrepared(t *testing.T) {
+	var (
+		prepares   int
+		executions int
+	)
+
+	handler := func(body map[string]any) (int, string) {
+		if body["statement"] == "PREPARE SELECT $1" {
+			prepares++
+			return http.StatusOK, `{"results":[{"name":"p1"}],"status":"success"}`
+		}
+
+		require.Equal(t, map[string]any{"prepared": "p1", "args": []any{1.0}}, body)
+
+		executions++
+
+		// The first execution succeeds, then the prepared statement is evicted by the Query Service
+		if executions == 2 {
+			return http.StatusNotFound, `{"errors":[{"code":4040,"msg":"no such prepared statement"}],"status":"fatal"}`
+		}
+
+		return http.StatusOK, `{"results":[1],"status":"success"}`
+	}
+
+	client := newTestClient(t, cbrest.ServiceQuery, handler)
+
+	for range 2 {
+		rows, err := client.Query(context.Background(), Options{
+			Statement:            "SELECT $1",
+			PositionalParameters: []any{1},
+			Prepared:             true,
+		})
+		require.NoError(t, err)
+
+		for rows.Next() {
+		}
+
+		require.NoError(t, rows.Err())
+	}
+
+	require.Equal(t, 2, prepares)
+	require.Equal(t, 3, executions)
+}
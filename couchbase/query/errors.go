@@ -0,0 +1,76 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrUnsupportedService is returned when creating a client for a service other than the Query/Analytics Services.
+	ErrUnsupportedService = errors.New("only the Query and Analytics Services are supported")
+
+	// ErrPreparedNotSupported is returned when attempting to use a prepared statement with the Analytics Service.
+	ErrPreparedNotSupported = errors.New("prepared statements are only supported by the Query Service")
+)
+
+// Message is an error/warning returned by the Query/Analytics Service.
+type Message struct {
+	Code    int    `json:"code"`
+	Message string `json:"msg"`
+}
+
+func (m Message) String() string {
+	return fmt.Sprintf("%d: %s", m.Code, m.Message)
+}
+
+// Error is returned when a statement fails, it contains the errors returned by the service.
+type Error struct {
+	// Status is the status of the request, as reported by the service e.g. "errors" or "fatal".
+	Status string
+
+	// Errors are the errors reported by the service.
+	Errors []Message
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+
+	for _, msg := range e.Errors {
+		msgs = append(msgs, msg.String())
+	}
+
+	return fmt.Sprintf("statement failed with status '%s': %s", e.Status, strings.Join(msgs, ", "))
+}
+
+// HasCode returns a boolean indicating whether any of the errors have one of the given codes.
+func (e *Error) HasCode(codes ...int) bool {
+	for _, msg := range e.Errors {
+		if slices.Contains(codes, msg.Code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsError returns a boolean indicating whether the given error is an 'Error'.
+func IsError(err error) bool {
+	var queryError *Error
+	return err != nil && errors.As(err, &queryError)
+}
+
+// UnexpectedResponseError is returned when the service returns a response which doesn't match the expected format.
+type UnexpectedResponseError struct {
+	status int
+	err    error
+}
+
+func (e *UnexpectedResponseError) Error() string {
+	return fmt.Sprintf("unexpected response with status code %d: %s", e.status, e.err)
+}
+
+func (e *UnexpectedResponseError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,234 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Metrics are the metrics reported by the service once a statement has completed.
+type Metrics struct {
+	ElapsedTime   string `json:"elapsedTime"`
+	ExecutionTime string `json:"executionTime"`
+	ResultCount   uint64 `json:"resultCount"`
+	ResultSize    uint64 `json:"resultSize"`
+	ErrorCount    uint64 `json:"errorCount"`
+	WarningCount  uint64 `json:"warningCount"`
+}
+
+// Metadata is the information returned by the service alongside the rows of a statement.
+type Metadata struct {
+	RequestID       string    `json:"requestID"`
+	ClientContextID string    `json:"clientContextID"`
+	Status          string    `json:"status"`
+	Errors          []Message `json:"errors"`
+	Warnings        []Message `json:"warnings"`
+	Metrics         Metrics   `json:"metrics"`
+}
+
+// Rows is a stream of rows returned by a statement, rows are decoded incrementally as they're read meaning the result
+// set is never buffered in memory.
+//
+// NOTE: Rows must be closed once they're no longer required, this is done automatically once all the rows are read.
+type Rows struct {
+	status  int
+	body    io.ReadCloser
+	decoder *json.Decoder
+
+	// fields are the top-level fields of the response, other than the results, which are used to populate the metadata
+	// once the response has been fully read.
+	fields map[string]json.RawMessage
+
+	// streaming indicates that the decoder is positioned within the results array.
+	streaming bool
+
+	row      json.RawMessage
+	metadata *Metadata
+	err      error
+}
+
+// newRows begins decoding the given response body, reading up until the first row. An error is returned if the
+// statement failed before returning any rows.
+func newRows(status int, body io.ReadCloser) (*Rows, error) {
+	rows := &Rows{
+		status:  status,
+		body:    body,
+		decoder: json.NewDecoder(body),
+		fields:  make(map[string]json.RawMessage),
+	}
+
+	err := rows.expect(json.Delim('{'))
+	if err == nil {
+		err = rows.readFields()
+	}
+
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Next advances to the next row, returning false once there are no more rows or an error occurs; 'Err' should be
+// checked once iteration is complete.
+func (r *Rows) Next() bool {
+	if !r.streaming {
+		return false
+	}
+
+	if r.decoder.More() {
+		r.row = nil
+
+		err := r.decoder.Decode(&r.row)
+		if err != nil {
+			r.fail(fmt.Errorf("failed to decode row: %w", err))
+			return false
+		}
+
+		return true
+	}
+
+	r.row, r.streaming = nil, false
+
+	// Consume the end of the results array, then any remaining metadata
+	err := r.expect(json.Delim(']'))
+	if err == nil {
+		err = r.readFields()
+	}
+
+	if err != nil {
+		r.fail(err)
+	}
+
+	return false
+}
+
+// Row returns the raw value of the current row.
+func (r *Rows) Row() json.RawMessage {
+	return r.row
+}
+
+// Decode unmarshals the current row into the given value.
+func (r *Rows) Decode(v any) error {
+	return json.Unmarshal(r.row, v)
+}
+
+// Err returns the error that occurred during iteration (if any), this includes errors reported by the service after
+// the statement began returning rows.
+func (r *Rows) Err() error {
+	return r.err
+}
+
+// Metadata returns the metadata for the statement, this is only available once all the rows have been read.
+func (r *Rows) Metadata() *Metadata {
+	return r.metadata
+}
+
+// Close releases the resources associated with the rows, rows which haven't been read are discarded.
+func (r *Rows) Close() error {
+	r.streaming = false
+
+	return r.body.Close()
+}
+
+// readFields reads top-level fields until either the start of the results array, or the end of the response.
+func (r *Rows) readFields() error {
+	for r.decoder.More() {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return r.unexpected(fmt.Errorf("failed to read field: %w", err))
+		}
+
+		key, _ := token.(string)
+
+		if key == "results" {
+			return r.readResults()
+		}
+
+		var value json.RawMessage
+
+		err = r.decoder.Decode(&value)
+		if err != nil {
+			return r.unexpected(fmt.Errorf("failed to decode field '%s': %w", key, err))
+		}
+
+		r.fields[key] = value
+	}
+
+	err := r.expect(json.Delim('}'))
+	if err != nil {
+		return err
+	}
+
+	return r.complete()
+}
+
+// readResults positions the decoder within the results array, the results may also be <nil>.
+func (r *Rows) readResults() error {
+	token, err := r.decoder.Token()
+	if err != nil {
+		return r.unexpected(fmt.Errorf("failed to read results: %w", err))
+	}
+
+	switch token {
+	case nil:
+		return r.readFields()
+	case json.Delim('['):
+		r.streaming = true
+		return nil
+	}
+
+	return r.unexpected(fmt.Errorf("unexpected token '%v' for results", token))
+}
+
+// complete populates the metadata once the response has been fully read, returning an error if the statement failed.
+func (r *Rows) complete() error {
+	defer r.Close()
+
+	encoded, err := json.Marshal(r.fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	err = json.Unmarshal(encoded, &r.metadata)
+	if err != nil {
+		return r.unexpected(fmt.Errorf("failed to decode metadata: %w", err))
+	}
+
+	if len(r.metadata.Errors) != 0 {
+		return &Error{Status: r.metadata.Status, Errors: r.metadata.Errors}
+	}
+
+	if r.status != http.StatusOK {
+		return r.unexpected(fmt.Errorf("no errors reported"))
+	}
+
+	return nil
+}
+
+// expect reads the next token, returning an error if it's not the given delimiter.
+func (r *Rows) expect(delim json.Delim) error {
+	token, err := r.decoder.Token()
+	if err != nil {
+		return r.unexpected(fmt.Errorf("failed to read token: %w", err))
+	}
+
+	if token != delim {
+		return r.unexpected(fmt.Errorf("expected '%s' but got '%v'", delim, token))
+	}
+
+	return nil
+}
+
+// unexpected wraps the given error to indicate that the response was not in the expected format.
+func (r *Rows) unexpected(err error) error {
+	return &UnexpectedResponseError{status: r.status, err: err}
+}
+
+// fail stops iteration, recording the given error.
+func (r *Rows) fail(err error) {
+	r.err = err
+	r.Close()
+}
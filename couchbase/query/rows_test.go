@@ -0,0 +1,129 @@
+package query
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRows(t *testing.T, status int, body string) (*Rows, error) {
+	t.Helper()
+
+	return newRows(status, io.NopCloser(strings.NewReader(body)))
+}
+
+func TestRows(t *testing.T) {
+	rows, err := newTestRows(t, http.StatusOK, `{
+		"requestID": "id",
+		"signature": {"*": "*"},
+		"results": [{"a":1}, {"a":2}],
+		"status": "success",
+		"warnings": [{"code": 1, "msg": "warning"}],
+		"metrics": {"elapsedTime": "1ms", "resultCount": 2}
+	}`)
+	require.NoError(t, err)
+
+	var values []int
+
+	for rows.Next() {
+		var row struct {
+			A int `json:"a"`
+		}
+
+		require.NoError(t, rows.Decode(&row))
+
+		values = append(values, row.A)
+	}
+
+	require.NoError(t, rows.Err())
+	require.Equal(t, []int{1, 2}, values)
+
+	expected := &Metadata{
+		RequestID: "id",
+		Status:    "success",
+		Warnings:  []Message{{Code: 1, Message: "warning"}},
+		Metrics:   Metrics{ElapsedTime: "1ms", ResultCount: 2},
+	}
+
+	require.Equal(t, expected, rows.Metadata())
+}
+
+func TestRowsNoResults(t *testing.T) {
+	rows, err := newTestRows(t, http.StatusOK, `{"requestID":"id","results":[],"status":"success"}`)
+	require.NoError(t, err)
+
+	require.False(t, rows.Next())
+	require.NoError(t, rows.Err())
+	require.Equal(t, "success", rows.Metadata().Status)
+}
+
+func TestRowsNullResults(t *testing.T) {
+	rows, err := newTestRows(t, http.StatusOK, `{"results":null,"status":"success"}`)
+	require.NoError(t, err)
+
+	require.False(t, rows.Next())
+	require.NoError(t, rows.Err())
+	require.Equal(t, "success", rows.Metadata().Status)
+}
+
+func TestRowsErrorBeforeResults(t *testing.T) {
+	_, err := newTestRows(t, http.StatusBadRequest, `{
+		"requestID": "id",
+		"errors": [{"code": 3000, "msg": "syntax error"}],
+		"status": "fatal"
+	}`)
+
+	var queryError *Error
+
+	require.ErrorAs(t, err, &queryError)
+	require.Equal(t, "fatal", queryError.Status)
+	require.True(t, queryError.HasCode(3000))
+	require.False(t, queryError.HasCode(4040))
+}
+
+func TestRowsErrorAfterResults(t *testing.T) {
+	rows, err := newTestRows(t, http.StatusOK, `{
+		"results": [1],
+		"errors": [{"code": 1080, "msg": "timeout"}],
+		"status": "timeout"
+	}`)
+	require.NoError(t, err)
+
+	require.True(t, rows.Next())
+	require.Equal(t, json.RawMessage(`1`), rows.Row())
+	require.False(t, rows.Next())
+
+	var queryError *Error
+
+	require.ErrorAs(t, rows.Err(), &queryError)
+	require.True(t, queryError.HasCode(1080))
+}
+
+func TestRowsUnexpectedStatusWithoutErrors(t *testing.T) {
+	_, err := newTestRows(t, http.StatusInternalServerError, `{"status":"fatal"}`)
+
+	var unexpected *UnexpectedResponseError
+
+	require.ErrorAs(t, err, &unexpected)
+}
+
+func TestRowsInvalidResponse(t *testing.T) {
+	_, err := newTestRows(t, http.StatusOK, `not json`)
+
+	var unexpected *UnexpectedResponseError
+
+	require.ErrorAs(t, err, &unexpected)
+}
+
+func TestRowsTruncated(t *testing.T) {
+	rows, err := newTestRows(t, http.StatusOK, `{"results":[{"a":1},{"a":`)
+	require.NoError(t, err)
+
+	require.True(t, rows.Next())
+	require.False(t, rows.Next())
+	require.Error(t, rows.Err())
+}
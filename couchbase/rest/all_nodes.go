@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// allNodesConcurrency is the maximum number of nodes which 'ExecuteOnAllNodes' dispatches requests to concurrently.
+const allNodesConcurrency = 8
+
+// NodeResult is the outcome of executing a request against a single node, using 'ExecuteOnAllNodes'.
+type NodeResult struct {
+	// Host is the fully qualified address of the node the request was dispatched to.
+	Host string
+
+	// Response is the response returned by the node, this will be <nil> if the request failed.
+	Response *Response
+
+	// Err is the reason the request failed, if it failed.
+	Err error
+}
+
+// ExecuteOnAllNodes executes the given request against every node running the given service concurrently, returning
+// the result for each node in the same order as 'GetAllServiceHosts'. This may be used to get/update node-level
+// settings or gather stats from each node.
+//
+// When the request fails on any node a 'NodesFailedError' is returned alongside the results, so that the results from
+// the nodes where the request succeeded may still be used.
+//
+// NOTE: The 'Host' and 'Service' attributes of the given request are overridden.
+func (c *Client) ExecuteOnAllNodes(ctx context.Context, request *Request, service Service) ([]NodeResult, error) {
+	hosts, err := c.GetAllServiceHosts(service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hosts for service '%s': %w", service, err)
+	}
+
+	var (
+		results = make([]NodeResult, len(hosts))
+		group   = errgroup.Group{}
+	)
+
+	group.SetLimit(allNodesConcurrency)
+
+	for index, host := range hosts {
+		group.Go(func() error {
+			req := *request
+			req.Host, req.Service = host, service
+
+			response, err := c.ExecuteWithContext(ctx, &req)
+
+			results[index] = NodeResult{Host: host, Response: response, Err: err}
+
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+
+	failed := make([]NodeResult, 0)
+
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	if len(failed) != 0 {
+		return results, &NodesFailedError{failed: failed, total: len(results)}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newAllNodesTestCluster(t *testing.T, handler http.HandlerFunc) *TestCluster {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", handler)
+
+	return NewTestCluster(t, TestClusterOptions{
+		Nodes: TestNodes{
+			{Services: []Service{ServiceData}},
+			{Services: []Service{ServiceManagement}},
+			{Services: []Service{ServiceData}},
+		},
+		Handlers: handlers,
+	})
+}
+
+func TestClientExecuteOnAllNodes(t *testing.T) {
+	cluster := newAllNodesTestCluster(t, NewTestHandler(t, http.StatusOK, []byte("body")))
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+	}
+
+	hosts, err := client.GetAllServiceHosts(ServiceData)
+	require.NoError(t, err)
+
+	results, err := client.ExecuteOnAllNodes(context.Background(), request, ServiceData)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	for index, result := range results {
+		require.Equal(t, hosts[index], result.Host)
+		require.NoError(t, result.Err)
+		require.Equal(t, &Response{StatusCode: http.StatusOK, Body: []byte("body")}, result.Response)
+	}
+
+	// The given request shouldn't be modified
+	require.Empty(t, request.Host)
+}
+
+func TestClientExecuteOnAllNodesPartialFailure(t *testing.T) {
+	var (
+		calls   atomic.Int64
+		success = NewTestHandler(t, http.StatusOK, []byte("body"))
+		failure = NewTestHandler(t, http.StatusBadRequest, []byte("bad request"))
+	)
+
+	cluster := newAllNodesTestCluster(t, func(writer http.ResponseWriter, request *http.Request) {
+		if calls.Add(1) == 1 {
+			failure(writer, request)
+		} else {
+			success(writer, request)
+		}
+	})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+	}
+
+	results, err := client.ExecuteOnAllNodes(context.Background(), request, ServiceManagement)
+	require.Len(t, results, 3)
+
+	var failed *NodesFailedError
+
+	require.ErrorAs(t, err, &failed)
+	require.Len(t, failed.Hosts(), 1)
+
+	var unexpected *UnexpectedStatusCodeError
+
+	require.ErrorAs(t, err, &unexpected)
+
+	var succeeded int
+
+	for _, result := range results {
+		if result.Err == nil {
+			succeeded++
+		}
+	}
+
+	require.Equal(t, 2, succeeded)
+}
+
+func TestClientExecuteOnAllNodesServiceNotAvailable(t *testing.T) {
+	cluster := newAllNodesTestCluster(t, NewTestHandler(t, http.StatusOK, nil))
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.ExecuteOnAllNodes(context.Background(), &Request{Endpoint: "/test"}, ServiceAnalytics)
+
+	var notAvailable *ServiceNotAvailableError
+
+	require.ErrorAs(t, err, &notAvailable)
+}
@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// AuditRecord describes a mutating (non-GET) request performed by the client.
+type AuditRecord struct {
+	// Timestamp is the time at which the request was started.
+	Timestamp time.Time
+
+	// User is the user the request was authenticated as, empty if the request wasn't sent to a node.
+	User string
+
+	// Method is the HTTP method used by the request e.g. 'POST'.
+	Method Method
+
+	// Host is the host the request was last dispatched to, empty if the request wasn't sent to a node.
+	Host string
+
+	// Endpoint is the endpoint the request was dispatched to.
+	Endpoint Endpoint
+
+	// StatusCode is the status code of the final response, zero if no response was received.
+	StatusCode int
+
+	// Duration is the time taken to perform the request, including any retries.
+	Duration time.Duration
+
+	// BodyDigest is the hex encoded SHA256 digest of the (uncompressed) request body, empty if the request had no body.
+	BodyDigest string
+
+	// Error is the error which caused the request to fail, if any.
+	Error error
+}
+
+// AuditSink receives a record for every mutating request performed by the client, this may be used by embedding tools
+// to satisfy change-audit requirements.
+//
+// NOTE: Records are delivered synchronously once the request completes, implementations should therefore be quick to
+// return and must be safe for concurrent use.
+type AuditSink interface {
+	Audit(record AuditRecord)
+}
+
+// audit delivers an audit record for the given request to the audit sink, if one has been provided and the request is
+// a mutating request.
+func (c *Client) audit(request *Request, start time.Time, resp *http.Response, err error) {
+	if c.auditSink == nil || request.Method == http.MethodGet {
+		return
+	}
+
+	record := AuditRecord{
+		Timestamp: start,
+		Method:    request.Method,
+		Endpoint:  request.Endpoint,
+		Duration:  c.clock.Now().Sub(start),
+		Error:     err,
+	}
+
+	if len(request.Body) != 0 {
+		digest := sha256.Sum256(request.Body)
+		record.BodyDigest = hex.EncodeToString(digest[:])
+	}
+
+	if resp != nil {
+		record.StatusCode = resp.StatusCode
+	}
+
+	if resp != nil && resp.Request != nil {
+		record.Host = resp.Request.URL.Scheme + "://" + resp.Request.URL.Host
+
+		// The credentials are fetched again, rather than reading them from the request, because requests may not be
+		// authenticated using HTTP basic auth e.g. when using a signer.
+		credentials, credErr := c.authProvider.provider.GetCredentials(record.Host)
+		if credErr == nil {
+			record.User = credentials.Username
+		}
+	}
+
+	c.auditSink.Audit(record)
+}
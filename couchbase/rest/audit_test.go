@@ -0,0 +1,150 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testAuditSink struct {
+	lock    sync.Mutex
+	records []AuditRecord
+}
+
+func (t *testAuditSink) Audit(record AuditRecord) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.records = append(t.records, record)
+}
+
+func newAuditTestClient(t *testing.T, cluster *TestCluster, sink AuditSink) *Client {
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	client.auditSink = sink
+
+	return client
+}
+
+func TestClientAuditMutatingRequest(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodPost, "/test", NewTestHandler(t, http.StatusOK, nil))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	sink := &testAuditSink{}
+
+	client := newAuditTestClient(t, cluster, sink)
+	defer client.Close()
+
+	_, err := client.Execute(&Request{
+		Body:               []byte("key=value"),
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, sink.records, 1)
+
+	record := sink.records[0]
+
+	digest := sha256.Sum256([]byte("key=value"))
+
+	require.False(t, record.Timestamp.IsZero())
+	require.Equal(t, "username", record.User)
+	require.Equal(t, Method(http.MethodPost), record.Method)
+	require.Equal(t, cluster.URL(), record.Host)
+	require.Equal(t, Endpoint("/test"), record.Endpoint)
+	require.Equal(t, http.StatusOK, record.StatusCode)
+	require.Positive(t, record.Duration)
+	require.Equal(t, hex.EncodeToString(digest[:]), record.BodyDigest)
+	require.NoError(t, record.Error)
+}
+
+func TestClientAuditFailedRequest(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodDelete, "/test", NewTestHandler(t, http.StatusNotFound, nil))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	sink := &testAuditSink{}
+
+	client := newAuditTestClient(t, cluster, sink)
+	defer client.Close()
+
+	_, err := client.Execute(&Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodDelete,
+		Service:            ServiceManagement,
+	})
+	require.Error(t, err)
+
+	require.Len(t, sink.records, 1)
+	require.Equal(t, http.StatusNotFound, sink.records[0].StatusCode)
+	require.Empty(t, sink.records[0].BodyDigest)
+}
+
+func TestClientAuditIgnoresGetRequests(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusOK, nil))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	sink := &testAuditSink{}
+
+	client := newAuditTestClient(t, cluster, sink)
+	defer client.Close()
+
+	_, err := client.Execute(&Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+
+	require.Empty(t, sink.records)
+}
+
+func TestClientAuditUsesClock(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodDelete, "/test", NewTestHandler(t, http.StatusOK, nil))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	sink := &testAuditSink{}
+
+	client := newAuditTestClient(t, cluster, sink)
+	defer client.Close()
+
+	now := time.Unix(1700000000, 0)
+
+	client.clock = &testClock{now: now}
+
+	_, err := client.Execute(&Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodDelete,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, sink.records, 1)
+	require.Equal(t, now, sink.records[0].Timestamp)
+	require.Zero(t, sink.records[0].Duration)
+}
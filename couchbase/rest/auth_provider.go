@@ -3,6 +3,7 @@ package rest
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
 
 	aprov "github.com/couchbase/tools-common/auth/v2/provider"
@@ -19,23 +20,34 @@ type AuthProvider struct {
 
 	provider aprov.Provider
 
-	manager *ClusterConfigManager
+	manager ConfigManager
 	lock    sync.RWMutex
+
+	health *nodeHealth
 }
 
 // AuthProviderOptions encapsulates the options for creating a new REST AuthProvider.
 type AuthProviderOptions struct {
 	resolved *connstr.ResolvedConnectionString
 	provider aprov.Provider
+	manager  ConfigManager
 	logger   *slog.Logger
+	clock    Clock
+	health   *nodeHealth
 }
 
 // NewAuthProvider creates a new 'AuthProvider' using the provided credentials.
 func NewAuthProvider(options AuthProviderOptions) *AuthProvider {
+	manager := options.manager
+	if manager == nil {
+		manager = newClusterConfigManager(options.logger, clockOrDefault(options.clock))
+	}
+
 	return &AuthProvider{
 		resolved: options.resolved,
 		provider: options.provider,
-		manager:  NewClusterConfigManager(options.logger),
+		manager:  manager,
+		health:   options.health,
 	}
 }
 
@@ -45,23 +57,46 @@ func NewAuthProvider(options AuthProviderOptions) *AuthProvider {
 // Supplying an offest will (where possible) "shift" the node index so that we dispatch the request to a different node;
 // this may help in certain cases where a node is currently being removed from the cluster.
 //
+// Where health aware routing is enabled, nodes which are degraded (i.e. responding slowly or failing requests) are only
+// chosen once the healthy nodes have been exhausted.
+//
 // NOTE: The returned string is a fully qualified hostname with scheme and port.
 func (a *AuthProvider) GetServiceHost(service Service, offset int) (string, error) {
+	return a.getServiceHost(service, offset, routing{})
+}
+
+// getServiceHost is similar to 'GetServiceHost', however, nodes which are in maintenance are excluded (as are nodes
+// which are unreachable, where possible); a 'NodeInMaintenanceError' is returned if all the nodes running the service
+// are in maintenance.
+func (a *AuthProvider) getServiceHost(service Service, offset int, route routing) (string, error) {
 	hosts, err := a.GetAllServiceHosts(service)
 	if err != nil {
 		return "", err // Purposefully not wrapped
 	}
 
+	available := route.filter(hosts)
+	if len(available) == 0 {
+		return "", &NodeInMaintenanceError{service: service, nodes: hosts}
+	}
+
 	// If the bootstrap host is running the required service, it will be placed at the beginning of the slice by the
 	// 'GetAllServiceHosts' function; this means we prioritize sending requests to the node which we bootstrapped
-	// against.
-	return hosts[offset%len(hosts)], nil
+	// against (unless it's degraded).
+	available = a.health.order(available)
+
+	return available[offset%len(available)], nil
 }
 
 // GetAllServiceHosts gets all the possible hosts for a given service type.
 //
 // NOTE: The returned strings are fully qualified hostnames with schemes and ports.
 func (a *AuthProvider) GetAllServiceHosts(service Service) ([]string, error) {
+	return a.getAllServiceHosts(service, nil)
+}
+
+// getAllServiceHosts is similar to 'GetAllServiceHosts', however, only the nodes accepted by the optional 'include'
+// function are returned.
+func (a *AuthProvider) getAllServiceHosts(service Service, include func(node *Node) bool) ([]string, error) {
 	a.lock.RLock()
 	defer a.lock.RUnlock()
 
@@ -76,6 +111,10 @@ func (a *AuthProvider) GetAllServiceHosts(service Service) ([]string, error) {
 	hosts := make([]string, 0)
 
 	for _, node := range config.Nodes {
+		if include != nil && !include(node) {
+			continue
+		}
+
 		hostname, bootstrap := node.GetQualifiedHostname(service, a.resolved.UseSSL, a.useAltAddr)
 		if hostname == "" {
 			continue
@@ -96,6 +135,37 @@ func (a *AuthProvider) GetAllServiceHosts(service Service) ([]string, error) {
 	return hosts, nil
 }
 
+// GetNodeServiceHost gets the host for the given service, running on the node with the given uuid (or otp node name).
+//
+// NOTE: The returned string is a fully qualified hostname with scheme and port.
+func (a *AuthProvider) GetNodeServiceHost(uuid string, service Service) (string, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	config := a.manager.GetClusterConfig()
+
+	// We've not bootstrapped the client yet, this shouldn't happen in the normal case for the REST client since we
+	// bootstrap upon creation.
+	if config == nil {
+		return "", ErrNotBootstrapped
+	}
+
+	for _, node := range config.Nodes {
+		if node.UUID != uuid && node.OTPNode != uuid {
+			continue
+		}
+
+		hostname, _ := node.GetQualifiedHostname(service, a.resolved.UseSSL, a.useAltAddr)
+		if hostname == "" {
+			return "", &ServiceNotAvailableError{service: service}
+		}
+
+		return hostname, nil
+	}
+
+	return "", &NodeNotFoundError{uuid: uuid}
+}
+
 // SetClusterConfig updates the auth providers cluster config in a thread safe fashion. Returns an error if the provided
 // config is older than the current config; this ensures that we don't use the config from a node which have been
 // removed from the cluster.
@@ -128,6 +198,9 @@ func (a *AuthProvider) bootstrapHostFunc() func() string {
 	return func() string {
 		defer func() { index++ }()
 
+		a.lock.RLock()
+		defer a.lock.RUnlock()
+
 		if index >= len(a.resolved.Addresses) {
 			return ""
 		}
@@ -142,6 +215,26 @@ func (a *AuthProvider) bootstrapHostFunc() func() string {
 	}
 }
 
+// mergeResolvedAddresses adds any of the given addresses which aren't already known to the resolved connection string,
+// returning the number of addresses which were added.
+func (a *AuthProvider) mergeResolvedAddresses(addresses []connstr.Address) int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	var added int
+
+	for _, address := range addresses {
+		if slices.Contains(a.resolved.Addresses, address) {
+			continue
+		}
+
+		a.resolved.Addresses = append(a.resolved.Addresses, address)
+		added++
+	}
+
+	return added
+}
+
 // shouldUseAltAddr returns a boolean indicating whether we should send all future requests using alternative addresses.
 func (a *AuthProvider) shouldUseAltAddr(host string, nodes Nodes) (bool, error) {
 	network := a.resolved.Params.Get("network")
@@ -169,10 +262,14 @@ func (a *AuthProvider) shouldUseAltAddr(host string, nodes Nodes) (bool, error)
 
 // updateResolvedAddress updates the resolved connection string for AuthProvider to contain all node addresses.
 func (a *AuthProvider) UpdateResolvedAddress() {
-	port := a.resolved.Addresses[0].Port
-	addresses := make([]connstr.Address, 0, len(a.manager.config.Nodes))
+	var (
+		port  = a.resolved.Addresses[0].Port
+		nodes = a.manager.GetClusterConfig().Nodes
+	)
+
+	addresses := make([]connstr.Address, 0, len(nodes))
 
-	for _, node := range a.manager.config.Nodes {
+	for _, node := range nodes {
 		addresses = append(addresses, connstr.Address{
 			Host: node.Hostname,
 			Port: port,
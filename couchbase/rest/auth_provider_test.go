@@ -22,14 +22,14 @@ func TestNewAuthProvider(t *testing.T) {
 	)
 
 	// Don't compare the time attribute from the config manager
-	actual.manager.last = nil
-	actual.manager.signal = nil
-	actual.manager.cond = nil
+	actual.manager.(*ClusterConfigManager).last = nil
+	actual.manager.(*ClusterConfigManager).signal = nil
+	actual.manager.(*ClusterConfigManager).cond = nil
 
 	expected := &AuthProvider{
 		resolved: &connstr.ResolvedConnectionString{},
 		provider: provider,
-		manager:  &ClusterConfigManager{maxAge: DefaultCCMaxAge},
+		manager:  &ClusterConfigManager{maxAge: DefaultCCMaxAge, clock: systemClock{}},
 	}
 
 	require.Equal(t, expected, actual)
@@ -281,6 +281,93 @@ func TestAuthProviderGetAllServiceHosts(t *testing.T) {
 			service:  ServiceManagement,
 			expected: []string{"https://host1:18091"},
 		},
+		{
+			name: "MultiNodeMixedServicesBackup",
+			provider: &AuthProvider{
+				resolved: &connstr.ResolvedConnectionString{
+					Addresses: []connstr.Address{{Host: "localhost", Port: 8091}},
+				},
+				manager: &ClusterConfigManager{
+					config: &ClusterConfig{
+						Nodes: Nodes{
+							{Hostname: "host1", Services: testServices}, {Hostname: "host2", Services: kvOnlyService},
+						},
+					},
+				},
+			},
+			service:  ServiceBackup,
+			expected: []string{"http://host1:7100"},
+		},
+		{
+			name: "MultiNodeMixedServicesBackupSSL",
+			provider: &AuthProvider{
+				resolved: &connstr.ResolvedConnectionString{
+					Addresses: []connstr.Address{{Host: "localhost", Port: 8091}},
+					UseSSL:    true,
+				},
+				manager: &ClusterConfigManager{
+					config: &ClusterConfig{
+						Nodes: Nodes{
+							{Hostname: "host1", Services: testServices}, {Hostname: "host2", Services: kvOnlyService},
+						},
+					},
+				},
+			},
+			service:  ServiceBackup,
+			expected: []string{"https://host1:17100"},
+		},
+		{
+			name: "MultiNodeMixedServicesEventing",
+			provider: &AuthProvider{
+				resolved: &connstr.ResolvedConnectionString{
+					Addresses: []connstr.Address{{Host: "localhost", Port: 8091}},
+				},
+				manager: &ClusterConfigManager{
+					config: &ClusterConfig{
+						Nodes: Nodes{
+							{Hostname: "host1", Services: testServices}, {Hostname: "host2", Services: kvOnlyService},
+						},
+					},
+				},
+			},
+			service:  ServiceEventing,
+			expected: []string{"http://host1:8096"},
+		},
+		{
+			name: "MultiNodeMixedServicesGSI",
+			provider: &AuthProvider{
+				resolved: &connstr.ResolvedConnectionString{
+					Addresses: []connstr.Address{{Host: "localhost", Port: 8091}},
+				},
+				manager: &ClusterConfigManager{
+					config: &ClusterConfig{
+						Nodes: Nodes{
+							{Hostname: "host1", Services: testServices}, {Hostname: "host2", Services: kvOnlyService},
+						},
+					},
+				},
+			},
+			service:  ServiceGSI,
+			expected: []string{"http://host1:9102"},
+		},
+		{
+			name: "MultiNodeMixedServicesGSISSL",
+			provider: &AuthProvider{
+				resolved: &connstr.ResolvedConnectionString{
+					Addresses: []connstr.Address{{Host: "localhost", Port: 8091}},
+					UseSSL:    true,
+				},
+				manager: &ClusterConfigManager{
+					config: &ClusterConfig{
+						Nodes: Nodes{
+							{Hostname: "host1", Services: testServices}, {Hostname: "host2", Services: kvOnlyService},
+						},
+					},
+				},
+			},
+			service:  ServiceGSI,
+			expected: []string{"https://host1:19102"},
+		},
 		{
 			name: "MultiNodeAllServicesAltAddr",
 			provider: &AuthProvider{
@@ -325,6 +412,54 @@ func TestAuthProviderGetAllServiceHosts(t *testing.T) {
 	}
 }
 
+func TestAuthProviderGetNodeServiceHost(t *testing.T) {
+	services := &Services{
+		Management:    8091,
+		ManagementSSL: 18091,
+	}
+
+	provider := &AuthProvider{
+		resolved: &connstr.ResolvedConnectionString{
+			Addresses: []connstr.Address{{Host: "host1", Port: 8091}},
+		},
+		manager: &ClusterConfigManager{
+			config: &ClusterConfig{
+				Nodes: Nodes{
+					{UUID: "uuid1", OTPNode: "ns_1@host1", Hostname: "host1", Services: testServices},
+					{UUID: "uuid2", OTPNode: "ns_1@host2", Hostname: "host2", Services: services},
+				},
+			},
+		},
+	}
+
+	type test struct {
+		name     string
+		uuid     string
+		service  Service
+		expected string
+	}
+
+	tests := []*test{
+		{name: "UUID", uuid: "uuid2", service: ServiceManagement, expected: "http://host2:8091"},
+		{name: "OTPNode", uuid: "ns_1@host2", service: ServiceManagement, expected: "http://host2:8091"},
+		{name: "Service", uuid: "uuid1", service: ServiceQuery, expected: "http://host1:8093"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := provider.GetNodeServiceHost(test.uuid, test.service)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+
+	_, err := provider.GetNodeServiceHost("uuid3", ServiceManagement)
+	require.True(t, IsNodeNotFound(err))
+
+	_, err = provider.GetNodeServiceHost("uuid2", ServiceQuery)
+	require.True(t, IsServiceNotAvailable(err))
+}
+
 func TestAuthProviderGetAllServiceHostsServiceNotAvailable(t *testing.T) {
 	services := &Services{
 		Management:    8091,
@@ -499,6 +634,30 @@ func TestAuthProviderSuccessiveBootstrapping(t *testing.T) {
 	}
 }
 
+func TestAuthProviderMergeResolvedAddresses(t *testing.T) {
+	provider := &AuthProvider{
+		resolved: &connstr.ResolvedConnectionString{
+			Addresses: []connstr.Address{{Host: "hostname1", Port: 8091}},
+		},
+	}
+
+	added := provider.mergeResolvedAddresses([]connstr.Address{
+		{Host: "hostname1", Port: 8091},
+		{Host: "hostname2", Port: 8091},
+		{Host: "hostname1", Port: 18091},
+	})
+
+	require.Equal(t, 2, added)
+
+	require.Equal(t, []connstr.Address{
+		{Host: "hostname1", Port: 8091},
+		{Host: "hostname2", Port: 8091},
+		{Host: "hostname1", Port: 18091},
+	}, provider.resolved.Addresses)
+
+	require.Zero(t, provider.mergeResolvedAddresses([]connstr.Address{{Host: "hostname2", Port: 8091}}))
+}
+
 func TestAuthProviderShouldUseAltAddr(t *testing.T) {
 	type test struct {
 		name     string
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/couchbase/tools-common/utils/v3/retry"
+)
+
+// BootstrapRetryOptions encapsulates the options available when enabling bootstrap retries, where the full list of
+// hosts from the connection string is retried with backoff until the client bootstraps, or the timeout elapses. This
+// may be used by tools which are started before the cluster has finished coming up e.g. in a Kubernetes init container.
+type BootstrapRetryOptions struct {
+	// Timeout is the overall time allowed for bootstrapping. Defaults to 'DefaultBootstrapTimeout'.
+	//
+	// NOTE: The timeout is checked between attempts, so an attempt in progress isn't interrupted.
+	Timeout time.Duration
+
+	// MinDelay is the delay before the first retry, which grows exponentially with each subsequent retry. Defaults to
+	// 'DefaultBootstrapMinDelay'.
+	MinDelay time.Duration
+
+	// MaxDelay is the maximum delay between retries. Defaults to 'DefaultBootstrapMaxDelay'.
+	MaxDelay time.Duration
+}
+
+// defaults fills any missing attributes to a sane default.
+func (b *BootstrapRetryOptions) defaults() {
+	if b.Timeout <= 0 {
+		b.Timeout = DefaultBootstrapTimeout
+	}
+
+	if b.MinDelay <= 0 {
+		b.MinDelay = DefaultBootstrapMinDelay
+	}
+
+	if b.MaxDelay <= 0 {
+		b.MaxDelay = DefaultBootstrapMaxDelay
+	}
+
+	b.MaxDelay = max(b.MaxDelay, b.MinDelay)
+}
+
+// bootstrapWithRetries bootstraps the client, retrying with backoff according to the given options; the client is
+// bootstrapped once, as usual, when no options are provided.
+func (c *Client) bootstrapWithRetries(options *BootstrapRetryOptions) error {
+	if options == nil {
+		return c.bootstrap()
+	}
+
+	opts := *options
+	opts.defaults()
+
+	var (
+		// NOTE: The retryer is only used to calculate the backoff, since retries are bounded by the timeout rather than
+		// a number of attempts.
+		backoff = retry.NewRetryer(retry.RetryerOptions[struct{}]{
+			Algorithm: retry.AlgorithmExponential,
+			MinDelay:  opts.MinDelay,
+			MaxDelay:  opts.MaxDelay,
+		})
+		deadline = c.clock.Now().Add(opts.Timeout)
+	)
+
+	for attempt := 1; ; attempt++ {
+		err := c.bootstrap()
+		if err == nil || !shouldRetryBootstrap(err) {
+			return err
+		}
+
+		delay := backoff.Duration(attempt - 1)
+
+		if !c.clock.Now().Add(delay).Before(deadline) {
+			return fmt.Errorf("bootstrap timeout of %s exceeded after %d attempt(s): %w", opts.Timeout, attempt, err)
+		}
+
+		c.logger.Warn(
+			"failed to bootstrap client using any host, will retry",
+			"attempt", attempt,
+			"delay", delay,
+			"error", err,
+		)
+
+		<-c.clock.After(delay)
+	}
+}
+
+// shouldRetryBootstrap returns a boolean indicating whether bootstrapping should continue after the given error, for
+// security reasons x509 errors are never retried.
+func shouldRetryBootstrap(err error) bool {
+	var (
+		errUnknownAuthority *UnknownAuthorityError
+		errUnknownX509Error *UnknownX509Error
+	)
+
+	return !errors.As(err, &errUnknownAuthority) && !errors.As(err, &errUnknownX509Error)
+}
@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrapRetryOptionsDefaults(t *testing.T) {
+	var options BootstrapRetryOptions
+
+	options.defaults()
+
+	expected := BootstrapRetryOptions{
+		Timeout:  DefaultBootstrapTimeout,
+		MinDelay: DefaultBootstrapMinDelay,
+		MaxDelay: DefaultBootstrapMaxDelay,
+	}
+
+	require.Equal(t, expected, options)
+}
+
+func TestShouldRetryBootstrap(t *testing.T) {
+	require.True(t, shouldRetryBootstrap(&BootstrapFailureError{}))
+	require.False(t, shouldRetryBootstrap(&UnknownAuthorityError{}))
+	require.False(t, shouldRetryBootstrap(&UnknownX509Error{}))
+}
+
+func TestNewClientBootstrapRetry(t *testing.T) {
+	var (
+		cluster  *TestCluster
+		calls    atomic.Int64
+		handlers = make(TestHandlers)
+		clock    = &testClock{now: time.Now()}
+	)
+
+	// The cluster isn't ready for the first couple of passes over the host list
+	handlers.Add(http.MethodGet, string(EndpointNodesServices), func(writer http.ResponseWriter, request *http.Request) {
+		if calls.Add(1) <= 2 {
+			writer.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		cluster.NodeServices(writer, request)
+	})
+
+	cluster = NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		Clock:            clock,
+		BootstrapRetry:   &BootstrapRetryOptions{MinDelay: time.Second, MaxDelay: time.Minute},
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second}, clock.Waits())
+}
+
+func TestNewClientBootstrapRetryTimeout(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, string(EndpointNodesServices), NewTestHandler(t, http.StatusForbidden, nil))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	_, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		BootstrapRetry: &BootstrapRetryOptions{
+			Timeout:  50 * time.Millisecond,
+			MinDelay: 5 * time.Millisecond,
+			MaxDelay: 5 * time.Millisecond,
+		},
+	})
+
+	var bootstrapFailure *BootstrapFailureError
+
+	require.ErrorAs(t, err, &bootstrapFailure)
+	require.NotNil(t, bootstrapFailure.ErrAuthorization)
+	require.ErrorContains(t, err, "bootstrap timeout of 50ms exceeded")
+}
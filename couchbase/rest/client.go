@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"sort"
@@ -39,6 +40,9 @@ import (
 // used in cases where private hostnames need remapping into public hostnames or vice versa.
 type HostnameTransform func(string) string
 
+// DialContextFunc is used to establish network connections, it has the same signature as 'net.Dialer.DialContext'.
+type DialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
 // ClientOptions encapsulates the options for creating a new REST client.
 type ClientOptions struct {
 	ConnectionString string
@@ -65,7 +69,144 @@ type ClientOptions struct {
 	ReqResLogLevel slog.Level
 
 	// Logger is the passed Logger struct that implements the Log method for logger the user wants to use.
+	//
+	// NOTE: Credentials are redacted from anything logged by the client, see 'Redaction'.
 	Logger *slog.Logger
+
+	// Redaction allows redacting additional fields from the URLs, headers and bodies logged by the client, in addition
+	// to credentials which are always redacted.
+	Redaction *RedactionOptions
+
+	// MaxConcurrentRequests limits the number of requests which may be in-flight at once, requests which would exceed
+	// the limit block until capacity becomes available. A request is considered in-flight until its response body has
+	// been closed. A zero value means there's no limit.
+	//
+	// NOTE: Requests performed internally by the client (e.g. cluster config polling) are not limited.
+	MaxConcurrentRequests int
+
+	// SignerForHost is called for each request with the host it's being dispatched to, allowing requests to be
+	// authenticated using a scheme other than HTTP basic auth (e.g. 'SigV4Signer'). When omitted, or when a <nil>
+	// signer is returned, HTTP basic auth is used.
+	SignerForHost SignerForHost
+
+	// DialContext overrides the function used to establish connections to the cluster, this may be used to tunnel
+	// connections (e.g. via a SOCKS proxy) or to shape traffic in tests.
+	//
+	// NOTE: When provided, 'Resolver' and the dialer timeouts are ignored; the function is responsible for both.
+	DialContext DialContextFunc
+
+	// Resolver is the DNS resolver used when establishing connections to the cluster, when omitted the system resolver
+	// is used. This may be used in networks which require split-horizon DNS.
+	//
+	// NOTE: Connection strings which use DNS SRV records are still resolved using the system resolver.
+	Resolver *net.Resolver
+
+	// Proxy routes requests to the cluster through a proxy, either an explicit proxy or the one configured in the
+	// environment. When omitted, requests are sent directly to the cluster.
+	Proxy *ProxyOptions
+
+	// ClusterConfigCache is the path to a file used to persist the last known cluster config; when provided, the client
+	// will attempt to bootstrap using the cached config (after validating it against the bootstrap host) rather than
+	// fetching a new one. This may be used to speed up short-lived clients, where the topology rarely changes.
+	//
+	// NOTE: The cached config is only a starting point, it's updated as usual when cluster config polling is enabled.
+	ClusterConfigCache string
+
+	// DefaultHeaders are set on every request dispatched by the client, including those dispatched internally. This
+	// may be used by embedding tools to stamp information (e.g. a run id) on requests, for correlation in the cluster
+	// logs.
+	//
+	// NOTE: Headers set on an individual request take precedence, as do those set by the client e.g. 'Content-Type'.
+	DefaultHeaders Header
+
+	// UserAgentSuffix is appended to the user agent returned by the auth provider e.g. to include the version of an
+	// embedding tool.
+	UserAgentSuffix string
+
+	// AuthMode is the mechanism used to authenticate requests, some endpoints behave differently when authenticated
+	// using a session rather than HTTP basic auth. Defaults to 'AuthModeBasic'.
+	//
+	// NOTE: Signers returned by 'SignerForHost' take precedence.
+	AuthMode AuthMode
+
+	// ResponseCache enables caching the responses from the given read-only endpoints, this may be used by tools which
+	// repeatedly request the same information (e.g. the bucket list) during a run. When omitted, responses aren't
+	// cached.
+	//
+	// NOTE: Only applies to 'Execute'/'ExecuteWithContext', streaming requests and 'Do' are never cached.
+	ResponseCache *ResponseCacheOptions
+
+	// ConfigManager manages the cluster config used by the client, this may be used to share a cluster config between
+	// multiple clients. Defaults to a new 'ClusterConfigManager'.
+	ConfigManager ConfigManager
+
+	// DNSRefreshInterval is the interval at which the connection string is re-resolved, where it was resolved using a
+	// DNS SRV record; any new addresses are added to the bootstrap hosts, which are used to update the cluster config
+	// once all the known nodes have been exhausted. This allows long-running clients to survive the replacement of all
+	// the nodes in the cluster, behind a stable hostname. Defaults to 'DefaultDNSRefreshInterval', a negative value
+	// disables re-resolution.
+	//
+	// NOTE: Only applies when cluster config polling is enabled.
+	DNSRefreshInterval time.Duration
+
+	// AuditSink receives a record for every mutating (non-GET) request performed by the client, including those
+	// performed using 'Do' and streaming requests. When omitted, requests aren't audited.
+	AuditSink AuditSink
+
+	// ConnectionPool is used to tune the pool of connections used by the client, any missing values are set to a sane
+	// default.
+	ConnectionPool ConnectionPoolOptions
+
+	// Clock is used for all the time dependent behavior of the client e.g. waiting before retrying requests, and
+	// polling for cluster config updates. Defaults to the system clock, a fake clock may be provided in tests.
+	//
+	// NOTE: Not used by the cluster config manager when one is provided using 'ConfigManager'.
+	Clock Clock
+
+	// SpillDirectory is the directory in which temporary files are created, for response bodies which are larger than
+	// the 'SpillThreshold' of their request. Defaults to the directory returned by 'os.TempDir'.
+	//
+	// NOTE: This should be on disk, rather than in memory (e.g. tmpfs), otherwise spilling has no benefit.
+	SpillDirectory string
+
+	// RetryBudget enables limiting the number of retries performed across all the requests dispatched by the client,
+	// once exhausted, requests which would otherwise be retried fail with 'ErrRetryBudgetExhausted'. When omitted,
+	// each request is retried independently.
+	RetryBudget *RetryBudgetOptions
+
+	// PathPrefix is prepended to the endpoint of every request, this allows connecting to a cluster which is exposed
+	// behind a reverse proxy using a URL prefix e.g. '/couchbase'. The hosts returned by 'GetServiceHost' and
+	// 'GetAllServiceHosts' include the prefix, so may be used as the 'Host' of a request.
+	//
+	// NOTE: Not applied when using 'ConnectionModeLoopback', since requests are dispatched directly to the local node.
+	PathPrefix string
+
+	// TLSVerify replaces the standard verification of the certificates presented by the cluster, allowing certificate
+	// pinning using SPKI hashes or a custom verification function; this may be used with self-managed PKI, where
+	// distributing the CA certificates isn't practical.
+	TLSVerify *TLSVerifyOptions
+
+	// BootstrapRetry enables retrying the full list of hosts from the connection string with backoff, until the client
+	// bootstraps or the bootstrap timeout elapses. When omitted, each host is tried once.
+	BootstrapRetry *BootstrapRetryOptions
+
+	// ServiceDefaults are the default 'Timeout'/'ContentType' for requests dispatched to each service, which are used
+	// when the request leaves them unset.
+	//
+	// NOTE: Only applies to requests which set a 'Service', and default timeouts aren't applied to streaming requests.
+	ServiceDefaults map[Service]ServiceDefaults
+
+	// DisableHealthAwareRouting disables tracking the latency/error rate of the requests dispatched to each node. By
+	// default, requests avoid nodes which are responding much slower than the other nodes running the same service, or
+	// which are failing most requests, so long as there's a healthy node available.
+	DisableHealthAwareRouting bool
+
+	// AllowDiagEval allows evaluating arbitrary Erlang expressions on the cluster using 'DiagEval'; by default,
+	// 'DiagEval' returns 'ErrDiagEvalNotAllowed'.
+	//
+	// NOTE: This is an escape hatch for support tooling, an incorrect expression may do irreparable damage to the
+	// cluster.
+	AllowDiagEval bool
 }
 
 // defaults fills any missing attributes to a sane default.
@@ -73,6 +214,10 @@ func (c *ClientOptions) defaults() {
 	if c.Logger == nil {
 		c.Logger = slog.Default()
 	}
+
+	if c.DNSRefreshInterval == 0 {
+		c.DNSRefreshInterval = DefaultDNSRefreshInterval
+	}
 }
 
 // Client is a REST client used to retrieve/send information to/from a Couchbase Cluster.
@@ -90,6 +235,46 @@ type Client struct {
 
 	reqResLogLevel slog.Level
 
+	limiter *concurrencyLimiter
+
+	signerForHost SignerForHost
+
+	defaultHeaders  Header
+	userAgentSuffix string
+
+	serviceDefaults serviceDefaults
+
+	sessions *sessionSigner
+
+	cache *responseCache
+
+	auditSink AuditSink
+
+	topology *topologyWatchers
+
+	configListeners *configListeners
+
+	pool *connectionPool
+
+	resolve            func() (*connstr.ResolvedConnectionString, error)
+	dnsRefreshInterval time.Duration
+
+	clock Clock
+
+	spillDirectory string
+
+	retryBudget *retryBudget
+
+	pathPrefix string
+
+	bootstrapHost string
+	ccCache       *clusterConfigCache
+	nsCache       *nodeServicesCache
+
+	onBehalfOf onBehalfOfSupport
+
+	allowDiagEval bool
+
 	wg         sync.WaitGroup
 	ctx        context.Context
 	cancelFunc context.CancelFunc
@@ -111,12 +296,17 @@ func NewClient(options ClientOptions) (*Client, error) {
 		return nil, err
 	}
 
-	// Get commonly used information about the cluster now to avoid multiple duplicate requests at a later date
-	client.clusterInfo, err = client.getClusterInfo()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get cluster information: %w", err)
+	// Get commonly used information about the cluster now to avoid multiple duplicate requests at a later date, this
+	// will have already been populated if we bootstrapped using a cached cluster config.
+	if client.clusterInfo.UUID == "" {
+		client.clusterInfo, err = client.getClusterInfo()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cluster information: %w", err)
+		}
 	}
 
+	client.persistCC()
+
 	client.logger.Info(
 		"successfully connected to cluster",
 		"enterprise", client.clusterInfo.Enterprise,
@@ -128,6 +318,7 @@ func NewClient(options ClientOptions) (*Client, error) {
 	// having the cluster uuid to determine whether it's safe to use a given cluster config.
 	if !(options.ConnectionMode.ThisNodeOnly() || options.DisableCCP) {
 		client.beginCCP()
+		client.beginDNSRefresh()
 	}
 
 	return client, nil
@@ -144,6 +335,8 @@ func returnBootstrappedClient(options ClientOptions) (*Client, error) {
 		logger = slog.Default()
 	}
 
+	logger = newRedactingLogger(logger, options.Redaction)
+
 	clientTimeout, ok := envvar.GetDurationBC("CB_REST_CLIENT_TIMEOUT_SECS")
 	if !ok {
 		clientTimeout = DefaultClientTimeout
@@ -191,23 +384,71 @@ func returnBootstrappedClient(options ClientOptions) (*Client, error) {
 	authProviderOptions := AuthProviderOptions{
 		resolved: resolved,
 		provider: options.Provider,
+		manager:  options.ConfigManager,
 		logger:   logger,
+		clock:    clockOrDefault(options.Clock),
+		health:   newNodeHealth(options.DisableHealthAwareRouting),
+	}
+
+	tlsConfig, err := newTLSConfig(options.TLSConfig, options.TLSVerify)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TLS config: %w", err)
+	}
+
+	transport := netutil.NewHTTPTransport(tlsConfig, timeouts)
+
+	if dial := newDialContext(options.DialContext, options.Resolver, timeouts); dial != nil {
+		transport.DialContext = dial
 	}
 
+	transport.Proxy, err = newProxyFunc(options.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create proxy: %w", err)
+	}
+
+	pool := newConnectionPool(transport, options.ConnectionPool)
+
 	// Added nil ClusterInfo so that it can be populated later if needed.
 	client := &Client{
-		client:            newHTTPClient(clientTimeout, netutil.NewHTTPTransport(options.TLSConfig, timeouts)),
-		authProvider:      NewAuthProvider(authProviderOptions),
-		connectionMode:    options.ConnectionMode,
-		hostnameTransform: options.HostnameTransform,
-		pollTimeout:       pollTimeout,
-		requestRetries:    requestRetries,
-		reqResLogLevel:    options.ReqResLogLevel,
-		clusterInfo:       &clusterInfo{},
-		logger:            logger,
-	}
-
-	err = client.bootstrap()
+		client:             newHTTPClient(clientTimeout, transport),
+		authProvider:       NewAuthProvider(authProviderOptions),
+		connectionMode:     options.ConnectionMode,
+		hostnameTransform:  options.HostnameTransform,
+		pollTimeout:        pollTimeout,
+		requestRetries:     requestRetries,
+		reqResLogLevel:     options.ReqResLogLevel,
+		limiter:            newConcurrencyLimiter(options.MaxConcurrentRequests),
+		signerForHost:      options.SignerForHost,
+		defaultHeaders:     options.DefaultHeaders,
+		userAgentSuffix:    options.UserAgentSuffix,
+		serviceDefaults:    newServiceDefaults(options.ServiceDefaults),
+		clusterInfo:        &clusterInfo{},
+		ccCache:            newClusterConfigCache(options.ClusterConfigCache),
+		nsCache:            newNodeServicesCache(),
+		cache:              newResponseCache(options.ResponseCache, clockOrDefault(options.Clock)),
+		auditSink:          options.AuditSink,
+		topology:           newTopologyWatchers(),
+		configListeners:    newConfigListeners(),
+		pool:               pool,
+		resolve:            parsed.Resolve,
+		dnsRefreshInterval: options.DNSRefreshInterval,
+		clock:              clockOrDefault(options.Clock),
+		spillDirectory:     options.SpillDirectory,
+		retryBudget:        newRetryBudget(options.RetryBudget, clockOrDefault(options.Clock)),
+		pathPrefix:         cleanPathPrefix(options.PathPrefix),
+		allowDiagEval:      options.AllowDiagEval,
+		logger:             logger,
+	}
+
+	if options.AuthMode == AuthModeSession {
+		client.sessions = newSessionSigner(client.client, client.userAgent, client.pathPrefix)
+	}
+
+	if client.bootstrapFromCache() {
+		return client, nil
+	}
+
+	err = client.bootstrapWithRetries(options.BootstrapRetry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to bootstrap client: %w", err)
 	}
@@ -223,6 +464,7 @@ func (c *Client) bootstrap() error {
 		hostFunc          = c.authProvider.bootstrapHostFunc()
 		errAuthentication *AuthenticationError
 		errAuthorization  *AuthorizationError
+		errCredentials    *CredentialsError
 	)
 
 	for {
@@ -231,23 +473,19 @@ func (c *Client) bootstrap() error {
 		// If this call returned an empty hostname then we've tried all the available hostnames and we've failed to
 		// bootstrap against any of them.
 		if host == "" {
-			return &BootstrapFailureError{ErrAuthentication: errAuthentication, ErrAuthorization: errAuthorization}
+			return newBootstrapFailureError(errAuthentication, errAuthorization, errCredentials)
 		}
 
 		err := c.updateCCFromHost(host)
 
 		// We've successfully bootstrapped the client
 		if err == nil {
+			c.bootstrapHost = host
 			break
 		}
 
-		var (
-			errUnknownAuthority *UnknownAuthorityError
-			errUnknownX509Error *UnknownX509Error
-		)
-
 		// For security reasons, return immediately if the user is connecting using TLS and we've received an x509 error
-		if errors.As(err, &errUnknownAuthority) || errors.As(err, &errUnknownX509Error) {
+		if !shouldRetryBootstrap(err) {
 			return err
 		}
 
@@ -257,12 +495,138 @@ func (c *Client) bootstrap() error {
 		errors.As(err, &errAuthentication)
 		errors.As(err, &errAuthorization)
 
+		// Failing to get credentials is reported distinctly, so that it's not confused with the cluster rejecting them
+		errors.As(err, &errCredentials)
+
 		c.logger.Warn("failed to bootstrap client, will retry", "error", err)
 	}
 
 	return nil
 }
 
+// newBootstrapFailureError returns a new bootstrap failure error, only populating the errors which are non-nil; this
+// avoids storing typed nil pointers in the error interfaces.
+func newBootstrapFailureError(
+	errAuthentication *AuthenticationError,
+	errAuthorization *AuthorizationError,
+	errCredentials *CredentialsError,
+) *BootstrapFailureError {
+	var failure BootstrapFailureError
+
+	if errAuthentication != nil {
+		failure.ErrAuthentication = errAuthentication
+	}
+
+	if errAuthorization != nil {
+		failure.ErrAuthorization = errAuthorization
+	}
+
+	if errCredentials != nil {
+		failure.ErrCredentials = errCredentials
+	}
+
+	return &failure
+}
+
+// bootstrapFromCache attempts to bootstrap the client using the cached cluster config, returning a boolean indicating
+// whether it was successful. The cached config is only used if it was fetched from one of the bootstrap hosts, and that
+// host is still a member of the same cluster.
+func (c *Client) bootstrapFromCache() bool {
+	if c.ccCache == nil {
+		return false
+	}
+
+	cached, err := c.ccCache.load()
+	if err != nil {
+		c.logger.Warn("failed to load cached cluster config, will bootstrap", "error", err)
+		return false
+	}
+
+	if cached == nil || cached.ThisNodeOnly != c.connectionMode.ThisNodeOnly() {
+		return false
+	}
+
+	hostFunc := c.authProvider.bootstrapHostFunc()
+
+	for host := hostFunc(); host != ""; host = hostFunc() {
+		if host != cached.Host {
+			continue
+		}
+
+		err = c.validateCachedCC(host, cached)
+		if err == nil {
+			c.logger.Debug("bootstrapped using cached cluster config", "host", host, "revision", cached.Config.Revision)
+			return true
+		}
+
+		c.logger.Warn("cached cluster config is not valid, will bootstrap", "host", host, "error", err)
+
+		return false
+	}
+
+	return false
+}
+
+// validateCachedCC checks that the given host is still a member of the cluster the cached config was fetched from, and
+// if so, uses the cached config.
+func (c *Client) validateCachedCC(host string, cached *cachedClusterConfig) error {
+	body, err := c.get(host, EndpointPools)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var meta *clusterMetadata
+
+	err = json.Unmarshal(body, &meta)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if meta == nil || meta.UUID != cached.UUID {
+		return fmt.Errorf("node is a member of a different cluster")
+	}
+
+	parsed, err := url.Parse(host)
+	if err != nil {
+		return fmt.Errorf("failed to parse host '%s': %w", host, err)
+	}
+
+	err = c.authProvider.SetClusterConfig(parsed.Hostname(), cached.Config)
+	if err != nil {
+		return fmt.Errorf("failed to set cluster config: %w", err)
+	}
+
+	c.bootstrapHost = host
+
+	c.clusterInfo = &clusterInfo{
+		Enterprise:       meta.Enterprise,
+		UUID:             meta.UUID,
+		DeveloperPreview: meta.DeveloperPreview,
+	}
+
+	return nil
+}
+
+// persistCC persists the current cluster config to the cluster config cache (if enabled), failures are logged but
+// otherwise ignored since the cache is only an optimization.
+func (c *Client) persistCC() {
+	if c.ccCache == nil || c.bootstrapHost == "" {
+		return
+	}
+
+	cached := &cachedClusterConfig{
+		UUID:         c.clusterInfo.UUID,
+		Host:         c.bootstrapHost,
+		ThisNodeOnly: c.connectionMode.ThisNodeOnly(),
+		Config:       c.authProvider.manager.GetClusterConfig(),
+	}
+
+	err := c.ccCache.store(cached)
+	if err != nil {
+		c.logger.Warn("failed to persist cluster config", "error", err)
+	}
+}
+
 // beginCCP is a utility function which sets up and begins the cluster config polling goroutine.
 func (c *Client) beginCCP() {
 	// Ensure we add to the wait group before spinning up the polling goroutine
@@ -291,7 +655,10 @@ func (c *Client) pollCC() {
 
 		if err := c.updateCC(); err != nil {
 			c.logger.Warn("failed to update cluster config, will retry", "error", err)
+			continue
 		}
+
+		c.persistCC()
 	}
 }
 
@@ -320,6 +687,12 @@ func (c *Client) updateCC() error {
 		c.logger.Warn("failed to update config using host", "hostname", node.Hostname, "error", err)
 	}
 
+	// All the nodes in the cluster may have been replaced e.g. behind a stable DNS SRV record, fall back to the bootstrap
+	// hosts which are periodically re-resolved.
+	if c.authProvider.resolved.SRV && c.updateCCFromBootstrapHosts() {
+		return nil
+	}
+
 	return ErrExhaustedClusterNodes
 }
 
@@ -330,6 +703,12 @@ func (c *Client) updateCCFromNode(node *Node) error {
 		return &ServiceNotAvailableError{service: ServiceManagement}
 	}
 
+	return c.updateCCFromValidHost(host)
+}
+
+// updateCCFromValidHost will attempt to update the clients cluster config using the provided host, once it's been
+// confirmed that the host is a member of the cluster.
+func (c *Client) updateCCFromValidHost(host string) error {
 	valid, err := c.validHost(host)
 	if err != nil {
 		return fmt.Errorf("failed to check if node is valid: %w", err)
@@ -344,30 +723,67 @@ func (c *Client) updateCCFromNode(node *Node) error {
 
 // updateCCFromHost will attempt to update the clients cluster config using the provided host.
 func (c *Client) updateCCFromHost(host string) error {
-	body, err := c.get(host, EndpointNodesServices)
+	config, err := c.fetchCC(host)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return err
+	}
+
+	if c.connectionMode.ThisNodeOnly() {
+		config.FilterOtherNodes()
+	}
+
+	old := c.authProvider.manager.GetClusterConfig()
+
+	err = c.authProvider.SetClusterConfig(host, config)
+	if err != nil {
+		return err
+	}
+
+	c.topology.publish(diffTopology(old, config))
+
+	if configChanged(old, config) {
+		c.configListeners.notify(config.Revision)
+	}
+
+	return nil
+}
+
+// fetchCC fetches the cluster config from the provided host, reusing the previously fetched config where it hasn't
+// changed since the last poll.
+func (c *Client) fetchCC(host string) (*ClusterConfig, error) {
+	body, etag, err := c.getConditional(host, EndpointNodesServices, c.nsCache.etag(host))
+	if errors.Is(err, errNotModified) {
+		if config, ok := c.nsCache.lookup(host, nil); ok {
+			return config, nil
+		}
+
+		// We should never get here, we only perform conditional requests for configs we've cached
+		body, etag, err = c.getConditional(host, EndpointNodesServices, "")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	if config, ok := c.nsCache.lookup(host, body); ok {
+		return config, nil
 	}
 
 	// This shouldn't really fail since we should be constructing valid hosts in the auth provider
 	parsed, err := url.Parse(host)
 	if err != nil {
-		return fmt.Errorf("failed to parse host '%s': %w", host, err)
+		return nil, fmt.Errorf("failed to parse host '%s': %w", host, err)
 	}
 
 	// We must extract the raw hostname (no port) so that it can be used as the fallback host
-	host = parsed.Hostname()
-
-	config, err := c.unmarshalCC(host, body)
+	config, err := c.unmarshalCC(parsed.Hostname(), body)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal cluster config: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal cluster config: %w", err)
 	}
 
-	if c.connectionMode.ThisNodeOnly() {
-		config.FilterOtherNodes()
-	}
+	c.nsCache.store(host, etag, body, config)
 
-	return c.authProvider.SetClusterConfig(host, config)
+	return config, nil
 }
 
 // validHost returns a boolean indicating whether we should use the cluster config from the provided host. This should
@@ -406,35 +822,58 @@ func (c *Client) validHost(host string) (bool, error) {
 // get is similar to the public 'Execute' function, however, it is meant only to be used internally is less flexible and
 // doesn't support automatic retries.
 func (c *Client) get(host string, endpoint Endpoint) ([]byte, error) {
+	body, _, err := c.getConditional(host, endpoint, "")
+	return body, err
+}
+
+// getConditional is similar to 'get', however, when provided with an 'ETag' the request is only fulfilled if the
+// resource has changed, 'errNotModified' is returned otherwise. Returns the body, and 'ETag' (if any) of the response.
+func (c *Client) getConditional(host string, endpoint Endpoint, etag string) ([]byte, string, error) {
 	ctx, cancelFunc := context.WithTimeout(context.Background(), defaultInternalRequestTimeout)
 	defer cancelFunc()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host+string(endpoint), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host+c.pathPrefix+string(endpoint), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for key, value := range c.defaultHeaders {
+		req.Header.Set(key, value)
+	}
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
 	}
 
-	err = setAuthHeaders(host, c.authProvider.provider, req, c.logger)
+	req.Header.Set("Accept-Encoding", encodingGzip)
+
+	err = setAuthHeaders(host, c.userAgent(), c.authProvider.provider, c.signer(host), req, nil, c.logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to set auth headers: %w", err)
+		return nil, "", fmt.Errorf("failed to set auth headers: %w", err)
 	}
 
 	resp, err := c.perform(retry.NewContext(context.Background()), req, slog.LevelDebug, 0)
 	if err != nil {
-		return nil, handleRequestError(req, err) // Purposefully not wrapped
+		return nil, "", handleRequestError(req, err) // Purposefully not wrapped
 	}
 	defer c.cleanupResp(resp)
 
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return nil, "", errNotModified
+	}
+
+	c.decompressResponse(ctx, resp)
+
 	body, err := readBody(http.MethodGet, endpoint, resp.Body, resp.ContentLength)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, handleResponseError(http.MethodGet, endpoint, resp.StatusCode, body)
+		return nil, "", handleResponseError(http.MethodGet, endpoint, resp, body)
 	}
 
-	return body, nil
+	return body, resp.Header.Get("ETag"), nil
 }
 
 // unmarshalCC is a utility function which handles unmarshalling the cluster config response whilst cleaning
@@ -521,6 +960,53 @@ func (c *Client) AltAddr() bool {
 	return c.authProvider.useAltAddr
 }
 
+// InFlightRequests returns the number of requests which have been dispatched by the client, but whose response bodies
+// have not yet been closed.
+func (c *Client) InFlightRequests() int64 {
+	return c.limiter.inFlight.Load()
+}
+
+// ConnectionPoolStats returns metrics about the connections used by the client, this may be used to diagnose whether
+// the connection pool is limiting throughput.
+func (c *Client) ConnectionPoolStats() ConnectionPoolStats {
+	return c.pool.stats()
+}
+
+// ResponseCacheStats returns metrics about the usage of the response cache, the returned stats will be zero if the
+// response cache is disabled.
+func (c *Client) ResponseCacheStats() ResponseCacheStats {
+	if c.cache == nil {
+		return ResponseCacheStats{}
+	}
+
+	return c.cache.stats()
+}
+
+// RetryBudgetStats returns metrics about the usage of the retry budget, the returned stats will be zero if the retry
+// budget is disabled.
+func (c *Client) RetryBudgetStats() RetryBudgetStats {
+	return c.retryBudget.stats()
+}
+
+// InvalidateResponseCache removes the cached responses for the given endpoints, or all the cached responses if no
+// endpoints are given. This should be used after modifying the cluster in a way which isn't visible to the client,
+// for example, by using another tool.
+func (c *Client) InvalidateResponseCache(endpoints ...Endpoint) {
+	if c.cache != nil {
+		c.cache.invalidate(endpoints...)
+	}
+}
+
+// WatchTopology returns a channel on which events are delivered when the topology of the cluster changes e.g. when a
+// node is added/removed, this may be used to redistribute work between nodes. The channel is closed once the given
+// context is cancelled, or the client is closed.
+//
+// NOTE: Events are derived from the cluster config, so are only delivered when cluster config polling is enabled; they
+// must be consumed promptly, the cluster config is not updated whilst events are being delivered.
+func (c *Client) WatchTopology(ctx context.Context) <-chan TopologyEvent {
+	return c.topology.watch(ctx)
+}
+
 // Execute the given request to completion reading the entire response body whilst honoring request level
 // retries/timeout.
 func (c *Client) Execute(request *Request) (*Response, error) {
@@ -530,24 +1016,47 @@ func (c *Client) Execute(request *Request) (*Response, error) {
 // ExecuteWithContext the given request to completion, using the provided context, reading the entire response body
 // whilst honoring request level retries/timeout.
 func (c *Client) ExecuteWithContext(ctx context.Context, request *Request) (*Response, error) {
+	cacheable := c.cache.cacheable(request)
+
+	if cacheable {
+		if response, ok := c.cache.get(request); ok {
+			return response, nil
+		}
+	}
+
 	resp, err := c.Do(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer c.cleanupResp(resp)
 
-	response := &Response{StatusCode: resp.StatusCode}
+	// Any request which may modify an endpoint invalidates its cached responses, regardless of whether it succeeded
+	if c.cache != nil && request.Method != http.MethodGet {
+		c.cache.invalidate(request.Endpoint)
+	}
+
+	response := &Response{StatusCode: resp.StatusCode, ETag: resp.Header.Get("ETag")}
+
+	// Only successful responses are spilled, error responses are parsed to create an informative error
+	if response.StatusCode == request.ExpectedStatusCode && request.SpillThreshold > 0 {
+		response.Body, response.file, err = spillResponseBody(c.spillDirectory, request, resp)
+	} else {
+		response.Body, err = readResponseBody(request, resp)
+	}
 
-	response.Body, err = readBody(request.Method, request.Endpoint, resp.Body, resp.ContentLength)
 	if err != nil {
 		return response, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	if response.StatusCode == request.ExpectedStatusCode {
+		if cacheable && !response.Spilled() {
+			c.cache.set(request, response)
+		}
+
 		return response, nil
 	}
 
-	return response, handleResponseError(request.Method, request.Endpoint, response.StatusCode, response.Body)
+	return response, handleResponseError(request.Method, request.Endpoint, resp, response.Body)
 }
 
 // ExecuteStream executes the given request, returning a read only channel which can be used to read updates from a
@@ -588,12 +1097,12 @@ func (c *Client) ExecuteStreamWithContext(ctx context.Context, request *Request)
 	// Received a valid response, but with the wrong status code, ensure we drain and close the response body
 	defer c.cleanupResp(resp)
 
-	body, err := readBody(request.Method, request.Endpoint, resp.Body, resp.ContentLength)
+	body, err := readResponseBody(request, resp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	return nil, handleResponseError(request.Method, request.Endpoint, resp.StatusCode, body)
+	return nil, handleResponseError(request.Method, request.Endpoint, resp, body)
 }
 
 // beginStream constructs a stream, and kicks off a goroutine to wait for, and process mutations.
@@ -680,12 +1189,62 @@ func (c *Client) stream(ctx *retry.Context, request *Request, resp *http.Respons
 //
 // NOTE: If the returned error is nil, the Response will contain a non-nil Body which the caller is expected to close.
 func (c *Client) Do(ctx context.Context, request *Request) (*http.Response, error) {
+	start := c.clock.Now()
+
+	request = c.serviceDefaults.apply(request)
+
+	err := c.checkOnBehalfOf(ctx, request)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	// Compress the body once upfront, rather than for each attempt
+	compressed, err := compressRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare request body: %w", err)
+	}
+
+	c.logCompressed(ctx, request, compressed)
+
+	cancelFunc := func() {}
+
+	if request.TotalTimeout > 0 {
+		ctx, cancelFunc = context.WithTimeout(ctx, request.TotalTimeout)
+	}
+
+	// retryErr is the reason a request with a retryable response was not retried (if any), this is returned instead of
+	// the response
+	var retryErr error
+
+	// Retries are limited across all requests, only the initial attempt counts as a request
+	c.retryBudget.recordRequest()
+
+	var (
+		// node is the node the last attempt was dispatched to
+		node string
+
+		// route tracks the nodes which are in maintenance/unreachable, subsequent attempts are routed elsewhere
+		route routing
+	)
+
 	shouldRetry := func(ctx *retry.Context, resp *http.Response, err error) bool {
-		if resp != nil {
-			return c.shouldRetryWithResponse(ctx, request, resp)
+		var retry bool
+
+		if resp != nil && node != "" && isNodeInMaintenance(resp) {
+			retry, retryErr = c.shouldRetryInMaintenance(ctx, request, node, &route.maintenance)
+		} else if resp != nil {
+			retry, retryErr = c.shouldRetryWithResponse(ctx, request, resp)
+		} else {
+			retry, retryErr = c.shouldRetryWithError(ctx, request, err)
+		}
+
+		// The node may have crashed, dispatch subsequent attempts to another node (where possible); only idempotent
+		// requests are rerouted, since the node may have processed the request before the connection failed.
+		if retry && request.IsIdempotent() && node != "" && isConnectionError(err) {
+			route.unreachable.add(node)
 		}
 
-		return c.shouldRetryWithError(ctx, request, err)
+		return retry
 	}
 
 	logRetry := func(ctx *retry.Context, resp *http.Response, err error) {
@@ -725,13 +1284,37 @@ func (c *Client) Do(ctx context.Context, request *Request) (*http.Response, erro
 
 	resp, err := retryer.DoWithContext(
 		ctx,
-		func(ctx *retry.Context) (*http.Response, error) { return c.do(ctx, request) },
+		func(ctx *retry.Context) (*http.Response, error) {
+			resp, dispatched, err := c.do(ctx, compressed, route)
+			node = dispatched
+
+			return resp, err
+		},
 	)
 
+	switch {
+	case errors.Is(retryErr, ErrRetryBudgetExhausted):
+		// Include the reason the request would have been retried, as exhausting the budget isn't why it failed
+		err = fmt.Errorf("failed to retry request: %w, last error: %w", retryErr, enhanceError(err, request, resp))
+	case err == nil && retryErr != nil:
+		err = fmt.Errorf("failed to retry request: %w", retryErr)
+	}
+
+	c.audit(request, start, resp, err)
+
 	if err == nil || (resp != nil && resp.StatusCode == request.ExpectedStatusCode) {
+		// The total timeout applies until the caller has finished with the response body
+		if resp != nil {
+			resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: cancelFunc}
+		} else {
+			cancelFunc()
+		}
+
 		return resp, err
 	}
 
+	defer cancelFunc()
+
 	// The request failed, meaning the response won't be returned to the user, ensure it's cleaned up
 	defer c.cleanupResp(resp)
 
@@ -743,8 +1326,45 @@ func (c *Client) Do(ctx context.Context, request *Request) (*http.Response, erro
 	return nil, err
 }
 
-// shouldRetryWithError returns a boolean indicating whether the given error is retryable.
-func (c *Client) shouldRetryWithError(ctx *retry.Context, request *Request, err error) bool {
+// shouldRetryInMaintenance returns a boolean indicating whether the given request, which was rejected because the node
+// it was dispatched to is in maintenance, should be retried using another node; a 'NodeInMaintenanceError' is returned
+// if there are no other nodes running the service.
+//
+// NOTE: The node didn't process the request, so it's safe to retry regardless of whether it's idempotent.
+func (c *Client) shouldRetryInMaintenance(
+	ctx *retry.Context,
+	request *Request,
+	node string,
+	maintenance *nodeSet,
+) (bool, error) {
+	c.logger.Warn(
+		"node is in maintenance, rerouting request",
+		"attempt", ctx.Attempt(),
+		"method", request.Method,
+		"endpoint", request.Endpoint,
+		"node", node,
+	)
+
+	maintenance.add(node)
+
+	// Requests for a specific node can't be rerouted
+	if request.NodeUUID != "" {
+		return false, &NodeInMaintenanceError{service: request.Service, nodes: []string{node}}
+	}
+
+	_, err := c.authProvider.getServiceHost(request.Service, 0, routing{maintenance: *maintenance})
+	if err != nil {
+		return false, err
+	}
+
+	err = c.acquireRetry(ctx)
+
+	return err == nil, err
+}
+
+// shouldRetryWithError returns a boolean indicating whether the given error is retryable, an error is returned if the
+// request is retryable but can't be retried because the retry budget has been exhausted.
+func (c *Client) shouldRetryWithError(ctx *retry.Context, request *Request, err error) (bool, error) {
 	c.logger.Warn(
 		"request failed",
 		"attempt", ctx.Attempt(),
@@ -754,7 +1374,13 @@ func (c *Client) shouldRetryWithError(ctx *retry.Context, request *Request, err
 	)
 
 	if !shouldRetry(err) {
-		return false
+		return false, nil
+	}
+
+	// Checked before waiting, there's no point waiting for a retry which won't be permitted
+	err = c.acquireRetry(ctx)
+	if err != nil {
+		return false, err
 	}
 
 	// We always update the cluster config after a failed request, since some connection failures may be due to an
@@ -762,16 +1388,17 @@ func (c *Client) shouldRetryWithError(ctx *retry.Context, request *Request, err
 	// example, the 'connection refused' error.
 	c.waitUntilUpdated(ctx)
 
-	return true
+	return true, nil
 }
 
-// shouldRetryWithResponse returns a boolean indicating whether the given request is retryable.
+// shouldRetryWithResponse returns a boolean indicating whether the given request is retryable, an error is returned if
+// the request is retryable but can't be retried e.g. because doing so would exceed its deadline.
 //
 // NOTE: When CCP is enabled, this function may block until the client has the latest available cluster config.
-func (c *Client) shouldRetryWithResponse(ctx *retry.Context, request *Request, resp *http.Response) bool {
+func (c *Client) shouldRetryWithResponse(ctx *retry.Context, request *Request, resp *http.Response) (bool, error) {
 	// We've got our expected status code, don't retry
 	if resp.StatusCode == request.ExpectedStatusCode {
-		return false
+		return false, nil
 	}
 
 	c.logger.Warn(
@@ -782,10 +1409,17 @@ func (c *Client) shouldRetryWithResponse(ctx *retry.Context, request *Request, r
 		"status_code", resp.StatusCode,
 	)
 
+	// The session used to authenticate the request has expired, the request was rejected without being processed so
+	// it's safe to retry (with a new session) regardless of whether it's idempotent.
+	if resp.StatusCode == http.StatusUnauthorized && c.sessions != nil && c.sessions.expire(resp.Request) {
+		err := c.acquireRetry(ctx)
+		return err == nil, err
+	}
+
 	// Either this request can't be retried, or the user has explicitly stated that they don't want this status code
 	// retried, don't retry.
 	if !request.IsIdempotent() || slices.Contains(request.NoRetryOnStatusCodes, resp.StatusCode) {
-		return false
+		return false, nil
 	}
 
 	var (
@@ -797,16 +1431,36 @@ func (c *Client) shouldRetryWithResponse(ctx *retry.Context, request *Request, r
 	)
 
 	if !retry {
-		return false
+		return false, nil
 	}
 
+	// Checked before waiting, there's no point waiting for a retry which won't be permitted
+	err := c.acquireRetry(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// The response is no longer in-flight, it shouldn't prevent other requests being dispatched whilst we're waiting
+	releaseLimit(resp)
+
 	if updateCC {
 		c.waitUntilUpdated(ctx)
 	}
 
-	waitForRetryAfter(resp)
+	err = waitForRetryAfter(ctx, c.clock, resp)
 
-	return true
+	return err == nil, err
+}
+
+// acquireRetry returns an error if retrying the request isn't permitted by the retry budget.
+//
+// NOTE: The final attempt isn't retried, so doesn't consume the budget.
+func (c *Client) acquireRetry(ctx *retry.Context) error {
+	if ctx.Attempt() >= c.requestRetries || c.retryBudget.acquire() {
+		return nil
+	}
+
+	return ErrRetryBudgetExhausted
 }
 
 // waitUntilUpdated blocks the calling goroutine until the cluster config has been updated.
@@ -835,34 +1489,62 @@ func (c *Client) waitUntilUpdated(ctx context.Context) {
 	}
 }
 
-// do is a convenience which prepares then performs the provided request.
-func (c *Client) do(ctx *retry.Context, request *Request) (*http.Response, error) {
-	prep, err := c.prepare(ctx, request)
+// do is a convenience which prepares then performs the provided request, avoiding the given nodes which are in
+// maintenance. Also returns the node the request was dispatched to (see 'prepare').
+func (c *Client) do(
+	ctx *retry.Context,
+	request *Request,
+	route routing,
+) (*http.Response, string, error) {
+	prep, node, err := c.prepare(ctx, request, route)
+	if err != nil {
+		return nil, node, fmt.Errorf("failed to prepare request: %w", err)
+	}
+
+	release, err := c.limiter.acquire(ctx, int64(request.Weight))
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare request: %w", err)
+		return nil, node, fmt.Errorf("failed to acquire concurrency limit: %w", err)
 	}
 
+	start := c.clock.Now()
+
 	resp, err := c.perform(ctx, prep, c.reqResLogLevel, request.Timeout)
+
+	c.authProvider.health.observe(node, c.clock.Now().Sub(start), err != nil || resp.StatusCode >= 500)
+
 	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %w", err)
+		release()
+		return nil, node, fmt.Errorf("failed to perform request: %w", err)
 	}
 
-	return resp, nil
+	c.decompressResponse(ctx, resp)
+
+	// The request remains in-flight until the caller has finished with the response body
+	resp.Body = &limitedBody{releaseOnClose{ReadCloser: resp.Body, release: release}}
+
+	return resp, node, nil
 }
 
 // prepare converts the request into a raw HTTP request which can be dispatched to the cluster. Uses the same context
 // meaning the request timeout is not reset by retries.
-func (c *Client) prepare(ctx *retry.Context, request *Request) (*http.Request, error) {
+//
+// NOTE: Also returns the host of the node the request is being dispatched to (prior to any transformation), which is
+// empty if the request was dispatched to a user provided host.
+func (c *Client) prepare(
+	ctx *retry.Context,
+	request *Request,
+	route routing,
+) (*http.Request, string, error) {
 	// Get the fully qualified address to the node that we are sending this request to
-	host, err := c.serviceHostForRequest(request, ctx.Attempt()-1)
+	host, node, err := c.serviceHostForRequest(request, route.offset(ctx.Attempt()-1), route)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get host for service '%s': %w", request.Service, err)
+		return nil, "", fmt.Errorf("failed to get host for service '%s': %w", request.Service, err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, string(request.Method), host+string(request.Endpoint),
 		bytes.NewReader(request.Body))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// If we received one or more non-nil query parameters ensure that they will be postfixed to the request URL.
@@ -872,34 +1554,131 @@ func (c *Client) prepare(ctx *retry.Context, request *Request) (*http.Request, e
 
 	// Using 'Set' overwrites an existing values set in the header, set these values first to that the settings below
 	// take precedence.
-	for key, value := range request.Header {
+	for key, value := range c.defaultHeaders {
 		req.Header.Set(key, value)
 	}
 
-	err = setAuthHeaders(host, c.authProvider.provider, req, c.logger)
-	if err != nil {
-		return nil, fmt.Errorf("failed to set auth headers: %w", err)
+	for key, value := range request.Header {
+		req.Header.Set(key, value)
 	}
 
 	// Set the content type for the request body. Note that we don't default to a value e.g. if must be set for every
 	// request otherwise the string zero value will be used.
 	req.Header.Set("Content-Type", string(request.ContentType))
 
-	return req, nil
+	if request.IfMatch != "" {
+		req.Header.Set("If-Match", request.IfMatch)
+	}
+
+	if request.IfNoneMatch != "" {
+		req.Header.Set("If-None-Match", request.IfNoneMatch)
+	}
+
+	if request.IdempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", request.IdempotencyKey)
+	}
+
+	// Advertise that we accept compressed responses, these are transparently decompressed upon receipt. We don't rely
+	// on the transport to do this for us so that we can log the size of compressed payloads.
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", encodingGzip)
+	}
+
+	signer := c.signer(host)
+
+	if request.OnBehalfOf != nil {
+		if signer != nil {
+			return nil, "", ErrOnBehalfOfIncompatibleAuth
+		}
+
+		req.Header.Set(headerOnBehalfOf, request.OnBehalfOf.header())
+	}
+
+	if signer == nil && c.sessions != nil {
+		signer = c.sessions
+	}
+
+	// Authenticate last, signers may need to sign the other headers
+	err = setAuthHeaders(host, c.userAgent(), c.authProvider.provider, signer, req, request.Body, c.logger)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to set auth headers: %w", err)
+	}
+
+	return req, node, nil
+}
+
+// userAgent returns the user agent which should be used for requests, this is the user agent returned by the auth
+// provider with the user provided suffix appended.
+func (c *Client) userAgent() string {
+	userAgent := c.authProvider.provider.GetUserAgent()
+
+	if c.userAgentSuffix == "" {
+		return userAgent
+	}
+
+	if userAgent == "" {
+		return c.userAgentSuffix
+	}
+
+	return userAgent + " " + c.userAgentSuffix
 }
 
-// serviceHostForRequest returns the service host that this request should be dispatched too.
-func (c *Client) serviceHostForRequest(request *Request, attempt int) (string, error) {
+// signer returns the signer which should be used to authenticate requests to the given host, or <nil> if HTTP basic
+// auth should be used.
+func (c *Client) signer(host string) Signer {
+	if c.signerForHost == nil {
+		return nil
+	}
+
+	return c.signerForHost(host)
+}
+
+// serviceHostForRequest returns the service host that this request should be dispatched too, and the host of the node
+// prior to any transformation (empty if the user specified the host).
+func (c *Client) serviceHostForRequest(
+	request *Request,
+	attempt int,
+	route routing,
+) (string, string, error) {
 	// If the user has specified a host, use that instead
 	if request.Host != "" {
-		return request.Host, nil
+		return request.Host, "", nil
+	}
+
+	if request.NodeUUID != "" {
+		return c.nodeServiceHost(request.NodeUUID, request.Service)
+	}
+
+	return c.serviceHost(request.Service, attempt, route)
+}
+
+// nodeServiceHost returns the host for the given service, running on the node with the given uuid.
+func (c *Client) nodeServiceHost(uuid string, service Service) (string, string, error) {
+	node, err := c.authProvider.GetNodeServiceHost(uuid, service)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get host for service '%s' on node '%s': %w", service, uuid, err)
 	}
 
-	return c.serviceHost(request.Service, attempt)
+	host, err := c.transformHost(node)
+
+	return host, node, err
 }
 
-// serviceHost returns a host that's running the given service.
-func (c *Client) serviceHost(service Service, attempt int) (string, error) {
+// serviceHost returns a host that's running the given service, which isn't in maintenance (or unreachable).
+func (c *Client) serviceHost(service Service, attempt int, route routing) (string, string, error) {
+	node, err := c.authProvider.getServiceHost(service, attempt, route)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get host for service '%s': %w", service, err)
+	}
+
+	host, err := c.transformHost(node)
+
+	return host, node, err
+}
+
+// transformHost returns the host which requests should be dispatched to, honoring the connection mode and hostname
+// transform.
+func (c *Client) transformHost(host string) (string, error) {
 	transform := func(before string) string {
 		if c.hostnameTransform == nil {
 			return before
@@ -914,11 +1693,6 @@ func (c *Client) serviceHost(service Service, attempt int) (string, error) {
 		return after
 	}
 
-	host, err := c.authProvider.GetServiceHost(service, attempt)
-	if err != nil {
-		return "", fmt.Errorf("failed to get host for service '%s': %w", service, err)
-	}
-
 	// This shouldn't really fail since we should be constructing valid hosts in the auth provider
 	parsed, err := url.Parse(host)
 	if err != nil {
@@ -926,7 +1700,7 @@ func (c *Client) serviceHost(service Service, attempt int) (string, error) {
 	}
 
 	if c.connectionMode != ConnectionModeLoopback {
-		return fmt.Sprintf("%s://%s:%s", parsed.Scheme, transform(parsed.Hostname()), parsed.Port()), nil
+		return fmt.Sprintf("%s://%s:%s%s", parsed.Scheme, transform(parsed.Hostname()), parsed.Port(), c.pathPrefix), nil
 	}
 
 	return "http://localhost:" + parsed.Port(), nil
@@ -957,8 +1731,13 @@ func (c *Client) perform(
 		client = newHTTPClient(max(0, timeout), client.Transport)
 	}
 
+	req, release := c.pool.trace(req)
+
 	resp, err := client.Do(req)
 	if err == nil {
+		// The connection remains in-use until the caller has finished with the response body
+		resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+
 		c.logger.Log(
 			ctx,
 			level,
@@ -972,6 +1751,8 @@ func (c *Client) perform(
 		return resp, nil
 	}
 
+	release()
+
 	c.logger.Error(
 		"failed to perform request",
 		"attempt", ctx.Attempt(),
@@ -985,13 +1766,23 @@ func (c *Client) perform(
 
 // GetServiceHost retrieves the address for a single node in the cluster which is running the provided service.
 func (c *Client) GetServiceHost(service Service) (string, error) {
-	return c.serviceHost(service, 0)
+	host, _, err := c.serviceHost(service, 0, routing{})
+	return host, err
 }
 
 // GetAllServiceHosts retrieves a list of all the nodes in the cluster that are running the provided service.
 func (c *Client) GetAllServiceHosts(service Service) ([]string, error) {
 	if !c.connectionMode.ThisNodeOnly() {
-		return c.authProvider.GetAllServiceHosts(service)
+		hosts, err := c.authProvider.GetAllServiceHosts(service)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range hosts {
+			hosts[i] += c.pathPrefix
+		}
+
+		return hosts, nil
 	}
 
 	host, err := c.GetServiceHost(service)
@@ -1004,6 +1795,8 @@ func (c *Client) GetAllServiceHosts(service Service) ([]string, error) {
 
 // Close releases any resources that are actively being consumed/used by the client.
 func (c *Client) Close() {
+	c.topology.close()
+
 	if c.ctx == nil || c.cancelFunc == nil {
 		return
 	}
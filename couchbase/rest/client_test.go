@@ -8,12 +8,15 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -31,6 +34,31 @@ var provider = &aprov.Static{
 	Credentials: aprov.Credentials{Username: "username", Password: "password"},
 }
 
+// flakyProvider is a provider which fails to get credentials the given number of times, before returning the static
+// test credentials.
+type flakyProvider struct {
+	lock     sync.Mutex
+	failures int
+	calls    int
+}
+
+func (f *flakyProvider) GetUserAgent() string {
+	return provider.GetUserAgent()
+}
+
+func (f *flakyProvider) GetCredentials(host string) (aprov.Credentials, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.calls++
+
+	if f.failures < 0 || f.calls <= f.failures {
+		return aprov.Credentials{}, errors.New("secret store unavailable")
+	}
+
+	return provider.GetCredentials(host)
+}
+
 // newTestClient returns a client which is boostrapped against the provided cluster.
 //
 // NOTE: Returns an error because some tests expect bootstrapping to fail.
@@ -63,13 +91,43 @@ func TestNewClientWithTransportDefaults(t *testing.T) {
 	require.Zero(t, transport.ExpectContinueTimeout)
 	require.Equal(t, 10*time.Second, transport.TLSHandshakeTimeout)
 	require.Equal(t, 100, transport.MaxIdleConns)
+	require.Equal(t, DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	require.Zero(t, transport.MaxConnsPerHost)
 	require.Equal(t, 90*time.Second, transport.IdleConnTimeout)
 	require.NotNil(t, transport.DialContext)
 	require.Nil(t, transport.Proxy)
 	require.NotNil(t, transport.TLSClientConfig)
+	require.NotNil(t, transport.TLSClientConfig.ClientSessionCache)
 	require.True(t, transport.ForceAttemptHTTP2)
 }
 
+func TestNewClientWithDialContext(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{})
+	defer cluster.Close()
+
+	var (
+		dialer = &net.Dialer{}
+		dials  atomic.Int64
+	)
+
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		dials.Add(1)
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		DialContext:      dial,
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.NotZero(t, dials.Load())
+}
+
 func TestNewClientWithThisNodeOnly(t *testing.T) {
 	cluster := NewTestCluster(t, TestClusterOptions{})
 	defer cluster.Close()
@@ -114,9 +172,12 @@ func TestNewClient(t *testing.T) {
 	defer client.Close()
 
 	// Don't compare the time attribute from the config manager
-	client.authProvider.manager.last = nil
-	client.authProvider.manager.signal = nil
-	client.authProvider.manager.cond = nil
+	client.authProvider.manager.(*ClusterConfigManager).last = nil
+	client.authProvider.manager.(*ClusterConfigManager).signal = nil
+	client.authProvider.manager.(*ClusterConfigManager).cond = nil
+
+	// Don't compare the tracked node health, which depends on the latency of the requests made during bootstrapping
+	client.authProvider.health = nil
 
 	expected := &AuthProvider{
 		resolved: &connstr.ResolvedConnectionString{
@@ -134,6 +195,7 @@ func TestNewClient(t *testing.T) {
 				}},
 			},
 			maxAge: DefaultCCMaxAge,
+			clock:  systemClock{},
 		},
 	}
 
@@ -157,7 +219,7 @@ func TestNewClientThisNodeOnly(t *testing.T) {
 	require.NoError(t, err)
 
 	defer client.Close()
-	require.Len(t, client.authProvider.manager.config.Nodes, 1)
+	require.Len(t, client.authProvider.manager.(*ClusterConfigManager).config.Nodes, 1)
 }
 
 // This is a smoke test to assert that the cluster config poller doesn't attempt to dereference a <nil> pointer. See
@@ -217,9 +279,12 @@ func TestNewClientFailedToBootstrapAgainstHost(t *testing.T) {
 	require.NoError(t, err)
 
 	// Don't compare the time attribute from the config manager
-	client.authProvider.manager.last = nil
-	client.authProvider.manager.signal = nil
-	client.authProvider.manager.cond = nil
+	client.authProvider.manager.(*ClusterConfigManager).last = nil
+	client.authProvider.manager.(*ClusterConfigManager).signal = nil
+	client.authProvider.manager.(*ClusterConfigManager).cond = nil
+
+	// Don't compare the tracked node health, which depends on the latency of the requests made during bootstrapping
+	client.authProvider.health = nil
 
 	expected := &AuthProvider{
 		resolved: &connstr.ResolvedConnectionString{
@@ -240,6 +305,7 @@ func TestNewClientFailedToBootstrapAgainstHost(t *testing.T) {
 				Nodes: cluster.Nodes(),
 			},
 			maxAge: DefaultCCMaxAge,
+			clock:  systemClock{},
 		},
 	}
 
@@ -306,6 +372,46 @@ func TestNewClientFailedToBootstrapAgainstAnyHostForbidden(t *testing.T) {
 	require.NotNil(t, bootstrapFailure.ErrAuthorization)
 }
 
+func TestNewClientFailedToBootstrapCredentialsError(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{})
+	defer cluster.Close()
+
+	flaky := &flakyProvider{failures: -1}
+
+	_, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         flaky,
+	})
+
+	var bootstrapFailure *BootstrapFailureError
+
+	require.ErrorAs(t, err, &bootstrapFailure)
+	require.Nil(t, bootstrapFailure.ErrAuthentication)
+	require.Nil(t, bootstrapFailure.ErrAuthorization)
+
+	var errCredentials *CredentialsError
+
+	require.ErrorAs(t, bootstrapFailure.ErrCredentials, &errCredentials)
+	require.Contains(t, err.Error(), "secret store unavailable")
+	require.Equal(t, 3, flaky.calls)
+}
+
+func TestNewClientRetriesGettingCredentials(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{})
+	defer cluster.Close()
+
+	flaky := &flakyProvider{failures: 2}
+
+	_, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         flaky,
+	})
+	require.NoError(t, err)
+	require.Greater(t, flaky.calls, 2)
+}
+
 func TestNewClientForcedExternalNetworkMode(t *testing.T) {
 	cluster := NewTestCluster(t, TestClusterOptions{
 		Nodes: TestNodes{{AltAddress: true}},
@@ -322,9 +428,12 @@ func TestNewClientForcedExternalNetworkMode(t *testing.T) {
 	defer client.Close()
 
 	// Don't compare the time attribute from the config manager
-	client.authProvider.manager.last = nil
-	client.authProvider.manager.signal = nil
-	client.authProvider.manager.cond = nil
+	client.authProvider.manager.(*ClusterConfigManager).last = nil
+	client.authProvider.manager.(*ClusterConfigManager).signal = nil
+	client.authProvider.manager.(*ClusterConfigManager).cond = nil
+
+	// Don't compare the tracked node health, which depends on the latency of the requests made during bootstrapping
+	client.authProvider.health = nil
 
 	expected := &AuthProvider{
 		resolved: &connstr.ResolvedConnectionString{
@@ -343,6 +452,7 @@ func TestNewClientForcedExternalNetworkMode(t *testing.T) {
 				Nodes: cluster.Nodes(),
 			},
 			maxAge: DefaultCCMaxAge,
+			clock:  systemClock{},
 		},
 	}
 
@@ -379,9 +489,12 @@ func TestNewClientTLS(t *testing.T) {
 	defer client.Close()
 
 	// Don't compare the time attribute from the config manager
-	client.authProvider.manager.last = nil
-	client.authProvider.manager.signal = nil
-	client.authProvider.manager.cond = nil
+	client.authProvider.manager.(*ClusterConfigManager).last = nil
+	client.authProvider.manager.(*ClusterConfigManager).signal = nil
+	client.authProvider.manager.(*ClusterConfigManager).cond = nil
+
+	// Don't compare the tracked node health, which depends on the latency of the requests made during bootstrapping
+	client.authProvider.health = nil
 
 	expected := &AuthProvider{
 		resolved: &connstr.ResolvedConnectionString{
@@ -397,6 +510,7 @@ func TestNewClientTLS(t *testing.T) {
 				Nodes: cluster.Nodes(),
 			},
 			maxAge: DefaultCCMaxAge,
+			clock:  systemClock{},
 		},
 	}
 
@@ -542,6 +656,147 @@ func TestClientExecuteWithOverrideHost(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestClientExecuteWithMaxConcurrentRequests(t *testing.T) {
+	var active, peak atomic.Int64
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, _ *http.Request) {
+		defer active.Add(-1)
+
+		n := active.Add(1)
+
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString:      cluster.URL(),
+		DisableCCP:            true,
+		Provider:              provider,
+		MaxConcurrentRequests: 2,
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := client.Execute(&Request{
+				ContentType:        ContentTypeURLEncoded,
+				Endpoint:           "/test",
+				ExpectedStatusCode: http.StatusOK,
+				Method:             http.MethodGet,
+				Service:            ServiceManagement,
+			})
+			require.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	require.LessOrEqual(t, peak.Load(), int64(2))
+	require.Zero(t, client.InFlightRequests())
+}
+
+// inFlightClock is a 'testClock' which records the number of in-flight requests when waiting.
+type inFlightClock struct {
+	*testClock
+	client   *Client
+	inFlight []int64
+}
+
+func (i *inFlightClock) After(d time.Duration) <-chan time.Time {
+	i.inFlight = append(i.inFlight, i.client.InFlightRequests())
+	return i.testClock.After(d)
+}
+
+func TestClientExecuteRetryAfterReleasesConcurrencyLimit(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(
+		http.MethodGet,
+		"/test",
+		NewTestHandlerWithRetries(t, 1, http.StatusServiceUnavailable, http.StatusOK, "30", make([]byte, 0)),
+	)
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString:      cluster.URL(),
+		DisableCCP:            true,
+		Provider:              provider,
+		MaxConcurrentRequests: 1,
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	clock := &inFlightClock{testClock: &testClock{now: time.Now()}, client: client}
+	client.clock = clock
+
+	_, err = client.Execute(&Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+
+	// The rejected request shouldn't hold the only concurrency slot whilst waiting for the 'Retry-After' duration
+	require.Equal(t, []int64{0}, clock.inFlight)
+	require.Zero(t, client.InFlightRequests())
+}
+
+func TestClientDoInFlightRequests(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusOK, []byte("body")))
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	resp, err := client.Do(context.Background(), &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), client.InFlightRequests())
+
+	client.cleanupResp(resp)
+	require.Zero(t, client.InFlightRequests())
+}
+
 func TestClientExecuteRetryWithCCUpdate(t *testing.T) {
 	for _, disableCCP := range []bool{false, true} {
 		t.Run(fmt.Sprintf(`{"disable_ccp":"%t"}`, disableCCP), func(t *testing.T) {
@@ -570,7 +825,7 @@ func TestClientExecuteRetryWithCCUpdate(t *testing.T) {
 			var retriesExhausted *RetriesExhaustedError
 
 			require.ErrorAs(t, err, &retriesExhausted)
-			require.Equal(t, int64(3), client.authProvider.manager.config.Revision)
+			require.Equal(t, int64(3), client.authProvider.manager.(*ClusterConfigManager).config.Revision)
 		})
 	}
 }
@@ -603,7 +858,7 @@ func TestClientExecuteRetryResponseWithCCUpdate(t *testing.T) {
 			var retriesExhausted *RetriesExhaustedError
 
 			require.ErrorAs(t, err, &retriesExhausted)
-			require.Equal(t, int64(3), client.authProvider.manager.config.Revision)
+			require.Equal(t, int64(3), client.authProvider.manager.(*ClusterConfigManager).config.Revision)
 		})
 	}
 }
@@ -679,7 +934,7 @@ func TestClientExecuteWithModeLoopback(t *testing.T) {
 
 	// Any requests should timeout if they attempt to use the hostname from the payload, however, we're using loopback
 	// mode so they should be ignored.
-	for _, node := range client.authProvider.manager.config.Nodes {
+	for _, node := range client.authProvider.manager.(*ClusterConfigManager).config.Nodes {
 		node.Hostname = "not-a-hostname"
 	}
 
@@ -780,6 +1035,12 @@ func TestClientExecuteWithRetryAfter(t *testing.T) {
 			after:  func() string { return "1" },
 			waited: true,
 		},
+		{
+			name:   "IntegerNumberOfSeconds429",
+			status: http.StatusTooManyRequests,
+			after:  func() string { return "1" },
+			waited: true,
+		},
 		{
 			name:   "IntegerNumberOfSecondsNot503",
 			status: http.StatusGatewayTimeout,
@@ -847,9 +1108,14 @@ func TestClientExecuteWithRetryAfter(t *testing.T) {
 	}
 }
 
-func TestClientExecuteStandardError(t *testing.T) {
+func TestClientExecuteWithRetryAfterUsesClock(t *testing.T) {
 	handlers := make(TestHandlers)
-	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusOK, []byte("body")))
+
+	handlers.Add(
+		http.MethodGet,
+		"/test",
+		NewTestHandlerWithRetries(t, 1, http.StatusServiceUnavailable, http.StatusOK, "30", make([]byte, 0)),
+	)
 
 	cluster := NewTestCluster(t, TestClusterOptions{
 		Handlers: handlers,
@@ -858,34 +1124,36 @@ func TestClientExecuteStandardError(t *testing.T) {
 
 	request := &Request{
 		ContentType:        ContentTypeURLEncoded,
-		Endpoint:           EndpointPools,
-		ExpectedStatusCode: http.StatusTeapot, // We will not get this status code, so we should error out
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
 		Method:             http.MethodGet,
 		Service:            ServiceManagement,
 	}
 
-	expected := &Response{
-		StatusCode: http.StatusOK,
-		Body:       []byte(`{"isEnterprise":false,"uuid":"","isDeveloperPreview":false}` + "\n"),
-	}
-
 	client, err := newTestClient(cluster, true)
 	require.NoError(t, err)
-
 	defer client.Close()
 
-	actual, err := client.Execute(request)
-	require.Error(t, err)
-	require.Equal(t, expected, actual)
+	clock := &testClock{now: time.Now()}
+	client.clock = clock
 
-	var unexpectedStatus *UnexpectedStatusCodeError
+	_, err = client.Execute(request)
+	require.NoError(t, err)
 
-	require.ErrorAs(t, err, &unexpectedStatus)
+	waits := clock.Waits()
+	require.Len(t, waits, 1)
+	require.GreaterOrEqual(t, waits[0], 30*time.Second)
+	require.LessOrEqual(t, waits[0], 33*time.Second)
 }
 
-func TestClientExecuteWithNonIdepotentRequest(t *testing.T) {
+func TestClientExecuteWithRetryAfterExceedsDeadline(t *testing.T) {
 	handlers := make(TestHandlers)
-	handlers.Add(http.MethodPost, "/test", NewTestHandler(t, http.StatusTooEarly, make([]byte, 0)))
+
+	handlers.Add(
+		http.MethodGet,
+		"/test",
+		NewTestHandlerWithRetries(t, 1, http.StatusTooManyRequests, http.StatusOK, "30", make([]byte, 0)),
+	)
 
 	cluster := NewTestCluster(t, TestClusterOptions{
 		Handlers: handlers,
@@ -896,31 +1164,32 @@ func TestClientExecuteWithNonIdepotentRequest(t *testing.T) {
 		ContentType:        ContentTypeURLEncoded,
 		Endpoint:           "/test",
 		ExpectedStatusCode: http.StatusOK,
-		Method:             http.MethodPost,
-		RetryOnStatusCodes: []int{http.StatusTooEarly},
+		Method:             http.MethodGet,
 		Service:            ServiceManagement,
 	}
 
 	client, err := newTestClient(cluster, true)
 	require.NoError(t, err)
-
 	defer client.Close()
 
-	_, err = client.Execute(request)
-	require.Error(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	var (
-		retriesExhausted *RetriesExhaustedError
-		unexpectedStatus *UnexpectedStatusCodeError
-	)
+	start := time.Now()
 
-	require.False(t, errors.As(err, &retriesExhausted)) // testify doesn't appear to have a 'NotErrorAs' function...
-	require.ErrorAs(t, err, &unexpectedStatus)
+	_, err = client.ExecuteWithContext(ctx, request)
+	require.ErrorIs(t, err, ErrDeadlineWouldBeExceeded)
+	require.Less(t, time.Since(start), time.Second)
 }
 
-func TestClientExecuteWithRetriesExhausted(t *testing.T) {
+func TestClientExecuteWithRetryAfterExceedsTotalTimeout(t *testing.T) {
 	handlers := make(TestHandlers)
-	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusTooEarly, make([]byte, 0)))
+
+	handlers.Add(
+		http.MethodGet,
+		"/test",
+		NewTestHandlerWithRetries(t, 1, http.StatusServiceUnavailable, http.StatusOK, "30", make([]byte, 0)),
+	)
 
 	cluster := NewTestCluster(t, TestClusterOptions{
 		Handlers: handlers,
@@ -932,26 +1201,30 @@ func TestClientExecuteWithRetriesExhausted(t *testing.T) {
 		Endpoint:           "/test",
 		ExpectedStatusCode: http.StatusOK,
 		Method:             http.MethodGet,
-		RetryOnStatusCodes: []int{http.StatusTooEarly},
 		Service:            ServiceManagement,
+		TotalTimeout:       5 * time.Second,
 	}
 
 	client, err := newTestClient(cluster, true)
 	require.NoError(t, err)
-
 	defer client.Close()
 
-	_, err = client.Execute(request)
-	require.Error(t, err)
-
-	var retriesExhausted *RetriesExhaustedError
+	start := time.Now()
 
-	require.ErrorAs(t, err, &retriesExhausted)
+	_, err = client.Execute(request)
+	require.ErrorIs(t, err, ErrDeadlineWouldBeExceeded)
+	require.Less(t, time.Since(start), time.Second)
 }
 
-func TestClientExecuteSpecificServiceNotAvailable(t *testing.T) {
+func TestClientExecuteWithTotalTimeout(t *testing.T) {
 	handlers := make(TestHandlers)
-	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusTooEarly, make([]byte, 0)))
+
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, _ *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	handlers.Add(http.MethodGet, "/ok", NewTestHandler(t, http.StatusOK, []byte("body")))
 
 	cluster := NewTestCluster(t, TestClusterOptions{
 		Handlers: handlers,
@@ -963,25 +1236,31 @@ func TestClientExecuteSpecificServiceNotAvailable(t *testing.T) {
 		Endpoint:           "/test",
 		ExpectedStatusCode: http.StatusOK,
 		Method:             http.MethodGet,
-		Service:            ServiceAnalytics,
+		Service:            ServiceManagement,
+		TotalTimeout:       200 * time.Millisecond,
 	}
 
 	client, err := newTestClient(cluster, true)
 	require.NoError(t, err)
-
 	defer client.Close()
 
+	start := time.Now()
+
 	_, err = client.Execute(request)
-	require.Error(t, err)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
 
-	var notAvailable *ServiceNotAvailableError
+	// The total timeout shouldn't affect reading the body of a successful response
+	request.Endpoint = "/ok"
 
-	require.ErrorAs(t, err, &notAvailable)
+	response, err := client.Execute(request)
+	require.NoError(t, err)
+	require.Equal(t, []byte("body"), response.Body)
 }
 
-func TestClientExecuteAuthError(t *testing.T) {
+func TestClientExecuteStandardError(t *testing.T) {
 	handlers := make(TestHandlers)
-	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusUnauthorized, make([]byte, 0)))
+	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusOK, []byte("body")))
 
 	cluster := NewTestCluster(t, TestClusterOptions{
 		Handlers: handlers,
@@ -990,28 +1269,34 @@ func TestClientExecuteAuthError(t *testing.T) {
 
 	request := &Request{
 		ContentType:        ContentTypeURLEncoded,
-		Endpoint:           "/test",
-		ExpectedStatusCode: http.StatusOK,
+		Endpoint:           EndpointPools,
+		ExpectedStatusCode: http.StatusTeapot, // We will not get this status code, so we should error out
 		Method:             http.MethodGet,
 		Service:            ServiceManagement,
 	}
 
+	expected := &Response{
+		StatusCode: http.StatusOK,
+		Body:       []byte(`{"isEnterprise":false,"uuid":"","isDeveloperPreview":false}` + "\n"),
+	}
+
 	client, err := newTestClient(cluster, true)
 	require.NoError(t, err)
 
 	defer client.Close()
 
-	_, err = client.Execute(request)
+	actual, err := client.Execute(request)
 	require.Error(t, err)
+	require.Equal(t, expected, actual)
 
-	var unauthorized *AuthenticationError
+	var unexpectedStatus *UnexpectedStatusCodeError
 
-	require.ErrorAs(t, err, &unauthorized)
+	require.ErrorAs(t, err, &unexpectedStatus)
 }
 
-func TestClientExecuteInternalServerError(t *testing.T) {
+func TestClientExecuteWithNonIdepotentRequest(t *testing.T) {
 	handlers := make(TestHandlers)
-	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusInternalServerError, []byte("response body")))
+	handlers.Add(http.MethodPost, "/test", NewTestHandler(t, http.StatusTooEarly, make([]byte, 0)))
 
 	cluster := NewTestCluster(t, TestClusterOptions{
 		Handlers: handlers,
@@ -1022,7 +1307,133 @@ func TestClientExecuteInternalServerError(t *testing.T) {
 		ContentType:        ContentTypeURLEncoded,
 		Endpoint:           "/test",
 		ExpectedStatusCode: http.StatusOK,
-		Method:             http.MethodGet,
+		Method:             http.MethodPost,
+		RetryOnStatusCodes: []int{http.StatusTooEarly},
+		Service:            ServiceManagement,
+	}
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.Execute(request)
+	require.Error(t, err)
+
+	var (
+		retriesExhausted *RetriesExhaustedError
+		unexpectedStatus *UnexpectedStatusCodeError
+	)
+
+	require.False(t, errors.As(err, &retriesExhausted)) // testify doesn't appear to have a 'NotErrorAs' function...
+	require.ErrorAs(t, err, &unexpectedStatus)
+}
+
+func TestClientExecuteWithRetriesExhausted(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusTooEarly, make([]byte, 0)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		RetryOnStatusCodes: []int{http.StatusTooEarly},
+		Service:            ServiceManagement,
+	}
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.Execute(request)
+	require.Error(t, err)
+
+	var retriesExhausted *RetriesExhaustedError
+
+	require.ErrorAs(t, err, &retriesExhausted)
+}
+
+func TestClientExecuteSpecificServiceNotAvailable(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusTooEarly, make([]byte, 0)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceAnalytics,
+	}
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.Execute(request)
+	require.Error(t, err)
+
+	var notAvailable *ServiceNotAvailableError
+
+	require.ErrorAs(t, err, &notAvailable)
+}
+
+func TestClientExecuteAuthError(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusUnauthorized, make([]byte, 0)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.Execute(request)
+	require.Error(t, err)
+
+	var unauthorized *AuthenticationError
+
+	require.ErrorAs(t, err, &unauthorized)
+}
+
+func TestClientExecuteInternalServerError(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusInternalServerError, []byte("response body")))
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
 		Service:            ServiceManagement,
 	}
 
@@ -1040,6 +1451,46 @@ func TestClientExecuteInternalServerError(t *testing.T) {
 	require.Equal(t, []byte("response body"), internalServerError.body)
 }
 
+func TestClientExecuteErrorResponseDetails(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("X-Request-Id", "d5b1c8e2")
+		writer.Header().Set("Retry-After", "5")
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusConflict)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.Execute(request)
+	require.Error(t, err)
+
+	var unexpected *UnexpectedStatusCodeError
+
+	require.ErrorAs(t, err, &unexpected)
+	require.Equal(t, http.StatusConflict, unexpected.Status)
+	require.Equal(t, "d5b1c8e2", unexpected.RequestID())
+	require.Equal(t, "5", unexpected.RetryAfter())
+	require.Equal(t, "application/json", unexpected.ContentType())
+	require.Equal(t, fmt.Sprintf("%s:%d", cluster.Address(), cluster.Port()), unexpected.Host())
+}
+
 func TestClientExecute404Status(t *testing.T) {
 	handlers := make(TestHandlers)
 	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusNotFound, make([]byte, 0)))
@@ -1070,6 +1521,228 @@ func TestClientExecute404Status(t *testing.T) {
 	require.ErrorAs(t, err, &endpointNotFound)
 }
 
+func TestClientExecuteConditional(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodPost, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("If-Match") != `"1"` {
+			writer.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+
+		writer.Header().Set("ETag", `"2"`)
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+		IfMatch:            `"1"`,
+	}
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	response, err := client.Execute(request)
+	require.NoError(t, err)
+	require.Equal(t, `"2"`, response.ETag)
+
+	request.IfMatch = `"2"`
+
+	_, err = client.Execute(request)
+	require.Error(t, err)
+	require.True(t, IsPreconditionFailed(err))
+}
+
+func TestClientExecuteWithIdempotencyKey(t *testing.T) {
+	var (
+		handlers = make(TestHandlers)
+		keys     []string
+	)
+
+	handlers.Add(http.MethodPost, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		keys = append(keys, request.Header.Get("Idempotency-Key"))
+
+		// Fail the first attempt, non-idempotent methods are only retried when an idempotency key is provided
+		if len(keys) == 1 {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+		IdempotencyKey:     "key",
+	}
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.Execute(request)
+	require.NoError(t, err)
+	require.Equal(t, []string{"key", "key"}, keys)
+}
+
+func TestClientExecuteWithNodeUUID(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusOK, []byte("body")))
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Nodes:    TestNodes{{UUID: "uuid1"}, {UUID: "uuid2", Services: []Service{ServiceQuery}}},
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		NodeUUID:           "uuid2",
+		Service:            ServiceQuery,
+	}
+
+	response, err := client.Execute(request)
+	require.NoError(t, err)
+	require.Equal(t, []byte("body"), response.Body)
+
+	request.NodeUUID = "uuid1"
+
+	_, err = client.Execute(request)
+	require.True(t, IsServiceNotAvailable(err))
+
+	request.NodeUUID = "uuid3"
+
+	_, err = client.Execute(request)
+	require.True(t, IsNodeNotFound(err))
+}
+
+func TestClientExecuteWithDefaultHeaders(t *testing.T) {
+	var (
+		handlers = make(TestHandlers)
+		header   http.Header
+	)
+
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		header = request.Header.Clone()
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		DefaultHeaders:   Header{"X-Run-Id": "run", "X-Tool": "tool"},
+		UserAgentSuffix:  "tool/1.0.0",
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Header:             Header{"X-Tool": "override"},
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	_, err = client.Execute(request)
+	require.NoError(t, err)
+	require.Equal(t, "run", header.Get("X-Run-Id"))
+	require.Equal(t, "override", header.Get("X-Tool"))
+	require.Equal(t, "user-agent tool/1.0.0", header.Get("User-Agent"))
+}
+
+func TestClientUserAgent(t *testing.T) {
+	type test struct {
+		name      string
+		userAgent string
+		suffix    string
+		expected  string
+	}
+
+	tests := []test{
+		{name: "NoSuffix", userAgent: "agent", expected: "agent"},
+		{name: "Suffix", userAgent: "agent", suffix: "suffix", expected: "agent suffix"},
+		{name: "OnlySuffix", suffix: "suffix", expected: "suffix"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &Client{
+				authProvider:    &AuthProvider{provider: &aprov.Static{UserAgent: test.userAgent}},
+				userAgentSuffix: test.suffix,
+			}
+
+			require.Equal(t, test.expected, client.userAgent())
+		})
+	}
+}
+
+func TestClientExecuteWithoutIdempotencyKeyNotRetried(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(
+		http.MethodPost,
+		"/test",
+		NewTestHandlerWithRetries(t, 1, http.StatusServiceUnavailable, http.StatusOK, "", nil),
+	)
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+	}
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.Execute(request)
+	require.Error(t, err)
+}
+
 func TestClientExecuteUnexpectedEOF(t *testing.T) {
 	handlers := make(TestHandlers)
 	handlers.Add(http.MethodGet, "/test", NewTestHandlerWithEOF(t))
@@ -1486,6 +2159,81 @@ func TestGetServiceHost(t *testing.T) {
 	require.Equal(t, cluster.URL(), host)
 }
 
+func TestClientPathPrefix(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusOK, []byte("body")))
+	handlers.Add(http.MethodGet, "/stream", NewTestHandlerWithStream(t, 2, []byte(`"payload"`)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers:   handlers,
+		PathPrefix: "/couchbase",
+	})
+	defer cluster.Close()
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		PathPrefix:       "couchbase/",
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	host, err := client.GetServiceHost(ServiceManagement)
+	require.NoError(t, err)
+	require.Equal(t, cluster.URL()+"/couchbase", host)
+
+	hosts, err := client.GetAllServiceHosts(ServiceManagement)
+	require.NoError(t, err)
+	require.Equal(t, []string{cluster.URL() + "/couchbase"}, hosts)
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	response, err := client.Execute(request)
+	require.NoError(t, err)
+	require.Equal(t, []byte("body"), response.Body)
+
+	// A host returned by the client already includes the prefix, so must not have it added again
+	request.Host = host
+
+	_, err = client.Execute(request)
+	require.NoError(t, err)
+
+	stream, err := client.ExecuteStream(&Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/stream",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+
+	var responses int
+
+	for response := range stream {
+		require.NoError(t, response.Error)
+
+		responses++
+	}
+
+	require.Equal(t, 2, responses)
+}
+
+func TestClientPathPrefixMissing(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{PathPrefix: "/couchbase"})
+	defer cluster.Close()
+
+	_, err := newTestClient(cluster, true)
+	require.Error(t, err)
+}
+
 func TestGetServiceHostHostnameTransform(t *testing.T) {
 	cluster := NewTestCluster(t, TestClusterOptions{})
 	defer cluster.Close()
@@ -1748,11 +2496,11 @@ func TestClientPollCC(t *testing.T) {
 
 	// The test cluster currently doesn't return a revision, we can exploit this to detect whether the cluster config
 	// has been updated or not.
-	client.authProvider.manager.config.Revision = math.MaxInt64
+	client.authProvider.manager.(*ClusterConfigManager).config.Revision = math.MaxInt64
 
 	time.Sleep(100 * time.Millisecond)
 
-	require.Equal(t, int64(math.MaxInt64), client.authProvider.manager.config.Revision)
+	require.Equal(t, int64(math.MaxInt64), client.authProvider.manager.(*ClusterConfigManager).config.Revision)
 }
 
 func TestClientPollCCOldRevisionIgnore(t *testing.T) {
@@ -1767,11 +2515,11 @@ func TestClientPollCCOldRevisionIgnore(t *testing.T) {
 
 	defer client.Close()
 
-	rev := client.authProvider.manager.config.Revision
+	rev := client.authProvider.manager.(*ClusterConfigManager).config.Revision
 
 	time.Sleep(75 * time.Millisecond)
 
-	require.Equal(t, rev+1, client.authProvider.manager.config.Revision)
+	require.Equal(t, rev+1, client.authProvider.manager.(*ClusterConfigManager).config.Revision)
 }
 
 func TestClientUpdateCC(t *testing.T) {
@@ -1783,10 +2531,10 @@ func TestClientUpdateCC(t *testing.T) {
 
 	defer client.Close()
 
-	rev := client.authProvider.manager.config.Revision
+	rev := client.authProvider.manager.(*ClusterConfigManager).config.Revision
 
 	require.NoError(t, client.updateCC())
-	require.Equal(t, rev+1, client.authProvider.manager.config.Revision)
+	require.Equal(t, rev+1, client.authProvider.manager.(*ClusterConfigManager).config.Revision)
 }
 
 func TestClientUpdateCCExhaustedClusterNodes(t *testing.T) {
@@ -1798,11 +2546,11 @@ func TestClientUpdateCCExhaustedClusterNodes(t *testing.T) {
 
 	defer client.Close()
 
-	client.authProvider.manager.config.Nodes = make(Nodes, 0)
-	rev := client.authProvider.manager.config.Revision
+	client.authProvider.manager.(*ClusterConfigManager).config.Nodes = make(Nodes, 0)
+	rev := client.authProvider.manager.(*ClusterConfigManager).config.Revision
 
 	require.ErrorIs(t, client.updateCC(), ErrExhaustedClusterNodes)
-	require.Equal(t, rev, client.authProvider.manager.config.Revision)
+	require.Equal(t, rev, client.authProvider.manager.(*ClusterConfigManager).config.Revision)
 }
 
 func TestClientUpdateCCFromNode(t *testing.T) {
@@ -1814,10 +2562,10 @@ func TestClientUpdateCCFromNode(t *testing.T) {
 
 	defer client.Close()
 
-	rev := client.authProvider.manager.config.Revision
+	rev := client.authProvider.manager.(*ClusterConfigManager).config.Revision
 
-	require.NoError(t, client.updateCCFromNode(client.authProvider.manager.config.Nodes[0]))
-	require.Equal(t, rev+1, client.authProvider.manager.config.Revision)
+	require.NoError(t, client.updateCCFromNode(client.authProvider.manager.(*ClusterConfigManager).config.Nodes[0]))
+	require.Equal(t, rev+1, client.authProvider.manager.(*ClusterConfigManager).config.Revision)
 }
 
 func TestClientUpdateCCFromNodeThisNodeOnly(t *testing.T) {
@@ -1832,11 +2580,11 @@ func TestClientUpdateCCFromNodeThisNodeOnly(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	rev := client.authProvider.manager.config.Revision
+	rev := client.authProvider.manager.(*ClusterConfigManager).config.Revision
 
-	require.NoError(t, client.updateCCFromNode(client.authProvider.manager.config.Nodes[0]))
-	require.Equal(t, rev+1, client.authProvider.manager.config.Revision)
-	require.Len(t, client.authProvider.manager.config.Nodes, 1)
+	require.NoError(t, client.updateCCFromNode(client.authProvider.manager.(*ClusterConfigManager).config.Nodes[0]))
+	require.Equal(t, rev+1, client.authProvider.manager.(*ClusterConfigManager).config.Revision)
+	require.Len(t, client.authProvider.manager.(*ClusterConfigManager).config.Nodes, 1)
 }
 
 func TestClientUpdateCCFromHost(t *testing.T) {
@@ -1848,11 +2596,11 @@ func TestClientUpdateCCFromHost(t *testing.T) {
 
 	defer client.Close()
 
-	rev := client.authProvider.manager.config.Revision
+	rev := client.authProvider.manager.(*ClusterConfigManager).config.Revision
 
 	require.NoError(t, client.updateCCFromHost(fmt.Sprintf("http://localhost:%d", cluster.Port())))
-	require.Equal(t, rev+1, client.authProvider.manager.config.Revision)
-	require.Len(t, client.authProvider.manager.config.Nodes, 4)
+	require.Equal(t, rev+1, client.authProvider.manager.(*ClusterConfigManager).config.Revision)
+	require.Len(t, client.authProvider.manager.(*ClusterConfigManager).config.Nodes, 4)
 }
 
 func TestClientUpdateCCFromHostThisNodeOnly(t *testing.T) {
@@ -1867,11 +2615,11 @@ func TestClientUpdateCCFromHostThisNodeOnly(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	rev := client.authProvider.manager.config.Revision
+	rev := client.authProvider.manager.(*ClusterConfigManager).config.Revision
 
 	require.NoError(t, client.updateCCFromHost(fmt.Sprintf("http://localhost:%d", cluster.Port())))
-	require.Equal(t, rev+1, client.authProvider.manager.config.Revision)
-	require.Len(t, client.authProvider.manager.config.Nodes, 1)
+	require.Equal(t, rev+1, client.authProvider.manager.(*ClusterConfigManager).config.Revision)
+	require.Len(t, client.authProvider.manager.(*ClusterConfigManager).config.Nodes, 1)
 }
 
 func TestClientValidHost(t *testing.T) {
@@ -2040,14 +2788,14 @@ func TestClientWaitUntilUpdated(t *testing.T) {
 			})
 			require.NoError(t, err)
 
-			rev := client.authProvider.manager.config.Revision
+			rev := client.authProvider.manager.(*ClusterConfigManager).config.Revision
 
 			client.waitUntilUpdated(context.Background())
 
 			if connectionMode == ConnectionModeThisNodeOnly || connectionMode == ConnectionModeLoopback {
-				require.Equal(t, rev, client.authProvider.manager.config.Revision)
+				require.Equal(t, rev, client.authProvider.manager.(*ClusterConfigManager).config.Revision)
 			} else {
-				require.NotEqual(t, rev, client.authProvider.manager.config.Revision)
+				require.NotEqual(t, rev, client.authProvider.manager.(*ClusterConfigManager).config.Revision)
 			}
 		})
 	}
@@ -0,0 +1,38 @@
+package rest
+
+import "time"
+
+// Clock provides the current time, and allows waiting for durations to elapse. The client uses it for its time
+// dependent behavior (e.g. waiting before retrying requests, and polling for cluster config updates), allowing that
+// behavior to be tested deterministically.
+//
+// NOTE: This is the subset of 'timeprovider.TimeProvider' used by this package, so any time provider (e.g. a
+// 'timeprovider.FakeClock') may be passed as-is, and a single fake may drive both this package and the caller. It
+// should become an alias of that interface once the released 'types/v2' module includes the 'timeprovider' package.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the given duration to elapse, then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is a 'Clock' which uses the system clock.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// clockOrDefault returns the given clock, or the system clock if it's <nil>.
+func clockOrDefault(clock Clock) Clock {
+	if clock == nil {
+		return systemClock{}
+	}
+
+	return clock
+}
@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"sync"
+	"time"
+)
+
+// testClock is a 'Clock' where waits complete immediately, recording the requested durations.
+type testClock struct {
+	now time.Time
+
+	// advance indicates whether waiting should advance the current time.
+	advance bool
+
+	lock  sync.Mutex
+	waits []time.Duration
+}
+
+func (t *testClock) Now() time.Time {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.now
+}
+
+func (t *testClock) After(d time.Duration) <-chan time.Time {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.waits = append(t.waits, d)
+
+	at := t.now.Add(d)
+
+	if t.advance {
+		t.now = at
+	}
+
+	ch := make(chan time.Time, 1)
+	ch <- at
+
+	return ch
+}
+
+// Waits returns the durations which have been waited for.
+func (t *testClock) Waits() []time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return append([]time.Duration(nil), t.waits...)
+}
@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// cachedClusterConfig is the payload persisted to disk by the 'clusterConfigCache'.
+type cachedClusterConfig struct {
+	// UUID is the uuid of the cluster the config was fetched from, used to validate the config before use.
+	UUID string `json:"uuid"`
+
+	// Host is the bootstrap host the client was connected to when the config was persisted.
+	Host string `json:"host"`
+
+	// ThisNodeOnly indicates whether the persisted config was filtered to only contain the bootstrap node.
+	ThisNodeOnly bool `json:"this_node_only"`
+
+	Config *ClusterConfig `json:"config"`
+}
+
+// matches returns a boolean indicating whether the given cached config is from the same cluster/host and has the same
+// revision, meaning there's no need to persist it again.
+func (c *cachedClusterConfig) matches(other *cachedClusterConfig) bool {
+	return c != nil &&
+		c.UUID == other.UUID &&
+		c.Host == other.Host &&
+		c.ThisNodeOnly == other.ThisNodeOnly &&
+		c.Config.Revision == other.Config.Revision
+}
+
+// clusterConfigCache persists the last known cluster config to a file, allowing short-lived clients to skip fetching
+// the cluster config when the topology of the cluster hasn't changed.
+type clusterConfigCache struct {
+	path string
+
+	lock sync.Mutex
+	last *cachedClusterConfig
+}
+
+// newClusterConfigCache returns a new cache which persists the cluster config to the given path, a <nil> cache is
+// returned when no path is provided.
+func newClusterConfigCache(path string) *clusterConfigCache {
+	if path == "" {
+		return nil
+	}
+
+	return &clusterConfigCache{path: path}
+}
+
+// load reads the cached cluster config, a <nil> config is returned if nothing has been cached yet.
+func (c *clusterConfigCache) load() (*cachedClusterConfig, error) {
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var cached *cachedClusterConfig
+
+	err = json.Unmarshal(data, &cached)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cluster config: %w", err)
+	}
+
+	if cached == nil || cached.Config == nil || len(cached.Config.Nodes) == 0 {
+		return nil, nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.last = cached
+
+	return cached, nil
+}
+
+// store persists the given cluster config, the file is only rewritten when the config differs from the one which was
+// last loaded/stored.
+//
+// NOTE: The file is written atomically, so concurrent clients using the same cache will never see a partial config.
+func (c *clusterConfigCache) store(cached *cachedClusterConfig) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.last.matches(cached) {
+		return nil
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cluster config: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	err = os.Rename(file.Name(), c.path)
+	if err != nil {
+		return fmt.Errorf("failed to rename temporary file: %w", err)
+	}
+
+	c.last = cached
+
+	return nil
+}
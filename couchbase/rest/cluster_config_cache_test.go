@@ -0,0 +1,148 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newClusterConfigCacheTestCluster returns a test cluster which counts the number of times the cluster config is
+// fetched.
+func newClusterConfigCacheTestCluster(t *testing.T, uuid string) (*TestCluster, *atomic.Int64) {
+	var (
+		cluster  *TestCluster
+		fetches  = &atomic.Int64{}
+		handlers = make(TestHandlers)
+	)
+
+	handlers.Add(http.MethodGet, string(EndpointNodesServices), func(writer http.ResponseWriter, request *http.Request) {
+		fetches.Add(1)
+		cluster.NodeServices(writer, request)
+	})
+
+	cluster = NewTestCluster(t, TestClusterOptions{
+		UUID:     uuid,
+		Nodes:    TestNodes{{}, {}},
+		Handlers: handlers,
+	})
+
+	return cluster, fetches
+}
+
+func newCachingTestClient(cluster *TestCluster, path string) (*Client, error) {
+	return NewClient(ClientOptions{
+		ConnectionString:   cluster.URL(),
+		DisableCCP:         true,
+		Provider:           provider,
+		ClusterConfigCache: path,
+	})
+}
+
+func TestNewClientClusterConfigCache(t *testing.T) {
+	cluster, fetches := newClusterConfigCacheTestCluster(t, "uuid")
+	defer cluster.Close()
+
+	path := filepath.Join(t.TempDir(), "cc.json")
+
+	client, err := newCachingTestClient(cluster, path)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.Equal(t, int64(1), fetches.Load())
+	require.FileExists(t, path)
+
+	cached, err := newCachingTestClient(cluster, path)
+	require.NoError(t, err)
+
+	defer cached.Close()
+
+	require.Equal(t, int64(1), fetches.Load())
+	require.Equal(t, "uuid", cached.ClusterUUID())
+	require.Equal(t, client.authProvider.manager.GetClusterConfig(), cached.authProvider.manager.GetClusterConfig())
+	require.Equal(t, client.authProvider.useAltAddr, cached.authProvider.useAltAddr)
+}
+
+func TestNewClientClusterConfigCacheDifferentCluster(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cc.json")
+
+	first, _ := newClusterConfigCacheTestCluster(t, "uuid")
+
+	client, err := newCachingTestClient(first, path)
+	require.NoError(t, err)
+
+	client.Close()
+	first.Close()
+
+	var cached *cachedClusterConfig
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &cached))
+
+	// Simulate the node being re-initialized as a member of a different cluster with the same address
+	second, fetches := newClusterConfigCacheTestCluster(t, "other")
+	defer second.Close()
+
+	cached.Host = second.URL()
+
+	data, err = json.Marshal(cached)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	client, err = newCachingTestClient(second, path)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.Equal(t, int64(1), fetches.Load())
+	require.Equal(t, "other", client.ClusterUUID())
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &cached))
+	require.Equal(t, "other", cached.UUID)
+}
+
+func TestNewClientClusterConfigCacheInvalidFile(t *testing.T) {
+	cluster, fetches := newClusterConfigCacheTestCluster(t, "uuid")
+	defer cluster.Close()
+
+	path := filepath.Join(t.TempDir(), "cc.json")
+
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+	client, err := newCachingTestClient(cluster, path)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.Equal(t, int64(1), fetches.Load())
+}
+
+func TestClusterConfigCacheStoreUnchangedRevision(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cc.json")
+
+	cache := newClusterConfigCache(path)
+
+	cached := &cachedClusterConfig{UUID: "uuid", Config: &ClusterConfig{Revision: 1, Nodes: Nodes{{Hostname: "host"}}}}
+	require.NoError(t, cache.store(cached))
+
+	require.NoError(t, os.Remove(path))
+
+	// The revision hasn't changed, so the file shouldn't be rewritten
+	require.NoError(t, cache.store(cached))
+	require.NoFileExists(t, path)
+
+	cached = &cachedClusterConfig{UUID: "uuid", Config: &ClusterConfig{Revision: 2, Nodes: Nodes{{Hostname: "host"}}}}
+	require.NoError(t, cache.store(cached))
+
+	loaded, err := newClusterConfigCache(path).load()
+	require.NoError(t, err)
+	require.Equal(t, cached, loaded)
+}
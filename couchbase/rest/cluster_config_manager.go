@@ -16,9 +16,25 @@ const DefaultCCMaxAge = 15 * time.Second
 // ClusterConfig represents the payload sent by 'ns_server' when hitting the '/pools/default/nodeServices' endpoint.
 type ClusterConfig struct {
 	Revision int64 `json:"rev"`
+	Epoch    int64 `json:"revEpoch"`
 	Nodes    Nodes `json:"nodesExt"`
 }
 
+// Copy returns a deep copy of the cluster config.
+func (c *ClusterConfig) Copy() *ClusterConfig {
+	return &ClusterConfig{Revision: c.Revision, Epoch: c.Epoch, Nodes: c.Nodes.Copy()}
+}
+
+// olderThan returns a boolean indicating whether this cluster config is older than the given config. The epoch takes
+// precedence over the revision, since it's bumped when the revision is reset e.g. after quorum failover.
+func (c *ClusterConfig) olderThan(other *ClusterConfig) bool {
+	if c.Epoch != other.Epoch {
+		return c.Epoch < other.Epoch
+	}
+
+	return c.Revision < other.Revision
+}
+
 // BootstrapNode returns the node which we bootstrapped against.
 func (c *ClusterConfig) BootstrapNode() *Node {
 	for _, node := range c.Nodes {
@@ -37,12 +53,34 @@ func (c *ClusterConfig) FilterOtherNodes() {
 	c.Nodes = Nodes{c.BootstrapNode()}
 }
 
+// ConfigManager manages the cluster config used by the REST client, allowing embedding tools to provide their own
+// implementation e.g. one which is shared between multiple clients. The default implementation is
+// 'ClusterConfigManager'.
+//
+// NOTE: Access to the config manager is guarded by the client, however, implementations which are shared between
+// clients must be safe for concurrent use.
+type ConfigManager interface {
+	// GetClusterConfig returns a copy of the current cluster config, or <nil> if the client hasn't bootstrapped.
+	GetClusterConfig() *ClusterConfig
+
+	// Update attempts to update the cluster config using the one provided, returning an 'OldClusterConfigError' if the
+	// provided config is older than the current config.
+	Update(config *ClusterConfig) error
+
+	// WaitUntilUpdated triggers a config update and then blocks until the update is complete.
+	WaitUntilUpdated(ctx context.Context)
+
+	// WaitUntilExpired blocks until the current config has expired, or an update has been triggered.
+	WaitUntilExpired(ctx context.Context)
+}
+
 // ClusterConfigManager is a utility wrapper around the current cluster config which provides utility functions required
 // when periodically updating the REST clients cluster config.
 type ClusterConfigManager struct {
 	config *ClusterConfig
 	last   *time.Time
 	maxAge time.Duration
+	clock  Clock
 
 	// Related to triggering config updates in-between request retries
 	cond   *sync.Cond
@@ -53,6 +91,12 @@ type ClusterConfigManager struct {
 // NewClusterConfigManager returns a new cluster config manager which will not immediately trigger a cluster config
 // update.
 func NewClusterConfigManager(logger *slog.Logger) *ClusterConfigManager {
+	return newClusterConfigManager(logger, systemClock{})
+}
+
+// newClusterConfigManager returns a new cluster config manager which uses the given clock to determine when the cluster
+// config has expired.
+func newClusterConfigManager(logger *slog.Logger, clock Clock) *ClusterConfigManager {
 	maxAge, ok := envvar.GetDuration("CB_REST_CC_MAX_AGE")
 	if !ok {
 		maxAge = DefaultCCMaxAge
@@ -60,11 +104,12 @@ func NewClusterConfigManager(logger *slog.Logger) *ClusterConfigManager {
 		logger.Info("set max cluster config age", "age", maxAge)
 	}
 
-	now := time.Now()
+	now := clock.Now()
 
 	return &ClusterConfigManager{
 		last:   &now,
 		maxAge: maxAge,
+		clock:  clock,
 		cond:   sync.NewCond(&sync.Mutex{}),
 		signal: make(chan struct{}),
 	}
@@ -77,7 +122,7 @@ func (c *ClusterConfigManager) GetClusterConfig() *ClusterConfig {
 		return nil
 	}
 
-	return &ClusterConfig{Revision: c.config.Revision, Nodes: c.config.Nodes.Copy()}
+	return c.config.Copy()
 }
 
 // Update attempts to update the cluster config using the one provided, note that it may be rejected depending on the
@@ -90,11 +135,11 @@ func (c *ClusterConfigManager) Update(config *ClusterConfig) error {
 		c.cond.L.Unlock()
 	}()
 
-	if c.config != nil && c.config.Revision > config.Revision {
+	if c.config != nil && config.olderThan(c.config) {
 		return &OldClusterConfigError{old: config.Revision, curr: c.config.Revision}
 	}
 
-	now := time.Now()
+	now := c.clock.Now()
 
 	c.config = config
 	c.last = &now
@@ -136,7 +181,7 @@ func (c *ClusterConfigManager) WaitUntilExpired(ctx context.Context) {
 	select {
 	case <-ctx.Done():
 	case <-c.createSignalChannel():
-	case <-time.After(time.Until(c.last.Add(c.maxAge))):
+	case <-c.clock.After(c.last.Add(c.maxAge).Sub(c.clock.Now())):
 	}
 }
 
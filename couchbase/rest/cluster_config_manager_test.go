@@ -57,6 +57,17 @@ func TestClusterConfigManagerUpdate(t *testing.T) {
 			current: &ClusterConfig{Revision: 42},
 			updated: &ClusterConfig{Revision: 64},
 		},
+		{
+			name:    "NewIsLesserEpoch",
+			current: &ClusterConfig{Revision: 42, Epoch: 2},
+			updated: &ClusterConfig{Revision: 64, Epoch: 1},
+			old:     true,
+		},
+		{
+			name:    "NewIsGreaterEpochLesserRev",
+			current: &ClusterConfig{Revision: 64, Epoch: 1},
+			updated: &ClusterConfig{Revision: 42, Epoch: 2},
+		},
 	}
 
 	for _, test := range tests {
@@ -200,6 +211,19 @@ func TestClusterConfigManagerWaitUntilExpired(t *testing.T) {
 	require.True(t, woken)
 }
 
+func TestClusterConfigManagerWaitUntilExpiredUsesClock(t *testing.T) {
+	var (
+		clock   = &testClock{now: time.Now()}
+		manager = newClusterConfigManager(slog.Default(), clock)
+	)
+
+	clock.now = clock.now.Add(5 * time.Second)
+
+	manager.WaitUntilExpired(context.Background())
+
+	require.Equal(t, []time.Duration{DefaultCCMaxAge - 5*time.Second}, clock.Waits())
+}
+
 func TestClusterConfigManagerWaitUntilExpiredContextCancel(t *testing.T) {
 	var (
 		woken       bool
@@ -0,0 +1,144 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	cbvalue "github.com/couchbase/tools-common/couchbase/v3/value"
+)
+
+// Feature represents functionality which is only supported by clusters running a minimum version of Couchbase Server.
+type Feature struct {
+	// Name is a human readable name for the feature, used in error messages.
+	Name string
+
+	// MinVersion is the minimum version which all the nodes in the cluster must be running to use the feature.
+	MinVersion cbvalue.Version
+}
+
+var (
+	// FeatureXDCRFilterExpression is the filtering of the documents replicated by XDCR using a filter expression.
+	FeatureXDCRFilterExpression = Feature{Name: "XDCR filter expressions", MinVersion: cbvalue.Version5_5_0}
+
+	// FeatureXDCRHalfEncryption is the encryption of only the credentials sent to an XDCR remote cluster.
+	FeatureXDCRHalfEncryption = Feature{Name: "XDCR half encryption", MinVersion: cbvalue.Version5_5_0}
+
+	// FeatureMagma is the use of the Magma storage backend, and its associated bucket settings.
+	FeatureMagma = Feature{Name: "The Magma storage backend", MinVersion: cbvalue.Version7_1_0}
+)
+
+// CompatibilityChecker gates the use of features on the version of the cluster, allowing requests which the cluster
+// can't handle to be refused before they're sent.
+//
+// NOTE: Mixed version clusters only support the features supported by the oldest node.
+type CompatibilityChecker struct {
+	version cbvalue.ClusterVersion
+}
+
+// NewCompatibilityChecker returns a new checker for a cluster with the given version.
+func NewCompatibilityChecker(version cbvalue.ClusterVersion) *CompatibilityChecker {
+	return &CompatibilityChecker{version: version}
+}
+
+// Version returns the version of the cluster being checked against.
+func (c *CompatibilityChecker) Version() cbvalue.ClusterVersion {
+	return c.version
+}
+
+// Supports returns a boolean indicating whether the cluster supports the given feature.
+func (c *CompatibilityChecker) Supports(feature Feature) bool {
+	return c.version.MinVersion.AtLeast(feature.MinVersion)
+}
+
+// Check returns an 'UnsupportedServerVersionError' if the cluster doesn't support any of the given features.
+func (c *CompatibilityChecker) Check(features ...Feature) error {
+	for _, feature := range features {
+		if c.Supports(feature) {
+			continue
+		}
+
+		return &UnsupportedServerVersionError{
+			Required: feature.MinVersion,
+			feature:  feature.Name,
+			version:  c.version.MinVersion,
+		}
+	}
+
+	return nil
+}
+
+// GetClusterVersion returns the version of the cluster, which is the oldest version being run by any of its nodes.
+func (c *Client) GetClusterVersion(ctx context.Context) (cbvalue.ClusterVersion, error) {
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointPoolsDefault,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	response, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return cbvalue.ClusterVersion{}, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var decoded struct {
+		Nodes []struct {
+			Version string `json:"version"`
+		} `json:"nodes"`
+	}
+
+	err = json.Unmarshal(response.Body, &decoded)
+	if err != nil {
+		return cbvalue.ClusterVersion{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if len(decoded.Nodes) == 0 {
+		return cbvalue.ClusterVersion{}, ErrNoNodes
+	}
+
+	version := cbvalue.ClusterVersion{MinVersion: cbvalue.ParseVersion(decoded.Nodes[0].Version)}
+
+	for _, node := range decoded.Nodes[1:] {
+		parsed := cbvalue.ParseVersion(node.Version)
+
+		if !parsed.Equal(version.MinVersion) {
+			version.Mixed = true
+		}
+
+		if parsed.Older(version.MinVersion) {
+			version.MinVersion = parsed
+		}
+	}
+
+	return version, nil
+}
+
+// NewCompatibilityChecker returns a checker for the current version of the cluster.
+//
+// NOTE: The version of the cluster may change e.g. during an upgrade, so the checker shouldn't be retained.
+func (c *Client) NewCompatibilityChecker(ctx context.Context) (*CompatibilityChecker, error) {
+	version, err := c.GetClusterVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster version: %w", err)
+	}
+
+	return NewCompatibilityChecker(version), nil
+}
+
+// checkCompatibility returns an 'UnsupportedServerVersionError' if the cluster doesn't support any of the given
+// features; the version of the cluster is only fetched where there are features to check.
+func (c *Client) checkCompatibility(ctx context.Context, features ...Feature) error {
+	if len(features) == 0 {
+		return nil
+	}
+
+	checker, err := c.NewCompatibilityChecker(ctx)
+	if err != nil {
+		return err
+	}
+
+	return checker.Check(features...)
+}
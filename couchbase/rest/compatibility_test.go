@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cbvalue "github.com/couchbase/tools-common/couchbase/v3/value"
+)
+
+func TestCompatibilityChecker(t *testing.T) {
+	type test struct {
+		name     string
+		version  cbvalue.Version
+		feature  Feature
+		expected bool
+	}
+
+	tests := []*test{
+		{
+			name:     "Supported",
+			version:  cbvalue.Version7_1_0,
+			feature:  FeatureMagma,
+			expected: true,
+		},
+		{
+			name:    "Unsupported",
+			version: cbvalue.Version7_0_2,
+			feature: FeatureMagma,
+		},
+		{
+			name:     "Unknown",
+			version:  cbvalue.VersionUnknown,
+			feature:  FeatureMagma,
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checker := NewCompatibilityChecker(cbvalue.ClusterVersion{MinVersion: test.version})
+			require.Equal(t, test.expected, checker.Supports(test.feature))
+
+			err := checker.Check(test.feature)
+			if test.expected {
+				require.NoError(t, err)
+				return
+			}
+
+			var unsupported *UnsupportedServerVersionError
+
+			require.ErrorAs(t, err, &unsupported)
+			require.Equal(t, test.feature.MinVersion, unsupported.Required)
+			require.True(t, IsUnsupportedServerVersion(err))
+		})
+	}
+}
+
+func TestClientGetClusterVersion(t *testing.T) {
+	type test struct {
+		name     string
+		nodes    TestNodes
+		expected cbvalue.ClusterVersion
+	}
+
+	tests := []*test{
+		{
+			name:     "Single",
+			nodes:    TestNodes{{Version: cbvalue.Version7_6_0}},
+			expected: cbvalue.ClusterVersion{MinVersion: cbvalue.Version7_6_0},
+		},
+		{
+			name:     "Same",
+			nodes:    TestNodes{{Version: cbvalue.Version7_6_0}, {Version: cbvalue.Version7_6_0}},
+			expected: cbvalue.ClusterVersion{MinVersion: cbvalue.Version7_6_0},
+		},
+		{
+			name:     "Mixed",
+			nodes:    TestNodes{{Version: cbvalue.Version7_6_0}, {Version: cbvalue.Version7_1_0}},
+			expected: cbvalue.ClusterVersion{MinVersion: cbvalue.Version7_1_0, Mixed: true},
+		},
+		{
+			name:     "Build",
+			nodes:    TestNodes{{Version: "7.6.0-2176-enterprise"}},
+			expected: cbvalue.ClusterVersion{MinVersion: cbvalue.Version7_6_0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cluster := NewTestCluster(t, TestClusterOptions{Nodes: test.nodes})
+			defer cluster.Close()
+
+			client, err := newTestClient(cluster, true)
+			require.NoError(t, err)
+
+			defer client.Close()
+
+			version, err := client.GetClusterVersion(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, test.expected, version)
+
+			checker, err := client.NewCompatibilityChecker(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, test.expected, checker.Version())
+		})
+	}
+}
+
+func TestClientGetClusterVersionNoNodes(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, string(EndpointPoolsDefault), NewTestHandler(t, http.StatusOK, []byte(`{"nodes":[]}`)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.GetClusterVersion(context.Background())
+	require.ErrorIs(t, err, ErrNoNodes)
+}
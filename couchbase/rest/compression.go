@@ -0,0 +1,144 @@
+package rest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// CompressionThreshold is the minimum size of a request body before it will be compressed, smaller bodies are sent
+// as-is because the overhead of compression outweighs the benefit.
+const CompressionThreshold = 1024
+
+// encodingGzip is the 'Content-Encoding'/'Accept-Encoding' value used for gzip compressed payloads.
+const encodingGzip = "gzip"
+
+// compressRequest returns a shallow copy of the given request with a gzip compressed body, the original request is
+// returned if compression isn't requested, or the body is smaller than the 'CompressionThreshold'.
+func compressRequest(request *Request) (*Request, error) {
+	if !request.CompressBody || len(request.Body) < CompressionThreshold {
+		return request, nil
+	}
+
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+
+	_, err := writer.Write(request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress body: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to flush compressed body: %w", err)
+	}
+
+	compressed := *request
+	compressed.Body = buffer.Bytes()
+	compressed.Header = make(Header, len(request.Header)+1)
+
+	for key, value := range request.Header {
+		compressed.Header[key] = value
+	}
+
+	compressed.Header["Content-Encoding"] = encodingGzip
+
+	return &compressed, nil
+}
+
+// decompressResponse replaces the body of the given response with one which transparently decompresses the payload,
+// where the server has responded with a gzip encoded body.
+func (c *Client) decompressResponse(ctx context.Context, resp *http.Response) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), encodingGzip) {
+		return
+	}
+
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+
+	resp.Body = &gzipBody{
+		raw:    &countingReader{r: resp.Body},
+		closer: resp.Body,
+		log: func(compressed, decompressed int64) {
+			c.logger.Log(
+				ctx,
+				c.reqResLogLevel,
+				"decompressed response body",
+				"url", resp.Request.URL,
+				"compressed_size", compressed,
+				"size", decompressed,
+			)
+		},
+	}
+
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// countingReader is a reader which counts the number of bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+
+	return n, err
+}
+
+// gzipBody is a response body which lazily decompresses a gzip encoded payload.
+type gzipBody struct {
+	raw    *countingReader
+	closer io.Closer
+	reader *gzip.Reader
+	n      int64
+	log    func(compressed, decompressed int64)
+}
+
+func (g *gzipBody) Read(p []byte) (int, error) {
+	if g.reader == nil {
+		reader, err := gzip.NewReader(g.raw)
+		if err != nil {
+			return 0, err
+		}
+
+		g.reader = reader
+	}
+
+	n, err := g.reader.Read(p)
+	g.n += int64(n)
+
+	return n, err
+}
+
+func (g *gzipBody) Close() error {
+	if g.raw.n != 0 {
+		g.log(g.raw.n, g.n)
+	}
+
+	return g.closer.Close()
+}
+
+// logCompressed logs the size of a compressed request body.
+func (c *Client) logCompressed(ctx context.Context, original, compressed *Request) {
+	if original == compressed {
+		return
+	}
+
+	c.logger.Log(
+		ctx,
+		c.reqResLogLevel,
+		"compressed request body",
+		"method", original.Method,
+		"endpoint", original.Endpoint,
+		"size", len(original.Body),
+		"compressed_size", len(compressed.Body),
+	)
+}
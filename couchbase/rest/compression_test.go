@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"testing"
+
+	testutil "github.com/couchbase/tools-common/testing/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+// gzipped returns the gzip compressed representation of the given data.
+func gzipped(t *testing.T, data []byte) []byte {
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+
+	_, err := writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buffer.Bytes()
+}
+
+func TestCompressRequestNotRequested(t *testing.T) {
+	request := &Request{Body: make([]byte, CompressionThreshold)}
+
+	compressed, err := compressRequest(request)
+	require.NoError(t, err)
+	require.Same(t, request, compressed)
+}
+
+func TestCompressRequestBelowThreshold(t *testing.T) {
+	request := &Request{Body: make([]byte, CompressionThreshold-1), CompressBody: true}
+
+	compressed, err := compressRequest(request)
+	require.NoError(t, err)
+	require.Same(t, request, compressed)
+}
+
+func TestCompressRequest(t *testing.T) {
+	var (
+		body    = bytes.Repeat([]byte("a"), 4*CompressionThreshold)
+		request = &Request{Body: body, CompressBody: true, Header: Header{"key": "value"}}
+	)
+
+	compressed, err := compressRequest(request)
+	require.NoError(t, err)
+	require.NotSame(t, request, compressed)
+	require.Less(t, len(compressed.Body), len(body))
+	require.Equal(t, Header{"key": "value", "Content-Encoding": "gzip"}, compressed.Header)
+
+	// The original request should not have been modified
+	require.Equal(t, body, request.Body)
+	require.Equal(t, Header{"key": "value"}, request.Header)
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed.Body))
+	require.NoError(t, err)
+	require.Equal(t, body, testutil.ReadAll(t, reader))
+}
+
+func TestGzipBody(t *testing.T) {
+	var (
+		logged [2]int64
+		raw    = gzipped(t, []byte("body"))
+	)
+
+	body := &gzipBody{
+		raw:    &countingReader{r: bytes.NewReader(raw)},
+		closer: io.NopCloser(nil),
+		log:    func(compressed, decompressed int64) { logged = [2]int64{compressed, decompressed} },
+	}
+
+	require.Equal(t, []byte("body"), testutil.ReadAll(t, body))
+	require.NoError(t, body.Close())
+	require.Equal(t, [2]int64{int64(len(raw)), 4}, logged)
+}
+
+func TestClientExecuteWithCompression(t *testing.T) {
+	body := bytes.Repeat([]byte("a"), 4*CompressionThreshold)
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodPost, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		require.Equal(t, "gzip", request.Header.Get("Content-Encoding"))
+		require.Equal(t, "gzip", request.Header.Get("Accept-Encoding"))
+
+		reader, err := gzip.NewReader(request.Body)
+		require.NoError(t, err)
+		require.Equal(t, body, testutil.ReadAll(t, reader))
+
+		writer.Header().Set("Content-Encoding", "gzip")
+		writer.WriteHeader(http.StatusOK)
+
+		testutil.Write(t, writer, gzipped(t, []byte("response")))
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	response, err := client.Execute(&Request{
+		Body:               body,
+		CompressBody:       true,
+		ContentType:        ContentTypeJSON,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte("response"), response.Body)
+}
@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// concurrencyLimiter limits the number of requests which may be in-flight at once, where a request is considered
+// in-flight from the moment it's dispatched until its response body is closed.
+type concurrencyLimiter struct {
+	sem      *semaphore.Weighted
+	max      int64
+	inFlight atomic.Int64
+}
+
+// newConcurrencyLimiter returns a new limiter which allows up to the given number of concurrent requests; a
+// non-positive value disables limiting, however, in-flight requests are still tracked.
+func newConcurrencyLimiter(limit int) *concurrencyLimiter {
+	limiter := &concurrencyLimiter{max: int64(limit)}
+
+	if limit > 0 {
+		limiter.sem = semaphore.NewWeighted(int64(limit))
+	}
+
+	return limiter
+}
+
+// acquire blocks until the given weight is available, or the context is cancelled. The returned function must be
+// called to release the acquired weight.
+//
+// NOTE: Weights larger than the limit are truncated, this allows heavy requests to run exclusively rather than
+// blocking indefinitely.
+func (c *concurrencyLimiter) acquire(ctx context.Context, weight int64) (func(), error) {
+	weight = max(1, weight)
+
+	if c.sem != nil {
+		weight = min(weight, c.max)
+
+		if err := c.sem.Acquire(ctx, weight); err != nil {
+			return nil, err
+		}
+	}
+
+	c.inFlight.Add(1)
+
+	var once sync.Once
+
+	release := func() {
+		once.Do(func() {
+			c.inFlight.Add(-1)
+
+			if c.sem != nil {
+				c.sem.Release(weight)
+			}
+		})
+	}
+
+	return release, nil
+}
+
+// releaseOnClose wraps a response body, calling the release function once the body has been closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
+
+// limitedBody wraps a response body, releasing the concurrency limit acquired for the request once it's closed.
+type limitedBody struct {
+	releaseOnClose
+}
+
+// releaseLimit releases the concurrency limit acquired for the given response without closing its body, this allows
+// other requests to be dispatched whilst we're waiting to retry it.
+func releaseLimit(resp *http.Response) {
+	var closer io.Closer = resp.Body
+
+	// The body may have been peeked at, see 'isNodeInMaintenance'
+	if peeked, ok := closer.(*peekedBody); ok {
+		closer = peeked.Closer
+	}
+
+	if body, ok := closer.(*limitedBody); ok {
+		body.release()
+	}
+}
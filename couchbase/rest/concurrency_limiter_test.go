@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimiterUnlimited(t *testing.T) {
+	limiter := newConcurrencyLimiter(0)
+	require.Nil(t, limiter.sem)
+
+	release, err := limiter.acquire(context.Background(), 1024)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), limiter.inFlight.Load())
+
+	release()
+	require.Zero(t, limiter.inFlight.Load())
+}
+
+func TestConcurrencyLimiterBlocksWhenFull(t *testing.T) {
+	limiter := newConcurrencyLimiter(2)
+
+	release, err := limiter.acquire(context.Background(), 2)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = limiter.acquire(ctx, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+
+	release, err = limiter.acquire(context.Background(), 1)
+	require.NoError(t, err)
+
+	release()
+}
+
+func TestConcurrencyLimiterTruncatesWeight(t *testing.T) {
+	limiter := newConcurrencyLimiter(2)
+
+	release, err := limiter.acquire(context.Background(), 64)
+	require.NoError(t, err)
+
+	// Releasing multiple times should be a no-op
+	release()
+	release()
+
+	require.Zero(t, limiter.inFlight.Load())
+	require.True(t, limiter.sem.TryAcquire(2))
+}
+
+func TestReleaseOnClose(t *testing.T) {
+	var released int
+
+	body := &releaseOnClose{
+		ReadCloser: io.NopCloser(strings.NewReader("body")),
+		release:    func() { released++ },
+	}
+
+	require.NoError(t, body.Close())
+	require.Equal(t, 1, released)
+}
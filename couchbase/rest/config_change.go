@@ -0,0 +1,93 @@
+package rest
+
+import "sync"
+
+// ConfigChangeFunc is a function which is called with the new revision when the cluster config used by the client
+// changes.
+type ConfigChangeFunc func(rev int64)
+
+// configListeners tracks the functions which are notified when the cluster config changes.
+type configListeners struct {
+	lock      sync.Mutex
+	next      int
+	listeners map[int]ConfigChangeFunc
+}
+
+// newConfigListeners returns an initialized set of config listeners.
+func newConfigListeners() *configListeners {
+	return &configListeners{listeners: make(map[int]ConfigChangeFunc)}
+}
+
+// add registers the given function, returning a function which unregisters it.
+func (c *configListeners) add(fn ConfigChangeFunc) func() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	id := c.next
+	c.next++
+
+	c.listeners[id] = fn
+
+	return func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		delete(c.listeners, id)
+	}
+}
+
+// notify calls each of the registered functions with the given revision.
+func (c *configListeners) notify(rev int64) {
+	c.lock.Lock()
+
+	listeners := make([]ConfigChangeFunc, 0, len(c.listeners))
+
+	for _, fn := range c.listeners {
+		listeners = append(listeners, fn)
+	}
+
+	c.lock.Unlock()
+
+	// Listeners are called without holding the lock, so that they may unregister themselves
+	for _, fn := range listeners {
+		fn(rev)
+	}
+}
+
+// configChanged returns a boolean indicating whether the given cluster configs have a different revision/epoch.
+func configChanged(old, curr *ClusterConfig) bool {
+	return old == nil || old.Revision != curr.Revision || old.Epoch != curr.Epoch
+}
+
+// ClusterConfigRev returns the revision of the cluster config currently in use by the client, this may be used to cache
+// data derived from the cluster config; see 'OnConfigChange'.
+//
+// NOTE: The revision is only comparable with revisions which have the same epoch, see 'ClusterConfigEpoch'.
+func (c *Client) ClusterConfigRev() int64 {
+	config := c.authProvider.manager.GetClusterConfig()
+	if config == nil {
+		return 0
+	}
+
+	return config.Revision
+}
+
+// ClusterConfigEpoch returns the epoch of the cluster config currently in use by the client, the epoch is bumped when
+// the revision is reset (e.g. after a quorum failover) and is always zero for clusters which don't support epochs.
+func (c *Client) ClusterConfigEpoch() int64 {
+	config := c.authProvider.manager.GetClusterConfig()
+	if config == nil {
+		return 0
+	}
+
+	return config.Epoch
+}
+
+// OnConfigChange registers a function which is called with the new revision each time the client updates to a cluster
+// config with a different revision/epoch, the returned function unregisters it.
+//
+// NOTE: Changes are only observed when cluster config polling is enabled, and the function is called synchronously by
+// the polling goroutine, so should return promptly.
+func (c *Client) OnConfigChange(fn ConfigChangeFunc) func() {
+	return c.configListeners.add(fn)
+}
@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigChanged(t *testing.T) {
+	require.True(t, configChanged(nil, &ClusterConfig{}))
+	require.True(t, configChanged(&ClusterConfig{Revision: 1}, &ClusterConfig{Revision: 2}))
+	require.True(t, configChanged(&ClusterConfig{Revision: 1, Epoch: 1}, &ClusterConfig{Revision: 1, Epoch: 2}))
+	require.False(t, configChanged(&ClusterConfig{Revision: 1, Epoch: 1}, &ClusterConfig{Revision: 1, Epoch: 1}))
+}
+
+func TestConfigListeners(t *testing.T) {
+	var (
+		listeners = newConfigListeners()
+		a, b      []int64
+	)
+
+	listeners.add(func(rev int64) { a = append(a, rev) })
+	remove := listeners.add(func(rev int64) { b = append(b, rev) })
+
+	listeners.notify(1)
+	remove()
+	listeners.notify(2)
+
+	require.Equal(t, []int64{1, 2}, a)
+	require.Equal(t, []int64{1}, b)
+}
+
+func TestClientOnConfigChange(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	var revs []int64
+
+	remove := client.OnConfigChange(func(rev int64) { revs = append(revs, rev) })
+
+	before := client.ClusterConfigRev()
+
+	require.NoError(t, client.updateCC())
+	require.Equal(t, []int64{client.ClusterConfigRev()}, revs)
+	require.Greater(t, client.ClusterConfigRev(), before)
+	require.Zero(t, client.ClusterConfigEpoch())
+
+	remove()
+
+	require.NoError(t, client.updateCC())
+	require.Len(t, revs, 1)
+}
@@ -0,0 +1,245 @@
+package rest
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+)
+
+// ConnectionPoolOptions encapsulates the options available to tune the pool of connections used by the client, these
+// should be tuned for workloads which dispatch many concurrent requests to the same nodes.
+type ConnectionPoolOptions struct {
+	// MaxIdleConns is the maximum number of idle connections kept across all hosts. Defaults to 'DefaultMaxIdleConns'.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept per host, connections beyond this limit are
+	// closed once they become idle. Defaults to 'DefaultMaxIdleConnsPerHost'.
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost limits the total number of connections per host, including those being dialed, in-use and idle;
+	// requests will block until a connection is available once the limit is reached. Defaults to no limit.
+	MaxConnsPerHost int
+
+	// TLSSessionCacheSize is the number of TLS sessions cached, allowing them to be resumed which reduces the cost of
+	// establishing new connections. Defaults to 'DefaultTLSSessionCacheSize', a negative value disables the cache.
+	//
+	// NOTE: Not used where the provided TLS config already has a session cache.
+	TLSSessionCacheSize int
+}
+
+// defaults fills any missing attributes to a sane default.
+func (c *ConnectionPoolOptions) defaults() {
+	if c.MaxIdleConns <= 0 {
+		c.MaxIdleConns = DefaultMaxIdleConns
+	}
+
+	if c.MaxIdleConnsPerHost <= 0 {
+		c.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+
+	if c.TLSSessionCacheSize == 0 {
+		c.TLSSessionCacheSize = DefaultTLSSessionCacheSize
+	}
+}
+
+// ConnectionPoolStats contains metrics about the connections used by the client.
+type ConnectionPoolStats struct {
+	// Open is the number of connections which have been established and not yet closed.
+	Open int64
+
+	// InUse is the number of open connections which are currently being used by at least one request.
+	InUse int64
+
+	// Idle is the number of open connections which aren't being used by any requests.
+	Idle int64
+}
+
+// connectionPool tracks the connections established by the HTTP client, so that metrics can be reported about them.
+type connectionPool struct {
+	open  atomic.Int64
+	inUse atomic.Int64
+}
+
+// newConnectionPool applies the given options to the given transport, returning a pool which tracks the connections it
+// establishes.
+//
+// NOTE: Must be called after the dial function of the transport has been set.
+func newConnectionPool(transport *http.Transport, options ConnectionPoolOptions) *connectionPool {
+	options.defaults()
+
+	transport.MaxIdleConns = options.MaxIdleConns
+	transport.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = options.MaxConnsPerHost
+
+	if options.TLSSessionCacheSize > 0 {
+		transport.TLSClientConfig = withSessionCache(transport.TLSClientConfig, options.TLSSessionCacheSize)
+	}
+
+	pool := &connectionPool{}
+
+	transport.DialContext = pool.wrap(transport.DialContext)
+
+	return pool
+}
+
+// withSessionCache returns a copy of the given TLS config, which uses a session cache of the given size.
+func withSessionCache(config *tls.Config, size int) *tls.Config {
+	if config == nil {
+		config = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	if config.ClientSessionCache != nil {
+		return config
+	}
+
+	config = config.Clone()
+	config.ClientSessionCache = tls.NewLRUClientSessionCache(size)
+
+	return config
+}
+
+// wrap returns a dial function which tracks the connections established using the given dial function.
+func (p *connectionPool) wrap(dial DialContextFunc) DialContextFunc {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err // Purposefully not wrapped
+		}
+
+		p.open.Add(1)
+
+		return &pooledConn{Conn: conn, pool: p}, nil
+	}
+}
+
+// trace returns a copy of the given request which marks the connection it uses as in-use, until the returned function
+// is called.
+func (p *connectionPool) trace(req *http.Request) (*http.Request, func()) {
+	if p == nil {
+		return req, func() {}
+	}
+
+	var (
+		lock sync.Mutex
+		conn *pooledConn
+		once sync.Once
+	)
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			// The transport may retry the request using another connection
+			if conn != nil {
+				conn.release()
+			}
+
+			conn = unwrapPooledConn(info.Conn)
+			if conn != nil {
+				conn.acquire()
+			}
+		},
+	}
+
+	release := func() {
+		once.Do(func() {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if conn != nil {
+				conn.release()
+			}
+		})
+	}
+
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), release
+}
+
+// stats returns metrics about the connections in the pool.
+func (p *connectionPool) stats() ConnectionPoolStats {
+	if p == nil {
+		return ConnectionPoolStats{}
+	}
+
+	var (
+		open  = p.open.Load()
+		inUse = min(p.inUse.Load(), open)
+	)
+
+	return ConnectionPoolStats{Open: open, InUse: inUse, Idle: open - inUse}
+}
+
+// unwrapPooledConn returns the pooled connection underlying the given connection, or <nil> if there isn't one.
+func unwrapPooledConn(conn net.Conn) *pooledConn {
+	// TLS connections wrap the connection returned by the dial function
+	if wrapper, ok := conn.(interface{ NetConn() net.Conn }); ok {
+		conn = wrapper.NetConn()
+	}
+
+	pooled, _ := conn.(*pooledConn)
+
+	return pooled
+}
+
+// pooledConn is a connection which is tracked by a connection pool.
+//
+// NOTE: A connection may be used by multiple requests at once when using HTTP/2.
+type pooledConn struct {
+	net.Conn
+	pool *connectionPool
+
+	lock   sync.Mutex
+	users  int
+	closed bool
+}
+
+// acquire marks the connection as being used by a request.
+func (p *pooledConn) acquire() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.users++
+
+	if p.users == 1 && !p.closed {
+		p.pool.inUse.Add(1)
+	}
+}
+
+// release marks the connection as no longer being used by a request.
+func (p *pooledConn) release() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.users--
+
+	if p.users == 0 && !p.closed {
+		p.pool.inUse.Add(-1)
+	}
+}
+
+// Close closes the connection, removing it from the pool.
+func (p *pooledConn) Close() error {
+	p.lock.Lock()
+
+	if !p.closed {
+		p.closed = true
+		p.pool.open.Add(-1)
+
+		if p.users > 0 {
+			p.pool.inUse.Add(-1)
+		}
+	}
+
+	p.lock.Unlock()
+
+	return p.Conn.Close()
+}
@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionPoolOptionsDefaults(t *testing.T) {
+	options := ConnectionPoolOptions{}
+	options.defaults()
+
+	require.Equal(t, ConnectionPoolOptions{
+		MaxIdleConns:        DefaultMaxIdleConns,
+		MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+		TLSSessionCacheSize: DefaultTLSSessionCacheSize,
+	}, options)
+}
+
+func TestNewConnectionPool(t *testing.T) {
+	transport := &http.Transport{}
+
+	newConnectionPool(transport, ConnectionPoolOptions{
+		MaxIdleConns:        64,
+		MaxIdleConnsPerHost: 16,
+		MaxConnsPerHost:     8,
+		TLSSessionCacheSize: -1,
+	})
+
+	require.Equal(t, 64, transport.MaxIdleConns)
+	require.Equal(t, 16, transport.MaxIdleConnsPerHost)
+	require.Equal(t, 8, transport.MaxConnsPerHost)
+	require.Nil(t, transport.TLSClientConfig)
+	require.NotNil(t, transport.DialContext)
+}
+
+func TestWithSessionCache(t *testing.T) {
+	config := withSessionCache(nil, 1)
+	require.NotNil(t, config.ClientSessionCache)
+
+	// The given config should not be modified
+	original := &tls.Config{ServerName: "hostname"}
+
+	config = withSessionCache(original, 1)
+	require.Nil(t, original.ClientSessionCache)
+	require.NotNil(t, config.ClientSessionCache)
+	require.Equal(t, "hostname", config.ServerName)
+
+	// An existing cache should be preserved
+	cache := tls.NewLRUClientSessionCache(1)
+
+	config = withSessionCache(&tls.Config{ClientSessionCache: cache}, 1)
+	require.Equal(t, cache, config.ClientSessionCache)
+}
+
+func TestClientConnectionPoolStats(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusOK, make([]byte, 0)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	resp, err := client.Do(context.Background(), &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+
+	stats := client.ConnectionPoolStats()
+	require.Positive(t, stats.Open)
+	require.Equal(t, int64(1), stats.InUse)
+	require.Equal(t, stats.Open-1, stats.Idle)
+
+	client.cleanupResp(resp)
+
+	stats = client.ConnectionPoolStats()
+	require.Positive(t, stats.Open)
+	require.Zero(t, stats.InUse)
+	require.Equal(t, stats.Open, stats.Idle)
+}
+
+func TestConnectionPoolStatsNil(t *testing.T) {
+	require.Zero(t, (&Client{}).ConnectionPoolStats())
+}
@@ -40,6 +40,47 @@ const (
 	// 'healthy'.
 	DefaultPollTimeout = 5 * time.Minute
 
+	// DefaultResponseCacheTTL is the default duration for which responses are cached, when the response cache is
+	// enabled.
+	DefaultResponseCacheTTL = 30 * time.Second
+
+	// DefaultDNSRefreshInterval is the default interval at which connection strings which were resolved using a DNS
+	// SRV record are re-resolved.
+	DefaultDNSRefreshInterval = 5 * time.Minute
+
+	// DefaultMaxIdleConns is the default maximum number of idle connections kept by the HTTP client, across all hosts.
+	DefaultMaxIdleConns = 100
+
+	// DefaultMaxIdleConnsPerHost is the default maximum number of idle connections kept by the HTTP client, per host.
+	DefaultMaxIdleConnsPerHost = 32
+
+	// DefaultTLSSessionCacheSize is the default number of TLS sessions cached by the HTTP client, allowing them to be
+	// resumed when establishing new connections.
+	DefaultTLSSessionCacheSize = 64
+
+	// DefaultMaxDecodeBodySize is the default maximum size of a response body decoded using 'ExecuteInto'.
+	DefaultMaxDecodeBodySize = 256 * 1024 * 1024
+
+	// DefaultRetryBudgetRatio is the default maximum ratio of retries to requests, when the retry budget is enabled.
+	DefaultRetryBudgetRatio = 0.2
+
+	// DefaultRetryBudgetWindow is the default duration over which requests/retries are counted, when the retry budget
+	// is enabled.
+	DefaultRetryBudgetWindow = 10 * time.Second
+
+	// DefaultRetryBudgetMinRetries is the default number of retries which are always permitted within the window, so
+	// that a client dispatching few requests may still retry them.
+	DefaultRetryBudgetMinRetries = 10
+
+	// DefaultBootstrapTimeout is the default overall time allowed for bootstrapping, when bootstrap retries are enabled.
+	DefaultBootstrapTimeout = 2 * time.Minute
+
+	// DefaultBootstrapMinDelay is the default delay before the first bootstrap retry.
+	DefaultBootstrapMinDelay = time.Second
+
+	// DefaultBootstrapMaxDelay is the default maximum delay between bootstrap retries.
+	DefaultBootstrapMaxDelay = 15 * time.Second
+
 	// TimeoutsEnvVar is the environment variable that should be used to supply configurable timeouts for a REST HTTP
 	// client. If it is not provided then the default values are used.
 	TimeoutsEnvVar = "CB_REST_HTTP_TIMEOUTS"
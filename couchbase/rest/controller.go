@@ -0,0 +1,270 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// taskStartGracePeriod is the maximum amount of time to wait for a long running task to be reported by the cluster
+// after starting it; tasks which aren't reported within this period are assumed to have completed before being polled.
+const taskStartGracePeriod = 10 * time.Second
+
+// TaskType represents the type of a long running task returned by the tasks endpoint.
+type TaskType string
+
+const (
+	// TaskTypeRebalance is the task type for a cluster rebalance.
+	TaskTypeRebalance TaskType = "rebalance"
+
+	// TaskTypeBucketCompaction is the task type for bucket compaction.
+	TaskTypeBucketCompaction TaskType = "bucket_compaction"
+
+	// TaskTypeXDCR is the task type for an XDCR replication.
+	TaskTypeXDCR TaskType = "xdcr"
+)
+
+// TaskStatus represents the status of a long running task.
+type TaskStatus string
+
+const (
+	// TaskStatusRunning indicates that the task is still running.
+	TaskStatusRunning TaskStatus = "running"
+
+	// TaskStatusNotRunning indicates that the task is not running, for a rebalance this means it has completed (or has
+	// never been run).
+	TaskStatusNotRunning TaskStatus = "notRunning"
+
+	// TaskStatusPaused indicates that the task has been paused, only applicable to XDCR replications.
+	TaskStatusPaused TaskStatus = "paused"
+)
+
+// Task represents a single long running task, as returned by the tasks endpoint.
+type Task struct {
+	Type     TaskType   `json:"type"`
+	Status   TaskStatus `json:"status"`
+	Bucket   string     `json:"bucket,omitempty"`
+	Progress float64    `json:"progress"`
+
+	// ErrorMessage is populated for a rebalance which completed unsuccessfully.
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// TaskProgressFunc is called with the progress (as a percentage) of a long running task each time it's polled.
+type TaskProgressFunc func(progress float64)
+
+// RebalanceOptions encapsulates the options available when starting a rebalance.
+type RebalanceOptions struct {
+	// KnownNodes is the list of all the nodes in the cluster (by their 'otpNode' name), including those being ejected.
+	KnownNodes []string
+
+	// EjectedNodes is the list of nodes (by their 'otpNode' name) which will be removed from the cluster.
+	EjectedNodes []string
+
+	// Progress is called with the progress of the rebalance, may be omitted.
+	Progress TaskProgressFunc
+}
+
+// GetTasks returns the long running tasks for the cluster.
+func (c *Client) GetTasks(ctx context.Context) ([]Task, error) {
+	request := &Request{
+		Endpoint:           EndpointTasks,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	response, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var tasks []Task
+
+	err = json.Unmarshal(response.Body, &tasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// FlushBucket flushes all the data from the given bucket, flush must be enabled for the bucket.
+//
+// NOTE: The cluster only responds once the flush has completed, there's no task to poll.
+func (c *Client) FlushBucket(ctx context.Context, name string) error {
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointBucketFlush.Format(name),
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+		Timeout:            -1,
+	}
+
+	_, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	return nil
+}
+
+// CompactBucket triggers compaction for the given bucket, and waits until it has completed. The given progress
+// function (which may be <nil>) is called with the progress of the compaction.
+func (c *Client) CompactBucket(ctx context.Context, name string, progress TaskProgressFunc) error {
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointBucketCompact.Format(name),
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+	}
+
+	_, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	// Compaction is complete once the task is no longer reported by the cluster
+	poll := func(task *Task) (bool, error) {
+		if task == nil {
+			return true, nil
+		}
+
+		if progress != nil {
+			progress(task.Progress)
+		}
+
+		return false, nil
+	}
+
+	match := func(task Task) bool {
+		return task.Type == TaskTypeBucketCompaction && task.Bucket == name
+	}
+
+	// The task may not be reported immediately, so it's only complete once it has been seen
+	started := func(task *Task) bool {
+		return task != nil
+	}
+
+	return c.waitForTask(ctx, match, started, poll)
+}
+
+// StartRebalance starts rebalancing the cluster, and waits until it has completed. A 'RebalanceFailedError' is returned
+// if the rebalance completes unsuccessfully.
+func (c *Client) StartRebalance(ctx context.Context, opts RebalanceOptions) error {
+	values := make(url.Values)
+	values.Set("knownNodes", strings.Join(opts.KnownNodes, ","))
+	values.Set("ejectedNodes", strings.Join(opts.EjectedNodes, ","))
+
+	request := &Request{
+		Body:               []byte(values.Encode()),
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointRebalance,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+	}
+
+	_, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var final *Task
+
+	// The rebalance task is always reported by the cluster, it's complete once it's no longer running
+	poll := func(task *Task) (bool, error) {
+		if task == nil || task.Status != TaskStatusRunning {
+			final = task
+			return true, nil
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(task.Progress)
+		}
+
+		return false, nil
+	}
+
+	match := func(task Task) bool {
+		return task.Type == TaskTypeRebalance
+	}
+
+	// The task may be reported as 'notRunning' (e.g. from a previous rebalance) before the rebalance has started
+	started := func(task *Task) bool {
+		return task != nil && task.Status == TaskStatusRunning
+	}
+
+	err = c.waitForTask(ctx, match, started, poll)
+	if err != nil {
+		return err
+	}
+
+	if final != nil && final.ErrorMessage != "" {
+		return &RebalanceFailedError{message: final.ErrorMessage}
+	}
+
+	return nil
+}
+
+// waitForTask polls the tasks endpoint until the given function returns true for the first task which matches; the
+// function is called with a <nil> task if there's no matching task.
+//
+// NOTE: The function isn't called until the task has started, or 'taskStartGracePeriod' has elapsed, so that a task
+// which hasn't yet been reported by the cluster isn't mistaken for one which has completed.
+func (c *Client) waitForTask(
+	ctx context.Context,
+	match func(task Task) bool,
+	started func(task *Task) bool,
+	fn func(task *Task) (bool, error),
+) error {
+	var (
+		deadline = c.clock.Now().Add(taskStartGracePeriod)
+		seen     bool
+	)
+
+	poll := func(_ int) (bool, error) {
+		task, err := c.getTask(ctx, match)
+		if err != nil {
+			return false, err
+		}
+
+		seen = seen || started(task)
+
+		if !seen && c.clock.Now().Before(deadline) {
+			return false, nil
+		}
+
+		return fn(task)
+	}
+
+	_, err := c.PollWithContext(ctx, poll)
+	if err != nil {
+		return err
+	}
+
+	// Polling stops without an error if the context is cancelled, ensure we don't report success in that case
+	return ctx.Err()
+}
+
+// getTask returns the first task which matches the given function, or <nil> if there's no matching task.
+func (c *Client) getTask(ctx context.Context, match func(task Task) bool) (*Task, error) {
+	tasks, err := c.GetTasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	for _, task := range tasks {
+		if match(task) {
+			return &task, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,297 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTasksHandler returns a handler which responds with each of the given sets of tasks in turn, repeating the last.
+func newTasksHandler(t *testing.T, responses ...[]Task) http.HandlerFunc {
+	var polls int
+
+	return func(writer http.ResponseWriter, _ *http.Request) {
+		tasks := responses[min(polls, len(responses)-1)]
+		polls++
+
+		body, err := json.Marshal(tasks)
+		require.NoError(t, err)
+
+		writer.WriteHeader(http.StatusOK)
+
+		_, err = writer.Write(body)
+		require.NoError(t, err)
+	}
+}
+
+func TestClientGetTasks(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, string(EndpointTasks), NewTestHandler(t, http.StatusOK, []byte(`[
+		{"type":"rebalance","status":"notRunning","statusIsStale":false},
+		{"type":"bucket_compaction","status":"running","bucket":"default","progress":42.5}
+	]`)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	tasks, err := client.GetTasks(context.Background())
+	require.NoError(t, err)
+
+	expected := []Task{
+		{Type: TaskTypeRebalance, Status: TaskStatusNotRunning},
+		{Type: TaskTypeBucketCompaction, Status: TaskStatusRunning, Bucket: "default", Progress: 42.5},
+	}
+
+	require.Equal(t, expected, tasks)
+}
+
+func TestClientFlushBucket(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodPost, string(EndpointBucketFlush.Format("default")), NewTestHandler(t, http.StatusOK, nil))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.NoError(t, client.FlushBucket(context.Background(), "default"))
+}
+
+func TestClientFlushBucketNotEnabled(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(
+		http.MethodPost,
+		string(EndpointBucketFlush.Format("default")),
+		NewTestHandler(t, http.StatusBadRequest, []byte(`{"_":"Flush is disabled for the bucket"}`)),
+	)
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	var unexpected *UnexpectedStatusCodeError
+
+	require.ErrorAs(t, client.FlushBucket(context.Background(), "default"), &unexpected)
+	require.Equal(t, http.StatusBadRequest, unexpected.Status)
+}
+
+func TestClientCompactBucket(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodPost, string(EndpointBucketCompact.Format("default")), NewTestHandler(t, http.StatusOK, nil))
+
+	handlers.Add(http.MethodGet, string(EndpointTasks), newTasksHandler(t,
+		[]Task{
+			{Type: TaskTypeBucketCompaction, Status: TaskStatusRunning, Bucket: "other", Progress: 10},
+			{Type: TaskTypeBucketCompaction, Status: TaskStatusRunning, Bucket: "default", Progress: 50},
+		},
+		[]Task{{Type: TaskTypeBucketCompaction, Status: TaskStatusRunning, Bucket: "other", Progress: 20}},
+	))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	client.clock = &testClock{now: time.Now(), advance: true}
+
+	var progress []float64
+
+	err = client.CompactBucket(context.Background(), "default", func(p float64) { progress = append(progress, p) })
+	require.NoError(t, err)
+	require.Equal(t, []float64{50}, progress)
+}
+
+func TestClientCompactBucketNotYetReported(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodPost, string(EndpointBucketCompact.Format("default")), NewTestHandler(t, http.StatusOK, nil))
+
+	// The task isn't reported immediately after triggering compaction, which mustn't be mistaken for completion
+	handlers.Add(http.MethodGet, string(EndpointTasks), newTasksHandler(t,
+		[]Task{},
+		[]Task{{Type: TaskTypeBucketCompaction, Status: TaskStatusRunning, Bucket: "default", Progress: 50}},
+		[]Task{},
+	))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	client.clock = &testClock{now: time.Now(), advance: true}
+
+	var progress []float64
+
+	err = client.CompactBucket(context.Background(), "default", func(p float64) { progress = append(progress, p) })
+	require.NoError(t, err)
+	require.Equal(t, []float64{50}, progress)
+}
+
+func TestClientCompactBucketNeverReported(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodPost, string(EndpointBucketCompact.Format("default")), NewTestHandler(t, http.StatusOK, nil))
+	handlers.Add(http.MethodGet, string(EndpointTasks), newTasksHandler(t, []Task{}))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	clock := &testClock{now: time.Now(), advance: true}
+	client.clock = clock
+
+	// Compaction which completes before the first poll should be considered complete after the grace period
+	require.NoError(t, client.CompactBucket(context.Background(), "default", nil))
+	require.Len(t, clock.Waits(), int(taskStartGracePeriod/time.Second))
+}
+
+func TestClientStartRebalance(t *testing.T) {
+	var (
+		handlers = make(TestHandlers)
+		values   url.Values
+	)
+
+	handlers.Add(http.MethodPost, string(EndpointRebalance), NewTestHandlerWithValue(t, http.StatusOK, nil, &values))
+
+	handlers.Add(http.MethodGet, string(EndpointTasks), newTasksHandler(t,
+		[]Task{{Type: TaskTypeRebalance, Status: TaskStatusRunning, Progress: 25}},
+		[]Task{{Type: TaskTypeRebalance, Status: TaskStatusNotRunning}},
+	))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	client.clock = &testClock{now: time.Now(), advance: true}
+
+	var progress []float64
+
+	err = client.StartRebalance(context.Background(), RebalanceOptions{
+		KnownNodes:   []string{"ns_1@node1", "ns_1@node2"},
+		EjectedNodes: []string{"ns_1@node2"},
+		Progress:     func(p float64) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float64{25}, progress)
+
+	expected := url.Values{"knownNodes": {"ns_1@node1,ns_1@node2"}, "ejectedNodes": {"ns_1@node2"}}
+	require.Equal(t, expected, values)
+}
+
+func TestClientStartRebalancePreviouslyCompleted(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodPost, string(EndpointRebalance), NewTestHandler(t, http.StatusOK, nil))
+
+	// The task from a previous rebalance may be reported before the new one starts running
+	handlers.Add(http.MethodGet, string(EndpointTasks), newTasksHandler(t,
+		[]Task{{Type: TaskTypeRebalance, Status: TaskStatusNotRunning, ErrorMessage: "Rebalance failed"}},
+		[]Task{{Type: TaskTypeRebalance, Status: TaskStatusRunning, Progress: 25}},
+		[]Task{{Type: TaskTypeRebalance, Status: TaskStatusNotRunning}},
+	))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	client.clock = &testClock{now: time.Now(), advance: true}
+
+	var progress []float64
+
+	err = client.StartRebalance(context.Background(), RebalanceOptions{
+		KnownNodes: []string{"ns_1@node1"},
+		Progress:   func(p float64) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float64{25}, progress)
+}
+
+func TestClientStartRebalanceFailed(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodPost, string(EndpointRebalance), NewTestHandler(t, http.StatusOK, nil))
+
+	handlers.Add(http.MethodGet, string(EndpointTasks), newTasksHandler(t,
+		[]Task{{Type: TaskTypeRebalance, Status: TaskStatusNotRunning, ErrorMessage: "Rebalance failed"}},
+	))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	client.clock = &testClock{now: time.Now(), advance: true}
+
+	err = client.StartRebalance(context.Background(), RebalanceOptions{KnownNodes: []string{"ns_1@node1"}})
+	require.True(t, IsRebalanceFailed(err))
+}
+
+func TestClientStartRebalanceContextCancelled(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodPost, string(EndpointRebalance), NewTestHandler(t, http.StatusOK, nil))
+
+	handlers.Add(http.MethodGet, string(EndpointTasks), newTasksHandler(t,
+		[]Task{{Type: TaskTypeRebalance, Status: TaskStatusRunning}},
+	))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	client.clock = &testClock{now: time.Now(), advance: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err = client.StartRebalance(ctx, RebalanceOptions{
+		KnownNodes: []string{"ns_1@node1"},
+		Progress:   func(_ float64) { cancel() },
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,134 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// errBodyTooLarge is returned by a 'limitedReader' once the limit has been exceeded.
+var errBodyTooLarge = errors.New("body too large")
+
+// DecodeOptions encapsulates the options available when decoding a response body using 'ExecuteIntoWithOptions'.
+type DecodeOptions struct {
+	// UseNumber causes numbers to be decoded as a 'json.Number' rather than a 'float64', when decoding into an
+	// interface; this avoids losing precision e.g. for sequence numbers.
+	UseNumber bool
+
+	// DisallowUnknownFields causes an error to be returned if the body contains a field which doesn't match any
+	// exported field in the destination struct.
+	DisallowUnknownFields bool
+
+	// MaxBodySize is the maximum size of the body which will be decoded, a 'BodyTooLargeError' is returned for larger
+	// bodies. Defaults to 'DefaultMaxDecodeBodySize', a negative value disables the limit.
+	MaxBodySize int64
+}
+
+// defaults fills any missing attributes to a sane default.
+func (d *DecodeOptions) defaults() {
+	if d.MaxBodySize == 0 {
+		d.MaxBodySize = DefaultMaxDecodeBodySize
+	}
+}
+
+// ExecuteInto executes the given request, decoding the JSON response body into the given value using the default
+// decoding options; see 'ExecuteIntoWithOptions' for more information.
+func (c *Client) ExecuteInto(ctx context.Context, request *Request, out any) error {
+	return c.ExecuteIntoWithOptions(ctx, request, out, DecodeOptions{})
+}
+
+// ExecuteIntoWithOptions executes the given request whilst honoring request level retries/timeout, decoding the JSON
+// response body into the given value. The body is streamed into the decoder rather than being buffered, which reduces
+// the memory required for large responses e.g. for '/pools/default'.
+//
+// NOTE: The response cache is not used, however, requests which may modify an endpoint still invalidate its cached
+// responses.
+func (c *Client) ExecuteIntoWithOptions(ctx context.Context, request *Request, out any, opts DecodeOptions) error {
+	// Fill out any missing fields with the sane defaults
+	opts.defaults()
+
+	resp, err := c.Do(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer c.cleanupResp(resp)
+
+	// Any request which may modify an endpoint invalidates its cached responses, regardless of whether it succeeded
+	if c.cache != nil && request.Method != http.MethodGet {
+		c.cache.invalidate(request.Endpoint)
+	}
+
+	if resp.StatusCode != request.ExpectedStatusCode {
+		body, err := readBody(request.Method, request.Endpoint, resp.Body, resp.ContentLength)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		return handleResponseError(request.Method, request.Endpoint, resp, body)
+	}
+
+	tooLarge := &BodyTooLargeError{method: request.Method, endpoint: request.Endpoint, limit: opts.MaxBodySize}
+
+	// Fail fast where we know upfront that the body is too large
+	if opts.MaxBodySize > 0 && resp.ContentLength > opts.MaxBodySize {
+		return tooLarge
+	}
+
+	var reader io.Reader = resp.Body
+
+	if opts.MaxBodySize > 0 {
+		reader = &limitedReader{r: reader, remaining: opts.MaxBodySize}
+	}
+
+	decoder := json.NewDecoder(reader)
+
+	if opts.UseNumber {
+		decoder.UseNumber()
+	}
+
+	if opts.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	err = decoder.Decode(out)
+	if errors.Is(err, errBodyTooLarge) {
+		return tooLarge
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return nil
+}
+
+// limitedReader is a reader which returns an error once more than the given number of bytes have been read; unlike an
+// 'io.LimitedReader' this allows distinguishing between reaching the end of the body and exceeding the limit.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, errBodyTooLarge
+	}
+
+	// Allow reading one byte past the limit, so that we can detect when it's been exceeded
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.r.Read(p)
+
+	l.remaining -= int64(n)
+
+	if l.remaining < 0 {
+		return n, errBodyTooLarge
+	}
+
+	return n, err
+}
@@ -0,0 +1,166 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newDecodeTestClient(t *testing.T, status int, body string) (*Client, *TestCluster) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", NewTestHandler(t, status, []byte(body)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	return client, cluster
+}
+
+func newDecodeTestRequest() *Request {
+	return &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+}
+
+func TestClientExecuteInto(t *testing.T) {
+	client, cluster := newDecodeTestClient(t, http.StatusOK, `{"name":"value","count":42,"unknown":true}`)
+	defer cluster.Close()
+	defer client.Close()
+
+	var out struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	require.NoError(t, client.ExecuteInto(context.Background(), newDecodeTestRequest(), &out))
+	require.Equal(t, "value", out.Name)
+	require.Equal(t, 42, out.Count)
+}
+
+func TestClientExecuteIntoWithOptions(t *testing.T) {
+	client, cluster := newDecodeTestClient(t, http.StatusOK, `{"seqno":18446744073709551615}`)
+	defer cluster.Close()
+	defer client.Close()
+
+	var out map[string]any
+
+	err := client.ExecuteIntoWithOptions(context.Background(), newDecodeTestRequest(), &out, DecodeOptions{
+		UseNumber: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, json.Number("18446744073709551615"), out["seqno"])
+}
+
+func TestClientExecuteIntoDisallowUnknownFields(t *testing.T) {
+	client, cluster := newDecodeTestClient(t, http.StatusOK, `{"name":"value","unknown":true}`)
+	defer cluster.Close()
+	defer client.Close()
+
+	var out struct {
+		Name string `json:"name"`
+	}
+
+	err := client.ExecuteIntoWithOptions(context.Background(), newDecodeTestRequest(), &out, DecodeOptions{
+		DisallowUnknownFields: true,
+	})
+	require.ErrorContains(t, err, "unknown field")
+}
+
+func TestClientExecuteIntoBodyTooLarge(t *testing.T) {
+	client, cluster := newDecodeTestClient(t, http.StatusOK, `"`+strings.Repeat("a", 64)+`"`)
+	defer cluster.Close()
+	defer client.Close()
+
+	var out string
+
+	err := client.ExecuteIntoWithOptions(context.Background(), newDecodeTestRequest(), &out, DecodeOptions{
+		MaxBodySize: 32,
+	})
+
+	var tooLarge *BodyTooLargeError
+
+	require.ErrorAs(t, err, &tooLarge)
+
+	// A negative limit disables the limit
+	err = client.ExecuteIntoWithOptions(context.Background(), newDecodeTestRequest(), &out, DecodeOptions{
+		MaxBodySize: -1,
+	})
+	require.NoError(t, err)
+	require.Len(t, out, 64)
+}
+
+func TestClientExecuteIntoUnexpectedStatus(t *testing.T) {
+	client, cluster := newDecodeTestClient(t, http.StatusNotFound, "")
+	defer cluster.Close()
+	defer client.Close()
+
+	var out any
+
+	err := client.ExecuteInto(context.Background(), newDecodeTestRequest(), &out)
+
+	var notFound *EndpointNotFoundError
+
+	require.ErrorAs(t, err, &notFound)
+}
+
+func TestLimitedReader(t *testing.T) {
+	type test struct {
+		name     string
+		body     string
+		limit    int64
+		tooLarge bool
+	}
+
+	tests := []*test{
+		{
+			name:  "UnderLimit",
+			body:  "value",
+			limit: 10,
+		},
+		{
+			name:  "AtLimit",
+			body:  "value",
+			limit: 5,
+		},
+		{
+			name:     "OverLimit",
+			body:     "value",
+			limit:    4,
+			tooLarge: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader := &limitedReader{r: strings.NewReader(test.body), remaining: test.limit}
+
+			data := make([]byte, 0)
+			buffer := make([]byte, 2)
+
+			var err error
+
+			for err == nil {
+				var n int
+
+				n, err = reader.Read(buffer)
+				data = append(data, buffer[:n]...)
+			}
+
+			if test.tooLarge {
+				require.ErrorIs(t, err, errBodyTooLarge)
+				return
+			}
+
+			require.Equal(t, test.body, string(data))
+		})
+	}
+}
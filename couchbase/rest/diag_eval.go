@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// DiagEvalResult is the output of an Erlang expression evaluated using '/diag/eval'.
+type DiagEvalResult struct {
+	// Term is the value returned by the expression, printed as an Erlang term e.g. 'ok' or '<<"value">>'.
+	Term string
+
+	// JSON is the value returned by the expression when it's a JSON document (or a binary containing one) e.g. when
+	// the expression uses 'ejson:encode/1', <nil> otherwise.
+	JSON json.RawMessage
+}
+
+// newDiagEvalResult parses the given response body returned by '/diag/eval'.
+func newDiagEvalResult(body []byte) *DiagEvalResult {
+	trimmed := bytes.TrimSpace(body)
+
+	result := &DiagEvalResult{Term: string(trimmed)}
+
+	if json.Valid(trimmed) {
+		result.JSON = trimmed
+		return result
+	}
+
+	binary, ok := unwrapBinary(trimmed)
+	if ok && json.Valid(binary) {
+		result.JSON = binary
+	}
+
+	return result
+}
+
+// unwrapBinary returns the contents of the given printed Erlang binary e.g. '<<"value">>' returns 'value', a boolean is
+// returned indicating whether the term was a binary.
+func unwrapBinary(term []byte) ([]byte, bool) {
+	if !bytes.HasPrefix(term, []byte(`<<"`)) || !bytes.HasSuffix(term, []byte(`">>`)) {
+		return nil, false
+	}
+
+	// Erlang escapes quotes/backslashes in printed binaries in the same way as Go, other escape sequences are unlikely
+	// to be valid JSON regardless.
+	unquoted, err := strconv.Unquote(string(term[2 : len(term)-2]))
+	if err != nil {
+		return nil, false
+	}
+
+	return []byte(unquoted), true
+}
+
+// Decode unmarshals the JSON document returned by the expression into the given value, 'ErrDiagEvalNotJSON' is
+// returned if the expression didn't return a JSON document.
+func (d *DiagEvalResult) Decode(v any) error {
+	if d.JSON == nil {
+		return ErrDiagEvalNotJSON
+	}
+
+	return json.Unmarshal(d.JSON, v)
+}
+
+// DiagEval evaluates the given Erlang expression on a node in the cluster using '/diag/eval', returning its result.
+//
+// NOTE: Only available when the client was created using the 'AllowDiagEval' option, the expression is never logged
+// since it may contain credentials, and it's never retried since it may not be idempotent.
+func (c *Client) DiagEval(ctx context.Context, expr string) (*DiagEvalResult, error) {
+	if !c.allowDiagEval {
+		return nil, ErrDiagEvalNotAllowed
+	}
+
+	c.logger.Log(ctx, c.reqResLogLevel, "evaluating expression using '/diag/eval'", "size", len(expr))
+
+	request := &Request{
+		Body:               []byte(expr),
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointDiagEval,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+	}
+
+	response, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	return newDiagEvalResult(response.Body), nil
+}
@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/couchbase/tools-common/testing/util"
+)
+
+func TestNewDiagEvalResult(t *testing.T) {
+	type test struct {
+		name         string
+		body         string
+		expectedTerm string
+		expectedJSON string
+	}
+
+	tests := []*test{
+		{
+			name:         "Atom",
+			body:         "ok\n",
+			expectedTerm: "ok",
+		},
+		{
+			name:         "Tuple",
+			body:         `{error,"not found"}`,
+			expectedTerm: `{error,"not found"}`,
+		},
+		{
+			name:         "JSON",
+			body:         `{"key":"value"}`,
+			expectedTerm: `{"key":"value"}`,
+			expectedJSON: `{"key":"value"}`,
+		},
+		{
+			name:         "BinaryJSON",
+			body:         `<<"{\"key\":\"value\"}">>`,
+			expectedTerm: `<<"{\"key\":\"value\"}">>`,
+			expectedJSON: `{"key":"value"}`,
+		},
+		{
+			name:         "Binary",
+			body:         `<<"value">>`,
+			expectedTerm: `<<"value">>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := newDiagEvalResult([]byte(test.body))
+			require.Equal(t, test.expectedTerm, result.Term)
+
+			if test.expectedJSON == "" {
+				require.Nil(t, result.JSON)
+				require.ErrorIs(t, result.Decode(&map[string]string{}), ErrDiagEvalNotJSON)
+
+				return
+			}
+
+			require.JSONEq(t, test.expectedJSON, string(result.JSON))
+
+			var decoded map[string]string
+
+			require.NoError(t, result.Decode(&decoded))
+			require.Equal(t, map[string]string{"key": "value"}, decoded)
+		})
+	}
+}
+
+func TestDiagEvalNotAllowed(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.DiagEval(context.Background(), "ns_config:get().")
+	require.ErrorIs(t, err, ErrDiagEvalNotAllowed)
+}
+
+func TestDiagEval(t *testing.T) {
+	const expr = `ns_config:search(password).`
+
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodPost, string(EndpointDiagEval), func(writer http.ResponseWriter, request *http.Request) {
+		require.Equal(t, expr, string(testutil.ReadAll(t, request.Body)))
+
+		_, err := writer.Write([]byte(`<<"{\"enabled\":true}">>`))
+		require.NoError(t, err)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	pool := x509.NewCertPool()
+
+	if cluster.Certificate() != nil {
+		pool.AddCert(cluster.Certificate())
+	}
+
+	var buffer bytes.Buffer
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		TLSConfig:        &tls.Config{RootCAs: pool},
+		ReqResLogLevel:   slog.LevelDebug,
+		Logger:           slog.New(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		AllowDiagEval:    true,
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	result, err := client.DiagEval(context.Background(), expr)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Enabled bool `json:"enabled"`
+	}
+
+	require.NoError(t, result.Decode(&decoded))
+	require.True(t, decoded.Enabled)
+
+	require.Contains(t, buffer.String(), "/diag/eval")
+	require.NotContains(t, buffer.String(), "ns_config")
+}
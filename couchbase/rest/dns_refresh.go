@@ -0,0 +1,70 @@
+package rest
+
+// beginDNSRefresh begins the goroutine which periodically re-resolves the connection string, where it was resolved
+// using a DNS SRV record.
+//
+// NOTE: Must be called after 'beginCCP', the goroutine is cleaned up after a call to 'Close'.
+func (c *Client) beginDNSRefresh() {
+	if c.dnsRefreshInterval <= 0 || !c.authProvider.resolved.SRV {
+		return
+	}
+
+	c.wg.Add(1)
+
+	go c.pollDNS()
+}
+
+// pollDNS loops until cancelled, re-resolving the connection string at the configured interval.
+func (c *Client) pollDNS() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-c.clock.After(c.dnsRefreshInterval):
+		}
+
+		c.refreshDNS()
+	}
+}
+
+// refreshDNS re-resolves the connection string, merging any new addresses into the bootstrap hosts.
+func (c *Client) refreshDNS() {
+	resolved, err := c.resolve()
+	if err != nil {
+		c.logger.Warn("failed to re-resolve connection string, will retry", "error", err)
+		return
+	}
+
+	// The lookup falls back to using the hostname as an address when the SRV record doesn't resolve, which isn't useful
+	// as a bootstrap host.
+	if !resolved.SRV {
+		c.logger.Warn("failed to re-resolve connection string using DNS SRV record, will retry")
+		return
+	}
+
+	added := c.authProvider.mergeResolvedAddresses(resolved.Addresses)
+	if added == 0 {
+		return
+	}
+
+	c.logger.Info("added bootstrap hosts from DNS SRV record", "added", added)
+}
+
+// updateCCFromBootstrapHosts attempts to update the cluster config using each of the bootstrap hosts, returning a
+// boolean indicating whether the update was successful.
+func (c *Client) updateCCFromBootstrapHosts() bool {
+	hostFunc := c.authProvider.bootstrapHostFunc()
+
+	for host := hostFunc(); host != ""; host = hostFunc() {
+		err := c.updateCCFromValidHost(host)
+		if err == nil {
+			return true
+		}
+
+		c.logger.Warn("failed to update config using bootstrap host", "hostname", host, "error", err)
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/couchbase/v3/connstr"
+)
+
+func TestClientOptionsDefaultsDNSRefreshInterval(t *testing.T) {
+	options := ClientOptions{}
+	options.defaults()
+	require.Equal(t, DefaultDNSRefreshInterval, options.DNSRefreshInterval)
+
+	options = ClientOptions{DNSRefreshInterval: -1}
+	options.defaults()
+	require.Equal(t, time.Duration(-1), options.DNSRefreshInterval)
+}
+
+func TestClientRefreshDNS(t *testing.T) {
+	type test struct {
+		name     string
+		resolved *connstr.ResolvedConnectionString
+		err      error
+		expected []connstr.Address
+	}
+
+	tests := []*test{
+		{
+			name: "SRV",
+			resolved: &connstr.ResolvedConnectionString{
+				Addresses: []connstr.Address{{Host: "hostname1", Port: 8091}, {Host: "hostname2", Port: 8091}},
+				SRV:       true,
+			},
+			expected: []connstr.Address{{Host: "hostname1", Port: 8091}, {Host: "hostname2", Port: 8091}},
+		},
+		{
+			name: "NotSRV",
+			resolved: &connstr.ResolvedConnectionString{
+				Addresses: []connstr.Address{{Host: "hostname2", Port: 8091}},
+			},
+			expected: []connstr.Address{{Host: "hostname1", Port: 8091}},
+		},
+		{
+			name:     "Error",
+			err:      errors.New("failed to resolve"),
+			expected: []connstr.Address{{Host: "hostname1", Port: 8091}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &Client{
+				authProvider: &AuthProvider{
+					resolved: &connstr.ResolvedConnectionString{
+						Addresses: []connstr.Address{{Host: "hostname1", Port: 8091}},
+						SRV:       true,
+					},
+				},
+				resolve: func() (*connstr.ResolvedConnectionString, error) { return test.resolved, test.err },
+				logger:  slog.Default(),
+			}
+
+			client.refreshDNS()
+
+			require.Equal(t, test.expected, client.authProvider.resolved.Addresses)
+		})
+	}
+}
+
+func TestClientPollDNS(t *testing.T) {
+	var (
+		resolved = make(chan struct{}, 1)
+		client   = &Client{
+			authProvider: &AuthProvider{
+				resolved: &connstr.ResolvedConnectionString{
+					Addresses: []connstr.Address{{Host: "hostname1", Port: 8091}},
+					SRV:       true,
+				},
+			},
+			dnsRefreshInterval: time.Millisecond,
+			clock:              systemClock{},
+			logger:             slog.Default(),
+		}
+	)
+
+	client.resolve = func() (*connstr.ResolvedConnectionString, error) {
+		select {
+		case resolved <- struct{}{}:
+		default:
+		}
+
+		return &connstr.ResolvedConnectionString{
+			Addresses: []connstr.Address{{Host: "hostname2", Port: 8091}},
+			SRV:       true,
+		}, nil
+	}
+
+	client.ctx, client.cancelFunc = context.WithCancel(context.Background())
+
+	client.beginDNSRefresh()
+
+	<-resolved
+
+	client.Close()
+
+	require.Contains(t, client.authProvider.resolved.Addresses, connstr.Address{Host: "hostname2", Port: 8091})
+}
+
+func TestClientUpdateCCFromBootstrapHosts(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	client.authProvider.manager.(*ClusterConfigManager).config.Nodes = make(Nodes, 0)
+	rev := client.authProvider.manager.(*ClusterConfigManager).config.Revision
+
+	// The bootstrap hosts are only used where they were resolved using a DNS SRV record
+	require.ErrorIs(t, client.updateCC(), ErrExhaustedClusterNodes)
+
+	client.authProvider.resolved.SRV = true
+
+	require.NoError(t, client.updateCC())
+	require.Equal(t, rev+1, client.authProvider.manager.(*ClusterConfigManager).config.Revision)
+}
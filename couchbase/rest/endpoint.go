@@ -3,6 +3,7 @@ package rest
 import (
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 // Endpoint represents a single REST endpoint. Requests should only be dispatched to endpoints which exist in this file
@@ -30,8 +31,62 @@ const (
 	// collection manifest for a bucket.
 	EndpointBucketManifest Endpoint = "/pools/default/buckets/%s/scopes"
 
+	// EndpointBucketFlush is used to flush all the data from a bucket, flush must be enabled for the bucket.
+	EndpointBucketFlush Endpoint = "/pools/default/buckets/%s/controller/doFlush"
+
+	// EndpointBucketCompact is used to trigger compaction for a bucket.
+	EndpointBucketCompact Endpoint = "/pools/default/buckets/%s/controller/compactBucket"
+
+	// EndpointTasks is used to fetch the status of the long running tasks in the cluster e.g. rebalance/compaction.
+	EndpointTasks Endpoint = "/pools/default/tasks"
+
+	// EndpointRebalance is used to begin rebalancing the cluster.
+	EndpointRebalance Endpoint = "/controller/rebalance"
+
 	// EndpointNodesServices is used during the bootstrapping process to fetch a list of all the nodes in the cluster.
 	EndpointNodesServices Endpoint = "/pools/default/nodeServices"
+
+	// EndpointDiag represents the diagnostics endpoint, which returns diagnostic information about a single node.
+	EndpointDiag Endpoint = "/diag"
+
+	// EndpointDiagEval is used to evaluate an arbitrary Erlang expression on a single node, see 'DiagEval'.
+	EndpointDiagEval Endpoint = "/diag/eval"
+
+	// EndpointSASLLogs represents the endpoint used to fetch a named log file from a single node.
+	EndpointSASLLogs Endpoint = "/sasl_logs/%s"
+
+	// EndpointUILogin is used to create a session for a single node, which is used when authenticating requests with
+	// 'AuthModeSession'.
+	EndpointUILogin Endpoint = "/uilogin"
+
+	// EndpointRemoteClusters is used to list/create XDCR remote cluster references.
+	EndpointRemoteClusters Endpoint = "/pools/default/remoteClusters"
+
+	// EndpointRemoteCluster represents the endpoint for interacting with a specific named XDCR remote cluster reference.
+	EndpointRemoteCluster Endpoint = "/pools/default/remoteClusters/%s"
+
+	// EndpointCreateReplication is used to create an XDCR replication to a remote cluster.
+	EndpointCreateReplication Endpoint = "/controller/createReplication"
+
+	// EndpointCancelReplication is used to delete the XDCR replication with the given id.
+	EndpointCancelReplication Endpoint = "/controller/cancelXDCR/%s"
+
+	// EndpointReplicationSettings is used to get/update the settings for the XDCR replication with the given id, this
+	// includes pausing/resuming the replication.
+	EndpointReplicationSettings Endpoint = "/settings/replications/%s"
+
+	// EndpointMetrics is used to scrape the metrics for a node in the Prometheus exposition format.
+	EndpointMetrics Endpoint = "/metrics"
+
+	// EndpointMetricsHigh is used to scrape the high cardinality metrics for a node in the Prometheus exposition
+	// format.
+	EndpointMetricsHigh Endpoint = "/_prometheusMetricsHigh"
+
+	// EndpointSearchIndex is used to create/get/delete the Search (FTS) index definition with the given name.
+	EndpointSearchIndex Endpoint = "/api/index/%s"
+
+	// EndpointSearchIndexProgress is used to get the build progress of the Search (FTS) index with the given name.
+	EndpointSearchIndexProgress Endpoint = "/api/stats/index/%s/progress"
 )
 
 // Format returns a new endpoint using 'fmt.Sprintf' to fill in any missing/required elements of the endpoint using the
@@ -41,8 +96,36 @@ const (
 func (e Endpoint) Format(args ...string) Endpoint {
 	escaped := make([]any, len(args))
 	for index, arg := range args {
-		escaped[index] = url.PathEscape(arg)
+		escaped[index] = escapeSegment(arg)
 	}
 
 	return Endpoint(fmt.Sprintf(string(e), escaped...))
 }
+
+// With returns a new endpoint with the given path segments appended, each segment is path escaped meaning user supplied
+// values (e.g. bucket names) can't alter the structure of the endpoint.
+//
+// For example, 'EndpointBuckets.With(name, "controller", "compactBucket")' is equivalent to
+// 'EndpointBucketCompact.Format(name)'.
+func (e Endpoint) With(segments ...string) Endpoint {
+	var builder strings.Builder
+
+	builder.WriteString(strings.TrimSuffix(string(e), "/"))
+
+	for _, segment := range segments {
+		builder.WriteString("/")
+		builder.WriteString(escapeSegment(segment))
+	}
+
+	return Endpoint(builder.String())
+}
+
+// escapeSegment path escapes the given segment, including dot segments which would otherwise be interpreted as
+// relative references e.g. a bucket named '..'.
+func escapeSegment(segment string) string {
+	if segment == "." || segment == ".." {
+		return strings.ReplaceAll(segment, ".", "%2E")
+	}
+
+	return url.PathEscape(segment)
+}
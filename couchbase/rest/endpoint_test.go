@@ -26,6 +26,12 @@ func TestEndpointFormat(t *testing.T) {
 			args:     []string{"b/uc%ket"},
 			expected: "/pools/default/buckets/b%2Fuc%25ket",
 		},
+		{
+			name:     "EndpointWithDotSegment",
+			input:    "/pools/default/buckets/%s/scopes",
+			args:     []string{".."},
+			expected: "/pools/default/buckets/%2E%2E/scopes",
+		},
 	}
 
 	for _, test := range tests {
@@ -34,3 +40,56 @@ func TestEndpointFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestEndpointWith(t *testing.T) {
+	type test struct {
+		name     string
+		input    Endpoint
+		segments []string
+		expected Endpoint
+	}
+
+	tests := []*test{
+		{
+			name:     "NoSegments",
+			input:    EndpointBuckets,
+			expected: EndpointBuckets,
+		},
+		{
+			name:     "SingleSegment",
+			input:    EndpointBuckets,
+			segments: []string{"default"},
+			expected: EndpointBucket.Format("default"),
+		},
+		{
+			name:     "MultipleSegments",
+			input:    EndpointBuckets,
+			segments: []string{"default", "controller", "compactBucket"},
+			expected: EndpointBucketCompact.Format("default"),
+		},
+		{
+			name:     "TrailingSlash",
+			input:    "/pools/default/buckets/",
+			segments: []string{"default"},
+			expected: "/pools/default/buckets/default",
+		},
+		{
+			name:     "SegmentWithEscape",
+			input:    EndpointBuckets,
+			segments: []string{"b/uc%ket?name", "scopes"},
+			expected: "/pools/default/buckets/b%2Fuc%25ket%3Fname/scopes",
+		},
+		{
+			name:     "DotSegments",
+			input:    EndpointBuckets,
+			segments: []string{"..", "."},
+			expected: "/pools/default/buckets/%2E%2E/%2E",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.input.With(test.segments...))
+		})
+	}
+}
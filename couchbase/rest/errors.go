@@ -3,7 +3,10 @@ package rest
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 
+	cbvalue "github.com/couchbase/tools-common/couchbase/v3/value"
 	"github.com/couchbase/tools-common/strings/format"
 )
 
@@ -34,19 +37,99 @@ var (
 
 	// ErrInvalidNetwork is returned if the user supplies an invalid value for the 'network' query parameter.
 	ErrInvalidNetwork = errors.New("invalid use of 'network' query parameter, expected 'default' or 'external'")
+
+	// ErrDeadlineWouldBeExceeded is returned if the cluster has asked us to wait before retrying a request (using the
+	// 'Retry-After' header), however, doing so would exceed the deadline of the request.
+	ErrDeadlineWouldBeExceeded = errors.New("waiting to retry the request would exceed its deadline")
+
+	// ErrNoSessionCookie is returned if the cluster didn't return a session cookie after successfully logging in, when
+	// using 'AuthModeSession'.
+	ErrNoSessionCookie = errors.New("login succeeded, but no session cookie was returned")
+
+	// ErrProxyURLAndFromEnvironment is returned if the user supplies both an explicit proxy URL, and requests that the
+	// proxy is read from the environment.
+	ErrProxyURLAndFromEnvironment = errors.New("proxy URL and 'FromEnvironment' are mutually exclusive")
+
+	// ErrNoNodes is returned when the cluster doesn't return any nodes, when fetching the version of the cluster.
+	ErrNoNodes = errors.New("cluster returned no nodes")
+
+	// ErrRetryBudgetExhausted is returned when a request would have been retried, however, the client has already
+	// performed the maximum number of retries permitted by its retry budget.
+	ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+	// ErrDiagEvalNotAllowed is returned when attempting to use 'DiagEval' with a client which wasn't created with the
+	// 'AllowDiagEval' option.
+	ErrDiagEvalNotAllowed = errors.New("evaluating expressions using '/diag/eval' is not allowed by this client")
+
+	// ErrDiagEvalNotJSON is returned when attempting to decode the result of 'DiagEval' which is an Erlang term, rather
+	// than a JSON document.
+	ErrDiagEvalNotJSON = errors.New("result of expression is not a JSON document")
 )
 
+// responseDetails contains selected information about the response which resulted in an error, allowing failures to be
+// correlated with the logs on the cluster. It's embedded in the errors created from unexpected responses, so that these
+// methods are available on each of them.
+type responseDetails struct {
+	host        string
+	requestID   string
+	retryAfter  string
+	contentType string
+}
+
+// newResponseDetails returns the details for the given response, which may be nil.
+func newResponseDetails(resp *http.Response) responseDetails {
+	if resp == nil {
+		return responseDetails{}
+	}
+
+	details := responseDetails{
+		requestID:   resp.Header.Get("X-Request-Id"),
+		retryAfter:  resp.Header.Get("Retry-After"),
+		contentType: resp.Header.Get("Content-Type"),
+	}
+
+	if resp.Request != nil && resp.Request.URL != nil {
+		details.host = resp.Request.URL.Host
+	}
+
+	return details
+}
+
+// Host returns the host (including the port) which returned the response.
+func (r responseDetails) Host() string {
+	return r.host
+}
+
+// RequestID returns the value of the 'X-Request-Id' header from the response, or an empty string if it wasn't set.
+func (r responseDetails) RequestID() string {
+	return r.requestID
+}
+
+// RetryAfter returns the raw value of the 'Retry-After' header from the response, which may either be a number of
+// seconds or an HTTP date, or an empty string if it wasn't set.
+func (r responseDetails) RetryAfter() string {
+	return r.retryAfter
+}
+
+// ContentType returns the value of the 'Content-Type' header from the response, or an empty string if it wasn't set.
+func (r responseDetails) ContentType() string {
+	return r.contentType
+}
+
 // BootstrapFailureError is returned to the user if we've failed to bootstrap the REST client.
 //
 // NOTE: The error message varies depending on whether we received at least one 401 when attempting to bootstrap.
 type BootstrapFailureError struct {
 	ErrAuthentication error
 	ErrAuthorization  error
+	ErrCredentials    error
 }
 
 func (e *BootstrapFailureError) Error() string {
 	msg := "failed to connect to any host(s) from the connection string"
-	if e.ErrAuthentication != nil {
+	if e.ErrCredentials != nil {
+		msg += ", failed to get credentials: " + e.ErrCredentials.Error()
+	} else if e.ErrAuthentication != nil {
 		msg += ", check username and password"
 	} else if e.ErrAuthorization != nil {
 		msg += ", user does not have the required permissions"
@@ -57,9 +140,25 @@ func (e *BootstrapFailureError) Error() string {
 	return msg
 }
 
+// CredentialsError is returned if we failed to get credentials from the auth provider, after retrying with backoff.
+// This is distinct from an 'AuthenticationError', where the cluster rejected the credentials.
+type CredentialsError struct {
+	host string
+	err  error
+}
+
+func (e *CredentialsError) Error() string {
+	return fmt.Sprintf("failed to get credentials for host '%s': %s", e.host, e.err)
+}
+
+func (e *CredentialsError) Unwrap() error {
+	return e.err
+}
+
 // AuthorizationError is returned if we receive a 403 status code from the cluster which means the credentials are
 // correct but they don't have the needed permissions.
 type AuthorizationError struct {
+	responseDetails
 	method      Method
 	endpoint    Endpoint
 	permissions []string
@@ -78,6 +177,7 @@ func (e *AuthorizationError) Error() string {
 // AuthenticationError is returned if we received a 401 status code from the cluster i.e. the users credentials are
 // incorrect.
 type AuthenticationError struct {
+	responseDetails
 	method   Method
 	endpoint Endpoint
 }
@@ -88,6 +188,7 @@ func (e *AuthenticationError) Error() string {
 
 // InternalServerError is returned if we received a 500 status code from the cluster.
 type InternalServerError struct {
+	responseDetails
 	method   Method
 	endpoint Endpoint
 	body     []byte
@@ -104,6 +205,7 @@ func (e *InternalServerError) Error() string {
 
 // EndpointNotFoundError is returned if we received a 404 status code from the cluster.
 type EndpointNotFoundError struct {
+	responseDetails
 	method   Method
 	endpoint Endpoint
 }
@@ -119,12 +221,38 @@ func IsEndpointNotFound(err error) bool {
 	return err != nil && errors.As(err, &notFound)
 }
 
+// PreconditionFailedError is returned if we received a 412 status code from the cluster, this indicates that the
+// resource was modified since the entity tag provided using 'IfMatch' was retrieved.
+type PreconditionFailedError struct {
+	responseDetails
+	method   Method
+	endpoint Endpoint
+	body     []byte
+}
+
+func (e *PreconditionFailedError) Error() string {
+	msg := fmt.Sprintf("precondition failed executing '%s' request to '%s', the resource has been modified", e.method,
+		e.endpoint)
+	if len(e.body) != 0 {
+		msg += fmt.Sprintf(": %s", e.body)
+	}
+
+	return msg
+}
+
+// IsPreconditionFailed returns a boolean indicating whether the given error is a 'PreconditionFailedError'.
+func IsPreconditionFailed(err error) bool {
+	var preconditionFailed *PreconditionFailedError
+	return err != nil && errors.As(err, &preconditionFailed)
+}
+
 // UnexpectedStatusCodeError returned if a request was executed successfully, however, we received a response status
 // code which was unexpected.
 //
 // NOTE: During development its possible to hit this error in the event that the expected status code is set incorrectly
 // and the successful response does not return a body so is therefore something to watch out for.
 type UnexpectedStatusCodeError struct {
+	responseDetails
 	Status   int
 	method   Method
 	endpoint Endpoint
@@ -158,6 +286,21 @@ func IsServiceNotAvailable(err error) bool {
 	return err != nil && errors.As(err, &notAvailable)
 }
 
+// NodeNotFoundError is returned when a request is scoped to a node which is not in the cluster.
+type NodeNotFoundError struct {
+	uuid string
+}
+
+func (e *NodeNotFoundError) Error() string {
+	return fmt.Sprintf("node '%s' not found in the cluster", e.uuid)
+}
+
+// IsNodeNotFound returns a boolean indicating whether the given error is a 'NodeNotFoundError'.
+func IsNodeNotFound(err error) bool {
+	var notFound *NodeNotFoundError
+	return err != nil && errors.As(err, &notFound)
+}
+
 // UnknownAuthorityError is returned when the dispatched REST request receives an 'UnknownAuthorityError'.
 type UnknownAuthorityError struct {
 	inner error
@@ -210,6 +353,18 @@ func (e *UnexpectedEndOfBodyError) Error() string {
 		e.method, e.endpoint, format.Bytes(uint64(e.expected)), format.Bytes(uint64(e.got)))
 }
 
+// BodyTooLargeError is returned when reading/decoding a response body which is larger than the configured limit.
+type BodyTooLargeError struct {
+	method   Method
+	endpoint Endpoint
+	limit    int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("response body for '%s' request to '%s' exceeds the limit of %s", e.method, e.endpoint,
+		format.Bytes(uint64(e.limit)))
+}
+
 // RetriesExhaustedError is returned if the REST request was retried the maximum number of times.
 //
 // NOTE: The number of times retried is configurable at runtime via an environment variable. See the 'Client'
@@ -236,3 +391,109 @@ type OldClusterConfigError struct {
 func (e *OldClusterConfigError) Error() string {
 	return fmt.Sprintf("cluster config revision %d is older than the current revision %d", e.old, e.curr)
 }
+
+// RebalanceFailedError is returned when a rebalance started using 'StartRebalance' completes unsuccessfully.
+type RebalanceFailedError struct {
+	message string
+}
+
+func (e *RebalanceFailedError) Error() string {
+	return fmt.Sprintf("rebalance failed: %s", e.message)
+}
+
+// IsRebalanceFailed returns a boolean indicating whether the given error is a 'RebalanceFailedError'.
+func IsRebalanceFailed(err error) bool {
+	var rebalanceFailed *RebalanceFailedError
+	return err != nil && errors.As(err, &rebalanceFailed)
+}
+
+// SearchIndexNotFoundError is returned when the Search index being accessed doesn't exist.
+type SearchIndexNotFoundError struct {
+	name string
+}
+
+func (e *SearchIndexNotFoundError) Error() string {
+	return fmt.Sprintf("search index '%s' not found", e.name)
+}
+
+// IsSearchIndexNotFound returns a boolean indicating whether the given error is a 'SearchIndexNotFoundError'.
+func IsSearchIndexNotFound(err error) bool {
+	var notFound *SearchIndexNotFoundError
+	return err != nil && errors.As(err, &notFound)
+}
+
+// NodeInMaintenanceError is returned when a request couldn't be dispatched because all the nodes running the required
+// service are being failed over, or are in maintenance.
+type NodeInMaintenanceError struct {
+	service Service
+	nodes   []string
+}
+
+func (e *NodeInMaintenanceError) Error() string {
+	return fmt.Sprintf("all nodes running the '%s' service are in maintenance or being failed over: %s",
+		e.service, strings.Join(e.nodes, ", "))
+}
+
+// IsNodeInMaintenance returns a boolean indicating whether the given error is a 'NodeInMaintenanceError'.
+func IsNodeInMaintenance(err error) bool {
+	var maintenance *NodeInMaintenanceError
+	return err != nil && errors.As(err, &maintenance)
+}
+
+// UnsupportedServerVersionError is returned when attempting to use a feature which isn't supported by the version of
+// Couchbase Server running on the cluster; 'Required' is the minimum version which all the nodes must be running.
+type UnsupportedServerVersionError struct {
+	Required cbvalue.Version
+	feature  string
+	version  cbvalue.Version
+}
+
+func (e *UnsupportedServerVersionError) Error() string {
+	return fmt.Sprintf("%s requires all nodes to be running Couchbase Server %s or later, but the cluster version is %s",
+		e.feature, e.Required, e.version)
+}
+
+// IsUnsupportedServerVersion returns a boolean indicating whether the given error is an
+// 'UnsupportedServerVersionError'.
+func IsUnsupportedServerVersion(err error) bool {
+	var unsupported *UnsupportedServerVersionError
+	return err != nil && errors.As(err, &unsupported)
+}
+
+// NodesFailedError is returned by 'ExecuteOnAllNodes' when the request failed on at least one node; the results for the
+// nodes where the request succeeded are still returned.
+type NodesFailedError struct {
+	failed []NodeResult
+	total  int
+}
+
+func (e *NodesFailedError) Error() string {
+	msgs := make([]string, 0, len(e.failed))
+
+	for _, result := range e.failed {
+		msgs = append(msgs, fmt.Sprintf("'%s': %s", result.Host, result.Err))
+	}
+
+	return fmt.Sprintf("request failed on %d/%d node(s): %s", len(e.failed), e.total, strings.Join(msgs, ", "))
+}
+
+func (e *NodesFailedError) Unwrap() []error {
+	errs := make([]error, 0, len(e.failed))
+
+	for _, result := range e.failed {
+		errs = append(errs, result.Err)
+	}
+
+	return errs
+}
+
+// Hosts returns the hosts of the nodes where the request failed.
+func (e *NodesFailedError) Hosts() []string {
+	hosts := make([]string, 0, len(e.failed))
+
+	for _, result := range e.failed {
+		hosts = append(hosts, result.Host)
+	}
+
+	return hosts
+}
@@ -0,0 +1,359 @@
+package rest
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// LogDiag is the name given to the output of the '/diag' endpoint when it's passed to a 'LogWriterFunc'.
+const LogDiag = "diag"
+
+// serviceLogs maps each service to the logs that are collected for it using the '/sasl_logs' endpoint.
+var serviceLogs = map[Service][]string{
+	ServiceManagement: {"debug", "info", "error", "babysitter"},
+	ServiceAnalytics:  {"analytics_info"},
+	ServiceData:       {"memcached"},
+	ServiceEventing:   {"eventing"},
+	ServiceGSI:        {"indexer", "projector"},
+	ServiceQuery:      {"query"},
+	ServiceSearch:     {"fts"},
+	ServiceViews:      {"couchdb"},
+	ServiceBackup:     {"backup_service"},
+}
+
+// LogWriterFunc returns the writer that the given log, collected from the node with the given hostname, should be
+// written to. The writer will be closed once the log has been collected.
+//
+// NOTE: Logs which aren't append only (e.g. 'LogDiag') may only be resumed after a transient failure if the writer
+// supports being truncated (e.g. an '*os.File'), since collection must restart from the beginning.
+type LogWriterFunc func(hostname, name string) (io.WriteCloser, error)
+
+// truncater is a writer which may be truncated, allowing collection of a log to be restarted.
+type truncater interface {
+	io.Seeker
+	Truncate(size int64) error
+}
+
+// LogCollectorOptions encapsulates the options available when creating a new log collector.
+type LogCollectorOptions struct {
+	// Client is the client used to dispatch requests to each node.
+	//
+	// NOTE: Required
+	Client *Client
+
+	// Writer returns the writer for each collected log, this may be used to write logs to disk, or upload them to
+	// object storage.
+	//
+	// NOTE: Required
+	Writer LogWriterFunc
+
+	// Services limits collection to the logs for the given services, when omitted logs for all services are collected.
+	// The '/diag' endpoint is only collected when the management service is included.
+	Services []Service
+
+	// Compress indicates that logs should be gzip compressed before being passed to the writer.
+	Compress bool
+
+	// Concurrency is the number of logs which will be collected concurrently, defaults to four.
+	Concurrency int
+
+	// Resumes is the number of times collection of a log will be resumed after a transient failure whilst streaming,
+	// defaults to three.
+	Resumes int
+
+	// Logger is the passed logger which implements a custom Log method
+	Logger *slog.Logger
+}
+
+// defaults fills any missing attributes to a sane default.
+func (l *LogCollectorOptions) defaults() {
+	if len(l.Services) == 0 {
+		l.Services = []Service{
+			ServiceManagement,
+			ServiceAnalytics,
+			ServiceData,
+			ServiceEventing,
+			ServiceGSI,
+			ServiceQuery,
+			ServiceSearch,
+			ServiceViews,
+			ServiceBackup,
+		}
+	}
+
+	if l.Concurrency == 0 {
+		l.Concurrency = 4
+	}
+
+	if l.Resumes == 0 {
+		l.Resumes = 3
+	}
+
+	if l.Logger == nil {
+		l.Logger = slog.Default()
+	}
+}
+
+// LogCollector collects diagnostic information and logs from every node in a cluster, streaming them to caller provided
+// writers.
+type LogCollector struct {
+	client      *Client
+	writer      LogWriterFunc
+	services    []Service
+	compress    bool
+	concurrency int
+	resumes     int
+	logger      *slog.Logger
+}
+
+// NewLogCollector returns a new log collector using the given options.
+func NewLogCollector(options LogCollectorOptions) *LogCollector {
+	// Fill out any missing fields with the sane defaults
+	options.defaults()
+
+	collector := LogCollector{
+		client:      options.Client,
+		writer:      options.Writer,
+		services:    options.Services,
+		compress:    options.Compress,
+		concurrency: options.Concurrency,
+		resumes:     options.Resumes,
+		logger:      options.Logger,
+	}
+
+	return &collector
+}
+
+// logTask represents a single log which should be collected from a node.
+type logTask struct {
+	hostname string
+	host     string
+	name     string
+	endpoint Endpoint
+
+	// appendOnly indicates that the log is only ever appended to, so collection may be resumed from where it left off.
+	appendOnly bool
+}
+
+// Collect collects the logs from every node in the cluster, returning the first error encountered.
+func (l *LogCollector) Collect(ctx context.Context) error {
+	tasks, err := l.tasks()
+	if err != nil {
+		return fmt.Errorf("failed to determine logs to collect: %w", err)
+	}
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(l.concurrency)
+
+	for _, task := range tasks {
+		group.Go(func() error {
+			err := l.collect(ctx, task)
+			if err != nil {
+				return fmt.Errorf("failed to collect log '%s' from node '%s': %w", task.name, task.hostname, err)
+			}
+
+			return nil
+		})
+	}
+
+	return group.Wait()
+}
+
+// tasks returns the logs which should be collected from each node in the cluster.
+func (l *LogCollector) tasks() ([]logTask, error) {
+	tasks := make([]logTask, 0)
+
+	for _, node := range l.client.Nodes() {
+		// When only communicating with the bootstrap node, the other nodes may not be reachable
+		if l.client.connectionMode.ThisNodeOnly() && !node.BootstrapNode {
+			continue
+		}
+
+		host, _ := node.GetQualifiedHostname(ServiceManagement, l.client.TLS(), l.client.AltAddr())
+		if host == "" {
+			return nil, fmt.Errorf("node '%s' has no reachable management address", node.Hostname)
+		}
+
+		for _, service := range l.services {
+			if node.GetPort(service, l.client.TLS(), l.client.AltAddr()) == 0 {
+				continue
+			}
+
+			if service == ServiceManagement {
+				tasks = append(tasks, logTask{hostname: node.Hostname, host: host, name: LogDiag, endpoint: EndpointDiag})
+			}
+
+			for _, name := range serviceLogs[service] {
+				tasks = append(tasks, logTask{
+					hostname:   node.Hostname,
+					host:       host,
+					name:       name,
+					endpoint:   EndpointSASLLogs.Format(name),
+					appendOnly: true,
+				})
+			}
+		}
+	}
+
+	return tasks, nil
+}
+
+// collect streams the given log to the writer returned by the user provided function.
+func (l *LogCollector) collect(ctx context.Context, task logTask) error {
+	writer, err := l.writer(task.hostname, task.name)
+	if err != nil {
+		return fmt.Errorf("failed to get writer: %w", err)
+	}
+
+	err = l.write(ctx, task, writer)
+	if err != nil {
+		writer.Close()
+		return err
+	}
+
+	return writer.Close()
+}
+
+// write streams the given log to the given writer, optionally compressing it, resuming upon transient failures.
+func (l *LogCollector) write(ctx context.Context, task logTask, writer io.Writer) error {
+	var (
+		dst   = &logWriter{writer: writer}
+		gzipW *gzip.Writer
+	)
+
+	if l.compress {
+		gzipW = gzip.NewWriter(writer)
+		dst.writer = gzipW
+	}
+
+	for attempt := 0; ; attempt++ {
+		resumable, err := l.stream(ctx, task, dst)
+		if err == nil {
+			break
+		}
+
+		if !resumable || attempt >= l.resumes || ctx.Err() != nil {
+			return err
+		}
+
+		// The log may have changed since we started collecting it, so we must start again from the beginning
+		if !task.appendOnly {
+			if !restart(writer, dst, gzipW) {
+				return err
+			}
+		}
+
+		l.logger.Warn(
+			"failed to stream log, will resume",
+			"hostname", task.hostname,
+			"name", task.name,
+			"written", dst.written,
+			"error", err,
+		)
+	}
+
+	if gzipW == nil {
+		return nil
+	}
+
+	err := gzipW.Close()
+	if err != nil {
+		return fmt.Errorf("failed to compress log: %w", err)
+	}
+
+	return nil
+}
+
+// stream performs a single attempt at streaming the given log to the given writer, skipping any data which has
+// already been written by a previous attempt. Returns a boolean indicating whether a failed attempt may be resumed,
+// which is only the case when we failed whilst reading the log; the client will have already retried any failures
+// which occurred whilst dispatching the request.
+func (l *LogCollector) stream(ctx context.Context, task logTask, dst *logWriter) (bool, error) {
+	request := &Request{
+		Host:               task.host,
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           task.endpoint,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Timeout:            -1,
+	}
+
+	resp, err := l.client.Do(ctx, request)
+	if err != nil {
+		return false, err // Purposefully not wrapped
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != request.ExpectedStatusCode {
+		body, _ := io.ReadAll(resp.Body)
+		return false, handleResponseError(request.Method, request.Endpoint, resp, body)
+	}
+
+	// Append only logs may be resumed by skipping the data we've already written, other logs will have been restarted
+	// from the beginning, having truncated the data already written
+	_, err = io.CopyN(io.Discard, resp.Body, dst.written)
+	if err != nil {
+		return true, fmt.Errorf("failed to skip previously collected data: %w", err)
+	}
+
+	_, err = io.Copy(dst, resp.Body)
+	if dst.err != nil {
+		return false, fmt.Errorf("failed to write log: %w", err)
+	}
+
+	if err != nil {
+		return true, fmt.Errorf("failed to read log: %w", err)
+	}
+
+	return false, nil
+}
+
+// restart truncates the given writer, so that collection of a log may be restarted from the beginning; returns a
+// boolean indicating whether the writer could be truncated.
+func restart(writer io.Writer, dst *logWriter, gzipW *gzip.Writer) bool {
+	truncater, ok := writer.(truncater)
+	if !ok {
+		return false
+	}
+
+	if truncater.Truncate(0) != nil {
+		return false
+	}
+
+	if _, err := truncater.Seek(0, io.SeekStart); err != nil {
+		return false
+	}
+
+	if gzipW != nil {
+		gzipW.Reset(writer)
+	}
+
+	dst.written = 0
+
+	return true
+}
+
+// logWriter tracks the number of bytes written, and any error returned by the underlying writer; the number of bytes
+// written is tracked prior to compression, so it's the number of bytes which should be skipped when resuming.
+type logWriter struct {
+	writer  io.Writer
+	written int64
+	err     error
+}
+
+func (l *logWriter) Write(p []byte) (int, error) {
+	n, err := l.writer.Write(p)
+	l.written += int64(n)
+
+	if err != nil && l.err == nil {
+		l.err = err
+	}
+
+	return n, err
+}
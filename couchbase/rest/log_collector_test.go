@@ -0,0 +1,296 @@
+package rest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testLogWriters stores the logs written by a log collector in memory.
+type testLogWriters struct {
+	lock sync.Mutex
+	logs map[string]*bytes.Buffer
+}
+
+func (t *testLogWriters) writer(_, name string) (io.WriteCloser, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.logs == nil {
+		t.logs = make(map[string]*bytes.Buffer)
+	}
+
+	buffer := &bytes.Buffer{}
+	t.logs[name] = buffer
+
+	return nopWriteCloser{Writer: buffer}, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func newTestLogCluster(t *testing.T, nodes TestNodes, handlers TestHandlers) *TestCluster {
+	for _, name := range []string{"debug", "info", "error", "babysitter", "memcached", "couchdb", "indexer", "projector"} {
+		endpoint := string(EndpointSASLLogs.Format(name))
+
+		if _, ok := handlers[http.MethodGet+":"+endpoint]; !ok {
+			handlers.Add(http.MethodGet, endpoint, NewTestHandler(t, http.StatusOK, []byte(name+" contents")))
+		}
+	}
+
+	if _, ok := handlers[http.MethodGet+":"+string(EndpointDiag)]; !ok {
+		handlers.Add(http.MethodGet, string(EndpointDiag), NewTestHandler(t, http.StatusOK, []byte("diag contents")))
+	}
+
+	return NewTestCluster(t, TestClusterOptions{Nodes: nodes, Handlers: handlers})
+}
+
+func TestLogCollectorCollect(t *testing.T) {
+	type test struct {
+		name     string
+		services []Service
+		expected []string
+	}
+
+	tests := []*test{
+		{
+			name:     "AllServices",
+			expected: []string{"diag", "debug", "info", "error", "babysitter", "memcached", "couchdb"},
+		},
+		{
+			name:     "DataOnly",
+			services: []Service{ServiceData},
+			expected: []string{"memcached"},
+		},
+		{
+			name:     "ServiceNotRunning",
+			services: []Service{ServiceGSI},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cluster := newTestLogCluster(t, TestNodes{{Services: []Service{ServiceData}}}, make(TestHandlers))
+			defer cluster.Close()
+
+			client, err := newTestClient(cluster, true)
+			require.NoError(t, err)
+			defer client.Close()
+
+			writers := &testLogWriters{}
+
+			collector := NewLogCollector(LogCollectorOptions{
+				Client:   client,
+				Writer:   writers.writer,
+				Services: test.services,
+			})
+
+			require.NoError(t, collector.Collect(context.Background()))
+			require.Len(t, writers.logs, len(test.expected))
+
+			for _, name := range test.expected {
+				require.Contains(t, writers.logs, name)
+				require.Equal(t, name+" contents", writers.logs[name].String())
+			}
+		})
+	}
+}
+
+func TestLogCollectorCollectCompressed(t *testing.T) {
+	cluster := newTestLogCluster(t, TestNodes{{Services: []Service{ServiceData}}}, make(TestHandlers))
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+	defer client.Close()
+
+	writers := &testLogWriters{}
+
+	collector := NewLogCollector(LogCollectorOptions{
+		Client:   client,
+		Writer:   writers.writer,
+		Services: []Service{ServiceData},
+		Compress: true,
+	})
+
+	require.NoError(t, collector.Collect(context.Background()))
+
+	reader, err := gzip.NewReader(writers.logs["memcached"])
+	require.NoError(t, err)
+
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "memcached contents", string(decompressed))
+}
+
+func TestLogCollectorCollectResume(t *testing.T) {
+	var (
+		contents = bytes.Repeat([]byte("0123456789"), 1024)
+		attempts int
+	)
+
+	// The first attempt fails half way through streaming the log
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		defer func() { attempts++ }()
+
+		body := contents
+		if attempts == 0 {
+			body = contents[:len(contents)/2]
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(contents)))
+		w.WriteHeader(http.StatusOK)
+
+		_, err := w.Write(body)
+		require.NoError(t, err)
+	}
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, string(EndpointSASLLogs.Format("memcached")), handler)
+
+	cluster := newTestLogCluster(t, TestNodes{{Services: []Service{ServiceData}}}, handlers)
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+	defer client.Close()
+
+	writers := &testLogWriters{}
+
+	collector := NewLogCollector(LogCollectorOptions{
+		Client:   client,
+		Writer:   writers.writer,
+		Services: []Service{ServiceData},
+	})
+
+	require.NoError(t, collector.Collect(context.Background()))
+	require.Equal(t, 2, attempts)
+	require.Equal(t, contents, writers.logs["memcached"].Bytes())
+}
+
+// newPartialHandler returns a handler which responds with the given contents, the first response is truncated half way
+// through with the second half being replaced by the given prefix, as if the contents changed between requests.
+func newPartialHandler(t *testing.T, contents []byte, attempts *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		defer func() { *attempts++ }()
+
+		body, length := contents, len(contents)
+		if *attempts == 0 {
+			body, length = append([]byte("stale "), contents[:len(contents)/2]...), len(contents)+len("stale ")
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(length))
+		w.WriteHeader(http.StatusOK)
+
+		_, err := w.Write(body)
+		require.NoError(t, err)
+	}
+}
+
+func TestLogCollectorCollectRestartDiag(t *testing.T) {
+	var (
+		contents = bytes.Repeat([]byte("0123456789"), 1024)
+		attempts int
+	)
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, string(EndpointDiag), newPartialHandler(t, contents, &attempts))
+
+	cluster := newTestLogCluster(t, TestNodes{{Services: []Service{ServiceManagement}}}, handlers)
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+	defer client.Close()
+
+	dir := t.TempDir()
+
+	writer := func(_, name string) (io.WriteCloser, error) {
+		return os.Create(filepath.Join(dir, name))
+	}
+
+	collector := NewLogCollector(LogCollectorOptions{
+		Client:   client,
+		Writer:   writer,
+		Services: []Service{ServiceManagement},
+	})
+
+	// The diag may change between requests, so the data written by the failed attempt should be discarded
+	require.NoError(t, collector.Collect(context.Background()))
+	require.Equal(t, 2, attempts)
+
+	written, err := os.ReadFile(filepath.Join(dir, LogDiag))
+	require.NoError(t, err)
+	require.Equal(t, contents, written)
+}
+
+func TestLogCollectorCollectRestartDiagNotTruncatable(t *testing.T) {
+	var (
+		contents = bytes.Repeat([]byte("0123456789"), 1024)
+		attempts int
+	)
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, string(EndpointDiag), newPartialHandler(t, contents, &attempts))
+
+	cluster := newTestLogCluster(t, TestNodes{{Services: []Service{ServiceManagement}}}, handlers)
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+	defer client.Close()
+
+	writers := &testLogWriters{}
+
+	collector := NewLogCollector(LogCollectorOptions{
+		Client:   client,
+		Writer:   writers.writer,
+		Services: []Service{ServiceManagement},
+	})
+
+	// The data which has already been written can't be discarded, so collection can't be restarted
+	require.Error(t, collector.Collect(context.Background()))
+	require.Equal(t, 1, attempts)
+}
+
+func TestLogCollectorCollectNotFound(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(
+		http.MethodGet,
+		string(EndpointSASLLogs.Format("memcached")),
+		NewTestHandler(t, http.StatusNotFound, make([]byte, 0)),
+	)
+
+	cluster := newTestLogCluster(t, TestNodes{{Services: []Service{ServiceData}}}, handlers)
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+	defer client.Close()
+
+	collector := NewLogCollector(LogCollectorOptions{
+		Client:   client,
+		Writer:   (&testLogWriters{}).writer,
+		Services: []Service{ServiceData},
+	})
+
+	err = collector.Collect(context.Background())
+
+	var notFound *EndpointNotFoundError
+
+	require.ErrorAs(t, err, &notFound)
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"golang.org/x/exp/slices"
+)
+
+// maintenancePeekLimit is the maximum number of bytes read from the body of a '503 Service Unavailable' response when
+// determining whether it was returned because the node is in maintenance.
+const maintenancePeekLimit = 4096
+
+// maintenanceMessages are the messages returned by ns_server (in the global field of the error payload) when a node
+// can't service requests because it's being failed over, or has been put into maintenance.
+var maintenanceMessages = []string{
+	"Node is in maintenance mode",
+	"Node is being failed over",
+	"Node has been failed over",
+}
+
+// maintenanceError is the error payload returned by ns_server, where the global error is reported using the '_' key.
+type maintenanceError struct {
+	Errors struct {
+		Global string `json:"_"`
+	} `json:"errors"`
+}
+
+// isNodeInMaintenance returns a boolean indicating whether the given response was returned because the node is being
+// failed over, or is in maintenance; these requests weren't processed by the node, so may be dispatched to another.
+//
+// NOTE: Only the ns_server error payload is matched, other '503 Service Unavailable' responses which mention
+// maintenance (e.g. from a proxy) aren't considered to be from a node in maintenance.
+//
+// NOTE: The response body is peeked at, and remains readable by the caller.
+func isNodeInMaintenance(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Body == nil {
+		return false
+	}
+
+	peeked, err := io.ReadAll(io.LimitReader(resp.Body, maintenancePeekLimit))
+
+	resp.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(peeked), resp.Body), Closer: resp.Body}
+
+	if err != nil {
+		return false
+	}
+
+	var payload maintenanceError
+	if json.Unmarshal(peeked, &payload) != nil {
+		return false
+	}
+
+	return slices.Contains(maintenanceMessages, payload.Errors.Global)
+}
+
+// peekedBody is a response body whose prefix has been read, and is replayed before the remainder of the body.
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,151 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	testutil "github.com/couchbase/tools-common/testing/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMaintenanceBody = `{"errors":{"_":"Node is in maintenance mode"}}`
+
+func TestIsNodeInMaintenance(t *testing.T) {
+	type test struct {
+		name     string
+		status   int
+		body     string
+		expected bool
+	}
+
+	tests := []*test{
+		{
+			name:     "Maintenance",
+			status:   http.StatusServiceUnavailable,
+			body:     testMaintenanceBody,
+			expected: true,
+		},
+		{
+			name:     "FailingOver",
+			status:   http.StatusServiceUnavailable,
+			body:     `{"errors":{"_":"Node is being failed over"}}`,
+			expected: true,
+		},
+		{
+			name:   "MentionsMaintenance",
+			status: http.StatusServiceUnavailable,
+			body:   "Service unavailable during scheduled maintenance",
+		},
+		{
+			name:   "OtherField",
+			status: http.StatusServiceUnavailable,
+			body:   `{"errors":{"bucket":"Node is in maintenance mode"}}`,
+		},
+		{
+			name:   "OtherGlobalError",
+			status: http.StatusServiceUnavailable,
+			body:   `{"errors":{"_":"Failover is in progress on another node"}}`,
+		},
+		{
+			name:   "OtherServiceUnavailable",
+			status: http.StatusServiceUnavailable,
+			body:   "Service unavailable",
+		},
+		{
+			name:   "OtherStatus",
+			status: http.StatusInternalServerError,
+			body:   testMaintenanceBody,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: test.status, Body: io.NopCloser(strings.NewReader(test.body))}
+			require.Equal(t, test.expected, isNodeInMaintenance(resp))
+
+			// The body must remain readable by the caller
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, test.body, string(body))
+		})
+	}
+}
+
+func TestClientExecuteNodeInMaintenanceRerouted(t *testing.T) {
+	var (
+		primary  *TestCluster
+		attempts = &atomic.Int64{}
+	)
+
+	secondaryHandlers := make(TestHandlers)
+	secondaryHandlers.Add(http.MethodGet, "/test", NewTestHandler(t, http.StatusOK, []byte("body")))
+
+	secondary := NewTestCluster(t, TestClusterOptions{Handlers: secondaryHandlers})
+	defer secondary.Close()
+
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		attempts.Add(1)
+		NewTestHandler(t, http.StatusServiceUnavailable, []byte(testMaintenanceBody))(writer, request)
+	})
+
+	handlers.Add(http.MethodGet, string(EndpointNodesServices), func(writer http.ResponseWriter, _ *http.Request) {
+		testutil.EncodeJSON(t, writer, ClusterConfig{
+			Nodes: Nodes{
+				{Hostname: primary.Address(), Services: &Services{Management: primary.Port()}},
+				{Hostname: secondary.Address(), Services: &Services{Management: secondary.Port()}},
+			},
+		})
+	})
+
+	primary = NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer primary.Close()
+
+	client, err := newTestClient(primary, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	response, err := client.Execute(&Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte("body"), response.Body)
+	require.Equal(t, int64(1), attempts.Load())
+}
+
+func TestClientExecuteNodeInMaintenanceNoneRemaining(t *testing.T) {
+	attempts := &atomic.Int64{}
+
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		attempts.Add(1)
+		NewTestHandler(t, http.StatusServiceUnavailable, []byte(testMaintenanceBody))(writer, request)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.Execute(&Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	})
+	require.True(t, IsNodeInMaintenance(err))
+	require.Equal(t, int64(1), attempts.Load())
+}
@@ -0,0 +1,347 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxMetricsLineSize is the maximum length of a single line in the exposition format, lines which are longer than this
+// cause scraping to fail.
+const maxMetricsLineSize = 1024 * 1024
+
+// ScrapeMetricsOptions encapsulates the options available when scraping metrics using 'ScrapeMetrics'.
+type ScrapeMetricsOptions struct {
+	// Endpoint is the endpoint which is scraped, either 'EndpointMetrics' or 'EndpointMetricsHigh'. Defaults to
+	// 'EndpointMetrics'.
+	Endpoint Endpoint
+
+	// Hostnames limits scraping to the nodes with the given hostnames (as reported in the cluster config), when omitted
+	// every node is scraped.
+	Hostnames []string
+
+	// Names limits the returned samples to the metrics with the given names, when omitted samples for all metrics are
+	// returned.
+	Names []string
+
+	// Labels limits the returned samples to those which have all the given labels, with the given values.
+	Labels map[string]string
+
+	// Concurrency is the number of nodes which will be scraped concurrently, defaults to four.
+	Concurrency int
+}
+
+// defaults fills any missing attributes to a sane default.
+func (s *ScrapeMetricsOptions) defaults() {
+	if s.Endpoint == "" {
+		s.Endpoint = EndpointMetrics
+	}
+
+	if s.Concurrency <= 0 {
+		s.Concurrency = 4
+	}
+}
+
+// matches returns a boolean indicating whether the given sample should be returned.
+func (s *ScrapeMetricsOptions) matches(sample MetricSample) bool {
+	for key, value := range s.Labels {
+		if actual, ok := sample.Labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MetricSample is a single sample parsed from the Prometheus exposition format.
+type MetricSample struct {
+	// Name is the name of the metric.
+	Name string
+
+	// Labels are the labels attached to the sample, this may be <nil> if there are none.
+	Labels map[string]string
+
+	// Value is the value of the sample.
+	Value float64
+
+	// Timestamp is the time at which the sample was taken, this is the zero time where the node didn't report one.
+	Timestamp time.Time
+}
+
+// NodeMetrics are the samples which were scraped from a single node.
+type NodeMetrics struct {
+	// Hostname is the hostname of the node, as reported in the cluster config.
+	Hostname string
+
+	// Samples are the samples which matched the requested names/labels, in the order reported by the node.
+	Samples []MetricSample
+}
+
+// Find returns the samples for the metric with the given name, which have all the given labels.
+func (n NodeMetrics) Find(name string, labels map[string]string) []MetricSample {
+	options := ScrapeMetricsOptions{Labels: labels}
+
+	found := make([]MetricSample, 0)
+
+	for _, sample := range n.Samples {
+		if sample.Name == name && options.matches(sample) {
+			found = append(found, sample)
+		}
+	}
+
+	return found
+}
+
+// ScrapeMetrics scrapes the Prometheus metrics from the selected nodes in the cluster, returning only the samples
+// which match the requested names/labels. The response from each node is filtered as it's parsed, so only the
+// matching samples are held in memory.
+//
+// NOTE: The metrics are returned in the same order as the nodes in the cluster config.
+func (c *Client) ScrapeMetrics(ctx context.Context, options ScrapeMetricsOptions) ([]NodeMetrics, error) {
+	// Fill out any missing fields with the sane defaults
+	options.defaults()
+
+	nodes, err := c.metricsNodes(options.Hostnames)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	var (
+		metrics     = make([]NodeMetrics, len(nodes))
+		group, gctx = errgroup.WithContext(ctx)
+	)
+
+	group.SetLimit(options.Concurrency)
+
+	for index, node := range nodes {
+		group.Go(func() error {
+			samples, err := c.scrapeMetrics(gctx, node, options)
+			if err != nil {
+				return fmt.Errorf("failed to scrape metrics from node '%s': %w", node.Hostname, err)
+			}
+
+			metrics[index] = NodeMetrics{Hostname: node.Hostname, Samples: samples}
+
+			return nil
+		})
+	}
+
+	err = group.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+// metricsNodes returns the nodes with the given hostnames, or all the reachable nodes if none are given.
+func (c *Client) metricsNodes(hostnames []string) (Nodes, error) {
+	var (
+		nodes = make(Nodes, 0)
+		found = make(map[string]struct{})
+	)
+
+	for _, node := range c.Nodes() {
+		// When only communicating with the bootstrap node, the other nodes may not be reachable
+		if len(hostnames) == 0 && c.connectionMode.ThisNodeOnly() && !node.BootstrapNode {
+			continue
+		}
+
+		if len(hostnames) != 0 && !slices.Contains(hostnames, node.Hostname) {
+			continue
+		}
+
+		nodes = append(nodes, node)
+		found[node.Hostname] = struct{}{}
+	}
+
+	for _, hostname := range hostnames {
+		if _, ok := found[hostname]; !ok {
+			return nil, fmt.Errorf("node '%s' is not a member of the cluster", hostname)
+		}
+	}
+
+	return nodes, nil
+}
+
+// scrapeMetrics scrapes the metrics from the given node, returning the matching samples.
+func (c *Client) scrapeMetrics(ctx context.Context, node *Node, options ScrapeMetricsOptions) ([]MetricSample, error) {
+	host, _ := node.GetQualifiedHostname(ServiceManagement, c.TLS(), c.AltAddr())
+	if host == "" {
+		return nil, fmt.Errorf("node '%s' has no reachable management address", node.Hostname)
+	}
+
+	request := &Request{
+		Host:               host,
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           options.Endpoint,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+	}
+
+	resp, err := c.Do(ctx, request)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != request.ExpectedStatusCode {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, handleResponseError(request.Method, request.Endpoint, resp, body)
+	}
+
+	return parseMetrics(resp.Body, options)
+}
+
+// parseMetrics parses the given Prometheus exposition format, returning the samples which match the given options.
+func parseMetrics(reader io.Reader, options ScrapeMetricsOptions) ([]MetricSample, error) {
+	var (
+		scanner = bufio.NewScanner(reader)
+		samples = make([]MetricSample, 0)
+	)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMetricsLineSize)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip blank lines, and the 'HELP'/'TYPE' comments
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name := line[:strings.IndexAny(line+" ", "{ \t")]
+
+		// Avoid parsing the labels/value of metrics which aren't going to be returned
+		if len(options.Names) != 0 && !slices.Contains(options.Names, name) {
+			continue
+		}
+
+		sample, err := parseMetricSample(name, line[len(name):])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sample '%s': %w", line, err)
+		}
+
+		if options.matches(sample) {
+			samples = append(samples, sample)
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics: %w", err)
+	}
+
+	return samples, nil
+}
+
+// parseMetricSample parses the remainder of a sample line (following the metric name) in the exposition format, which
+// is made up of optional labels, a value and an optional timestamp in milliseconds.
+func parseMetricSample(name, remainder string) (MetricSample, error) {
+	var (
+		sample = MetricSample{Name: name}
+		err    error
+	)
+
+	if strings.HasPrefix(remainder, "{") {
+		sample.Labels, remainder, err = parseMetricLabels(remainder[1:])
+		if err != nil {
+			return MetricSample{}, err
+		}
+	}
+
+	fields := strings.Fields(remainder)
+	if len(fields) == 0 || len(fields) > 2 {
+		return MetricSample{}, errors.New("expected a value and optional timestamp")
+	}
+
+	sample.Value, err = strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return MetricSample{}, fmt.Errorf("invalid value: %w", err)
+	}
+
+	if len(fields) == 1 {
+		return sample, nil
+	}
+
+	timestamp, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return MetricSample{}, fmt.Errorf("invalid timestamp: %w", err)
+	}
+
+	sample.Timestamp = time.UnixMilli(timestamp)
+
+	return sample, nil
+}
+
+// parseMetricLabels parses the labels of a sample (following the opening brace), returning them and the remainder of
+// the line following the closing brace.
+func parseMetricLabels(line string) (map[string]string, string, error) {
+	labels := make(map[string]string)
+
+	for {
+		line = strings.TrimLeft(line, " \t,")
+
+		if strings.HasPrefix(line, "}") {
+			break
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 || len(line) <= eq+1 || line[eq+1] != '"' {
+			return nil, "", errors.New("malformed label")
+		}
+
+		key := strings.TrimSpace(line[:eq])
+
+		value, rest, err := parseMetricLabelValue(line[eq+2:])
+		if err != nil {
+			return nil, "", fmt.Errorf("malformed value for label '%s': %w", key, err)
+		}
+
+		labels[key], line = value, rest
+	}
+
+	if len(labels) == 0 {
+		labels = nil
+	}
+
+	return labels, line[1:], nil
+}
+
+// parseMetricLabelValue parses a quoted label value (following the opening quote), unescaping it and returning the
+// remainder of the line following the closing quote.
+func parseMetricLabelValue(line string) (string, string, error) {
+	var builder strings.Builder
+
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			return builder.String(), line[i+1:], nil
+		case '\\':
+			if i+1 == len(line) {
+				return "", "", errors.New("unterminated escape sequence")
+			}
+
+			i++
+
+			if line[i] == 'n' {
+				builder.WriteByte('\n')
+			} else {
+				builder.WriteByte(line[i])
+			}
+		default:
+			builder.WriteByte(line[i])
+		}
+	}
+
+	return "", "", errors.New("unterminated quote")
+}
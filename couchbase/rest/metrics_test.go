@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMetrics = `# HELP kv_ops Number of operations
+# TYPE kv_ops counter
+kv_ops{bucket="travel-sample",op="get"} 42
+kv_ops{bucket="travel-sample",op="set"} 7 1700000000000
+kv_ops{bucket="beer-sample",op="get"} 3
+
+# TYPE sys_cpu_utilization_rate gauge
+sys_cpu_utilization_rate 12.5
+n1ql_requests{path="a \"quoted\", {braced} \\ value\n"} NaN
+`
+
+func TestParseMetrics(t *testing.T) {
+	samples, err := parseMetrics(strings.NewReader(testMetrics), ScrapeMetricsOptions{})
+	require.NoError(t, err)
+	require.Len(t, samples, 5)
+
+	require.Equal(t, MetricSample{
+		Name:   "kv_ops",
+		Labels: map[string]string{"bucket": "travel-sample", "op": "get"},
+		Value:  42,
+	}, samples[0])
+
+	require.Equal(t, time.UnixMilli(1700000000000), samples[1].Timestamp)
+
+	require.Equal(t, MetricSample{Name: "sys_cpu_utilization_rate", Value: 12.5}, samples[3])
+
+	require.Equal(t, map[string]string{"path": "a \"quoted\", {braced} \\ value\n"}, samples[4].Labels)
+	require.True(t, math.IsNaN(samples[4].Value))
+}
+
+func TestParseMetricsFiltered(t *testing.T) {
+	samples, err := parseMetrics(strings.NewReader(testMetrics), ScrapeMetricsOptions{
+		Names:  []string{"kv_ops"},
+		Labels: map[string]string{"bucket": "travel-sample"},
+	})
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	require.Equal(t, "get", samples[0].Labels["op"])
+	require.Equal(t, "set", samples[1].Labels["op"])
+}
+
+func TestParseMetricsMalformed(t *testing.T) {
+	for _, line := range []string{
+		`kv_ops{bucket="travel-sample"`,
+		`kv_ops{bucket=travel-sample} 1`,
+		`kv_ops{bucket="travel-sample} 1`,
+		`kv_ops`,
+		`kv_ops one`,
+		`kv_ops 1 now`,
+		`kv_ops 1 2 3`,
+	} {
+		t.Run(line, func(t *testing.T) {
+			_, err := parseMetrics(strings.NewReader(line), ScrapeMetricsOptions{})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestNodeMetricsFind(t *testing.T) {
+	samples, err := parseMetrics(strings.NewReader(testMetrics), ScrapeMetricsOptions{})
+	require.NoError(t, err)
+
+	metrics := NodeMetrics{Samples: samples}
+
+	require.Len(t, metrics.Find("kv_ops", nil), 3)
+	require.Len(t, metrics.Find("kv_ops", map[string]string{"op": "get"}), 2)
+	require.Empty(t, metrics.Find("kv_ops", map[string]string{"op": "delete"}))
+	require.Empty(t, metrics.Find("missing", nil))
+}
+
+func TestClientScrapeMetrics(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, string(EndpointMetricsHigh), NewTestHandler(t, http.StatusOK, []byte(testMetrics)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	metrics, err := client.ScrapeMetrics(context.Background(), ScrapeMetricsOptions{
+		Endpoint: EndpointMetricsHigh,
+		Names:    []string{"sys_cpu_utilization_rate"},
+	})
+	require.NoError(t, err)
+
+	expected := []NodeMetrics{{
+		Hostname: cluster.Address(),
+		Samples:  []MetricSample{{Name: "sys_cpu_utilization_rate", Value: 12.5}},
+	}}
+
+	require.Equal(t, expected, metrics)
+}
+
+func TestClientScrapeMetricsUnknownNode(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.ScrapeMetrics(context.Background(), ScrapeMetricsOptions{Hostnames: []string{"missing"}})
+	require.ErrorContains(t, err, "not a member of the cluster")
+}
+
+func TestClientScrapeMetricsNotFound(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, string(EndpointMetrics), NewTestHandler(t, http.StatusNotFound, make([]byte, 0)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.ScrapeMetrics(context.Background(), ScrapeMetricsOptions{})
+
+	var notFound *EndpointNotFoundError
+
+	require.ErrorAs(t, err, &notFound)
+}
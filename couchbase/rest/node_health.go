@@ -0,0 +1,124 @@
+package rest
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// healthAlpha is the smoothing factor used when updating the moving averages tracked for each host, higher values
+	// give more weight to recent requests.
+	healthAlpha = 0.2
+
+	// healthMinSamples is the number of requests which must have been dispatched to a host before it may be considered
+	// degraded, this avoids demoting a host because of a single slow request.
+	healthMinSamples = 5
+
+	// healthMaxErrorRate is the average error rate above which a host is considered degraded.
+	healthMaxErrorRate = 0.5
+
+	// healthLatencyFactor is the multiple of the lowest average latency above which a host is considered degraded.
+	healthLatencyFactor = 3
+
+	// healthLatencySlack is the minimum difference from the lowest average latency before a host may be considered
+	// degraded, this stops hosts being demoted due to insignificant differences in latency between fast hosts.
+	healthLatencySlack = 50 * time.Millisecond
+)
+
+// hostHealth is the exponentially weighted moving average of the latency/error rate of requests to a single host.
+type hostHealth struct {
+	latency float64
+	errors  float64
+	samples uint64
+}
+
+// nodeHealth tracks the health of the hosts requests are dispatched to, allowing requests to prefer nodes which are
+// responding quickly and successfully.
+type nodeHealth struct {
+	lock  sync.Mutex
+	hosts map[string]*hostHealth
+}
+
+// newNodeHealth returns a new health tracker, or <nil> if tracking has been disabled.
+func newNodeHealth(disabled bool) *nodeHealth {
+	if disabled {
+		return nil
+	}
+
+	return &nodeHealth{hosts: make(map[string]*hostHealth)}
+}
+
+// observe records the outcome of a request dispatched to the given host.
+func (n *nodeHealth) observe(host string, latency time.Duration, failed bool) {
+	if n == nil || host == "" {
+		return
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	var errored float64
+	if failed {
+		errored = 1
+	}
+
+	health, ok := n.hosts[host]
+	if !ok {
+		n.hosts[host] = &hostHealth{latency: float64(latency), errors: errored, samples: 1}
+		return
+	}
+
+	health.latency += healthAlpha * (float64(latency) - health.latency)
+	health.errors += healthAlpha * (errored - health.errors)
+	health.samples++
+}
+
+// order returns the given hosts with any degraded hosts moved to the end, the relative order of the healthy/degraded
+// hosts is otherwise preserved.
+func (n *nodeHealth) order(hosts []string) []string {
+	if n == nil {
+		return hosts
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	best := -1.0
+
+	for _, host := range hosts {
+		health, ok := n.hosts[host]
+		if ok && health.errors < healthMaxErrorRate && (best < 0 || health.latency < best) {
+			best = health.latency
+		}
+	}
+
+	degraded := func(host string) bool {
+		health, ok := n.hosts[host]
+		if !ok || health.samples < healthMinSamples {
+			return false
+		}
+
+		if health.errors >= healthMaxErrorRate {
+			return true
+		}
+
+		return best >= 0 && health.latency > best*healthLatencyFactor &&
+			health.latency-best > float64(healthLatencySlack)
+	}
+
+	ordered := slices.Clone(hosts)
+
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		switch da, db := degraded(a), degraded(b); {
+		case da == db:
+			return 0
+		case da:
+			return 1
+		default:
+			return -1
+		}
+	})
+
+	return ordered
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/couchbase/v3/connstr"
+)
+
+func TestNewNodeHealthDisabled(t *testing.T) {
+	health := newNodeHealth(true)
+	require.Nil(t, health)
+
+	// Must not panic
+	health.observe("http://node1:8091", time.Second, true)
+
+	hosts := []string{"http://node1:8091", "http://node2:8091"}
+	require.Equal(t, hosts, health.order(hosts))
+}
+
+func TestNodeHealthOrder(t *testing.T) {
+	type test struct {
+		name     string
+		observe  func(health *nodeHealth)
+		expected []string
+	}
+
+	observe := func(health *nodeHealth, host string, n int, latency time.Duration, failed bool) {
+		for i := 0; i < n; i++ {
+			health.observe(host, latency, failed)
+		}
+	}
+
+	tests := []*test{
+		{
+			name:     "NoObservations",
+			observe:  func(_ *nodeHealth) {},
+			expected: []string{"node1", "node2", "node3"},
+		},
+		{
+			name: "AllHealthy",
+			observe: func(health *nodeHealth) {
+				observe(health, "node1", 10, 20*time.Millisecond, false)
+				observe(health, "node2", 10, 10*time.Millisecond, false)
+				observe(health, "node3", 10, 30*time.Millisecond, false)
+			},
+			expected: []string{"node1", "node2", "node3"},
+		},
+		{
+			name: "Slow",
+			observe: func(health *nodeHealth) {
+				observe(health, "node1", 10, time.Second, false)
+				observe(health, "node2", 10, 10*time.Millisecond, false)
+				observe(health, "node3", 10, 10*time.Millisecond, false)
+			},
+			expected: []string{"node2", "node3", "node1"},
+		},
+		{
+			name: "SlowTooFewSamples",
+			observe: func(health *nodeHealth) {
+				observe(health, "node1", healthMinSamples-1, time.Second, false)
+				observe(health, "node2", 10, 10*time.Millisecond, false)
+			},
+			expected: []string{"node1", "node2", "node3"},
+		},
+		{
+			name: "Failing",
+			observe: func(health *nodeHealth) {
+				observe(health, "node1", 10, 10*time.Millisecond, false)
+				observe(health, "node2", 10, time.Millisecond, true)
+			},
+			expected: []string{"node1", "node3", "node2"},
+		},
+		{
+			name: "Recovered",
+			observe: func(health *nodeHealth) {
+				observe(health, "node1", 10, time.Second, false)
+				observe(health, "node1", 50, 10*time.Millisecond, false)
+				observe(health, "node2", 10, 10*time.Millisecond, false)
+			},
+			expected: []string{"node1", "node2", "node3"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			health := newNodeHealth(false)
+
+			test.observe(health)
+
+			require.Equal(t, test.expected, health.order([]string{"node1", "node2", "node3"}))
+		})
+	}
+}
+
+func TestAuthProviderGetServiceHostDegraded(t *testing.T) {
+	provider := &AuthProvider{
+		resolved: &connstr.ResolvedConnectionString{
+			Addresses: []connstr.Address{{Host: "node1", Port: 8091}},
+		},
+		manager: &ClusterConfigManager{
+			config: &ClusterConfig{
+				Nodes: Nodes{
+					{Hostname: "node1", Services: testServices},
+					{Hostname: "node2", Services: testServices},
+				},
+			},
+		},
+		health: newNodeHealth(false),
+	}
+
+	for i := 0; i < healthMinSamples; i++ {
+		provider.health.observe("http://node1:8091", time.Millisecond, true)
+	}
+
+	host, err := provider.GetServiceHost(ServiceManagement, 0)
+	require.NoError(t, err)
+	require.Equal(t, "http://node2:8091", host)
+
+	// Retries should still be able to use the degraded node
+	host, err = provider.GetServiceHost(ServiceManagement, 1)
+	require.NoError(t, err)
+	require.Equal(t, "http://node1:8091", host)
+}
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"errors"
+	"sync"
+)
+
+// errNotModified is returned when performing a conditional request, and the resource hasn't changed.
+var errNotModified = errors.New("not modified")
+
+// nodeServicesEntry is the most recent cluster config fetched from a single host.
+type nodeServicesEntry struct {
+	etag   string
+	digest [sha256.Size]byte
+	config *ClusterConfig
+}
+
+// nodeServicesCache caches the cluster configs fetched from each host when polling, so that unchanged configs don't
+// need to be unmarshalled/processed again.
+//
+// Where the cluster returns an 'ETag', subsequent polls are conditional, so that unchanged configs result in a '304 Not
+// Modified' without a payload. Otherwise, the payload is compared against the previous one, which is much cheaper than
+// unmarshalling the 'nodesExt' payload for large clusters.
+type nodeServicesCache struct {
+	lock    sync.Mutex
+	entries map[string]nodeServicesEntry
+}
+
+// newNodeServicesCache returns a new empty cache.
+func newNodeServicesCache() *nodeServicesCache {
+	return &nodeServicesCache{entries: make(map[string]nodeServicesEntry)}
+}
+
+// etag returns the 'ETag' of the last config fetched from the given host, or an empty string if the cluster didn't
+// provide one.
+func (n *nodeServicesCache) etag(host string) string {
+	if n == nil {
+		return ""
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.entries[host].etag
+}
+
+// lookup returns a copy of the config last fetched from the given host, the body is optional, and where provided must
+// match the body of the previous response.
+func (n *nodeServicesCache) lookup(host string, body []byte) (*ClusterConfig, bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	entry, ok := n.entries[host]
+	if !ok || (body != nil && entry.digest != sha256.Sum256(body)) {
+		return nil, false
+	}
+
+	return entry.config.Copy(), true
+}
+
+// store caches a copy of the given config, which was fetched from the given host.
+func (n *nodeServicesCache) store(host, etag string, body []byte, config *ClusterConfig) {
+	if n == nil {
+		return
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.entries[host] = nodeServicesEntry{
+		etag:   etag,
+		digest: sha256.Sum256(body),
+		config: config.Copy(),
+	}
+}
@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeServicesCacheLookup(t *testing.T) {
+	cache := newNodeServicesCache()
+
+	_, ok := cache.lookup("host", nil)
+	require.False(t, ok)
+
+	config := &ClusterConfig{Revision: 1, Nodes: Nodes{{Hostname: "hostname"}}}
+
+	cache.store("host", "etag", []byte("body"), config)
+	require.Equal(t, "etag", cache.etag("host"))
+
+	cached, ok := cache.lookup("host", []byte("body"))
+	require.True(t, ok)
+	require.Equal(t, int64(1), cached.Revision)
+	require.Equal(t, "hostname", cached.Nodes[0].Hostname)
+
+	// Modifying the returned config must not modify the cached config
+	cached.Nodes[0].Hostname = "modified"
+
+	cached, ok = cache.lookup("host", nil)
+	require.True(t, ok)
+	require.Equal(t, "hostname", cached.Nodes[0].Hostname)
+
+	_, ok = cache.lookup("host", []byte("changed"))
+	require.False(t, ok)
+}
+
+func TestNodeServicesCacheNil(t *testing.T) {
+	var cache *nodeServicesCache
+
+	cache.store("host", "etag", []byte("body"), &ClusterConfig{})
+	require.Empty(t, cache.etag("host"))
+
+	_, ok := cache.lookup("host", nil)
+	require.False(t, ok)
+}
+
+func TestClientUpdateCCNotModified(t *testing.T) {
+	var (
+		cluster     *TestCluster
+		notModified = &atomic.Int64{}
+		handlers    = make(TestHandlers)
+	)
+
+	handlers.Add(http.MethodGet, string(EndpointNodesServices), func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("If-None-Match") == `"etag"` {
+			notModified.Add(1)
+			writer.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		writer.Header().Set("ETag", `"etag"`)
+		cluster.NodeServices(writer, request)
+	})
+
+	cluster = NewTestCluster(t, TestClusterOptions{
+		Nodes:    TestNodes{{}, {}},
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	expected := client.authProvider.manager.GetClusterConfig()
+
+	require.NoError(t, client.updateCC())
+	require.Equal(t, int64(1), notModified.Load())
+	require.Equal(t, expected, client.authProvider.manager.GetClusterConfig())
+}
+
+func TestClientFetchCCUnchangedBody(t *testing.T) {
+	var (
+		cluster  *TestCluster
+		handlers = make(TestHandlers)
+	)
+
+	// Always respond with the same revision, so that the response body doesn't change between polls
+	handlers.Add(http.MethodGet, string(EndpointNodesServices), func(writer http.ResponseWriter, request *http.Request) {
+		cluster.revision = 1
+		cluster.NodeServices(writer, request)
+	})
+
+	cluster = NewTestCluster(t, TestClusterOptions{
+		Nodes:    TestNodes{{}, {}},
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	first, err := client.fetchCC(cluster.URL())
+	require.NoError(t, err)
+
+	// Modifying the returned config must not affect subsequent polls
+	first.FilterOtherNodes()
+
+	second, err := client.fetchCC(cluster.URL())
+	require.NoError(t, err)
+	require.Len(t, second.Nodes, 2)
+	require.Equal(t, client.authProvider.manager.GetClusterConfig(), second)
+}
@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// NodeStatus represents the health of a node, as reported by the cluster manager.
+type NodeStatus string
+
+const (
+	// NodeStatusHealthy indicates that the node is running, and responding to the cluster manager.
+	NodeStatusHealthy NodeStatus = "healthy"
+
+	// NodeStatusUnhealthy indicates that the node isn't responding to the cluster manager e.g. it's down.
+	NodeStatusUnhealthy NodeStatus = "unhealthy"
+
+	// NodeStatusWarmup indicates that the node is running, however, the Data Service is still warming up.
+	NodeStatusWarmup NodeStatus = "warmup"
+)
+
+// NodeMembership represents the membership of a node in the cluster.
+type NodeMembership string
+
+const (
+	// NodeMembershipActive indicates that the node is an active member of the cluster.
+	NodeMembershipActive NodeMembership = "active"
+
+	// NodeMembershipInactiveAdded indicates that the node has been added to the cluster, but is yet to be rebalanced in.
+	NodeMembershipInactiveAdded NodeMembership = "inactiveAdded"
+
+	// NodeMembershipInactiveFailed indicates that the node has been failed over, but is yet to be rebalanced out.
+	NodeMembershipInactiveFailed NodeMembership = "inactiveFailed"
+)
+
+// NodeState is the status/membership of a single node in the cluster.
+type NodeState struct {
+	UUID       string         `json:"nodeUUID"`
+	OTPNode    string         `json:"otpNode"`
+	Hostname   string         `json:"hostname"`
+	Status     NodeStatus     `json:"status"`
+	Membership NodeMembership `json:"clusterMembership"`
+}
+
+// HostFilter returns a boolean indicating whether requests should be dispatched to the node with the given state.
+type HostFilter func(state NodeState) bool
+
+// HealthyActiveNodes is a 'HostFilter' which only accepts nodes which are healthy, and are active members of the
+// cluster; failed over nodes are still returned by the cluster (until they're rebalanced out) and are rejected.
+func HealthyActiveNodes(state NodeState) bool {
+	return state.Status == NodeStatusHealthy && state.Membership == NodeMembershipActive
+}
+
+// GetNodeStates returns the status/membership of each of the nodes in the cluster.
+func (c *Client) GetNodeStates(ctx context.Context) ([]NodeState, error) {
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointPoolsDefault,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	response, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var decoded struct {
+		Nodes []NodeState `json:"nodes"`
+	}
+
+	err = json.Unmarshal(response.Body, &decoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return decoded.Nodes, nil
+}
+
+// GetFilteredServiceHosts is similar to 'GetAllServiceHosts', however, only the nodes accepted by the given filter are
+// returned; for example, 'HealthyActiveNodes' may be used to avoid dispatching requests to failed over nodes.
+//
+// A 'ServiceNotAvailableError' is returned if none of the accepted nodes are running the given service.
+//
+// NOTE: Nodes for which a state couldn't be found (e.g. they've just been added to the cluster) are not returned.
+func (c *Client) GetFilteredServiceHosts(ctx context.Context, service Service, filter HostFilter) ([]string, error) {
+	if c.connectionMode.ThisNodeOnly() || filter == nil {
+		return c.GetAllServiceHosts(service)
+	}
+
+	states, err := c.GetNodeStates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node states: %w", err)
+	}
+
+	include := func(node *Node) bool {
+		state, ok := findNodeState(states, node)
+		return ok && filter(state)
+	}
+
+	hosts, err := c.authProvider.getAllServiceHosts(service, include)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	for i := range hosts {
+		hosts[i] += c.pathPrefix
+	}
+
+	return hosts, nil
+}
+
+// findNodeState returns the state for the given node, matching on the node uuid where possible, and otherwise on its
+// management address (the 'hostname' returned by '/pools/default').
+func findNodeState(states []NodeState, node *Node) (NodeState, bool) {
+	for _, state := range states {
+		if node.UUID != "" && state.UUID == node.UUID {
+			return state, true
+		}
+	}
+
+	if node.Services == nil || node.Services.Management == 0 {
+		return NodeState{}, false
+	}
+
+	address := net.JoinHostPort(node.Hostname, strconv.Itoa(int(node.Services.Management)))
+
+	for _, state := range states {
+		if state.Hostname == address {
+			return state, true
+		}
+	}
+
+	return NodeState{}, false
+}
@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthyActiveNodes(t *testing.T) {
+	type test struct {
+		name     string
+		state    NodeState
+		expected bool
+	}
+
+	tests := []*test{
+		{
+			name:     "HealthyActive",
+			state:    NodeState{Status: NodeStatusHealthy, Membership: NodeMembershipActive},
+			expected: true,
+		},
+		{
+			name:  "Warmup",
+			state: NodeState{Status: NodeStatusWarmup, Membership: NodeMembershipActive},
+		},
+		{
+			name:  "FailedOver",
+			state: NodeState{Status: NodeStatusHealthy, Membership: NodeMembershipInactiveFailed},
+		},
+		{
+			name:  "Unhealthy",
+			state: NodeState{Status: NodeStatusUnhealthy, Membership: NodeMembershipActive},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, HealthyActiveNodes(test.state))
+		})
+	}
+}
+
+func TestClientGetNodeStates(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{Nodes: TestNodes{
+		{UUID: "a", Status: "healthy", Membership: "active"},
+		{UUID: "b", Status: "warmup", Membership: "inactiveFailed"},
+	}})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	states, err := client.GetNodeStates(context.Background())
+	require.NoError(t, err)
+
+	expected := []NodeState{
+		{UUID: "a", Status: NodeStatusHealthy, Membership: NodeMembershipActive},
+		{UUID: "b", Status: NodeStatusWarmup, Membership: NodeMembershipInactiveFailed},
+	}
+
+	require.Equal(t, expected, states)
+}
+
+func TestClientGetFilteredServiceHosts(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{Nodes: TestNodes{
+		{UUID: "a", Status: "healthy", Membership: "active", Services: []Service{ServiceData}},
+		{UUID: "b", Status: "healthy", Membership: "inactiveFailed", Services: []Service{ServiceData}},
+		{UUID: "c", Status: "healthy", Membership: "active"},
+	}})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	all, err := client.GetAllServiceHosts(ServiceData)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	hosts, err := client.GetFilteredServiceHosts(context.Background(), ServiceData, HealthyActiveNodes)
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+
+	_, err = client.GetFilteredServiceHosts(
+		context.Background(),
+		ServiceData,
+		func(state NodeState) bool { return state.UUID == "c" },
+	)
+	require.True(t, IsServiceNotAvailable(err))
+}
+
+func TestFindNodeState(t *testing.T) {
+	states := []NodeState{
+		{UUID: "a", Hostname: "10.0.0.1:8091", Status: NodeStatusHealthy},
+		{Hostname: "10.0.0.2:8091", Status: NodeStatusWarmup},
+	}
+
+	state, ok := findNodeState(states, &Node{UUID: "a", Hostname: "10.0.0.3"})
+	require.True(t, ok)
+	require.Equal(t, NodeStatusHealthy, state.Status)
+
+	state, ok = findNodeState(states, &Node{Hostname: "10.0.0.2", Services: &Services{Management: 8091}})
+	require.True(t, ok)
+	require.Equal(t, NodeStatusWarmup, state.Status)
+
+	_, ok = findNodeState(states, &Node{Hostname: "10.0.0.4", Services: &Services{Management: 8091}})
+	require.False(t, ok)
+}
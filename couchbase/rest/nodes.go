@@ -19,6 +19,8 @@ func (n Nodes) Copy() Nodes {
 
 // Node encapsulates the addressing information for a single node in a Couchbase Cluster.
 type Node struct {
+	UUID               string             `json:"nodeUUID"`
+	OTPNode            string             `json:"otpNode"`
 	Hostname           string             `json:"hostname"`
 	Services           *Services          `json:"services"`
 	AlternateAddresses AlternateAddresses `json:"alternateAddresses"`
@@ -38,6 +40,8 @@ func (n *Node) Copy() *Node {
 	}
 
 	return &Node{
+		UUID:               n.UUID,
+		OTPNode:            n.OTPNode,
 		Hostname:           n.Hostname,
 		Services:           &services,
 		AlternateAddresses: AlternateAddresses{External: &external},
@@ -110,7 +114,8 @@ type External struct {
 	Services *Services `json:"ports"`
 }
 
-// Services encapsulates the ports that are active on this cluster node.
+// Services encapsulates the ports that are active on this cluster node, a zero port indicates that the node isn't
+// running the service (or doesn't support TLS for the service).
 type Services struct {
 	CAPI              uint16 `json:"capi"`
 	CAPISSL           uint16 `json:"capiSSL"`
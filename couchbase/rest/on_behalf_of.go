@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sync/atomic"
+
+	cbvalue "github.com/couchbase/tools-common/couchbase/v3/value"
+)
+
+// DefaultOnBehalfOfDomain is the domain used for the user a request is performed on behalf of, when one isn't provided.
+const DefaultOnBehalfOfDomain = "local"
+
+// headerOnBehalfOf is the header used to indicate the user a request is being performed on behalf of.
+const headerOnBehalfOf = "cb-on-behalf-of"
+
+// FeatureOnBehalfOf is performing requests on behalf of another user, using the 'cb-on-behalf-of' header.
+var FeatureOnBehalfOf = Feature{Name: "Performing requests on behalf of another user", MinVersion: cbvalue.Version7_0_0}
+
+// ErrOnBehalfOfIncompatibleAuth is returned when attempting to perform a request on behalf of another user, using a
+// client which authenticates requests using a 'Signer'; only requests authenticated by the cluster may be attributed to
+// another user.
+var ErrOnBehalfOfIncompatibleAuth = errors.New("requests may only be performed on behalf of another user when " +
+	"authenticating using HTTP basic auth or a session")
+
+// OnBehalfOf identifies the user a request is being performed on behalf of, the request is authorized/audited as if it
+// were sent by this user.
+//
+// NOTE: The authenticated user must have the permission to impersonate other users.
+type OnBehalfOf struct {
+	// User is the name of the user.
+	User string
+
+	// Domain is the domain of the user e.g. 'local' or 'external', defaults to 'DefaultOnBehalfOfDomain'.
+	Domain string
+}
+
+// header returns the value of the 'cb-on-behalf-of' header, which is the base64 encoded 'user:domain'.
+func (o *OnBehalfOf) header() string {
+	domain := o.Domain
+	if domain == "" {
+		domain = DefaultOnBehalfOfDomain
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(o.User + ":" + domain))
+}
+
+// onBehalfOfSupport caches whether the cluster supports performing requests on behalf of another user.
+//
+// NOTE: Only support is cached, the cluster may be upgraded to a supported version.
+type onBehalfOfSupport struct {
+	supported atomic.Bool
+}
+
+// checkOnBehalfOf returns an 'UnsupportedServerVersionError' if the given request is to be performed on behalf of
+// another user, and the cluster is running a version prior to 7.0.0.
+func (c *Client) checkOnBehalfOf(ctx context.Context, request *Request) error {
+	if request.OnBehalfOf == nil || c.onBehalfOf.supported.Load() {
+		return nil
+	}
+
+	err := c.checkCompatibility(ctx, FeatureOnBehalfOf)
+	if err != nil {
+		return err
+	}
+
+	c.onBehalfOf.supported.Store(true)
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package rest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cbvalue "github.com/couchbase/tools-common/couchbase/v3/value"
+)
+
+func TestOnBehalfOfHeader(t *testing.T) {
+	type test struct {
+		name       string
+		onBehalfOf OnBehalfOf
+		expected   string
+	}
+
+	tests := []*test{
+		{
+			name:       "DefaultDomain",
+			onBehalfOf: OnBehalfOf{User: "user"},
+			expected:   "user:local",
+		},
+		{
+			name:       "ExternalDomain",
+			onBehalfOf: OnBehalfOf{User: "user", Domain: "external"},
+			expected:   "user:external",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decoded, err := base64.StdEncoding.DecodeString(test.onBehalfOf.header())
+			require.NoError(t, err)
+			require.Equal(t, test.expected, string(decoded))
+		})
+	}
+}
+
+func TestClientExecuteOnBehalfOf(t *testing.T) {
+	var header string
+
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		header = request.Header.Get(headerOnBehalfOf)
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Nodes:    TestNodes{{Version: cbvalue.Version7_0_0}},
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+		OnBehalfOf:         &OnBehalfOf{User: "user"},
+	}
+
+	_, err = client.Execute(request)
+	require.NoError(t, err)
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte("user:local")), header)
+	require.True(t, client.onBehalfOf.supported.Load())
+}
+
+func TestClientExecuteOnBehalfOfUnsupported(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, _ *http.Request) {
+		t.Fatal("Expected request to not be dispatched")
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Nodes:    TestNodes{{Version: cbvalue.Version6_6_0}},
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+		OnBehalfOf:         &OnBehalfOf{User: "user"},
+	}
+
+	_, err = client.Do(context.Background(), request)
+
+	var unsupported *UnsupportedServerVersionError
+
+	require.ErrorAs(t, err, &unsupported)
+	require.Equal(t, cbvalue.Version7_0_0, unsupported.Required)
+	require.False(t, client.onBehalfOf.supported.Load())
+}
+
+func TestClientExecuteOnBehalfOfWithSigner(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, _ *http.Request) {
+		t.Fatal("Expected request to not be dispatched")
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Nodes:    TestNodes{{Version: cbvalue.Version7_0_0}},
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	pool := x509.NewCertPool()
+
+	if cluster.Certificate() != nil {
+		pool.AddCert(cluster.Certificate())
+	}
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		TLSConfig:        &tls.Config{RootCAs: pool},
+		SignerForHost:    func(_ string) Signer { return &CapellaHMACSigner{} },
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+		OnBehalfOf:         &OnBehalfOf{User: "user"},
+	}
+
+	_, err = client.Do(context.Background(), request)
+	require.ErrorIs(t, err, ErrOnBehalfOfIncompatibleAuth)
+}
@@ -28,9 +28,6 @@ func (c *Client) Poll(poll func(attempt int) (bool, error)) (bool, error) {
 //
 // NOTE: Returns true in the event that the provided context is cancelled.
 func (c *Client) PollWithContext(ctx context.Context, poll func(attempt int) (bool, error)) (bool, error) {
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
-
 	for attempt := 0; ; attempt++ {
 		done, err := poll(attempt)
 		if err != nil {
@@ -41,6 +38,6 @@ func (c *Client) PollWithContext(ctx context.Context, poll func(attempt int) (bo
 			return errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(ctx.Err(), context.Canceled), nil
 		}
 
-		<-ticker.C
+		<-c.clock.After(time.Second)
 	}
 }
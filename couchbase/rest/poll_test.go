@@ -11,6 +11,7 @@ import (
 func TestPoll(t *testing.T) {
 	client := &Client{
 		pollTimeout: time.Minute,
+		clock:       systemClock{},
 	}
 
 	timeout, err := client.Poll(func(_ int) (bool, error) { return true, nil })
@@ -19,7 +20,7 @@ func TestPoll(t *testing.T) {
 }
 
 func TestPollWithContext(t *testing.T) {
-	client := &Client{}
+	client := &Client{clock: systemClock{}}
 
 	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Minute)
 	defer cancelFunc()
@@ -31,7 +32,7 @@ func TestPollWithContext(t *testing.T) {
 
 func TestPollWithContextCancel(t *testing.T) {
 	var (
-		client          = &Client{}
+		client          = &Client{clock: systemClock{}}
 		ctx, cancelFunc = context.WithTimeout(context.Background(), time.Minute)
 	)
 
@@ -44,3 +45,17 @@ func TestPollWithContextCancel(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, timeout)
 }
+
+func TestPollWithContextWaitsBetweenAttempts(t *testing.T) {
+	var (
+		clock  = &testClock{}
+		client = &Client{clock: clock}
+	)
+
+	timeout, err := client.PollWithContext(context.Background(), func(attempt int) (bool, error) {
+		return attempt == 2, nil
+	})
+	require.NoError(t, err)
+	require.False(t, timeout)
+	require.Equal(t, []time.Duration{time.Second, time.Second}, clock.Waits())
+}
@@ -0,0 +1,243 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	cbvalue "github.com/couchbase/tools-common/couchbase/v3/value"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// StorageBackend is the storage engine used by a bucket.
+type StorageBackend string
+
+const (
+	// StorageBackendCouchstore is the default storage engine, used by buckets created prior to 7.1.0.
+	StorageBackendCouchstore StorageBackend = "couchstore"
+
+	// StorageBackendMagma is the storage engine designed for buckets which have a low memory to data ratio.
+	StorageBackendMagma StorageBackend = "magma"
+)
+
+// MemoryQuotas are the memory quotas (in MiB) for each of the services which have one, a quota is <nil> when it's
+// unset e.g. because the cluster is running a version which doesn't support the service.
+type MemoryQuotas struct {
+	Data      *uint64 `json:"memoryQuota"`
+	Index     *uint64 `json:"indexMemoryQuota"`
+	Search    *uint64 `json:"ftsMemoryQuota"`
+	Analytics *uint64 `json:"cbasMemoryQuota"`
+	Eventing  *uint64 `json:"eventingMemoryQuota"`
+	Query     *uint64 `json:"queryMemoryQuota"`
+}
+
+// Quota returns the memory quota (in MiB) for the given service, and a boolean indicating whether the service has a
+// memory quota which is set.
+func (m MemoryQuotas) Quota(service Service) (uint64, bool) {
+	switch service {
+	case ServiceData:
+		return ptr.From(m.Data), m.Data != nil
+	case ServiceGSI:
+		return ptr.From(m.Index), m.Index != nil
+	case ServiceSearch:
+		return ptr.From(m.Search), m.Search != nil
+	case ServiceAnalytics:
+		return ptr.From(m.Analytics), m.Analytics != nil
+	case ServiceEventing:
+		return ptr.From(m.Eventing), m.Eventing != nil
+	case ServiceQuery:
+		return ptr.From(m.Query), m.Query != nil
+	}
+
+	return 0, false
+}
+
+// RAMStorageTotals is the memory usage of the cluster, in bytes.
+type RAMStorageTotals struct {
+	Total             int64 `json:"total"`
+	QuotaTotal        int64 `json:"quotaTotal"`
+	QuotaUsed         int64 `json:"quotaUsed"`
+	Used              int64 `json:"used"`
+	UsedByData        int64 `json:"usedByData"`
+	QuotaTotalPerNode int64 `json:"quotaTotalPerNode"`
+	QuotaUsedPerNode  int64 `json:"quotaUsedPerNode"`
+}
+
+// HDDStorageTotals is the disk usage of the cluster, in bytes.
+type HDDStorageTotals struct {
+	Total      int64 `json:"total"`
+	QuotaTotal int64 `json:"quotaTotal"`
+	Used       int64 `json:"used"`
+	UsedByData int64 `json:"usedByData"`
+	Free       int64 `json:"free"`
+}
+
+// StorageTotals is the memory/disk usage of the cluster.
+type StorageTotals struct {
+	RAM RAMStorageTotals `json:"ram"`
+	HDD HDDStorageTotals `json:"hdd"`
+}
+
+// PoolsDefaultNode is a single node, as returned by '/pools/default'.
+type PoolsDefaultNode struct {
+	NodeState
+
+	Version     cbvalue.Version `json:"version"`
+	Services    []string        `json:"services"`
+	MemoryTotal uint64          `json:"memoryTotal"`
+	MemoryFree  uint64          `json:"memoryFree"`
+	CPUCount    int             `json:"cpuCount"`
+	OS          string          `json:"os"`
+}
+
+// UnmarshalJSON implements the 'json.Unmarshaler' interface, the version returned by the cluster is parsed e.g.
+// '7.6.0-2176-enterprise' becomes '7.6.0'.
+func (p *PoolsDefaultNode) UnmarshalJSON(data []byte) error {
+	type alias PoolsDefaultNode
+
+	var decoded struct {
+		alias
+		Version string `json:"version"`
+	}
+
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return err
+	}
+
+	*p = PoolsDefaultNode(decoded.alias)
+	p.Version = cbvalue.ParseVersion(decoded.Version)
+
+	return nil
+}
+
+// BucketAutoCompaction are the auto-compaction settings overridden by a bucket.
+type BucketAutoCompaction struct {
+	// MagmaFragmentationPercentage is the fragmentation at which compaction is triggered for a Magma bucket, <nil> if
+	// it's not overridden by the bucket.
+	MagmaFragmentationPercentage *int `json:"magmaFragmentationPercentage"`
+}
+
+// PoolsDefaultBucket is a summary of a single bucket, including the storage backend it uses.
+type PoolsDefaultBucket struct {
+	Name           string         `json:"name"`
+	UUID           string         `json:"uuid"`
+	BucketType     string         `json:"bucketType"`
+	StorageBackend StorageBackend `json:"storageBackend"`
+	EvictionPolicy string         `json:"evictionPolicy"`
+	NumReplicas    int            `json:"replicaNumber"`
+
+	Quota struct {
+		RAM    uint64 `json:"ram"`
+		RawRAM uint64 `json:"rawRAM"`
+	} `json:"quota"`
+
+	// HistoryRetentionSeconds/HistoryRetentionBytes are the change history retention settings, which are only
+	// supported by (and therefore <nil> for buckets other than) Magma buckets.
+	HistoryRetentionSeconds *uint64 `json:"historyRetentionSeconds"`
+	HistoryRetentionBytes   *uint64 `json:"historyRetentionBytes"`
+
+	// AutoCompaction is <nil> unless the bucket overrides the cluster wide auto-compaction settings.
+	AutoCompaction *BucketAutoCompaction `json:"-"`
+}
+
+// UnmarshalJSON implements the 'json.Unmarshaler' interface, the auto-compaction settings are 'false' unless they're
+// overridden by the bucket.
+func (p *PoolsDefaultBucket) UnmarshalJSON(data []byte) error {
+	type alias PoolsDefaultBucket
+
+	var decoded struct {
+		alias
+		AutoCompactionSettings json.RawMessage `json:"autoCompactionSettings"`
+	}
+
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return err
+	}
+
+	*p = PoolsDefaultBucket(decoded.alias)
+
+	settings := decoded.AutoCompactionSettings
+	if len(settings) == 0 || string(settings) == "false" || string(settings) == "null" {
+		return nil
+	}
+
+	p.AutoCompaction = &BucketAutoCompaction{}
+
+	return json.Unmarshal(settings, p.AutoCompaction)
+}
+
+// PoolsDefault is a typed representation of the cluster wide information returned by '/pools/default', along with a
+// summary of each bucket.
+type PoolsDefault struct {
+	MemoryQuotas
+
+	ClusterName     string             `json:"clusterName"`
+	Balanced        bool               `json:"balanced"`
+	RebalanceStatus string             `json:"rebalanceStatus"`
+	StorageTotals   StorageTotals      `json:"storageTotals"`
+	Nodes           []PoolsDefaultNode `json:"nodes"`
+
+	// Buckets is populated using '/pools/default/buckets', since '/pools/default' only returns their location.
+	Buckets []PoolsDefaultBucket `json:"-"`
+}
+
+// GetPoolsDefault returns the memory quotas, storage totals, nodes and buckets of the cluster.
+func (c *Client) GetPoolsDefault(ctx context.Context) (*PoolsDefault, error) {
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointPoolsDefault,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	response, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var decoded PoolsDefault
+
+	err = json.Unmarshal(response.Body, &decoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	decoded.Buckets, err = c.getPoolsDefaultBuckets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get buckets: %w", err)
+	}
+
+	return &decoded, nil
+}
+
+// getPoolsDefaultBuckets returns a summary of each of the buckets in the cluster.
+func (c *Client) getPoolsDefaultBuckets(ctx context.Context) ([]PoolsDefaultBucket, error) {
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointBuckets,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		// The vBucket map is large, and isn't required
+		QueryParameters: url.Values{"skipMap": {"true"}},
+		Service:         ServiceManagement,
+	}
+
+	response, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var decoded []PoolsDefaultBucket
+
+	err = json.Unmarshal(response.Body, &decoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return decoded, nil
+}
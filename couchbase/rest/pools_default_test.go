@@ -0,0 +1,201 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cbvalue "github.com/couchbase/tools-common/couchbase/v3/value"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+const testPoolsDefault = `{
+  "clusterName": "cluster",
+  "balanced": true,
+  "rebalanceStatus": "none",
+  "memoryQuota": 2048,
+  "indexMemoryQuota": 512,
+  "ftsMemoryQuota": 256,
+  "cbasMemoryQuota": 1024,
+  "eventingMemoryQuota": 256,
+  "queryMemoryQuota": 0,
+  "storageTotals": {
+    "ram": {
+      "total": 8589934592,
+      "quotaTotal": 2147483648,
+      "quotaUsed": 1073741824,
+      "used": 4294967296,
+      "usedByData": 104857600,
+      "quotaUsedPerNode": 1073741824,
+      "quotaTotalPerNode": 2147483648
+    },
+    "hdd": {
+      "total": 107374182400,
+      "quotaTotal": 107374182400,
+      "used": 10737418240,
+      "usedByData": 52428800,
+      "free": 96636764160
+    }
+  },
+  "nodes": [
+    {
+      "nodeUUID": "uuid",
+      "otpNode": "ns_1@127.0.0.1",
+      "hostname": "127.0.0.1:8091",
+      "status": "healthy",
+      "clusterMembership": "active",
+      "version": "7.6.0-2176-enterprise",
+      "services": ["kv", "n1ql"],
+      "memoryTotal": 8589934592,
+      "memoryFree": 4294967296,
+      "cpuCount": 4,
+      "os": "x86_64-pc-linux-gnu"
+    }
+  ]
+}`
+
+const testPoolsDefaultBuckets = `[
+  {
+    "name": "magma",
+    "uuid": "uuid1",
+    "bucketType": "membase",
+    "storageBackend": "magma",
+    "evictionPolicy": "fullEviction",
+    "replicaNumber": 1,
+    "quota": {"ram": 1073741824, "rawRAM": 1073741824},
+    "historyRetentionSeconds": 86400,
+    "historyRetentionBytes": 2147483648,
+    "autoCompactionSettings": {"magmaFragmentationPercentage": 60}
+  },
+  {
+    "name": "couchstore",
+    "uuid": "uuid2",
+    "bucketType": "membase",
+    "storageBackend": "couchstore",
+    "evictionPolicy": "valueOnly",
+    "replicaNumber": 0,
+    "quota": {"ram": 268435456, "rawRAM": 268435456},
+    "autoCompactionSettings": false
+  }
+]`
+
+func TestGetPoolsDefault(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, string(EndpointPoolsDefault), NewTestHandler(t, http.StatusOK, []byte(testPoolsDefault)))
+
+	handlers.Add(http.MethodGet, string(EndpointBuckets), func(writer http.ResponseWriter, request *http.Request) {
+		require.Equal(t, "true", request.URL.Query().Get("skipMap"))
+		NewTestHandler(t, http.StatusOK, []byte(testPoolsDefaultBuckets))(writer, request)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	pools, err := client.GetPoolsDefault(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, "cluster", pools.ClusterName)
+	require.True(t, pools.Balanced)
+	require.Equal(t, "none", pools.RebalanceStatus)
+
+	require.Equal(t, MemoryQuotas{
+		Data:      ptr.To[uint64](2048),
+		Index:     ptr.To[uint64](512),
+		Search:    ptr.To[uint64](256),
+		Analytics: ptr.To[uint64](1024),
+		Eventing:  ptr.To[uint64](256),
+		Query:     ptr.To[uint64](0),
+	}, pools.MemoryQuotas)
+
+	// A quota of zero is returned by the cluster, so should be distinguishable from an unset quota
+	quota, ok := pools.Quota(ServiceQuery)
+	require.True(t, ok)
+	require.Zero(t, quota)
+
+	require.Equal(t, int64(2147483648), pools.StorageTotals.RAM.QuotaTotal)
+	require.Equal(t, int64(96636764160), pools.StorageTotals.HDD.Free)
+
+	require.Len(t, pools.Nodes, 1)
+	require.Equal(t, "uuid", pools.Nodes[0].UUID)
+	require.Equal(t, NodeStatusHealthy, pools.Nodes[0].Status)
+	require.Equal(t, NodeMembershipActive, pools.Nodes[0].Membership)
+	require.Equal(t, []string{"kv", "n1ql"}, pools.Nodes[0].Services)
+	require.Equal(t, 4, pools.Nodes[0].CPUCount)
+	require.True(t, pools.Nodes[0].Version.AtLeast("7.6.0"))
+
+	require.Len(t, pools.Buckets, 2)
+
+	magma := pools.Buckets[0]
+	require.Equal(t, "magma", magma.Name)
+	require.Equal(t, StorageBackendMagma, magma.StorageBackend)
+	require.Equal(t, uint64(1073741824), magma.Quota.RAM)
+	require.Equal(t, ptr.To[uint64](86400), magma.HistoryRetentionSeconds)
+	require.Equal(t, &BucketAutoCompaction{MagmaFragmentationPercentage: ptr.To(60)}, magma.AutoCompaction)
+
+	couchstore := pools.Buckets[1]
+	require.Equal(t, StorageBackendCouchstore, couchstore.StorageBackend)
+	require.Equal(t, "valueOnly", couchstore.EvictionPolicy)
+	require.Nil(t, couchstore.HistoryRetentionSeconds)
+	require.Nil(t, couchstore.AutoCompaction)
+}
+
+func TestMemoryQuotasQuota(t *testing.T) {
+	quotas := MemoryQuotas{
+		Data:      ptr.To[uint64](1),
+		Index:     ptr.To[uint64](2),
+		Search:    ptr.To[uint64](3),
+		Analytics: ptr.To[uint64](4),
+		Eventing:  ptr.To[uint64](5),
+		Query:     ptr.To[uint64](6),
+	}
+
+	for service, expected := range map[Service]uint64{
+		ServiceData:      1,
+		ServiceGSI:       2,
+		ServiceSearch:    3,
+		ServiceAnalytics: 4,
+		ServiceEventing:  5,
+		ServiceQuery:     6,
+	} {
+		quota, ok := quotas.Quota(service)
+		require.True(t, ok)
+		require.Equal(t, expected, quota)
+	}
+
+	_, ok := quotas.Quota(ServiceManagement)
+	require.False(t, ok)
+
+	_, ok = MemoryQuotas{}.Quota(ServiceData)
+	require.False(t, ok)
+}
+
+func TestGetPoolsDefaultTestCluster(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Nodes:   TestNodes{{Version: "7.2.0"}, {Version: "7.6.0"}},
+		Buckets: TestBuckets{"default": {UUID: "uuid"}},
+	})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	pools, err := client.GetPoolsDefault(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, pools.Nodes, 2)
+	require.Equal(t, cbvalue.Version("7.2.0"), pools.Nodes[0].Version)
+
+	require.Len(t, pools.Buckets, 1)
+	require.Equal(t, "default", pools.Buckets[0].Name)
+	require.Nil(t, pools.Buckets[0].AutoCompaction)
+}
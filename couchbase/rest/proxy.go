@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyOptions encapsulates the options available when routing requests to the cluster through a proxy.
+type ProxyOptions struct {
+	// URL is the proxy which requests are routed through e.g. 'http://proxy.example.com:3128'.
+	//
+	// NOTE: Mutually exclusive with 'FromEnvironment'.
+	URL string
+
+	// FromEnvironment routes requests through the proxy configured using the 'HTTP_PROXY', 'HTTPS_PROXY' and
+	// 'NO_PROXY' environment variables (or their lowercase equivalents).
+	FromEnvironment bool
+
+	// Bypass is a list of hosts which requests are sent to directly, rather than through the proxy; each entry uses the
+	// same format as the 'NO_PROXY' environment variable e.g. 'cb.internal', '.internal', '10.0.0.0/8' or '*'. When
+	// using 'FromEnvironment', these are in addition to the hosts in 'NO_PROXY'.
+	//
+	// NOTE: Requests to 'localhost' and loopback addresses are never sent through the proxy.
+	Bypass []string
+}
+
+// newProxyFunc returns the function used by the HTTP transport to determine which proxy (if any) a request should be
+// sent through, or <nil> if requests shouldn't be sent through a proxy.
+func newProxyFunc(options *ProxyOptions) (func(req *http.Request) (*url.URL, error), error) {
+	if options == nil || (options.URL == "" && !options.FromEnvironment) {
+		return nil, nil
+	}
+
+	if options.URL != "" && options.FromEnvironment {
+		return nil, ErrProxyURLAndFromEnvironment
+	}
+
+	var config *httpproxy.Config
+
+	if options.FromEnvironment {
+		config = httpproxy.FromEnvironment()
+	} else {
+		_, err := url.Parse(options.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy URL: %w", err)
+		}
+
+		config = &httpproxy.Config{HTTPProxy: options.URL, HTTPSProxy: options.URL}
+	}
+
+	bypass := options.Bypass
+
+	if config.NoProxy != "" {
+		bypass = append([]string{config.NoProxy}, bypass...)
+	}
+
+	config.NoProxy = strings.Join(bypass, ",")
+
+	proxy := config.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) { return proxy(req.URL) }, nil
+}
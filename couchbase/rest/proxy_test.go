@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProxyFunc(t *testing.T) {
+	type test struct {
+		name     string
+		options  *ProxyOptions
+		env      map[string]string
+		host     string
+		expected string
+	}
+
+	tests := []*test{
+		{
+			name:     "URL",
+			options:  &ProxyOptions{URL: "http://proxy.example.com:3128"},
+			host:     "http://node.example.com:8091",
+			expected: "http://proxy.example.com:3128",
+		},
+		{
+			name:     "URLTLS",
+			options:  &ProxyOptions{URL: "http://proxy.example.com:3128"},
+			host:     "https://node.example.com:18091",
+			expected: "http://proxy.example.com:3128",
+		},
+		{
+			name:    "URLBypassDomain",
+			options: &ProxyOptions{URL: "http://proxy.example.com:3128", Bypass: []string{".internal"}},
+			host:    "http://node.cb.internal:8091",
+		},
+		{
+			name:    "URLBypassCIDR",
+			options: &ProxyOptions{URL: "http://proxy.example.com:3128", Bypass: []string{"10.0.0.0/8"}},
+			host:    "http://10.1.2.3:8091",
+		},
+		{
+			name:     "URLBypassOtherHost",
+			options:  &ProxyOptions{URL: "http://proxy.example.com:3128", Bypass: []string{".internal"}},
+			host:     "http://node.example.com:8091",
+			expected: "http://proxy.example.com:3128",
+		},
+		{
+			name:     "FromEnvironment",
+			options:  &ProxyOptions{FromEnvironment: true},
+			env:      map[string]string{"HTTP_PROXY": "http://env.example.com:3128"},
+			host:     "http://node.example.com:8091",
+			expected: "http://env.example.com:3128",
+		},
+		{
+			name:    "FromEnvironmentNoProxy",
+			options: &ProxyOptions{FromEnvironment: true},
+			env:     map[string]string{"HTTP_PROXY": "http://env.example.com:3128", "NO_PROXY": "node.example.com"},
+			host:    "http://node.example.com:8091",
+		},
+		{
+			name:    "FromEnvironmentBypass",
+			options: &ProxyOptions{FromEnvironment: true, Bypass: []string{"node2.example.com"}},
+			env:     map[string]string{"HTTP_PROXY": "http://env.example.com:3128", "NO_PROXY": "node1.example.com"},
+			host:    "http://node2.example.com:8091",
+		},
+		{
+			name:    "FromEnvironmentNotSet",
+			options: &ProxyOptions{FromEnvironment: true},
+			host:    "http://node.example.com:8091",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "REQUEST_METHOD"} {
+				t.Setenv(name, test.env[name])
+				t.Setenv(strings.ToLower(name), "")
+			}
+
+			proxy, err := newProxyFunc(test.options)
+			require.NoError(t, err)
+			require.NotNil(t, proxy)
+
+			req, err := http.NewRequest(http.MethodGet, test.host, nil)
+			require.NoError(t, err)
+
+			url, err := proxy(req)
+			require.NoError(t, err)
+
+			if test.expected == "" {
+				require.Nil(t, url)
+				return
+			}
+
+			require.Equal(t, test.expected, url.String())
+		})
+	}
+}
+
+func TestNewProxyFuncDisabled(t *testing.T) {
+	for _, options := range []*ProxyOptions{nil, {}, {Bypass: []string{".internal"}}} {
+		proxy, err := newProxyFunc(options)
+		require.NoError(t, err)
+		require.Nil(t, proxy)
+	}
+}
+
+func TestNewProxyFuncURLAndFromEnvironment(t *testing.T) {
+	_, err := newProxyFunc(&ProxyOptions{URL: "http://proxy.example.com:3128", FromEnvironment: true})
+	require.ErrorIs(t, err, ErrProxyURLAndFromEnvironment)
+}
+
+func TestNewClientWithProxy(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{})
+	defer cluster.Close()
+
+	// Requests to loopback addresses are never sent through the proxy, so the client can still bootstrap
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		Proxy:            &ProxyOptions{URL: "http://proxy.example.com:3128"},
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.NotNil(t, client.client.Transport.(*http.Transport).Proxy)
+}
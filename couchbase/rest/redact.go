@@ -0,0 +1,304 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redactedMask is the value used in place of redacted values, it has a fixed length to avoid revealing any details
+// about the original value.
+const redactedMask = "*****"
+
+// RedactionOptions encapsulates the options available when redacting sensitive values from the logs emitted by the
+// client.
+//
+// NOTE: Redaction is always enabled, 'Authorization' (and similar) headers, URL credentials and 'password' fields are
+// always redacted; these options only allow redacting additional values.
+type RedactionOptions struct {
+	// Fields is a list of additional field names (matched case-insensitively) whose values are redacted from JSON
+	// bodies, form bodies and URL query strings e.g. 'secretAccessKey'.
+	Fields []string
+}
+
+// redactor removes credentials/secrets from values before they're logged.
+type redactor struct {
+	fields map[string]struct{}
+}
+
+// newRedactor returns a redactor which redacts the default set of sensitive fields, in addition to those in the given
+// options.
+func newRedactor(options *RedactionOptions) *redactor {
+	r := &redactor{fields: map[string]struct{}{"password": {}}}
+
+	if options == nil {
+		return r
+	}
+
+	for _, field := range options.Fields {
+		r.fields[strings.ToLower(field)] = struct{}{}
+	}
+
+	return r
+}
+
+// sensitive returns a boolean indicating whether the value of the given field/key should be redacted.
+func (r *redactor) sensitive(field string) bool {
+	_, ok := r.fields[strings.ToLower(field)]
+	return ok
+}
+
+// url returns a copy of the given URL with any password and sensitive query parameters redacted.
+func (r *redactor) url(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	redacted := *u
+
+	if _, ok := redacted.User.Password(); ok {
+		redacted.User = url.UserPassword(redacted.User.Username(), redactedMask)
+	}
+
+	if redacted.RawQuery != "" {
+		query, err := url.ParseQuery(redacted.RawQuery)
+		if err != nil {
+			query = url.Values{}
+		}
+
+		if r.values(query) || err != nil {
+			redacted.RawQuery = query.Encode()
+		}
+	}
+
+	return redacted.String()
+}
+
+// urlString is the same as 'url' but for a URL which has yet to be parsed, the whole value is redacted if it can't be
+// parsed.
+func (r *redactor) urlString(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return redactedMask
+	}
+
+	return r.url(parsed)
+}
+
+// values redacts any sensitive values in-place, returning a boolean indicating whether anything was redacted.
+func (r *redactor) values(values url.Values) bool {
+	var redacted bool
+
+	for key, vals := range values {
+		if !r.sensitive(key) {
+			continue
+		}
+
+		for i := range vals {
+			vals[i] = redactedMask
+		}
+
+		redacted = true
+	}
+
+	return redacted
+}
+
+// header returns a copy of the given headers with any credentials redacted.
+func (r *redactor) header(header http.Header) http.Header {
+	redacted := header.Clone()
+
+	for key, vals := range redacted {
+		if !sensitiveHeader(key) && !r.sensitive(key) {
+			continue
+		}
+
+		for i := range vals {
+			vals[i] = redactedMask
+		}
+	}
+
+	return redacted
+}
+
+// sensitiveHeader returns a boolean indicating whether the given header may contain credentials.
+func sensitiveHeader(key string) bool {
+	switch http.CanonicalHeaderKey(key) {
+	case "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie":
+		return true
+	}
+
+	return false
+}
+
+// body returns the given request/response body with any sensitive fields redacted; JSON and form encoded bodies are
+// supported, other bodies are returned as is.
+func (r *redactor) body(body []byte) string {
+	if json.Valid(body) {
+		return r.json(body)
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil || !r.values(values) {
+		return string(body)
+	}
+
+	return values.Encode()
+}
+
+// json returns the given (valid) JSON document with the values of any sensitive fields redacted, at any depth.
+func (r *redactor) json(body []byte) string {
+	var decoded any
+
+	// We use 'UseNumber' to avoid losing precision when re-encoding numbers
+	decoder := json.NewDecoder(strings.NewReader(string(body)))
+	decoder.UseNumber()
+
+	if decoder.Decode(&decoded) != nil || !r.walk(decoded) {
+		return string(body)
+	}
+
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return redactedMask
+	}
+
+	return string(encoded)
+}
+
+// walk redacts any sensitive fields in the given decoded JSON value in-place, returning a boolean indicating whether
+// anything was redacted.
+func (r *redactor) walk(value any) bool {
+	var redacted bool
+
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, val := range typed {
+			if r.sensitive(key) {
+				typed[key], redacted = redactedMask, true
+				continue
+			}
+
+			redacted = r.walk(val) || redacted
+		}
+	case []any:
+		for _, val := range typed {
+			redacted = r.walk(val) || redacted
+		}
+	}
+
+	return redacted
+}
+
+// error returns the message of the given error with any URLs it contains redacted.
+func (r *redactor) error(err error) string {
+	msg := err.Error()
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && urlErr.URL != "" {
+		msg = strings.ReplaceAll(msg, urlErr.URL, r.urlString(urlErr.URL))
+	}
+
+	return msg
+}
+
+// attr returns the given attribute with any sensitive values redacted.
+func (r *redactor) attr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+
+		redacted := make([]slog.Attr, 0, len(group))
+		for _, a := range group {
+			redacted = append(redacted, r.attr(a))
+		}
+
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+	}
+
+	if r.sensitive(attr.Key) || sensitiveHeader(attr.Key) {
+		return slog.String(attr.Key, redactedMask)
+	}
+
+	if attr.Value.Kind() != slog.KindAny {
+		return attr
+	}
+
+	switch value := attr.Value.Any().(type) {
+	case *url.URL:
+		return slog.String(attr.Key, r.url(value))
+	case url.URL:
+		return slog.String(attr.Key, r.url(&value))
+	case url.Values:
+		values := make(url.Values, len(value))
+		for key, vals := range value {
+			values[key] = append([]string(nil), vals...)
+		}
+
+		r.values(values)
+
+		return slog.String(attr.Key, values.Encode())
+	case http.Header:
+		return slog.Any(attr.Key, r.header(value))
+	case Header:
+		header := make(http.Header, len(value))
+		for key, val := range value {
+			header.Set(key, val)
+		}
+
+		return slog.Any(attr.Key, r.header(header))
+	case json.RawMessage:
+		return slog.String(attr.Key, r.body(value))
+	case []byte:
+		return slog.String(attr.Key, r.body(value))
+	case error:
+		return slog.String(attr.Key, r.error(value))
+	}
+
+	return attr
+}
+
+// redactingHandler is a 'slog.Handler' which redacts credentials/secrets from all the attributes of a record before
+// passing it on to the wrapped handler; this ensures that credentials aren't leaked regardless of the log level.
+type redactingHandler struct {
+	handler  slog.Handler
+	redactor *redactor
+}
+
+// newRedactingLogger returns a logger which redacts credentials/secrets before passing records to the given logger.
+func newRedactingLogger(logger *slog.Logger, options *RedactionOptions) *slog.Logger {
+	return slog.New(&redactingHandler{handler: logger.Handler(), redactor: newRedactor(options)})
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactor.attr(attr))
+		return true
+	})
+
+	return h.handler.Handle(ctx, redacted)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		redacted = append(redacted, h.redactor.attr(attr))
+	}
+
+	return &redactingHandler{handler: h.handler.WithAttrs(redacted), redactor: h.redactor}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{handler: h.handler.WithGroup(name), redactor: h.redactor}
+}
@@ -1,7 +1,11 @@
 package rest
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"net/url"
+	"os"
 	"time"
 
 	netutil "github.com/couchbase/tools-common/http/util"
@@ -47,6 +51,12 @@ type Request struct {
 	// Body is the request body itself. This attribute is not always required.
 	Body []byte
 
+	// CompressBody indicates that the body should be gzip compressed before being sent, this may significantly reduce
+	// the time taken to send large payloads over slow links.
+	//
+	// NOTE: Bodies smaller than the 'CompressionThreshold' are not compressed.
+	CompressBody bool
+
 	// Endpoint is the REST endpoint to hit, all endpoints should be of type 'Endpoint' so that urls are correctly
 	// escaped.
 	Endpoint Endpoint
@@ -58,6 +68,12 @@ type Request struct {
 	// provided, this attribute is ignored.
 	Service Service
 
+	// NodeUUID scopes the request to the node with the given uuid (or otp node name e.g. 'ns_1@10.0.0.1'), the
+	// request is dispatched to the 'Service' running on that node. If 'Host' is provided, this attribute is ignored.
+	//
+	// NOTE: A 'NodeNotFoundError' is returned if the node is not in the cluster.
+	NodeUUID string
+
 	// ExpectedStatusCode indicates that when this REST request is successful, we will get this specific status code.
 	ExpectedStatusCode int
 
@@ -66,6 +82,13 @@ type Request struct {
 	// NOTE: A value of -1 indicates that the timeout should be disabled.
 	Timeout time.Duration
 
+	// TotalTimeout limits the total time spent executing the request, including all attempts and the time spent
+	// waiting between them; this complements 'Timeout', which only limits a single attempt. A zero value means there's
+	// no limit other than the deadline of the context (if any).
+	//
+	// NOTE: The response body must be read before the total timeout expires.
+	TotalTimeout time.Duration
+
 	// Idempotent indicates whether this request is idempotent and can be retried.
 	//
 	// The following attributes (RetryOnStatusCodes and NoRetryOnStatusCodes) may be used to configure retry
@@ -80,17 +103,104 @@ type Request struct {
 
 	// NoRetryOnStatusCodes is a list of status codes which will explicitly not be retried.
 	NoRetryOnStatusCodes []int
+
+	// Weight is the number of slots this request occupies when the client is limiting the number of concurrent
+	// requests, this may be used to indicate that a request is particularly expensive. Defaults to one.
+	//
+	// NOTE: Weights larger than the client's 'MaxConcurrentRequests' are truncated to the limit.
+	Weight int
+
+	// IfMatch is an entity tag which will be sent using the 'If-Match' header, this allows optimistic concurrency
+	// control for endpoints which support it; a 'PreconditionFailedError' is returned if the resource has been modified.
+	IfMatch string
+
+	// IfNoneMatch is an entity tag which will be sent using the 'If-None-Match' header.
+	IfNoneMatch string
+
+	// IdempotencyKey is a unique key which will be sent using the 'Idempotency-Key' header, endpoints which support
+	// idempotency keys will only apply a request with a given key once. Providing a key makes the request eligible for
+	// retries, regardless of its method.
+	//
+	// NOTE: The same key is sent for every attempt, a new key must be used for each logical request.
+	IdempotencyKey string
+
+	// OnBehalfOf is the user this request is performed on behalf of, sent using the 'cb-on-behalf-of' header; an
+	// 'UnsupportedServerVersionError' is returned for clusters prior to 7.0.0.
+	//
+	// NOTE: Can't be used with clients which authenticate using a 'Signer'.
+	OnBehalfOf *OnBehalfOf
+
+	// MaxResponseBytes is the maximum size of the response body which will be read, a 'BodyTooLargeError' is returned
+	// for larger bodies. A zero value means there's no limit.
+	MaxResponseBytes int64
+
+	// SpillThreshold is the size above which the response body is written to a temporary file, rather than being held
+	// in memory; spilled bodies must be read using 'Response.Reader'. A zero value means bodies are never spilled.
+	//
+	// NOTE: Only applies when using 'Execute', to responses with the expected status code. 'Response.Close' must be
+	// called to remove the temporary file.
+	SpillThreshold int64
 }
 
 // IsIdempotent returns a boolean indicating whether this request is idempotent and may be retried.
 func (r *Request) IsIdempotent() bool {
-	return r.Idempotent || netutil.IsMethodIdempotent(string(r.Method))
+	return r.Idempotent || r.IdempotencyKey != "" || netutil.IsMethodIdempotent(string(r.Method))
 }
 
 // Response represents a REST response from the Couchbase Cluster.
 type Response struct {
 	StatusCode int
-	Body       []byte
+
+	// Body is the response body, this will be <nil> if the body was spilled to disk; use 'Reader' to read bodies
+	// regardless of whether they were spilled.
+	Body []byte
+
+	// ETag is the entity tag returned by the cluster, this may be used as the 'IfMatch' attribute of a subsequent
+	// request. Will be empty for endpoints which don't support conditional requests.
+	ETag string
+
+	// file is the temporary file containing the body, when it was larger than the request's 'SpillThreshold'.
+	file *os.File
+}
+
+// Spilled returns a boolean indicating whether the response body was written to a temporary file, rather than being
+// held in memory.
+func (r *Response) Spilled() bool {
+	return r.file != nil
+}
+
+// Reader returns a reader for the response body, regardless of whether it was spilled to disk.
+//
+// NOTE: Readers for spilled bodies share the same offset, and are invalidated by 'Close'.
+func (r *Response) Reader() io.ReadSeeker {
+	if r.file != nil {
+		return r.file
+	}
+
+	return bytes.NewReader(r.Body)
+}
+
+// Close removes the temporary file containing the response body, if it was spilled to disk; it's safe to call for all
+// responses.
+func (r *Response) Close() error {
+	if r.file == nil {
+		return nil
+	}
+
+	file := r.file
+	r.file = nil
+
+	err := file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	err = os.Remove(file.Name())
+	if err != nil {
+		return fmt.Errorf("failed to remove file: %w", err)
+	}
+
+	return nil
 }
 
 // StreamingResponse encapsulates a single streaming response payload/error.
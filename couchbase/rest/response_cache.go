@@ -0,0 +1,173 @@
+package rest
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ResponseCacheOptions encapsulates the options available when enabling the response cache.
+type ResponseCacheOptions struct {
+	// TTL is the duration for which a cached response is used, before the request is dispatched again. Defaults to
+	// 'DefaultResponseCacheTTL'.
+	TTL time.Duration
+
+	// Endpoints are the read-only endpoints whose responses may be cached e.g. 'EndpointPoolsDefault'; only 'GET'
+	// requests to these exact endpoints are cached.
+	//
+	// NOTE: Requests using any other method to one of these endpoints invalidate the cached responses for the endpoint.
+	Endpoints []Endpoint
+}
+
+// defaults fills any missing attributes to a sane default.
+func (r *ResponseCacheOptions) defaults() {
+	if r.TTL <= 0 {
+		r.TTL = DefaultResponseCacheTTL
+	}
+}
+
+// ResponseCacheStats contains metrics about the usage of the response cache.
+type ResponseCacheStats struct {
+	// Hits is the number of requests which were served from the cache.
+	Hits uint64
+
+	// Misses is the number of cacheable requests which were dispatched to the cluster.
+	Misses uint64
+
+	// Entries is the number of responses currently cached, this may include expired responses.
+	Entries int
+}
+
+// responseCacheKey uniquely identifies a cached response.
+type responseCacheKey struct {
+	method   Method
+	host     string
+	endpoint Endpoint
+	query    string
+}
+
+// responseCacheEntry is a cached response, and the time at which it expires.
+type responseCacheEntry struct {
+	response Response
+	expires  time.Time
+}
+
+// responseCache is an in-memory cache of responses from read-only endpoints, used to avoid repeatedly dispatching the
+// same requests to the cluster.
+type responseCache struct {
+	ttl       time.Duration
+	endpoints []Endpoint
+	clock     Clock
+
+	lock    sync.Mutex
+	entries map[responseCacheKey]responseCacheEntry
+	hits    uint64
+	misses  uint64
+}
+
+// newResponseCache returns a new response cache which uses the given clock to expire entries, or <nil> if the given
+// options are <nil> (the cache is disabled).
+func newResponseCache(options *ResponseCacheOptions, clock Clock) *responseCache {
+	if options == nil {
+		return nil
+	}
+
+	options.defaults()
+
+	return &responseCache{
+		ttl:       options.TTL,
+		endpoints: slices.Clone(options.Endpoints),
+		clock:     clock,
+		entries:   make(map[responseCacheKey]responseCacheEntry),
+	}
+}
+
+// cacheable returns a boolean indicating whether the response for the given request may be cached.
+func (r *responseCache) cacheable(request *Request) bool {
+	return r != nil && request.Method == http.MethodGet && slices.Contains(r.endpoints, request.Endpoint)
+}
+
+// get returns a copy of the cached response for the given request, if there's one which hasn't expired.
+func (r *responseCache) get(request *Request) (*Response, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := newResponseCacheKey(request)
+
+	entry, ok := r.entries[key]
+	if ok && r.clock.Now().After(entry.expires) {
+		delete(r.entries, key)
+
+		ok = false
+	}
+
+	if !ok {
+		r.misses++
+		return nil, false
+	}
+
+	r.hits++
+
+	response := entry.response
+	response.Body = slices.Clone(entry.response.Body)
+
+	return &response, true
+}
+
+// set caches a copy of the given response for the given request.
+func (r *responseCache) set(request *Request, response *Response) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entry := responseCacheEntry{response: *response, expires: r.clock.Now().Add(r.ttl)}
+	entry.response.Body = slices.Clone(response.Body)
+
+	r.entries[newResponseCacheKey(request)] = entry
+}
+
+// invalidate removes the cached responses for the given endpoints, or all the cached responses if no endpoints are
+// given.
+func (r *responseCache) invalidate(endpoints ...Endpoint) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(endpoints) == 0 {
+		clear(r.entries)
+		return
+	}
+
+	for key := range r.entries {
+		if slices.Contains(endpoints, key.endpoint) {
+			delete(r.entries, key)
+		}
+	}
+}
+
+// stats returns metrics about the usage of the cache.
+func (r *responseCache) stats() ResponseCacheStats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return ResponseCacheStats{Hits: r.hits, Misses: r.misses, Entries: len(r.entries)}
+}
+
+// newResponseCacheKey returns the key used to cache the response for the given request. Requests which aren't sent to
+// an explicit host are keyed by service, any node running the service is expected to return the same response.
+func newResponseCacheKey(request *Request) responseCacheKey {
+	host := request.Host
+	if host == "" {
+		host = string(request.Service)
+	}
+
+	if request.NodeUUID != "" {
+		host += "/" + request.NodeUUID
+	}
+
+	return responseCacheKey{
+		method:   request.Method,
+		host:     host,
+		endpoint: request.Endpoint,
+		query:    request.QueryParameters.Encode(),
+	}
+}
@@ -0,0 +1,227 @@
+package rest
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newCountingTestHandler(t *testing.T, calls *atomic.Int64, body []byte) http.HandlerFunc {
+	handler := NewTestHandler(t, http.StatusOK, body)
+
+	return func(writer http.ResponseWriter, request *http.Request) {
+		calls.Add(1)
+		handler(writer, request)
+	}
+}
+
+func newResponseCacheTestClient(t *testing.T, cluster *TestCluster, options *ResponseCacheOptions) *Client {
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	client.cache = newResponseCache(options, client.clock)
+
+	return client
+}
+
+func TestResponseCacheOptionsDefaults(t *testing.T) {
+	options := ResponseCacheOptions{}
+	options.defaults()
+	require.Equal(t, DefaultResponseCacheTTL, options.TTL)
+
+	options = ResponseCacheOptions{TTL: time.Minute}
+	options.defaults()
+	require.Equal(t, time.Minute, options.TTL)
+}
+
+func TestNewResponseCacheDisabled(t *testing.T) {
+	cache := newResponseCache(nil, systemClock{})
+	require.Nil(t, cache)
+	require.False(t, cache.cacheable(&Request{Method: http.MethodGet, Endpoint: "/test"}))
+}
+
+func TestClientExecuteResponseCache(t *testing.T) {
+	var calls atomic.Int64
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", newCountingTestHandler(t, &calls, []byte("body")))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client := newResponseCacheTestClient(t, cluster, &ResponseCacheOptions{Endpoints: []Endpoint{"/test"}})
+	defer client.Close()
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	expected := &Response{StatusCode: http.StatusOK, Body: []byte("body")}
+
+	for i := 0; i < 2; i++ {
+		actual, err := client.Execute(request)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+
+		// Modifying the returned body must not modify the cached response
+		actual.Body[0] = 'B'
+	}
+
+	require.Equal(t, int64(1), calls.Load())
+	require.Equal(t, ResponseCacheStats{Hits: 1, Misses: 1, Entries: 1}, client.ResponseCacheStats())
+}
+
+func TestClientExecuteResponseCacheExpired(t *testing.T) {
+	var calls atomic.Int64
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", newCountingTestHandler(t, &calls, []byte("body")))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client := newResponseCacheTestClient(t, cluster, &ResponseCacheOptions{
+		TTL:       time.Minute,
+		Endpoints: []Endpoint{"/test"},
+	})
+	defer client.Close()
+
+	clock := &testClock{now: time.Now(), advance: true}
+
+	client.cache.clock = clock
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	_, err := client.Execute(request)
+	require.NoError(t, err)
+
+	<-clock.After(time.Minute + time.Second)
+
+	_, err = client.Execute(request)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(2), calls.Load())
+	require.Equal(t, ResponseCacheStats{Misses: 2, Entries: 1}, client.ResponseCacheStats())
+}
+
+func TestClientExecuteResponseCacheEndpointNotCached(t *testing.T) {
+	var calls atomic.Int64
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", newCountingTestHandler(t, &calls, []byte("body")))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client := newResponseCacheTestClient(t, cluster, &ResponseCacheOptions{Endpoints: []Endpoint{"/other"}})
+	defer client.Close()
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Execute(request)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, int64(2), calls.Load())
+	require.Equal(t, ResponseCacheStats{}, client.ResponseCacheStats())
+}
+
+func TestClientExecuteResponseCacheInvalidatedByWrite(t *testing.T) {
+	var calls atomic.Int64
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", newCountingTestHandler(t, &calls, []byte("body")))
+	handlers.Add(http.MethodPost, "/test", NewTestHandler(t, http.StatusOK, nil))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client := newResponseCacheTestClient(t, cluster, &ResponseCacheOptions{Endpoints: []Endpoint{"/test"}})
+	defer client.Close()
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	_, err := client.Execute(request)
+	require.NoError(t, err)
+
+	_, err = client.Execute(&Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+
+	_, err = client.Execute(request)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(2), calls.Load())
+	require.Equal(t, ResponseCacheStats{Misses: 2, Entries: 1}, client.ResponseCacheStats())
+}
+
+func TestClientInvalidateResponseCache(t *testing.T) {
+	var calls atomic.Int64
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", newCountingTestHandler(t, &calls, []byte("body")))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client := newResponseCacheTestClient(t, cluster, &ResponseCacheOptions{Endpoints: []Endpoint{"/test"}})
+	defer client.Close()
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	_, err := client.Execute(request)
+	require.NoError(t, err)
+
+	client.InvalidateResponseCache()
+	require.Zero(t, client.ResponseCacheStats().Entries)
+
+	_, err = client.Execute(request)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(2), calls.Load())
+}
+
+func TestClientResponseCacheStatsDisabled(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	client.InvalidateResponseCache()
+	require.Equal(t, ResponseCacheStats{}, client.ResponseCacheStats())
+}
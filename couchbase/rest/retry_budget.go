@@ -0,0 +1,185 @@
+package rest
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudgetBuckets is the number of buckets the sliding window is split into, requests/retries expire from the
+// window one bucket at a time.
+const retryBudgetBuckets = 10
+
+// RetryBudgetOptions encapsulates the options available when enabling the retry budget, which limits the number of
+// retries performed across all the requests dispatched by a client.
+//
+// NOTE: Without a budget each request is retried independently, meaning that during a cluster-wide outage the load on
+// the cluster is multiplied by the number of retries.
+type RetryBudgetOptions struct {
+	// Ratio is the maximum number of retries, as a fraction of the number of requests dispatched within the window,
+	// for example 0.2 permits one retry for every five requests. Defaults to 'DefaultRetryBudgetRatio'.
+	Ratio float64
+
+	// Window is the duration of the sliding window over which requests/retries are counted. Defaults to
+	// 'DefaultRetryBudgetWindow'.
+	Window time.Duration
+
+	// MinRetries is the number of retries permitted within the window regardless of the ratio. Defaults to
+	// 'DefaultRetryBudgetMinRetries', a negative value means only the ratio is considered.
+	MinRetries int
+}
+
+// defaults fills any missing attributes to a sane default.
+func (r *RetryBudgetOptions) defaults() {
+	if r.Ratio <= 0 {
+		r.Ratio = DefaultRetryBudgetRatio
+	}
+
+	if r.Window <= 0 {
+		r.Window = DefaultRetryBudgetWindow
+	}
+
+	if r.MinRetries == 0 {
+		r.MinRetries = DefaultRetryBudgetMinRetries
+	}
+
+	if r.MinRetries < 0 {
+		r.MinRetries = 0
+	}
+}
+
+// RetryBudgetStats contains metrics about the usage of the retry budget.
+type RetryBudgetStats struct {
+	// Requests is the number of requests dispatched within the current window.
+	Requests int
+
+	// Retries is the number of retries performed within the current window.
+	Retries int
+
+	// Refused is the total number of retries which were refused because the budget was exhausted.
+	Refused uint64
+}
+
+// retryBudgetBucket counts the requests/retries which were dispatched within a slice of the sliding window.
+type retryBudgetBucket struct {
+	start    time.Time
+	requests int
+	retries  int
+}
+
+// retryBudget limits the ratio of retries to requests within a sliding window, shared by all the requests dispatched
+// by a client.
+type retryBudget struct {
+	ratio      float64
+	minRetries int
+	width      time.Duration
+	clock      Clock
+
+	lock    sync.Mutex
+	buckets [retryBudgetBuckets]retryBudgetBucket
+	refused uint64
+}
+
+// newRetryBudget returns a new retry budget, or <nil> if the given options are <nil> (the budget is disabled).
+func newRetryBudget(options *RetryBudgetOptions, clock Clock) *retryBudget {
+	if options == nil {
+		return nil
+	}
+
+	options.defaults()
+
+	return &retryBudget{
+		ratio:      options.Ratio,
+		minRetries: options.MinRetries,
+		width:      max(options.Window/retryBudgetBuckets, time.Nanosecond),
+		clock:      clock,
+	}
+}
+
+// recordRequest records that a request has been dispatched.
+func (r *retryBudget) recordRequest() {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.bucket().requests++
+}
+
+// acquire returns a boolean indicating whether a retry is permitted by the budget, recording the retry if it is.
+func (r *retryBudget) acquire() bool {
+	if r == nil {
+		return true
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// Ensure the current bucket exists before summing, so that expired buckets aren't counted
+	bucket := r.bucket()
+
+	requests, retries := r.sum()
+
+	if float64(retries) >= float64(r.minRetries)+r.ratio*float64(requests) {
+		r.refused++
+		return false
+	}
+
+	bucket.retries++
+
+	return true
+}
+
+// stats returns metrics about the usage of the retry budget.
+func (r *retryBudget) stats() RetryBudgetStats {
+	if r == nil {
+		return RetryBudgetStats{}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.bucket()
+
+	requests, retries := r.sum()
+
+	return RetryBudgetStats{Requests: requests, Retries: retries, Refused: r.refused}
+}
+
+// bucket returns the bucket for the current time, resetting it if it was last used in a previous window.
+//
+// NOTE: Expects the lock to be held.
+func (r *retryBudget) bucket() *retryBudgetBucket {
+	var (
+		start  = r.clock.Now().Truncate(r.width)
+		bucket = &r.buckets[(start.UnixNano()/int64(r.width))%retryBudgetBuckets]
+	)
+
+	if !bucket.start.Equal(start) {
+		*bucket = retryBudgetBucket{start: start}
+	}
+
+	return bucket
+}
+
+// sum returns the number of requests/retries within the current window.
+//
+// NOTE: Expects the lock to be held.
+func (r *retryBudget) sum() (int, int) {
+	var (
+		cutoff            = r.clock.Now().Truncate(r.width).Add(-r.width * (retryBudgetBuckets - 1))
+		requests, retries int
+	)
+
+	for _, bucket := range r.buckets {
+		if bucket.start.Before(cutoff) {
+			continue
+		}
+
+		requests += bucket.requests
+		retries += bucket.retries
+	}
+
+	return requests, retries
+}
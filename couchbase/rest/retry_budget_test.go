@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBudgetOptionsDefaults(t *testing.T) {
+	options := RetryBudgetOptions{}
+	options.defaults()
+	require.Equal(t, DefaultRetryBudgetRatio, options.Ratio)
+	require.Equal(t, DefaultRetryBudgetWindow, options.Window)
+	require.Equal(t, DefaultRetryBudgetMinRetries, options.MinRetries)
+
+	options = RetryBudgetOptions{Ratio: 0.5, Window: time.Minute, MinRetries: -1}
+	options.defaults()
+	require.Equal(t, 0.5, options.Ratio)
+	require.Equal(t, time.Minute, options.Window)
+	require.Zero(t, options.MinRetries)
+}
+
+func TestNewRetryBudgetDisabled(t *testing.T) {
+	budget := newRetryBudget(nil, systemClock{})
+	require.Nil(t, budget)
+
+	// A disabled budget permits all retries
+	budget.recordRequest()
+	require.True(t, budget.acquire())
+	require.Zero(t, budget.stats())
+}
+
+func TestRetryBudgetMinRetries(t *testing.T) {
+	budget := newRetryBudget(&RetryBudgetOptions{MinRetries: 2}, &testClock{now: time.Unix(0, 0)})
+
+	require.True(t, budget.acquire())
+	require.True(t, budget.acquire())
+	require.False(t, budget.acquire())
+
+	require.Equal(t, RetryBudgetStats{Retries: 2, Refused: 1}, budget.stats())
+}
+
+func TestRetryBudgetRatio(t *testing.T) {
+	budget := newRetryBudget(&RetryBudgetOptions{Ratio: 0.2, MinRetries: -1}, &testClock{now: time.Unix(0, 0)})
+
+	for i := 0; i < 10; i++ {
+		budget.recordRequest()
+	}
+
+	require.True(t, budget.acquire())
+	require.True(t, budget.acquire())
+	require.False(t, budget.acquire())
+
+	budget.recordRequest()
+	budget.recordRequest()
+	budget.recordRequest()
+	budget.recordRequest()
+	budget.recordRequest()
+
+	require.True(t, budget.acquire())
+	require.False(t, budget.acquire())
+
+	require.Equal(t, RetryBudgetStats{Requests: 15, Retries: 3, Refused: 2}, budget.stats())
+}
+
+func TestRetryBudgetSlidingWindow(t *testing.T) {
+	clock := &testClock{now: time.Unix(0, 0)}
+
+	budget := newRetryBudget(&RetryBudgetOptions{Window: 10 * time.Second, MinRetries: 1}, clock)
+
+	require.True(t, budget.acquire())
+	require.False(t, budget.acquire())
+
+	// The retry is still within the window
+	clock.now = clock.now.Add(9 * time.Second)
+	require.False(t, budget.acquire())
+
+	// The retry has expired from the window, so another is permitted
+	clock.now = clock.now.Add(time.Second)
+	require.True(t, budget.acquire())
+	require.False(t, budget.acquire())
+
+	require.Equal(t, RetryBudgetStats{Retries: 1, Refused: 3}, budget.stats())
+}
+
+func TestClientExecuteRetryBudgetExhausted(t *testing.T) {
+	var (
+		calls    atomic.Int64
+		handlers = make(TestHandlers)
+		handler  = NewTestHandler(t, http.StatusServiceUnavailable, nil)
+	)
+
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		calls.Add(1)
+		handler(writer, request)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	client.retryBudget = newRetryBudget(&RetryBudgetOptions{Ratio: 0.2, MinRetries: -1}, &testClock{now: time.Unix(0, 0)})
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	// The budget permits a single retry for the first request, the second retry is refused
+	_, err = client.Execute(request)
+	require.ErrorIs(t, err, ErrRetryBudgetExhausted)
+
+	var unexpected *UnexpectedStatusCodeError
+	require.ErrorAs(t, err, &unexpected)
+
+	require.Equal(t, int64(2), calls.Load())
+	require.Equal(t, RetryBudgetStats{Requests: 1, Retries: 1, Refused: 1}, client.RetryBudgetStats())
+}
+
+func TestClientExecuteRetryBudgetExhaustedDoesNotWait(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("Retry-After", "30")
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	clock := &testClock{now: time.Unix(0, 0)}
+
+	client.clock = clock
+	client.retryBudget = newRetryBudget(&RetryBudgetOptions{Ratio: 0.2, MinRetries: -1}, clock)
+
+	_, err = client.Execute(&Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	})
+	require.ErrorIs(t, err, ErrRetryBudgetExhausted)
+
+	// Only the permitted retry should wait for the 'Retry-After' duration, the refused retry should fail immediately
+	require.Len(t, clock.Waits(), 1)
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"golang.org/x/exp/slices"
+)
+
+// routing tracks the nodes which should be avoided by subsequent attempts to dispatch a request.
+type routing struct {
+	// maintenance are the nodes which reported they're in maintenance, these are never used.
+	maintenance nodeSet
+
+	// unreachable are the nodes which failed with a connection error, these are only used once all the other nodes
+	// running the service have also failed.
+	unreachable nodeSet
+}
+
+// nodeSet is a set of nodes which should be avoided whilst dispatching a request, in the order they were added.
+type nodeSet []string
+
+// add adds the given node to the set.
+func (n *nodeSet) add(node string) {
+	if node != "" && !slices.Contains(*n, node) {
+		*n = append(*n, node)
+	}
+}
+
+// filter returns the given hosts, excluding those which are in the set.
+func (n nodeSet) filter(hosts []string) []string {
+	if len(n) == 0 {
+		return hosts
+	}
+
+	return slices.DeleteFunc(slices.Clone(hosts), func(host string) bool { return slices.Contains(n, host) })
+}
+
+// filter returns the given hosts, excluding those which are in maintenance, or unreachable.
+//
+// NOTE: Unreachable hosts are only excluded when there's another host available.
+func (r routing) filter(hosts []string) []string {
+	available := r.maintenance.filter(hosts)
+
+	reachable := r.unreachable.filter(available)
+	if len(reachable) == 0 {
+		return available
+	}
+
+	return reachable
+}
+
+// offset returns the offset used to select a node for the given attempt (zero indexed); moving to another node after a
+// connection error doesn't advance the offset, so each reachable node is tried in turn.
+func (r routing) offset(attempt int) int {
+	return max(attempt-len(r.unreachable), 0)
+}
+
+// isConnectionError returns a boolean indicating whether the given error occurred whilst connecting to, or
+// communicating with a node before receiving a response e.g. the connection was refused/reset, or the dial timed out;
+// the node may have crashed, so the request should be dispatched to another node.
+func isConnectionError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var (
+		opErr        *net.OpError
+		socketClosed *SocketClosedInFlightError
+	)
+
+	return errors.As(err, &opErr) || errors.As(err, &socketClosed)
+}
@@ -0,0 +1,166 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	testutil "github.com/couchbase/tools-common/testing/util"
+)
+
+func TestRoutingFilter(t *testing.T) {
+	var route routing
+
+	require.Equal(t, []string{"a", "b", "c"}, route.filter([]string{"a", "b", "c"}))
+
+	route.maintenance.add("a")
+	route.unreachable.add("b")
+
+	require.Equal(t, []string{"c"}, route.filter([]string{"a", "b", "c"}))
+
+	// Unreachable nodes are used once there are no other nodes, unlike nodes in maintenance
+	route.unreachable.add("c")
+
+	require.Equal(t, []string{"b", "c"}, route.filter([]string{"a", "b", "c"}))
+
+	route.maintenance.add("b")
+	route.maintenance.add("c")
+
+	require.Empty(t, route.filter([]string{"a", "b", "c"}))
+}
+
+func TestRoutingOffset(t *testing.T) {
+	var route routing
+
+	require.Equal(t, 0, route.offset(0))
+	require.Equal(t, 2, route.offset(2))
+
+	route.unreachable.add("a")
+
+	require.Equal(t, 0, route.offset(0))
+	require.Equal(t, 0, route.offset(1))
+	require.Equal(t, 1, route.offset(2))
+}
+
+func TestIsConnectionError(t *testing.T) {
+	require.False(t, isConnectionError(nil))
+	require.False(t, isConnectionError(errors.New("error")))
+	require.False(t, isConnectionError(context.DeadlineExceeded))
+	require.True(t, isConnectionError(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}))
+	require.True(t, isConnectionError(&net.OpError{Op: "read", Err: syscall.ECONNRESET}))
+	require.True(t, isConnectionError(&SocketClosedInFlightError{}))
+}
+
+// newClosingListener returns a listener which closes every connection it accepts, simulating a node which has crashed,
+// along with the number of requests it received for the given endpoint.
+func newClosingListener(t *testing.T, endpoint string) (net.Listener, *atomic.Int64) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	received := &atomic.Int64{}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			if strings.Contains(line, " "+endpoint+" ") {
+				received.Add(1)
+			}
+
+			_ = conn.Close()
+		}
+	}()
+
+	return listener, received
+}
+
+func TestClientExecuteConnectionErrorRerouted(t *testing.T) {
+	attempts := &atomic.Int64{}
+
+	crashed, received := newClosingListener(t, "/test")
+	defer crashed.Close()
+
+	var secondary *TestCluster
+
+	// Every node must return the same config, otherwise the crashed node may be removed when updating the config
+	nodeServices := func(writer http.ResponseWriter, _ *http.Request) {
+		testutil.EncodeJSON(t, writer, ClusterConfig{
+			Nodes: Nodes{
+				{
+					Hostname: secondary.Address(),
+					Services: &Services{Management: uint16(crashed.Addr().(*net.TCPAddr).Port)},
+				},
+				{Hostname: secondary.Address(), Services: &Services{Management: secondary.Port()}},
+			},
+		})
+	}
+
+	// The healthy node initially fails with a retryable status code, so that requests would cycle back to the crashed
+	// node if it weren't avoided.
+	secondaryHandlers := make(TestHandlers)
+	secondaryHandlers.Add(http.MethodGet, string(EndpointNodesServices), nodeServices)
+	secondaryHandlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		if attempts.Add(1) == 1 {
+			NewTestHandler(t, http.StatusServiceUnavailable, nil)(writer, request)
+			return
+		}
+
+		NewTestHandler(t, http.StatusOK, []byte("body"))(writer, request)
+	})
+
+	secondary = NewTestCluster(t, TestClusterOptions{Handlers: secondaryHandlers})
+	defer secondary.Close()
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, string(EndpointNodesServices), nodeServices)
+
+	primary := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer primary.Close()
+
+	// Health aware routing would also avoid the crashed node, once it's failed enough requests
+	client, err := NewClient(ClientOptions{
+		ConnectionString:          primary.URL(),
+		DisableCCP:                true,
+		Provider:                  provider,
+		DisableHealthAwareRouting: true,
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	response, err := client.Execute(&Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte("body"), response.Body)
+	require.Equal(t, int64(1), received.Load())
+	require.Equal(t, int64(2), attempts.Load())
+}
+
+func TestNodeSetFilter(t *testing.T) {
+	var nodes nodeSet
+
+	require.Equal(t, []string{"a", "b"}, nodes.filter([]string{"a", "b"}))
+
+	nodes.add("a")
+	nodes.add("a")
+	nodes.add("")
+
+	require.Equal(t, nodeSet{"a"}, nodes)
+	require.Equal(t, []string{"b"}, nodes.filter([]string{"a", "b"}))
+}
@@ -0,0 +1,203 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SearchIndex represents a Search (FTS) index definition, as accepted/returned by the Search Service.
+//
+// NOTE: The index specific parameters are left encoded, they're passed through to/from the Search Service as is.
+type SearchIndex struct {
+	Name         string          `json:"name"`
+	Type         string          `json:"type"`
+	UUID         string          `json:"uuid,omitempty"`
+	SourceType   string          `json:"sourceType"`
+	SourceName   string          `json:"sourceName"`
+	SourceUUID   string          `json:"sourceUUID,omitempty"`
+	Params       json.RawMessage `json:"params,omitempty"`
+	SourceParams json.RawMessage `json:"sourceParams,omitempty"`
+	PlanParams   json.RawMessage `json:"planParams,omitempty"`
+}
+
+// SearchIndexProgress represents the build progress of a Search (FTS) index.
+type SearchIndexProgress struct {
+	DocCount         uint64 `json:"doc_count"`
+	SeqnosReceived   uint64 `json:"tot_seq_received"`
+	MutationsToIndex uint64 `json:"num_mutations_to_index"`
+}
+
+// Built returns a boolean indicating whether the index has caught up with the mutations in its source bucket.
+func (s SearchIndexProgress) Built() bool {
+	return s.MutationsToIndex == 0
+}
+
+// searchResponse is the envelope returned by the Search Service for all requests.
+type searchResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+// CreateSearchIndex creates (or updates, when the definition contains the uuid of the existing index) the given Search
+// index definition.
+func (c *Client) CreateSearchIndex(ctx context.Context, index SearchIndex) error {
+	body, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index definition: %w", err)
+	}
+
+	request := &Request{
+		Body:               body,
+		ContentType:        ContentTypeJSON,
+		Endpoint:           EndpointSearchIndex.Format(index.Name),
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPut,
+		Service:            ServiceSearch,
+	}
+
+	_, err = c.executeSearchRequest(ctx, request, index.Name)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	return nil
+}
+
+// GetSearchIndex returns the Search index definition with the given name, a 'SearchIndexNotFoundError' is returned if
+// the index doesn't exist.
+func (c *Client) GetSearchIndex(ctx context.Context, name string) (*SearchIndex, error) {
+	request := &Request{
+		Endpoint:           EndpointSearchIndex.Format(name),
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceSearch,
+		Idempotent:         true,
+	}
+
+	body, err := c.executeSearchRequest(ctx, request, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var decoded struct {
+		IndexDef *SearchIndex `json:"indexDef"`
+	}
+
+	err = json.Unmarshal(body, &decoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal index definition: %w", err)
+	}
+
+	if decoded.IndexDef == nil {
+		return nil, &SearchIndexNotFoundError{name: name}
+	}
+
+	return decoded.IndexDef, nil
+}
+
+// DeleteSearchIndex deletes the Search index with the given name, a 'SearchIndexNotFoundError' is returned if the index
+// doesn't exist.
+func (c *Client) DeleteSearchIndex(ctx context.Context, name string) error {
+	request := &Request{
+		Endpoint:           EndpointSearchIndex.Format(name),
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodDelete,
+		Service:            ServiceSearch,
+	}
+
+	_, err := c.executeSearchRequest(ctx, request, name)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	return nil
+}
+
+// GetSearchIndexProgress returns the build progress of the Search index with the given name.
+func (c *Client) GetSearchIndexProgress(ctx context.Context, name string) (*SearchIndexProgress, error) {
+	request := &Request{
+		Endpoint:           EndpointSearchIndexProgress.Format(name),
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceSearch,
+		Idempotent:         true,
+	}
+
+	body, err := c.executeSearchRequest(ctx, request, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var progress SearchIndexProgress
+
+	err = json.Unmarshal(body, &progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal index progress: %w", err)
+	}
+
+	return &progress, nil
+}
+
+// WaitForSearchIndexBuild polls the build progress of the Search index with the given name until it has been built,
+// or the given context is cancelled.
+func (c *Client) WaitForSearchIndexBuild(ctx context.Context, name string) error {
+	poll := func(_ int) (bool, error) {
+		progress, err := c.GetSearchIndexProgress(ctx, name)
+		if err != nil {
+			return false, fmt.Errorf("failed to get index progress: %w", err)
+		}
+
+		return progress.Built(), nil
+	}
+
+	_, err := c.PollWithContext(ctx, poll)
+	if err != nil {
+		return err
+	}
+
+	// Polling stops without an error if the context is cancelled, ensure we don't report success in that case
+	return ctx.Err()
+}
+
+// executeSearchRequest executes the given request against the Search Service, returning the response body.
+//
+// The Search Service may respond with a '400 Bad Request' whose body reports success (e.g. when re-creating an index
+// with an identical definition), these responses are treated as successful. Other failures are converted into a
+// 'SearchIndexNotFoundError' where possible.
+func (c *Client) executeSearchRequest(ctx context.Context, request *Request, name string) ([]byte, error) {
+	response, err := c.ExecuteWithContext(ctx, request)
+	if err == nil {
+		return response.Body, nil
+	}
+
+	var unexpected *UnexpectedStatusCodeError
+	if !errors.As(err, &unexpected) || unexpected.Status != http.StatusBadRequest {
+		return nil, err // Purposefully not wrapped
+	}
+
+	var decoded searchResponse
+
+	// Purposefully ignored, the error is returned as is if the body isn't in the expected format
+	_ = json.Unmarshal(unexpected.body, &decoded)
+
+	if strings.EqualFold(decoded.Status, "ok") {
+		return unexpected.body, nil
+	}
+
+	if isSearchIndexNotFound(decoded.Error) {
+		return nil, &SearchIndexNotFoundError{name: name}
+	}
+
+	return nil, err // Purposefully not wrapped
+}
+
+// isSearchIndexNotFound returns a boolean indicating whether the given error message, returned by the Search Service,
+// indicates that the index doesn't exist.
+func isSearchIndexNotFound(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "index not found") || strings.Contains(message, "no indexname")
+}
@@ -0,0 +1,173 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newSearchTestClient returns a client for a test cluster running the Search Service, using the given handlers.
+func newSearchTestClient(t *testing.T, handlers TestHandlers) *Client {
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Nodes:    TestNodes{{Services: []Service{ServiceSearch}}},
+		Handlers: handlers,
+	})
+	t.Cleanup(cluster.Close)
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func TestClientCreateSearchIndex(t *testing.T) {
+	var (
+		handlers = make(TestHandlers)
+		received SearchIndex
+	)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &received))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}
+
+	handlers.Add(http.MethodPut, string(EndpointSearchIndex.Format("index")), handler)
+
+	client := newSearchTestClient(t, handlers)
+
+	index := SearchIndex{
+		Name:       "index",
+		Type:       "fulltext-index",
+		SourceType: "gocbcore",
+		SourceName: "bucket",
+		Params:     json.RawMessage(`{"mapping":{"default_mapping":{"enabled":true}}}`),
+	}
+
+	err := client.CreateSearchIndex(context.Background(), index)
+	require.NoError(t, err)
+	require.Equal(t, index, received)
+}
+
+func TestClientCreateSearchIndexBadRequestWithOKBody(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(
+		http.MethodPut,
+		string(EndpointSearchIndex.Format("index")),
+		NewTestHandler(t, http.StatusBadRequest, []byte(`{"status":"ok"}`)),
+	)
+
+	client := newSearchTestClient(t, handlers)
+
+	err := client.CreateSearchIndex(context.Background(), SearchIndex{Name: "index"})
+	require.NoError(t, err)
+}
+
+func TestClientCreateSearchIndexBadRequest(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(
+		http.MethodPut,
+		string(EndpointSearchIndex.Format("index")),
+		NewTestHandler(t, http.StatusBadRequest, []byte(`{"status":"fail","error":"invalid mapping"}`)),
+	)
+
+	client := newSearchTestClient(t, handlers)
+
+	err := client.CreateSearchIndex(context.Background(), SearchIndex{Name: "index"})
+
+	var unexpected *UnexpectedStatusCodeError
+	require.ErrorAs(t, err, &unexpected)
+	require.Equal(t, http.StatusBadRequest, unexpected.Status)
+}
+
+func TestClientGetSearchIndex(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, string(EndpointSearchIndex.Format("index")), NewTestHandler(t, http.StatusOK, []byte(
+		`{"status":"ok","indexDef":{"name":"index","type":"fulltext-index","uuid":"uuid","sourceType":"gocbcore",`+
+			`"sourceName":"bucket","params":{}}}`,
+	)))
+
+	client := newSearchTestClient(t, handlers)
+
+	index, err := client.GetSearchIndex(context.Background(), "index")
+	require.NoError(t, err)
+
+	expected := &SearchIndex{
+		Name:       "index",
+		Type:       "fulltext-index",
+		UUID:       "uuid",
+		SourceType: "gocbcore",
+		SourceName: "bucket",
+		Params:     json.RawMessage(`{}`),
+	}
+
+	require.Equal(t, expected, index)
+}
+
+func TestClientGetSearchIndexNotFound(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, string(EndpointSearchIndex.Format("index")), NewTestHandler(
+		t,
+		http.StatusBadRequest,
+		[]byte(`{"status":"fail","error":"rest_index: GetIndex, no indexName: index"}`),
+	))
+
+	client := newSearchTestClient(t, handlers)
+
+	_, err := client.GetSearchIndex(context.Background(), "index")
+	require.True(t, IsSearchIndexNotFound(err))
+}
+
+func TestClientDeleteSearchIndex(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(
+		http.MethodDelete,
+		string(EndpointSearchIndex.Format("index")),
+		NewTestHandler(t, http.StatusOK, []byte(`{"status":"ok"}`)),
+	)
+
+	client := newSearchTestClient(t, handlers)
+
+	err := client.DeleteSearchIndex(context.Background(), "index")
+	require.NoError(t, err)
+}
+
+func TestClientWaitForSearchIndexBuild(t *testing.T) {
+	var (
+		handlers  = make(TestHandlers)
+		responses = []string{
+			`{"doc_count":10,"tot_seq_received":20,"num_mutations_to_index":10}`,
+			`{"doc_count":20,"tot_seq_received":20,"num_mutations_to_index":0}`,
+		}
+		requests int
+	)
+
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(responses[min(requests, len(responses)-1)]))
+
+		requests++
+	}
+
+	handlers.Add(http.MethodGet, string(EndpointSearchIndexProgress.Format("index")), handler)
+
+	client := newSearchTestClient(t, handlers)
+
+	err := client.WaitForSearchIndexBuild(context.Background(), "index")
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+}
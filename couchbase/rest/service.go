@@ -1,6 +1,7 @@
 package rest
 
-// Service represents a service which can be running on a Couchbase node.
+// Service represents a service which can be running on a Couchbase node, requests are routed to the port for the service
+// given in the 'ports' extension of the cluster config (see 'Services.GetPort').
 type Service string
 
 const (
@@ -13,10 +14,11 @@ const (
 	// ServiceData represents the KV/Data Service.
 	ServiceData Service = "Data"
 
-	// ServiceEventing represents the cluster level Eventing Service.
+	// ServiceEventing represents the cluster level Eventing Service, requests are sent to its admin REST API e.g. port
+	// 8096/18096.
 	ServiceEventing Service = "Eventing"
 
-	// ServiceGSI represents the Indexing Service.
+	// ServiceGSI represents the Indexing Service, requests are sent to its HTTP API e.g. port 9102/19102.
 	ServiceGSI Service = "Indexing"
 
 	// ServiceQuery represents the Query Service e.g. nodes running N1QL.
@@ -28,6 +30,6 @@ const (
 	// ServiceViews represents hosts accepting requests for Views.
 	ServiceViews Service = "Views"
 
-	// ServiceBackup represents hosts accepting requests for the Backup Service.
+	// ServiceBackup represents hosts accepting requests for the Backup Service REST API e.g. port 8097/18097.
 	ServiceBackup Service = "Backup"
 )
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"maps"
+	"time"
+)
+
+// ServiceDefaults are the values used for requests dispatched to a service, where they've not been set on the request.
+type ServiceDefaults struct {
+	// Timeout is used when the request doesn't have a 'Timeout' e.g. a longer timeout for the Analytics Service.
+	Timeout time.Duration
+
+	// ContentType is used when the request doesn't have a 'ContentType' e.g. JSON for the Query Service.
+	ContentType ContentType
+}
+
+// serviceDefaults maps services to the defaults for requests dispatched to them.
+type serviceDefaults map[Service]ServiceDefaults
+
+// apply returns the given request with any defaults for its service applied; the request is copied, rather than being
+// modified, where defaults are applied.
+func (s serviceDefaults) apply(request *Request) *Request {
+	defaults, ok := s[request.Service]
+	if !ok {
+		return request
+	}
+
+	var (
+		timeout     = request.Timeout == 0 && defaults.Timeout != 0
+		contentType = request.ContentType == "" && defaults.ContentType != ""
+	)
+
+	if !timeout && !contentType {
+		return request
+	}
+
+	cp := *request
+
+	if timeout {
+		cp.Timeout = defaults.Timeout
+	}
+
+	if contentType {
+		cp.ContentType = defaults.ContentType
+	}
+
+	return &cp
+}
+
+// newServiceDefaults returns a copy of the given service defaults, so that they can't be modified once the client has
+// been created.
+func newServiceDefaults(defaults map[Service]ServiceDefaults) serviceDefaults {
+	return maps.Clone(defaults)
+}
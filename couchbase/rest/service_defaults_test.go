@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceDefaultsApply(t *testing.T) {
+	defaults := serviceDefaults{
+		ServiceAnalytics: {Timeout: time.Minute},
+		ServiceQuery:     {Timeout: 2 * time.Minute, ContentType: ContentTypeJSON},
+	}
+
+	type test struct {
+		name     string
+		request  *Request
+		expected *Request
+		copied   bool
+	}
+
+	tests := []*test{
+		{
+			name:     "NoDefaultsForService",
+			request:  &Request{Service: ServiceManagement},
+			expected: &Request{Service: ServiceManagement},
+		},
+		{
+			name:     "TimeoutOnly",
+			request:  &Request{Service: ServiceAnalytics, ContentType: ContentTypeURLEncoded},
+			expected: &Request{Service: ServiceAnalytics, ContentType: ContentTypeURLEncoded, Timeout: time.Minute},
+			copied:   true,
+		},
+		{
+			name:     "Both",
+			request:  &Request{Service: ServiceQuery},
+			expected: &Request{Service: ServiceQuery, ContentType: ContentTypeJSON, Timeout: 2 * time.Minute},
+			copied:   true,
+		},
+		{
+			name:     "RequestTakesPrecedence",
+			request:  &Request{Service: ServiceQuery, ContentType: ContentTypeURLEncoded, Timeout: time.Second},
+			expected: &Request{Service: ServiceQuery, ContentType: ContentTypeURLEncoded, Timeout: time.Second},
+		},
+		{
+			name:     "StreamingTimeoutNotOverridden",
+			request:  &Request{Service: ServiceAnalytics, Timeout: -1},
+			expected: &Request{Service: ServiceAnalytics, Timeout: -1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := *test.request
+
+			actual := defaults.apply(test.request)
+			require.Equal(t, test.expected, actual)
+
+			// The given request must never be modified
+			require.Equal(t, original, *test.request)
+
+			if !test.copied {
+				require.Same(t, test.request, actual)
+			}
+		})
+	}
+}
+
+func TestClientExecuteWithServiceDefaults(t *testing.T) {
+	var contentType string
+
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		contentType = request.Header.Get("Content-Type")
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		ServiceDefaults:  map[Service]ServiceDefaults{ServiceManagement: {ContentType: ContentTypeJSON}},
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.Execute(&Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(ContentTypeJSON), contentType)
+}
@@ -0,0 +1,170 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	aprov "github.com/couchbase/tools-common/auth/v2/provider"
+)
+
+// AuthMode represents the mechanism used to authenticate requests dispatched to the cluster.
+type AuthMode int
+
+const (
+	// AuthModeBasic authenticates every request using HTTP basic auth.
+	AuthModeBasic AuthMode = iota
+
+	// AuthModeSession authenticates requests using a session cookie, obtained by logging in using the '/uilogin'
+	// endpoint (in the same way as the UI). Sessions are created per-host, and are transparently re-created when they
+	// expire.
+	//
+	// NOTE: Bootstrapping and cluster config polling always use HTTP basic auth.
+	AuthModeSession
+)
+
+// sessionHeader is the header which must accompany requests authenticated using a session cookie.
+const sessionHeader = "ns-server-ui"
+
+// sessionSigner implements the 'Signer' interface, authenticating requests using a session cookie obtained by logging
+// into the host the request is being dispatched to.
+type sessionSigner struct {
+	client     *http.Client
+	userAgent  func() string
+	pathPrefix string
+
+	lock     sync.Mutex
+	sessions map[string][]*http.Cookie
+}
+
+var _ Signer = (*sessionSigner)(nil)
+
+// newSessionSigner returns a new session signer which will login using the given client, the path prefix is prepended
+// to the login endpoint.
+func newSessionSigner(client *http.Client, userAgent func() string, pathPrefix string) *sessionSigner {
+	return &sessionSigner{
+		client:     client,
+		userAgent:  userAgent,
+		pathPrefix: pathPrefix,
+		sessions:   make(map[string][]*http.Cookie),
+	}
+}
+
+func (s *sessionSigner) Sign(req *http.Request, _ []byte, credentials aprov.Credentials) error {
+	cookies, err := s.session(req.Context(), sessionKey(req.URL), credentials)
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	req.Header.Set(sessionHeader, "yes")
+
+	return nil
+}
+
+// session returns the session cookies for the given host, logging in if there's no existing session.
+func (s *sessionSigner) session(
+	ctx context.Context,
+	host string,
+	credentials aprov.Credentials,
+) ([]*http.Cookie, error) {
+	// Logins are serialized so that concurrent requests to a host without a session don't each create their own
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	cookies, ok := s.sessions[host]
+	if ok {
+		return cookies, nil
+	}
+
+	cookies, err := s.login(ctx, host, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sessions[host] = cookies
+
+	return cookies, nil
+}
+
+// login creates a new session for the given host.
+func (s *sessionSigner) login(ctx context.Context, host string, credentials aprov.Credentials) ([]*http.Cookie, error) {
+	values := make(url.Values)
+	values.Set("user", credentials.Username)
+	values.Set("password", credentials.Password)
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		host+s.pathPrefix+string(EndpointUILogin),
+		strings.NewReader(values.Encode()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", string(ContentTypeURLEncoded))
+	req.Header.Set("User-Agent", s.userAgent())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, handleRequestError(req, err) // Purposefully not wrapped
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusUnauthorized:
+		return nil, &AuthenticationError{
+			responseDetails: newResponseDetails(resp),
+			method:          http.MethodPost,
+			endpoint:        EndpointUILogin,
+		}
+	default:
+		return nil, handleResponseError(http.MethodPost, EndpointUILogin, resp, body)
+	}
+
+	cookies := resp.Cookies()
+	if len(cookies) == 0 {
+		return nil, ErrNoSessionCookie
+	}
+
+	return cookies, nil
+}
+
+// expire removes the session used to authenticate the given request, returning a boolean indicating whether the
+// request was authenticated using a session (and should therefore be retried using a new session).
+func (s *sessionSigner) expire(req *http.Request) bool {
+	if req == nil || req.Header.Get(sessionHeader) == "" {
+		return false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	host := sessionKey(req.URL)
+
+	// Only remove the session if it's the one which was used, it may have already been replaced by another request
+	for _, cookie := range s.sessions[host] {
+		if used, err := req.Cookie(cookie.Name); err == nil && used.Value == cookie.Value {
+			delete(s.sessions, host)
+			break
+		}
+	}
+
+	return true
+}
+
+// sessionKey returns the key used to store the session for the host of the given URL.
+func sessionKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
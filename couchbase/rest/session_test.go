@@ -0,0 +1,166 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newSessionTestClient returns a client, using session auth, for a cluster where '/test' only accepts the most recent
+// session; sessions may be expired by incrementing the returned counter.
+func newSessionTestClient(t *testing.T) (*Client, *atomic.Int64, *atomic.Int64) {
+	var (
+		handlers = make(TestHandlers)
+		logins   = &atomic.Int64{}
+		session  = &atomic.Int64{}
+	)
+
+	handlers.Add(http.MethodPost, string(EndpointUILogin), func(writer http.ResponseWriter, request *http.Request) {
+		require.NoError(t, request.ParseForm())
+
+		if request.PostForm.Get("user") != "username" || request.PostForm.Get("password") != "password" {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		logins.Add(1)
+
+		http.SetCookie(writer, &http.Cookie{Name: "ui-auth", Value: fmt.Sprint(session.Load())})
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		cookie, err := request.Cookie("ui-auth")
+
+		if err != nil || cookie.Value != fmt.Sprint(session.Load()) || request.Header.Get("ns-server-ui") != "yes" {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		// Basic auth credentials shouldn't be sent when using a session
+		_, _, ok := request.BasicAuth()
+		require.False(t, ok)
+
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	t.Cleanup(cluster.Close)
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		AuthMode:         AuthModeSession,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(client.Close)
+
+	return client, logins, session
+}
+
+func TestClientExecuteWithSession(t *testing.T) {
+	client, logins, _ := newSessionTestClient(t)
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	for range 3 {
+		_, err := client.ExecuteWithContext(context.Background(), request)
+		require.NoError(t, err)
+	}
+
+	// The session should be reused
+	require.Equal(t, int64(1), logins.Load())
+}
+
+func TestClientExecuteWithExpiredSession(t *testing.T) {
+	client, logins, session := newSessionTestClient(t)
+
+	request := &Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+		// Requests rejected because the session has expired should be retried regardless
+		NoRetryOnStatusCodes: []int{http.StatusUnauthorized},
+	}
+
+	_, err := client.ExecuteWithContext(context.Background(), request)
+	require.NoError(t, err)
+
+	session.Add(1)
+
+	_, err = client.ExecuteWithContext(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), logins.Load())
+}
+
+func TestNewClientWithSessionInvalidCredentials(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodPost, string(EndpointUILogin), NewTestHandler(t, http.StatusBadRequest, nil))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	_, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		AuthMode:         AuthModeSession,
+	})
+
+	var errAuthentication *AuthenticationError
+
+	require.ErrorAs(t, err, &errAuthentication)
+}
+
+func TestClientExecuteWithSessionPathPrefix(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodPost, string(EndpointUILogin), func(writer http.ResponseWriter, _ *http.Request) {
+		http.SetCookie(writer, &http.Cookie{Name: "ui-auth", Value: "session"})
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		_, err := request.Cookie("ui-auth")
+		if err != nil {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers, PathPrefix: "/couchbase"})
+	defer cluster.Close()
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		AuthMode:         AuthModeSession,
+		PathPrefix:       "/couchbase",
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.Execute(&Request{
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	})
+	require.NoError(t, err)
+}
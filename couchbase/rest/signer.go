@@ -0,0 +1,190 @@
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	aprov "github.com/couchbase/tools-common/auth/v2/provider"
+)
+
+// Signer is used to authenticate requests using a scheme other than HTTP basic auth, for example, when communicating
+// with a cloud control plane rather than ns_server.
+type Signer interface {
+	// Sign authenticates the given request, which will have all other headers populated, using the given credentials.
+	Sign(req *http.Request, body []byte, credentials aprov.Credentials) error
+}
+
+// SignerForHost returns the signer which should be used to authenticate requests to the given host, a <nil> return
+// value indicates that HTTP basic auth should be used.
+type SignerForHost func(host string) Signer
+
+// SigV4Signer implements the 'Signer' interface and signs requests using AWS Signature Version 4.
+//
+// NOTE: The username/password from the credentials are used as the access key id/secret access key respectively.
+type SigV4Signer struct {
+	// Region is the region in which the service being communicated with resides.
+	Region string
+
+	// Service is the name of the service being communicated with.
+	Service string
+
+	// SessionToken is an optional session token, required when using temporary credentials.
+	SessionToken string
+
+	// Clock is used to determine the time at which requests are signed, defaults to the system clock.
+	Clock Clock
+}
+
+var _ Signer = (*SigV4Signer)(nil)
+
+func (s *SigV4Signer) Sign(req *http.Request, body []byte, credentials aprov.Credentials) error {
+	var (
+		timestamp = clockOrDefault(s.Clock).Now().UTC()
+		date      = timestamp.Format("20060102")
+		scope     = strings.Join([]string{date, s.Region, s.Service, "aws4_request"}, "/")
+	)
+
+	req.Header.Set("X-Amz-Date", timestamp.Format("20060102T150405Z"))
+
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	headers, signed := sigV4CanonicalHeaders(req)
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonical := strings.Join([]string{
+		req.Method,
+		path,
+		sigV4CanonicalQuery(req.URL.Query()),
+		headers,
+		signed,
+		hex.EncodeToString(sha256Sum(body)),
+	}, "\n")
+
+	toSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		req.Header.Get("X-Amz-Date"),
+		scope,
+		hex.EncodeToString(sha256Sum([]byte(canonical))),
+	}, "\n")
+
+	key := []byte("AWS4" + credentials.Password)
+
+	for _, part := range []string{date, s.Region, s.Service, "aws4_request"} {
+		key = hmacSHA256(key, []byte(part))
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.Username, scope, signed, hex.EncodeToString(hmacSHA256(key, []byte(toSign)))))
+
+	return nil
+}
+
+// sigV4CanonicalHeaders returns the canonical headers, and the list of signed headers for the given request.
+//
+// NOTE: Only the 'Host', 'Content-Type' and 'X-Amz-*' headers are signed, other headers may be modified by proxies.
+func sigV4CanonicalHeaders(req *http.Request) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	values := map[string]string{"host": host}
+
+	for key, value := range req.Header {
+		lower := strings.ToLower(key)
+
+		if lower != "content-type" && !strings.HasPrefix(lower, "x-amz-") {
+			continue
+		}
+
+		values[lower] = strings.TrimSpace(strings.Join(value, ","))
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonical strings.Builder
+
+	for _, name := range names {
+		canonical.WriteString(name + ":" + values[name] + "\n")
+	}
+
+	return canonical.String(), strings.Join(names, ";")
+}
+
+// sigV4CanonicalQuery returns the given query parameters sorted and encoded as required by SigV4.
+func sigV4CanonicalQuery(query url.Values) string {
+	escape := func(s string) string {
+		return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+	}
+
+	pairs := make([]string, 0, len(query))
+
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, escape(key)+"="+escape(value))
+		}
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, "&")
+}
+
+// CapellaHMACSigner implements the 'Signer' interface and signs requests using the HMAC scheme supported by the
+// Capella public API.
+//
+// NOTE: The username/password from the credentials are used as the access/secret key respectively.
+type CapellaHMACSigner struct {
+	// Clock is used to determine the time at which requests are signed, defaults to the system clock.
+	Clock Clock
+}
+
+var _ Signer = (*CapellaHMACSigner)(nil)
+
+func (c *CapellaHMACSigner) Sign(req *http.Request, _ []byte, credentials aprov.Credentials) error {
+	timestamp := strconv.FormatInt(clockOrDefault(c.Clock).Now().UnixMilli(), 10)
+
+	signature := hmacSHA256(
+		[]byte(credentials.Password),
+		[]byte(strings.Join([]string{req.Method, req.URL.RequestURI(), timestamp}, "\n")),
+	)
+
+	req.Header.Set("Couchbase-Timestamp", timestamp)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s:%s", credentials.Username,
+		base64.StdEncoding.EncodeToString(signature)))
+
+	return nil
+}
+
+// sha256Sum returns the SHA256 checksum of the given data.
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the given data using the given key.
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(data)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,132 @@
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	aprov "github.com/couchbase/tools-common/auth/v2/provider"
+)
+
+func TestSigV4SignerSign(t *testing.T) {
+	// Test vector 'get-vanilla' from the AWS SigV4 test suite
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+
+	signer := &SigV4Signer{
+		Region:  "us-east-1",
+		Service: "service",
+		Clock:   &testClock{now: time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)},
+	}
+
+	credentials := aprov.Credentials{
+		Username: "AKIDEXAMPLE",
+		Password: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+
+	require.NoError(t, signer.Sign(req, nil, credentials))
+
+	require.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	require.Equal(
+		t,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+			"SignedHeaders=host;x-amz-date, "+
+			"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"),
+	)
+}
+
+func TestSigV4SignerSignSessionToken(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+
+	signer := &SigV4Signer{Region: "us-east-1", Service: "service", SessionToken: "token"}
+
+	require.NoError(t, signer.Sign(req, nil, aprov.Credentials{Username: "key", Password: "secret"}))
+	require.Equal(t, "token", req.Header.Get("X-Amz-Security-Token"))
+	require.Contains(t, req.Header.Get("Authorization"), "SignedHeaders=host;x-amz-date;x-amz-security-token")
+}
+
+func TestSigV4CanonicalQuery(t *testing.T) {
+	query := map[string][]string{
+		"b":     {"2", "1"},
+		"a":     {"hello world"},
+		"tilde": {"~"},
+	}
+
+	require.Equal(t, "a=hello%20world&b=1&b=2&tilde=~", sigV4CanonicalQuery(query))
+}
+
+func TestCapellaHMACSignerSign(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://cloudapi.cloud.couchbase.com/v3/clusters?page=1", nil)
+	require.NoError(t, err)
+
+	signer := &CapellaHMACSigner{Clock: &testClock{now: time.UnixMilli(1600000000000)}}
+
+	require.NoError(t, signer.Sign(req, nil, aprov.Credentials{Username: "access", Password: "secret"}))
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, _ = mac.Write([]byte("GET\n/v3/clusters?page=1\n1600000000000"))
+
+	require.Equal(t, "1600000000000", req.Header.Get("Couchbase-Timestamp"))
+	require.Equal(t, "Bearer access:"+base64.StdEncoding.EncodeToString(mac.Sum(nil)), req.Header.Get("Authorization"))
+}
+
+func TestClientExecuteWithSigner(t *testing.T) {
+	handlers := make(TestHandlers)
+	handlers.Add(http.MethodGet, "/test", func(writer http.ResponseWriter, request *http.Request) {
+		if !strings.HasPrefix(request.Header.Get("Authorization"), "Bearer username:") {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Handlers: handlers,
+	})
+	defer cluster.Close()
+
+	pool := x509.NewCertPool()
+
+	if cluster.Certificate() != nil {
+		pool.AddCert(cluster.Certificate())
+	}
+
+	var hosts []string
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		TLSConfig:        &tls.Config{RootCAs: pool},
+		SignerForHost: func(host string) Signer {
+			hosts = append(hosts, host)
+			return &CapellaHMACSigner{}
+		},
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           "/test",
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	_, err = client.Execute(request)
+	require.NoError(t, err)
+	require.NotEmpty(t, hosts)
+}
@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// readResponseBody returns the entire response body, honoring the 'MaxResponseBytes' limit of the given request.
+func readResponseBody(request *Request, resp *http.Response) ([]byte, error) {
+	reader, err := limitResponseBody(request, resp)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	body, err := readBody(request.Method, request.Endpoint, reader, resp.ContentLength)
+	if errors.Is(err, errBodyTooLarge) {
+		return nil, newResponseTooLargeError(request)
+	}
+
+	return body, err
+}
+
+// spillResponseBody returns the entire response body, honoring the 'MaxResponseBytes' limit of the given request;
+// bodies larger than the 'SpillThreshold' of the request are written to a temporary file in the given directory, which
+// is returned instead of the body.
+func spillResponseBody(dir string, request *Request, resp *http.Response) ([]byte, *os.File, error) {
+	reader, err := limitResponseBody(request, resp)
+	if err != nil {
+		return nil, nil, err // Purposefully not wrapped
+	}
+
+	// Read up to one byte past the threshold, to determine whether the body needs to be spilled
+	body, err := readBody(request.Method, request.Endpoint, io.LimitReader(reader, request.SpillThreshold+1),
+		resp.ContentLength)
+	if errors.Is(err, errBodyTooLarge) {
+		return nil, nil, newResponseTooLargeError(request)
+	}
+
+	if err != nil || int64(len(body)) <= request.SpillThreshold {
+		return body, nil, err
+	}
+
+	file, err := os.CreateTemp(dir, "rest-response-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	err = spill(file, request, resp, io.MultiReader(bytes.NewReader(body), reader))
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+
+		return nil, nil, err
+	}
+
+	return nil, file, nil
+}
+
+// spill writes the given body to the given file, returning an informative error in the case where the response body
+// is less than the expected length, or exceeds the limit of the request.
+func spill(file *os.File, request *Request, resp *http.Response, body io.Reader) error {
+	n, err := io.Copy(file, body)
+
+	if errors.Is(err, errBodyTooLarge) {
+		return newResponseTooLargeError(request)
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return &UnexpectedEndOfBodyError{
+			method:   request.Method,
+			endpoint: request.Endpoint,
+			expected: resp.ContentLength,
+			got:      int(n),
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write body to temporary file: %w", err)
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to seek to start of temporary file: %w", err)
+	}
+
+	return nil
+}
+
+// limitResponseBody returns a reader for the response body which honors the 'MaxResponseBytes' limit of the given
+// request, failing fast where we know upfront that the body is too large.
+func limitResponseBody(request *Request, resp *http.Response) (io.Reader, error) {
+	if request.MaxResponseBytes <= 0 {
+		return resp.Body, nil
+	}
+
+	if resp.ContentLength > request.MaxResponseBytes {
+		return nil, newResponseTooLargeError(request)
+	}
+
+	return &limitedReader{r: resp.Body, remaining: request.MaxResponseBytes}, nil
+}
+
+// newResponseTooLargeError returns an error indicating that the response body for the given request exceeded its
+// 'MaxResponseBytes' limit.
+func newResponseTooLargeError(request *Request) error {
+	return &BodyTooLargeError{method: request.Method, endpoint: request.Endpoint, limit: request.MaxResponseBytes}
+}
@@ -0,0 +1,180 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientExecuteMaxResponseBytes(t *testing.T) {
+	client, cluster := newDecodeTestClient(t, http.StatusOK, "body")
+	defer cluster.Close()
+	defer client.Close()
+
+	request := newDecodeTestRequest()
+	request.MaxResponseBytes = 4
+
+	response, err := client.Execute(request)
+	require.NoError(t, err)
+	require.Equal(t, []byte("body"), response.Body)
+
+	request.MaxResponseBytes = 3
+
+	_, err = client.Execute(request)
+
+	var tooLarge *BodyTooLargeError
+
+	require.ErrorAs(t, err, &tooLarge)
+	require.Equal(t, int64(3), tooLarge.limit)
+}
+
+func TestClientExecuteSpillThreshold(t *testing.T) {
+	client, cluster := newDecodeTestClient(t, http.StatusOK, "body")
+	defer cluster.Close()
+	defer client.Close()
+
+	client.spillDirectory = t.TempDir()
+
+	request := newDecodeTestRequest()
+	request.SpillThreshold = 4
+
+	response, err := client.Execute(request)
+	require.NoError(t, err)
+	require.False(t, response.Spilled())
+	require.Equal(t, []byte("body"), response.Body)
+	require.NoError(t, response.Close())
+
+	request.SpillThreshold = 3
+
+	response, err = client.Execute(request)
+	require.NoError(t, err)
+	require.True(t, response.Spilled())
+	require.Nil(t, response.Body)
+
+	entries, err := os.ReadDir(client.spillDirectory)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	data, err := io.ReadAll(response.Reader())
+	require.NoError(t, err)
+	require.Equal(t, "body", string(data))
+
+	require.NoError(t, response.Close())
+	require.NoError(t, response.Close())
+
+	entries, err = os.ReadDir(client.spillDirectory)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestClientExecuteSpillThresholdUnexpectedStatusCode(t *testing.T) {
+	client, cluster := newDecodeTestClient(t, http.StatusNotFound, "body")
+	defer cluster.Close()
+	defer client.Close()
+
+	client.spillDirectory = t.TempDir()
+
+	request := newDecodeTestRequest()
+	request.SpillThreshold = 1
+
+	response, err := client.Execute(request)
+	require.Error(t, err)
+	require.False(t, response.Spilled())
+	require.Equal(t, []byte("body"), response.Body)
+}
+
+func TestSpillResponseBody(t *testing.T) {
+	type test struct {
+		name      string
+		body      string
+		threshold int64
+		limit     int64
+		spilled   bool
+		tooLarge  bool
+	}
+
+	tests := []*test{
+		{
+			name:      "BelowThreshold",
+			body:      "body",
+			threshold: 4,
+		},
+		{
+			name:      "AboveThreshold",
+			body:      "body",
+			threshold: 2,
+			spilled:   true,
+		},
+		{
+			name:      "AboveThresholdWithinLimit",
+			body:      "body",
+			threshold: 2,
+			limit:     4,
+			spilled:   true,
+		},
+		{
+			name:      "BelowThresholdExceedsLimit",
+			body:      "body",
+			threshold: 8,
+			limit:     2,
+			tooLarge:  true,
+		},
+		{
+			name:      "AboveThresholdExceedsLimit",
+			body:      "body",
+			threshold: 1,
+			limit:     3,
+			tooLarge:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				dir     = t.TempDir()
+				request = &Request{SpillThreshold: test.threshold, MaxResponseBytes: test.limit}
+				// An unknown content length means the limit is only detected whilst reading the body
+				resp = &http.Response{Body: io.NopCloser(strings.NewReader(test.body)), ContentLength: -1}
+			)
+
+			body, file, err := spillResponseBody(dir, request, resp)
+
+			entries, readErr := os.ReadDir(dir)
+			require.NoError(t, readErr)
+
+			if test.tooLarge {
+				var tooLarge *BodyTooLargeError
+
+				require.ErrorAs(t, err, &tooLarge)
+				require.Empty(t, entries)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if !test.spilled {
+				require.Nil(t, file)
+				require.Equal(t, test.body, string(body))
+				require.Empty(t, entries)
+
+				return
+			}
+
+			require.Nil(t, body)
+			require.NotNil(t, file)
+			require.Len(t, entries, 1)
+
+			response := &Response{file: file}
+
+			data, err := io.ReadAll(response.Reader())
+			require.NoError(t, err)
+			require.Equal(t, test.body, string(data))
+			require.NoError(t, response.Close())
+		})
+	}
+}
@@ -37,6 +37,10 @@ type TestClusterOptions struct {
 
 	// A non-nil TLS config indicates that the cluster should use TLS
 	TLSConfig *tls.Config
+
+	// PathPrefix is stripped from the path of each request before it's handled, simulating a cluster exposed behind a
+	// reverse proxy; requests without the prefix receive a 404.
+	PathPrefix string
 }
 
 // TestCluster is a mock Couchbase cluster used for unit testing functionaility which relies on the REST client.
@@ -88,12 +92,18 @@ func NewTestCluster(t *testing.T, options TestClusterOptions) *TestCluster {
 		def(http.MethodGet, EndpointBucketManifest.Format(name), cluster.BucketManifest(name))
 	}
 
+	var handler http.Handler = http.HandlerFunc(cluster.Handler)
+
+	if options.PathPrefix != "" {
+		handler = http.StripPrefix(options.PathPrefix, handler)
+	}
+
 	if options.TLSConfig != nil {
-		cluster.server = httptest.NewUnstartedServer(http.HandlerFunc(cluster.Handler))
+		cluster.server = httptest.NewUnstartedServer(handler)
 		cluster.server.TLS = options.TLSConfig
 		cluster.server.StartTLS()
 	} else {
-		cluster.server = httptest.NewServer(http.HandlerFunc(cluster.Handler))
+		cluster.server = httptest.NewServer(handler)
 	}
 
 	return cluster
@@ -254,6 +264,7 @@ func (t *TestCluster) createNode(n *TestNode) *Node {
 	port := t.Port()
 
 	node := &Node{
+		UUID:     n.UUID,
 		Hostname: t.Address(),
 		Services: &Services{
 			Management: port,
@@ -289,7 +300,7 @@ func (t *TestCluster) Close() {
 func createNodeList(nodes []*TestNode) []node {
 	list := make([]node, 0, len(nodes))
 	for _, n := range nodes {
-		list = append(list, node{Version: n.Version, Status: n.Status})
+		list = append(list, node{UUID: n.UUID, Version: n.Version, Status: n.Status, Membership: n.Membership})
 	}
 
 	return list
@@ -4,8 +4,10 @@ import cbvalue "github.com/couchbase/tools-common/couchbase/v3/value"
 
 // node is the structure used when marshalling basic node information.
 type node struct {
-	Version cbvalue.Version `json:"version"`
-	Status  string          `json:"status"`
+	UUID       string          `json:"nodeUUID,omitempty"`
+	Version    cbvalue.Version `json:"version"`
+	Status     string          `json:"status"`
+	Membership string          `json:"clusterMembership,omitempty"`
 }
 
 // vbsm represents the vBucketServerMap and is currently only used to indicate the number of vBuckets a bucket has.
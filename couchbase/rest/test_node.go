@@ -7,8 +7,10 @@ type TestNodes []*TestNode
 
 // TestNode encapsulates the options which can be used to configure a single node in a test cluster.
 type TestNode struct {
+	UUID       string
 	Version    cbvalue.Version
 	Status     string
+	Membership string
 	Services   []Service
 	SSL        bool
 	AltAddress bool
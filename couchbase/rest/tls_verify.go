@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrCertificateNotPinned is returned when none of the certificates presented by the cluster have a public key which
+// matches one of the pinned SPKI hashes.
+var ErrCertificateNotPinned = errors.New("x509: none of the certificates presented match a pinned public key")
+
+// VerifyPeerCertificateFunc is a custom verification function, with the same signature as
+// 'tls.Config.VerifyPeerCertificate'.
+type VerifyPeerCertificateFunc func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+
+// TLSVerifyOptions encapsulates the options available to replace the standard verification of the certificates
+// presented by the cluster, which requires distributing the CA certificate(s) which signed them.
+//
+// NOTE: When used, the standard verification (including the hostname check) is disabled, so the peer certificates are
+// only verified as configured here; 'verifiedChains' will always be empty.
+type TLSVerifyOptions struct {
+	// SPKIHashes are the base64 encoded SHA-256 hashes of the Subject Public Key Info of the certificates which are
+	// trusted, the connection is accepted when any certificate in the chain presented by the cluster matches.
+	//
+	// The hash may be generated using:
+	//   openssl x509 -in cert.pem -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | \
+	//     openssl enc -base64
+	SPKIHashes []string
+
+	// VerifyPeerCertificate is called for each connection, after any SPKI hashes have been checked, the connection is
+	// rejected if an error is returned.
+	//
+	// NOTE: Errors of type 'x509.UnknownAuthorityError' are reported as an 'UnknownAuthorityError'.
+	VerifyPeerCertificate VerifyPeerCertificateFunc
+}
+
+// newTLSConfig returns a copy of the given config, which verifies peer certificates using the given options; the
+// config is returned as is where no custom verification is required.
+func newTLSConfig(config *tls.Config, options *TLSVerifyOptions) (*tls.Config, error) {
+	if options == nil || (len(options.SPKIHashes) == 0 && options.VerifyPeerCertificate == nil) {
+		return config, nil
+	}
+
+	pins := make(map[[sha256.Size]byte]struct{}, len(options.SPKIHashes))
+
+	for _, encoded := range options.SPKIHashes {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("invalid SPKI hash '%s', expected a base64 encoded SHA-256 hash", encoded)
+		}
+
+		pins[[sha256.Size]byte(decoded)] = struct{}{}
+	}
+
+	if config == nil {
+		config = &tls.Config{MinVersion: tls.VersionTLS12}
+	} else {
+		config = config.Clone()
+	}
+
+	// The standard verification is replaced, rather than skipped, by the 'VerifyPeerCertificate' function below
+	config.InsecureSkipVerify = true //nolint:gosec
+
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if len(pins) != 0 {
+			err := verifyPinned(rawCerts, pins)
+			if err != nil {
+				return err
+			}
+		}
+
+		if options.VerifyPeerCertificate == nil {
+			return nil
+		}
+
+		return options.VerifyPeerCertificate(rawCerts, verifiedChains)
+	}
+
+	return config, nil
+}
+
+// verifyPinned returns an error if none of the given certificates have a public key matching one of the given pins.
+func verifyPinned(rawCerts [][]byte, pins map[[sha256.Size]byte]struct{}) error {
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		if _, ok := pins[sha256.Sum256(cert.RawSubjectPublicKeyInfo)]; ok {
+			return nil
+		}
+	}
+
+	return ErrCertificateNotPinned
+}
@@ -0,0 +1,120 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spkiHash returns the base64 encoded SPKI hash of the given certificate.
+func spkiHash(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func TestNewTLSConfigNoVerify(t *testing.T) {
+	config := &tls.Config{MinVersion: tls.VersionTLS13}
+
+	actual, err := newTLSConfig(config, nil)
+	require.NoError(t, err)
+	require.Same(t, config, actual)
+
+	actual, err = newTLSConfig(config, &TLSVerifyOptions{})
+	require.NoError(t, err)
+	require.Same(t, config, actual)
+}
+
+func TestNewTLSConfigInvalidSPKIHash(t *testing.T) {
+	_, err := newTLSConfig(nil, &TLSVerifyOptions{SPKIHashes: []string{"not-base64"}})
+	require.Error(t, err)
+
+	_, err = newTLSConfig(nil, &TLSVerifyOptions{SPKIHashes: []string{base64.StdEncoding.EncodeToString([]byte("a"))}})
+	require.Error(t, err)
+}
+
+func TestNewTLSConfigDoesNotModifyGiven(t *testing.T) {
+	config := &tls.Config{MinVersion: tls.VersionTLS13}
+
+	actual, err := newTLSConfig(config, &TLSVerifyOptions{SPKIHashes: []string{spkiHash(&x509.Certificate{})}})
+	require.NoError(t, err)
+	require.NotSame(t, config, actual)
+	require.False(t, config.InsecureSkipVerify)
+	require.Nil(t, config.VerifyPeerCertificate)
+	require.Equal(t, uint16(tls.VersionTLS13), actual.MinVersion)
+}
+
+func TestNewClientTLSVerifyPinned(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Nodes:     TestNodes{{SSL: true}},
+		TLSConfig: &tls.Config{},
+	})
+	defer cluster.Close()
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		TLSVerify:        &TLSVerifyOptions{SPKIHashes: []string{spkiHash(cluster.Certificate())}},
+	})
+	require.NoError(t, err)
+
+	client.Close()
+}
+
+func TestNewClientTLSVerifyNotPinned(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Nodes:     TestNodes{{SSL: true}},
+		TLSConfig: &tls.Config{},
+	})
+	defer cluster.Close()
+
+	other := sha256.Sum256([]byte("other"))
+
+	_, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		TLSVerify:        &TLSVerifyOptions{SPKIHashes: []string{base64.StdEncoding.EncodeToString(other[:])}},
+	})
+
+	var unknownAuthority *UnknownAuthorityError
+
+	require.ErrorAs(t, err, &unknownAuthority)
+}
+
+func TestNewClientTLSVerifyCallback(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{
+		Nodes:     TestNodes{{SSL: true}},
+		TLSConfig: &tls.Config{},
+	})
+	defer cluster.Close()
+
+	var called atomic.Bool
+
+	verify := func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		called.Store(true)
+
+		if len(rawCerts) == 0 {
+			return assert.AnError
+		}
+
+		return nil
+	}
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		TLSVerify:        &TLSVerifyOptions{VerifyPeerCertificate: verify},
+	})
+	require.NoError(t, err)
+	require.True(t, called.Load())
+
+	client.Close()
+}
@@ -0,0 +1,170 @@
+package rest
+
+import (
+	"context"
+	"sync"
+)
+
+// TopologyEventType represents a change in the topology of the cluster.
+type TopologyEventType string
+
+const (
+	// TopologyEventNodeAdded indicates that a node has been added to the cluster.
+	TopologyEventNodeAdded TopologyEventType = "node_added"
+
+	// TopologyEventNodeRemoved indicates that a node has been removed from the cluster.
+	TopologyEventNodeRemoved TopologyEventType = "node_removed"
+
+	// TopologyEventServicesChanged indicates that the services running on a node have changed.
+	TopologyEventServicesChanged TopologyEventType = "services_changed"
+)
+
+// TopologyEvent describes a change in the topology of the cluster, derived by comparing successive cluster configs.
+type TopologyEvent struct {
+	// Type is the type of change which occurred.
+	Type TopologyEventType
+
+	// Revision is the revision of the cluster config in which the change was observed.
+	Revision int64
+
+	// Node is a copy of the node which changed, for removed nodes this is the node from the previous cluster config.
+	Node *Node
+}
+
+// diffTopology returns the events which describe the changes in topology between the given cluster configs.
+func diffTopology(old, curr *ClusterConfig) []TopologyEvent {
+	if old == nil || curr == nil {
+		return nil
+	}
+
+	previous := make(map[string]*Node, len(old.Nodes))
+
+	for _, node := range old.Nodes {
+		previous[topologyNodeKey(node)] = node
+	}
+
+	events := make([]TopologyEvent, 0)
+
+	for _, node := range curr.Nodes {
+		key := topologyNodeKey(node)
+
+		prev, ok := previous[key]
+
+		delete(previous, key)
+
+		switch {
+		case !ok:
+			events = append(events, TopologyEvent{Type: TopologyEventNodeAdded, Revision: curr.Revision, Node: node.Copy()})
+		case !servicesEqual(prev.Services, node.Services):
+			events = append(events, TopologyEvent{
+				Type:     TopologyEventServicesChanged,
+				Revision: curr.Revision,
+				Node:     node.Copy(),
+			})
+		}
+	}
+
+	// Iterate over the previous config (rather than the map) so that events are emitted in a deterministic order
+	for _, node := range old.Nodes {
+		if _, ok := previous[topologyNodeKey(node)]; ok {
+			events = append(events, TopologyEvent{Type: TopologyEventNodeRemoved, Revision: curr.Revision, Node: node.Copy()})
+		}
+	}
+
+	return events
+}
+
+// topologyNodeKey returns the key used to identify the given node between cluster configs.
+func topologyNodeKey(node *Node) string {
+	if node.UUID != "" {
+		return node.UUID
+	}
+
+	return node.Hostname
+}
+
+// servicesEqual returns a boolean indicating whether the given services are the same.
+func servicesEqual(a, b *Services) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// topologyWatchers tracks the channels on which topology events are delivered to watchers.
+type topologyWatchers struct {
+	lock     sync.Mutex
+	watchers map[chan TopologyEvent]context.Context
+}
+
+// newTopologyWatchers returns an initialized set of topology watchers.
+func newTopologyWatchers() *topologyWatchers {
+	return &topologyWatchers{watchers: make(map[chan TopologyEvent]context.Context)}
+}
+
+// watch returns a new channel on which topology events will be delivered, the channel is closed once the given context
+// is cancelled.
+func (t *topologyWatchers) watch(ctx context.Context) <-chan TopologyEvent {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	ch := make(chan TopologyEvent)
+
+	t.watchers[ch] = ctx
+
+	go func() {
+		<-ctx.Done()
+		t.remove(ch)
+	}()
+
+	return ch
+}
+
+// publish delivers the given events to each of the watchers, blocking until they've been received or the watcher has
+// been cancelled.
+func (t *topologyWatchers) publish(events []TopologyEvent) {
+	if t == nil || len(events) == 0 {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for ch, ctx := range t.watchers {
+		for _, event := range events {
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+			}
+		}
+	}
+}
+
+// remove stops delivering events to the given channel, and closes it.
+func (t *topologyWatchers) remove(ch chan TopologyEvent) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.watchers[ch]; !ok {
+		return
+	}
+
+	delete(t.watchers, ch)
+	close(ch)
+}
+
+// close stops delivering events to all the watchers, and closes their channels.
+func (t *topologyWatchers) close() {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for ch := range t.watchers {
+		delete(t.watchers, ch)
+		close(ch)
+	}
+}
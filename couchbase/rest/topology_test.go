@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffTopology(t *testing.T) {
+	type test struct {
+		name     string
+		old      *ClusterConfig
+		curr     *ClusterConfig
+		expected []TopologyEvent
+	}
+
+	var (
+		node1 = &Node{UUID: "1", Hostname: "host1", Services: &Services{Management: 8091}}
+		node2 = &Node{UUID: "2", Hostname: "host2", Services: &Services{Management: 8091}}
+		node3 = &Node{Hostname: "host3", Services: &Services{Management: 8091}}
+
+		changed = &Node{UUID: "2", Hostname: "host2", Services: &Services{Management: 8091, KV: 11210}}
+	)
+
+	tests := []*test{
+		{
+			name: "NoPreviousConfig",
+			curr: &ClusterConfig{Revision: 1, Nodes: Nodes{node1}},
+		},
+		{
+			name:     "NoChange",
+			old:      &ClusterConfig{Revision: 1, Nodes: Nodes{node1, node2}},
+			curr:     &ClusterConfig{Revision: 2, Nodes: Nodes{node2, node1}},
+			expected: make([]TopologyEvent, 0),
+		},
+		{
+			name: "NodeAdded",
+			old:  &ClusterConfig{Revision: 1, Nodes: Nodes{node1}},
+			curr: &ClusterConfig{Revision: 2, Nodes: Nodes{node1, node3}},
+			expected: []TopologyEvent{
+				{Type: TopologyEventNodeAdded, Revision: 2, Node: node3.Copy()},
+			},
+		},
+		{
+			name: "NodeRemoved",
+			old:  &ClusterConfig{Revision: 1, Nodes: Nodes{node1, node2}},
+			curr: &ClusterConfig{Revision: 2, Nodes: Nodes{node1}},
+			expected: []TopologyEvent{
+				{Type: TopologyEventNodeRemoved, Revision: 2, Node: node2.Copy()},
+			},
+		},
+		{
+			name: "ServicesChanged",
+			old:  &ClusterConfig{Revision: 1, Nodes: Nodes{node1, node2}},
+			curr: &ClusterConfig{Revision: 2, Nodes: Nodes{node1, changed}},
+			expected: []TopologyEvent{
+				{Type: TopologyEventServicesChanged, Revision: 2, Node: changed.Copy()},
+			},
+		},
+		{
+			name: "NodeSwapped",
+			old:  &ClusterConfig{Revision: 1, Nodes: Nodes{node1, node2}},
+			curr: &ClusterConfig{Revision: 2, Nodes: Nodes{node1, node3}},
+			expected: []TopologyEvent{
+				{Type: TopologyEventNodeAdded, Revision: 2, Node: node3.Copy()},
+				{Type: TopologyEventNodeRemoved, Revision: 2, Node: node2.Copy()},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, diffTopology(test.old, test.curr))
+		})
+	}
+}
+
+func TestTopologyWatchers(t *testing.T) {
+	watchers := newTopologyWatchers()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := watchers.watch(ctx)
+
+	expected := []TopologyEvent{
+		{Type: TopologyEventNodeAdded, Revision: 2, Node: &Node{Hostname: "host1"}},
+		{Type: TopologyEventNodeRemoved, Revision: 2, Node: &Node{Hostname: "host2"}},
+	}
+
+	go watchers.publish(expected)
+
+	require.Equal(t, expected[0], <-events)
+	require.Equal(t, expected[1], <-events)
+
+	cancel()
+
+	require.Eventually(t, func() bool {
+		_, ok := <-events
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTopologyWatchersPublishCancelledWatcher(t *testing.T) {
+	watchers := newTopologyWatchers()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Publishing must not block on a watcher which has been cancelled, but not yet removed
+	watchers.watch(ctx)
+	watchers.publish([]TopologyEvent{{Type: TopologyEventNodeAdded}})
+}
+
+func TestTopologyWatchersClose(t *testing.T) {
+	watchers := newTopologyWatchers()
+
+	events := watchers.watch(context.Background())
+
+	watchers.close()
+
+	_, ok := <-events
+	require.False(t, ok)
+}
+
+func TestClientWatchTopologyClosedByClose(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	events := client.WatchTopology(context.Background())
+
+	client.Close()
+
+	_, ok := <-events
+	require.False(t, ok)
+}
+
+func TestClientConfigManager(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{})
+	defer cluster.Close()
+
+	manager := NewClusterConfigManager(slog.Default())
+
+	client, err := NewClient(ClientOptions{
+		ConnectionString: cluster.URL(),
+		DisableCCP:       true,
+		Provider:         provider,
+		ConfigManager:    manager,
+	})
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.NotNil(t, manager.GetClusterConfig())
+	require.Equal(t, manager.GetClusterConfig(), client.authProvider.manager.GetClusterConfig())
+}
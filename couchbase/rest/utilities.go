@@ -2,11 +2,14 @@ package rest
 
 import (
 	"bufio"
+	"context"
 	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"io"
 	"log/slog"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -38,6 +41,26 @@ func newDefaultHTTPTimeouts() netutil.HTTPTimeouts {
 	}
 }
 
+// newDialContext returns the function which should be used to establish connections, a <nil> return value indicates
+// that the transport default should be used.
+func newDialContext(dial DialContextFunc, resolver *net.Resolver, timeouts netutil.HTTPTimeouts) DialContextFunc {
+	if dial != nil {
+		return dial
+	}
+
+	if resolver == nil {
+		return nil
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   ptr.From(timeouts.Dialer),
+		KeepAlive: ptr.From(timeouts.KeepAlive),
+		Resolver:  resolver,
+	}
+
+	return dialer.DialContext
+}
+
 // enhanceError returns a more informative error using information from the given request/response.
 func enhanceError(err error, request *Request, resp *http.Response) error {
 	if err != nil || resp == nil {
@@ -48,7 +71,7 @@ func enhanceError(err error, request *Request, resp *http.Response) error {
 	defer resp.Body.Close()
 	body, _ := readBody(request.Method, request.Endpoint, resp.Body, resp.ContentLength)
 
-	return handleResponseError(request.Method, request.Endpoint, resp.StatusCode, body)
+	return handleResponseError(request.Method, request.Endpoint, resp, body)
 }
 
 // readBody returns the entire response body returning an informative error in the case where the response body is less
@@ -72,13 +95,27 @@ func readBody(method Method, endpoint Endpoint, reader io.Reader, contentLength
 }
 
 // setAuthHeaders is a utility function which sets all the request headers which are provided by the 'AuthProvider'.
-func setAuthHeaders(host string, provider aprov.Provider, req *http.Request, logger *slog.Logger) error {
+//
+// NOTE: When a signer is provided, it's used to authenticate the request instead of HTTP basic auth; this should be
+// called once all other headers have been set, so that they may be signed.
+func setAuthHeaders(
+	host, userAgent string,
+	provider aprov.Provider,
+	signer Signer,
+	req *http.Request,
+	body []byte,
+	logger *slog.Logger,
+) error {
 	// Set the 'User-Agent' so that we can trace how these requests are handled by the cluster
-	req.Header.Set("User-Agent", provider.GetUserAgent())
+	req.Header.Set("User-Agent", userAgent)
 
-	credentials, err := getCredentials(provider, host, logger)
+	credentials, err := getCredentials(req.Context(), provider, host, logger)
 	if err != nil {
-		return err
+		return err // Purposefully not wrapped
+	}
+
+	if signer != nil {
+		return signer.Sign(req, body, credentials)
 	}
 
 	// Use the auth provider to populate the credentials
@@ -87,45 +124,81 @@ func setAuthHeaders(host string, provider aprov.Provider, req *http.Request, log
 	return nil
 }
 
-// getCredentials uses a retryer to get the credentials from the given provider.
-func getCredentials(provider aprov.Provider, host string, logger *slog.Logger) (aprov.Credentials, error) {
+// getCredentials uses a retryer to get the credentials from the given provider, backing off exponentially between
+// attempts; this allows riding out temporary failures e.g. when the provider is backed by an external secret store.
+func getCredentials(
+	ctx context.Context,
+	provider aprov.Provider,
+	host string,
+	logger *slog.Logger,
+) (aprov.Credentials, error) {
 	log := func(ctx *retry.Context, _ aprov.Credentials, err error) {
-		logger.Warn("failed to get credentials", "attempt", ctx.Attempt, "error", err)
+		logger.Warn("failed to get credentials, will retry", "host", host, "attempt", ctx.Attempt(), "error", err)
 	}
 
 	retryer := retry.NewRetryer[aprov.Credentials](retry.RetryerOptions[aprov.Credentials]{
 		Algorithm:  retry.AlgorithmExponential,
 		MaxRetries: 3,
-		MinDelay:   250 * time.Second,
+		MinDelay:   250 * time.Millisecond,
+		MaxDelay:   5 * time.Second,
 		Log:        log,
 	})
 
-	return retryer.Do(func(_ *retry.Context) (aprov.Credentials, error) { return provider.GetCredentials(host) })
+	credentials, err := retryer.DoWithContext(
+		ctx,
+		func(_ *retry.Context) (aprov.Credentials, error) { return provider.GetCredentials(host) },
+	)
+	if err != nil {
+		return aprov.Credentials{}, &CredentialsError{host: host, err: err}
+	}
+
+	return credentials, nil
 }
 
-// waitForRetryAfter sleeps until we can retry the request for the given response.
+// waitForRetryAfter sleeps until we can retry the request for the given response, returning an error if the request
+// shouldn't be retried e.g. because waiting would exceed the deadline of the given context.
 //
-// NOTE: Truncates the value from the 'Retry-After' header to a maximum of 60s.
-func waitForRetryAfter(resp *http.Response) {
-	if resp.StatusCode != http.StatusServiceUnavailable {
-		return
+// NOTE: Truncates the value from the 'Retry-After' header to a maximum of 60s, and adds up to 10% jitter so that many
+// clients being told to back off at once don't all retry at the same time.
+func waitForRetryAfter(ctx context.Context, clock Clock, resp *http.Response) error {
+	if resp.StatusCode != http.StatusServiceUnavailable && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
 	}
 
 	after := resp.Header.Get("Retry-After")
 	if after == "" {
-		return
+		return nil
+	}
+
+	duration := waitForRetryDuration(clock.Now(), after)
+	if duration <= 0 {
+		return nil
+	}
+
+	duration = withJitter(min(duration, time.Minute))
+
+	// There's no point waiting if the request is going to run out of time before we're allowed to retry it
+	deadline, ok := ctx.Deadline()
+	if ok && deadline.Sub(clock.Now()) < duration {
+		return ErrDeadlineWouldBeExceeded
 	}
 
-	duration := waitForRetryDuration(after)
-	if duration == 0 {
-		return
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clock.After(duration):
+		return nil
 	}
+}
 
-	time.Sleep(min(duration, time.Minute))
+// withJitter returns the given duration with up to 10% random jitter added.
+func withJitter(duration time.Duration) time.Duration {
+	return duration + rand.N(duration/10+1)
 }
 
-// waitForRetryDuration returns the duration to wait until we've satisfied the given 'Retry-After' header.
-func waitForRetryDuration(after string) time.Duration {
+// waitForRetryDuration returns the duration to wait from the given time until we've satisfied the given 'Retry-After'
+// header.
+func waitForRetryDuration(now time.Time, after string) time.Duration {
 	seconds, err := strconv.Atoi(after)
 	if seconds != 0 || err == nil {
 		return time.Duration(seconds) * time.Second
@@ -133,7 +206,7 @@ func waitForRetryDuration(after string) time.Duration {
 
 	date, err := time.Parse(time.RFC1123, after)
 	if err == nil {
-		return time.Until(date.UTC())
+		return date.UTC().Sub(now)
 	}
 
 	return 0
@@ -145,7 +218,7 @@ func handleRequestError(req *http.Request, err error) error {
 	// If we received and unknown authority error, wrap it with our informative error explaining the alternatives
 	// available to the user.
 	var unknownAuth x509.UnknownAuthorityError
-	if errors.As(err, &unknownAuth) {
+	if errors.As(err, &unknownAuth) || errors.Is(err, ErrCertificateNotPinned) {
 		return &UnknownAuthorityError{inner: err}
 	}
 
@@ -163,10 +236,23 @@ func handleRequestError(req *http.Request, err error) error {
 	return err
 }
 
+// cleanPathPrefix returns the given path prefix with a single leading slash and no trailing slash, so that it may be
+// placed between a host and an endpoint; an empty string is returned when there's no prefix.
+func cleanPathPrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+
+	return "/" + prefix
+}
+
 // handleResponseError is a utility function which converts a failed REST request (soft failure i.e. the request itself
 // was successful) into a more useful/user friendly error.
-func handleResponseError(method Method, endpoint Endpoint, statusCode int, body []byte) error {
-	switch statusCode {
+func handleResponseError(method Method, endpoint Endpoint, resp *http.Response, body []byte) error {
+	details := newResponseDetails(resp)
+
+	switch resp.StatusCode {
 	case http.StatusForbidden:
 		type overlay struct {
 			Permissions []string `json:"permissions"`
@@ -179,19 +265,28 @@ func handleResponseError(method Method, endpoint Endpoint, statusCode int, body
 		_ = json.Unmarshal(body, &data)
 
 		return &AuthorizationError{
-			method:      method,
-			endpoint:    endpoint,
-			permissions: data.Permissions,
+			responseDetails: details,
+			method:          method,
+			endpoint:        endpoint,
+			permissions:     data.Permissions,
 		}
 	case http.StatusUnauthorized:
-		return &AuthenticationError{method: method, endpoint: endpoint}
+		return &AuthenticationError{responseDetails: details, method: method, endpoint: endpoint}
 	case http.StatusInternalServerError:
-		return &InternalServerError{method: method, endpoint: endpoint, body: body}
+		return &InternalServerError{responseDetails: details, method: method, endpoint: endpoint, body: body}
 	case http.StatusNotFound:
-		return &EndpointNotFoundError{method: method, endpoint: endpoint}
+		return &EndpointNotFoundError{responseDetails: details, method: method, endpoint: endpoint}
+	case http.StatusPreconditionFailed:
+		return &PreconditionFailedError{responseDetails: details, method: method, endpoint: endpoint, body: body}
 	}
 
-	return &UnexpectedStatusCodeError{Status: statusCode, method: method, endpoint: endpoint, body: body}
+	return &UnexpectedStatusCodeError{
+		responseDetails: details,
+		Status:          resp.StatusCode,
+		method:          method,
+		endpoint:        endpoint,
+		body:            body,
+	}
 }
 
 // shouldRetry returns a boolean indicating whether the request which returned the given error should be retried.
@@ -1,8 +1,11 @@
 package rest
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -52,3 +55,41 @@ func TestShouldRetry(t *testing.T) {
 		})
 	}
 }
+
+func TestNewDialContext(t *testing.T) {
+	timeouts := newDefaultHTTPTimeouts()
+
+	require.Nil(t, newDialContext(nil, nil, timeouts))
+	require.NotNil(t, newDialContext(nil, &net.Resolver{}, timeouts))
+
+	var called bool
+
+	dial := func(_ context.Context, _, _ string) (net.Conn, error) {
+		called = true
+		return nil, nil
+	}
+
+	_, _ = newDialContext(dial, &net.Resolver{}, timeouts)(context.Background(), "tcp", "localhost:8091")
+	require.True(t, called)
+}
+
+func TestWithJitter(t *testing.T) {
+	for range 100 {
+		actual := withJitter(time.Second)
+		require.GreaterOrEqual(t, actual, time.Second)
+		require.LessOrEqual(t, actual, time.Second+100*time.Millisecond)
+	}
+}
+
+func TestCleanPathPrefix(t *testing.T) {
+	for input, expected := range map[string]string{
+		"":             "",
+		"/":            "",
+		"couchbase":    "/couchbase",
+		"/couchbase":   "/couchbase",
+		"/couchbase/":  "/couchbase",
+		"a/couchbase/": "/a/couchbase",
+	} {
+		require.Equal(t, expected, cleanPathPrefix(input), "input %q", input)
+	}
+}
@@ -0,0 +1,331 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// RemoteClusterEncryption represents the level of encryption used when communicating with an XDCR remote cluster.
+type RemoteClusterEncryption string
+
+const (
+	// RemoteClusterEncryptionNone indicates that communication with the remote cluster is not encrypted.
+	RemoteClusterEncryptionNone RemoteClusterEncryption = "none"
+
+	// RemoteClusterEncryptionHalf indicates that only credentials are encrypted when communicating with the remote
+	// cluster.
+	RemoteClusterEncryptionHalf RemoteClusterEncryption = "half"
+
+	// RemoteClusterEncryptionFull indicates that all communication with the remote cluster is encrypted.
+	RemoteClusterEncryptionFull RemoteClusterEncryption = "full"
+)
+
+// RemoteClusterOptions encapsulates the options available when creating an XDCR remote cluster reference.
+type RemoteClusterOptions struct {
+	// Name is the name given to the remote cluster reference.
+	//
+	// NOTE: Required
+	Name string
+
+	// Hostname is the address of a node in the remote cluster.
+	//
+	// NOTE: Required
+	Hostname string
+
+	// Username is the username used to authenticate against the remote cluster.
+	Username string
+
+	// Password is the password used to authenticate against the remote cluster.
+	Password string
+
+	// Encryption is the level of encryption used when communicating with the remote cluster, when omitted
+	// communication is not encrypted.
+	Encryption RemoteClusterEncryption
+
+	// Certificate is the PEM encoded root certificate of the remote cluster.
+	//
+	// NOTE: Required when using 'RemoteClusterEncryptionFull'.
+	Certificate string
+}
+
+// RemoteCluster represents an XDCR remote cluster reference, as returned by the remote clusters endpoint.
+type RemoteCluster struct {
+	Name       string                  `json:"name"`
+	UUID       string                  `json:"uuid"`
+	Hostname   string                  `json:"hostname"`
+	Username   string                  `json:"username"`
+	Encryption RemoteClusterEncryption `json:"encryptionType,omitempty"`
+	Deleted    bool                    `json:"deleted"`
+}
+
+// ReplicationOptions encapsulates the options available when creating an XDCR replication.
+type ReplicationOptions struct {
+	// FromBucket is the bucket in this cluster which will be replicated.
+	//
+	// NOTE: Required
+	FromBucket string
+
+	// ToCluster is the name of the remote cluster reference to replicate to.
+	//
+	// NOTE: Required
+	ToCluster string
+
+	// ToBucket is the bucket in the remote cluster which will be replicated to.
+	//
+	// NOTE: Required
+	ToBucket string
+
+	// FilterExpression limits replication to the documents which match the given expression.
+	FilterExpression string
+}
+
+// Replication represents a single XDCR replication, as returned by the tasks endpoint.
+type Replication struct {
+	ID               string     `json:"id"`
+	Status           TaskStatus `json:"status"`
+	Source           string     `json:"source"`
+	Target           string     `json:"target"`
+	FilterExpression string     `json:"filterExpression,omitempty"`
+}
+
+// CreateRemoteCluster creates a new XDCR remote cluster reference, returning the created reference.
+func (c *Client) CreateRemoteCluster(ctx context.Context, opts RemoteClusterOptions) (*RemoteCluster, error) {
+	values := make(url.Values)
+	values.Set("name", opts.Name)
+	values.Set("hostname", opts.Hostname)
+	values.Set("username", opts.Username)
+	values.Set("password", opts.Password)
+
+	var features []Feature
+
+	if opts.Encryption == RemoteClusterEncryptionHalf {
+		features = append(features, FeatureXDCRHalfEncryption)
+	}
+
+	err := c.checkCompatibility(ctx, features...)
+	if err != nil {
+		return nil, err // Purposefully not wrapped
+	}
+
+	if opts.Encryption != "" && opts.Encryption != RemoteClusterEncryptionNone {
+		values.Set("demandEncryption", "1")
+		values.Set("encryptionType", string(opts.Encryption))
+	}
+
+	if opts.Certificate != "" {
+		values.Set("certificate", opts.Certificate)
+	}
+
+	request := &Request{
+		Body:               []byte(values.Encode()),
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointRemoteClusters,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+	}
+
+	response, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var remote RemoteCluster
+
+	err = json.Unmarshal(response.Body, &remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal remote cluster: %w", err)
+	}
+
+	return &remote, nil
+}
+
+// GetRemoteClusters returns the XDCR remote cluster references for the cluster.
+//
+// NOTE: References which have been deleted (but are still reported by the cluster) are not returned.
+func (c *Client) GetRemoteClusters(ctx context.Context) ([]RemoteCluster, error) {
+	request := &Request{
+		Endpoint:           EndpointRemoteClusters,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	response, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var remotes []RemoteCluster
+
+	err = json.Unmarshal(response.Body, &remotes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal remote clusters: %w", err)
+	}
+
+	filtered := make([]RemoteCluster, 0, len(remotes))
+
+	for _, remote := range remotes {
+		if !remote.Deleted {
+			filtered = append(filtered, remote)
+		}
+	}
+
+	return filtered, nil
+}
+
+// DeleteRemoteCluster deletes the XDCR remote cluster reference with the given name.
+func (c *Client) DeleteRemoteCluster(ctx context.Context, name string) error {
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointRemoteCluster.Format(name),
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodDelete,
+		Service:            ServiceManagement,
+	}
+
+	_, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	return nil
+}
+
+// CreateReplication creates a new continuous XDCR replication, returning its id.
+func (c *Client) CreateReplication(ctx context.Context, opts ReplicationOptions) (string, error) {
+	values := make(url.Values)
+	values.Set("fromBucket", opts.FromBucket)
+	values.Set("toCluster", opts.ToCluster)
+	values.Set("toBucket", opts.ToBucket)
+	values.Set("replicationType", "continuous")
+
+	var features []Feature
+
+	if opts.FilterExpression != "" {
+		values.Set("filterExpression", opts.FilterExpression)
+
+		features = append(features, FeatureXDCRFilterExpression)
+	}
+
+	err := c.checkCompatibility(ctx, features...)
+	if err != nil {
+		return "", err // Purposefully not wrapped
+	}
+
+	request := &Request{
+		Body:               []byte(values.Encode()),
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointCreateReplication,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+	}
+
+	response, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var decoded struct {
+		ID string `json:"id"`
+	}
+
+	err = json.Unmarshal(response.Body, &decoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal replication: %w", err)
+	}
+
+	return decoded.ID, nil
+}
+
+// ListReplications returns the XDCR replications for the cluster.
+func (c *Client) ListReplications(ctx context.Context) ([]Replication, error) {
+	request := &Request{
+		Endpoint:           EndpointTasks,
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodGet,
+		Service:            ServiceManagement,
+	}
+
+	response, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	var tasks []struct {
+		Replication
+		Type TaskType `json:"type"`
+	}
+
+	err = json.Unmarshal(response.Body, &tasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tasks: %w", err)
+	}
+
+	replications := make([]Replication, 0)
+
+	for _, task := range tasks {
+		if task.Type == TaskTypeXDCR {
+			replications = append(replications, task.Replication)
+		}
+	}
+
+	return replications, nil
+}
+
+// PauseReplication pauses the XDCR replication with the given id.
+func (c *Client) PauseReplication(ctx context.Context, id string) error {
+	return c.setReplicationPaused(ctx, id, true)
+}
+
+// ResumeReplication resumes the paused XDCR replication with the given id.
+func (c *Client) ResumeReplication(ctx context.Context, id string) error {
+	return c.setReplicationPaused(ctx, id, false)
+}
+
+// DeleteReplication deletes the XDCR replication with the given id.
+func (c *Client) DeleteReplication(ctx context.Context, id string) error {
+	request := &Request{
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointCancelReplication.Format(id),
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodDelete,
+		Service:            ServiceManagement,
+	}
+
+	_, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	return nil
+}
+
+// setReplicationPaused pauses/resumes the XDCR replication with the given id.
+func (c *Client) setReplicationPaused(ctx context.Context, id string, paused bool) error {
+	values := make(url.Values)
+	values.Set("pauseRequested", strconv.FormatBool(paused))
+
+	request := &Request{
+		Body:               []byte(values.Encode()),
+		ContentType:        ContentTypeURLEncoded,
+		Endpoint:           EndpointReplicationSettings.Format(id),
+		ExpectedStatusCode: http.StatusOK,
+		Method:             http.MethodPost,
+		Service:            ServiceManagement,
+		// Updating the settings to the same value has no additional effect, so it's safe to retry
+		Idempotent: true,
+	}
+
+	_, err := c.ExecuteWithContext(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	return nil
+}
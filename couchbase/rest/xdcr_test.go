@@ -0,0 +1,254 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cbvalue "github.com/couchbase/tools-common/couchbase/v3/value"
+)
+
+func TestClientCreateRemoteCluster(t *testing.T) {
+	var (
+		handlers = make(TestHandlers)
+		values   url.Values
+	)
+
+	handlers.Add(http.MethodPost, string(EndpointRemoteClusters), NewTestHandlerWithValue(
+		t,
+		http.StatusOK,
+		[]byte(`{"name":"remote","uuid":"uuid","hostname":"host:8091","username":"user","encryptionType":"full"}`),
+		&values,
+	))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	remote, err := client.CreateRemoteCluster(context.Background(), RemoteClusterOptions{
+		Name:        "remote",
+		Hostname:    "host",
+		Username:    "user",
+		Password:    "pass",
+		Encryption:  RemoteClusterEncryptionFull,
+		Certificate: "cert",
+	})
+	require.NoError(t, err)
+
+	expected := &RemoteCluster{
+		Name:       "remote",
+		UUID:       "uuid",
+		Hostname:   "host:8091",
+		Username:   "user",
+		Encryption: RemoteClusterEncryptionFull,
+	}
+
+	require.Equal(t, expected, remote)
+
+	require.Equal(t, url.Values{
+		"name":             {"remote"},
+		"hostname":         {"host"},
+		"username":         {"user"},
+		"password":         {"pass"},
+		"demandEncryption": {"1"},
+		"encryptionType":   {"full"},
+		"certificate":      {"cert"},
+	}, values)
+}
+
+func TestClientGetRemoteClusters(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, string(EndpointRemoteClusters), NewTestHandler(t, http.StatusOK, []byte(`[
+		{"name":"remote","uuid":"uuid","hostname":"host:8091","username":"user","deleted":false},
+		{"name":"old","uuid":"uuid","hostname":"host:8091","username":"user","deleted":true}
+	]`)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	remotes, err := client.GetRemoteClusters(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []RemoteCluster{{Name: "remote", UUID: "uuid", Hostname: "host:8091", Username: "user"}}, remotes)
+}
+
+func TestClientDeleteRemoteCluster(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodDelete, "/pools/default/remoteClusters/remote", NewTestHandler(t, http.StatusOK, nil))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.NoError(t, client.DeleteRemoteCluster(context.Background(), "remote"))
+}
+
+func TestClientCreateReplication(t *testing.T) {
+	var (
+		handlers = make(TestHandlers)
+		values   url.Values
+	)
+
+	handlers.Add(
+		http.MethodPost,
+		string(EndpointCreateReplication),
+		NewTestHandlerWithValue(t, http.StatusOK, []byte(`{"id":"uuid/src/dst"}`), &values),
+	)
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	id, err := client.CreateReplication(context.Background(), ReplicationOptions{
+		FromBucket:       "src",
+		ToCluster:        "remote",
+		ToBucket:         "dst",
+		FilterExpression: "REGEXP_CONTAINS(META().id, '^a')",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "uuid/src/dst", id)
+
+	require.Equal(t, url.Values{
+		"fromBucket":       {"src"},
+		"toCluster":        {"remote"},
+		"toBucket":         {"dst"},
+		"replicationType":  {"continuous"},
+		"filterExpression": {"REGEXP_CONTAINS(META().id, '^a')"},
+	}, values)
+}
+
+func TestClientCreateReplicationFilterExpressionUnsupported(t *testing.T) {
+	cluster := NewTestCluster(t, TestClusterOptions{Nodes: TestNodes{{Version: cbvalue.Version5_0_0}}})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	_, err = client.CreateReplication(context.Background(), ReplicationOptions{
+		FromBucket:       "src",
+		ToCluster:        "remote",
+		ToBucket:         "dst",
+		FilterExpression: "REGEXP_CONTAINS(META().id, '^a')",
+	})
+
+	var unsupported *UnsupportedServerVersionError
+
+	require.ErrorAs(t, err, &unsupported)
+	require.Equal(t, cbvalue.Version5_5_0, unsupported.Required)
+}
+
+func TestClientListReplications(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodGet, string(EndpointTasks), NewTestHandler(t, http.StatusOK, []byte(`[
+		{"type":"rebalance","status":"notRunning"},
+		{"type":"xdcr","id":"uuid/src/dst","status":"paused","source":"src","target":"/remoteClusters/uuid/buckets/dst"}
+	]`)))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	replications, err := client.ListReplications(context.Background())
+	require.NoError(t, err)
+
+	expected := []Replication{{
+		ID:     "uuid/src/dst",
+		Status: TaskStatusPaused,
+		Source: "src",
+		Target: "/remoteClusters/uuid/buckets/dst",
+	}}
+
+	require.Equal(t, expected, replications)
+}
+
+func TestClientPauseResumeReplication(t *testing.T) {
+	var (
+		handlers = make(TestHandlers)
+		values   url.Values
+	)
+
+	// The replication id is path escaped, the test cluster routes using the unescaped path
+	handlers.Add(
+		http.MethodPost,
+		"/settings/replications/uuid/src/dst",
+		NewTestHandlerWithValue(t, http.StatusOK, nil, &values),
+	)
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.NoError(t, client.PauseReplication(context.Background(), "uuid/src/dst"))
+	require.Equal(t, url.Values{"pauseRequested": {"true"}}, values)
+
+	require.NoError(t, client.ResumeReplication(context.Background(), "uuid/src/dst"))
+	require.Equal(t, url.Values{"pauseRequested": {"false"}}, values)
+}
+
+func TestClientPauseReplicationRetries(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(
+		http.MethodPost,
+		"/settings/replications/uuid/src/dst",
+		NewTestHandlerWithRetries(t, 1, http.StatusServiceUnavailable, http.StatusOK, "", nil),
+	)
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.NoError(t, client.PauseReplication(context.Background(), "uuid/src/dst"))
+}
+
+func TestClientDeleteReplication(t *testing.T) {
+	handlers := make(TestHandlers)
+
+	handlers.Add(http.MethodDelete, "/controller/cancelXDCR/uuid/src/dst", NewTestHandler(t, http.StatusOK, nil))
+
+	cluster := NewTestCluster(t, TestClusterOptions{Handlers: handlers})
+	defer cluster.Close()
+
+	client, err := newTestClient(cluster, true)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	require.NoError(t, client.DeleteReplication(context.Background(), "uuid/src/dst"))
+}
@@ -0,0 +1,336 @@
+// Package topology provides utilities to compare cluster configs, describing how the topology of a cluster has changed.
+package topology
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/couchbase/tools-common/couchbase/v3/rest"
+	"github.com/couchbase/tools-common/types/v2/ptr"
+)
+
+// services is the list of services which are considered when comparing the topology of a cluster, in the order they
+// are reported.
+//
+// NOTE: The Management and Views services are omitted, as they're implied by the presence of the node/Data Service.
+var services = []rest.Service{
+	rest.ServiceData,
+	rest.ServiceQuery,
+	rest.ServiceGSI,
+	rest.ServiceSearch,
+	rest.ServiceAnalytics,
+	rest.ServiceEventing,
+	rest.ServiceBackup,
+}
+
+// Changes describes the differences in topology between two cluster configs.
+type Changes struct {
+	// OldRevision is the revision of the previous cluster config.
+	OldRevision int64
+
+	// NewRevision is the revision of the current cluster config.
+	NewRevision int64
+
+	// Added are the nodes which are only in the current cluster config.
+	Added []*rest.Node
+
+	// Removed are the nodes which are only in the previous cluster config.
+	Removed []*rest.Node
+
+	// Changed are the nodes which are in both cluster configs, but whose addressing information or services differ.
+	Changed []NodeChange
+
+	// Movements describes the services which are running on a different set of nodes, including services which are
+	// running on added/removed nodes.
+	Movements []ServiceMovement
+}
+
+// Empty returns a boolean indicating whether there are no differences in topology.
+func (c *Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0 && len(c.Movements) == 0
+}
+
+// Summary returns a human-readable summary of the changes, suitable for logging; each line describes a single change.
+func (c *Changes) Summary() string {
+	if c.Empty() {
+		return fmt.Sprintf("revision %d -> %d: no topology changes", c.OldRevision, c.NewRevision)
+	}
+
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "revision %d -> %d: %d node(s) added, %d node(s) removed, %d node(s) changed",
+		c.OldRevision, c.NewRevision, len(c.Added), len(c.Removed), len(c.Changed))
+
+	for _, node := range c.Added {
+		fmt.Fprintf(&builder, "\n  + %s %s", nodeName(node), formatServices(nodeServices(node)))
+	}
+
+	for _, node := range c.Removed {
+		fmt.Fprintf(&builder, "\n  - %s %s", nodeName(node), formatServices(nodeServices(node)))
+	}
+
+	for _, change := range c.Changed {
+		fmt.Fprintf(&builder, "\n  ~ %s", change)
+	}
+
+	for _, movement := range c.Movements {
+		fmt.Fprintf(&builder, "\n  > %s", movement)
+	}
+
+	return builder.String()
+}
+
+// String implements the 'Stringer' interface, returning a summary of the changes.
+func (c *Changes) String() string {
+	return c.Summary()
+}
+
+// NodeChange describes how a node which is in both cluster configs has changed.
+type NodeChange struct {
+	// Old is the node from the previous cluster config.
+	Old *rest.Node
+
+	// New is the node from the current cluster config.
+	New *rest.Node
+
+	// ServicesAdded are the services which are only running on the node in the current cluster config.
+	ServicesAdded []rest.Service
+
+	// ServicesRemoved are the services which were only running on the node in the previous cluster config.
+	ServicesRemoved []rest.Service
+}
+
+// HostnameChanged returns a boolean indicating whether the hostname of the node has changed.
+func (n NodeChange) HostnameChanged() bool {
+	return n.Old.Hostname != n.New.Hostname
+}
+
+// String implements the 'Stringer' interface, returning a human-readable description of the change.
+func (n NodeChange) String() string {
+	changes := make([]string, 0, 3)
+
+	if n.HostnameChanged() {
+		changes = append(changes, fmt.Sprintf("hostname '%s' -> '%s'", n.Old.Hostname, n.New.Hostname))
+	}
+
+	if len(n.ServicesAdded) != 0 {
+		changes = append(changes, "services added "+formatServices(n.ServicesAdded))
+	}
+
+	if len(n.ServicesRemoved) != 0 {
+		changes = append(changes, "services removed "+formatServices(n.ServicesRemoved))
+	}
+
+	if len(changes) == 0 {
+		changes = append(changes, "addressing changed")
+	}
+
+	return fmt.Sprintf("%s %s", nodeName(n.New), strings.Join(changes, ", "))
+}
+
+// ServiceMovement describes a service which is running on a different set of nodes.
+type ServiceMovement struct {
+	// Service is the service which has moved.
+	Service rest.Service
+
+	// From are the names of the nodes which were running the service in the previous cluster config, but aren't in the
+	// current cluster config.
+	From []string
+
+	// To are the names of the nodes which are running the service in the current cluster config, but weren't in the
+	// previous cluster config.
+	To []string
+}
+
+// String implements the 'Stringer' interface, returning a human-readable description of the movement.
+func (s ServiceMovement) String() string {
+	return fmt.Sprintf("%s moved from [%s] to [%s]", s.Service, strings.Join(s.From, ", "), strings.Join(s.To, ", "))
+}
+
+// Diff returns the differences in topology between the given cluster configs, a <nil> config is treated as having no
+// nodes.
+//
+// NOTE: Nodes are matched between configs using their uuid, falling back to their hostname for nodes without a uuid.
+func Diff(oldConfig, newConfig *rest.ClusterConfig) *Changes {
+	var (
+		changes  = &Changes{}
+		oldNodes rest.Nodes
+		newNodes rest.Nodes
+	)
+
+	if oldConfig != nil {
+		changes.OldRevision, oldNodes = oldConfig.Revision, oldConfig.Nodes
+	}
+
+	if newConfig != nil {
+		changes.NewRevision, newNodes = newConfig.Revision, newConfig.Nodes
+	}
+
+	previous := make(map[string]*rest.Node, len(oldNodes))
+
+	for _, node := range oldNodes {
+		previous[nodeKey(node)] = node
+	}
+
+	current := make(map[string]*rest.Node, len(newNodes))
+
+	for _, node := range newNodes {
+		current[nodeKey(node)] = node
+
+		prev, ok := previous[nodeKey(node)]
+		if !ok {
+			changes.Added = append(changes.Added, node.Copy())
+			continue
+		}
+
+		if change, ok := diffNode(prev, node); ok {
+			changes.Changed = append(changes.Changed, change)
+		}
+	}
+
+	// Iterate over the previous config (rather than the map) so that changes are reported in a deterministic order
+	for _, node := range oldNodes {
+		if _, ok := current[nodeKey(node)]; !ok {
+			changes.Removed = append(changes.Removed, node.Copy())
+		}
+	}
+
+	changes.Movements = diffServices(oldNodes, newNodes)
+
+	return changes
+}
+
+// diffNode returns the changes between the given versions of a node, and a boolean indicating whether it changed.
+func diffNode(old, curr *rest.Node) (NodeChange, bool) {
+	change := NodeChange{
+		Old:             old.Copy(),
+		New:             curr.Copy(),
+		ServicesAdded:   difference(nodeServices(curr), nodeServices(old)),
+		ServicesRemoved: difference(nodeServices(old), nodeServices(curr)),
+	}
+
+	changed := len(change.ServicesAdded) != 0 ||
+		len(change.ServicesRemoved) != 0 ||
+		change.HostnameChanged() ||
+		!portsEqual(old.Services, curr.Services) ||
+		!externalEqual(old.AlternateAddresses.External, curr.AlternateAddresses.External)
+
+	return change, changed
+}
+
+// diffServices returns the services which are running on a different set of nodes.
+func diffServices(oldNodes, newNodes rest.Nodes) []ServiceMovement {
+	var (
+		before    = servicePlacement(oldNodes)
+		after     = servicePlacement(newNodes)
+		movements []ServiceMovement
+	)
+
+	for _, service := range services {
+		var (
+			from = difference(before[service], after[service])
+			to   = difference(after[service], before[service])
+		)
+
+		if len(from) == 0 && len(to) == 0 {
+			continue
+		}
+
+		movements = append(movements, ServiceMovement{Service: service, From: from, To: to})
+	}
+
+	return movements
+}
+
+// servicePlacement returns the names of the nodes running each service.
+func servicePlacement(nodes rest.Nodes) map[rest.Service][]string {
+	placement := make(map[rest.Service][]string)
+
+	for _, node := range nodes {
+		for _, service := range nodeServices(node) {
+			placement[service] = append(placement[service], nodeName(node))
+		}
+	}
+
+	return placement
+}
+
+// nodeServices returns the services running on the given node, in the order they're reported.
+func nodeServices(node *rest.Node) []rest.Service {
+	if node.Services == nil {
+		return nil
+	}
+
+	running := make([]rest.Service, 0)
+
+	for _, service := range services {
+		if node.Services.GetPort(service, false) != 0 || node.Services.GetPort(service, true) != 0 {
+			running = append(running, service)
+		}
+	}
+
+	return running
+}
+
+// nodeKey returns the key used to identify the given node between cluster configs.
+func nodeKey(node *rest.Node) string {
+	if node.UUID != "" {
+		return node.UUID
+	}
+
+	return node.Hostname
+}
+
+// nodeName returns a human-readable name for the given node.
+func nodeName(node *rest.Node) string {
+	if node.Hostname != "" {
+		return node.Hostname
+	}
+
+	return node.UUID
+}
+
+// formatServices returns a human-readable list of the given services.
+func formatServices(services []rest.Service) string {
+	names := make([]string, 0, len(services))
+
+	for _, service := range services {
+		names = append(names, string(service))
+	}
+
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+// difference returns the elements of 'a' which aren't in 'b', preserving their order.
+func difference[T comparable](a, b []T) []T {
+	var diff []T
+
+	for _, elem := range a {
+		if !slices.Contains(b, elem) {
+			diff = append(diff, elem)
+		}
+	}
+
+	return diff
+}
+
+// portsEqual returns a boolean indicating whether the given services are listening on the same ports, where <nil> is
+// equivalent to no ports.
+func portsEqual(a, b *rest.Services) bool {
+	return ptr.From(a) == ptr.From(b)
+}
+
+// externalEqual returns a boolean indicating whether the given alternate addresses are the same, where <nil> is
+// equivalent to no alternate addresses.
+//
+// NOTE: Copying a node populates missing alternate addresses, so they must be treated as equivalent.
+func externalEqual(a, b *rest.External) bool {
+	var (
+		x = ptr.From(a)
+		y = ptr.From(b)
+	)
+
+	return x.Hostname == y.Hostname && portsEqual(x.Services, y.Services)
+}
@@ -0,0 +1,105 @@
+package topology
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/couchbase/v3/rest"
+)
+
+var (
+	node1 = &rest.Node{UUID: "1", Hostname: "host1", Services: &rest.Services{Management: 8091, KV: 11210}}
+	node2 = &rest.Node{UUID: "2", Hostname: "host2", Services: &rest.Services{Management: 8091, N1QL: 8093}}
+	node3 = &rest.Node{Hostname: "host3", Services: &rest.Services{Management: 8091, KV: 11210}}
+)
+
+func TestDiffNoChanges(t *testing.T) {
+	var (
+		old  = &rest.ClusterConfig{Revision: 1, Nodes: rest.Nodes{node1, node2}}
+		curr = &rest.ClusterConfig{Revision: 2, Nodes: rest.Nodes{node2.Copy(), node1.Copy()}}
+	)
+
+	changes := Diff(old, curr)
+	require.True(t, changes.Empty())
+	require.Equal(t, &Changes{OldRevision: 1, NewRevision: 2}, changes)
+	require.Equal(t, "revision 1 -> 2: no topology changes", changes.Summary())
+}
+
+func TestDiffNoPreviousConfig(t *testing.T) {
+	changes := Diff(nil, &rest.ClusterConfig{Revision: 1, Nodes: rest.Nodes{node1}})
+	require.Equal(t, rest.Nodes{node1.Copy()}, rest.Nodes(changes.Added))
+	require.Empty(t, changes.Removed)
+	require.Equal(t, []ServiceMovement{{Service: rest.ServiceData, To: []string{"host1"}}}, changes.Movements)
+}
+
+func TestDiffAddedRemoved(t *testing.T) {
+	var (
+		old  = &rest.ClusterConfig{Revision: 1, Nodes: rest.Nodes{node1, node2}}
+		curr = &rest.ClusterConfig{Revision: 2, Nodes: rest.Nodes{node2, node3}}
+	)
+
+	changes := Diff(old, curr)
+	require.Equal(t, rest.Nodes{node3.Copy()}, rest.Nodes(changes.Added))
+	require.Equal(t, rest.Nodes{node1.Copy()}, rest.Nodes(changes.Removed))
+	require.Empty(t, changes.Changed)
+
+	expected := []ServiceMovement{{Service: rest.ServiceData, From: []string{"host1"}, To: []string{"host3"}}}
+	require.Equal(t, expected, changes.Movements)
+
+	expectedSummary := `revision 1 -> 2: 1 node(s) added, 1 node(s) removed, 0 node(s) changed
+  + host3 [Data]
+  - host1 [Data]
+  > Data moved from [host1] to [host3]`
+
+	require.Equal(t, expectedSummary, changes.Summary())
+}
+
+func TestDiffChanged(t *testing.T) {
+	var (
+		services = &rest.Services{Management: 8091, N1QL: 8093, FullText: 8094}
+		renamed  = &rest.Node{UUID: "1", Hostname: "renamed", Services: node1.Services}
+		moved    = &rest.Node{UUID: "2", Hostname: "host2", Services: services}
+		old      = &rest.ClusterConfig{Revision: 1, Nodes: rest.Nodes{node1, node2}}
+		curr     = &rest.ClusterConfig{Revision: 2, Nodes: rest.Nodes{renamed, moved}}
+	)
+
+	changes := Diff(old, curr)
+	require.Empty(t, changes.Added)
+	require.Empty(t, changes.Removed)
+	require.Len(t, changes.Changed, 2)
+
+	require.True(t, changes.Changed[0].HostnameChanged())
+	require.Empty(t, changes.Changed[0].ServicesAdded)
+	require.Equal(t, "renamed hostname 'host1' -> 'renamed'", changes.Changed[0].String())
+
+	require.False(t, changes.Changed[1].HostnameChanged())
+	require.Equal(t, []rest.Service{rest.ServiceSearch}, changes.Changed[1].ServicesAdded)
+	require.Empty(t, changes.Changed[1].ServicesRemoved)
+	require.Equal(t, "host2 services added [Search]", changes.Changed[1].String())
+
+	expected := []ServiceMovement{
+		{Service: rest.ServiceData, From: []string{"host1"}, To: []string{"renamed"}},
+		{Service: rest.ServiceSearch, To: []string{"host2"}},
+	}
+
+	require.Equal(t, expected, changes.Movements)
+}
+
+func TestDiffAddressingChanged(t *testing.T) {
+	var (
+		external = &rest.Node{
+			UUID:               "1",
+			Hostname:           "host1",
+			Services:           node1.Services,
+			AlternateAddresses: rest.AlternateAddresses{External: &rest.External{Hostname: "external"}},
+		}
+		old  = &rest.ClusterConfig{Revision: 1, Nodes: rest.Nodes{node1}}
+		curr = &rest.ClusterConfig{Revision: 2, Nodes: rest.Nodes{external}}
+	)
+
+	changes := Diff(old, curr)
+	require.Len(t, changes.Changed, 1)
+	require.Empty(t, changes.Movements)
+	require.Equal(t, "host1 addressing changed", changes.Changed[0].String())
+}
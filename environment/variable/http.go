@@ -4,7 +4,6 @@ package variable
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
 	netutil "github.com/couchbase/tools-common/http/util"
 	"github.com/couchbase/tools-common/types/v2/ptr"
@@ -33,7 +32,7 @@ func GetHTTPTimeouts(envVar string, defaults netutil.HTTPTimeouts) (netutil.HTTP
 
 // getHTTPTimeoutsFromEnv returns the timeouts that should be used for a HTTP client from the environment.
 func getHTTPTimeoutsFromEnv(envVar string) (netutil.HTTPTimeouts, error) {
-	env, ok := os.LookupEnv(envVar)
+	env, ok := lookup(envVar)
 	if !ok {
 		return netutil.HTTPTimeouts{}, nil
 	}
@@ -0,0 +1,313 @@
+package variable
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	// SecretFileSuffix is appended to the name of a variable to get the name of the variable containing the path to a
+	// file which contains its value e.g. 'CB_PASSWORD_FILE'.
+	SecretFileSuffix = "_FILE"
+
+	// SecretCommandSuffix is appended to the name of a variable to get the name of the variable containing a command
+	// which outputs its value e.g. 'CB_PASSWORD_CMD'.
+	SecretCommandSuffix = "_CMD"
+
+	// DefaultSecretCommandTimeout is the default amount of time a secret command may run for before it's killed.
+	DefaultSecretCommandTimeout = 30 * time.Second
+
+	// DefaultSecretCommandCacheTTL is the default amount of time the output of a secret command is cached for.
+	DefaultSecretCommandCacheTTL = 5 * time.Minute
+)
+
+var (
+	// ErrAmbiguousSecret is returned when more than one of the variables which may be used to provide a secret are set.
+	ErrAmbiguousSecret = errors.New("secret provided by more than one variable")
+
+	// ErrSecretCommandNotAllowed is returned when a secret is provided using a command, but running commands hasn't
+	// been allowed.
+	ErrSecretCommandNotAllowed = errors.New("secret commands are not allowed")
+
+	// ErrInvalidSecretCommand is returned when a secret command is empty, or contains an unterminated quote/escape.
+	ErrInvalidSecretCommand = errors.New("invalid secret command")
+)
+
+// SecretOptions encapsulates the options available when getting a secret from the environment.
+type SecretOptions struct {
+	// AllowCommand allows running the command given by the '_CMD' variable, this should only be enabled where the
+	// environment is trusted.
+	AllowCommand bool
+
+	// CommandTimeout is the maximum amount of time the command may run for, defaults to 'DefaultSecretCommandTimeout'.
+	CommandTimeout time.Duration
+
+	// CommandCacheTTL is the amount of time the output of the command is cached for, defaults to
+	// 'DefaultSecretCommandCacheTTL'. A negative value disables caching, running the command each time.
+	CommandCacheTTL time.Duration
+}
+
+// defaults fills any missing attributes to a sane default.
+func (s *SecretOptions) defaults() {
+	if s.CommandTimeout == 0 {
+		s.CommandTimeout = DefaultSecretCommandTimeout
+	}
+
+	if s.CommandCacheTTL == 0 {
+		s.CommandCacheTTL = DefaultSecretCommandCacheTTL
+	}
+}
+
+// cachedSecret is a resolved secret, along with the information required to determine whether it's stale.
+type cachedSecret struct {
+	value string
+
+	// modTime/size are the modification time and size of the file the secret was read from.
+	modTime time.Time
+	size    int64
+
+	// expires is the time at which the output of a command should be discarded.
+	expires time.Time
+}
+
+// secrets caches resolved secrets, keyed by their source so that changing the variable results in it being resolved
+// again.
+var secrets sync.Map
+
+// GetSecret returns the value of a secret, such as a password or token, provided by one of the following variables:
+//  1. varName, containing the value itself
+//  2. varName_FILE, containing the path to a file which contains the value
+//  3. varName_CMD, containing a command whose output is the value; the command is split into arguments using shell
+//     quoting rules, but is run without a shell so expansions/pipes etc. aren't supported
+//
+// Trailing newlines are removed from values read from a file/command output. Values read from a file are cached until
+// the modification time/size of the file changes, whilst command output is cached for 'CommandCacheTTL'.
+//
+// NOTE: The value of the secret is never included in returned errors, and the output of a failed command is discarded.
+func GetSecret(varName string, opts SecretOptions) (string, bool, error) {
+	opts.defaults()
+
+	value, hasValue := os.LookupEnv(varName)
+	path, hasFile := os.LookupEnv(varName + SecretFileSuffix)
+	command, hasCommand := os.LookupEnv(varName + SecretCommandSuffix)
+
+	n := count(hasValue, hasFile, hasCommand)
+	if n == 0 {
+		return "", false, nil
+	}
+
+	if n > 1 {
+		return "", false, fmt.Errorf("%w: '%s'", ErrAmbiguousSecret, varName)
+	}
+
+	if hasValue {
+		return value, true, nil
+	}
+
+	if hasCommand && !opts.AllowCommand {
+		return "", false, fmt.Errorf("%w: '%s'", ErrSecretCommandNotAllowed, varName+SecretCommandSuffix)
+	}
+
+	var err error
+	if hasFile {
+		value, err = readSecretFile(path)
+	} else {
+		value, err = runSecretCommand(command, opts.CommandTimeout, opts.CommandCacheTTL)
+	}
+
+	if err != nil {
+		return "", false, fmt.Errorf("failed to resolve secret '%s': %w", varName, err)
+	}
+
+	return value, true, nil
+}
+
+// Lookup returns the value of the given variable, which may be provided using a '_FILE' variable; unlike 'GetSecret',
+// the variable itself takes precedence if both are set. An error is returned if the '_FILE' variable is used, but the
+// file can't be read.
+//
+// NOTE: Commands aren't allowed, as they must be explicitly enabled using 'GetSecret'.
+func Lookup(varName string) (string, bool, error) {
+	value, ok := os.LookupEnv(varName)
+	if ok {
+		return value, true, nil
+	}
+
+	path, ok := os.LookupEnv(varName + SecretFileSuffix)
+	if !ok {
+		return "", false, nil
+	}
+
+	value, err := readSecretFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read value of '%s': %w", varName+SecretFileSuffix, err)
+	}
+
+	return value, true, nil
+}
+
+// lookup returns the value of the given variable using 'Lookup', this is used by the typed getters so that any value
+// may be provided using a file. Errors are logged so that misconfiguration isn't silently ignored, use 'Lookup' where
+// the error should be handled.
+func lookup(varName string) (string, bool) {
+	value, ok, err := Lookup(varName)
+	if err != nil {
+		slog.Error("failed to lookup environment variable", "name", varName, "error", err)
+	}
+
+	return value, ok
+}
+
+// readSecretFile returns the value of a secret stored in the file at the given path.
+func readSecretFile(path string) (string, error) {
+	// The file is stat'd before it's read, so a concurrent modification results in it being read again next time
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	key := "file:" + path
+
+	if cached, ok := secrets.Load(key); ok {
+		if secret := cached.(cachedSecret); secret.modTime.Equal(info.ModTime()) && secret.size == info.Size() {
+			return secret.value, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	value := strings.TrimRight(string(data), "\r\n")
+
+	secrets.Store(key, cachedSecret{value: value, modTime: info.ModTime(), size: info.Size()})
+
+	return value, nil
+}
+
+// runSecretCommand returns the value of a secret output by the given command.
+func runSecretCommand(command string, timeout, ttl time.Duration) (string, error) {
+	key := "cmd:" + command
+
+	if cached, ok := secrets.Load(key); ok && ttl > 0 && time.Now().Before(cached.(cachedSecret).expires) {
+		return cached.(cachedSecret).value, nil
+	}
+
+	args, err := splitCommand(command)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+
+	err = cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("failed to run command '%s': %w", args[0], err)
+	}
+
+	value := strings.TrimRight(stdout.String(), "\r\n")
+
+	if ttl > 0 {
+		secrets.Store(key, cachedSecret{value: value, expires: time.Now().Add(ttl)})
+	}
+
+	return value, nil
+}
+
+// splitCommand splits the given command into its arguments using shell quoting rules; arguments are separated by
+// whitespace, which may be quoted using single/double quotes or escaped using a backslash.
+//
+// NOTE: Only quoting is supported, variables/globs etc. are not expanded.
+func splitCommand(command string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range command {
+		switch {
+		case escaped:
+			// Within double quotes, a backslash only escapes characters which are otherwise special
+			if quote == '"' && !strings.ContainsRune(`"\$`+"`", r) {
+				current.WriteRune('\\')
+			}
+
+			current.WriteRune(r)
+
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inArg = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+			}
+
+			inArg = false
+		default:
+			current.WriteRune(r)
+
+			inArg = true
+		}
+	}
+
+	if escaped || quote != 0 {
+		return nil, fmt.Errorf("%w: unterminated quote or escape", ErrInvalidSecretCommand)
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: command is empty", ErrInvalidSecretCommand)
+	}
+
+	return args, nil
+}
+
+// count returns the number of the given booleans which are true.
+func count(values ...bool) int {
+	var n int
+
+	for _, value := range values {
+		if value {
+			n++
+		}
+	}
+
+	return n
+}
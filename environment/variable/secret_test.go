@@ -0,0 +1,262 @@
+package variable
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+
+	type test struct {
+		name     string
+		env      map[string]string
+		opts     SecretOptions
+		expected string
+		ok       bool
+		err      error
+	}
+
+	tests := []*test{
+		{
+			name: "NotSet",
+		},
+		{
+			name:     "Value",
+			env:      map[string]string{"CB_TEST_SECRET": "value"},
+			expected: "value",
+			ok:       true,
+		},
+		{
+			name:     "File",
+			env:      map[string]string{"CB_TEST_SECRET_FILE": path},
+			expected: "from-file",
+			ok:       true,
+		},
+		{
+			name:     "Command",
+			env:      map[string]string{"CB_TEST_SECRET_CMD": "echo from-cmd"},
+			opts:     SecretOptions{AllowCommand: true},
+			expected: "from-cmd",
+			ok:       true,
+		},
+		{
+			name: "CommandNotAllowed",
+			env:  map[string]string{"CB_TEST_SECRET_CMD": "echo from-cmd"},
+			err:  ErrSecretCommandNotAllowed,
+		},
+		{
+			name: "Ambiguous",
+			env:  map[string]string{"CB_TEST_SECRET": "value", "CB_TEST_SECRET_FILE": path},
+			err:  ErrAmbiguousSecret,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+
+			value, ok, err := GetSecret("CB_TEST_SECRET", test.opts)
+			require.ErrorIs(t, err, test.err)
+			require.Equal(t, test.ok, ok)
+			require.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func TestGetSecretFileCached(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(path, []byte("first"), 0o600))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	t.Setenv("CB_TEST_SECRET_CACHED_FILE", path)
+
+	value, ok, err := GetSecret("CB_TEST_SECRET_CACHED", SecretOptions{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "first", value)
+
+	// The file is unchanged according to its modification time/size, so the cached value should be returned
+	require.NoError(t, os.WriteFile(path, []byte("other"), 0o600))
+	require.NoError(t, os.Chtimes(path, info.ModTime(), info.ModTime()))
+
+	value, ok, err = GetSecret("CB_TEST_SECRET_CACHED", SecretOptions{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "first", value)
+}
+
+func TestGetSecretFileChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(path, []byte("first"), 0o600))
+
+	t.Setenv("CB_TEST_SECRET_CHANGED_FILE", path)
+
+	value, ok, err := GetSecret("CB_TEST_SECRET_CHANGED", SecretOptions{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "first", value)
+
+	// Ensure the modification time changes, even on filesystems with a coarse resolution
+	require.NoError(t, os.WriteFile(path, []byte("second"), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+	value, ok, err = GetSecret("CB_TEST_SECRET_CHANGED", SecretOptions{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "second", value)
+}
+
+func TestGetSecretCommandCached(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "count")
+
+	t.Setenv("CB_TEST_SECRET_COUNT_CMD", fmt.Sprintf(`sh -c 'echo run >> "%s"; wc -l < "%s"'`, path, path))
+
+	get := func(opts SecretOptions) string {
+		value, ok, err := GetSecret("CB_TEST_SECRET_COUNT", opts)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		return strings.TrimSpace(value)
+	}
+
+	require.Equal(t, "1", get(SecretOptions{AllowCommand: true}))
+	require.Equal(t, "1", get(SecretOptions{AllowCommand: true}))
+	require.Equal(t, "2", get(SecretOptions{AllowCommand: true, CommandCacheTTL: -1}))
+}
+
+func TestGetSecretCommandFailed(t *testing.T) {
+	t.Setenv("CB_TEST_SECRET_FAILED_CMD", "sh -c exit-with-failure")
+
+	_, ok, err := GetSecret("CB_TEST_SECRET_FAILED", SecretOptions{AllowCommand: true})
+	require.Error(t, err)
+	require.False(t, ok)
+}
+
+func TestGetSecretCommandInvalid(t *testing.T) {
+	t.Setenv("CB_TEST_SECRET_INVALID_CMD", "echo 'unterminated")
+
+	_, ok, err := GetSecret("CB_TEST_SECRET_INVALID", SecretOptions{AllowCommand: true})
+	require.ErrorIs(t, err, ErrInvalidSecretCommand)
+	require.False(t, ok)
+}
+
+func TestSplitCommand(t *testing.T) {
+	type test struct {
+		name     string
+		command  string
+		expected []string
+		err      error
+	}
+
+	tests := []*test{
+		{
+			name:     "Simple",
+			command:  "cat /path/to/secret",
+			expected: []string{"cat", "/path/to/secret"},
+		},
+		{
+			name:     "ExtraWhitespace",
+			command:  "  cat \t /path/to/secret  ",
+			expected: []string{"cat", "/path/to/secret"},
+		},
+		{
+			name:     "SingleQuoted",
+			command:  `cat '/path/with spaces/\secret'`,
+			expected: []string{"cat", `/path/with spaces/\secret`},
+		},
+		{
+			name:     "DoubleQuoted",
+			command:  `echo "a \"quoted\" \$value \n"`,
+			expected: []string{"echo", `a "quoted" $value \n`},
+		},
+		{
+			name:     "Escaped",
+			command:  `cat /path/with\ spaces`,
+			expected: []string{"cat", "/path/with spaces"},
+		},
+		{
+			name:     "EmptyQuoted",
+			command:  `echo ''`,
+			expected: []string{"echo", ""},
+		},
+		{
+			name:     "Adjacent",
+			command:  `echo a'b'"c"`,
+			expected: []string{"echo", "abc"},
+		},
+		{
+			name:    "Empty",
+			command: "  ",
+			err:     ErrInvalidSecretCommand,
+		},
+		{
+			name:    "UnterminatedQuote",
+			command: `echo "value`,
+			err:     ErrInvalidSecretCommand,
+		},
+		{
+			name:    "TrailingEscape",
+			command: `echo value\`,
+			err:     ErrInvalidSecretCommand,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args, err := splitCommand(test.command)
+			require.ErrorIs(t, err, test.err)
+			require.Equal(t, test.expected, args)
+		})
+	}
+}
+
+func TestGetIntFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "value")
+	require.NoError(t, os.WriteFile(path, []byte("42\n"), 0o600))
+
+	t.Setenv("CB_TEST_INT_FILE", path)
+
+	value, ok := GetInt("CB_TEST_INT")
+	require.True(t, ok)
+	require.Equal(t, 42, value)
+}
+
+func TestLookupPrefersValue(t *testing.T) {
+	t.Setenv("CB_TEST_INT", "42")
+	t.Setenv("CB_TEST_INT_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	value, ok, err := Lookup("CB_TEST_INT")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "42", value)
+
+	parsed, ok := GetInt("CB_TEST_INT")
+	require.True(t, ok)
+	require.Equal(t, 42, parsed)
+}
+
+func TestLookupFileMissing(t *testing.T) {
+	t.Setenv("CB_TEST_INT_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	_, ok, err := Lookup("CB_TEST_INT")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.False(t, ok)
+}
+
+func TestLookupUnset(t *testing.T) {
+	_, ok, err := Lookup("CB_TEST_UNSET")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -1,7 +1,6 @@
 package variable
 
 import (
-	"os"
 	"strconv"
 	"time"
 
@@ -11,7 +10,7 @@ import (
 // GetInt returns the int value of the environmental variable varName  if the env var is not an int or empty it will
 // return 0, false.
 func GetInt(varName string) (int, bool) {
-	env, ok := os.LookupEnv(varName)
+	env, ok := lookup(varName)
 	if !ok {
 		return 0, false
 	}
@@ -27,7 +26,7 @@ func GetInt(varName string) (int, bool) {
 // GetUint64 returns the uint64 value of the environmental variable varName if the env var is not an int or empty it
 // will return 0, false.
 func GetUint64(varName string) (uint64, bool) {
-	env, ok := os.LookupEnv(varName)
+	env, ok := lookup(varName)
 	if !ok {
 		return 0, false
 	}
@@ -43,7 +42,7 @@ func GetUint64(varName string) (uint64, bool) {
 // GetBool returns the boolean value of the environmental variable varName  if the env var is empty or not a boolean it
 // will return false, false.
 func GetBool(varName string) (bool, bool) {
-	val, ok := os.LookupEnv(varName)
+	val, ok := lookup(varName)
 	if !ok {
 		return false, false
 	}
@@ -59,7 +58,7 @@ func GetBool(varName string) (bool, bool) {
 // GetDuration returns the time.Duration value of the environmental variable varName if the env var is empty or not a
 // valid duration string it will return 0, false.
 func GetDuration(varName string) (time.Duration, bool) {
-	val, ok := os.LookupEnv(varName)
+	val, ok := lookup(varName)
 	if !ok {
 		return 0, false
 	}
@@ -94,7 +93,9 @@ func GetDurationBC(varName string) (time.Duration, bool) {
 // GetBytes returns the number of bytes represented by the environmental variable varName if the env var is empty or not
 // a valid byte string it will return 0, false.
 func GetBytes(varName string) (uint64, bool) {
-	b, err := parse.Bytes(os.Getenv(varName))
+	val, _ := lookup(varName)
+
+	b, err := parse.Bytes(val)
 	if err != nil {
 		return 0, false
 	}
@@ -104,7 +105,7 @@ func GetBytes(varName string) (uint64, bool) {
 
 // GetFloat64 returns the float64 value of the environmental variable varName if the env var is empty or not a valid.
 func GetFloat64(varName string) (float64, bool) {
-	val, ok := os.LookupEnv(varName)
+	val, ok := lookup(varName)
 	if !ok {
 		return 0, false
 	}
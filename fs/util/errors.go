@@ -8,4 +8,18 @@ var (
 
 	// ErrNotDir is returned by 'DirExists' if a file exists at the provided path.
 	ErrNotDir = errors.New("not a directory")
+
+	// ErrLocked is returned when attempting to acquire a lock which is already held by the same 'Lock'.
+	ErrLocked = errors.New("lock is already held")
+
+	// ErrNotLocked is returned when attempting to release a lock which is not held.
+	ErrNotLocked = errors.New("lock is not held")
+
+	// ErrLockLost is returned when releasing a lock file which was removed/replaced by another process whilst the lock
+	// was held e.g. because it was considered stale.
+	ErrLockLost = errors.New("lock file was removed or replaced by another process")
+
+	// ErrAdvisoryLockNotSupported is returned when attempting to take an advisory lock on a platform which doesn't
+	// support them, a lock file should be used instead.
+	ErrAdvisoryLockNotSupported = errors.New("advisory locks are not supported on this platform")
 )
@@ -0,0 +1,373 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultLockStaleAfter is the default duration after which a lock file which hasn't been refreshed by its owner is
+	// considered stale.
+	DefaultLockStaleAfter = 30 * time.Second
+
+	// DefaultLockPollInterval is the default interval at which 'Lock' attempts to acquire a lock which is held
+	// elsewhere.
+	DefaultLockPollInterval = 100 * time.Millisecond
+)
+
+// LockOptions encapsulates the options available when creating a lock.
+type LockOptions struct {
+	// LockFile indicates that the lock should be acquired by exclusively creating a lock file containing the pid of the
+	// owning process, rather than by taking an advisory lock. This should be used when the lock may be shared by
+	// processes on different hosts, for example when locking a directory on NFS where advisory locks are unreliable.
+	LockFile bool
+
+	// StaleAfter is the duration after which a lock file which hasn't been refreshed by its owner is considered stale,
+	// and may be removed by another process. Owners refresh the lock file periodically whilst the lock is held.
+	// Defaults to 'DefaultLockStaleAfter'.
+	//
+	// NOTE: Only applicable when using a lock file.
+	StaleAfter time.Duration
+
+	// PollInterval is the interval at which 'Lock' attempts to acquire a lock which is held elsewhere. Defaults to
+	// 'DefaultLockPollInterval'.
+	PollInterval time.Duration
+}
+
+// defaults fills any missing attributes to a sane default.
+func (l *LockOptions) defaults() {
+	if l.StaleAfter <= 0 {
+		l.StaleAfter = DefaultLockStaleAfter
+	}
+
+	if l.PollInterval <= 0 {
+		l.PollInterval = DefaultLockPollInterval
+	}
+}
+
+// Lock is an inter-process lock, backed by a file at the given path. By default the lock is an advisory lock (using
+// 'flock' on Unix and 'LockFileEx' on Windows) which is released by the OS if the owning process exits; the file is
+// not removed when the lock is released.
+//
+// NOTE: A lock must not be used concurrently by multiple goroutines, each should create its own lock.
+type Lock struct {
+	path string
+	opts LockOptions
+
+	file *os.File
+
+	// contents is what we wrote to the lock file, used to detect whether it has been removed/replaced by another
+	// process whilst we hold the lock.
+	contents []byte
+	lost     atomic.Bool
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewLock returns a new lock, backed by the file at the given path; the lock is not acquired.
+func NewLock(path string, opts LockOptions) *Lock {
+	opts.defaults()
+
+	return &Lock{path: path, opts: opts}
+}
+
+// TryLock attempts to acquire the lock without blocking, returning a boolean indicating whether it was acquired.
+func (l *Lock) TryLock() (bool, error) {
+	if l.file != nil || l.stop != nil {
+		return false, ErrLocked
+	}
+
+	if l.opts.LockFile {
+		return l.tryLockFile()
+	}
+
+	return l.tryLockAdvisory()
+}
+
+// Lock acquires the lock, blocking until it's acquired or the given context is cancelled.
+func (l *Lock) Lock(ctx context.Context) error {
+	ticker := time.NewTicker(l.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		locked, err := l.TryLock()
+		if err != nil || locked {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Unlock releases the lock, returning an 'ErrNotLocked' error if the lock is not held.
+//
+// NOTE: When using a lock file, an 'ErrLockLost' error is returned if the lock file was removed/replaced by another
+// process whilst the lock was held; the lock is still released, but the lock file is left in place.
+func (l *Lock) Unlock() error {
+	if l.opts.LockFile {
+		return l.unlockFile()
+	}
+
+	return l.unlockAdvisory()
+}
+
+// tryLockAdvisory attempts to take an advisory lock on the lock file.
+func (l *Lock) tryLockAdvisory() (bool, error) {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, DefaultFileMode)
+	if err != nil {
+		return false, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	locked, err := tryLockFile(file)
+	if err != nil || !locked {
+		file.Close()
+		return false, err
+	}
+
+	l.file = file
+
+	return true, nil
+}
+
+// unlockAdvisory releases the advisory lock on the lock file.
+func (l *Lock) unlockAdvisory() error {
+	if l.file == nil {
+		return ErrNotLocked
+	}
+
+	defer func() { l.file = nil }()
+
+	err := unlockFile(l.file)
+	if err != nil {
+		l.file.Close()
+		return fmt.Errorf("failed to unlock file: %w", err)
+	}
+
+	return l.file.Close()
+}
+
+// tryLockFile attempts to exclusively create the lock file, removing the existing lock file if it's stale.
+func (l *Lock) tryLockFile() (bool, error) {
+	locked, err := l.createLockFile()
+	if err != nil || locked {
+		return locked, err
+	}
+
+	stale, observed, err := l.stale()
+	if err != nil || !stale {
+		return false, err
+	}
+
+	removed, err := l.removeUnchanged(observed)
+	if err != nil || !removed {
+		return false, err
+	}
+
+	// We make a single further attempt, if another process has acquired the lock since we removed the stale lock file
+	// we'll fail to create it.
+	return l.createLockFile()
+}
+
+// removeUnchanged atomically moves the lock file (with the given contents) aside before removing it, so that if
+// multiple processes consider it stale only one of them will remove it. Returns a boolean indicating whether the lock
+// file was removed; it's not removed if it has been replaced since its contents were observed.
+func (l *Lock) removeUnchanged(observed []byte) (bool, error) {
+	aside := fmt.Sprintf("%s.stale.%d.%d", l.path, os.Getpid(), time.Now().UnixNano())
+
+	err := os.Rename(l.path, aside)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to move lock file: %w", err)
+	}
+
+	defer os.Remove(aside)
+
+	data, err := os.ReadFile(aside)
+	if err != nil {
+		return false, fmt.Errorf("failed to read moved lock file: %w", err)
+	}
+
+	if bytes.Equal(data, observed) {
+		return true, nil
+	}
+
+	// Another process removed the lock file and acquired the lock before we moved it, so we must put it back; a
+	// link is used so that we don't replace a lock file which has been created in the meantime.
+	err = os.Link(aside, l.path)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		return false, fmt.Errorf("failed to restore lock file: %w", err)
+	}
+
+	return false, nil
+}
+
+// createLockFile exclusively creates the lock file, returning false if it already exists. The lock file contains the
+// pid of this process, the hostname and a random token unique to this acquisition of the lock.
+func (l *Lock) createLockFile() (bool, error) {
+	token := make([]byte, 16)
+
+	_, err := rand.Read(token)
+	if err != nil {
+		return false, fmt.Errorf("failed to generate lock token: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+
+	contents := []byte(fmt.Sprintf("%d\n%s\n%s\n", os.Getpid(), hostname, hex.EncodeToString(token)))
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, DefaultFileMode)
+	if errors.Is(err, os.ErrExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to create lock file: %w", err)
+	}
+
+	_, err = file.Write(contents)
+	if err != nil {
+		file.Close()
+		os.Remove(l.path)
+
+		return false, fmt.Errorf("failed to write lock file: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		os.Remove(l.path)
+		return false, fmt.Errorf("failed to close lock file: %w", err)
+	}
+
+	l.contents = contents
+	l.lost.Store(false)
+	l.stop = make(chan struct{})
+
+	l.wg.Add(1)
+
+	go l.refresh()
+
+	return true, nil
+}
+
+// refresh periodically updates the modification time of the lock file, so that other processes don't consider it
+// stale whilst we're holding the lock. Refreshing stops if the lock file is no longer ours, so that we don't keep
+// another process' lock file fresh.
+func (l *Lock) refresh() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(l.opts.StaleAfter / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if !l.owned() {
+				l.lost.Store(true)
+				return
+			}
+
+			now := time.Now()
+			_ = os.Chtimes(l.path, now, now)
+		}
+	}
+}
+
+// unlockFile stops refreshing, and removes the lock file if it's still ours.
+func (l *Lock) unlockFile() error {
+	if l.stop == nil {
+		return ErrNotLocked
+	}
+
+	close(l.stop)
+	l.wg.Wait()
+
+	l.stop = nil
+
+	if l.lost.Load() || !l.owned() {
+		return ErrLockLost
+	}
+
+	// The lock file is moved aside before being removed, so that we don't remove a lock file which replaced ours since
+	// we checked that it's still ours.
+	removed, err := l.removeUnchanged(l.contents)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// owned returns a boolean indicating whether the lock file is the one we created, by comparing its pid, hostname and
+// token with the ones we wrote.
+func (l *Lock) owned() bool {
+	data, err := os.ReadFile(l.path)
+
+	return err == nil && bytes.Equal(data, l.contents)
+}
+
+// stale returns a boolean indicating whether the existing lock file is stale, along with its contents; a lock file is
+// stale if it hasn't been refreshed recently, or if it was created by a process on this host which is no longer
+// running.
+func (l *Lock) stale() (bool, []byte, error) {
+	// The contents are read first, so that if the lock file is replaced before we stat it, we'll see that it's fresh
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil, nil
+	}
+
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	stats, err := os.Stat(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil, nil
+	}
+
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to stat lock file: %w", err)
+	}
+
+	if time.Since(stats.ModTime()) > l.opts.StaleAfter {
+		return true, data, nil
+	}
+
+	// The lock file may not have been written yet, in which case we rely on the modification time
+	fields := strings.Split(string(data), "\n")
+	if len(fields) < 2 {
+		return false, nil, nil
+	}
+
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return false, nil, nil
+	}
+
+	hostname, _ := os.Hostname()
+
+	return fields[1] == hostname && !processExists(pid), data, nil
+}
@@ -0,0 +1,23 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly,!windows
+
+package util
+
+import "os"
+
+// tryLockFile is a fallback function which will be run if no OS specific function exists; advisory locks aren't
+// supported, so a lock file should be used instead.
+func tryLockFile(_ *os.File) (bool, error) {
+	return false, ErrAdvisoryLockNotSupported
+}
+
+// unlockFile is a fallback function which will be run if no OS specific function exists.
+func unlockFile(_ *os.File) error {
+	return ErrAdvisoryLockNotSupported
+}
+
+// processExists is a fallback function which will be run if no OS specific function exists; we can't determine
+// whether the process is running, so we conservatively assume that it is.
+func processExists(_ int) bool {
+	return true
+}
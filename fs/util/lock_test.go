@@ -0,0 +1,237 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockAdvisory(t *testing.T) {
+	var (
+		path   = filepath.Join(t.TempDir(), "lock")
+		first  = NewLock(path, LockOptions{})
+		second = NewLock(path, LockOptions{})
+	)
+
+	locked, err := first.TryLock()
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	_, err = first.TryLock()
+	require.ErrorIs(t, err, ErrLocked)
+
+	locked, err = second.TryLock()
+	require.NoError(t, err)
+	require.False(t, locked)
+
+	require.NoError(t, first.Unlock())
+	require.ErrorIs(t, first.Unlock(), ErrNotLocked)
+
+	locked, err = second.TryLock()
+	require.NoError(t, err)
+	require.True(t, locked)
+	require.NoError(t, second.Unlock())
+
+	// The file isn't removed for advisory locks
+	require.FileExists(t, path)
+}
+
+func TestLockBlocks(t *testing.T) {
+	for _, lockFile := range []bool{false, true} {
+		t.Run(fmt.Sprintf("LockFile=%t", lockFile), func(t *testing.T) {
+			var (
+				path   = filepath.Join(t.TempDir(), "lock")
+				opts   = LockOptions{LockFile: lockFile, PollInterval: 10 * time.Millisecond}
+				first  = NewLock(path, opts)
+				second = NewLock(path, opts)
+			)
+
+			require.NoError(t, first.Lock(context.Background()))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			require.ErrorIs(t, second.Lock(ctx), context.DeadlineExceeded)
+
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				require.NoError(t, first.Unlock())
+			}()
+
+			require.NoError(t, second.Lock(context.Background()))
+			require.NoError(t, second.Unlock())
+		})
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	var (
+		path   = filepath.Join(t.TempDir(), "lock")
+		first  = NewLock(path, LockOptions{LockFile: true})
+		second = NewLock(path, LockOptions{LockFile: true})
+	)
+
+	locked, err := first.TryLock()
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	hostname, _ := os.Hostname()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), fmt.Sprintf("%d\n%s\n", os.Getpid(), hostname))
+
+	// The owning process is running, and the lock file is fresh
+	locked, err = second.TryLock()
+	require.NoError(t, err)
+	require.False(t, locked)
+
+	require.NoError(t, first.Unlock())
+	require.NoFileExists(t, path)
+
+	require.ErrorIs(t, first.Unlock(), ErrNotLocked)
+}
+
+func TestLockFileStale(t *testing.T) {
+	t.Run("NotRefreshed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "lock")
+
+		require.NoError(t, os.WriteFile(path, []byte("1\nanother-host\n0\n"), DefaultFileMode))
+
+		old := time.Now().Add(-time.Minute)
+		require.NoError(t, os.Chtimes(path, old, old))
+
+		lock := NewLock(path, LockOptions{LockFile: true})
+
+		locked, err := lock.TryLock()
+		require.NoError(t, err)
+		require.True(t, locked)
+		require.NoError(t, lock.Unlock())
+	})
+
+	t.Run("ProcessNotRunning", func(t *testing.T) {
+		var (
+			path        = filepath.Join(t.TempDir(), "lock")
+			hostname, _ = os.Hostname()
+		)
+
+		data := fmt.Sprintf("%d\n%s\n%d\n", math.MaxInt32, hostname, time.Now().Unix())
+		require.NoError(t, os.WriteFile(path, []byte(data), DefaultFileMode))
+
+		lock := NewLock(path, LockOptions{LockFile: true})
+
+		locked, err := lock.TryLock()
+		require.NoError(t, err)
+		require.True(t, locked)
+		require.NoError(t, lock.Unlock())
+	})
+
+	t.Run("AnotherHost", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "lock")
+
+		data := fmt.Sprintf("%d\nanother-host\n%d\n", math.MaxInt32, time.Now().Unix())
+		require.NoError(t, os.WriteFile(path, []byte(data), DefaultFileMode))
+
+		lock := NewLock(path, LockOptions{LockFile: true})
+
+		locked, err := lock.TryLock()
+		require.NoError(t, err)
+		require.False(t, locked)
+	})
+}
+
+func TestLockFileRefresh(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "lock")
+		opts = LockOptions{LockFile: true, StaleAfter: 150 * time.Millisecond}
+		lock = NewLock(path, opts)
+	)
+
+	locked, err := lock.TryLock()
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	defer lock.Unlock()
+
+	// The lock is held for longer than the stale duration, but is being refreshed
+	time.Sleep(300 * time.Millisecond)
+
+	locked, err = NewLock(path, opts).TryLock()
+	require.NoError(t, err)
+	require.False(t, locked)
+}
+
+func TestLockFileStolen(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "lock")
+		lock = NewLock(path, LockOptions{LockFile: true, StaleAfter: 150 * time.Millisecond})
+	)
+
+	locked, err := lock.TryLock()
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	// Another process considers the lock stale, and replaces the lock file with its own
+	stolen := []byte("2\nanother-host\n0123456789abcdef\n")
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, os.WriteFile(path, stolen, DefaultFileMode))
+
+	old := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	// We should stop refreshing the lock file, as it's no longer ours
+	time.Sleep(150 * time.Millisecond)
+
+	stats, err := os.Stat(path)
+	require.NoError(t, err)
+	require.True(t, stats.ModTime().Equal(old))
+
+	require.ErrorIs(t, lock.Unlock(), ErrLockLost)
+
+	// The other process' lock file must be left in place
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, stolen, data)
+
+	require.ErrorIs(t, lock.Unlock(), ErrNotLocked)
+}
+
+func TestLockFileStaleReplaced(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "lock")
+		lock = NewLock(path, LockOptions{LockFile: true})
+	)
+
+	require.NoError(t, os.WriteFile(path, []byte("1\nanother-host\n0\n"), DefaultFileMode))
+
+	old := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	stale, observed, err := lock.stale()
+	require.NoError(t, err)
+	require.True(t, stale)
+
+	// Another process removes the stale lock file and acquires the lock, before we remove it
+	fresh := []byte("2\nanother-host\n1\n")
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, os.WriteFile(path, fresh, DefaultFileMode))
+
+	removed, err := lock.removeUnchanged(observed)
+	require.NoError(t, err)
+	require.False(t, removed)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, fresh, data)
+
+	// The lock file should be restored, without leaving the moved file behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
@@ -0,0 +1,34 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package util
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// tryLockFile attempts to take an exclusive advisory lock on the given file using 'flock', without blocking.
+func tryLockFile(file *os.File) (bool, error) {
+	err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// unlockFile releases the advisory lock on the given file.
+func unlockFile(file *os.File) error {
+	return unix.Flock(int(file.Fd()), unix.LOCK_UN)
+}
+
+// processExists returns a boolean indicating whether a process with the given pid is running.
+func processExists(pid int) bool {
+	err := unix.Kill(pid, 0)
+
+	// The process exists but is owned by another user
+	return err == nil || errors.Is(err, unix.EPERM)
+}
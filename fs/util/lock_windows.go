@@ -0,0 +1,53 @@
+//go:build windows
+// +build windows
+
+package util
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code reported by 'GetExitCodeProcess' for a process which is still running.
+const stillActive = 259
+
+// tryLockFile attempts to take an exclusive lock on the first byte of the given file using 'LockFileEx', without
+// blocking.
+func tryLockFile(file *os.File) (bool, error) {
+	err := windows.LockFileEx(
+		windows.Handle(file.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0,
+		1,
+		0,
+		&windows.Overlapped{},
+	)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// unlockFile releases the lock on the given file.
+func unlockFile(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, &windows.Overlapped{})
+}
+
+// processExists returns a boolean indicating whether a process with the given pid is running.
+func processExists(pid int) bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// The process exists but is owned by another user
+		return errors.Is(err, windows.ERROR_ACCESS_DENIED)
+	}
+	defer windows.CloseHandle(handle)
+
+	var code uint32
+
+	err = windows.GetExitCodeProcess(handle, &code)
+
+	return err == nil && code == stillActive
+}
@@ -0,0 +1,119 @@
+// Package optional provides a generic optional type, which removes the ambiguity between a zero value and an unset
+// value without resorting to pointers.
+package optional
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Optional represents a value which may or may not be present, the zero value is an absent value.
+//
+// NOTE: Absent values are marshalled as 'null'; the 'omitempty' JSON tag option has no effect on struct fields, so
+// where absent values must be omitted, the field should be a pointer populated using 'Ptr'.
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+// Some returns a present optional containing the given value.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, present: true}
+}
+
+// None returns an absent optional.
+func None[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// FromPtr returns an optional containing the value pointed to by the given pointer, or an absent optional if <nil>.
+func FromPtr[T any](p *T) Optional[T] {
+	if p == nil {
+		return None[T]()
+	}
+
+	return Some(*p)
+}
+
+// Get returns the value, and a boolean indicating whether it's present.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// IsPresent returns a boolean indicating whether the value is present.
+func (o Optional[T]) IsPresent() bool {
+	return o.present
+}
+
+// IsZero returns a boolean indicating whether the value is absent.
+func (o Optional[T]) IsZero() bool {
+	return !o.present
+}
+
+// OrElse returns the value if present, otherwise the given value.
+func (o Optional[T]) OrElse(v T) T {
+	if o.present {
+		return o.value
+	}
+
+	return v
+}
+
+// OrElseFunc returns the value if present, otherwise the result of the given function.
+func (o Optional[T]) OrElseFunc(fn func() T) T {
+	if o.present {
+		return o.value
+	}
+
+	return fn()
+}
+
+// Ptr returns a pointer to a copy of the value if present, otherwise <nil>; this allows interoperability with APIs
+// which use pointers to represent optional values.
+func (o Optional[T]) Ptr() *T {
+	if !o.present {
+		return nil
+	}
+
+	v := o.value
+
+	return &v
+}
+
+// String implements the 'fmt.Stringer' interface.
+func (o Optional[T]) String() string {
+	if !o.present {
+		return "None"
+	}
+
+	return fmt.Sprintf("Some(%v)", o.value)
+}
+
+// MarshalJSON implements the 'json.Marshaler' interface, absent values are marshalled as 'null'.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.present {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON implements the 'json.Unmarshaler' interface, 'null' is unmarshalled as an absent value.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = None[T]()
+		return nil
+	}
+
+	var v T
+
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+
+	*o = Some(v)
+
+	return nil
+}
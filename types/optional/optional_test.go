@@ -0,0 +1,70 @@
+package optional
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptional(t *testing.T) {
+	some := Some(0)
+
+	v, ok := some.Get()
+	require.True(t, ok)
+	require.True(t, some.IsPresent())
+	require.False(t, some.IsZero())
+	require.Equal(t, 0, v)
+	require.Equal(t, 0, some.OrElse(42))
+	require.Equal(t, 0, some.OrElseFunc(func() int { return 42 }))
+	require.Equal(t, 0, *some.Ptr())
+	require.Equal(t, "Some(0)", some.String())
+
+	none := None[int]()
+
+	_, ok = none.Get()
+	require.False(t, ok)
+	require.False(t, none.IsPresent())
+	require.True(t, none.IsZero())
+	require.Equal(t, 42, none.OrElse(42))
+	require.Equal(t, 42, none.OrElseFunc(func() int { return 42 }))
+	require.Nil(t, none.Ptr())
+	require.Equal(t, "None", none.String())
+
+	require.Equal(t, none, Optional[int]{})
+}
+
+func TestFromPtr(t *testing.T) {
+	require.Equal(t, None[string](), FromPtr[string](nil))
+
+	s := "value"
+
+	require.Equal(t, Some("value"), FromPtr(&s))
+}
+
+func TestOptionalPtrCopies(t *testing.T) {
+	some := Some(1)
+
+	*some.Ptr() = 2
+
+	require.Equal(t, 1, some.OrElse(0))
+}
+
+func TestOptionalJSON(t *testing.T) {
+	type payload struct {
+		Present Optional[int] `json:"present"`
+		Absent  Optional[int] `json:"absent"`
+		Omitted *int          `json:"omitted,omitempty"`
+	}
+
+	data, err := json.Marshal(payload{Present: Some(0), Omitted: None[int]().Ptr()})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"present":0,"absent":null}`, string(data))
+
+	var decoded payload
+
+	require.NoError(t, json.Unmarshal([]byte(`{"present":0,"absent":null}`), &decoded))
+	require.Equal(t, payload{Present: Some(0)}, decoded)
+
+	require.Error(t, json.Unmarshal([]byte(`{"present":"string"}`), &decoded))
+}
@@ -0,0 +1,120 @@
+package stats
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/couchbase/tools-common/types/v2/timeprovider"
+)
+
+const (
+	// DefaultEWMAInterval is the default interval at which an 'EWMA' folds new values into its average.
+	DefaultEWMAInterval = 5 * time.Second
+
+	// DefaultEWMAWindow is the default window over which an 'EWMA' averages; this is similar to the one minute load
+	// average reported by Unix systems.
+	DefaultEWMAWindow = time.Minute
+)
+
+// EWMAOptions encapsulates the options available when creating an 'EWMA'.
+type EWMAOptions struct {
+	// Interval is how often values are folded into the moving average.
+	Interval time.Duration
+
+	// Window is the period over which the average is computed, older values decay exponentially.
+	Window time.Duration
+
+	// TimeProvider is used to determine how much time has elapsed, defaults to the system clock.
+	TimeProvider timeprovider.TimeProvider
+}
+
+// defaults fills any missing attributes to a sane default.
+func (e *EWMAOptions) defaults() {
+	if e.Interval <= 0 {
+		e.Interval = DefaultEWMAInterval
+	}
+
+	if e.Window <= 0 {
+		e.Window = DefaultEWMAWindow
+	}
+
+	if e.TimeProvider == nil {
+		e.TimeProvider = timeprovider.CurrentTimeProvider{}
+	}
+}
+
+// EWMA is a thread safe exponentially weighted moving average rate tracker, for example, it may be used to track the
+// throughput of a transfer in bytes per second.
+//
+// NOTE: Updates are applied lazily, there's no background goroutine and therefore nothing to cleanup.
+type EWMA struct {
+	lock sync.Mutex
+
+	interval time.Duration
+	alpha    float64
+
+	uncounted float64
+	rate      float64
+	init      bool
+	last      time.Time
+
+	clock timeprovider.TimeProvider
+}
+
+// NewEWMA returns a new moving average rate tracker using the given options.
+func NewEWMA(options EWMAOptions) *EWMA {
+	options.defaults()
+
+	return &EWMA{
+		interval: options.Interval,
+		alpha:    1 - math.Exp(-options.Interval.Seconds()/options.Window.Seconds()),
+		last:     options.TimeProvider.Now(),
+		clock:    options.TimeProvider,
+	}
+}
+
+// Add records the given number of events (e.g. bytes transferred).
+func (e *EWMA) Add(n float64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.tick()
+
+	e.uncounted += n
+}
+
+// Rate returns the current moving average rate in events per second.
+func (e *EWMA) Rate() float64 {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.tick()
+
+	return e.rate
+}
+
+// tick folds the uncounted events into the moving average for every interval which has elapsed since the last tick.
+//
+// NOTE: The lock must be held by the caller.
+func (e *EWMA) tick() {
+	ticks := e.clock.Since(e.last) / e.interval
+	if ticks <= 0 {
+		return
+	}
+
+	instant := e.uncounted / e.interval.Seconds()
+	e.uncounted = 0
+
+	if e.init {
+		e.rate += e.alpha * (instant - e.rate)
+	} else {
+		e.rate, e.init = instant, true
+	}
+
+	// No events were recorded during the remaining intervals, folding in a zero rate 'n' times is equivalent to folding
+	// it in once using 'alpha = 1-(1-alpha)^n', so we don't need to loop for long idle periods.
+	e.rate -= (1 - math.Pow(1-e.alpha, float64(ticks-1))) * e.rate
+
+	e.last = e.last.Add(ticks * e.interval)
+}
@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/couchbase/tools-common/types/v2/timeprovider"
+)
+
+// newTestEWMA returns an EWMA which uses a fake clock.
+func newTestEWMA(options EWMAOptions) (*EWMA, *timeprovider.FakeClock) {
+	clock := timeprovider.NewFakeClock(time.Now())
+	options.TimeProvider = clock
+
+	return NewEWMA(options), clock
+}
+
+func TestNewEWMADefaults(t *testing.T) {
+	ewma := NewEWMA(EWMAOptions{})
+	require.Equal(t, DefaultEWMAInterval, ewma.interval)
+	require.InDelta(t, 1-math.Exp(-5.0/60.0), ewma.alpha, 1e-9)
+}
+
+func TestEWMARateBeforeFirstTick(t *testing.T) {
+	ewma, _ := newTestEWMA(EWMAOptions{Interval: time.Second, Window: time.Minute})
+	ewma.Add(1024)
+
+	require.Zero(t, ewma.Rate())
+}
+
+func TestEWMARate(t *testing.T) {
+	ewma, now := newTestEWMA(EWMAOptions{Interval: time.Second, Window: time.Minute})
+
+	ewma.Add(100)
+	now.Advance(time.Second)
+
+	// The first interval initializes the rate
+	require.Equal(t, float64(100), ewma.Rate())
+
+	ewma.Add(200)
+	now.Advance(time.Second)
+
+	alpha := 1 - math.Exp(-1.0/60.0)
+	require.InDelta(t, 100+alpha*100, ewma.Rate(), 1e-9)
+}
+
+func TestEWMARateDecaysWhenIdle(t *testing.T) {
+	ewma, now := newTestEWMA(EWMAOptions{Interval: time.Second, Window: time.Second})
+
+	ewma.Add(100)
+	now.Advance(time.Second)
+	require.Equal(t, float64(100), ewma.Rate())
+
+	now.Advance(10 * time.Second)
+	require.Less(t, ewma.Rate(), 0.01)
+}
+
+func TestEWMAPartialInterval(t *testing.T) {
+	ewma, now := newTestEWMA(EWMAOptions{Interval: time.Second, Window: time.Minute})
+
+	ewma.Add(50)
+	now.Advance(500 * time.Millisecond)
+	ewma.Add(50)
+	now.Advance(500 * time.Millisecond)
+
+	require.Equal(t, float64(100), ewma.Rate())
+}
+
+func TestEWMARateDecayClosedForm(t *testing.T) {
+	var (
+		options           = EWMAOptions{Interval: time.Second, Window: time.Minute}
+		ewma, now         = newTestEWMA(options)
+		looped, loopedNow = newTestEWMA(options)
+	)
+
+	ewma.Add(100)
+	looped.Add(100)
+
+	// Decaying over a long idle period at once should match ticking through each interval
+	now.Advance(time.Hour)
+
+	for i := 0; i < 3600; i++ {
+		loopedNow.Advance(time.Second)
+		looped.Rate()
+	}
+
+	require.InDelta(t, looped.Rate(), ewma.Rate(), 1e-9)
+	require.Equal(t, now.Now(), ewma.last)
+}
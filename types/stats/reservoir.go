@@ -0,0 +1,164 @@
+// Package stats exposes streaming statistics accumulators which use a bounded amount of memory regardless of the
+// number of observed samples.
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultReservoirSize is the default number of samples retained by a 'Reservoir'; this gives quantile estimates which
+// are accurate to within a couple of percent whilst using a small, fixed amount of memory.
+const DefaultReservoirSize = 1028
+
+// Reservoir is a thread safe quantile estimator which uses reservoir sampling (Algorithm R) to maintain a uniform sample
+// of all the observed values.
+//
+// NOTE: The count, sum, min and max are exact, quantiles are estimated from the retained sample.
+type Reservoir struct {
+	lock sync.Mutex
+
+	rand    *rand.Rand
+	samples []float64
+	size    int
+
+	count uint64
+	sum   float64
+	min   float64
+	max   float64
+}
+
+// NewReservoir returns a new reservoir which will retain at most the given number of samples, a zero size will result
+// in the 'DefaultReservoirSize' being used.
+func NewReservoir(size int) *Reservoir {
+	if size <= 0 {
+		size = DefaultReservoirSize
+	}
+
+	return &Reservoir{
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		samples: make([]float64, 0, size),
+		size:    size,
+		min:     math.Inf(1),
+		max:     math.Inf(-1),
+	}
+}
+
+// Observe records the given value.
+func (r *Reservoir) Observe(value float64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.count++
+	r.sum += value
+	r.min = min(r.min, value)
+	r.max = max(r.max, value)
+
+	if len(r.samples) < r.size {
+		r.samples = append(r.samples, value)
+		return
+	}
+
+	// Replace an existing sample with decreasing probability, this ensures each observed value has an equal chance of
+	// being in the reservoir.
+	if index := r.rand.Int63n(int64(r.count)); index < int64(r.size) {
+		r.samples[index] = value
+	}
+}
+
+// ObserveDuration is a convenience wrapper around 'Observe' which records the given duration in seconds.
+func (r *Reservoir) ObserveDuration(duration time.Duration) {
+	r.Observe(duration.Seconds())
+}
+
+// Count returns the total number of observed values.
+func (r *Reservoir) Count() uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.count
+}
+
+// Snapshot returns a point in time summary of the observed values.
+func (r *Reservoir) Snapshot() Snapshot {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.count == 0 {
+		return Snapshot{}
+	}
+
+	sorted := make([]float64, len(r.samples))
+	copy(sorted, r.samples)
+	sort.Float64s(sorted)
+
+	return Snapshot{
+		Count:  r.count,
+		Sum:    r.sum,
+		Min:    r.min,
+		Max:    r.max,
+		Mean:   r.sum / float64(r.count),
+		sorted: sorted,
+	}
+}
+
+// Reset discards all the observed values.
+func (r *Reservoir) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.samples = r.samples[:0]
+	r.count = 0
+	r.sum = 0
+	r.min = math.Inf(1)
+	r.max = math.Inf(-1)
+}
+
+// Snapshot is an immutable summary of the values observed by a 'Reservoir'.
+type Snapshot struct {
+	Count uint64
+	Sum   float64
+	Min   float64
+	Max   float64
+	Mean  float64
+
+	sorted []float64
+}
+
+// Quantile returns the estimated value at the given quantile, which should be in the range [0, 1].
+//
+// NOTE: Returns zero if no values have been observed.
+func (s Snapshot) Quantile(q float64) float64 {
+	if len(s.sorted) == 0 {
+		return 0
+	}
+
+	q = max(0, min(1, q))
+
+	// Linearly interpolate between the closest ranks
+	var (
+		pos   = q * float64(len(s.sorted)-1)
+		lower = int(math.Floor(pos))
+		upper = int(math.Ceil(pos))
+	)
+
+	return s.sorted[lower] + (s.sorted[upper]-s.sorted[lower])*(pos-float64(lower))
+}
+
+// P50 returns the estimated median.
+func (s Snapshot) P50() float64 {
+	return s.Quantile(0.50)
+}
+
+// P95 returns the estimated 95th percentile.
+func (s Snapshot) P95() float64 {
+	return s.Quantile(0.95)
+}
+
+// P99 returns the estimated 99th percentile.
+func (s Snapshot) P99() float64 {
+	return s.Quantile(0.99)
+}
@@ -0,0 +1,107 @@
+package stats
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReservoirDefaultSize(t *testing.T) {
+	reservoir := NewReservoir(0)
+	require.Equal(t, DefaultReservoirSize, reservoir.size)
+	require.Equal(t, DefaultReservoirSize, cap(reservoir.samples))
+}
+
+func TestReservoirEmptySnapshot(t *testing.T) {
+	snapshot := NewReservoir(16).Snapshot()
+	require.Equal(t, Snapshot{}, snapshot)
+	require.Zero(t, snapshot.P50())
+}
+
+func TestReservoirSnapshot(t *testing.T) {
+	reservoir := NewReservoir(128)
+
+	for i := 1; i <= 101; i++ {
+		reservoir.Observe(float64(i))
+	}
+
+	snapshot := reservoir.Snapshot()
+	require.Equal(t, uint64(101), snapshot.Count)
+	require.Equal(t, float64(5151), snapshot.Sum)
+	require.Equal(t, float64(1), snapshot.Min)
+	require.Equal(t, float64(101), snapshot.Max)
+	require.Equal(t, float64(51), snapshot.Mean)
+	require.Equal(t, float64(51), snapshot.P50())
+	require.Equal(t, float64(96), snapshot.P95())
+	require.Equal(t, float64(100), snapshot.P99())
+	require.Equal(t, float64(1), snapshot.Quantile(-1))
+	require.Equal(t, float64(101), snapshot.Quantile(2))
+}
+
+func TestReservoirQuantileInterpolation(t *testing.T) {
+	reservoir := NewReservoir(16)
+	reservoir.Observe(10)
+	reservoir.Observe(20)
+
+	require.Equal(t, float64(15), reservoir.Snapshot().P50())
+}
+
+func TestReservoirBoundedMemory(t *testing.T) {
+	reservoir := NewReservoir(100)
+
+	for i := 0; i < 100_000; i++ {
+		reservoir.Observe(float64(i % 1000))
+	}
+
+	require.Len(t, reservoir.samples, 100)
+	require.Equal(t, uint64(100_000), reservoir.Count())
+
+	snapshot := reservoir.Snapshot()
+	require.Equal(t, float64(0), snapshot.Min)
+	require.Equal(t, float64(999), snapshot.Max)
+	require.InDelta(t, 500, snapshot.P50(), 200)
+}
+
+func TestReservoirObserveDuration(t *testing.T) {
+	reservoir := NewReservoir(16)
+	reservoir.ObserveDuration(1500 * time.Millisecond)
+
+	require.Equal(t, 1.5, reservoir.Snapshot().Max)
+}
+
+func TestReservoirReset(t *testing.T) {
+	reservoir := NewReservoir(16)
+	reservoir.Observe(42)
+	reservoir.Reset()
+
+	require.Zero(t, reservoir.Count())
+	require.Equal(t, Snapshot{}, reservoir.Snapshot())
+
+	reservoir.Observe(-1)
+	require.Equal(t, float64(-1), reservoir.Snapshot().Max)
+}
+
+func TestReservoirConcurrentObserve(t *testing.T) {
+	var (
+		reservoir = NewReservoir(64)
+		wg        sync.WaitGroup
+	)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				reservoir.Observe(float64(j))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, uint64(8000), reservoir.Count())
+}
@@ -0,0 +1,257 @@
+package timeprovider
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// FakeClock is a 'TimeProvider' where time only passes when advanced manually, allowing time dependent logic to be
+// tested deterministically.
+//
+// NOTE: As with the standard library, timers and tickers use channels with a capacity of one; ticks are dropped if the
+// receiver falls behind.
+type FakeClock struct {
+	lock    sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+var _ TimeProvider = (*FakeClock)(nil)
+
+// NewFakeClock returns a new fake clock, which starts at the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	clock := &FakeClock{now: now}
+	clock.cond = sync.NewCond(&clock.lock)
+
+	return clock
+}
+
+// Now returns the current time of the fake clock.
+func (f *FakeClock) Now() time.Time {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.now
+}
+
+// Since returns the time elapsed since the given time, according to the fake clock.
+func (f *FakeClock) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// After returns a channel on which the time is sent once the fake clock has been advanced by the given duration.
+func (f *FakeClock) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// Sleep blocks the calling goroutine until the fake clock has been advanced by the given duration.
+func (f *FakeClock) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// NewTimer returns a timer which fires once the fake clock has been advanced by the given duration.
+func (f *FakeClock) NewTimer(d time.Duration) Timer {
+	waiter := &fakeWaiter{clock: f, ch: make(chan time.Time, 1)}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.schedule(waiter, d)
+
+	return waiter
+}
+
+// NewTicker returns a ticker which ticks each time the fake clock is advanced past the given interval.
+//
+// NOTE: Panics if the given interval is not positive, matching 'time.NewTicker'.
+func (f *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for FakeClock.NewTicker")
+	}
+
+	waiter := &fakeWaiter{clock: f, ch: make(chan time.Time, 1), period: d}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.schedule(waiter, d)
+
+	return &fakeTicker{waiter: waiter}
+}
+
+// Advance moves the fake clock forward by the given duration, firing any timers/tickers which expire in the process.
+func (f *FakeClock) Advance(d time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.set(f.now.Add(d))
+}
+
+// Set moves the fake clock to the given time, firing any timers/tickers which expire in the process.
+//
+// NOTE: Moving the clock backwards doesn't fire anything, waiters keep their original expiry time.
+func (f *FakeClock) Set(t time.Time) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.set(t)
+}
+
+// Waiters returns the number of timers/tickers which are currently waiting for the fake clock to be advanced.
+func (f *FakeClock) Waiters() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return len(f.waiters)
+}
+
+// BlockUntil blocks the calling goroutine until there are at least the given number of timers/tickers waiting for the
+// fake clock to be advanced; this should be used to avoid racing with the goroutine under test before calling
+// 'Advance'.
+func (f *FakeClock) BlockUntil(n int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+// set moves the fake clock to the given time, firing waiters in order of expiry.
+//
+// NOTE: Expects the lock to be held.
+func (f *FakeClock) set(t time.Time) {
+	f.now = t
+
+	for len(f.waiters) > 0 && !f.waiters[0].until.After(f.now) {
+		waiter := f.waiters[0]
+		f.waiters = f.waiters[1:]
+
+		waiter.fire()
+
+		if waiter.period <= 0 {
+			continue
+		}
+
+		// Tickers drop any ticks they've missed, their next expiry must be after the current time
+		for !waiter.until.After(f.now) {
+			waiter.until = waiter.until.Add(waiter.period)
+		}
+
+		f.insert(waiter)
+	}
+}
+
+// schedule adds the given waiter to expire after the given duration, firing it immediately if the duration isn't
+// positive.
+//
+// NOTE: Expects the lock to be held.
+func (f *FakeClock) schedule(waiter *fakeWaiter, d time.Duration) {
+	waiter.until = f.now.Add(d)
+
+	if d <= 0 && waiter.period <= 0 {
+		waiter.fire()
+		return
+	}
+
+	f.insert(waiter)
+	f.cond.Broadcast()
+}
+
+// insert adds the given waiter, maintaining ordering by expiry time.
+//
+// NOTE: Expects the lock to be held.
+func (f *FakeClock) insert(waiter *fakeWaiter) {
+	idx, _ := slices.BinarySearchFunc(f.waiters, waiter, func(a, b *fakeWaiter) int {
+		// Waiters which expire at the same time should fire in the order they were created
+		if a.until.After(b.until) {
+			return 1
+		}
+
+		return -1
+	})
+
+	f.waiters = slices.Insert(f.waiters, idx, waiter)
+}
+
+// remove removes the given waiter, returning a boolean indicating whether it was waiting.
+//
+// NOTE: Expects the lock to be held.
+func (f *FakeClock) remove(waiter *fakeWaiter) bool {
+	idx := slices.Index(f.waiters, waiter)
+	if idx == -1 {
+		return false
+	}
+
+	f.waiters = slices.Delete(f.waiters, idx, idx+1)
+
+	return true
+}
+
+// fakeWaiter is a timer, or the underlying timer of a ticker, which is waiting for the fake clock to be advanced.
+type fakeWaiter struct {
+	clock  *FakeClock
+	ch     chan time.Time
+	until  time.Time
+	period time.Duration
+}
+
+var _ Timer = (*fakeWaiter)(nil)
+
+func (f *fakeWaiter) C() <-chan time.Time {
+	return f.ch
+}
+
+func (f *fakeWaiter) Stop() bool {
+	f.clock.lock.Lock()
+	defer f.clock.lock.Unlock()
+
+	return f.clock.remove(f)
+}
+
+func (f *fakeWaiter) Reset(d time.Duration) bool {
+	f.clock.lock.Lock()
+	defer f.clock.lock.Unlock()
+
+	active := f.clock.remove(f)
+
+	f.clock.schedule(f, d)
+
+	return active
+}
+
+// fire sends the expiry time of the waiter on its channel, dropping it if the channel is full.
+func (f *fakeWaiter) fire() {
+	select {
+	case f.ch <- f.until:
+	default:
+	}
+}
+
+// fakeTicker wraps a waiter so that it implements the 'Ticker' interface.
+type fakeTicker struct {
+	waiter *fakeWaiter
+}
+
+func (f *fakeTicker) C() <-chan time.Time {
+	return f.waiter.ch
+}
+
+func (f *fakeTicker) Stop() {
+	f.waiter.Stop()
+}
+
+func (f *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for FakeClock.Ticker.Reset")
+	}
+
+	f.waiter.clock.lock.Lock()
+	defer f.waiter.clock.lock.Unlock()
+
+	f.waiter.clock.remove(f.waiter)
+	f.waiter.period = d
+	f.waiter.clock.schedule(f.waiter, d)
+}
@@ -0,0 +1,166 @@
+package timeprovider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeClockNow(t *testing.T) {
+	clock := NewFakeClock(epoch)
+	require.Equal(t, epoch, clock.Now())
+
+	clock.Advance(time.Minute)
+	require.Equal(t, epoch.Add(time.Minute), clock.Now())
+	require.Equal(t, time.Minute, clock.Since(epoch))
+
+	clock.Set(epoch)
+	require.Equal(t, epoch, clock.Now())
+}
+
+func TestFakeClockAfter(t *testing.T) {
+	clock := NewFakeClock(epoch)
+
+	ch := clock.After(time.Second)
+	require.Equal(t, 1, clock.Waiters())
+
+	clock.Advance(time.Second - 1)
+	requireNotFired(t, ch)
+
+	clock.Advance(1)
+	require.Equal(t, epoch.Add(time.Second), requireFired(t, ch))
+	require.Zero(t, clock.Waiters())
+}
+
+func TestFakeClockAfterNonPositive(t *testing.T) {
+	clock := NewFakeClock(epoch)
+
+	requireFired(t, clock.After(0))
+	requireFired(t, clock.After(-time.Second))
+	require.Zero(t, clock.Waiters())
+}
+
+func TestFakeClockAfterFiresInOrder(t *testing.T) {
+	var (
+		clock  = NewFakeClock(epoch)
+		first  = clock.After(2 * time.Second)
+		second = clock.After(time.Second)
+	)
+
+	clock.Advance(time.Minute)
+
+	require.Equal(t, epoch.Add(2*time.Second), requireFired(t, first))
+	require.Equal(t, epoch.Add(time.Second), requireFired(t, second))
+}
+
+func TestFakeClockSleep(t *testing.T) {
+	var (
+		clock = NewFakeClock(epoch)
+		done  = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+		clock.Sleep(time.Hour)
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected sleep to return after advancing the clock")
+	}
+}
+
+func TestFakeClockTimerStop(t *testing.T) {
+	var (
+		clock = NewFakeClock(epoch)
+		timer = clock.NewTimer(time.Second)
+	)
+
+	require.True(t, timer.Stop())
+	require.False(t, timer.Stop())
+
+	clock.Advance(time.Second)
+	requireNotFired(t, timer.C())
+}
+
+func TestFakeClockTimerReset(t *testing.T) {
+	var (
+		clock = NewFakeClock(epoch)
+		timer = clock.NewTimer(time.Second)
+	)
+
+	require.True(t, timer.Reset(time.Minute))
+
+	clock.Advance(time.Second)
+	requireNotFired(t, timer.C())
+
+	clock.Advance(time.Minute)
+	requireFired(t, timer.C())
+
+	require.False(t, timer.Reset(time.Second))
+
+	clock.Advance(time.Second)
+	requireFired(t, timer.C())
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	var (
+		clock  = NewFakeClock(epoch)
+		ticker = clock.NewTicker(time.Second)
+	)
+
+	clock.Advance(time.Second)
+	require.Equal(t, epoch.Add(time.Second), requireFired(t, ticker.C()))
+
+	// Missed ticks are dropped, rather than being queued
+	clock.Advance(5 * time.Second)
+	require.Equal(t, epoch.Add(2*time.Second), requireFired(t, ticker.C()))
+	requireNotFired(t, ticker.C())
+
+	clock.Advance(time.Second)
+	require.Equal(t, epoch.Add(7*time.Second), requireFired(t, ticker.C()))
+
+	ticker.Reset(time.Minute)
+
+	clock.Advance(time.Second)
+	requireNotFired(t, ticker.C())
+
+	clock.Advance(time.Minute)
+	requireFired(t, ticker.C())
+
+	ticker.Stop()
+	require.Zero(t, clock.Waiters())
+
+	clock.Advance(time.Hour)
+	requireNotFired(t, ticker.C())
+}
+
+func TestFakeClockTickerNonPositive(t *testing.T) {
+	require.Panics(t, func() { NewFakeClock(epoch).NewTicker(0) })
+}
+
+func requireFired(t *testing.T, ch <-chan time.Time) time.Time {
+	select {
+	case now := <-ch:
+		return now
+	default:
+		t.Fatal("Expected channel to have fired")
+	}
+
+	return time.Time{}
+}
+
+func requireNotFired(t *testing.T, ch <-chan time.Time) {
+	select {
+	case <-ch:
+		t.Fatal("Expected channel not to have fired")
+	default:
+	}
+}
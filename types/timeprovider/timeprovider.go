@@ -0,0 +1,119 @@
+// Package timeprovider exposes an abstraction over the passage of time, allowing time dependent logic to be tested
+// deterministically using a 'FakeClock' which is only advanced manually.
+package timeprovider
+
+import "time"
+
+// TimeProvider is an interface which provides the current time, and allows waiting for durations to elapse.
+type TimeProvider interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since the given time.
+	Since(t time.Time) time.Duration
+
+	// After waits for the given duration to elapse, then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+
+	// Sleep blocks the calling goroutine until the given duration has elapsed.
+	Sleep(d time.Duration)
+
+	// NewTimer returns a timer which sends the current time on its channel after the given duration.
+	NewTimer(d time.Duration) Timer
+
+	// NewTicker returns a ticker which sends the current time on its channel at the given interval.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is an interface which represents a single event, see 'time.Timer'.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing, returning false if the timer has already expired or been stopped.
+	Stop() bool
+
+	// Reset changes the timer to expire after the given duration, returning true if the timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// Ticker is an interface which represents a repeating event, see 'time.Ticker'.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() <-chan time.Time
+
+	// Stop turns off the ticker, no more ticks will be sent.
+	Stop()
+
+	// Reset stops the ticker and resets its interval to the given duration.
+	Reset(d time.Duration)
+}
+
+// CurrentTimeProvider is a 'TimeProvider' which uses the system clock.
+type CurrentTimeProvider struct{}
+
+var _ TimeProvider = CurrentTimeProvider{}
+
+// Now returns the current time.
+func (CurrentTimeProvider) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns the time elapsed since the given time.
+func (CurrentTimeProvider) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// After waits for the given duration to elapse, then sends the current time on the returned channel.
+func (CurrentTimeProvider) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Sleep blocks the calling goroutine until the given duration has elapsed.
+func (CurrentTimeProvider) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// NewTimer returns a timer which sends the current time on its channel after the given duration.
+func (CurrentTimeProvider) NewTimer(d time.Duration) Timer {
+	return &timer{timer: time.NewTimer(d)}
+}
+
+// NewTicker returns a ticker which sends the current time on its channel at the given interval.
+func (CurrentTimeProvider) NewTicker(d time.Duration) Ticker {
+	return &ticker{ticker: time.NewTicker(d)}
+}
+
+// timer wraps a 'time.Timer' so that it implements the 'Timer' interface.
+type timer struct {
+	timer *time.Timer
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t *timer) Stop() bool {
+	return t.timer.Stop()
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	return t.timer.Reset(d)
+}
+
+// ticker wraps a 'time.Ticker' so that it implements the 'Ticker' interface.
+type ticker struct {
+	ticker *time.Ticker
+}
+
+func (t *ticker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t *ticker) Stop() {
+	t.ticker.Stop()
+}
+
+func (t *ticker) Reset(d time.Duration) {
+	t.ticker.Reset(d)
+}
@@ -0,0 +1,136 @@
+// Package group exposes a typed alternative to 'errgroup', where each function returns a result; the results are
+// collected in the order the functions were added, removing the need for callers to manage a slice and mutex.
+//
+// Panics in a function are recovered and converted into a 'PanicError', rather than crashing the process.
+package group
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// Func is a function run by a group, where possible, the function should honor the cancellation of the given context
+// and return as quickly/cleanly as possible.
+type Func[T any] func(ctx context.Context) (T, error)
+
+// PanicError is returned when a function run by a group panics.
+type PanicError struct {
+	// Value is the value passed to 'panic'.
+	Value any
+
+	// Stack is the stack trace of the goroutine which panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns the value passed to 'panic', where it's an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Group runs functions concurrently, collecting their results. The first error returned by any function cancels the
+// context given to the other functions, and is returned by 'Wait'.
+//
+// NOTE: A group is intended to be used once, functions should not be added after calling 'Wait'.
+type Group[T any] struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	wg  sync.WaitGroup
+	sem chan struct{}
+
+	lock    sync.Mutex
+	results []T
+	err     error
+}
+
+// New returns a new group, the given context may be used to cancel the functions run by the group.
+func New[T any](ctx context.Context) *Group[T] {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	return &Group[T]{ctx: ctx, cancel: cancel}
+}
+
+// Context returns the context given to the functions run by the group, this context is cancelled after the first error.
+func (g *Group[T]) Context() context.Context {
+	return g.ctx
+}
+
+// SetLimit limits the number of functions which may run concurrently, 'Go' blocks until a function can be run once the
+// limit is reached. A zero/negative limit means there's no limit.
+//
+// NOTE: Must be called before any functions are added to the group.
+func (g *Group[T]) SetLimit(n int) {
+	if n <= 0 {
+		g.sem = nil
+		return
+	}
+
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs the given function in a new goroutine, its result is stored at the index matching the order in which it was
+// added to the group.
+func (g *Group[T]) Go(fn Func[T]) {
+	g.lock.Lock()
+	index := len(g.results)
+	g.results = append(g.results, *new(T))
+	g.lock.Unlock()
+
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+
+		result, err := run(g.ctx, fn)
+
+		g.lock.Lock()
+		defer g.lock.Unlock()
+
+		g.results[index] = result
+
+		if err != nil && g.err == nil {
+			g.err = err
+			g.cancel(err)
+		}
+	}()
+}
+
+// Wait blocks until all the functions in the group have completed, returning their results in the order they were
+// added, and the first error which occurred. Results are returned even when an error occurs, the result for a function
+// which failed is the one it returned alongside the error.
+func (g *Group[T]) Wait() ([]T, error) {
+	g.wg.Wait()
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.cancel(nil)
+
+	return g.results, g.err
+}
+
+// run runs the given function, converting any panic into an error.
+func run[T any](ctx context.Context, fn Func[T]) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return fn(ctx)
+}
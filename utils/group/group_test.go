@@ -0,0 +1,128 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	g := New[int](context.Background())
+
+	for i := 0; i < 10; i++ {
+		i := i
+
+		g.Go(func(_ context.Context) (int, error) {
+			// Complete in reverse order, results must still be ordered
+			time.Sleep(time.Duration(10-i) * time.Millisecond)
+			return i * i, nil
+		})
+	}
+
+	results, err := g.Wait()
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 4, 9, 16, 25, 36, 49, 64, 81}, results)
+}
+
+func TestGroupEmpty(t *testing.T) {
+	results, err := New[string](context.Background()).Wait()
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestGroupError(t *testing.T) {
+	var (
+		g        = New[int](context.Background())
+		expected = errors.New("failed")
+	)
+
+	g.Go(func(_ context.Context) (int, error) { return 1, nil })
+	g.Go(func(_ context.Context) (int, error) { return 0, expected })
+	g.Go(func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	results, err := g.Wait()
+	require.ErrorIs(t, err, expected)
+	require.Equal(t, []int{1, 0, 0}, results)
+	require.ErrorIs(t, context.Cause(g.Context()), expected)
+}
+
+func TestGroupPanic(t *testing.T) {
+	g := New[int](context.Background())
+
+	g.Go(func(_ context.Context) (int, error) { panic("oops") })
+
+	_, err := g.Wait()
+
+	var panicErr *PanicError
+
+	require.ErrorAs(t, err, &panicErr)
+	require.Equal(t, "oops", panicErr.Value)
+	require.Contains(t, string(panicErr.Stack), "group.TestGroupPanic")
+}
+
+func TestGroupPanicWithError(t *testing.T) {
+	var (
+		g        = New[int](context.Background())
+		expected = errors.New("failed")
+	)
+
+	g.Go(func(_ context.Context) (int, error) { panic(expected) })
+
+	_, err := g.Wait()
+	require.ErrorIs(t, err, expected)
+}
+
+func TestGroupSetLimit(t *testing.T) {
+	var (
+		g                = New[struct{}](context.Background())
+		running, maximum atomic.Int64
+	)
+
+	g.SetLimit(2)
+
+	for i := 0; i < 10; i++ {
+		g.Go(func(_ context.Context) (struct{}, error) {
+			curr := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				prev := maximum.Load()
+				if curr <= prev || maximum.CompareAndSwap(prev, curr) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+
+			return struct{}{}, nil
+		})
+	}
+
+	results, err := g.Wait()
+	require.NoError(t, err)
+	require.Len(t, results, 10)
+	require.LessOrEqual(t, maximum.Load(), int64(2))
+}
+
+func TestGroupCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	g := New[int](ctx)
+
+	g.Go(func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	cancel()
+
+	_, err := g.Wait()
+	require.ErrorIs(t, err, context.Canceled)
+}
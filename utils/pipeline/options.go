@@ -0,0 +1,22 @@
+package pipeline
+
+// Options encapsulates the options available when adding a stage to a pipeline.
+type Options struct {
+	// Workers is the number of goroutines used to process items concurrently, items are fanned out to the workers and
+	// their output is fanned back in; the order of items is therefore only preserved when using a single worker.
+	// Defaults to one.
+	Workers int
+
+	// Buffer is the capacity of the channel on which the stage outputs items, this bounds the number of items which
+	// may be waiting to be processed by the next stage. Defaults to the number of workers.
+	Buffer int
+}
+
+// defaults fills any missing attributes to a sane default.
+func (o *Options) defaults() {
+	o.Workers = max(1, o.Workers)
+
+	if o.Buffer <= 0 {
+		o.Buffer = o.Workers
+	}
+}
@@ -0,0 +1,39 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsDefaults(t *testing.T) {
+	type test struct {
+		name     string
+		input    Options
+		expected Options
+	}
+
+	tests := []*test{
+		{
+			name:     "Empty",
+			expected: Options{Workers: 1, Buffer: 1},
+		},
+		{
+			name:     "Workers",
+			input:    Options{Workers: 4},
+			expected: Options{Workers: 4, Buffer: 4},
+		},
+		{
+			name:     "Buffer",
+			input:    Options{Workers: 4, Buffer: 16},
+			expected: Options{Workers: 4, Buffer: 16},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.input.defaults()
+			require.Equal(t, test.expected, test.input)
+		})
+	}
+}
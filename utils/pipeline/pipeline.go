@@ -0,0 +1,114 @@
+// Package pipeline exposes a generic framework for building concurrent processing pipelines, where items flow through
+// a chain of stages connected by bounded channels e.g. list -> download -> decompress -> verify.
+//
+// Bounded channels provide backpressure, a slow stage will block the stages before it rather than allowing items to
+// accumulate in memory. The first error returned by any stage cancels the pipeline, causing all the remaining stages to
+// stop, and is returned by 'Wait'; goroutines are never leaked, even when a stage stops consuming its input early.
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Pipeline tracks the stages of a pipeline, cancelling them all in the event of an error.
+//
+// NOTE: A pipeline is intended to be used once, stages should not be added after calling 'Wait'.
+type Pipeline struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg sync.WaitGroup
+
+	lock sync.Mutex
+	err  error
+}
+
+// New returns a new pipeline, the given context may be used to cancel the pipeline.
+func New(ctx context.Context) *Pipeline {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &Pipeline{ctx: ctx, cancel: cancel}
+}
+
+// Context returns the context used by the pipeline, this context is cancelled after the first error.
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Go runs the given function in a new goroutine, which is tracked by the pipeline; this may be used to implement custom
+// stages. If the function returns an error, the pipeline is cancelled.
+func (p *Pipeline) Go(fn func(ctx context.Context) error) {
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		err := fn(p.ctx)
+		if err != nil {
+			p.setErr(err)
+		}
+	}()
+}
+
+// Wait blocks until all the stages in the pipeline have completed, returning the first error which occurred. If the
+// pipeline was cancelled using the context given to 'New', the context error is returned.
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+
+	defer p.cancel()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+
+	// The parent context may have been cancelled whilst some stages were still running, which may have stopped early
+	// without returning an error.
+	return context.Cause(p.ctx)
+}
+
+// setErr records the first error to occur, and cancels the pipeline; subsequent errors are ignored because they're
+// likely caused by the cancellation.
+func (p *Pipeline) setErr(err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.err != nil {
+		return
+	}
+
+	p.err = err
+	p.cancel()
+}
+
+// Send sends the given item on the given channel, blocking until it's been received or the context is cancelled in
+// which case the context error is returned.
+func Send[T any](ctx context.Context, ch chan<- T, item T) error {
+	select {
+	case ch <- item:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Receive receives an item from the given channel, blocking until one is available; returns false if the channel has
+// been closed or the context is cancelled.
+func Receive[T any](ctx context.Context, ch <-chan T) (T, bool) {
+	var zero T
+
+	// Check for cancellation first, so that we stop promptly when both are ready
+	if ctx.Err() != nil {
+		return zero, false
+	}
+
+	select {
+	case item, ok := <-ch:
+		return item, ok
+	case <-ctx.Done():
+		return zero, false
+	}
+}
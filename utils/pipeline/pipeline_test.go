@@ -0,0 +1,174 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// count returns a source function which emits the integers from one to n.
+func count(n int) SourceFunc[int] {
+	return func(_ context.Context, emit EmitFunc[int]) error {
+		for i := 1; i <= n; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	var (
+		p     = New(context.Background())
+		total int
+	)
+
+	numbers := Source(p, count(100), Options{})
+
+	squared := Stage(p, numbers, func(_ context.Context, n int) (int, error) { return n * n, nil }, Options{Workers: 4})
+
+	Sink(p, squared, func(_ context.Context, n int) error { total += n; return nil }, Options{})
+
+	require.NoError(t, p.Wait())
+	require.Equal(t, 338350, total)
+}
+
+func TestPipelinePreservesOrderWithSingleWorker(t *testing.T) {
+	var (
+		p      = New(context.Background())
+		actual []int
+	)
+
+	numbers := Source(p, count(10), Options{})
+
+	Sink(p, numbers, func(_ context.Context, n int) error { actual = append(actual, n); return nil }, Options{})
+
+	require.NoError(t, p.Wait())
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, actual)
+}
+
+func TestPipelineStageError(t *testing.T) {
+	var (
+		p        = New(context.Background())
+		expected = errors.New("failed to download")
+	)
+
+	// The source would block forever if it weren't cancelled by the error
+	numbers := Source(p, count(1<<30), Options{})
+
+	failed := Stage(p, numbers, func(_ context.Context, n int) (int, error) {
+		if n == 10 {
+			return 0, expected
+		}
+
+		return n, nil
+	}, Options{Workers: 2})
+
+	Sink(p, failed, func(_ context.Context, _ int) error { return nil }, Options{})
+
+	require.ErrorIs(t, p.Wait(), expected)
+	require.ErrorIs(t, p.Context().Err(), context.Canceled)
+}
+
+func TestPipelineSinkStopsEarly(t *testing.T) {
+	var (
+		p        = New(context.Background())
+		expected = errors.New("failed to verify")
+	)
+
+	numbers := Source(p, count(1<<30), Options{})
+
+	squared := Stage(p, numbers, func(_ context.Context, n int) (int, error) { return n * n, nil }, Options{Workers: 4})
+
+	Sink(p, squared, func(_ context.Context, _ int) error { return expected }, Options{})
+
+	require.ErrorIs(t, p.Wait(), expected)
+}
+
+func TestPipelineCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := New(ctx)
+
+	numbers := Source(p, count(1<<30), Options{})
+
+	Sink(p, numbers, func(_ context.Context, n int) error {
+		if n == 10 {
+			cancel()
+		}
+
+		return nil
+	}, Options{})
+
+	require.ErrorIs(t, p.Wait(), context.Canceled)
+}
+
+func TestPipelineBackpressure(t *testing.T) {
+	var (
+		p       = New(context.Background())
+		emitted atomic.Int64
+		release = make(chan struct{})
+	)
+
+	numbers := Source(p, func(_ context.Context, emit EmitFunc[int]) error {
+		for i := 0; i < 100; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+
+			emitted.Add(1)
+		}
+
+		return nil
+	}, Options{Buffer: 2})
+
+	Sink(p, numbers, func(_ context.Context, _ int) error { <-release; return nil }, Options{})
+
+	time.Sleep(50 * time.Millisecond)
+
+	// One item is held by the blocked sink, and the buffer is full
+	require.LessOrEqual(t, emitted.Load(), int64(3))
+
+	close(release)
+
+	require.NoError(t, p.Wait())
+	require.Equal(t, int64(100), emitted.Load())
+}
+
+func TestMerge(t *testing.T) {
+	var (
+		p     = New(context.Background())
+		total int
+	)
+
+	merged := Merge(p, Source(p, count(10), Options{}), Source(p, count(20), Options{}))
+
+	Sink(p, merged, func(_ context.Context, n int) error { total += n; return nil }, Options{})
+
+	require.NoError(t, p.Wait())
+	require.Equal(t, 55+210, total)
+}
+
+func TestReceiveCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ch := make(chan int, 1)
+	ch <- 1
+
+	_, ok := Receive(ctx, ch)
+	require.False(t, ok)
+}
+
+func TestSendCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, Send(ctx, make(chan int), 1), context.Canceled)
+}
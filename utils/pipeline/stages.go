@@ -0,0 +1,143 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// EmitFunc is used by a source to emit an item into the pipeline, an error is returned if the pipeline is cancelled in
+// which case the source should stop and return the error.
+type EmitFunc[T any] func(item T) error
+
+// SourceFunc produces the items which flow through the pipeline e.g. by listing objects.
+type SourceFunc[T any] func(ctx context.Context, emit EmitFunc[T]) error
+
+// StageFunc transforms a single item e.g. by downloading an object.
+type StageFunc[T, U any] func(ctx context.Context, item T) (U, error)
+
+// SinkFunc consumes a single item e.g. by verifying a downloaded object.
+type SinkFunc[T any] func(ctx context.Context, item T) error
+
+// Source adds a stage which produces items using the given function, returning the channel on which they're output.
+//
+// NOTE: The source function is run by a single goroutine, the number of workers is ignored.
+func Source[T any](p *Pipeline, fn SourceFunc[T], opts Options) <-chan T {
+	opts.defaults()
+
+	out := make(chan T, opts.Buffer)
+
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+
+		return fn(ctx, func(item T) error { return Send(ctx, out, item) })
+	})
+
+	return out
+}
+
+// Stage adds a stage which transforms each of the items received from the given channel using the given function,
+// returning the channel on which the transformed items are output.
+func Stage[T, U any](p *Pipeline, in <-chan T, fn StageFunc[T, U], opts Options) <-chan U {
+	opts.defaults()
+
+	out := make(chan U, opts.Buffer)
+
+	workers(p, opts.Workers, func() { close(out) }, func(ctx context.Context) error {
+		for {
+			item, ok := Receive(ctx, in)
+			if !ok {
+				return nil
+			}
+
+			transformed, err := fn(ctx, item)
+			if err != nil {
+				return err
+			}
+
+			err = Send(ctx, out, transformed)
+			if err != nil {
+				return err
+			}
+		}
+	})
+
+	return out
+}
+
+// Sink adds a final stage which consumes each of the items received from the given channel using the given function.
+func Sink[T any](p *Pipeline, in <-chan T, fn SinkFunc[T], opts Options) {
+	opts.defaults()
+
+	workers(p, opts.Workers, func() {}, func(ctx context.Context) error {
+		for {
+			item, ok := Receive(ctx, in)
+			if !ok {
+				return nil
+			}
+
+			err := fn(ctx, item)
+			if err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// Merge fans in the items received from the given channels, returning the channel on which they're output.
+func Merge[T any](p *Pipeline, ins ...<-chan T) <-chan T {
+	out := make(chan T, len(ins))
+
+	var wg sync.WaitGroup
+
+	wg.Add(len(ins))
+
+	for _, in := range ins {
+		in := in
+
+		p.Go(func(ctx context.Context) error {
+			defer wg.Done()
+
+			for {
+				item, ok := Receive(ctx, in)
+				if !ok {
+					return nil
+				}
+
+				err := Send(ctx, out, item)
+				if err != nil {
+					return err
+				}
+			}
+		})
+	}
+
+	p.Go(func(_ context.Context) error {
+		wg.Wait()
+		close(out)
+
+		return nil
+	})
+
+	return out
+}
+
+// workers runs the given function using the given number of goroutines, running 'done' once they've all returned.
+func workers(p *Pipeline, n int, done func(), fn func(ctx context.Context) error) {
+	var wg sync.WaitGroup
+
+	wg.Add(n)
+
+	for w := 0; w < n; w++ {
+		p.Go(func(ctx context.Context) error {
+			defer wg.Done()
+			return fn(ctx)
+		})
+	}
+
+	p.Go(func(_ context.Context) error {
+		wg.Wait()
+		done()
+
+		return nil
+	})
+}
@@ -14,6 +14,7 @@ type RetryableFunc[T any] func(ctx *Context) (T, error)
 // Retryer is a function retryer, which supports executing a given function a number of times until successful.
 type Retryer[T any] struct {
 	options RetryerOptions[T]
+	resume  *State
 }
 
 // NewRetryer returns a new retryer with the given options.
@@ -28,6 +29,17 @@ func NewRetryer[T any](options RetryerOptions[T]) Retryer[T] {
 	return retryer
 }
 
+// Resume returns a copy of the retryer which resumes from the given state, the first attempt is made once the next
+// attempt time has passed, and the backoff continues from the number of attempts already made.
+//
+// NOTE: At least one attempt is always made, even if the given state has exhausted the max number of retries.
+func (r Retryer[T]) Resume(state State) Retryer[T] {
+	cp := r
+	cp.resume = &state
+
+	return cp
+}
+
 // Do executes the given function until it's successful.
 func (r Retryer[T]) Do(fn RetryableFunc[T]) (T, error) {
 	return r.DoWithContext(context.Background(), fn)
@@ -42,6 +54,15 @@ func (r Retryer[T]) DoWithContext(ctx context.Context, fn RetryableFunc[T]) (T,
 		err     error
 	)
 
+	if r.resume != nil {
+		wrapped.attempt = min(max(r.resume.Attempts+1, 1), r.options.MaxRetries)
+
+		err = r.wait(wrapped, time.Until(r.resume.NextAttempt))
+		if err != nil {
+			return payload, err
+		}
+	}
+
 	for ; wrapped.attempt <= r.options.MaxRetries; wrapped.attempt++ {
 		payload, done, err = r.do(wrapped, fn)
 		if done {
@@ -63,10 +84,8 @@ func (r Retryer[T]) do(ctx *Context, fn RetryableFunc[T]) (T, bool, error) {
 		return *new(T), true, &RetriesAbortedError{attempts: ctx.attempt - 1, err: err}
 	}
 
-	payload, err := fn(ctx)
-
-	// NOTE: The error returned by 'retry' may differ from the error defined above
-	if retry, err := r.retry(ctx, payload, err); !retry {
+	payload, retry, err := r.call(ctx, fn)
+	if !retry {
 		return payload, true, err
 	}
 
@@ -82,6 +101,112 @@ func (r Retryer[T]) do(ctx *Context, fn RetryableFunc[T]) (T, bool, error) {
 	return payload, false, err
 }
 
+// call executes the given function, hedging the attempt if enabled, returning the payload, error and whether the
+// function should be executed again.
+func (r Retryer[T]) call(ctx *Context, fn RetryableFunc[T]) (T, bool, error) {
+	if r.options.HedgeDelay <= 0 {
+		payload, err := fn(ctx)
+
+		// NOTE: The error returned by 'retry' may differ from the error returned by the function
+		retry, err := r.retry(ctx, payload, err)
+
+		return payload, retry, err
+	}
+
+	return r.hedge(ctx, fn)
+}
+
+// result is the result of a single execution of a 'RetryableFunc'.
+type result[T any] struct {
+	index   int
+	payload T
+	retry   bool
+	err     error
+}
+
+// hedge executes the given function, starting a second attempt if the first hasn't completed within the hedge delay.
+// The result of the first attempt to complete successfully is returned, and the other attempt is cancelled; where both
+// attempts fail, the result of the last to complete is returned.
+func (r Retryer[T]) hedge(ctx *Context, fn RetryableFunc[T]) (T, bool, error) {
+	var (
+		results = make(chan result[T], 2)
+		cancels = make([]context.CancelFunc, 0, 2)
+	)
+
+	start := func() {
+		child, cancel := context.WithCancel(ctx.Context)
+
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+
+		go func() {
+			payload, err := fn(&Context{Context: child, attempt: ctx.attempt})
+			results <- result[T]{index: index, payload: payload, err: err}
+		}()
+	}
+
+	// finish releases the context of the given attempt, returning its result
+	finish := func(res result[T]) (T, bool, error) {
+		if res.retry {
+			cancels[res.index]()
+			return res.payload, res.retry, res.err
+		}
+
+		if r.options.HedgeRelease != nil {
+			return r.options.HedgeRelease(res.payload, cancels[res.index]), res.retry, res.err
+		}
+
+		return res.payload, res.retry, res.err
+	}
+
+	// evaluate determines whether the given attempt was successful, or should be retried
+	evaluate := func(res result[T]) result[T] {
+		res.retry, res.err = r.retry(ctx, res.payload, res.err)
+		return res
+	}
+
+	start()
+
+	timer := time.NewTimer(r.options.HedgeDelay)
+	defer timer.Stop()
+
+	select {
+	case res := <-results:
+		return finish(evaluate(res))
+	case <-timer.C:
+		start()
+	}
+
+	res := evaluate(<-results)
+
+	// The attempt failed, but the other may still succeed so we must wait for it
+	if res.retry {
+		cancels[res.index]()
+
+		if r.options.Cleanup != nil {
+			r.options.Cleanup(res.payload)
+		}
+
+		return finish(evaluate(<-results))
+	}
+
+	// The losing attempt is cancelled, it will be cleaned up in the background once it returns
+	cancels[1-res.index]()
+
+	go r.cleanupHedged(results)
+
+	return finish(res)
+}
+
+// cleanupHedged waits for the losing hedged attempt to complete, cleaning up its payload.
+func (r Retryer[T]) cleanupHedged(results <-chan result[T]) {
+	res := <-results
+
+	if r.options.Cleanup != nil {
+		r.options.Cleanup(res.payload)
+	}
+}
+
 // retry returns a boolean indicating whether the function should be executed again.
 //
 // NOTE: Users may supply a custom 'ShouldRetry' function for more complex retry behavior which depends on the payload.
@@ -102,7 +227,22 @@ func (r Retryer[T]) retry(ctx *Context, payload T, err error) (bool, error) {
 
 // sleep until the next retry attempt, or the given context is cancelled.
 func (r Retryer[T]) sleep(ctx *Context) error {
-	timer := time.NewTimer(r.Duration(ctx.Attempt()))
+	duration := r.Duration(ctx.Attempt())
+
+	if r.options.Checkpoint != nil {
+		r.options.Checkpoint(State{Attempts: ctx.attempt, NextAttempt: time.Now().Add(duration)})
+	}
+
+	return r.wait(ctx, duration)
+}
+
+// wait for the given duration, or until the given context is cancelled.
+func (r Retryer[T]) wait(ctx *Context, duration time.Duration) error {
+	if duration <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(duration)
 	defer timer.Stop()
 
 	select {
@@ -1,6 +1,9 @@
 package retry
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Algorithm represents a retry algorithm used to determine backoff before retrying function execution.
 type Algorithm int
@@ -30,6 +33,11 @@ type ShouldRetryFunc[T any] func(ctx *Context, payload T, err error) bool
 // NOTE: The final attempt is not cleaned up because the payload may want to be used/read to enhance returned errors.
 type CleanupFunc[T any] func(payload T)
 
+// HedgeReleaseFunc is a function which is run with the payload of the winning hedged attempt, and a function which
+// cancels its context; it should return a payload which calls the function once the payload is no longer needed e.g.
+// by wrapping an HTTP response body so that the function is called once the body is closed.
+type HedgeReleaseFunc[T any] func(payload T, cancel context.CancelFunc) T
+
 // RetryerOptions encapsulates the options available when creating a retryer.
 type RetryerOptions[T any] struct {
 	// Algorithm is the algorithm to use when calculating backoff.
@@ -52,6 +60,26 @@ type RetryerOptions[T any] struct {
 
 	// Cleanup is a cleanup function run for all but the last payloads prior to performing a retry.
 	Cleanup CleanupFunc[T]
+
+	// HedgeDelay enables hedged requests; when non-zero, if an attempt hasn't completed within the given delay, a second
+	// (speculative) attempt is started concurrently. The first of the two to complete successfully (i.e. which wouldn't
+	// be retried) is used, and the other is cancelled, with its payload being passed to 'Cleanup'; the attempt only
+	// fails if both of them fail.
+	//
+	// NOTE: This must only be used for idempotent functions, since both attempts may run to completion; the retryer has
+	// no way to determine whether a function is idempotent so it's the responsibility of the caller.
+	HedgeDelay time.Duration
+
+	// HedgeRelease is used to release the context of the winning hedged attempt, once its payload is no longer needed,
+	// see 'HedgeReleaseFunc'. When not supplied, the context is released once the context given to the retryer is done.
+	//
+	// NOTE: The context of the winning attempt isn't cancelled when it completes, as its payload may depend upon it
+	// e.g. an HTTP response body.
+	HedgeRelease HedgeReleaseFunc[T]
+
+	// Checkpoint is a function which is run with the state of the retryer before backing off prior to each retry, this
+	// may be used to persist the state, allowing the operation to be resumed using 'Retryer.Resume'.
+	Checkpoint CheckpointFunc
 }
 
 func (r *RetryerOptions[T]) defaults() {
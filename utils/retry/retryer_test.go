@@ -2,6 +2,8 @@ package retry
 
 import (
 	"context"
+	"encoding/json"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -235,3 +237,235 @@ func TestRetryerDurationWithOverflow(t *testing.T) {
 		NewRetryer[int](RetryerOptions[int]{Algorithm: AlgorithmExponential}).Duration(42),
 	)
 }
+
+func TestRetryerDoHedged(t *testing.T) {
+	var (
+		calls   atomic.Int64
+		cleaned = make(chan int, 1)
+		options = RetryerOptions[int]{
+			HedgeDelay: 10 * time.Millisecond,
+			Cleanup:    func(payload int) { cleaned <- payload },
+		}
+	)
+
+	payload, err := NewRetryer(options).Do(func(ctx *Context) (int, error) {
+		call := int(calls.Add(1))
+
+		require.Equal(t, 1, ctx.Attempt())
+
+		// The first attempt is slow, and should be cancelled once the hedged attempt completes
+		if call == 1 {
+			<-ctx.Done()
+			return call, ctx.Err()
+		}
+
+		return call, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, payload)
+	require.Equal(t, int64(2), calls.Load())
+
+	select {
+	case cleanup := <-cleaned:
+		require.Equal(t, 1, cleanup)
+	case <-time.After(time.Second):
+		require.FailNow(t, "Expected the losing attempt to be cleaned up")
+	}
+}
+
+func TestRetryerDoHedgedCompletesBeforeDelay(t *testing.T) {
+	var (
+		calls   atomic.Int64
+		options = RetryerOptions[int]{HedgeDelay: time.Minute}
+	)
+
+	payload, err := NewRetryer(options).Do(func(_ *Context) (int, error) {
+		return int(calls.Add(1)), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, payload)
+	require.Equal(t, int64(1), calls.Load())
+}
+
+func TestRetryerDoHedgedWithRetries(t *testing.T) {
+	var (
+		calls   atomic.Int64
+		options = RetryerOptions[int]{HedgeDelay: time.Minute, MinDelay: time.Millisecond}
+	)
+
+	// Failures within the hedge delay should be retried as usual
+	payload, err := NewRetryer(options).Do(func(ctx *Context) (int, error) {
+		calls.Add(1)
+
+		if ctx.Attempt() < 3 {
+			return 0, assert.AnError
+		}
+
+		return ctx.Attempt(), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, payload)
+	require.Equal(t, int64(3), calls.Load())
+}
+
+func TestRetryerDoWithCheckpoint(t *testing.T) {
+	var (
+		states  []State
+		options = RetryerOptions[int]{
+			MinDelay:   time.Millisecond,
+			MaxDelay:   time.Millisecond,
+			Checkpoint: func(state State) { states = append(states, state) },
+		}
+	)
+
+	_, err := NewRetryer(options).Do(func(ctx *Context) (int, error) {
+		if ctx.Attempt() < 3 {
+			return 0, assert.AnError
+		}
+
+		return ctx.Attempt(), nil
+	})
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	require.Equal(t, 1, states[0].Attempts)
+	require.Equal(t, 2, states[1].Attempts)
+	require.False(t, states[0].NextAttempt.IsZero())
+}
+
+func TestRetryerResume(t *testing.T) {
+	var attempts []int
+
+	retryer := NewRetryer(RetryerOptions[int]{MaxRetries: 5, MinDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	_, err := retryer.Resume(State{Attempts: 3}).Do(func(ctx *Context) (int, error) {
+		attempts = append(attempts, ctx.Attempt())
+		return 0, assert.AnError
+	})
+
+	var exhausted *RetriesExhaustedError
+
+	require.ErrorAs(t, err, &exhausted)
+	require.Equal(t, []int{4, 5}, attempts)
+}
+
+func TestRetryerResumeExhausted(t *testing.T) {
+	var called int
+
+	retryer := NewRetryer(RetryerOptions[int]{MaxRetries: 3})
+
+	_, err := retryer.Resume(State{Attempts: 3}).Do(func(_ *Context) (int, error) {
+		called++
+		return 0, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, called)
+}
+
+func TestRetryerResumeWaitsForNextAttempt(t *testing.T) {
+	retryer := NewRetryer(RetryerOptions[int]{}).Resume(State{Attempts: 1, NextAttempt: time.Now().Add(time.Hour)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var called int
+
+	_, err := retryer.DoWithContext(ctx, func(_ *Context) (int, error) {
+		called++
+		return 0, nil
+	})
+
+	var aborted *RetriesAbortedError
+
+	require.ErrorAs(t, err, &aborted)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Zero(t, called)
+}
+
+func TestStateJSONRoundTrip(t *testing.T) {
+	expected := State{Attempts: 2, NextAttempt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	data, err := json.Marshal(expected)
+	require.NoError(t, err)
+
+	var actual State
+
+	require.NoError(t, json.Unmarshal(data, &actual))
+	require.Equal(t, expected, actual)
+}
+
+func TestRetryerDoHedgedRelease(t *testing.T) {
+	var (
+		contexts = make(chan context.Context, 1)
+		released context.CancelFunc
+		options  = RetryerOptions[int]{
+			HedgeDelay:   time.Minute,
+			HedgeRelease: func(payload int, cancel context.CancelFunc) int { released = cancel; return payload },
+		}
+	)
+
+	_, err := NewRetryer(options).Do(func(ctx *Context) (int, error) {
+		contexts <- ctx.Context
+		return 0, nil
+	})
+	require.NoError(t, err)
+
+	// The payload of the winning attempt may depend upon its context, so it should only be cancelled once released
+	ctx := <-contexts
+	require.NoError(t, ctx.Err())
+
+	require.NotNil(t, released)
+	released()
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestRetryerDoHedgedFirstFails(t *testing.T) {
+	var (
+		calls   atomic.Int64
+		hedged  = make(chan struct{})
+		cleaned = make(chan int, 1)
+		options = RetryerOptions[int]{
+			MaxRetries: 1,
+			HedgeDelay: 10 * time.Millisecond,
+			Cleanup:    func(payload int) { cleaned <- payload },
+		}
+	)
+
+	payload, err := NewRetryer(options).Do(func(ctx *Context) (int, error) {
+		call := int(calls.Add(1))
+
+		// The first attempt fails after the hedged attempt has started, but the hedged attempt succeeds
+		if call == 1 {
+			<-hedged
+			return call, assert.AnError
+		}
+
+		close(hedged)
+
+		time.Sleep(10 * time.Millisecond)
+
+		return call, ctx.Err()
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, payload)
+	require.Equal(t, 1, <-cleaned)
+}
+
+func TestRetryerDoHedgedBothFail(t *testing.T) {
+	var (
+		calls   atomic.Int64
+		options = RetryerOptions[int]{MaxRetries: 1, HedgeDelay: 10 * time.Millisecond}
+	)
+
+	_, err := NewRetryer(options).Do(func(_ *Context) (int, error) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+
+		return 0, assert.AnError
+	})
+
+	var exhausted *RetriesExhaustedError
+
+	require.ErrorAs(t, err, &exhausted)
+	require.ErrorIs(t, err, assert.AnError)
+	require.Equal(t, int64(2), calls.Load())
+}
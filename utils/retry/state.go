@@ -0,0 +1,16 @@
+package retry
+
+import "time"
+
+// State is the state of a retryer between attempts, which may be persisted (e.g. as JSON) so that an interrupted
+// operation can be resumed using 'Retryer.Resume', continuing its backoff schedule rather than starting from scratch.
+type State struct {
+	// Attempts is the number of attempts which have been made so far.
+	Attempts int `json:"attempts"`
+
+	// NextAttempt is the time at which the next attempt should be made, after backing off.
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// CheckpointFunc is a function which is run with the state of the retryer, prior to backing off before each retry.
+type CheckpointFunc func(state State)